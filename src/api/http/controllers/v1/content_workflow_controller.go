@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"errors"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/errors/gorm_err"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// TransitionContent applies a workflow action to /:type/:id. The route
+// decides which permission the action needs.
+func TransitionContent(cn *container.AppContainer, action string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.ContentTransitionDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.ContentWorkflowHandler.TransitionContentHandler(userID, c.Params("type"), id, action, input.Comment)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func CreateContentComment(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.CreateContentCommentDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.ContentWorkflowHandler.AddContentCommentHandler(userID, c.Params("type"), id, input.Body)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessCreatedResponse(c, data)
+	}
+}
+
+func GetContentComments(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.ContentWorkflowHandler.GetContentCommentsHandler(c.Params("type"), id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetContentTransitions(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.ContentWorkflowHandler.GetContentTransitionsHandler(c.Params("type"), id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// canPreviewContent reports whether the caller may see courses, lessons and
+// materials that are not published yet.
+func canPreviewContent(c *fiber.Ctx) bool {
+	return middleware.HasRole(c, "super") || middleware.HasRole(c, "teacher")
+}
+
+// contentErrorStatus answers 404 for rows the caller can't see, including
+// course content of another school, and 500 for anything else.
+func contentErrorStatus(err error) int {
+	if errors.Is(err, gorm_err.ErrDataTidakDitemukan) {
+		return fiber.StatusNotFound
+	}
+	return fiber.StatusInternalServerError
+}
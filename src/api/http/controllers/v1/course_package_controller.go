@@ -0,0 +1,76 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/pkg/services/v1"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func ExportMCourse(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.CoursePackageHandler.ExportCourseHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+
+		c.Set(fiber.HeaderContentType, "application/zip")
+		c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", fmt.Sprintf("course-%d.zip", id)))
+		return c.Send(data)
+	}
+}
+
+// ImportMCourse accepts a multipart upload with a "file" field holding a
+// course package and optional form fields dry_run, on_conflict and
+// school_id.
+func ImportMCourse(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "File wajib diunggah")
+		}
+
+		options := dto.CourseImportOptionsDto{
+			DryRun:     c.FormValue("dry_run", c.Query("dry_run", "false")) == "true",
+			OnConflict: c.FormValue("on_conflict"),
+		}
+		if value := c.FormValue("school_id"); value != "" {
+			schoolID, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid school_id")
+			}
+			options.SchoolID = &schoolID
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(file)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		report, err := cn.CoursePackageHandler.ImportCourseHandler(data, options)
+		if errors.Is(err, services.ErrCourseImportInvalid) {
+			return presenters.ErrorResponseWithData(c, fiber.StatusUnprocessableEntity, err, report)
+		}
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+		return presenters.SuccessResponse(c, report)
+	}
+}
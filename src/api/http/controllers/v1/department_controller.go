@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetDepartments(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		parentID, _ := helper.ParseQueryInt64(c, "parent_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.Department{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.DepartmentFilterDto{
+			Preload:     c.Query("preload", "false") == "true",
+			Spec:        spec,
+			Name:        c.Query("name"),
+			ParentID:    parentID,
+			RootOnly:    c.Query("root_only", "false") == "true",
+			ShowDeleted: c.Query("show_deleted", "false") == "true",
+		}
+
+		data, page, err := cn.DepartmentHandler.GetAllDepartmentsHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetDepartmentTree(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		data, err := cn.DepartmentHandler.GetDepartmentTreeHandler()
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetDepartmentByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		filter := dto.DepartmentFilterDto{
+			Preload: c.Query("preload", "false") == "true",
+		}
+
+		data, err := cn.DepartmentHandler.GetDepartmentByIDHandler(id, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetDepartmentSummary(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.DepartmentHandler.GetDepartmentSummaryHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func CreateDepartment(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.CreateDepartmentDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		result, err := cn.DepartmentHandler.CreateDepartmentHandler(&input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessCreatedResponse(c, result)
+	}
+}
+
+func BulkCreateDepartments(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.BulkCreateDepartments
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request body for bulk create")
+		}
+		if len(input.Data) == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "No data provided")
+		}
+
+		created, err := cn.DepartmentHandler.BulkCreateDepartmentsHandler(&input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessCreatedResponse(c, created)
+	}
+}
+
+func UpdateDepartment(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.UpdateDepartmentDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid input")
+		}
+
+		updated, err := cn.DepartmentHandler.UpdateDepartmentHandler(id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, updated)
+	}
+}
+
+func DeleteDepartment(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		if err := cn.DepartmentHandler.DeleteDepartmentHandler(id); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Department deleted successfully", nil)
+	}
+}
+
+func BulkDeleteDepartments(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.BulkDeleteDepartmentDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request body for bulk delete")
+		}
+		if len(input.IDs) == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "No department IDs provided")
+		}
+
+		if err := cn.DepartmentHandler.BulkDeleteDepartmentsHandler(&input); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, fmt.Sprintf("Successfully deleted %d departments", len(input.IDs)), nil)
+	}
+}
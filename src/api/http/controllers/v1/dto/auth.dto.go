@@ -18,13 +18,15 @@ type UserResponse struct {
 	Name     string        `json:"name"`
 	Email    string        `json:"email"`
 	HasRoles []models.Role `json:"has_roles"`
+	SchoolID *int64        `json:"school_id"`
 }
 
 type RegisterRequest struct {
 	Name     string `json:"name"`
 	Email    string `json:"email"`
 	Password string `json:"password"`
-	Role     string `json:"role"` // "student" | "teacher"
+	Role     string `json:"role"` // "student" | "teacher" | "guardian"
+	SchoolCode string `json:"school_code"`
 }
 
 type ProfileResponse struct {
@@ -34,4 +36,5 @@ type ProfileResponse struct {
 	IsPasswordDefault bool `json:"is_password_default"`
 	HasRoles  []models.Role  `json:"has_roles"`
 	HasClass  *models.MClass `json:"class,omitempty"`
+	School    *models.MSchool `json:"school,omitempty"`
 }
@@ -0,0 +1,12 @@
+package dto
+
+// ContentTransitionDto is the optional note left with a workflow action.
+// Requesting changes requires one.
+type ContentTransitionDto struct {
+	Comment *string `json:"comment"`
+}
+
+// CreateContentCommentDto is review feedback on a course, lesson or material.
+type CreateContentCommentDto struct {
+	Body string `json:"body" binding:"required"`
+}
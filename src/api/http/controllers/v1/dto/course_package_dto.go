@@ -0,0 +1,27 @@
+package dto
+
+// CourseImportOptionsDto controls a course package import. OnConflict is
+// "rename" (the default) or "fail". SchoolID is only honoured for platform
+// admins; everyone else imports into their own school.
+type CourseImportOptionsDto struct {
+	DryRun     bool   `json:"dry_run"`
+	OnConflict string `json:"on_conflict"`
+	SchoolID   *int64 `json:"school_id"`
+}
+
+type CourseImportReportDto struct {
+	DryRun         bool     `json:"dry_run"`
+	FormatVersion  int      `json:"format_version"`
+	CourseName     string   `json:"course_name"`
+	Renamed        bool     `json:"renamed"`
+	CourseID       int64    `json:"course_id,omitempty"`
+	Lessons        int      `json:"lessons"`
+	SubLessons     int      `json:"sub_lessons"`
+	Materials      int      `json:"materials"`
+	CodeQuestions  int      `json:"code_questions"`
+	EssayQuestions int      `json:"essay_questions"`
+	Media          []string `json:"media"`
+	Errors         []string `json:"errors,omitempty"`
+	// Keys maps every package key to the id it was imported as.
+	Keys map[string]int64 `json:"keys,omitempty"`
+}
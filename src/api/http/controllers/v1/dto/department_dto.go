@@ -2,20 +2,25 @@ package dto
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"time"
 )
 
 // CreateDepartmentDto is used when creating a new Department.
 type CreateDepartmentDto struct {
-	Name string  `json:"name"`
-	Code *string `json:"code"`
+	Name     string  `json:"name"`
+	Code     *string `json:"code"`
+	ParentID *int64  `json:"parent_id"`
 }
 
 // UpdateDepartmentDto is used when updating an existing Department.
 type UpdateDepartmentDto struct {
-	Name      *string    `json:"name"`
-	Code      *string    `json:"code"`
-	DeletedAt *time.Time `json:"deleted_at"`
+	Name     *string `json:"name"`
+	Code     *string `json:"code"`
+	ParentID *int64  `json:"parent_id"`
+	// ClearParent moves the department to the top of the tree.
+	ClearParent bool       `json:"clear_parent"`
+	DeletedAt   *time.Time `json:"deleted_at"`
 }
 
 type BulkCreateDepartments struct {
@@ -33,11 +38,10 @@ type BulkDeleteDepartmentDto struct {
 
 type DepartmentFilterDto struct {
 	Preload     bool
-	Sort        string
-	Order       string
-	Limit       int64
-	Cursor      int64
+	Spec        queryspec.Spec
 	Name        string
+	ParentID    int64
+	RootOnly    bool
 	ShowDeleted bool
 	Restore     bool
 }
@@ -46,3 +50,12 @@ type DepartmentFilterDto struct {
 type DepartmentResponseDto struct {
 	models.Department
 }
+
+// DepartmentSummaryDto reports what sits inside a department subtree.
+type DepartmentSummaryDto struct {
+	Department    models.Department `json:"department"`
+	DepartmentIDs []int64           `json:"department_ids"`
+	Members       int64             `json:"members"`
+	Classes       int64             `json:"classes"`
+	Students      int64             `json:"students"`
+}
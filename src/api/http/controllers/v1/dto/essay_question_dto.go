@@ -29,4 +29,7 @@ type EssayQuestionFilterDto struct {
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// Preview includes generated questions that are still drafts. Students
+	// never preview.
+	Preview bool
 }
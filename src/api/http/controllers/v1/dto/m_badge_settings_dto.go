@@ -10,6 +10,8 @@ import (
 type CreateMBadgeSettingsDto struct {
 	Name	   string    `json:"name"`
 	Image      string    `json:"image"`
+	// ImageMediaID points at an uploaded image and overrides Image.
+	ImageMediaID *int64  `json:"image_media_id"`
 	MinScore   int       `json:"min_score"`
 	MaxScore   int       `json:"max_score"`
 }
@@ -18,6 +20,7 @@ type CreateMBadgeSettingsDto struct {
 type UpdateMBadgeSettingsDto struct {
 	Name	   *string   `json:"name"`
 	Image      *string   `json:"image"`
+	ImageMediaID *int64  `json:"image_media_id"`
 	MinScore   *int      `json:"min_score"`
 	MaxScore   *int      `json:"max_score"`
 	IsActive   *bool     `json:"is_active"`
@@ -12,6 +12,8 @@ type CreateMClassDto struct {
 	ClassName string `json:"class_name"`
 	SchoolName string `json:"school_name"`
 	ClassCode string `json:"class_code"`
+	SchoolID  *int64 `json:"school_id"`
+	DepartmentID *int64 `json:"department_id"`
 }
 
 // UpdateMClassDto is used when updating an existing MClass.
@@ -19,6 +21,8 @@ type UpdateMClassDto struct {
 	ClassName  *string    `json:"class_name"`
 	SchoolName *string    `json:"school_name"`
 	ClassCode  *string    `json:"class_code"`
+	SchoolID   *int64     `json:"school_id"`
+	DepartmentID *int64   `json:"department_id"`
 	IsActive   *bool      `json:"is_active"`
 	DeletedAt  *time.Time `json:"deleted_at"`
 	Teachers   *[]int64   `json:"teachers"`
@@ -52,4 +56,6 @@ type MClassFilterDto struct {
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// DepartmentID limits the list to the department and its subtree.
+	DepartmentID int64
 }
@@ -4,6 +4,7 @@ package dto
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 )
 
 // CreateMCourseDto is used when creating a new MCourse.
@@ -11,6 +12,12 @@ type CreateMCourseDto struct {
      CourseName string `json:"course_name" binding:"required"`
 	 Description string `json:"description"`
 	 ImgThumbnail string `json:"img_thumbnail"`
+	 // ImgThumbnailMediaID points at an uploaded image and overrides
+	 // ImgThumbnail.
+	 ImgThumbnailMediaID *int64 `json:"img_thumbnail_media_id"`
+	 // SchoolID makes the course private to a school. Only platform admins
+	 // choose it; everyone else creates courses for their own school.
+	 SchoolID *int64 `json:"school_id"`
 }
 
 // UpdateMCourseDto is used when updating an existing MCourse.
@@ -18,8 +25,25 @@ type UpdateMCourseDto struct {
 	CourseName *string `json:"course_name"`
 	Description *string `json:"description"`
 	ImgThumbnail *string `json:"img_thumbnail"`
-	Published *bool `json:"published"`
+	ImgThumbnailMediaID *int64 `json:"img_thumbnail_media_id"`
 	IsActive *bool `json:"isactive"`
+	SchoolID *int64 `json:"school_id"`
+	IsTemplate *bool `json:"is_template"`
+}
+
+// CloneMCourseDto tunes how a course tree is copied.
+type CloneMCourseDto struct {
+	// CourseName names the copy. Defaults to the source name with a suffix.
+	CourseName *string `json:"course_name"`
+	// ResetPublished starts the copy and all its content as drafts. Defaults
+	// to true; false keeps the editorial status of every item.
+	ResetPublished *bool `json:"reset_published"`
+	// LevelMap moves lessons from one level to another, keyed by the source
+	// level id.
+	LevelMap map[int64]int64 `json:"level_map"`
+	// SchoolID copies the course into another school. Only platform admins
+	// choose it; everyone else copies into their own school.
+	SchoolID *int64 `json:"school_id"`
 }
 
 // MCourseResponseDto represents a detailed view of MCourse with related data.
@@ -29,11 +53,13 @@ type MCourseResponseDto struct {
 
 type MCourseFilterDto struct {
 	Preload     bool
-	Sort        string
-	Order       string
-	Limit       int64
-	Cursor      int64
+	Spec        queryspec.Spec
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// Preview includes content that is not published yet. Students never
+	// preview.
+	Preview     bool
+	// Template limits the list to the template gallery.
+	Template    bool
 }
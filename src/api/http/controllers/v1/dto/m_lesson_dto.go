@@ -4,6 +4,7 @@ package dto
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 )
 
 // CreateMLesson is used when creating a new MLesson.
@@ -14,6 +15,9 @@ type CreateMLessonDto struct {
 	Description string `json:"description"`
 	Position int `json:"position"`
 	ImgThumbnail string `json:"img_thumbnail"`
+	// ImgThumbnailMediaID points at an uploaded image and overrides
+	// ImgThumbnail.
+	ImgThumbnailMediaID *int64 `json:"img_thumbnail_media_id"`
 }
 
 // UpdateMLesson is used when updating an existing MLesson.
@@ -24,7 +28,7 @@ type UpdateMLessonDto struct {
 	Description *string `json:"description"`
 	Position *int `json:"position"`
 	ImgThumbnail *string `json:"img_thumbnail"`
-	Published *bool `json:"published"`
+	ImgThumbnailMediaID *int64 `json:"img_thumbnail_media_id"`
 	IsActive *bool `json:"isactive"`
 }
 
@@ -48,11 +52,11 @@ type MLessonResponseDto struct {
 
 type MLessonFilterDto struct {
 	Preload     bool
-	Sort        string
-	Order       string
-	Limit       int64
-	Cursor      int64
+	Spec        queryspec.Spec
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// Preview includes content that is not published yet. Students never
+	// preview.
+	Preview     bool
 }
@@ -3,7 +3,9 @@
 package dto
 
 import (
+	"encoding/json"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 )
 
 type ReadMMaterialDto struct {
@@ -13,21 +15,27 @@ type ReadMMaterialDto struct {
 type CreateMMaterialDto struct {
 	SubLessonID int64  `json:"sub_lesson_id" binding:"required"`
 	Title       string `json:"title" binding:"required"`
-	Materials  string `json:"materials" binding:"required"`
+	// Materials is a Tiptap document. A string is read as plain text.
+	Materials  json.RawMessage `json:"materials" binding:"required"`
 	URLVideo  string `json:"url_video"`
+	VideoMediaID *int64 `json:"video_media_id"`
 	ContentPosition int    `json:"content_position" binding:"required"`
-	PromptLLM       string `json:"prompt_llm" binding:"required"`
+	// PromptTemplateVersionID is the prompt template version the material
+	// is generated with. PromptLLM is only kept for older clients.
+	PromptTemplateVersionID *int64 `json:"prompt_template_version_id"`
+	PromptLLM       string `json:"prompt_llm"`
 }
 
 // UpdateMMaterial is used when updating an existing MMaterial.
 type UpdateMMaterialDto struct {
 	SubLessonID *int64  `json:"sub_lesson_id,omitempty"`
 	Title       *string `json:"title,omitempty"`
-	Materials  *string `json:"materials,omitempty"`
+	Materials  json.RawMessage `json:"materials,omitempty"`
 	URLVideo  *string `json:"url_video,omitempty"`
+	VideoMediaID *int64 `json:"video_media_id,omitempty"`
 	ContentPosition *int    `json:"content_position,omitempty"`
+	PromptTemplateVersionID *int64 `json:"prompt_template_version_id,omitempty"`
 	PromptLLM       *string `json:"prompt_llm,omitempty"`
-	Published       *bool   `json:"published,omitempty"`
 	IsActive        *bool   `json:"isactive,omitempty"`
 }
 
@@ -51,11 +59,18 @@ type MMaterialResponseDto struct {
 
 type MMaterialFilterDto struct {
 	Preload     bool
-	Sort        string
-	Order       string
-	Limit       int64
-	Cursor      int64
+	Spec        queryspec.Spec
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// Preview includes content that is not published yet. Students never
+	// preview.
+	Preview     bool
+}
+
+// RenderedMMaterialDto is the document of a material rendered on the server.
+type RenderedMMaterialDto struct {
+	ID      int64  `json:"id"`
+	Format  string `json:"format"`
+	Content string `json:"content"`
 }
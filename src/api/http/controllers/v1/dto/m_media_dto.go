@@ -0,0 +1,24 @@
+package dto
+
+import "time"
+
+// CreateMediaUploadDto starts a resumable upload of Size bytes. Protected
+// files are only served through signed links.
+type CreateMediaUploadDto struct {
+	Filename  string `json:"filename"`
+	Size      int64  `json:"size"`
+	Protected bool   `json:"protected"`
+}
+
+// SignMediaURLDto asks for a link to the media referenced by a piece of
+// content, e.g. the video of a material.
+type SignMediaURLDto struct {
+	Type    string
+	ID      int64
+	Variant string
+}
+
+type SignedMediaURLDto struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// CreatePromptTemplateDto creates a template with its first version.
+type CreatePromptTemplateDto struct {
+	Name        string                  `json:"name" validate:"required"`
+	Kind        string                  `json:"kind" validate:"required"`
+	Description string                  `json:"description"`
+	IsDefault   bool                    `json:"is_default"`
+	Body        string                  `json:"body" validate:"required"`
+	Variables   []models.PromptVariable `json:"variables"`
+	ChangeNote  string                  `json:"change_note"`
+}
+
+// UpdatePromptTemplateDto changes the details of a template. CurrentVersion
+// switches it to an existing version, e.g. to roll back; the text itself is
+// only changed by adding a version.
+type UpdatePromptTemplateDto struct {
+	Name           *string `json:"name,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	IsDefault      *bool   `json:"is_default,omitempty"`
+	IsActive       *bool   `json:"isactive,omitempty"`
+	CurrentVersion *int    `json:"current_version,omitempty"`
+}
+
+// CreatePromptTemplateVersionDto adds a version, which becomes the current
+// one.
+type CreatePromptTemplateVersionDto struct {
+	Body       string                  `json:"body" validate:"required"`
+	Variables  []models.PromptVariable `json:"variables"`
+	ChangeNote string                  `json:"change_note"`
+}
+
+// RenderPromptTemplateDto previews a version, the current one when Version
+// is nil, with every variable filled in by the caller.
+type RenderPromptTemplateDto struct {
+	Version   *int           `json:"version"`
+	Variables map[string]any `json:"variables"`
+}
+
+type RenderedPromptTemplateDto struct {
+	TemplateID int64  `json:"template_id"`
+	VersionID  int64  `json:"version_id"`
+	Version    int    `json:"version"`
+	Prompt     string `json:"prompt"`
+}
+
+// CreatePromptEvaluationDto notes how a version, the current one when
+// Version is nil, performed. Rating goes from 1 to 5.
+type CreatePromptEvaluationDto struct {
+	Version *int   `json:"version"`
+	Rating  *int   `json:"rating"`
+	Notes   string `json:"notes" validate:"required"`
+}
+
+type PromptTemplateFilterDto struct {
+	Kind string
+	Name string
+	Spec queryspec.Spec
+}
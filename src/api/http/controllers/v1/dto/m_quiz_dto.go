@@ -0,0 +1,141 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// CreateQuizDto creates a draft quiz on a sub-lesson; items are added to it
+// before it is published. MaxAttempts of 0 allows any number of attempts.
+type CreateQuizDto struct {
+	SubLessonID    int64  `json:"sub_lesson_id" validate:"required"`
+	Title          string `json:"title" validate:"required"`
+	Description    string `json:"description"`
+	ShuffleItems   bool   `json:"shuffle_items"`
+	ShuffleOptions bool   `json:"shuffle_options"`
+	MaxAttempts    int    `json:"max_attempts"`
+	ScorePolicy    string `json:"score_policy"`
+}
+
+// UpdateQuizDto changes a quiz. Publishing needs at least one item.
+type UpdateQuizDto struct {
+	Title          *string `json:"title,omitempty"`
+	Description    *string `json:"description,omitempty"`
+	Status         *string `json:"status,omitempty"`
+	ShuffleItems   *bool   `json:"shuffle_items,omitempty"`
+	ShuffleOptions *bool   `json:"shuffle_options,omitempty"`
+	MaxAttempts    *int    `json:"max_attempts,omitempty"`
+	ScorePolicy    *string `json:"score_policy,omitempty"`
+}
+
+// QuizItemDto writes an item. Choice items list their Options, true/false
+// items give TrueFalseAnswer instead, numeric items NumericAnswer and
+// Tolerance, and short answer items AcceptedAnswers. Options sent with the
+// id of an existing option keep it.
+type QuizItemDto struct {
+	Type            string              `json:"type" validate:"required"`
+	Prompt          string              `json:"prompt" validate:"required"`
+	Options         []models.QuizOption `json:"options"`
+	TrueFalseAnswer *bool               `json:"true_false_answer"`
+	NumericAnswer   *float64            `json:"numeric_answer"`
+	Tolerance       float64             `json:"tolerance"`
+	AcceptedAnswers []string            `json:"accepted_answers"`
+	CaseSensitive   bool                `json:"case_sensitive"`
+	Points          *int                `json:"points"`
+	Position        *int                `json:"position"`
+	Explanation     string              `json:"explanation"`
+}
+
+// SubmitQuizAttemptDto answers the items of an attempt. Items left out are
+// graded as wrong.
+type SubmitQuizAttemptDto struct {
+	Answers []QuizAnswerInputDto `json:"answers"`
+}
+
+type QuizAnswerInputDto struct {
+	ItemID    int64    `json:"item_id" validate:"required"`
+	OptionIDs []int    `json:"option_ids"`
+	Number    *float64 `json:"number"`
+	Text      *string  `json:"text"`
+}
+
+type QuizFilterDto struct {
+	// UserID limits attempts to one student; students only see their own.
+	UserID      int64
+	SubLessonID int64
+	Spec        queryspec.Spec
+	// Preview shows quizzes that are not published yet. Students never
+	// preview.
+	Preview bool
+}
+
+// QuizAttemptDto is an attempt with its items in the order the student
+// sees them. Until it is submitted the items carry no answers.
+type QuizAttemptDto struct {
+	models.TQuizAttempt
+	Title string               `json:"title"`
+	Items []QuizAttemptItemDto `json:"items"`
+}
+
+type QuizAttemptItemDto struct {
+	ID      int64                 `json:"id"`
+	Type    string                `json:"type"`
+	Prompt  string                `json:"prompt"`
+	Options []QuizAttemptOption   `json:"options,omitempty"`
+	Points  int                   `json:"points"`
+	Answer  *QuizAttemptAnswerDto `json:"answer,omitempty"`
+}
+
+type QuizAttemptOption struct {
+	ID   int    `json:"id"`
+	Text string `json:"text"`
+}
+
+// QuizAttemptAnswerDto is the graded answer to an item, with the right
+// answer so students can learn from their mistakes.
+type QuizAttemptAnswerDto struct {
+	Response      models.QuizResponse `json:"response"`
+	IsCorrect     bool                `json:"is_correct"`
+	Points        int                 `json:"points"`
+	CorrectOption []int               `json:"correct_option_ids,omitempty"`
+	NumericAnswer *float64            `json:"numeric_answer,omitempty"`
+	Accepted      []string            `json:"accepted_answers,omitempty"`
+	Explanation   string              `json:"explanation,omitempty"`
+}
+
+// QuizAnalyticsDto sums up the submitted attempts at a quiz by students of
+// the caller's school.
+type QuizAnalyticsDto struct {
+	QuizID       int64                  `json:"quiz_id"`
+	Attempts     int64                  `json:"attempts"`
+	Students     int64                  `json:"students"`
+	AverageScore float64                `json:"average_score"`
+	MaxScore     int                    `json:"max_score"`
+	Items        []QuizItemAnalyticsDto `json:"items"`
+}
+
+// QuizItemAnalyticsDto tells how an item fared: the share answered right,
+// how often each option was picked and the most common wrong answers.
+type QuizItemAnalyticsDto struct {
+	ItemID       int64                    `json:"item_id"`
+	Type         string                   `json:"type"`
+	Prompt       string                   `json:"prompt"`
+	Answers      int64                    `json:"answers"`
+	Correct      int64                    `json:"correct"`
+	CorrectRate  float64                  `json:"correct_rate"`
+	AveragePoint float64                  `json:"average_points"`
+	Options      []QuizOptionAnalyticsDto `json:"options,omitempty"`
+	CommonWrong  []QuizWrongAnswerDto     `json:"common_wrong_answers,omitempty"`
+}
+
+type QuizOptionAnalyticsDto struct {
+	ID      int    `json:"id"`
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+	Picks   int64  `json:"picks"`
+}
+
+type QuizWrongAnswerDto struct {
+	Response string `json:"response"`
+	Count    int64  `json:"count"`
+}
@@ -0,0 +1,31 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// CreateMSchoolDto is used when creating a new school. Code is optional and
+// lets students pick their school when they register.
+type CreateMSchoolDto struct {
+	Name    string  `json:"name"`
+	Code    *string `json:"code"`
+	Address string  `json:"address"`
+}
+
+// UpdateMSchoolDto is used when updating an existing school.
+type UpdateMSchoolDto struct {
+	Name     *string `json:"name"`
+	Code     *string `json:"code"`
+	Address  *string `json:"address"`
+	IsActive *bool   `json:"is_active"`
+}
+
+type MSchoolResponseDto struct {
+	models.MSchool
+}
+
+type MSchoolFilterDto struct {
+	Spec queryspec.Spec
+	Name string
+}
@@ -4,6 +4,7 @@ package dto
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 )
 
 // CreateMSubLesson is used when creating a new MSubLesson.
@@ -41,11 +42,11 @@ type MSubLessonResponseDto struct {
 
 type MSubLessonFilterDto struct {
 	Preload     bool
-	Sort        string
-	Order       string
-	Limit       int64
-	Cursor      int64
+	Spec        queryspec.Spec
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// Preview includes sub-lessons of content that is not published yet.
+	// Students never preview.
+	Preview     bool
 }
@@ -0,0 +1,36 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// CreateMWebhookSubscriptionDto is used when creating a new webhook
+// subscription. A random secret is generated when Secret is empty.
+type CreateMWebhookSubscriptionDto struct {
+	Name   string   `json:"name"`
+	URL    string   `json:"url"`
+	Secret string   `json:"secret"`
+	Events []string `json:"events"`
+}
+
+// UpdateMWebhookSubscriptionDto is used when updating an existing webhook
+// subscription.
+type UpdateMWebhookSubscriptionDto struct {
+	Name     *string   `json:"name"`
+	URL      *string   `json:"url"`
+	Secret   *string   `json:"secret"`
+	Events   *[]string `json:"events"`
+	IsActive *bool     `json:"is_active"`
+}
+
+// MWebhookSubscriptionResponseDto represents a webhook subscription. Secret is
+// only filled in on create.
+type MWebhookSubscriptionResponseDto struct {
+	models.MWebhookSubscription
+	Secret string `json:"secret,omitempty"`
+}
+
+type MWebhookSubscriptionFilterDto struct {
+	Spec queryspec.Spec
+}
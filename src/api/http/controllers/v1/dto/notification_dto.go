@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// NotificationResponseDto represents a single in-app notification.
+type NotificationResponseDto struct {
+	models.Notification
+}
+
+type NotificationFilterDto struct {
+	Unread bool
+	Type   string
+	Spec   queryspec.Spec
+}
+
+// NotificationPreferenceDto sets the channels for one notification type.
+// Omitted channels keep their current value.
+type NotificationPreferenceDto struct {
+	Type  string `json:"type"`
+	InApp *bool  `json:"in_app"`
+	Push  *bool  `json:"push"`
+	Omni  *bool  `json:"omni"`
+}
+
+type UpdateNotificationPreferencesDto struct {
+	Preferences []NotificationPreferenceDto `json:"preferences"`
+}
+
+type UnreadNotificationCountDto struct {
+	Unread int64 `json:"unread"`
+}
@@ -0,0 +1,43 @@
+package dto
+
+// SearchFilterDto is a full-text search over published course content. Types
+// holds the content types to search, all of them when empty.
+type SearchFilterDto struct {
+	Query    string
+	Types    []string
+	CourseID *int64
+	LevelID  *int64
+	Limit    int
+	Offset   int
+}
+
+// SearchResultDto is one piece of content matching a search. Snippet is an
+// HTML-escaped excerpt with the matches wrapped in <mark>.
+type SearchResultDto struct {
+	Type        string  `json:"type"`
+	ID          int64   `json:"id"`
+	CourseID    int64   `json:"course_id"`
+	LessonID    *int64  `json:"lesson_id"`
+	SubLessonID *int64  `json:"sub_lesson_id"`
+	LevelID     *int64  `json:"level_id"`
+	Title       string  `json:"title"`
+	Snippet     string  `json:"snippet"`
+	Rank        float64 `json:"rank"`
+}
+
+// SearchFacetDto counts the results in one course or level.
+type SearchFacetDto struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
+
+type SearchFacetsDto struct {
+	Courses []SearchFacetDto `json:"courses"`
+	Levels  []SearchFacetDto `json:"levels"`
+}
+
+type SearchResponseDto struct {
+	Results []SearchResultDto `json:"results"`
+	Facets  SearchFacetsDto   `json:"facets"`
+}
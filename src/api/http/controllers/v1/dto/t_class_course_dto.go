@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+type ClassLessonDeadlineDto struct {
+	LessonID int64     `json:"lesson_id"`
+	DueAt    time.Time `json:"due_at"`
+}
+
+type AssignClassCourseDto struct {
+	ClassID         int64                    `json:"class_id"`
+	CourseID        int64                    `json:"course_id"`
+	StartAt         *time.Time               `json:"start_at"`
+	EndAt           *time.Time               `json:"end_at"`
+	LessonDeadlines []ClassLessonDeadlineDto `json:"lesson_deadlines"`
+}
+
+// UpdateClassCourseDto changes an assignment's schedule. LessonDeadlines,
+// when present, replaces every existing lesson deadline.
+type UpdateClassCourseDto struct {
+	StartAt         *time.Time                `json:"start_at"`
+	EndAt           *time.Time                `json:"end_at"`
+	ClearStartAt    bool                      `json:"clear_start_at"`
+	ClearEndAt      bool                      `json:"clear_end_at"`
+	LessonDeadlines *[]ClassLessonDeadlineDto `json:"lesson_deadlines"`
+}
+
+type ClassCourseFilterDto struct {
+	ClassID  int64
+	CourseID int64
+	// DepartmentID limits the list to classes in the department subtree.
+	DepartmentID int64
+	Preload  bool
+	Spec     queryspec.Spec
+}
+
+type DueItemFilterDto struct {
+	Days             int64
+	IncludeCompleted bool
+}
+
+type DueItemDto struct {
+	ClassCourseID int64     `json:"class_course_id"`
+	CourseID      int64     `json:"course_id"`
+	CourseName    string    `json:"course_name"`
+	LessonID      int64     `json:"lesson_id"`
+	LessonTitle   string    `json:"lesson_title"`
+	DueAt         time.Time `json:"due_at"`
+	Completed     int64     `json:"completed"`
+	Total         int64     `json:"total"`
+	Status        string    `json:"status"`
+}
+
+type OverdueItemDto struct {
+	UserID      int64     `json:"user_id"`
+	Name        string    `json:"name"`
+	Email       string    `json:"email"`
+	LessonID    int64     `json:"lesson_id"`
+	LessonTitle string    `json:"lesson_title"`
+	DueAt       time.Time `json:"due_at"`
+	Completed   int64     `json:"completed"`
+	Total       int64     `json:"total"`
+	DaysOverdue int       `json:"days_overdue"`
+}
+
+type ClassCourseOverdueReportDto struct {
+	ClassCourseID int64            `json:"class_course_id"`
+	ClassID       int64            `json:"class_id"`
+	CourseID      int64            `json:"course_id"`
+	CourseName    string           `json:"course_name"`
+	GeneratedAt   time.Time        `json:"generated_at"`
+	Students      int              `json:"students"`
+	Overdue       int              `json:"overdue"`
+	Items         []OverdueItemDto `json:"items"`
+}
@@ -0,0 +1,44 @@
+package dto
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+type JoinMClassDto struct {
+	ClassCode string `json:"class_code"`
+}
+
+type RegenerateClassCodeDto struct {
+	ExpiresAt *time.Time `json:"expires_at"`
+}
+
+// UpdateClassJoinSettingsDto changes how students join a class. Set
+// ClearClassCodeExpiry to make the current code valid indefinitely.
+type UpdateClassJoinSettingsDto struct {
+	ClassCodeExpiresAt   *time.Time `json:"class_code_expires_at"`
+	ClearClassCodeExpiry bool       `json:"clear_class_code_expiry"`
+	AutoApproveJoin      *bool      `json:"auto_approve_join"`
+}
+
+type DecideClassJoinRequestDto struct {
+	Note *string `json:"note"`
+}
+
+type MoveClassStudentDto struct {
+	ToClassID int64 `json:"to_class_id"`
+}
+
+type AssignClassTeacherDto struct {
+	TeacherID int64 `json:"teacher_id"`
+}
+
+type ClassJoinRequestFilterDto struct {
+	Status string
+	Spec   queryspec.Spec
+}
+
+type ClassRosterLogFilterDto struct {
+	Action string
+	Spec   queryspec.Spec
+}
@@ -10,6 +10,8 @@ type TCodeQuestionCreateDto struct {
 	SubLessonID  int64  `json:"sub_lesson_id" validate:"required"`
 	CodeQuestion string `json:"code_question" validate:"required"`
 	Image        string `json:"image"`
+	// ImageMediaID points at an uploaded image and overrides Image.
+	ImageMediaID *int64 `json:"image_media_id"`
 	Score        int    `json:"score" validate:"required"`
 	Hint         string `json:"hint"`
 }
@@ -28,4 +30,7 @@ type TCodeQuestionFilterDto struct {
 	Name        string
 	ShowDeleted bool
 	Restore     bool
+	// Preview includes generated questions that are still drafts. Students
+	// never preview.
+	Preview bool
 }
@@ -0,0 +1,31 @@
+package dto
+
+import "jk-api/internal/queryspec"
+
+type CourseVersionFilterDto struct {
+	CourseID int64
+	Spec     queryspec.Spec
+}
+
+// MigrateCourseVersionDto moves an enrollment to a newer version of its
+// course. Without CourseVersionID the latest version is used.
+type MigrateCourseVersionDto struct {
+	CourseVersionID *int64 `json:"course_version_id"`
+}
+
+// CourseVersionChangeDto is one difference between two course versions.
+// Change is added, removed or modified; Fields lists the modified fields.
+type CourseVersionChangeDto struct {
+	Type   string   `json:"type"`
+	ID     int64    `json:"id"`
+	Title  string   `json:"title"`
+	Change string   `json:"change"`
+	Fields []string `json:"fields,omitempty"`
+}
+
+type CourseVersionDiffDto struct {
+	CourseID    int64                    `json:"course_id"`
+	FromVersion int                      `json:"from_version"`
+	ToVersion   int                      `json:"to_version"`
+	Changes     []CourseVersionChangeDto `json:"changes"`
+}
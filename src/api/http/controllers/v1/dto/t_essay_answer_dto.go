@@ -16,6 +16,11 @@ type TEssayAnswerCreateDto struct {
 	IsApprovedByTeacher bool   `json:"is_approved_by_teacher"`
 }
 
+// ApproveTEssayAnswerDto is used when a teacher approves an essay answer.
+type ApproveTEssayAnswerDto struct {
+	TeacherNotes *string `json:"teacher_notes"`
+}
+
 // TEssayAnswerResponseDto represents a detailed view of TEssayAnswer with related data.
 type TEssayAnswerResponseDto struct {
 	models.TEssayAnswer
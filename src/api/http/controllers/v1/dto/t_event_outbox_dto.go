@@ -0,0 +1,17 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// TEventOutboxResponseDto represents a single outbox entry.
+type TEventOutboxResponseDto struct {
+	models.TEventOutbox
+}
+
+type TEventOutboxFilterDto struct {
+	Status    string
+	EventName string
+	Spec      queryspec.Spec
+}
@@ -0,0 +1,25 @@
+package dto
+
+import "jk-api/internal/queryspec"
+
+// GenerateContentDto asks for drafts of one kind for a sub-lesson. Topic
+// defaults to the sub-lesson title. The prompt is rendered from the current
+// version of PromptTemplateID, or PromptTemplateVersion of it, falling back
+// to the default template of the kind. Variables holds the values of the
+// template variables the pipeline doesn't fill in, such as a student answer.
+// Essay questions are written for CodeQuestionID.
+type GenerateContentDto struct {
+	SubLessonID           int64          `json:"sub_lesson_id" validate:"required"`
+	Kind                  string         `json:"kind" validate:"required"`
+	Topic                 string         `json:"topic"`
+	PromptTemplateID      *int64         `json:"prompt_template_id"`
+	PromptTemplateVersion *int           `json:"prompt_template_version"`
+	Variables             map[string]any `json:"variables"`
+	Count                 int            `json:"count"`
+	CodeQuestionID        *int64         `json:"code_question_id"`
+}
+
+type GenerationFilterDto struct {
+	SubLessonID int64
+	Spec        queryspec.Spec
+}
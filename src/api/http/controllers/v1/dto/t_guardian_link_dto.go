@@ -0,0 +1,70 @@
+package dto
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// InviteGuardianStudentDto is sent by a guardian. Student is the student's
+// email or user code.
+type InviteGuardianStudentDto struct {
+	Student      string `json:"student"`
+	Relationship string `json:"relationship"`
+}
+
+type GuardianLinkFilterDto struct {
+	Status    string
+	StudentID int64
+	Spec      queryspec.Spec
+}
+
+type GuardianDashboardFilterDto struct {
+	// Days limits upcoming deadlines to the next Days days.
+	Days int64
+}
+
+type GuardianChildDto struct {
+	LinkID       int64   `json:"link_id"`
+	StudentID    int64   `json:"student_id"`
+	Name         string  `json:"name"`
+	Code         *string `json:"code"`
+	Relationship string  `json:"relationship"`
+	ClassID      *int64  `json:"class_id"`
+	ClassName    *string `json:"class_name"`
+}
+
+type GuardianBadgeDto struct {
+	BadgeID    int64  `json:"badge_id"`
+	Name       string `json:"name"`
+	Image      string `json:"image"`
+	CourseID   int64  `json:"course_id"`
+	CourseName string `json:"course_name"`
+}
+
+type GuardianCourseProgressDto struct {
+	CourseID           int64             `json:"course_id"`
+	CourseName         string            `json:"course_name"`
+	ProgressPercentage float64           `json:"progress_percentage"`
+	TotalScore         int               `json:"total_score"`
+	Badge              *GuardianBadgeDto `json:"badge"`
+}
+
+type GuardianEssayFeedbackDto struct {
+	EssayAnswerID     int64      `json:"essay_answer_id"`
+	EssayQuestionID   int64      `json:"essay_question_id"`
+	Question          string     `json:"question"`
+	KonteksPenjelasan int        `json:"konteks_penjelasan"`
+	Keruntutan        int        `json:"keruntutan"`
+	Kebenaran         int        `json:"kebenaran"`
+	TeacherNotes      string     `json:"teacher_notes"`
+	ReviewedAt        *time.Time `json:"reviewed_at"`
+}
+
+// GuardianDashboardDto is the read-only view a guardian gets of one child.
+type GuardianDashboardDto struct {
+	Child         GuardianChildDto            `json:"child"`
+	Courses       []GuardianCourseProgressDto `json:"courses"`
+	Badges        []GuardianBadgeDto          `json:"badges"`
+	EssayFeedback []GuardianEssayFeedbackDto  `json:"essay_feedback"`
+	Deadlines     []DueItemDto                `json:"deadlines"`
+}
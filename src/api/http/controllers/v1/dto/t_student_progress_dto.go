@@ -4,21 +4,74 @@ package dto
 
 import (
 	"jk-api/internal/database/models"
+	"time"
 )
 
-// CompleteTStudentProgressDto is used when completing a student's progress.
-type CompleteTStudentProgressDto struct {
-	UserID   int64 `json:"user_id" validate:"required"`
+// TransitionTStudentProgressDto is used when a student starts, completes or
+// submits a sub-lesson for review.
+type TransitionTStudentProgressDto struct {
 	SubLessonID int64 `json:"sub_lesson_id" validate:"required"`
-	Status   string `json:"status" validate:"required"`
 }
 
+// ApproveTStudentProgressDto is used by teachers to complete a sub-lesson a
+// student submitted for review.
+type ApproveTStudentProgressDto struct {
+	UserID      int64 `json:"user_id" validate:"required"`
+	SubLessonID int64 `json:"sub_lesson_id" validate:"required"`
+}
+
+// ResetTStudentProgressDto is used by teachers to reset a student's progress.
+// An empty SubLessonIDs resets every sub-lesson in the course.
+type ResetTStudentProgressDto struct {
+	UserID       int64   `json:"user_id" validate:"required"`
+	CourseID     int64   `json:"course_id" validate:"required"`
+	SubLessonIDs []int64 `json:"sub_lesson_ids"`
+}
+
+// RecomputeTStudentProgressDto selects the courses to recompute. An empty
+// CourseIDs recomputes every course that has at least one enrollment.
+type RecomputeTStudentProgressDto struct {
+	CourseIDs []int64 `json:"course_ids"`
+}
+
+type RecomputeTStudentProgressResultDto struct {
+	CourseID        int64 `json:"course_id"`
+	StudentsUpdated int   `json:"students_updated"`
+}
 
 // TStudentProgressResponseDto represents a detailed view of TStudentProgress with related data.
 type TStudentProgressResponseDto struct {
 	models.TStudentProgress
 }
 
+// TStudentProgressTreeDto is a course outline annotated with one student's progress.
+type TStudentProgressTreeDto struct {
+	UserID             int64                           `json:"user_id"`
+	CourseID           int64                           `json:"course_id"`
+	CourseName         string                          `json:"course_name"`
+	TotalSubLessons    int                             `json:"total_sub_lessons"`
+	CompletedCount     int                             `json:"completed_count"`
+	ProgressPercentage float64                         `json:"progress_percentage"`
+	Lessons            []TStudentProgressLessonNodeDto `json:"lessons"`
+}
+
+type TStudentProgressLessonNodeDto struct {
+	LessonID   int64                              `json:"lesson_id"`
+	Title      string                             `json:"title"`
+	Position   int                                `json:"position"`
+	SubLessons []TStudentProgressSubLessonNodeDto `json:"sub_lessons"`
+}
+
+type TStudentProgressSubLessonNodeDto struct {
+	SubLessonID   int64      `json:"sub_lesson_id"`
+	Title         string     `json:"title"`
+	OrderPosition int        `json:"order_position"`
+	Status        string     `json:"status"`
+	StartedAt     *time.Time `json:"started_at"`
+	CompletedAt   *time.Time `json:"completed_at"`
+	LastViewedAt  *time.Time `json:"last_viewed_at"`
+}
+
 type TStudentProgressFilterDto struct {
 	Preload     bool
 	Sort        string
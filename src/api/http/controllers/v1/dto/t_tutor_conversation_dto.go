@@ -0,0 +1,27 @@
+package dto
+
+import "jk-api/internal/queryspec"
+
+// CreateTutorConversationDto starts a conversation about a sub-lesson,
+// optionally about one of its materials or code questions.
+type CreateTutorConversationDto struct {
+	SubLessonID    int64  `json:"sub_lesson_id" validate:"required"`
+	MaterialID     *int64 `json:"material_id"`
+	CodeQuestionID *int64 `json:"code_question_id"`
+	Title          string `json:"title"`
+}
+
+type SendTutorMessageDto struct {
+	Content string `json:"content" validate:"required"`
+}
+
+type TutorConversationFilterDto struct {
+	// UserID limits the list to one student; students only see their own
+	// conversations.
+	UserID      int64
+	SubLessonID int64
+	Spec        queryspec.Spec
+	// Preview allows conversations about content that is not published
+	// yet. Students never preview.
+	Preview bool
+}
@@ -0,0 +1,18 @@
+package dto
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+)
+
+// TWebhookDeliveryResponseDto represents a single webhook delivery attempt log.
+type TWebhookDeliveryResponseDto struct {
+	models.TWebhookDelivery
+}
+
+type TWebhookDeliveryFilterDto struct {
+	SubscriptionID int64
+	Status         string
+	EventName      string
+	Spec           queryspec.Spec
+}
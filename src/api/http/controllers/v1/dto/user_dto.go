@@ -4,6 +4,7 @@ package dto
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"time"
 )
 
@@ -14,6 +15,8 @@ type CreateUserDto struct {
 	Email             string  `json:"email"`
 	Password          string  `json:"password"`
 	IsPasswordDefault *bool   `json:"is_password_default"`
+	SchoolID          *int64  `json:"school_id"`
+	DepartmentID      *int64  `json:"department_id"`
 }
 
 // UpdateUserDto is used when updating an existing User.
@@ -27,6 +30,10 @@ type UpdateUserDto struct {
 	ClassID           *int64     `json:"class_id"`
 	RoleIDs           *[]int64   `json:"has_roles"`
 	IsPasswordDefault *bool      `json:"is_password_default"`
+	SchoolID          *int64     `json:"school_id"`
+	DepartmentID      *int64     `json:"department_id"`
+	// ScopeDepartmentID limits what the user may see to a department subtree.
+	ScopeDepartmentID *int64     `json:"scope_department_id"`
 	DeletedAt         *time.Time `json:"deleted_at"`
 }
 
@@ -42,13 +49,12 @@ type BulkCreateUserDto struct {
 type UserFilterDto struct {
 	SquadID     int64
 	Preload     bool
-	Limit       int64
-	Cursor      int64
 	Name        string
-	Sort        string
-	Order       string
+	Spec        queryspec.Spec
 	ShowDeleted bool
 	Restore     bool
+	// DepartmentID limits the list to the department and its subtree.
+	DepartmentID int64
 }
 
 type BulkUpdateUserDto struct {
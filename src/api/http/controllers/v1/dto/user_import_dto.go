@@ -0,0 +1,46 @@
+package dto
+
+// UserImportOptionsDto controls a roster import. Mapping overrides header
+// detection and maps an import field (name, email, role, class_code,
+// password) to the header used in the file.
+type UserImportOptionsDto struct {
+	DryRun      bool              `json:"dry_run"`
+	Mapping     map[string]string `json:"mapping"`
+	DefaultRole string            `json:"default_role"`
+	ClassCode   string            `json:"class_code"`
+}
+
+type UserImportRowResultDto struct {
+	Row       int      `json:"row"`
+	Action    string   `json:"action,omitempty"`
+	UserID    int64    `json:"user_id,omitempty"`
+	Name      string   `json:"name"`
+	Email     string   `json:"email"`
+	Role      string   `json:"role"`
+	ClassCode string   `json:"class_code,omitempty"`
+	Errors    []string `json:"errors,omitempty"`
+}
+
+// UserImportCredentialDto carries a generated or supplied plaintext password.
+// It is only ever returned in the response of the import that set it.
+type UserImportCredentialDto struct {
+	Row       int    `json:"row"`
+	Name      string `json:"name"`
+	Email     string `json:"email"`
+	Code      string `json:"code"`
+	Role      string `json:"role"`
+	ClassCode string `json:"class_code,omitempty"`
+	Password  string `json:"password"`
+}
+
+type UserImportReportDto struct {
+	DryRun      bool                      `json:"dry_run"`
+	Columns     map[string]string         `json:"columns"`
+	Total       int                       `json:"total"`
+	Valid       int                       `json:"valid"`
+	Invalid     int                       `json:"invalid"`
+	Created     int                       `json:"created"`
+	Updated     int                       `json:"updated"`
+	Rows        []UserImportRowResultDto  `json:"rows"`
+	Credentials []UserImportCredentialDto `json:"credentials,omitempty"`
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
@@ -15,6 +16,12 @@ func NewAuthHandler(service services.AuthService) *AuthHandler {
 	return &AuthHandler{AuthService: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *AuthHandler) WithContext(ctx context.Context) *AuthHandler {
+	return &AuthHandler{AuthService: h.AuthService.WithTx(h.AuthService.GetDB().WithContext(ctx))}
+}
+
 func (h *AuthHandler) GetProfileHandler(token string) (*dto.ProfileResponse, error) {
 	user, err := h.AuthService.GetProfile(token)
 	fmt.Println(user)
@@ -62,26 +69,49 @@ func (h *AuthHandler) RefreshToken(Token string) (string, error) {
 }
 
 func (h *AuthHandler) Register(req *dto.RegisterRequest) (*dto.LoginResponse, string, error) {
-	user, err := h.AuthService.Register(req)
+	db := h.AuthService.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	authService := h.AuthService.WithTx(db)
+
+	user, err := authService.Register(req)
 	if err != nil {
 		return nil, "", err
 	}
 
 	// ❗ kalau teacher belum approve → jangan kasih token
 	if user.RoleID == 2 && !user.IsApprovedByAdmin {
+		if err := db.Commit().Error; err != nil {
+			return nil, "", err
+		}
+		committed = true
 		return nil, "", fmt.Errorf("akun teacher menunggu approval admin")
 	}
 
-	accessToken, err := h.AuthService.GenerateAccessToken(user)
+	accessToken, err := authService.GenerateAccessToken(user)
 	if err != nil {
 		return nil, "", err
 	}
 
-	refreshToken, err := h.AuthService.GenerateRefreshToken(user)
+	refreshToken, err := authService.GenerateRefreshToken(user)
 	if err != nil {
 		return nil, "", err
 	}
 
+	if err := db.Commit().Error; err != nil {
+		return nil, "", err
+	}
+	committed = true
+
 	data, err := mapper.AuthModelToDto(user, accessToken, refreshToken)
 	if err != nil {
 		return nil, "", err
@@ -0,0 +1,59 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/internal/database/models"
+	"jk-api/pkg/services/v1"
+)
+
+type ContentWorkflowHandler struct {
+	Service services.ContentWorkflowService
+}
+
+func NewContentWorkflowHandler(service services.ContentWorkflowService) *ContentWorkflowHandler {
+	return &ContentWorkflowHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *ContentWorkflowHandler) WithContext(ctx context.Context) *ContentWorkflowHandler {
+	return &ContentWorkflowHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+func (h *ContentWorkflowHandler) TransitionContentHandler(actorID int64, contentType string, id int64, action string, comment *string) (*models.TContentTransition, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	data, err := h.Service.WithTx(db).TransitionContent(actorID, contentType, id, action, comment)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return data, nil
+}
+
+func (h *ContentWorkflowHandler) AddContentCommentHandler(authorID int64, contentType string, id int64, body string) (*models.TContentComment, error) {
+	return h.Service.AddContentComment(authorID, contentType, id, body)
+}
+
+func (h *ContentWorkflowHandler) GetContentCommentsHandler(contentType string, id int64) ([]models.TContentComment, error) {
+	return h.Service.GetContentComments(contentType, id)
+}
+
+func (h *ContentWorkflowHandler) GetContentTransitionsHandler(contentType string, id int64) ([]models.TContentTransition, error) {
+	return h.Service.GetContentTransitions(contentType, id)
+}
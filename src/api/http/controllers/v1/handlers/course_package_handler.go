@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"bytes"
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/coursepkg"
+	"jk-api/pkg/services/v1"
+)
+
+type CoursePackageHandler struct {
+	Service services.CoursePackageService
+}
+
+func NewCoursePackageHandler(service services.CoursePackageService) *CoursePackageHandler {
+	return &CoursePackageHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *CoursePackageHandler) WithContext(ctx context.Context) *CoursePackageHandler {
+	return &CoursePackageHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// ExportCourseHandler returns the course as a zipped package.
+func (h *CoursePackageHandler) ExportCourseHandler(id int64) ([]byte, error) {
+	manifest, err := h.Service.ExportCourse(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := coursepkg.Write(&buf, manifest); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// ImportCourseHandler reads the uploaded package and runs the import in a
+// single transaction. Dry runs are always rolled back.
+func (h *CoursePackageHandler) ImportCourseHandler(data []byte, options dto.CourseImportOptionsDto) (*dto.CourseImportReportDto, error) {
+	manifest, err := coursepkg.Read(data)
+	if err != nil {
+		return nil, err
+	}
+
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	coursePackageService := h.Service.WithTx(db)
+
+	report, err := coursePackageService.ImportCourse(manifest, options)
+	if err != nil || options.DryRun {
+		return report, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return report, nil
+}
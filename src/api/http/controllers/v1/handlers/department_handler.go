@@ -0,0 +1,197 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/controllers/v1/mapper"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type DepartmentHandler struct {
+	Service services.DepartmentService
+}
+
+func NewDepartmentHandler(service services.DepartmentService) *DepartmentHandler {
+	return &DepartmentHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school and
+// department scope carried by ctx.
+func (h *DepartmentHandler) WithContext(ctx context.Context) *DepartmentHandler {
+	return &DepartmentHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+func (h *DepartmentHandler) CreateDepartmentHandler(input *dto.CreateDepartmentDto) (*dto.DepartmentResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	departmentService := h.Service.WithTx(db)
+
+	payload, err := mapper.CreateDepartmentDtoToModel(input)
+	if err != nil {
+		return nil, err
+	}
+
+	createdData, err := departmentService.CreateDepartment(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.DepartmentModelToResponseDto(createdData)
+}
+
+func (h *DepartmentHandler) BulkCreateDepartmentsHandler(input *dto.BulkCreateDepartments) ([]*models.Department, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	departmentService := h.Service.WithTx(db)
+
+	var departments []*models.Department
+	for _, createDto := range input.Data {
+		department, err := mapper.CreateDepartmentDtoToModel(createDto)
+		if err != nil {
+			return nil, err
+		}
+		if department != nil {
+			departments = append(departments, department)
+		}
+	}
+
+	created, err := departmentService.BulkCreateDepartments(departments)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return created, nil
+}
+
+func (h *DepartmentHandler) UpdateDepartmentHandler(id int64, input *dto.UpdateDepartmentDto) (*dto.DepartmentResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	departmentService := h.Service.WithTx(db)
+
+	payload, err := mapper.UpdateDepartmentDtoToModel(input)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedData, err := departmentService.UpdateDepartment(id, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.DepartmentModelToResponseDto(updatedData)
+}
+
+func (h *DepartmentHandler) DeleteDepartmentHandler(id int64) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := h.Service.WithTx(db).DeleteDepartment(id); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (h *DepartmentHandler) BulkDeleteDepartmentsHandler(input *dto.BulkDeleteDepartmentDto) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := h.Service.WithTx(db).BulkDeleteDepartments(input.IDs); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (h *DepartmentHandler) GetDepartmentByIDHandler(id int64, filter dto.DepartmentFilterDto) (*dto.DepartmentResponseDto, error) {
+	data, err := h.Service.GetDepartmentByID(id, filter)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.DepartmentModelToResponseDto(data)
+}
+
+func (h *DepartmentHandler) GetAllDepartmentsHandler(filter dto.DepartmentFilterDto) ([]models.Department, queryspec.Page, error) {
+	return h.Service.GetAllDepartments(filter)
+}
+
+func (h *DepartmentHandler) GetDepartmentTreeHandler() ([]*models.Department, error) {
+	return h.Service.GetDepartmentTree()
+}
+
+func (h *DepartmentHandler) GetDepartmentSummaryHandler(id int64) (*dto.DepartmentSummaryDto, error) {
+	return h.Service.GetDepartmentSummary(id)
+}
@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -16,6 +17,12 @@ func NewEssayQuestionHandler(service services.EssayQuestionService) *EssayQuesti
 	return &EssayQuestionHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *EssayQuestionHandler) WithContext(ctx context.Context) *EssayQuestionHandler {
+	return &EssayQuestionHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *EssayQuestionHandler) CreateEssayQuestionHandler(input *dto.EssayQuestionCreateDto) (*dto.EssayQuestionResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -50,7 +57,7 @@ func (h *EssayQuestionHandler) CreateEssayQuestionHandler(input *dto.EssayQuesti
 }
 
 func (h *EssayQuestionHandler) GetEssayQuestionsByCodeQuestionIDHandler(filter dto.EssayQuestionFilterDto, subLessonID int64) ([]dto.EssayQuestionResponseDto, error) {
-	data, err := h.Service.GetEssayQuestionsByCodeQuestionID(subLessonID)
+	data, err := h.Service.GetEssayQuestionsByCodeQuestionID(subLessonID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +73,7 @@ func (h *EssayQuestionHandler) GetEssayQuestionsByCodeQuestionIDHandler(filter d
 }
 
 func (h *EssayQuestionHandler) GetEssayQuestionHandlerByID(filter dto.EssayQuestionFilterDto, id int64) (*dto.EssayQuestionResponseDto, error) {
-	data, err := h.Service.GetEssayQuestionByID(id)
+	data, err := h.Service.GetEssayQuestionByID(id, filter)
 	if err != nil {
 		return nil, err
 	}
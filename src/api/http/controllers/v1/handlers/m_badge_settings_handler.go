@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
@@ -17,6 +18,12 @@ func NewMBadgeSettingsHandler(service services.MBadgeSettingsService) *MBadgeSet
 	return &MBadgeSettingsHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MBadgeSettingsHandler) WithContext(ctx context.Context) *MBadgeSettingsHandler {
+	return &MBadgeSettingsHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MBadgeSettingsHandler) CreateMBadgeSettingsHandler(input *dto.CreateMBadgeSettingsDto) (*dto.MBadgeSettingsResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
@@ -17,6 +18,12 @@ func NewMClassHandler(service services.MClassService) *MClassHandler {
 	return &MClassHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MClassHandler) WithContext(ctx context.Context) *MClassHandler {
+	return &MClassHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MClassHandler) CreateMClassHandler(input *dto.CreateMClassDto) (*dto.MClassResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -84,7 +91,30 @@ func (h *MClassHandler) UpdateMClassHandler(id int64, input *dto.UpdateMClassDto
 }
 
 func (h *MClassHandler) DeleteMClassHandler(id int64) error {
-	return h.Service.DeleteMClass(id)
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	MClassService := h.Service.WithTx(db)
+
+	if err := MClassService.DeleteMClass(id); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
 }
 
 func (h *MClassHandler) GetMClassByIDHandler(id int64, filter dto.MClassFilterDto) (*models.MClass, error) {
@@ -3,9 +3,11 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/services/v1"
 )
 
@@ -17,6 +19,12 @@ func NewMCourseHandler(service services.MCourseService) *MCourseHandler {
 	return &MCourseHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MCourseHandler) WithContext(ctx context.Context) *MCourseHandler {
+	return &MCourseHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MCourseHandler) CreateMCourseHandler(input *dto.CreateMCourseDto) (*dto.MCourseResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -83,6 +91,32 @@ func (h *MCourseHandler) UpdateMCourseHandler(id int64, input *dto.UpdateMCourse
 	return updatedData, nil
 }
 
+func (h *MCourseHandler) CloneMCourseHandler(id int64, actorID int64, input *dto.CloneMCourseDto) (*models.MCourse, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	clonedData, err := h.Service.WithTx(db).CloneMCourse(id, actorID, input)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return clonedData, nil
+}
+
 func (h *MCourseHandler) DeleteMCourseHandler(id int64) error {
 	return h.Service.DeleteMCourse(id)
 }
@@ -91,6 +125,6 @@ func (h *MCourseHandler) GetMCourseByIDHandler(id int64, filter dto.MCourseFilte
 	return h.Service.GetMCourseByID(id, filter)
 }
 
-func (h *MCourseHandler) GetAllMCoursesHandler(filter dto.MCourseFilterDto) ([]models.MCourse, error) {
+func (h *MCourseHandler) GetAllMCoursesHandler(filter dto.MCourseFilterDto) ([]models.MCourse, queryspec.Page, error) {
 	return h.Service.GetAllMCourses(filter)
 }
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/services/v1"
 )
 
@@ -16,6 +18,12 @@ func NewMLessonHandler(service services.MLessonService) *MLessonHandler {
 	return &MLessonHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MLessonHandler) WithContext(ctx context.Context) *MLessonHandler {
+	return &MLessonHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MLessonHandler) CreateMLessonHandler(input *dto.CreateMLessonDto) (*dto.MLessonResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -83,31 +91,38 @@ func (h *MLessonHandler) UpdateMLessonHandler(id int64, input *dto.UpdateMLesson
 }
 
 func (h *MLessonHandler) DeleteMLessonHandler(id int64) error {
-	return h.Service.DeleteMLesson(id)
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	mLessonService := h.Service.WithTx(db)
+
+	if err := mLessonService.DeleteMLesson(id); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
 }
 
 func (h *MLessonHandler) GetMLessonByIDHandler(id int64, filter dto.MLessonFilterDto) (*models.MLesson, error) {
 	return h.Service.GetMLessonByID(id, filter)
 }
 
-func (h *MLessonHandler) GetAllMLessonsHandler(filter dto.MLessonFilterDto) ([]models.MLesson, int64, error) {
-	data, err  := h.Service.GetAllMLessons(filter)
-	if err != nil {
-		return nil, 0, err
-	}
-	var total int64
-	db := h.Service.GetDB()
-	if filter.Name != "" {
-		db = db.Where("name ILIKE ?", "%"+filter.Name+"%")
-	}
-	if filter.ShowDeleted {
-		db = db.Unscoped().Where("deleted_at IS NOT NULL")
-	}
-	if err := db.Model(&models.MLesson{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return data, total, nil
+func (h *MLessonHandler) GetAllMLessonsHandler(filter dto.MLessonFilterDto) ([]models.MLesson, queryspec.Page, error) {
+	return h.Service.GetAllMLessons(filter)
 }
 
 func (h *MLessonHandler) BulkCreateMLessonsHandler(input *dto.BulkCreateMLessonsDto) ([]*models.MLesson, error) {
@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
@@ -17,6 +18,12 @@ func NewMLevelHandler(service services.MLevelService) *MLevelHandler {
 	return &MLevelHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MLevelHandler) WithContext(ctx context.Context) *MLevelHandler {
+	return &MLevelHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MLevelHandler) CreateMLevelHandler(input *dto.CreateMLevelDto) (*dto.MLevelResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/services/v1"
 )
 
@@ -16,6 +18,12 @@ func NewMMaterialHandler(service services.MMaterialService) *MMaterialHandler {
 	return &MMaterialHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MMaterialHandler) WithContext(ctx context.Context) *MMaterialHandler {
+	return &MMaterialHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MMaterialHandler) CreateMMaterialHandler(input *dto.CreateMMaterialDto) (*dto.MMaterialResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -90,24 +98,12 @@ func (h *MMaterialHandler) GetMMaterialByIDHandler(id int64, filter dto.MMateria
 	return h.Service.GetMMaterialByID(id, filter)
 }
 
-func (h *MMaterialHandler) GetAllMMaterialsHandler(filter dto.MMaterialFilterDto) ([]models.MMaterials, int64, error) {
-	data, err  := h.Service.GetAllMMaterials(filter)
-	if err != nil {
-		return nil, 0, err
-	}
-	var total int64
-	db := h.Service.GetDB()
-	if filter.Name != "" {
-		db = db.Where("title ILIKE ?", "%"+filter.Name+"%")
-	}
-	if filter.ShowDeleted {
-		db = db.Unscoped().Where("deleted_at IS NOT NULL")
-	}
-	if err := db.Model(&models.MMaterials{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
+func (h *MMaterialHandler) RenderMMaterialHandler(id int64, format string, filter dto.MMaterialFilterDto) (*dto.RenderedMMaterialDto, error) {
+	return h.Service.RenderMMaterial(id, format, filter)
+}
 
-	return data, total, nil
+func (h *MMaterialHandler) GetAllMMaterialsHandler(filter dto.MMaterialFilterDto) ([]models.MMaterials, queryspec.Page, error) {
+	return h.Service.GetAllMMaterials(filter)
 }
 
 func (h *MMaterialHandler) BulkCreateMMaterialsHandler(input *dto.BulkCreateMMaterialsDto) ([]*models.MMaterials, error) {
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type MPromptTemplateHandler struct {
+	Service services.MPromptTemplateService
+}
+
+func NewMPromptTemplateHandler(service services.MPromptTemplateService) *MPromptTemplateHandler {
+	return &MPromptTemplateHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MPromptTemplateHandler) WithContext(ctx context.Context) *MPromptTemplateHandler {
+	return &MPromptTemplateHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds.
+func (h *MPromptTemplateHandler) inTx(fn func(service services.MPromptTemplateService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *MPromptTemplateHandler) CreatePromptTemplateHandler(actorID int64, input *dto.CreatePromptTemplateDto) (*models.MPromptTemplate, error) {
+	var data *models.MPromptTemplate
+	err := h.inTx(func(service services.MPromptTemplateService) (err error) {
+		data, err = service.CreatePromptTemplate(actorID, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *MPromptTemplateHandler) UpdatePromptTemplateHandler(id int64, input *dto.UpdatePromptTemplateDto) (*models.MPromptTemplate, error) {
+	var data *models.MPromptTemplate
+	err := h.inTx(func(service services.MPromptTemplateService) (err error) {
+		data, err = service.UpdatePromptTemplate(id, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *MPromptTemplateHandler) GetPromptTemplatesHandler(filter dto.PromptTemplateFilterDto) ([]models.MPromptTemplate, queryspec.Page, error) {
+	return h.Service.GetPromptTemplates(filter)
+}
+
+func (h *MPromptTemplateHandler) GetPromptTemplateByIDHandler(id int64) (*models.MPromptTemplate, error) {
+	return h.Service.GetPromptTemplateByID(id)
+}
+
+func (h *MPromptTemplateHandler) CreatePromptTemplateVersionHandler(actorID int64, id int64, input *dto.CreatePromptTemplateVersionDto) (*models.TPromptTemplateVersion, error) {
+	var data *models.TPromptTemplateVersion
+	err := h.inTx(func(service services.MPromptTemplateService) (err error) {
+		data, err = service.CreatePromptTemplateVersion(actorID, id, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *MPromptTemplateHandler) GetPromptTemplateVersionsHandler(id int64, spec queryspec.Spec) ([]models.TPromptTemplateVersion, queryspec.Page, error) {
+	return h.Service.GetPromptTemplateVersions(id, spec)
+}
+
+func (h *MPromptTemplateHandler) GetPromptTemplateVersionHandler(id int64, version int) (*models.TPromptTemplateVersion, error) {
+	return h.Service.GetPromptTemplateVersion(id, version)
+}
+
+func (h *MPromptTemplateHandler) RenderPromptTemplateHandler(id int64, input *dto.RenderPromptTemplateDto) (*dto.RenderedPromptTemplateDto, error) {
+	return h.Service.RenderPromptTemplate(id, input)
+}
+
+func (h *MPromptTemplateHandler) CreatePromptEvaluationHandler(actorID int64, id int64, input *dto.CreatePromptEvaluationDto) (*models.TPromptEvaluation, error) {
+	var data *models.TPromptEvaluation
+	err := h.inTx(func(service services.MPromptTemplateService) (err error) {
+		data, err = service.CreatePromptEvaluation(actorID, id, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *MPromptTemplateHandler) GetPromptEvaluationsHandler(id int64, spec queryspec.Spec) ([]models.TPromptEvaluation, queryspec.Page, error) {
+	return h.Service.GetPromptEvaluations(id, spec)
+}
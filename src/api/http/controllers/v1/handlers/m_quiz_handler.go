@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type MQuizHandler struct {
+	Service services.MQuizService
+}
+
+func NewMQuizHandler(service services.MQuizService) *MQuizHandler {
+	return &MQuizHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MQuizHandler) WithContext(ctx context.Context) *MQuizHandler {
+	return &MQuizHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds.
+func (h *MQuizHandler) inTx(fn func(service services.MQuizService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *MQuizHandler) CreateQuizHandler(actorID int64, input *dto.CreateQuizDto) (*models.MQuiz, error) {
+	return h.Service.CreateQuiz(actorID, input)
+}
+
+func (h *MQuizHandler) UpdateQuizHandler(id int64, input *dto.UpdateQuizDto) (*models.MQuiz, error) {
+	return h.Service.UpdateQuiz(id, input)
+}
+
+func (h *MQuizHandler) DeleteQuizHandler(id int64) error {
+	return h.inTx(func(service services.MQuizService) error {
+		return service.DeleteQuiz(id)
+	})
+}
+
+func (h *MQuizHandler) GetQuizzesHandler(filter dto.QuizFilterDto) ([]models.MQuiz, queryspec.Page, error) {
+	return h.Service.GetQuizzes(filter)
+}
+
+func (h *MQuizHandler) GetQuizByIDHandler(id int64, preview bool) (*models.MQuiz, error) {
+	return h.Service.GetQuizByID(id, preview)
+}
+
+func (h *MQuizHandler) CreateQuizItemHandler(quizID int64, input *dto.QuizItemDto) (*models.MQuizItem, error) {
+	return h.Service.CreateQuizItem(quizID, input)
+}
+
+func (h *MQuizHandler) UpdateQuizItemHandler(quizID int64, id int64, input *dto.QuizItemDto) (*models.MQuizItem, error) {
+	return h.Service.UpdateQuizItem(quizID, id, input)
+}
+
+func (h *MQuizHandler) DeleteQuizItemHandler(quizID int64, id int64) error {
+	return h.inTx(func(service services.MQuizService) error {
+		return service.DeleteQuizItem(quizID, id)
+	})
+}
+
+func (h *MQuizHandler) StartAttemptHandler(actorID int64, quizID int64, preview bool) (*dto.QuizAttemptDto, error) {
+	var data *dto.QuizAttemptDto
+	err := h.inTx(func(service services.MQuizService) (err error) {
+		data, err = service.StartAttempt(actorID, quizID, preview)
+		return err
+	})
+	return data, err
+}
+
+// SubmitAttemptHandler grades the attempt and updates the course score in
+// one transaction.
+func (h *MQuizHandler) SubmitAttemptHandler(actorID int64, id int64, input *dto.SubmitQuizAttemptDto) (*dto.QuizAttemptDto, error) {
+	var data *dto.QuizAttemptDto
+	err := h.inTx(func(service services.MQuizService) (err error) {
+		data, err = service.SubmitAttempt(actorID, id, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *MQuizHandler) GetAttemptsHandler(quizID int64, filter dto.QuizFilterDto) ([]models.TQuizAttempt, queryspec.Page, error) {
+	return h.Service.GetAttempts(quizID, filter)
+}
+
+func (h *MQuizHandler) GetAttemptByIDHandler(id int64, filter dto.QuizFilterDto) (*dto.QuizAttemptDto, error) {
+	return h.Service.GetAttemptByID(id, filter)
+}
+
+func (h *MQuizHandler) GetQuizAnalyticsHandler(id int64) (*dto.QuizAnalyticsDto, error) {
+	return h.Service.GetQuizAnalytics(id)
+}
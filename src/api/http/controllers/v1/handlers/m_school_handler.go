@@ -0,0 +1,106 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/controllers/v1/mapper"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type MSchoolHandler struct {
+	Service services.MSchoolService
+}
+
+func NewMSchoolHandler(service services.MSchoolService) *MSchoolHandler {
+	return &MSchoolHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MSchoolHandler) WithContext(ctx context.Context) *MSchoolHandler {
+	return &MSchoolHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+func (h *MSchoolHandler) CreateMSchoolHandler(input *dto.CreateMSchoolDto) (*dto.MSchoolResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	mSchoolService := h.Service.WithTx(db)
+
+	payload, err := mapper.CreateMSchoolDtoToModel(input)
+	if err != nil {
+		return nil, err
+	}
+
+	createdData, err := mSchoolService.CreateMSchool(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.MSchoolModelToResponseDto(createdData)
+}
+
+func (h *MSchoolHandler) UpdateMSchoolHandler(id int64, input *dto.UpdateMSchoolDto) (*dto.MSchoolResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	mSchoolService := h.Service.WithTx(db)
+
+	payload, err := mapper.UpdateMSchoolDtoToModel(input)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedData, err := mSchoolService.UpdateMSchool(id, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.MSchoolModelToResponseDto(updatedData)
+}
+
+func (h *MSchoolHandler) DeleteMSchoolHandler(id int64) error {
+	return h.Service.DeleteMSchool(id)
+}
+
+func (h *MSchoolHandler) GetMSchoolByIDHandler(id int64) (*dto.MSchoolResponseDto, error) {
+	data, err := h.Service.GetMSchoolByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.MSchoolModelToResponseDto(data)
+}
+
+func (h *MSchoolHandler) GetAllMSchoolsHandler(filter dto.MSchoolFilterDto) ([]models.MSchool, queryspec.Page, error) {
+	return h.Service.GetAllMSchools(filter)
+}
@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/services/v1"
 )
 
@@ -16,6 +18,12 @@ func NewMSubLessonHandler(service services.MSubLessonService) *MSubLessonHandler
 	return &MSubLessonHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MSubLessonHandler) WithContext(ctx context.Context) *MSubLessonHandler {
+	return &MSubLessonHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *MSubLessonHandler) CreateMSubLessonHandler(input *dto.CreateMSubLessonDto) (*dto.MSubLessonResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -83,31 +91,38 @@ func (h *MSubLessonHandler) UpdateMSubLessonHandler(id int64, input *dto.UpdateM
 }
 
 func (h *MSubLessonHandler) DeleteMSubLessonHandler(id int64) error {
-	return h.Service.DeleteMSubLesson(id)
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	mSubLessonService := h.Service.WithTx(db)
+
+	if err := mSubLessonService.DeleteMSubLesson(id); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
 }
 
 func (h *MSubLessonHandler) GetMSubLessonByIDHandler(id int64, filter dto.MSubLessonFilterDto) (*models.MSubLesson, error) {
 	return h.Service.GetMSubLessonByID(id, filter)
 }
 
-func (h *MSubLessonHandler) GetAllMSubLessonsHandler(filter dto.MSubLessonFilterDto) ([]models.MSubLesson, int64, error) {
-	data, err  := h.Service.GetAllMSubLessons(filter)
-	if err != nil {
-		return nil, 0, err
-	}
-	var total int64
-	db := h.Service.GetDB()
-	if filter.Name != "" {
-		db = db.Where("name ILIKE ?", "%"+filter.Name+"%")
-	}
-	if filter.ShowDeleted {
-		db = db.Unscoped().Where("deleted_at IS NOT NULL")
-	}
-	if err := db.Model(&models.MSubLesson{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return data, total, nil
+func (h *MSubLessonHandler) GetAllMSubLessonsHandler(filter dto.MSubLessonFilterDto) ([]models.MSubLesson, queryspec.Page, error) {
+	return h.Service.GetAllMSubLessons(filter)
 }
 
 func (h *MSubLessonHandler) BulkCreateMSubLessonsHandler(input *dto.BulkCreateMSubLessonsDto) ([]*models.MSubLesson, error) {
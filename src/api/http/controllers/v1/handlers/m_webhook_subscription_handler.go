@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/controllers/v1/mapper"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type MWebhookSubscriptionHandler struct {
+	Service services.MWebhookSubscriptionService
+}
+
+func NewMWebhookSubscriptionHandler(service services.MWebhookSubscriptionService) *MWebhookSubscriptionHandler {
+	return &MWebhookSubscriptionHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MWebhookSubscriptionHandler) WithContext(ctx context.Context) *MWebhookSubscriptionHandler {
+	return &MWebhookSubscriptionHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// CreateMWebhookSubscriptionHandler is the only place the secret is returned.
+func (h *MWebhookSubscriptionHandler) CreateMWebhookSubscriptionHandler(input *dto.CreateMWebhookSubscriptionDto, createdBy int64) (*dto.MWebhookSubscriptionResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	mWebhookSubscriptionService := h.Service.WithTx(db)
+
+	payload, err := mapper.CreateMWebhookSubscriptionDtoToModel(input, createdBy)
+	if err != nil {
+		return nil, err
+	}
+
+	createdData, err := mWebhookSubscriptionService.CreateMWebhookSubscription(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.MWebhookSubscriptionModelToResponseDto(createdData, true)
+}
+
+func (h *MWebhookSubscriptionHandler) UpdateMWebhookSubscriptionHandler(id int64, input *dto.UpdateMWebhookSubscriptionDto) (*dto.MWebhookSubscriptionResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	mWebhookSubscriptionService := h.Service.WithTx(db)
+
+	payload, err := mapper.UpdateMWebhookSubscriptionDtoToModel(input)
+	if err != nil {
+		return nil, err
+	}
+
+	updatedData, err := mWebhookSubscriptionService.UpdateMWebhookSubscription(id, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.MWebhookSubscriptionModelToResponseDto(updatedData, false)
+}
+
+func (h *MWebhookSubscriptionHandler) DeleteMWebhookSubscriptionHandler(id int64) error {
+	return h.Service.DeleteMWebhookSubscription(id)
+}
+
+func (h *MWebhookSubscriptionHandler) GetMWebhookSubscriptionByIDHandler(id int64) (*dto.MWebhookSubscriptionResponseDto, error) {
+	data, err := h.Service.GetMWebhookSubscriptionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.MWebhookSubscriptionModelToResponseDto(data, false)
+}
+
+func (h *MWebhookSubscriptionHandler) GetAllMWebhookSubscriptionsHandler(filter dto.MWebhookSubscriptionFilterDto) ([]models.MWebhookSubscription, queryspec.Page, error) {
+	return h.Service.GetAllMWebhookSubscriptions(filter)
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/pkg/services/v1"
+	"net/url"
+	"time"
+)
+
+// cleanupBatchSize bounds how many expired uploads one cleanup run removes.
+const cleanupBatchSize = 100
+
+type MediaHandler struct {
+	Service services.MediaService
+}
+
+func NewMediaHandler(service services.MediaService) *MediaHandler {
+	return &MediaHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *MediaHandler) WithContext(ctx context.Context) *MediaHandler {
+	return &MediaHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds.
+func (h *MediaHandler) inTx(fn func(service services.MediaService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *MediaHandler) UploadMediaHandler(actorID int64, r io.Reader, protected bool) (*models.MMedia, error) {
+	var data *models.MMedia
+	err := h.inTx(func(service services.MediaService) (err error) {
+		data, err = service.UploadMedia(actorID, r, protected)
+		return err
+	})
+	return data, err
+}
+
+func (h *MediaHandler) CreateUploadHandler(actorID int64, input *dto.CreateMediaUploadDto) (*models.TMediaUpload, error) {
+	return h.Service.CreateUpload(actorID, input)
+}
+
+// AppendUploadHandler holds the upload row locked while the chunk is written
+// so two chunks for the same upload can't interleave.
+func (h *MediaHandler) AppendUploadHandler(actorID int64, id string, offset int64, chunk io.Reader) (*models.TMediaUpload, error) {
+	var data *models.TMediaUpload
+	err := h.inTx(func(service services.MediaService) (err error) {
+		data, err = service.AppendUpload(actorID, id, offset, chunk)
+		return err
+	})
+	return data, err
+}
+
+func (h *MediaHandler) GetUploadHandler(actorID int64, id string) (*models.TMediaUpload, error) {
+	return h.Service.GetUpload(actorID, id)
+}
+
+func (h *MediaHandler) GetMediaByIDHandler(id int64) (*models.MMedia, error) {
+	return h.Service.GetMediaByID(id)
+}
+
+func (h *MediaHandler) CleanupExpiredUploadsHandler(now time.Time) (int, error) {
+	return h.Service.CleanupExpiredUploads(now, cleanupBatchSize)
+}
+
+func (h *MediaHandler) SignContentMediaHandler(userID int64, staff bool, input dto.SignMediaURLDto) (*dto.SignedMediaURLDto, error) {
+	return h.Service.SignContentMedia(userID, staff, input)
+}
+
+func (h *MediaHandler) ResolveSignedMediaHandler(mediaID int64, query url.Values) (*models.MediaFile, error) {
+	return h.Service.ResolveSignedMedia(mediaID, query)
+}
+
+func (h *MediaHandler) ReadMediaHandler(key string, offset int64, length int64) (io.ReadCloser, error) {
+	return h.Service.ReadMedia(key, offset, length)
+}
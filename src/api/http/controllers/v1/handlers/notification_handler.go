@@ -0,0 +1,88 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/controllers/v1/mapper"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+	"time"
+)
+
+type NotificationHandler struct {
+	Service services.NotificationService
+}
+
+func NewNotificationHandler(service services.NotificationService) *NotificationHandler {
+	return &NotificationHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *NotificationHandler) WithContext(ctx context.Context) *NotificationHandler {
+	return &NotificationHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// DeliverPendingNotificationsHandler runs one push and omni-channel batch.
+// It deliberately opens no transaction: the rows are leased by the claim so
+// the channel calls never hold a lock.
+func (h *NotificationHandler) DeliverPendingNotificationsHandler(ctx context.Context, batchSize int, maxAttempts int, lease time.Duration) (int, error) {
+	return h.Service.DeliverPendingNotifications(ctx, batchSize, maxAttempts, lease)
+}
+
+func (h *NotificationHandler) GetMyNotificationsHandler(userID int64, filter dto.NotificationFilterDto) ([]models.Notification, queryspec.Page, error) {
+	return h.Service.GetMyNotifications(userID, filter)
+}
+
+func (h *NotificationHandler) CountUnreadNotificationsHandler(userID int64) (*dto.UnreadNotificationCountDto, error) {
+	unread, err := h.Service.CountUnreadNotifications(userID)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.UnreadNotificationCountDto{Unread: unread}, nil
+}
+
+func (h *NotificationHandler) MarkNotificationReadHandler(userID int64, id int64, read bool) (*dto.NotificationResponseDto, error) {
+	data, err := h.Service.MarkNotificationRead(userID, id, read)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.NotificationModelToResponseDto(data)
+}
+
+func (h *NotificationHandler) MarkAllNotificationsReadHandler(userID int64) (int64, error) {
+	return h.Service.MarkAllNotificationsRead(userID)
+}
+
+func (h *NotificationHandler) GetNotificationPreferencesHandler(userID int64) ([]models.MNotificationPreference, error) {
+	return h.Service.GetNotificationPreferences(userID)
+}
+
+func (h *NotificationHandler) UpdateNotificationPreferencesHandler(userID int64, input *dto.UpdateNotificationPreferencesDto) ([]models.MNotificationPreference, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	notificationService := h.Service.WithTx(db)
+
+	data, err := notificationService.UpdateNotificationPreferences(userID, input.Preferences)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return data, nil
+}
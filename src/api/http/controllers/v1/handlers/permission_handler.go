@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
@@ -17,6 +18,12 @@ func NewPermissionHandler(service services.PermissionService) *PermissionHandler
 	return &PermissionHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *PermissionHandler) WithContext(ctx context.Context) *PermissionHandler {
+	return &PermissionHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *PermissionHandler) CreatePermissionHandler(input *dto.CreatePermissionDto) (*dto.PermissionResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
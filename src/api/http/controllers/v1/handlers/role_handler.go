@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
@@ -17,6 +18,12 @@ func NewRoleHandler(service services.RoleService) *RoleHandler {
 	return &RoleHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *RoleHandler) WithContext(ctx context.Context) *RoleHandler {
+	return &RoleHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *RoleHandler) CreateRoleHandler(input *dto.CreateRoleDto) (*dto.RoleResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
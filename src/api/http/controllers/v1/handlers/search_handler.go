@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type SearchHandler struct {
+	Service services.SearchService
+}
+
+func NewSearchHandler(service services.SearchService) *SearchHandler {
+	return &SearchHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *SearchHandler) WithContext(ctx context.Context) *SearchHandler {
+	return &SearchHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+func (h *SearchHandler) SearchContentHandler(filter dto.SearchFilterDto) (*dto.SearchResponseDto, queryspec.Page, error) {
+	return h.Service.SearchContent(filter)
+}
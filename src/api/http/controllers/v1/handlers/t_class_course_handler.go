@@ -0,0 +1,90 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type TClassCourseHandler struct {
+	Service services.TClassCourseService
+}
+
+func NewTClassCourseHandler(service services.TClassCourseService) *TClassCourseHandler {
+	return &TClassCourseHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TClassCourseHandler) WithContext(ctx context.Context) *TClassCourseHandler {
+	return &TClassCourseHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds, so enrollments and deadlines land together with the assignment.
+func (h *TClassCourseHandler) inTx(fn func(service services.TClassCourseService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *TClassCourseHandler) AssignClassCourseHandler(actorID int64, isSuper bool, input *dto.AssignClassCourseDto) (*models.TClassCourse, error) {
+	var data *models.TClassCourse
+	err := h.inTx(func(service services.TClassCourseService) (err error) {
+		data, err = service.AssignClassCourse(actorID, isSuper, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *TClassCourseHandler) UpdateClassCourseHandler(actorID int64, isSuper bool, id int64, input *dto.UpdateClassCourseDto) (*models.TClassCourse, error) {
+	var data *models.TClassCourse
+	err := h.inTx(func(service services.TClassCourseService) (err error) {
+		data, err = service.UpdateClassCourse(actorID, isSuper, id, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *TClassCourseHandler) UnassignClassCourseHandler(actorID int64, isSuper bool, id int64) error {
+	return h.inTx(func(service services.TClassCourseService) error {
+		return service.UnassignClassCourse(actorID, isSuper, id)
+	})
+}
+
+func (h *TClassCourseHandler) GetClassCoursesHandler(actorID int64, isSuper bool, filter dto.ClassCourseFilterDto) ([]models.TClassCourse, queryspec.Page, error) {
+	return h.Service.GetClassCourses(actorID, isSuper, filter)
+}
+
+func (h *TClassCourseHandler) GetClassCourseByIDHandler(actorID int64, isSuper bool, id int64) (*models.TClassCourse, error) {
+	return h.Service.GetClassCourseByID(actorID, isSuper, id)
+}
+
+func (h *TClassCourseHandler) GetMyDueItemsHandler(userID int64, filter dto.DueItemFilterDto) ([]dto.DueItemDto, error) {
+	return h.Service.GetMyDueItems(userID, filter)
+}
+
+func (h *TClassCourseHandler) GetOverdueReportHandler(actorID int64, isSuper bool, id int64) (*dto.ClassCourseOverdueReportDto, error) {
+	return h.Service.GetOverdueReport(actorID, isSuper, id)
+}
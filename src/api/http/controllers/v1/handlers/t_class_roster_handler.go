@@ -0,0 +1,118 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type TClassRosterHandler struct {
+	Service services.TClassRosterService
+}
+
+func NewTClassRosterHandler(service services.TClassRosterService) *TClassRosterHandler {
+	return &TClassRosterHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TClassRosterHandler) WithContext(ctx context.Context) *TClassRosterHandler {
+	return &TClassRosterHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds. Every roster change writes its log in the same transaction.
+func (h *TClassRosterHandler) inTx(fn func(service services.TClassRosterService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *TClassRosterHandler) JoinMClassHandler(userID int64, input *dto.JoinMClassDto) (*models.TClassJoinRequest, error) {
+	var data *models.TClassJoinRequest
+	err := h.inTx(func(service services.TClassRosterService) (err error) {
+		data, err = service.JoinMClassByCode(userID, input.ClassCode)
+		return err
+	})
+	return data, err
+}
+
+func (h *TClassRosterHandler) RegenerateClassCodeHandler(actorID int64, isSuper bool, classID int64, input *dto.RegenerateClassCodeDto) (*models.MClass, error) {
+	var data *models.MClass
+	err := h.inTx(func(service services.TClassRosterService) (err error) {
+		data, err = service.RegenerateClassCode(actorID, isSuper, classID, input.ExpiresAt)
+		return err
+	})
+	return data, err
+}
+
+func (h *TClassRosterHandler) UpdateClassJoinSettingsHandler(actorID int64, isSuper bool, classID int64, input *dto.UpdateClassJoinSettingsDto) (*models.MClass, error) {
+	var data *models.MClass
+	err := h.inTx(func(service services.TClassRosterService) (err error) {
+		data, err = service.UpdateClassJoinSettings(actorID, isSuper, classID, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *TClassRosterHandler) GetClassJoinRequestsHandler(actorID int64, isSuper bool, classID int64, filter dto.ClassJoinRequestFilterDto) ([]models.TClassJoinRequest, queryspec.Page, error) {
+	return h.Service.GetClassJoinRequests(actorID, isSuper, classID, filter)
+}
+
+func (h *TClassRosterHandler) DecideClassJoinRequestHandler(actorID int64, isSuper bool, requestID int64, approve bool, input *dto.DecideClassJoinRequestDto) (*models.TClassJoinRequest, error) {
+	var data *models.TClassJoinRequest
+	err := h.inTx(func(service services.TClassRosterService) (err error) {
+		data, err = service.DecideClassJoinRequest(actorID, isSuper, requestID, approve, input.Note)
+		return err
+	})
+	return data, err
+}
+
+func (h *TClassRosterHandler) MoveClassStudentHandler(actorID int64, isSuper bool, classID int64, userID int64, input *dto.MoveClassStudentDto) error {
+	return h.inTx(func(service services.TClassRosterService) error {
+		return service.MoveClassStudent(actorID, isSuper, classID, userID, input.ToClassID)
+	})
+}
+
+func (h *TClassRosterHandler) RemoveClassStudentHandler(actorID int64, isSuper bool, classID int64, userID int64) error {
+	return h.inTx(func(service services.TClassRosterService) error {
+		return service.RemoveClassStudent(actorID, isSuper, classID, userID)
+	})
+}
+
+func (h *TClassRosterHandler) AssignClassTeacherHandler(actorID int64, isSuper bool, classID int64, input *dto.AssignClassTeacherDto) error {
+	return h.inTx(func(service services.TClassRosterService) error {
+		return service.AssignClassTeacher(actorID, isSuper, classID, input.TeacherID)
+	})
+}
+
+func (h *TClassRosterHandler) RemoveClassTeacherHandler(actorID int64, isSuper bool, classID int64, teacherID int64) error {
+	return h.inTx(func(service services.TClassRosterService) error {
+		return service.RemoveClassTeacher(actorID, isSuper, classID, teacherID)
+	})
+}
+
+func (h *TClassRosterHandler) GetClassRosterLogsHandler(actorID int64, isSuper bool, classID int64, filter dto.ClassRosterLogFilterDto) ([]models.TClassRosterLog, queryspec.Page, error) {
+	return h.Service.GetClassRosterLogs(actorID, isSuper, classID, filter)
+}
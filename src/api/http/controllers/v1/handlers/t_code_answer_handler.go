@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -16,6 +17,12 @@ func NewTCodeAnswerHandler(service services.TCodeAnswerService) *TCodeAnswerHand
 	return &TCodeAnswerHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TCodeAnswerHandler) WithContext(ctx context.Context) *TCodeAnswerHandler {
+	return &TCodeAnswerHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *TCodeAnswerHandler) CreateTCodeAnswerHandler(input *dto.TCodeAnswerCreateDto, userID int64) (*dto.TCodeAnswerResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -16,6 +17,12 @@ func NewTCodeQuestionHandler(service services.TCodeQuestionService) *TCodeQuesti
 	return &TCodeQuestionHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TCodeQuestionHandler) WithContext(ctx context.Context) *TCodeQuestionHandler {
+	return &TCodeQuestionHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *TCodeQuestionHandler) CreateTCodeQuestionHandler(input *dto.TCodeQuestionCreateDto) (*dto.TCodeQuestionResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -50,7 +57,7 @@ func (h *TCodeQuestionHandler) CreateTCodeQuestionHandler(input *dto.TCodeQuesti
 }
 
 func (h *TCodeQuestionHandler) GetTCodeQuestionsBySubLessonIDHandler(filter dto.TCodeQuestionFilterDto, subLessonID int64) ([]dto.TCodeQuestionResponseDto, error) {
-	data, err := h.Service.GetCodeQuestionsBySubLessonID(subLessonID)
+	data, err := h.Service.GetCodeQuestionsBySubLessonID(subLessonID, filter)
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +73,7 @@ func (h *TCodeQuestionHandler) GetTCodeQuestionsBySubLessonIDHandler(filter dto.
 }
 
 func (h *TCodeQuestionHandler) GetTCodeQuestionHandlerByID(filter dto.TCodeQuestionFilterDto, id int64) (*dto.TCodeQuestionResponseDto, error) {
-	data, err := h.Service.GetCodeQuestionByID(id)
+	data, err := h.Service.GetCodeQuestionByID(id, filter)
 	if err != nil {
 		return nil, err
 	}
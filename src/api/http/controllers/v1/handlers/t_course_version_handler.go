@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type TCourseVersionHandler struct {
+	Service services.TCourseVersionService
+}
+
+func NewTCourseVersionHandler(service services.TCourseVersionService) *TCourseVersionHandler {
+	return &TCourseVersionHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TCourseVersionHandler) WithContext(ctx context.Context) *TCourseVersionHandler {
+	return &TCourseVersionHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds, so content changes and their events are stored together.
+func (h *TCourseVersionHandler) inTx(fn func(service services.TCourseVersionService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *TCourseVersionHandler) GetCourseVersionsHandler(filter dto.CourseVersionFilterDto) ([]models.TCourseVersion, queryspec.Page, error) {
+	return h.Service.GetCourseVersions(filter)
+}
+
+func (h *TCourseVersionHandler) GetCourseVersionByIDHandler(id int64) (*models.TCourseVersion, error) {
+	return h.Service.GetCourseVersionByID(id)
+}
+
+func (h *TCourseVersionHandler) DiffCourseVersionsHandler(fromID int64, toID int64) (*dto.CourseVersionDiffDto, error) {
+	return h.Service.DiffCourseVersions(fromID, toID)
+}
+
+func (h *TCourseVersionHandler) RollbackCourseHandler(actorID int64, versionID int64) (*models.MCourse, error) {
+	var data *models.MCourse
+	err := h.inTx(func(service services.TCourseVersionService) (err error) {
+		data, err = service.RollbackCourse(actorID, versionID)
+		return err
+	})
+	return data, err
+}
+
+func (h *TCourseVersionHandler) MigrateEnrollmentHandler(actorID int64, isStaff bool, studentCourseID int64, input *dto.MigrateCourseVersionDto) (*models.TStudentCourse, error) {
+	var data *models.TStudentCourse
+	err := h.inTx(func(service services.TCourseVersionService) (err error) {
+		data, err = service.MigrateEnrollment(actorID, isStaff, studentCourseID, input.CourseVersionID)
+		return err
+	})
+	return data, err
+}
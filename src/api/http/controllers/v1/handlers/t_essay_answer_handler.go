@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -14,6 +15,12 @@ func NewTEssayAnswerHandler(service services.TEssayAnswerService) *TEssayAnswerH
 	return &TEssayAnswerHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TEssayAnswerHandler) WithContext(ctx context.Context) *TEssayAnswerHandler {
+	return &TEssayAnswerHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *TEssayAnswerHandler) CreateTEssayAnswerHandler(
 	input *dto.TEssayAnswerCreateDto,
 	userID int64,
@@ -60,6 +67,42 @@ func (h *TEssayAnswerHandler) CreateTEssayAnswerHandler(
 	return mapper.TEssayAnswerModelToResponseDto(data)
 }
 
+func (h *TEssayAnswerHandler) ApproveTEssayAnswerHandler(
+	id int64,
+	input *dto.ApproveTEssayAnswerDto,
+	teacherID int64,
+) (*dto.TEssayAnswerResponseDto, error) {
+
+	db := h.Service.GetDB().Begin()
+	committed := false
+
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	service := h.Service.WithTx(db)
+
+	data, err := service.ApproveTEssayAnswer(id, teacherID, input.TeacherNotes)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	committed = true
+
+	return mapper.TEssayAnswerModelToResponseDto(data)
+}
+
 func (h *TEssayAnswerHandler) GetTEssayAnswersByEssayQuestionIDAndUserIDHandler(
 	filter dto.TEssayAnswerFilterDto,
 	essayQuestionID int64,
@@ -0,0 +1,94 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/controllers/v1/mapper"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type TEventOutboxHandler struct {
+	Service services.TEventOutboxService
+}
+
+func NewTEventOutboxHandler(service services.TEventOutboxService) *TEventOutboxHandler {
+	return &TEventOutboxHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TEventOutboxHandler) WithContext(ctx context.Context) *TEventOutboxHandler {
+	return &TEventOutboxHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// RelayTEventOutboxHandler runs one relay batch. The claimed rows stay locked
+// until the batch's outcome has been recorded and the transaction commits.
+func (h *TEventOutboxHandler) RelayTEventOutboxHandler(ctx context.Context, batchSize int, maxAttempts int) (int, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	tEventOutboxService := h.Service.WithTx(db)
+
+	dispatched, err := tEventOutboxService.RelayPending(ctx, batchSize, maxAttempts)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return 0, err
+	}
+	committed = true
+
+	return dispatched, nil
+}
+
+func (h *TEventOutboxHandler) GetAllTEventOutboxesHandler(filter dto.TEventOutboxFilterDto) ([]models.TEventOutbox, queryspec.Page, error) {
+	return h.Service.GetAllTEventOutboxes(filter)
+}
+
+func (h *TEventOutboxHandler) GetTEventOutboxByIDHandler(id int64) (*dto.TEventOutboxResponseDto, error) {
+	data, err := h.Service.GetTEventOutboxByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TEventOutboxModelToResponseDto(data)
+}
+
+func (h *TEventOutboxHandler) RetryTEventOutboxHandler(id int64) (*dto.TEventOutboxResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	tEventOutboxService := h.Service.WithTx(db)
+
+	data, err := tEventOutboxService.RetryTEventOutbox(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.TEventOutboxModelToResponseDto(data)
+}
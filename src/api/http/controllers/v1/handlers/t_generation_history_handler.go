@@ -0,0 +1,82 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type TGenerationHistoryHandler struct {
+	Service services.TGenerationHistoryService
+}
+
+func NewTGenerationHistoryHandler(service services.TGenerationHistoryService) *TGenerationHistoryHandler {
+	return &TGenerationHistoryHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TGenerationHistoryHandler) WithContext(ctx context.Context) *TGenerationHistoryHandler {
+	return &TGenerationHistoryHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds.
+func (h *TGenerationHistoryHandler) inTx(fn func(service services.TGenerationHistoryService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+// GenerateContentHandler doesn't wrap the run in a transaction: the provider
+// may take a while, and failed runs must be logged all the same.
+func (h *TGenerationHistoryHandler) GenerateContentHandler(actorID int64, input *dto.GenerateContentDto) (*models.TGenerationHistory, error) {
+	return h.Service.GenerateContent(actorID, input)
+}
+
+func (h *TGenerationHistoryHandler) GetGenerationsHandler(filter dto.GenerationFilterDto) ([]models.TGenerationHistory, queryspec.Page, error) {
+	return h.Service.GetGenerations(filter)
+}
+
+func (h *TGenerationHistoryHandler) GetGenerationByIDHandler(id int64) (*models.TGenerationHistory, error) {
+	return h.Service.GetGenerationByID(id)
+}
+
+func (h *TGenerationHistoryHandler) AcceptGenerationHandler(actorID int64, id int64) (*models.TGenerationHistory, error) {
+	var data *models.TGenerationHistory
+	err := h.inTx(func(service services.TGenerationHistoryService) (err error) {
+		data, err = service.AcceptGeneration(actorID, id)
+		return err
+	})
+	return data, err
+}
+
+func (h *TGenerationHistoryHandler) DiscardGenerationHandler(actorID int64, id int64) (*models.TGenerationHistory, error) {
+	var data *models.TGenerationHistory
+	err := h.inTx(func(service services.TGenerationHistoryService) (err error) {
+		data, err = service.DiscardGeneration(actorID, id)
+		return err
+	})
+	return data, err
+}
@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+	"time"
+)
+
+type TGuardianLinkHandler struct {
+	Service services.TGuardianLinkService
+}
+
+func NewTGuardianLinkHandler(service services.TGuardianLinkService) *TGuardianLinkHandler {
+	return &TGuardianLinkHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TGuardianLinkHandler) WithContext(ctx context.Context) *TGuardianLinkHandler {
+	return &TGuardianLinkHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds, so link changes and their events are stored together.
+func (h *TGuardianLinkHandler) inTx(fn func(service services.TGuardianLinkService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *TGuardianLinkHandler) InviteStudentHandler(guardianID int64, input *dto.InviteGuardianStudentDto) (*models.TGuardianLink, error) {
+	var data *models.TGuardianLink
+	err := h.inTx(func(service services.TGuardianLinkService) (err error) {
+		data, err = service.InviteStudent(guardianID, input)
+		return err
+	})
+	return data, err
+}
+
+func (h *TGuardianLinkHandler) DecideGuardianInviteHandler(actorID int64, isSuper bool, id int64, accept bool) (*models.TGuardianLink, error) {
+	var data *models.TGuardianLink
+	err := h.inTx(func(service services.TGuardianLinkService) (err error) {
+		data, err = service.DecideGuardianInvite(actorID, isSuper, id, accept)
+		return err
+	})
+	return data, err
+}
+
+func (h *TGuardianLinkHandler) RevokeGuardianLinkHandler(actorID int64, isSuper bool, id int64) (*models.TGuardianLink, error) {
+	var data *models.TGuardianLink
+	err := h.inTx(func(service services.TGuardianLinkService) (err error) {
+		data, err = service.RevokeGuardianLink(actorID, isSuper, id)
+		return err
+	})
+	return data, err
+}
+
+func (h *TGuardianLinkHandler) GetGuardianLinksHandler(actorID int64, isSuper bool, filter dto.GuardianLinkFilterDto) ([]models.TGuardianLink, queryspec.Page, error) {
+	return h.Service.GetGuardianLinks(actorID, isSuper, filter)
+}
+
+func (h *TGuardianLinkHandler) GetChildrenHandler(guardianID int64) ([]dto.GuardianChildDto, error) {
+	return h.Service.GetChildren(guardianID)
+}
+
+func (h *TGuardianLinkHandler) GetChildDashboardHandler(guardianID int64, studentID int64, filter dto.GuardianDashboardFilterDto) (*dto.GuardianDashboardDto, error) {
+	return h.Service.GetChildDashboard(guardianID, studentID, filter)
+}
+
+// PublishWeeklyDigestsHandler is run by the digest loop.
+func (h *TGuardianLinkHandler) PublishWeeklyDigestsHandler(now time.Time) (int, error) {
+	var count int
+	err := h.inTx(func(service services.TGuardianLinkService) (err error) {
+		count, err = service.PublishWeeklyDigests(now)
+		return err
+	})
+	return count, err
+}
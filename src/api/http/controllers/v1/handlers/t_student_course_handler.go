@@ -3,6 +3,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -16,6 +17,12 @@ func NewTStudentCourseHandler(service services.TStudentCourseService) *TStudentC
 	return &TStudentCourseHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TStudentCourseHandler) WithContext(ctx context.Context) *TStudentCourseHandler {
+	return &TStudentCourseHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *TStudentCourseHandler) EnrollTStudentCourseHandler(userID int64, courseID int64) (*dto.TStudentCourseResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -1,7 +1,8 @@
-
 package handlers
 
 import (
+	"context"
+	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -15,7 +16,13 @@ func NewTStudentProgressHandler(service services.TStudentProgressService) *TStud
 	return &TStudentProgressHandler{Service: service}
 }
 
-func (h *TStudentProgressHandler) CompleteTStudentProgressHandler(input *dto.CompleteTStudentProgressDto, userID int64) (*dto.TStudentProgressResponseDto, error) {
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TStudentProgressHandler) WithContext(ctx context.Context) *TStudentProgressHandler {
+	return &TStudentProgressHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+func (h *TStudentProgressHandler) TransitionTStudentProgressHandler(input *dto.TransitionTStudentProgressDto, userID int64, status string) (*dto.TStudentProgressResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
 	defer func() {
@@ -30,12 +37,35 @@ func (h *TStudentProgressHandler) CompleteTStudentProgressHandler(input *dto.Com
 
 	TStudentProgressService := h.Service.WithTx(db)
 
-	payload, err := mapper.CompleteTStudentProgressDtoToModel(input, userID)
+	data, err := TStudentProgressService.TransitionTStudentProgress(userID, input.SubLessonID, status)
 	if err != nil {
 		return nil, err
 	}
 
-	createdData, err := TStudentProgressService.CompleteTStudentProgress(payload)
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.TStudentProgressModelToResponseDto(data)
+}
+
+func (h *TStudentProgressHandler) ApproveTStudentProgressHandler(actorID int64, isSuper bool, input *dto.ApproveTStudentProgressDto) (*dto.TStudentProgressResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	TStudentProgressService := h.Service.WithTx(db)
+
+	data, err := TStudentProgressService.ApproveTStudentProgress(actorID, isSuper, input.UserID, input.SubLessonID)
 	if err != nil {
 		return nil, err
 	}
@@ -45,6 +75,103 @@ func (h *TStudentProgressHandler) CompleteTStudentProgressHandler(input *dto.Com
 	}
 	committed = true
 
-	return mapper.TStudentProgressModelToResponseDto(createdData)
+	return mapper.TStudentProgressModelToResponseDto(data)
 }
 
+func (h *TStudentProgressHandler) ResetTStudentProgressHandler(actorID int64, isSuper bool, input *dto.ResetTStudentProgressDto) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	TStudentProgressService := h.Service.WithTx(db)
+
+	if err := TStudentProgressService.ResetTStudentProgress(actorID, isSuper, input.UserID, input.CourseID, input.SubLessonIDs); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+// RecomputeTStudentProgressHandler recomputes each course in its own
+// transaction so enrollment locks are held only for one course at a time.
+func (h *TStudentProgressHandler) RecomputeTStudentProgressHandler(input *dto.RecomputeTStudentProgressDto) ([]dto.RecomputeTStudentProgressResultDto, error) {
+	courseIDs := input.CourseIDs
+	if len(courseIDs) == 0 {
+		var err error
+		courseIDs, err = h.Service.GetEnrolledCourseIDs()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	results := make([]dto.RecomputeTStudentProgressResultDto, 0, len(courseIDs))
+	for _, courseID := range courseIDs {
+		updated, err := h.recomputeCourse(courseID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to recompute course %d: %w", courseID, err)
+		}
+		results = append(results, dto.RecomputeTStudentProgressResultDto{
+			CourseID:        courseID,
+			StudentsUpdated: updated,
+		})
+	}
+
+	return results, nil
+}
+
+func (h *TStudentProgressHandler) recomputeCourse(courseID int64) (int, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	TStudentProgressService := h.Service.WithTx(db)
+
+	updated, err := TStudentProgressService.RecomputeCourseProgress(courseID)
+	if err != nil {
+		return 0, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return 0, err
+	}
+	committed = true
+
+	return updated, nil
+}
+
+func (h *TStudentProgressHandler) GetProgressTreeHandler(userID int64, courseID int64, preview bool) (*dto.TStudentProgressTreeDto, error) {
+	course, progresses, err := h.Service.GetProgressTree(userID, courseID, preview)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TStudentProgressTreeToResponseDto(userID, course, progresses)
+}
+
+func (h *TStudentProgressHandler) GetStudentProgressTreeHandler(actorID int64, isSuper bool, userID int64, courseID int64) (*dto.TStudentProgressTreeDto, error) {
+	course, progresses, err := h.Service.GetStudentProgressTree(actorID, isSuper, userID, courseID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TStudentProgressTreeToResponseDto(userID, course, progresses)
+}
@@ -0,0 +1,79 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+)
+
+type TTutorConversationHandler struct {
+	Service services.TTutorConversationService
+}
+
+func NewTTutorConversationHandler(service services.TTutorConversationService) *TTutorConversationHandler {
+	return &TTutorConversationHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TTutorConversationHandler) WithContext(ctx context.Context) *TTutorConversationHandler {
+	return &TTutorConversationHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// inTx runs fn against a transactional copy of the service and commits when
+// it succeeds.
+func (h *TTutorConversationHandler) inTx(fn func(service services.TTutorConversationService) error) error {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	if err := fn(h.Service.WithTx(db)); err != nil {
+		return err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return err
+	}
+	committed = true
+
+	return nil
+}
+
+func (h *TTutorConversationHandler) CreateConversationHandler(actorID int64, input *dto.CreateTutorConversationDto, preview bool) (*models.TTutorConversation, error) {
+	return h.Service.CreateConversation(actorID, input, preview)
+}
+
+func (h *TTutorConversationHandler) GetConversationsHandler(filter dto.TutorConversationFilterDto) ([]models.TTutorConversation, queryspec.Page, error) {
+	return h.Service.GetConversations(filter)
+}
+
+func (h *TTutorConversationHandler) GetConversationByIDHandler(id int64, filter dto.TutorConversationFilterDto) (*models.TTutorConversation, error) {
+	return h.Service.GetConversationByID(id, filter)
+}
+
+// StartReplyHandler commits the student's message before the reply is
+// streamed, so it is kept even when the provider fails.
+func (h *TTutorConversationHandler) StartReplyHandler(actorID int64, id int64, input *dto.SendTutorMessageDto, preview bool) (*services.TutorTurn, error) {
+	var turn *services.TutorTurn
+	err := h.inTx(func(service services.TTutorConversationService) (err error) {
+		turn, err = service.StartReply(actorID, id, input, preview)
+		return err
+	})
+	return turn, err
+}
+
+// FinishReplyHandler doesn't wrap the reply in a transaction: it lasts as
+// long as the provider takes to write it.
+func (h *TTutorConversationHandler) FinishReplyHandler(ctx context.Context, turn *services.TutorTurn, onDelta func(text string) error) (*models.TTutorMessage, error) {
+	return h.Service.FinishReply(ctx, turn, onDelta)
+}
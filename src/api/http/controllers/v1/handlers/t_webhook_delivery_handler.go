@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/controllers/v1/mapper"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/services/v1"
+	"time"
+)
+
+type TWebhookDeliveryHandler struct {
+	Service services.TWebhookDeliveryService
+}
+
+func NewTWebhookDeliveryHandler(service services.TWebhookDeliveryService) *TWebhookDeliveryHandler {
+	return &TWebhookDeliveryHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TWebhookDeliveryHandler) WithContext(ctx context.Context) *TWebhookDeliveryHandler {
+	return &TWebhookDeliveryHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// DeliverPendingTWebhookDeliveriesHandler runs one delivery batch. It
+// deliberately opens no transaction: the rows are leased by the claim so the
+// receivers are called without holding a lock.
+func (h *TWebhookDeliveryHandler) DeliverPendingTWebhookDeliveriesHandler(ctx context.Context, batchSize int, maxAttempts int, lease time.Duration) (int, error) {
+	return h.Service.DeliverPending(ctx, batchSize, maxAttempts, lease)
+}
+
+func (h *TWebhookDeliveryHandler) SendTestTWebhookDeliveryHandler(ctx context.Context, subscriptionID int64) (*dto.TWebhookDeliveryResponseDto, error) {
+	data, err := h.Service.SendTestTWebhookDelivery(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TWebhookDeliveryModelToResponseDto(data)
+}
+
+func (h *TWebhookDeliveryHandler) ReplayTWebhookDeliveryHandler(id int64) (*dto.TWebhookDeliveryResponseDto, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	tWebhookDeliveryService := h.Service.WithTx(db)
+
+	data, err := tWebhookDeliveryService.ReplayTWebhookDelivery(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return mapper.TWebhookDeliveryModelToResponseDto(data)
+}
+
+func (h *TWebhookDeliveryHandler) GetAllTWebhookDeliveriesHandler(filter dto.TWebhookDeliveryFilterDto) ([]models.TWebhookDelivery, queryspec.Page, error) {
+	return h.Service.GetAllTWebhookDeliveries(filter)
+}
+
+func (h *TWebhookDeliveryHandler) GetTWebhookDeliveryByIDHandler(id int64) (*dto.TWebhookDeliveryResponseDto, error) {
+	data, err := h.Service.GetTWebhookDeliveryByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return mapper.TWebhookDeliveryModelToResponseDto(data)
+}
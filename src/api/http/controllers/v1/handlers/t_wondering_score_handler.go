@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"context"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/pkg/services/v1"
@@ -14,6 +15,12 @@ func NewTWonderingScoreHandler(service services.TWonderingScoreService) *TWonder
 	return &TWonderingScoreHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *TWonderingScoreHandler) WithContext(ctx context.Context) *TWonderingScoreHandler {
+	return &TWonderingScoreHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *TWonderingScoreHandler) CreateTWonderingScoreHandler(
 	input *dto.TWonderingScoreCreateDto,
 	userID int64,
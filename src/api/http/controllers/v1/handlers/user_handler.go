@@ -1,10 +1,12 @@
 package handlers
 
 import (
+	"context"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/controllers/v1/mapper"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/services/v1"
 )
 
@@ -16,6 +18,12 @@ func NewUserHandler(service services.UserService) *UserHandler {
 	return &UserHandler{Service: service}
 }
 
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *UserHandler) WithContext(ctx context.Context) *UserHandler {
+	return &UserHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
 func (h *UserHandler) CreateUserHandler(input *dto.CreateUserDto) (*dto.UserResponseDto, error) {
 	db := h.Service.GetDB().Begin()
 	committed := false
@@ -78,6 +86,34 @@ func (h *UserHandler) UpdateUserHandler(id int64, input *dto.UpdateUserDto) (*mo
 	return updatedData, nil
 }
 
+func (h *UserHandler) ApproveTeacherHandler(id int64, approvedBy int64) (*models.User, error) {
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	userService := h.Service.WithTx(db)
+
+	updatedData, err := userService.ApproveTeacher(id, approvedBy)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return updatedData, nil
+}
+
 func (h *UserHandler) DeleteUserHandler(id int64, isPermanent bool) error {
 	return h.Service.DeleteUser(id, isPermanent)
 }
@@ -86,25 +122,8 @@ func (h *UserHandler) GetUserByIDHandler(id int64, filter dto.UserFilterDto) (*m
 	return h.Service.GetUserByID(id, filter)
 }
 
-func (h *UserHandler) GetAllUsersHandler(filter dto.UserFilterDto) ([]models.User, int64, error) {
-	data, err := h.Service.GetAllUsers(filter)
-	if err != nil {
-		return nil, 0, err
-	}
-
-	var total int64
-	db := h.Service.GetDB()
-	if filter.Name != "" {
-		db = db.Where("name ILIKE ?", "%"+filter.Name+"%")
-	}
-	if filter.ShowDeleted {
-		db = db.Unscoped().Where("deleted_at IS NOT NULL")
-	}
-	if err := db.Model(&models.User{}).Count(&total).Error; err != nil {
-		return nil, 0, err
-	}
-
-	return data, total, nil
+func (h *UserHandler) GetAllUsersHandler(filter dto.UserFilterDto) ([]models.User, queryspec.Page, error) {
+	return h.Service.GetAllUsers(filter)
 }
 
 func (h *UserHandler) BulkCreateHandler(input *dto.BulkCreateUserDto) ([]*models.User, error) {
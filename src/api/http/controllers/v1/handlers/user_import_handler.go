@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"context"
+	"io"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/spreadsheet"
+	"jk-api/pkg/services/v1"
+)
+
+type UserImportHandler struct {
+	Service services.UserImportService
+}
+
+func NewUserImportHandler(service services.UserImportService) *UserImportHandler {
+	return &UserImportHandler{Service: service}
+}
+
+// WithContext returns a handler whose queries run under the school scope
+// carried by ctx.
+func (h *UserImportHandler) WithContext(ctx context.Context) *UserImportHandler {
+	return &UserImportHandler{Service: h.Service.WithTx(h.Service.GetDB().WithContext(ctx))}
+}
+
+// ImportUsersHandler parses the uploaded roster and runs the import in a
+// single transaction. Dry runs are always rolled back.
+func (h *UserImportHandler) ImportUsersHandler(actorID int64, isSuper bool, filename string, file io.Reader, options dto.UserImportOptionsDto) (*dto.UserImportReportDto, error) {
+	rows, err := spreadsheet.Read(filename, file)
+	if err != nil {
+		return nil, err
+	}
+
+	db := h.Service.GetDB().Begin()
+	committed := false
+	defer func() {
+		if r := recover(); r != nil {
+			db.Rollback()
+			panic(r)
+		}
+		if !committed {
+			db.Rollback()
+		}
+	}()
+
+	userImportService := h.Service.WithTx(db)
+
+	report, err := userImportService.ImportUsers(actorID, isSuper, rows, options)
+	if err != nil || options.DryRun {
+		return report, err
+	}
+
+	if err := db.Commit().Error; err != nil {
+		return nil, err
+	}
+	committed = true
+
+	return report, nil
+}
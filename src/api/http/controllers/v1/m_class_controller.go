@@ -6,6 +6,7 @@ import (
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/presenters"
 	"jk-api/internal/container"
+	"jk-api/internal/helper"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,8 +14,11 @@ import (
 
 func GetMClasses(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		departmentID, _ := helper.ParseQueryInt64(c, "department_id")
+
 		filter := dto.MClassFilterDto{
-			Preload: c.Query("preload", "false") == "true",
+			Preload:      c.Query("preload", "false") == "true",
+			DepartmentID: departmentID,
 		}
 
 		data, err := cn.MClassHandler.GetAllMClassesHandler(filter)
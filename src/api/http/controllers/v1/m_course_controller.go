@@ -6,6 +6,8 @@ import (
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/presenters"
 	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -13,15 +15,22 @@ import (
 
 func GetMCourses(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
+		spec, err := helper.ParseQuerySpec(c, &models.MCourse{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
 		filter := dto.MCourseFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Spec:    spec,
+			Preview: canPreviewContent(c),
 		}
 
-		data, err := cn.MCourseHandler.GetAllMCoursesHandler(filter)
+		data, page, err := cn.MCourseHandler.GetAllMCoursesHandler(filter)
 		if err != nil {
 			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
 		}
-		return presenters.SuccessResponse(c, data)
+		return presenters.SuccessResponse(c, data, page)
 	}
 }
 
@@ -29,6 +38,7 @@ func GetMCourseByID(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		filter := dto.MCourseFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
@@ -44,6 +54,30 @@ func GetMCourseByID(cn *container.AppContainer) fiber.Handler {
 	}
 }
 
+// GetMCourseTemplates lists the template gallery, drafts included, so authors
+// can start a course from one.
+func GetMCourseTemplates(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		spec, err := helper.ParseQuerySpec(c, &models.MCourse{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.MCourseFilterDto{
+			Preload:  c.Query("preload", "false") == "true",
+			Spec:     spec,
+			Preview:  true,
+			Template: true,
+		}
+
+		data, page, err := cn.MCourseHandler.GetAllMCoursesHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
 func CreateMCourse(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		var input dto.CreateMCourseDto	
@@ -79,6 +113,30 @@ func UpdateMCourse(cn *container.AppContainer) fiber.Handler {
 	}
 }
 
+func CloneMCourse(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.CloneMCourseDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		result, err := cn.MCourseHandler.CloneMCourseHandler(id, userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessCreatedResponse(c, result)
+	}
+}
+
 func DeleteMCourse(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
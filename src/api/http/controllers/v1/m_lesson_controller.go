@@ -7,6 +7,7 @@ import (
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/presenters"
 	"jk-api/internal/container"
+	"jk-api/internal/database/models"
 	"jk-api/internal/helper"
 	"strconv"
 
@@ -15,28 +16,27 @@ import (
 
 func GetMLessons(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		cursor, _ := helper.ParseQueryInt64(c, "cursor")
-		limit, _ := helper.ParseQueryInt64(c, "limit")
 		name := c.Query("name")
-		sort := c.Query("sort", "id")
-		order := c.Query("order", "asc")
 		deleted := c.Query("show_deleted", "false") == "true"
 
+		spec, err := helper.ParseQuerySpec(c, &models.MLesson{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
 		filter := dto.MLessonFilterDto{
 			Preload:     c.Query("preload", "false") == "true",
-			Cursor:      cursor,
-			Limit:       limit,
 			Name:        name,
-			Sort:        sort,
-			Order:       order,
+			Spec:        spec,
 			ShowDeleted: deleted,
+			Preview:     canPreviewContent(c),
 		}
 
-		data, total, err := cn.MLessonHandler.GetAllMLessonsHandler(filter)
+		data, page, err := cn.MLessonHandler.GetAllMLessonsHandler(filter)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
-		return presenters.SuccessResponse(c, data, total)
+		return presenters.SuccessResponse(c, data, page)
 	}
 }
 
@@ -44,6 +44,7 @@ func GetMLessonByID(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		filter := dto.MLessonFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
@@ -53,7 +54,7 @@ func GetMLessonByID(cn *container.AppContainer) fiber.Handler {
 
 		data, err := cn.MLessonHandler.GetMLessonByIDHandler(id, filter)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
@@ -68,7 +69,7 @@ func CreateMLessons(cn *container.AppContainer) fiber.Handler {
 
 		result, err := cn.MLessonHandler.CreateMLessonHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
@@ -88,7 +89,7 @@ func UpdateMLessons(cn *container.AppContainer) fiber.Handler {
 
 		updated, err := cn.MLessonHandler.UpdateMLessonHandler(id, &input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, updated)
 	}
@@ -102,7 +103,7 @@ func DeleteMLessons(cn *container.AppContainer) fiber.Handler {
 		}
 
 		if err := cn.MLessonHandler.DeleteMLessonHandler(id); err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponseWithMessage(c, "Lesson deleted successfully", nil)
 	}
@@ -120,7 +121,7 @@ func BulkCreateMLessons(cn *container.AppContainer) fiber.Handler {
 		
 		createdMLessons, err := cn.MLessonHandler.BulkCreateMLessonsHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, createdMLessons)
 	}
@@ -156,7 +157,7 @@ func BulkDeleteMLessons(cn *container.AppContainer) fiber.Handler {
 
 		err := cn.MLessonHandler.BulkDeleteMLessonsHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 
 		return presenters.SuccessResponseWithMessage(c, fmt.Sprintf("Successfully deleted %d Lessons", len(input.IDs)), nil)
@@ -3,11 +3,16 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/presenters"
+	"jk-api/internal/constant"
 	"jk-api/internal/container"
+	"jk-api/internal/database/models"
 	"jk-api/internal/helper"
+	"jk-api/internal/richtext"
+	"jk-api/pkg/services/v1"
 	"strconv"
 
 	"github.com/gofiber/fiber/v2"
@@ -15,28 +20,27 @@ import (
 
 func GetMMaterials(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		cursor, _ := helper.ParseQueryInt64(c, "cursor")
-		limit, _ := helper.ParseQueryInt64(c, "limit")
 		name := c.Query("name")
-		sort := c.Query("sort", "id")
-		order := c.Query("order", "asc")
 		deleted := c.Query("show_deleted", "false") == "true"
 
+		spec, err := helper.ParseQuerySpec(c, &models.MMaterials{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
 		filter := dto.MMaterialFilterDto{
 			Preload:     c.Query("preload", "false") == "true",
-			Cursor:      cursor,
-			Limit:       limit,
 			Name:        name,
-			Sort:        sort,
-			Order:       order,
+			Spec:        spec,
 			ShowDeleted: deleted,
+			Preview:     canPreviewContent(c),
 		}
 
-		data, total, err := cn.MMaterialHandler.GetAllMMaterialsHandler(filter)
+		data, page, err := cn.MMaterialHandler.GetAllMMaterialsHandler(filter)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
-		return presenters.SuccessResponse(c, data, total)
+		return presenters.SuccessResponse(c, data, page)
 	}
 }
 
@@ -44,6 +48,7 @@ func GetMMaterialByID(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		filter := dto.MMaterialFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
@@ -53,7 +58,29 @@ func GetMMaterialByID(cn *container.AppContainer) fiber.Handler {
 
 		data, err := cn.MMaterialHandler.GetMMaterialByIDHandler(id, filter)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// RenderMMaterial returns the document of a material as sanitized HTML,
+// plain text or Markdown, picked with ?format= (html by default).
+func RenderMMaterial(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := dto.MMaterialFilterDto{
+			Preview: canPreviewContent(c),
+		}
+
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		format := c.Query("format", constant.MaterialFormatHTML)
+		data, err := cn.MMaterialHandler.RenderMMaterialHandler(id, format, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, materialErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
@@ -68,7 +95,7 @@ func CreateMMaterials(cn *container.AppContainer) fiber.Handler {
 
 		result, err := cn.MMaterialHandler.CreateMMaterialHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, materialErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
@@ -88,7 +115,7 @@ func UpdateMMaterials(cn *container.AppContainer) fiber.Handler {
 
 		updated, err := cn.MMaterialHandler.UpdateMMaterialHandler(id, &input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, materialErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, updated)
 	}
@@ -102,7 +129,7 @@ func DeleteMMaterials(cn *container.AppContainer) fiber.Handler {
 		}
 
 		if err := cn.MMaterialHandler.DeleteMMaterialHandler(id); err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponseWithMessage(c, "Material deleted successfully", nil)
 	}
@@ -120,7 +147,7 @@ func BulkCreateMMaterials(cn *container.AppContainer) fiber.Handler {
 		
 		createdMMaterials, err := cn.MMaterialHandler.BulkCreateMMaterialsHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, materialErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, createdMMaterials)
 	}
@@ -136,7 +163,7 @@ func BulkUpdateMMaterials(cn *container.AppContainer) fiber.Handler {
 		// Use the handler's bulk update which performs UpdateMany in one query
 		updatedUsers, err := cn.MMaterialHandler.BulkUpdateMMaterialsHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponseWithMessage(c, fiber.StatusInternalServerError, fmt.Sprintf("Failed to bulk update users: %v", err))
+			return presenters.ErrorResponseWithMessage(c, materialErrorStatus(err), fmt.Sprintf("Failed to bulk update users: %v", err))
 		}
 
 		return presenters.SuccessResponse(c, updatedUsers)
@@ -156,9 +183,19 @@ func BulkDeleteMMaterials(cn *container.AppContainer) fiber.Handler {
 
 		err := cn.MMaterialHandler.BulkDeleteMMaterialsHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 
 		return presenters.SuccessResponseWithMessage(c, fmt.Sprintf("Successfully deleted %d Materials", len(input.IDs)), nil)
 	}
 }
+
+// materialErrorStatus reports documents that don't fit the schema as bad
+// requests.
+func materialErrorStatus(err error) int {
+	if errors.Is(err, richtext.ErrInvalid) || errors.Is(err, services.ErrMaterialFormat) ||
+		errors.Is(err, services.ErrPromptTemplateInvalid) {
+		return fiber.StatusBadRequest
+	}
+	return fiber.StatusInternalServerError
+}
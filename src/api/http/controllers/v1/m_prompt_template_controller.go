@@ -0,0 +1,228 @@
+package controllers
+
+import (
+	"errors"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/generation"
+	"jk-api/internal/helper"
+	"jk-api/pkg/services/v1"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetPromptTemplates(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		spec, err := helper.ParseQuerySpec(c, &models.MPromptTemplate{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.PromptTemplateFilterDto{
+			Kind: c.Query("kind"),
+			Name: c.Query("name"),
+			Spec: spec,
+		}
+
+		data, page, err := cn.MPromptTemplateHandler.GetPromptTemplatesHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+// GetPromptTemplateByID returns a template with its current version.
+func GetPromptTemplateByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.MPromptTemplateHandler.GetPromptTemplateByIDHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func CreatePromptTemplate(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.CreatePromptTemplateDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MPromptTemplateHandler.CreatePromptTemplateHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, promptTemplateErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UpdatePromptTemplate(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.UpdatePromptTemplateDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		data, err := cn.MPromptTemplateHandler.UpdatePromptTemplateHandler(id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, promptTemplateErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetPromptTemplateVersions(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		spec, err := helper.ParseQuerySpec(c, &models.TPromptTemplateVersion{}, "version", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		data, page, err := cn.MPromptTemplateHandler.GetPromptTemplateVersionsHandler(id, spec)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetPromptTemplateVersion(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+		version, err := strconv.Atoi(c.Params("version"))
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid version")
+		}
+
+		data, err := cn.MPromptTemplateHandler.GetPromptTemplateVersionHandler(id, version)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// CreatePromptTemplateVersion adds a version to a template and makes it the
+// current one.
+func CreatePromptTemplateVersion(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.CreatePromptTemplateVersionDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MPromptTemplateHandler.CreatePromptTemplateVersionHandler(userID, id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, promptTemplateErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// RenderPromptTemplate previews a version of a template filled in with the
+// given variables.
+func RenderPromptTemplate(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.RenderPromptTemplateDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		data, err := cn.MPromptTemplateHandler.RenderPromptTemplateHandler(id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, promptTemplateErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetPromptEvaluations(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		spec, err := helper.ParseQuerySpec(c, &models.TPromptEvaluation{}, "created_at", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		data, page, err := cn.MPromptTemplateHandler.GetPromptEvaluationsHandler(id, spec)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+// CreatePromptEvaluation notes how a version of a template performed.
+func CreatePromptEvaluation(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.CreatePromptEvaluationDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MPromptTemplateHandler.CreatePromptEvaluationHandler(userID, id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, promptTemplateErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func promptTemplateErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrPromptTemplateInvalid),
+		errors.Is(err, generation.ErrInvalidTemplate),
+		errors.Is(err, generation.ErrInvalidVariables):
+		return fiber.StatusBadRequest
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
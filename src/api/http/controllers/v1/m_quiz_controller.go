@@ -0,0 +1,299 @@
+package controllers
+
+import (
+	"errors"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"jk-api/internal/quiz"
+	"jk-api/pkg/services/v1"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetQuizzes lists quizzes. Students only see published quizzes of
+// published lessons and courses.
+func GetQuizzes(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subLessonID, _ := helper.ParseQueryInt64(c, "sub_lesson_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.MQuiz{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.QuizFilterDto{
+			SubLessonID: subLessonID,
+			Spec:        spec,
+			Preview:     canPreviewContent(c),
+		}
+
+		data, page, err := cn.MQuizHandler.GetQuizzesHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+// GetQuizByID returns a quiz; teachers also get its items and answers.
+func GetQuizByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.MQuizHandler.GetQuizByIDHandler(id, canPreviewContent(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func CreateQuiz(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.CreateQuizDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+		if input.SubLessonID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid sub lesson ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MQuizHandler.CreateQuizHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UpdateQuiz(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.UpdateQuizDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		data, err := cn.MQuizHandler.UpdateQuizHandler(id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func DeleteQuiz(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		if err := cn.MQuizHandler.DeleteQuizHandler(id); err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, fiber.Map{"id": id})
+	}
+}
+
+func CreateQuizItem(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.QuizItemDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		data, err := cn.MQuizHandler.CreateQuizItemHandler(id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UpdateQuizItem(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+		itemID, err := strconv.ParseInt(c.Params("itemID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid item ID")
+		}
+
+		var input dto.QuizItemDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		data, err := cn.MQuizHandler.UpdateQuizItemHandler(id, itemID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func DeleteQuizItem(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+		itemID, err := strconv.ParseInt(c.Params("itemID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid item ID")
+		}
+
+		if err := cn.MQuizHandler.DeleteQuizItemHandler(id, itemID); err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, fiber.Map{"id": itemID})
+	}
+}
+
+// StartQuizAttempt resumes the caller's unsubmitted attempt at a quiz or
+// starts a new one.
+func StartQuizAttempt(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MQuizHandler.StartAttemptHandler(userID, id, canPreviewContent(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// SubmitQuizAttempt grades the caller's attempt and returns it with the
+// right answers.
+func SubmitQuizAttempt(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("attemptID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid attempt ID")
+		}
+
+		var input dto.SubmitQuizAttemptDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MQuizHandler.SubmitAttemptHandler(userID, id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, quizErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// GetQuizAttempts lists the attempts at a quiz: the caller's own for
+// students, those of their school for teachers, who may filter by student.
+func GetQuizAttempts(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		spec, err := helper.ParseQuerySpec(c, &models.TQuizAttempt{}, "started_at", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := quizAttemptFilter(c)
+		filter.Spec = spec
+		if filter.Preview {
+			filter.UserID, _ = helper.ParseQueryInt64(c, "user_id")
+		}
+
+		data, page, err := cn.MQuizHandler.GetAttemptsHandler(id, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetQuizAttemptByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("attemptID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid attempt ID")
+		}
+
+		data, err := cn.MQuizHandler.GetAttemptByIDHandler(id, quizAttemptFilter(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// GetQuizAnalytics sums up how the students of the caller's school did on
+// each item of a quiz.
+func GetQuizAnalytics(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.MQuizHandler.GetQuizAnalyticsHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// quizAttemptFilter limits students to their own attempts.
+func quizAttemptFilter(c *fiber.Ctx) dto.QuizFilterDto {
+	filter := dto.QuizFilterDto{Preview: canPreviewContent(c)}
+	if !filter.Preview {
+		filter.UserID = c.Locals("user_id").(int64)
+	}
+	return filter
+}
+
+func quizErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrQuizInvalid),
+		errors.Is(err, quiz.ErrInvalidItem),
+		errors.Is(err, quiz.ErrInvalidResponse):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrQuizInUse),
+		errors.Is(err, services.ErrQuizAttemptsExhausted),
+		errors.Is(err, services.ErrQuizAttemptSubmitted):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
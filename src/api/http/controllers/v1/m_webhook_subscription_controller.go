@@ -0,0 +1,113 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetMWebhookSubscriptions(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		spec, err := helper.ParseQuerySpec(c, &models.MWebhookSubscription{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.MWebhookSubscriptionFilterDto{
+			Spec: spec,
+		}
+
+		data, page, err := cn.MWebhookSubscriptionHandler.GetAllMWebhookSubscriptionsHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetMWebhookSubscriptionByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.MWebhookSubscriptionHandler.GetMWebhookSubscriptionByIDHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func CreateMWebhookSubscription(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.CreateMWebhookSubscriptionDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		result, err := cn.MWebhookSubscriptionHandler.CreateMWebhookSubscriptionHandler(&input, userID)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, result)
+	}
+}
+
+func UpdateMWebhookSubscription(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.UpdateMWebhookSubscriptionDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid input")
+		}
+
+		updated, err := cn.MWebhookSubscriptionHandler.UpdateMWebhookSubscriptionHandler(id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, updated)
+	}
+}
+
+func DeleteMWebhookSubscription(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		if err := cn.MWebhookSubscriptionHandler.DeleteMWebhookSubscriptionHandler(id); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Webhook subscription deleted successfully", nil)
+	}
+}
+
+func TestMWebhookSubscription(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.TWebhookDeliveryHandler.SendTestTWebhookDeliveryHandler(c.UserContext(), id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
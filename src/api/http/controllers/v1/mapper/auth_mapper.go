@@ -12,6 +12,7 @@ func AuthModelToDto(data *models.User, accessToken, refreshToken string) (*dto.L
 			Name:     data.Name,
 			Email:    data.Email,
 			HasRoles: data.HasRoles,
+			SchoolID: data.SchoolID,
 		},
 		AccessToken:  accessToken,
 		RefreshToken: refreshToken,
@@ -29,6 +30,7 @@ func AuthModelToProfile(data *models.User) (*dto.ProfileResponse, error) {
 		Email:             data.Email,
 		HasRoles:          data.HasRoles,
 		HasClass:          data.HasClass,
+		School:            data.School,
 		IsPasswordDefault: data.IsPasswordDefault,
 	}
 	return response, nil
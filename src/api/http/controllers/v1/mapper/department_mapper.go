@@ -11,8 +11,9 @@ func CreateDepartmentDtoToModel(dto *dto.CreateDepartmentDto) (*models.Departmen
 	}
 
 	data := &models.Department{
-		Name: dto.Name,
-		Code: dto.Code,
+		Name:     dto.Name,
+		Code:     dto.Code,
+		ParentID: dto.ParentID,
 	}
 
 	return data, nil
@@ -31,6 +32,12 @@ func UpdateDepartmentDtoToModel(dto *dto.UpdateDepartmentDto) (map[string]interf
 	if dto.Code != nil {
 		updates["code"] = *dto.Code
 	}
+	if dto.ParentID != nil {
+		updates["parent_id"] = *dto.ParentID
+	}
+	if dto.ClearParent {
+		updates["parent_id"] = nil
+	}
 	updates["deleted_at"] = dto.DeletedAt
 
 	return updates, nil
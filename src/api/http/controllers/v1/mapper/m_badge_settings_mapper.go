@@ -15,6 +15,7 @@ func CreateMBadgeSettingsDtoToModel(dto *dto.CreateMBadgeSettingsDto) (*models.M
 	data := &models.MBadgeSettings{
 		Name:     dto.Name,
 		Image:    dto.Image,
+		ImageMediaID: dto.ImageMediaID,
 		MinScore: dto.MinScore,
 		MaxScore: dto.MaxScore,
 	}
@@ -38,6 +39,10 @@ func UpdateMBadgeSettingsDtoToModel(dto *dto.UpdateMBadgeSettingsDto) (
 		payload["image"] = *dto.Image
 	}
 
+	if dto.ImageMediaID != nil {
+		payload["image_media_id"] = *dto.ImageMediaID
+	}
+
 	if dto.MinScore != nil {
 		payload["min_score"] = *dto.MinScore
 	}
@@ -16,6 +16,8 @@ func CreateMClassDtoToModel(dto *dto.CreateMClassDto) (*models.MClass, error) {
 		ClassName: dto.ClassName,
 		SchoolName: dto.SchoolName,
 		ClassCode: dto.ClassCode,
+		SchoolID:  dto.SchoolID,
+		DepartmentID: dto.DepartmentID,
 	}
 
 	return data, nil
@@ -40,6 +42,12 @@ func UpdateMClassDtoToModel(dto *dto.UpdateMClassDto) (
 	if dto.ClassCode != nil {
 		payload["class_code"] = *dto.ClassCode
 	}
+	if dto.SchoolID != nil {
+		payload["school_id"] = *dto.SchoolID
+	}
+	if dto.DepartmentID != nil {
+		payload["department_id"] = *dto.DepartmentID
+	}
 	if dto.IsActive != nil {
 		payload["is_active"] = *dto.IsActive
 	}
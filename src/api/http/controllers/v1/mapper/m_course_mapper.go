@@ -16,6 +16,8 @@ func CreateMCourseDtoToModel(dto *dto.CreateMCourseDto) (*models.MCourse, error)
 		CourseName:     dto.CourseName,
 		Description: dto.Description,
 		ImgThumbnail: dto.ImgThumbnail,
+		ImgThumbnailMediaID: dto.ImgThumbnailMediaID,
+		SchoolID: dto.SchoolID,
 	}
 
 	return data, nil
@@ -37,16 +39,23 @@ func UpdateMCourseDtoToModel(dto *dto.UpdateMCourseDto) (
 		payload["img_thumbnail"] = *dto.ImgThumbnail
 	}
 
+	if dto.ImgThumbnailMediaID != nil {
+		payload["img_thumbnail_media_id"] = *dto.ImgThumbnailMediaID
+	}
+
 	if dto.Description != nil {
 		payload["description"] = *dto.Description
 	}
 
-	if dto.Published != nil {
-		payload["published"] = *dto.Published
-	}
 	if dto.IsActive != nil {
 		payload["is_active"] = *dto.IsActive
 	}
+	if dto.SchoolID != nil {
+		payload["school_id"] = *dto.SchoolID
+	}
+	if dto.IsTemplate != nil {
+		payload["is_template"] = *dto.IsTemplate
+	}
 
 	return payload, associations, nil
 }
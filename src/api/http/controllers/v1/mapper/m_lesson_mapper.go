@@ -16,6 +16,8 @@ func CreateMLessonDtoToModel(dto *dto.CreateMLessonDto) (*models.MLesson, error)
 		Title:       dto.Title,
 		Description: dto.Description,
 		Position:    dto.Position,
+		ImgThumbnail:        dto.ImgThumbnail,
+		ImgThumbnailMediaID: dto.ImgThumbnailMediaID,
 	}
 
 	return data, nil
@@ -46,8 +48,8 @@ func UpdateMLessonDtoToModel(dto *dto.UpdateMLessonDto) (map[string]interface{},
 	if dto.ImgThumbnail != nil {
 		updates["img_thumbnail"] = *dto.ImgThumbnail
 	}
-	if dto.Published != nil {
-		updates["published"] = *dto.Published
+	if dto.ImgThumbnailMediaID != nil {
+		updates["img_thumbnail_media_id"] = *dto.ImgThumbnailMediaID
 	}
 	if dto.IsActive != nil {
 		updates["is_active"] = *dto.IsActive
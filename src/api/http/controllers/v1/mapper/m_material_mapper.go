@@ -3,6 +3,8 @@ package mapper
 import (
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/database/models"
+
+	"gorm.io/datatypes"
 )
 
 func CreateMMaterialDtoToModel(dto *dto.CreateMMaterialDto) (*models.MMaterials, error) {
@@ -13,10 +15,12 @@ func CreateMMaterialDtoToModel(dto *dto.CreateMMaterialDto) (*models.MMaterials,
 	data := &models.MMaterials{
 		SubLessonID: dto.SubLessonID,
 		Title:       dto.Title,
-		Materials:  dto.Materials,
+		Materials:  datatypes.JSON(dto.Materials),
 		URLVideo:  dto.URLVideo,
+		VideoMediaID: dto.VideoMediaID,
 		ContentPosition: dto.ContentPosition,
 		PromptLLM:       dto.PromptLLM,
+		PromptTemplateVersionID: dto.PromptTemplateVersionID,
 	}
 
 	return data, nil
@@ -36,20 +40,23 @@ func UpdateMMaterialDtoToModel(dto *dto.UpdateMMaterialDto) (map[string]interfac
 		updates["title"] = *dto.Title
 	}
 	if dto.Materials != nil {
-		updates["materials"] = *dto.Materials
+		updates["materials"] = datatypes.JSON(dto.Materials)
 	}
 	if dto.URLVideo != nil {
 		updates["url_video"] = *dto.URLVideo
 	}
+	if dto.VideoMediaID != nil {
+		updates["video_media_id"] = *dto.VideoMediaID
+	}
 	if dto.PromptLLM != nil {
 		updates["prompt_llm"] = *dto.PromptLLM
 	}
+	if dto.PromptTemplateVersionID != nil {
+		updates["prompt_template_version_id"] = *dto.PromptTemplateVersionID
+	}
 	if dto.ContentPosition != nil {
 		updates["content_position"] = *dto.ContentPosition
 	}
-	if dto.Published != nil {
-		updates["published"] = *dto.Published
-	}
 	if dto.IsActive != nil {
 		updates["is_active"] = *dto.IsActive
 	}
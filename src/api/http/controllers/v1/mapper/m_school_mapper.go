@@ -0,0 +1,51 @@
+package mapper
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+)
+
+func CreateMSchoolDtoToModel(dto *dto.CreateMSchoolDto) (*models.MSchool, error) {
+	if dto == nil {
+		return nil, nil
+	}
+
+	data := &models.MSchool{
+		Name:    dto.Name,
+		Code:    dto.Code,
+		Address: dto.Address,
+	}
+
+	return data, nil
+}
+
+func UpdateMSchoolDtoToModel(dto *dto.UpdateMSchoolDto) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+
+	if dto.Name != nil {
+		payload["name"] = *dto.Name
+	}
+	if dto.Code != nil {
+		payload["code"] = *dto.Code
+	}
+	if dto.Address != nil {
+		payload["address"] = *dto.Address
+	}
+	if dto.IsActive != nil {
+		payload["is_active"] = *dto.IsActive
+	}
+
+	return payload, nil
+}
+
+func MSchoolModelToResponseDto(data *models.MSchool) (*dto.MSchoolResponseDto, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	responseDto := &dto.MSchoolResponseDto{
+		MSchool: *data,
+	}
+
+	return responseDto, nil
+}
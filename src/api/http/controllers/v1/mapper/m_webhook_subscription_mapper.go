@@ -0,0 +1,61 @@
+package mapper
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+
+	"gorm.io/datatypes"
+)
+
+func CreateMWebhookSubscriptionDtoToModel(dto *dto.CreateMWebhookSubscriptionDto, createdBy int64) (*models.MWebhookSubscription, error) {
+	if dto == nil {
+		return nil, nil
+	}
+
+	data := &models.MWebhookSubscription{
+		Name:      dto.Name,
+		URL:       dto.URL,
+		Secret:    dto.Secret,
+		Events:    datatypes.JSONSlice[string](dto.Events),
+		CreatedBy: &createdBy,
+	}
+
+	return data, nil
+}
+
+func UpdateMWebhookSubscriptionDtoToModel(dto *dto.UpdateMWebhookSubscriptionDto) (map[string]interface{}, error) {
+	payload := make(map[string]interface{})
+
+	if dto.Name != nil {
+		payload["name"] = *dto.Name
+	}
+	if dto.URL != nil {
+		payload["url"] = *dto.URL
+	}
+	if dto.Secret != nil {
+		payload["secret"] = *dto.Secret
+	}
+	if dto.Events != nil {
+		payload["events"] = datatypes.JSONSlice[string](*dto.Events)
+	}
+	if dto.IsActive != nil {
+		payload["is_active"] = *dto.IsActive
+	}
+
+	return payload, nil
+}
+
+func MWebhookSubscriptionModelToResponseDto(data *models.MWebhookSubscription, withSecret bool) (*dto.MWebhookSubscriptionResponseDto, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	responseDto := &dto.MWebhookSubscriptionResponseDto{
+		MWebhookSubscription: *data,
+	}
+	if withSecret {
+		responseDto.Secret = data.Secret
+	}
+
+	return responseDto, nil
+}
@@ -0,0 +1,18 @@
+package mapper
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+)
+
+func NotificationModelToResponseDto(data *models.Notification) (*dto.NotificationResponseDto, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	responseDto := &dto.NotificationResponseDto{
+		Notification: *data,
+	}
+
+	return responseDto, nil
+}
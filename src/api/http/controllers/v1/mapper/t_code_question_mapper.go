@@ -12,6 +12,7 @@ func CreateTCodeQuestionDtoToModel(dto *dto.TCodeQuestionCreateDto) (*models.Cod
 		SubLessonID:  dto.SubLessonID,
 		CodeQuestion: dto.CodeQuestion,
 		Image:          dto.Image,
+		ImageMediaID:   dto.ImageMediaID,
 		Score:          dto.Score,
 		Hint:           dto.Hint,
 	}
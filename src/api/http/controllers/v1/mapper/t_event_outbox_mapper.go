@@ -0,0 +1,18 @@
+package mapper
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+)
+
+func TEventOutboxModelToResponseDto(data *models.TEventOutbox) (*dto.TEventOutboxResponseDto, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	responseDto := &dto.TEventOutboxResponseDto{
+		TEventOutbox: *data,
+	}
+
+	return responseDto, nil
+}
@@ -4,31 +4,83 @@ package mapper
 
 import (
 	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 )
 
-func CompleteTStudentProgressDtoToModel(dto *dto.CompleteTStudentProgressDto, userID int64) (*models.TStudentProgress, error) {
-	if dto == nil {
+func TStudentProgressModelToResponseDto(data *models.TStudentProgress) (*dto.TStudentProgressResponseDto, error) {
+	if data == nil {
 		return nil, nil
 	}
 
-	data := &models.TStudentProgress{
-		UserID: userID,
-		SubLessonID: dto.SubLessonID,
-		Status: dto.Status,
+	responseDto := &dto.TStudentProgressResponseDto{
+		TStudentProgress: *data,
 	}
 
-	return data, nil
+	return responseDto, nil
 }
 
-func TStudentProgressModelToResponseDto(data *models.TStudentProgress) (*dto.TStudentProgressResponseDto, error) {
-	if data == nil {
+func TStudentProgressTreeToResponseDto(
+	userID int64,
+	course *models.MCourse,
+	progresses []models.TStudentProgress,
+) (*dto.TStudentProgressTreeDto, error) {
+	if course == nil {
 		return nil, nil
 	}
 
-	responseDto := &dto.TStudentProgressResponseDto{
-		TStudentProgress: *data,
+	bySubLesson := make(map[int64]models.TStudentProgress, len(progresses))
+	for _, p := range progresses {
+		bySubLesson[p.SubLessonID] = p
 	}
 
-	return responseDto, nil
+	tree := &dto.TStudentProgressTreeDto{
+		UserID:     userID,
+		CourseID:   course.ID,
+		CourseName: course.CourseName,
+		Lessons:    []dto.TStudentProgressLessonNodeDto{},
+	}
+
+	if course.Lessons == nil {
+		return tree, nil
+	}
+
+	for _, lesson := range *course.Lessons {
+		lessonNode := dto.TStudentProgressLessonNodeDto{
+			LessonID:   lesson.ID,
+			Title:      lesson.Title,
+			Position:   lesson.Position,
+			SubLessons: make([]dto.TStudentProgressSubLessonNodeDto, 0, len(lesson.SubLessons)),
+		}
+
+		for _, subLesson := range lesson.SubLessons {
+			node := dto.TStudentProgressSubLessonNodeDto{
+				SubLessonID:   subLesson.ID,
+				Title:         subLesson.Title,
+				OrderPosition: subLesson.OrderPosition,
+				Status:        constant.ProgressNotStarted,
+			}
+
+			if p, ok := bySubLesson[subLesson.ID]; ok {
+				node.Status = p.Status
+				node.StartedAt = p.StartedAt
+				node.CompletedAt = p.CompletedAt
+				node.LastViewedAt = p.LastViewedAt
+			}
+
+			if node.Status == constant.ProgressCompleted {
+				tree.CompletedCount++
+			}
+			tree.TotalSubLessons++
+			lessonNode.SubLessons = append(lessonNode.SubLessons, node)
+		}
+
+		tree.Lessons = append(tree.Lessons, lessonNode)
+	}
+
+	if tree.TotalSubLessons > 0 {
+		tree.ProgressPercentage = (float64(tree.CompletedCount) / float64(tree.TotalSubLessons)) * 100
+	}
+
+	return tree, nil
 }
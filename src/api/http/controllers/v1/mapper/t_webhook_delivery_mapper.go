@@ -0,0 +1,18 @@
+package mapper
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/database/models"
+)
+
+func TWebhookDeliveryModelToResponseDto(data *models.TWebhookDelivery) (*dto.TWebhookDeliveryResponseDto, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	responseDto := &dto.TWebhookDeliveryResponseDto{
+		TWebhookDelivery: *data,
+	}
+
+	return responseDto, nil
+}
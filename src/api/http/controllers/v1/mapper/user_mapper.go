@@ -25,6 +25,8 @@ func CreateUserDtoToModel(dto *dto.CreateUserDto) (*models.User, error) {
 		Code:  dto.Code,
 		Name:  dto.Name,
 		Email: dto.Email,
+		SchoolID: dto.SchoolID,
+		DepartmentID: dto.DepartmentID,
 		Password: func() string {
 			if dto.Password != "" {
 				return dto.Password
@@ -70,6 +72,15 @@ func UpdateUserDtoToModel(dto *dto.UpdateUserDto) (
 	if dto.IsPasswordDefault != nil {
 		payload["is_password_default"] = *dto.IsPasswordDefault
 	}
+	if dto.SchoolID != nil {
+		payload["school_id"] = *dto.SchoolID
+	}
+	if dto.DepartmentID != nil {
+		payload["department_id"] = *dto.DepartmentID
+	}
+	if dto.ScopeDepartmentID != nil {
+		payload["scope_department_id"] = *dto.ScopeDepartmentID
+	}
 
 	// if dto.TitleID != nil {
 	// 	payload["title_id"] = *dto.TitleID
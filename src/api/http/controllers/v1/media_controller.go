@@ -0,0 +1,237 @@
+package controllers
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/signedurl"
+	"jk-api/pkg/services/v1"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// UploadMedia stores the multipart "file" field in one request. With
+// protected=true the file is only served through signed links.
+func UploadMedia(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "File wajib diunggah")
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+		defer file.Close()
+
+		userID := c.Locals("user_id").(int64)
+
+		protected := c.FormValue("protected") == "true"
+
+		data, err := cn.MediaHandler.UploadMediaHandler(userID, file, protected)
+		if err != nil {
+			return presenters.ErrorResponse(c, mediaErrorStatus(err), err)
+		}
+		return presenters.SuccessCreatedResponse(c, data)
+	}
+}
+
+// CreateMediaUpload starts a resumable upload for files too large to send
+// in one request.
+func CreateMediaUpload(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.CreateMediaUploadDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MediaHandler.CreateUploadHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, mediaErrorStatus(err), err)
+		}
+		return presenters.SuccessCreatedResponse(c, data)
+	}
+}
+
+// AppendMediaUpload writes the raw request body at the byte given in the
+// Upload-Offset header. A 409 means the client should resume from the
+// upload's current offset.
+func AppendMediaUpload(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		offset, err := strconv.ParseInt(c.Get("Upload-Offset"), 10, 64)
+		if err != nil || offset < 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Header Upload-Offset wajib diisi")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MediaHandler.AppendUploadHandler(userID, c.Params("id"), offset, bytes.NewReader(c.Body()))
+		if err != nil {
+			return presenters.ErrorResponse(c, mediaErrorStatus(err), err)
+		}
+
+		c.Set("Upload-Offset", strconv.FormatInt(data.Offset, 10))
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetMediaUpload(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MediaHandler.GetUploadHandler(userID, c.Params("id"))
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusNotFound, err)
+		}
+
+		c.Set("Upload-Offset", strconv.FormatInt(data.Offset, 10))
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetMediaByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.MediaHandler.GetMediaByIDHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// SignMediaURL returns a short-lived link to the media of a material
+// (type=material) or code question (type=code_question).
+func SignMediaURL(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Query("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		input := dto.SignMediaURLDto{
+			Type:    c.Query("type"),
+			ID:      id,
+			Variant: c.Query("variant"),
+		}
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.MediaHandler.SignContentMediaHandler(userID, canPreviewContent(c), input)
+		if err != nil {
+			return presenters.ErrorResponse(c, mediaErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// ServeProtectedMedia streams the file a signed link grants. A single
+// "bytes=" range is honoured so videos can seek; other Range headers get the
+// whole file.
+func ServeProtectedMedia(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		handler := cn.MediaHandler
+
+		file, err := handler.ResolveSignedMediaHandler(id, query)
+		if err != nil {
+			return presenters.ErrorResponse(c, mediaErrorStatus(err), err)
+		}
+
+		offset, length, partial, ok := parseByteRange(c.Get(fiber.HeaderRange), file.Size)
+		if !ok {
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes */%d", file.Size))
+			return c.SendStatus(fiber.StatusRequestedRangeNotSatisfiable)
+		}
+
+		body, err := handler.ReadMediaHandler(file.StorageKey, offset, length)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+
+		c.Set(fiber.HeaderContentType, file.MimeType)
+		c.Set(fiber.HeaderAcceptRanges, "bytes")
+		c.Set(fiber.HeaderCacheControl, "private, no-store")
+		if partial {
+			c.Set(fiber.HeaderContentRange, fmt.Sprintf("bytes %d-%d/%d", offset, offset+length-1, file.Size))
+			c.Status(fiber.StatusPartialContent)
+		}
+		return c.SendStream(body, int(length))
+	}
+}
+
+// parseByteRange reads a single-range header such as "bytes=0-1023",
+// "bytes=1024-" or "bytes=-500" against a file of size bytes. ok is false
+// when the range can't be satisfied.
+func parseByteRange(header string, size int64) (offset int64, length int64, partial bool, ok bool) {
+	spec, found := strings.CutPrefix(header, "bytes=")
+	if !found || strings.Contains(spec, ",") {
+		return 0, size, false, true
+	}
+
+	first, last, found := strings.Cut(strings.TrimSpace(spec), "-")
+	if !found {
+		return 0, size, false, true
+	}
+
+	if first == "" {
+		suffix, err := strconv.ParseInt(last, 10, 64)
+		if err != nil || suffix <= 0 {
+			return 0, 0, false, false
+		}
+		suffix = min(suffix, size)
+		return size - suffix, suffix, true, size > 0
+	}
+
+	start, err := strconv.ParseInt(first, 10, 64)
+	if err != nil || start < 0 || start >= size {
+		return 0, 0, false, false
+	}
+	end := size - 1
+	if last != "" {
+		end, err = strconv.ParseInt(last, 10, 64)
+		if err != nil || end < start {
+			return 0, 0, false, false
+		}
+		end = min(end, size-1)
+	}
+	return start, end - start + 1, true, true
+}
+
+func mediaErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrMediaAccessDenied),
+		errors.Is(err, signedurl.ErrInvalidSignature),
+		errors.Is(err, signedurl.ErrExpired):
+		return fiber.StatusForbidden
+	case errors.Is(err, services.ErrMediaTooLarge):
+		return fiber.StatusRequestEntityTooLarge
+	case errors.Is(err, services.ErrMediaUploadOffset):
+		return fiber.StatusConflict
+	case errors.Is(err, services.ErrMediaUploadExpired):
+		return fiber.StatusGone
+	default:
+		return fiber.StatusBadRequest
+	}
+}
@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetMyNotifications(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+
+		spec, err := helper.ParseQuerySpec(c, &models.Notification{}, "id", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.NotificationFilterDto{
+			Unread: c.Query("unread", "false") == "true",
+			Type:   c.Query("type"),
+			Spec:   spec,
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, page, err := cn.NotificationHandler.GetMyNotificationsHandler(userID, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func CountUnreadNotifications(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.NotificationHandler.CountUnreadNotificationsHandler(userID)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func MarkNotificationRead(cn *container.AppContainer) fiber.Handler {
+	return markNotification(cn, true)
+}
+
+func MarkNotificationUnread(cn *container.AppContainer) fiber.Handler {
+	return markNotification(cn, false)
+}
+
+func markNotification(cn *container.AppContainer, read bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.NotificationHandler.MarkNotificationReadHandler(userID, id, read)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func MarkAllNotificationsRead(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int64)
+
+		updated, err := cn.NotificationHandler.MarkAllNotificationsReadHandler(userID)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, fiber.Map{"updated": updated})
+	}
+}
+
+func GetNotificationPreferences(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.NotificationHandler.GetNotificationPreferencesHandler(userID)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UpdateNotificationPreferences(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.UpdateNotificationPreferencesDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid input")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.NotificationHandler.UpdateNotificationPreferencesHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/helper"
+	"jk-api/pkg/services/v1"
+	"strconv"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// SearchContent searches published course content. q takes web search
+// syntax: quoted phrases, "or" and a leading "-" to exclude a word.
+func SearchContent(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		filter := dto.SearchFilterDto{Query: c.Query("q")}
+
+		if types := c.Query("type"); types != "" {
+			for _, t := range strings.Split(types, ",") {
+				if t = strings.TrimSpace(t); t != "" {
+					filter.Types = append(filter.Types, t)
+				}
+			}
+		}
+
+		courseID, err := helper.ParseQueryInt64(c, "course_id")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+		if courseID != 0 {
+			filter.CourseID = &courseID
+		}
+
+		levelID, err := helper.ParseQueryInt64(c, "level_id")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+		if levelID != 0 {
+			filter.LevelID = &levelID
+		}
+
+		if limit := c.Query("limit"); limit != "" {
+			if filter.Limit, err = strconv.Atoi(limit); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid limit")
+			}
+		}
+		if offset := c.Query("offset"); offset != "" {
+			if filter.Offset, err = strconv.Atoi(offset); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid offset")
+			}
+		}
+
+		data, page, err := cn.SearchHandler.SearchContentHandler(filter)
+		if err != nil {
+			if errors.Is(err, services.ErrSearchInvalid) {
+				return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+			}
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
@@ -0,0 +1,153 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func AssignClassCourse(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.AssignClassCourseDto
+		if err := c.BodyParser(&input); err != nil || input.ClassID == 0 || input.CourseID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassCourseHandler.AssignClassCourseHandler(userID, middleware.HasRole(c, "super"), &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessCreatedResponse(c, data)
+	}
+}
+
+func GetClassCourses(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		classID, _ := helper.ParseQueryInt64(c, "class_id")
+		courseID, _ := helper.ParseQueryInt64(c, "course_id")
+		departmentID, _ := helper.ParseQueryInt64(c, "department_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.TClassCourse{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.ClassCourseFilterDto{
+			ClassID:  classID,
+			CourseID: courseID,
+			DepartmentID: departmentID,
+			Preload:  c.Query("preload", "false") == "true",
+			Spec:     spec,
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, page, err := cn.TClassCourseHandler.GetClassCoursesHandler(userID, middleware.HasRole(c, "super"), filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetClassCourseByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassCourseHandler.GetClassCourseByIDHandler(userID, middleware.HasRole(c, "super"), id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UpdateClassCourse(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.UpdateClassCourseDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassCourseHandler.UpdateClassCourseHandler(userID, middleware.HasRole(c, "super"), id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UnassignClassCourse(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		if err := cn.TClassCourseHandler.UnassignClassCourseHandler(userID, middleware.HasRole(c, "super"), id); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Course unassigned successfully", nil)
+	}
+}
+
+func GetMyDueItems(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		days, err := helper.ParseQueryInt64(c, "days")
+		if err != nil || c.Query("days") == "" {
+			days = 7
+		}
+
+		filter := dto.DueItemFilterDto{
+			Days:             days,
+			IncludeCompleted: c.Query("include_completed", "false") == "true",
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassCourseHandler.GetMyDueItemsHandler(userID, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetClassCourseOverdueReport(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassCourseHandler.GetOverdueReportHandler(userID, middleware.HasRole(c, "super"), id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
@@ -0,0 +1,248 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func JoinMClass(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.JoinMClassDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassRosterHandler.JoinMClassHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func RegenerateClassCode(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.RegenerateClassCodeDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassRosterHandler.RegenerateClassCodeHandler(userID, middleware.HasRole(c, "super"), id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func UpdateClassJoinSettings(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.UpdateClassJoinSettingsDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassRosterHandler.UpdateClassJoinSettingsHandler(userID, middleware.HasRole(c, "super"), id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetClassJoinRequests(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		spec, err := helper.ParseQuerySpec(c, &models.TClassJoinRequest{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.ClassJoinRequestFilterDto{
+			Status: c.Query("status"),
+			Spec:   spec,
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, page, err := cn.TClassRosterHandler.GetClassJoinRequestsHandler(userID, middleware.HasRole(c, "super"), id, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func ApproveClassJoinRequest(cn *container.AppContainer) fiber.Handler {
+	return decideClassJoinRequest(cn, true)
+}
+
+func RejectClassJoinRequest(cn *container.AppContainer) fiber.Handler {
+	return decideClassJoinRequest(cn, false)
+}
+
+func decideClassJoinRequest(cn *container.AppContainer, approve bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		requestID, err := strconv.ParseInt(c.Params("requestID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.DecideClassJoinRequestDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TClassRosterHandler.DecideClassJoinRequestHandler(userID, middleware.HasRole(c, "super"), requestID, approve, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func MoveClassStudent(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+		studentID, err := strconv.ParseInt(c.Params("userID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid user ID")
+		}
+
+		var input dto.MoveClassStudentDto
+		if err := c.BodyParser(&input); err != nil || input.ToClassID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		if err := cn.TClassRosterHandler.MoveClassStudentHandler(userID, middleware.HasRole(c, "super"), id, studentID, &input); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Student moved successfully", nil)
+	}
+}
+
+func RemoveClassStudent(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+		studentID, err := strconv.ParseInt(c.Params("userID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid user ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		if err := cn.TClassRosterHandler.RemoveClassStudentHandler(userID, middleware.HasRole(c, "super"), id, studentID); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Student removed successfully", nil)
+	}
+}
+
+func AssignClassTeacher(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.AssignClassTeacherDto
+		if err := c.BodyParser(&input); err != nil || input.TeacherID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		if err := cn.TClassRosterHandler.AssignClassTeacherHandler(userID, middleware.HasRole(c, "super"), id, &input); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Teacher assigned successfully", nil)
+	}
+}
+
+func RemoveClassTeacher(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+		teacherID, err := strconv.ParseInt(c.Params("userID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid user ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		if err := cn.TClassRosterHandler.RemoveClassTeacherHandler(userID, middleware.HasRole(c, "super"), id, teacherID); err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponseWithMessage(c, "Teacher removed successfully", nil)
+	}
+}
+
+func GetClassRosterLogs(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		spec, err := helper.ParseQuerySpec(c, &models.TClassRosterLog{}, "id", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.ClassRosterLogFilterDto{
+			Action: c.Query("action"),
+			Spec:   spec,
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, page, err := cn.TClassRosterHandler.GetClassRosterLogsHandler(userID, middleware.HasRole(c, "super"), id, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
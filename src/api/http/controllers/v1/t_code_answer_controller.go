@@ -21,7 +21,7 @@ func CreateTCodeAnswer(cn *container.AppContainer) fiber.Handler {
 
 		result, err := cn.TCodeAnswerHandler.CreateTCodeAnswerHandler(&input, userID)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
@@ -40,7 +40,7 @@ func GetTCodeAnswersByCodeQuestionID(cn *container.AppContainer) fiber.Handler {
 
 		data, err := cn.TCodeAnswerHandler.GetTCodeAnswersByCodeQuestionIDHandler(filter, codeQuestionID)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
@@ -20,7 +20,7 @@ func CreateTCodeQuestions(cn *container.AppContainer) fiber.Handler {
 
 		result, err := cn.TCodeQuestionHandler.CreateTCodeQuestionHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
@@ -35,11 +35,12 @@ func GetTcodeQuestionsBySubLessonID(cn *container.AppContainer) fiber.Handler {
 	
 		filter := dto.TCodeQuestionFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		data, err := cn.TCodeQuestionHandler.GetTCodeQuestionsBySubLessonIDHandler(filter, subLessonID)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
@@ -54,11 +55,12 @@ func GetTCodeQuestionByID(cn *container.AppContainer) fiber.Handler {
 
 		filter := dto.TCodeQuestionFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		data, err := cn.TCodeQuestionHandler.GetTCodeQuestionHandlerByID(filter, id)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
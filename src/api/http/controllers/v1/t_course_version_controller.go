@@ -0,0 +1,114 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetCourseVersions(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		courseID, _ := helper.ParseQueryInt64(c, "course_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.TCourseVersion{}, "version", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.CourseVersionFilterDto{
+			CourseID: courseID,
+			Spec:     spec,
+		}
+
+		data, page, err := cn.TCourseVersionHandler.GetCourseVersionsHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetCourseVersionByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.TCourseVersionHandler.GetCourseVersionByIDHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func DiffCourseVersions(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		fromID, err := helper.ParseQueryInt64(c, "from")
+		if err != nil || fromID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid from version")
+		}
+		toID, err := helper.ParseQueryInt64(c, "to")
+		if err != nil || toID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid to version")
+		}
+
+		data, err := cn.TCourseVersionHandler.DiffCourseVersionsHandler(fromID, toID)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func RollbackCourseVersion(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TCourseVersionHandler.RollbackCourseHandler(userID, id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// MigrateCourseVersion moves an enrollment to a newer course version.
+// Students move their own enrollments; teachers and admins any in their
+// school.
+func MigrateCourseVersion(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.MigrateCourseVersionDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
+
+		userID := c.Locals("user_id").(int64)
+		isStaff := middleware.HasRole(c, "super") || middleware.HasRole(c, "teacher")
+
+		data, err := cn.TCourseVersionHandler.MigrateEnrollmentHandler(userID, isStaff, id, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
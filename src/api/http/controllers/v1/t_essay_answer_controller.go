@@ -21,7 +21,7 @@ func CreateTEssayAnswer(cn *container.AppContainer) fiber.Handler {
 
 		result, err := cn.TEssayAnswerHandler.CreateTEssayAnswerHandler(&input, userID)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
@@ -40,10 +40,31 @@ func GetTEssayAnswersByEssayQuestionIDAndUserID(cn *container.AppContainer) fibe
 
 		data, err := cn.TEssayAnswerHandler.GetTEssayAnswersByEssayQuestionIDAndUserIDHandler(filter, essayQuestionID, userID)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
 }
 
+func ApproveTEssayAnswer(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		teacherID := c.Locals("user_id").(int64)
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid essay answer ID")
+		}
+
+		var input dto.ApproveTEssayAnswerDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
 
+		result, err := cn.TEssayAnswerHandler.ApproveTEssayAnswerHandler(id, &input, teacherID)
+		if err != nil {
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, result)
+	}
+}
@@ -20,7 +20,7 @@ func CreateEssayQuestions(cn *container.AppContainer) fiber.Handler {
 
 		result, err := cn.EssayQuestionHandler.CreateEssayQuestionHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
@@ -35,11 +35,12 @@ func GetEssayQuestionsByCodeQuestionID(cn *container.AppContainer) fiber.Handler
 	
 		filter := dto.EssayQuestionFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		data, err := cn.EssayQuestionHandler.GetEssayQuestionsByCodeQuestionIDHandler(filter, codeQuestionID)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
@@ -54,11 +55,12 @@ func GetEssayQuestionByID(cn *container.AppContainer) fiber.Handler {
 
 		filter := dto.EssayQuestionFilterDto{
 			Preload: c.Query("preload", "false") == "true",
+			Preview: canPreviewContent(c),
 		}
 
 		data, err := cn.EssayQuestionHandler.GetEssayQuestionHandlerByID(filter, id)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, contentErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, data)
 	}
@@ -0,0 +1,124 @@
+package controllers
+
+import (
+	"errors"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/generation"
+	"jk-api/internal/helper"
+	"jk-api/pkg/services/v1"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GenerateContent drafts materials or questions for a sub-lesson. The run
+// is logged even when the provider fails.
+func GenerateContent(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.GenerateContentDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+		if input.SubLessonID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid sub lesson ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGenerationHistoryHandler.GenerateContentHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, generationErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetGenerations(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subLessonID, _ := helper.ParseQueryInt64(c, "sub_lesson_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.TGenerationHistory{}, "created_at", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.GenerationFilterDto{
+			SubLessonID: subLessonID,
+			Spec:        spec,
+		}
+
+		data, page, err := cn.TGenerationHistoryHandler.GetGenerationsHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetGenerationByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.TGenerationHistoryHandler.GetGenerationByIDHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// AcceptGeneration publishes the generated questions of a run. Generated
+// materials are submitted through the content workflow instead.
+func AcceptGeneration(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGenerationHistoryHandler.AcceptGenerationHandler(userID, id)
+		if err != nil {
+			return presenters.ErrorResponse(c, generationErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// DiscardGeneration deletes the drafts of a run.
+func DiscardGeneration(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGenerationHistoryHandler.DiscardGenerationHandler(userID, id)
+		if err != nil {
+			return presenters.ErrorResponse(c, generationErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func generationErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrGenerationInvalid),
+		errors.Is(err, generation.ErrInvalidTemplate),
+		errors.Is(err, generation.ErrInvalidVariables):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrGenerationFailed):
+		return fiber.StatusBadGateway
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,132 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func InviteGuardianStudent(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.InviteGuardianStudentDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGuardianLinkHandler.InviteStudentHandler(userID, &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessCreatedResponse(c, data)
+	}
+}
+
+func GetGuardianLinks(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		studentID, _ := helper.ParseQueryInt64(c, "student_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.TGuardianLink{}, "id", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.GuardianLinkFilterDto{
+			Status:    c.Query("status"),
+			StudentID: studentID,
+			Spec:      spec,
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, page, err := cn.TGuardianLinkHandler.GetGuardianLinksHandler(userID, middleware.HasRole(c, "super"), filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func AcceptGuardianInvite(cn *container.AppContainer) fiber.Handler {
+	return decideGuardianInvite(cn, true)
+}
+
+func RejectGuardianInvite(cn *container.AppContainer) fiber.Handler {
+	return decideGuardianInvite(cn, false)
+}
+
+func decideGuardianInvite(cn *container.AppContainer, accept bool) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGuardianLinkHandler.DecideGuardianInviteHandler(userID, middleware.HasRole(c, "super"), id, accept)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func RevokeGuardianLink(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGuardianLinkHandler.RevokeGuardianLinkHandler(userID, middleware.HasRole(c, "super"), id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// Guardian reads are authorized by the accepted link, not the school scope:
+// a guardian's children may attend different schools.
+
+func GetGuardianChildren(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGuardianLinkHandler.GetChildrenHandler(userID)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func GetGuardianChildDashboard(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		studentID, err := strconv.ParseInt(c.Params("studentID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		days, _ := helper.ParseQueryInt64(c, "days")
+		filter := dto.GuardianDashboardFilterDto{Days: days}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TGuardianLinkHandler.GetChildDashboardHandler(userID, studentID, filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
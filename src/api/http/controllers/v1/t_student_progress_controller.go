@@ -3,26 +3,164 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
 	"jk-api/api/http/presenters"
+	"jk-api/internal/constant"
 	"jk-api/internal/container"
-	
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/pkg/services/v1"
+	"strconv"
+
 	"github.com/gofiber/fiber/v2"
 )
 
+func StartTStudentProgress(cn *container.AppContainer) fiber.Handler {
+	return transitionTStudentProgress(cn, constant.ProgressInProgress)
+}
+
 func CompleteTStudentProgress(cn *container.AppContainer) fiber.Handler {
+	return transitionTStudentProgress(cn, constant.ProgressCompleted)
+}
+
+func SubmitTStudentProgressForReview(cn *container.AppContainer) fiber.Handler {
+	return transitionTStudentProgress(cn, constant.ProgressNeedsReview)
+}
+
+func transitionTStudentProgress(cn *container.AppContainer, status string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userID := c.Locals("user_id").(int64)
-		var input dto.CompleteTStudentProgressDto
+		var input dto.TransitionTStudentProgressDto
 		if err := c.BodyParser(&input); err != nil {
 			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
 		}
+		if input.SubLessonID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "sub_lesson_id is required")
+		}
+
+		result, err := cn.TStudentProgressHandler.TransitionTStudentProgressHandler(&input, userID, status)
+		if err != nil {
+			return presenters.ErrorResponse(c, progressErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, result)
+	}
+}
+
+// ApproveTStudentProgress completes a sub-lesson a student submitted for
+// review. Students cannot leave needs_review themselves.
+func ApproveTStudentProgress(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.ApproveTStudentProgressDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+		if input.UserID == 0 || input.SubLessonID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "user_id and sub_lesson_id are required")
+		}
+
+		actorID := c.Locals("user_id").(int64)
+
+		result, err := cn.TStudentProgressHandler.ApproveTStudentProgressHandler(actorID, middleware.HasRole(c, "super"), &input)
+		if err != nil {
+			return presenters.ErrorResponse(c, progressErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, result)
+	}
+}
+
+func ResetTStudentProgress(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.ResetTStudentProgressDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+		if input.UserID == 0 || input.CourseID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "user_id and course_id are required")
+		}
 
-		result, err := cn.TStudentProgressHandler.CompleteTStudentProgressHandler(&input, userID)
+		userID := c.Locals("user_id").(int64)
+
+		if err := cn.TStudentProgressHandler.ResetTStudentProgressHandler(userID, middleware.HasRole(c, "super"), &input); err != nil {
+			return presenters.ErrorResponse(c, progressErrorStatus(err), err)
+		}
+		return presenters.SuccessResponseWithMessage(c, fmt.Sprintf("Progress user %d reset successfully", input.UserID), nil)
+	}
+}
+
+func RecomputeTStudentProgress(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.RecomputeTStudentProgressDto
+		if len(c.Body()) > 0 {
+			if err := c.BodyParser(&input); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+			}
+		}
+
+		result, err := cn.TStudentProgressHandler.RecomputeTStudentProgressHandler(&input)
 		if err != nil {
-			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+			return presenters.ErrorResponse(c, progressErrorStatus(err), err)
 		}
 		return presenters.SuccessResponse(c, result)
 	}
 }
 
+// GetMyProgressTree shows the caller their progress in a course they are
+// enrolled in. Staff see the whole course, drafts included.
+func GetMyProgressTree(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID := c.Locals("user_id").(int64)
+		courseID, err := strconv.ParseInt(c.Params("courseID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid course ID")
+		}
+
+		data, err := cn.TStudentProgressHandler.GetProgressTreeHandler(userID, courseID, canPreviewContent(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, progressErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// GetStudentProgressTree shows a teacher the progress of a student in a
+// class they teach.
+func GetStudentProgressTree(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		userID, err := strconv.ParseInt(c.Params("userID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid user ID")
+		}
+		courseID, err := strconv.ParseInt(c.Params("courseID"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid course ID")
+		}
+
+		actorID := c.Locals("user_id").(int64)
+
+		data, err := cn.TStudentProgressHandler.GetStudentProgressTreeHandler(actorID, middleware.HasRole(c, "super"), userID, courseID)
+		if err != nil {
+			return presenters.ErrorResponse(c, progressErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func progressErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrInvalidProgressStatus):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrProgressForbidden),
+		errors.Is(err, services.ErrProgressNotEnrolled):
+		return fiber.StatusForbidden
+	case errors.Is(err, gorm_err.ErrDataTidakDitemukan),
+		errors.Is(err, gorm_err.ErrForeignKeyViolation):
+		// A missing sub-lesson shows up as a foreign key violation.
+		return fiber.StatusNotFound
+	case errors.Is(err, services.ErrInvalidProgressTransition):
+		return fiber.StatusConflict
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
@@ -0,0 +1,144 @@
+package controllers
+
+import (
+	"bufio"
+	"errors"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"jk-api/pkg/services/v1"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// GetTutorConversations lists the caller's conversations. Teachers see
+// every conversation of their school and may filter by student.
+func GetTutorConversations(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subLessonID, _ := helper.ParseQueryInt64(c, "sub_lesson_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.TTutorConversation{}, "updated_at", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := tutorConversationFilter(c)
+		filter.SubLessonID = subLessonID
+		filter.Spec = spec
+		if filter.Preview {
+			filter.UserID, _ = helper.ParseQueryInt64(c, "user_id")
+		}
+
+		data, page, err := cn.TTutorConversationHandler.GetConversationsHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+// GetTutorConversationByID returns a conversation with its transcript.
+func GetTutorConversationByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.TTutorConversationHandler.GetConversationByIDHandler(id, tutorConversationFilter(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func CreateTutorConversation(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		var input dto.CreateTutorConversationDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+		if input.SubLessonID == 0 {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid sub lesson ID")
+		}
+
+		userID := c.Locals("user_id").(int64)
+
+		data, err := cn.TTutorConversationHandler.CreateConversationHandler(userID, &input, canPreviewContent(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, tutorErrorStatus(err), err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+// SendTutorMessage stores the student's message and streams the reply as
+// server-sent events: "message" with the stored message, "delta" with every
+// piece of the reply, then "done" with the stored reply or "error".
+// Problems found before the reply starts are answered with plain JSON.
+func SendTutorMessage(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		var input dto.SendTutorMessageDto
+		if err := c.BodyParser(&input); err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid request")
+		}
+
+		userID := c.Locals("user_id").(int64)
+		ctx := c.UserContext()
+		handler := cn.TTutorConversationHandler
+
+		turn, err := handler.StartReplyHandler(userID, id, &input, canPreviewContent(c))
+		if err != nil {
+			return presenters.ErrorResponse(c, tutorErrorStatus(err), err)
+		}
+
+		presenters.StreamHeaders(c)
+		c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+			if err := presenters.StreamEvent(w, "message", turn.Message); err != nil {
+				return
+			}
+
+			reply, err := handler.FinishReplyHandler(ctx, turn, func(text string) error {
+				return presenters.StreamEvent(w, "delta", fiber.Map{"text": text})
+			})
+			if err != nil {
+				_ = presenters.StreamEvent(w, "error", fiber.Map{"error": err.Error(), "data": reply})
+				return
+			}
+			_ = presenters.StreamEvent(w, "done", reply)
+		})
+		return nil
+	}
+}
+
+// tutorConversationFilter limits students to their own conversations.
+func tutorConversationFilter(c *fiber.Ctx) dto.TutorConversationFilterDto {
+	filter := dto.TutorConversationFilterDto{Preview: canPreviewContent(c)}
+	if !filter.Preview {
+		filter.UserID = c.Locals("user_id").(int64)
+	}
+	return filter
+}
+
+func tutorErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, services.ErrTutorInvalid):
+		return fiber.StatusBadRequest
+	case errors.Is(err, services.ErrTutorRateLimited),
+		errors.Is(err, services.ErrTutorTokenLimit):
+		return fiber.StatusTooManyRequests
+	case errors.Is(err, services.ErrTutorFailed):
+		return fiber.StatusBadGateway
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
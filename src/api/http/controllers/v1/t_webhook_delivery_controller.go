@@ -0,0 +1,69 @@
+package controllers
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/internal/database/models"
+	"jk-api/internal/helper"
+	"strconv"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func GetTWebhookDeliveries(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		subscriptionID, err := helper.ParseQueryInt64(c, "subscription_id")
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid subscription_id")
+		}
+
+		spec, err := helper.ParseQuerySpec(c, &models.TWebhookDelivery{}, "id", "desc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		filter := dto.TWebhookDeliveryFilterDto{
+			SubscriptionID: subscriptionID,
+			Status:         c.Query("status"),
+			EventName:      c.Query("event_name"),
+			Spec:           spec,
+		}
+
+		data, page, err := cn.TWebhookDeliveryHandler.GetAllTWebhookDeliveriesHandler(filter)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data, page)
+	}
+}
+
+func GetTWebhookDeliveryByID(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.TWebhookDeliveryHandler.GetTWebhookDeliveryByIDHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
+
+func ReplayTWebhookDelivery(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		data, err := cn.TWebhookDeliveryHandler.ReplayTWebhookDeliveryHandler(id)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, data)
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/api/http/presenters"
 	"jk-api/internal/container"
+	"jk-api/internal/database/models"
 	"jk-api/internal/helper"
 	"strconv"
 
@@ -16,29 +17,29 @@ import (
 func GetUsers(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		squadID, _ := helper.ParseQueryInt64(c, "squad_id")
-		cursor, _ := helper.ParseQueryInt64(c, "cursor")
-		limit, _ := helper.ParseQueryInt64(c, "limit")
 		name := c.Query("name")
-		sort := c.Query("sort", "id")
-		order := c.Query("order", "asc")
 		deleted := c.Query("show_deleted", "false") == "true"
+		departmentID, _ := helper.ParseQueryInt64(c, "department_id")
+
+		spec, err := helper.ParseQuerySpec(c, &models.User{}, "id", "asc")
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
 
 		filter := dto.UserFilterDto{
 			SquadID:     squadID,
 			Preload:     c.Query("preload", "false") == "true",
-			Cursor:      cursor,
-			Limit:       limit,
 			Name:        name,
-			Sort:        sort,
-			Order:       order,
+			Spec:        spec,
 			ShowDeleted: deleted,
+			DepartmentID: departmentID,
 		}
 
-		data, total, err := cn.UserHandler.GetAllUsersHandler(filter)
+		data, page, err := cn.UserHandler.GetAllUsersHandler(filter)
 		if err != nil {
 			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
 		}
-		return presenters.SuccessResponse(c, data, total)
+		return presenters.SuccessResponse(c, data, page)
 	}
 }
 
@@ -96,6 +97,23 @@ func UpdateUsers(cn *container.AppContainer) fiber.Handler {
 	}
 }
 
+func ApproveTeacher(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid ID")
+		}
+
+		approvedBy := c.Locals("user_id").(int64)
+
+		updated, err := cn.UserHandler.ApproveTeacherHandler(id, approvedBy)
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusInternalServerError, err)
+		}
+		return presenters.SuccessResponse(c, updated)
+	}
+}
+
 func DeleteUsers(cn *container.AppContainer) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		id, err := strconv.ParseInt(c.Params("id"), 10, 64)
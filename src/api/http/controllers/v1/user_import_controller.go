@@ -0,0 +1,92 @@
+package controllers
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/api/http/middleware"
+	"jk-api/api/http/presenters"
+	"jk-api/internal/container"
+	"jk-api/pkg/services/v1"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ImportUsers accepts a multipart upload with a "file" field (.csv or .xlsx)
+// and optional form fields dry_run, default_role, class_code and mapping (a
+// JSON object of field to header). With ?export=csv a successful import
+// answers with the credentials sheet instead of the JSON report.
+func ImportUsers(cn *container.AppContainer) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		header, err := c.FormFile("file")
+		if err != nil {
+			return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "File wajib diunggah")
+		}
+
+		options := dto.UserImportOptionsDto{
+			DryRun:      c.FormValue("dry_run", c.Query("dry_run", "false")) == "true",
+			DefaultRole: c.FormValue("default_role"),
+			ClassCode:   c.FormValue("class_code"),
+		}
+		if mapping := c.FormValue("mapping"); mapping != "" {
+			if err := json.Unmarshal([]byte(mapping), &options.Mapping); err != nil {
+				return presenters.ErrorResponseWithMessage(c, fiber.StatusBadRequest, "Invalid mapping")
+			}
+		}
+
+		file, err := header.Open()
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+		defer file.Close()
+
+		userID := c.Locals("user_id").(int64)
+
+		report, err := cn.UserImportHandler.ImportUsersHandler(userID, middleware.HasRole(c, "super"), header.Filename, file, options)
+		if errors.Is(err, services.ErrUserImportInvalid) {
+			return presenters.ErrorResponseWithData(c, fiber.StatusUnprocessableEntity, err, report)
+		}
+		if err != nil {
+			return presenters.ErrorResponse(c, fiber.StatusBadRequest, err)
+		}
+
+		if c.Query("export") == "csv" && !options.DryRun {
+			return writeImportCredentials(c, report.Credentials)
+		}
+		return presenters.SuccessResponse(c, report)
+	}
+}
+
+func writeImportCredentials(c *fiber.Ctx, credentials []dto.UserImportCredentialDto) error {
+	filename := fmt.Sprintf("credentials-%s.csv", time.Now().Format("20060102-150405"))
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	w := csv.NewWriter(c.Response().BodyWriter())
+	if err := w.Write([]string{"row", "name", "email", "code", "role", "class_code", "password"}); err != nil {
+		return err
+	}
+	for _, cred := range credentials {
+		record := []string{strconv.Itoa(cred.Row), csvSafe(cred.Name), csvSafe(cred.Email), cred.Code, cred.Role, cred.ClassCode, cred.Password}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// csvSafe stops spreadsheet apps from evaluating user supplied text as a
+// formula when the export is opened.
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
@@ -1,7 +1,10 @@
 package middleware
 
 import (
+	"jk-api/internal/constant"
+	"jk-api/internal/tenant"
 	"os"
+	"strconv"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
@@ -36,6 +39,46 @@ func JWTMiddleware() fiber.Handler {
 		c.Locals("name", claims["name"])
 		c.Locals("roles", toStringSlice(claims["roles"]))
 		c.Locals("permissions", toStringSlice(claims["permissions"]))
+
+		scope, err := schoolScope(c, claims)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid X-School-ID header",
+			})
+		}
+		c.Locals("school_id", scope.SchoolID)
+		c.Locals("department_scope_id", scope.DepartmentID)
+		c.SetUserContext(tenant.WithScope(c.UserContext(), scope))
 		return c.Next()
 	}
 }
+
+// schoolScope resolves the school and department subtree a request acts for
+// from the token. Platform admins see every school unless they pick one with
+// the X-School-ID header.
+func schoolScope(c *fiber.Ctx, claims jwt.MapClaims) (tenant.Scope, error) {
+	var scope tenant.Scope
+	if schoolID, ok := claims["school_id"].(float64); ok {
+		id := int64(schoolID)
+		scope.SchoolID = &id
+	}
+	if departmentID, ok := claims["department_scope_id"].(float64); ok {
+		id := int64(departmentID)
+		scope.DepartmentID = &id
+	}
+
+	if !HasRole(c, constant.RolePlatformAdmin) {
+		return scope, nil
+	}
+
+	header := strings.TrimSpace(c.Get("X-School-ID"))
+	if header == "" {
+		return tenant.Scope{AllSchools: true}, nil
+	}
+
+	id, err := strconv.ParseInt(header, 10, 64)
+	if err != nil {
+		return scope, err
+	}
+	return tenant.Scope{SchoolID: &id}, nil
+}
@@ -1,14 +1,21 @@
 package middleware
 
 import (
+	"jk-api/internal/constant"
+
 	"github.com/gofiber/fiber/v2"
 )
 
+// RequireRole lets the request through when the user holds one of roles.
+// Platform admins pass every role check.
 func RequireRole(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
 		userRoles := c.Locals("roles").([]string)
 
 		for _, ur := range userRoles {
+			if ur == constant.RolePlatformAdmin {
+				return c.Next()
+			}
 			for _, r := range roles {
 				if ur == r {
 					return c.Next()
@@ -34,4 +41,18 @@ func RequirePermission(perms ...string) fiber.Handler {
 
 		return fiber.ErrForbidden
 	}
-}
\ No newline at end of file
+}
+
+// HasRole reports whether the authenticated user holds role. Platform admins
+// hold every role.
+func HasRole(c *fiber.Ctx, role string) bool {
+	userRoles, _ := c.Locals("roles").([]string)
+
+	for _, ur := range userRoles {
+		if ur == role || ur == constant.RolePlatformAdmin {
+			return true
+		}
+	}
+
+	return false
+}
@@ -1,15 +1,24 @@
 package presenters
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"jk-api/internal/queryspec"
 
-func SuccessResponse(c *fiber.Ctx, data any, total ...int64) error {
+	"github.com/gofiber/fiber/v2"
+)
+
+// SuccessResponse writes data, and for a page of a list, a "pagination"
+// object with the total, the limit and the cursors of the neighbouring pages.
+func SuccessResponse(c *fiber.Ctx, data any, page ...queryspec.Page) error {
 	resp := fiber.Map{
 		"success": true,
 		"data":    data,
 	}
 
-	if len(total) > 0 && total[0] > 0 {
-		resp["total"] = total[0]
+	if len(page) > 0 {
+		resp["pagination"] = page[0]
 	}
 
 	return c.Status(fiber.StatusOK).JSON(resp)
@@ -29,6 +38,14 @@ func ErrorResponse(c *fiber.Ctx, status int, err error) error {
 	})
 }
 
+func ErrorResponseWithData(c *fiber.Ctx, status int, err error, data any) error {
+	return c.Status(status).JSON(fiber.Map{
+		"success": false,
+		"error":   err.Error(),
+		"data":    data,
+	})
+}
+
 func SuccessResponseWithMessage(c *fiber.Ctx, message string, data any) error {
 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 		"success": true,
@@ -44,6 +61,28 @@ func ErrorResponseWithMessage(c *fiber.Ctx, status int, message string) error {
 	})
 }
 
+// StreamHeaders prepares the response for server-sent events, which are then
+// written with StreamEvent from the body stream writer.
+func StreamHeaders(c *fiber.Ctx) {
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+}
+
+// StreamEvent writes one server-sent event with data as JSON and flushes it.
+// The error tells the client is gone.
+func StreamEvent(w *bufio.Writer, event string, data any) error {
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, payload); err != nil {
+		return err
+	}
+	return w.Flush()
+}
+
 // func SuccessLogin(c *fiber.Ctx, data any, token string) error {
 // 	return c.Status(fiber.StatusOK).JSON(fiber.Map{
 // 		"success": true,
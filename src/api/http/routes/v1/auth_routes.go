@@ -11,9 +11,9 @@ import (
 func AuthRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("auth")
 
-	app.Get("/profile", controllers.GetProfile(c), middleware.JWTMiddleware())
-	app.Post("/login", controllers.Login(c))
-	app.Post("/register", controllers.Register(c))
-	app.Post("/refresh", controllers.RefreshToken(c))
-	app.Post("/logout", controllers.Logout(c), middleware.JWTMiddleware())
+	app.Get("/profile", c.Bind(controllers.GetProfile), middleware.JWTMiddleware())
+	app.Post("/login", c.Bind(controllers.Login))
+	app.Post("/register", c.Bind(controllers.Register))
+	app.Post("/refresh", c.Bind(controllers.RefreshToken))
+	app.Post("/logout", c.Bind(controllers.Logout), middleware.JWTMiddleware())
 }
@@ -0,0 +1,39 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// ContentWorkflowRoutes moves courses, lessons and materials through the
+// editorial workflow. :type is one of course, lesson or material.
+func ContentWorkflowRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("content", middleware.JWTMiddleware())
+
+	submit := middleware.RequirePermission(constant.PermContentSubmit)
+	review := middleware.RequirePermission(constant.PermContentReview)
+	publish := middleware.RequirePermission(constant.PermContentPublish)
+	archive := middleware.RequirePermission(constant.PermContentArchive)
+
+	transition := func(action string) container.Controller {
+		return func(cn *container.AppContainer) fiber.Handler {
+			return controllers.TransitionContent(cn, action)
+		}
+	}
+
+	app.Post("/:type/:id/submit", submit, c.Bind(transition(constant.ContentActionSubmit)))
+	app.Post("/:type/:id/approve", review, c.Bind(transition(constant.ContentActionApprove)))
+	app.Post("/:type/:id/request-changes", review, c.Bind(transition(constant.ContentActionRequestChanges)))
+	app.Post("/:type/:id/publish", publish, c.Bind(transition(constant.ContentActionPublish)))
+	app.Post("/:type/:id/unpublish", publish, c.Bind(transition(constant.ContentActionUnpublish)))
+	app.Post("/:type/:id/archive", archive, c.Bind(transition(constant.ContentActionArchive)))
+	app.Post("/:type/:id/restore", archive, c.Bind(transition(constant.ContentActionRestore)))
+
+	app.Post("/:type/:id/comments", middleware.RequirePermission(constant.PermContentReview, constant.PermContentSubmit), c.Bind(controllers.CreateContentComment))
+	app.Get("/:type/:id/comments", middleware.RequireRole("super", "teacher"), c.Bind(controllers.GetContentComments))
+	app.Get("/:type/:id/transitions", middleware.RequireRole("super", "teacher"), c.Bind(controllers.GetContentTransitions))
+}
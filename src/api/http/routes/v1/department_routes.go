@@ -0,0 +1,23 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func DepartmentRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("departments", middleware.JWTMiddleware())
+
+	app.Get("/", c.Bind(controllers.GetDepartments))
+	app.Get("/tree", c.Bind(controllers.GetDepartmentTree))
+	app.Get("/:id", c.Bind(controllers.GetDepartmentByID))
+	app.Get("/:id/summary", c.Bind(controllers.GetDepartmentSummary))
+	app.Post("/bulk-create", middleware.RequireRole("super"), c.Bind(controllers.BulkCreateDepartments))
+	app.Delete("/bulk-delete", middleware.RequireRole("super"), c.Bind(controllers.BulkDeleteDepartments))
+	app.Post("/", middleware.RequireRole("super"), c.Bind(controllers.CreateDepartment))
+	app.Put("/:id", middleware.RequireRole("super"), c.Bind(controllers.UpdateDepartment))
+	app.Delete("/:id", middleware.RequireRole("super"), c.Bind(controllers.DeleteDepartment))
+}
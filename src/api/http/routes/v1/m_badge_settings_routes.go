@@ -12,9 +12,9 @@ import (
 
 func MBadgeSettings(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("m_badge_settings", middleware.JWTMiddleware())
-	app.Get("/", controllers.GetMBadgeSettings(c))
-	app.Post("/", controllers.CreateMBadgeSettings(c))
-	app.Get("/:id", controllers.GetMBadgeSettingsByID(c))
-	app.Put("/:id", controllers.UpdateMBadgeSettings(c))
-	app.Delete("/:id", controllers.DeleteMBadgeSettings(c))
+	app.Get("/", c.Bind(controllers.GetMBadgeSettings))
+	app.Post("/", c.Bind(controllers.CreateMBadgeSettings))
+	app.Get("/:id", c.Bind(controllers.GetMBadgeSettingsByID))
+	app.Put("/:id", c.Bind(controllers.UpdateMBadgeSettings))
+	app.Delete("/:id", c.Bind(controllers.DeleteMBadgeSettings))
 }
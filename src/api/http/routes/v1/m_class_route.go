@@ -12,9 +12,22 @@ import (
 
 func MClassRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("m_classes", middleware.JWTMiddleware())
-	app.Get("/", controllers.GetMClasses(c))
-	app.Post("/", controllers.CreateMClasses(c))
-	app.Get("/:id", controllers.GetMClassByID(c))
-	app.Put("/:id", controllers.UpdateMClasses(c))
-	app.Delete("/:id", controllers.DeleteMClasses(c))
+	app.Post("/join", middleware.RequireRole("student"), c.Bind(controllers.JoinMClass))
+	app.Put("/join_requests/:requestID/approve", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ApproveClassJoinRequest))
+	app.Put("/join_requests/:requestID/reject", middleware.RequireRole("super", "teacher"), c.Bind(controllers.RejectClassJoinRequest))
+	app.Get("/", c.Bind(controllers.GetMClasses))
+	app.Post("/", c.Bind(controllers.CreateMClasses))
+	app.Get("/:id", c.Bind(controllers.GetMClassByID))
+	app.Put("/:id", c.Bind(controllers.UpdateMClasses))
+	app.Delete("/:id", c.Bind(controllers.DeleteMClasses))
+
+	roster := middleware.RequireRole("super", "teacher")
+	app.Post("/:id/regenerate_code", roster, c.Bind(controllers.RegenerateClassCode))
+	app.Put("/:id/join_settings", roster, c.Bind(controllers.UpdateClassJoinSettings))
+	app.Get("/:id/join_requests", roster, c.Bind(controllers.GetClassJoinRequests))
+	app.Put("/:id/students/:userID/move", roster, c.Bind(controllers.MoveClassStudent))
+	app.Delete("/:id/students/:userID", roster, c.Bind(controllers.RemoveClassStudent))
+	app.Post("/:id/teachers", roster, c.Bind(controllers.AssignClassTeacher))
+	app.Delete("/:id/teachers/:userID", roster, c.Bind(controllers.RemoveClassTeacher))
+	app.Get("/:id/roster_logs", roster, c.Bind(controllers.GetClassRosterLogs))
 }
@@ -12,9 +12,13 @@ import (
 
 func MCourses(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("m_courses", middleware.JWTMiddleware())
-	app.Get("/", controllers.GetMCourses(c))
-	app.Post("/", controllers.CreateMCourse(c))
-	app.Get("/:id", controllers.GetMCourseByID(c))
-	app.Put("/:id", controllers.UpdateMCourse(c))
-	app.Delete("/:id", controllers.DeleteMCourse(c))
+	app.Get("/", c.Bind(controllers.GetMCourses))
+	app.Post("/", c.Bind(controllers.CreateMCourse))
+	app.Get("/templates", middleware.RequireRole("super", "teacher"), c.Bind(controllers.GetMCourseTemplates))
+	app.Post("/import", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ImportMCourse))
+	app.Get("/:id", c.Bind(controllers.GetMCourseByID))
+	app.Post("/:id/clone", middleware.RequireRole("super", "teacher"), c.Bind(controllers.CloneMCourse))
+	app.Get("/:id/export", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ExportMCourse))
+	app.Put("/:id", c.Bind(controllers.UpdateMCourse))
+	app.Delete("/:id", c.Bind(controllers.DeleteMCourse))
 }
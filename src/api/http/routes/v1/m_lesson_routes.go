@@ -13,12 +13,12 @@ import (
 func MLessonRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("m_lessons", middleware.JWTMiddleware())
 
-	app.Get("/", controllers.GetMLessons(c))
-	app.Get("/:id", controllers.GetMLessonByID(c))
-	app.Post("/bulk-create", controllers.BulkCreateMLessons(c))
-	app.Put("/bulk-update", controllers.BulkUpdateMLessons(c))
-	app.Delete("/bulk-delete", controllers.BulkDeleteMLessons(c))
-	app.Post("/", controllers.CreateMLessons(c))
-	app.Put("/:id", controllers.UpdateMLessons(c))
-	app.Delete("/:id", controllers.DeleteMLessons(c))
+	app.Get("/", c.Bind(controllers.GetMLessons))
+	app.Get("/:id", c.Bind(controllers.GetMLessonByID))
+	app.Post("/bulk-create", c.Bind(controllers.BulkCreateMLessons))
+	app.Put("/bulk-update", c.Bind(controllers.BulkUpdateMLessons))
+	app.Delete("/bulk-delete", c.Bind(controllers.BulkDeleteMLessons))
+	app.Post("/", c.Bind(controllers.CreateMLessons))
+	app.Put("/:id", c.Bind(controllers.UpdateMLessons))
+	app.Delete("/:id", c.Bind(controllers.DeleteMLessons))
 }
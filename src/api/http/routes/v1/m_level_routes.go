@@ -12,9 +12,9 @@ import (
 
 func MLevelRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("/m_levels", middleware.JWTMiddleware())
-	app.Get("/", controllers.GetMLevels(c))
-	app.Post("/", controllers.CreateMLevels(c))
-	app.Get("/:id", controllers.GetMLevelByID(c))
-	app.Put("/:id", controllers.UpdateMLevels(c))
-	app.Delete("/:id", controllers.DeleteMLevels(c))
+	app.Get("/", c.Bind(controllers.GetMLevels))
+	app.Post("/", c.Bind(controllers.CreateMLevels))
+	app.Get("/:id", c.Bind(controllers.GetMLevelByID))
+	app.Put("/:id", c.Bind(controllers.UpdateMLevels))
+	app.Delete("/:id", c.Bind(controllers.DeleteMLevels))
 }
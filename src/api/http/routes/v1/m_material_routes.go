@@ -13,14 +13,15 @@ import (
 func MMaterialRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("m_materials", middleware.JWTMiddleware())
 
-	app.Get("/", controllers.GetMMaterials(c))
-	app.Get("/:id", controllers.GetMMaterialByID(c))
-	app.Post("/bulk-create", controllers.BulkCreateMMaterials(c))
-	app.Put("/bulk-update", controllers.BulkUpdateMMaterials(c))
-	app.Delete("/bulk-delete", controllers.BulkDeleteMMaterials(c))
-	app.Post("/", controllers.CreateMMaterials(c))
-	app.Put("/:id", controllers.UpdateMMaterials(c))
-	app.Delete("/:id", controllers.DeleteMMaterials(c))
+	app.Get("/", c.Bind(controllers.GetMMaterials))
+	app.Get("/:id", c.Bind(controllers.GetMMaterialByID))
+	app.Get("/:id/render", c.Bind(controllers.RenderMMaterial))
+	app.Post("/bulk-create", c.Bind(controllers.BulkCreateMMaterials))
+	app.Put("/bulk-update", c.Bind(controllers.BulkUpdateMMaterials))
+	app.Delete("/bulk-delete", c.Bind(controllers.BulkDeleteMMaterials))
+	app.Post("/", c.Bind(controllers.CreateMMaterials))
+	app.Put("/:id", c.Bind(controllers.UpdateMMaterials))
+	app.Delete("/:id", c.Bind(controllers.DeleteMMaterials))
 
-	app.Get("/:id/read", controllers.CreateTWonderingScore(c))
+	app.Get("/:id/read", c.Bind(controllers.CreateTWonderingScore))
 }
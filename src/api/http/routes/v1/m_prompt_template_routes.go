@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MPromptTemplateRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("prompt_templates", middleware.JWTMiddleware(), middleware.RequireRole("super", "teacher"))
+
+	app.Get("/", c.Bind(controllers.GetPromptTemplates))
+	app.Get("/:id", c.Bind(controllers.GetPromptTemplateByID))
+	app.Post("/", c.Bind(controllers.CreatePromptTemplate))
+	app.Put("/:id", c.Bind(controllers.UpdatePromptTemplate))
+	app.Get("/:id/versions", c.Bind(controllers.GetPromptTemplateVersions))
+	app.Get("/:id/versions/:version", c.Bind(controllers.GetPromptTemplateVersion))
+	app.Post("/:id/versions", c.Bind(controllers.CreatePromptTemplateVersion))
+	app.Post("/:id/render", c.Bind(controllers.RenderPromptTemplate))
+	app.Get("/:id/evaluations", c.Bind(controllers.GetPromptEvaluations))
+	app.Post("/:id/evaluations", c.Bind(controllers.CreatePromptEvaluation))
+}
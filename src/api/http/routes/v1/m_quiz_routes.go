@@ -0,0 +1,28 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MQuizRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("quizzes", middleware.JWTMiddleware())
+
+	app.Get("/", c.Bind(controllers.GetQuizzes))
+	app.Get("/attempts/:attemptID", c.Bind(controllers.GetQuizAttemptByID))
+	app.Post("/attempts/:attemptID/submit", c.Bind(controllers.SubmitQuizAttempt))
+	app.Get("/:id", c.Bind(controllers.GetQuizByID))
+	app.Get("/:id/attempts", c.Bind(controllers.GetQuizAttempts))
+	app.Post("/:id/attempts", c.Bind(controllers.StartQuizAttempt))
+	app.Get("/:id/analytics", middleware.RequireRole("super", "teacher"), c.Bind(controllers.GetQuizAnalytics))
+
+	app.Post("/", middleware.RequireRole("super", "teacher"), c.Bind(controllers.CreateQuiz))
+	app.Put("/:id", middleware.RequireRole("super", "teacher"), c.Bind(controllers.UpdateQuiz))
+	app.Delete("/:id", middleware.RequireRole("super", "teacher"), c.Bind(controllers.DeleteQuiz))
+	app.Post("/:id/items", middleware.RequireRole("super", "teacher"), c.Bind(controllers.CreateQuizItem))
+	app.Put("/:id/items/:itemID", middleware.RequireRole("super", "teacher"), c.Bind(controllers.UpdateQuizItem))
+	app.Delete("/:id/items/:itemID", middleware.RequireRole("super", "teacher"), c.Bind(controllers.DeleteQuizItem))
+}
@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MSchoolRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("m_schools", middleware.JWTMiddleware(), middleware.RequireRole(constant.RolePlatformAdmin))
+	app.Get("/", c.Bind(controllers.GetMSchools))
+	app.Get("/:id", c.Bind(controllers.GetMSchoolByID))
+	app.Post("/", c.Bind(controllers.CreateMSchool))
+	app.Put("/:id", c.Bind(controllers.UpdateMSchool))
+	app.Delete("/:id", c.Bind(controllers.DeleteMSchool))
+}
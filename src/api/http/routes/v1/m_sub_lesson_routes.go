@@ -13,12 +13,12 @@ import (
 func MSubLessonRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("m_sub_lessons", middleware.JWTMiddleware())
 
-	app.Get("/", controllers.GetMSubLessons(c))
-	app.Get("/:id", controllers.GetMSubLessonByID(c))
-	app.Post("/bulk-create", controllers.BulkCreateMSubLessons(c))
-	app.Put("/bulk-update", controllers.BulkUpdateMSubLessons(c))
-	app.Delete("/bulk-delete", controllers.BulkDeleteMSubLessons(c))
-	app.Post("/", controllers.CreateMSubLessons(c))
-	app.Put("/:id", controllers.UpdateMSubLessons(c))
-	app.Delete("/:id", controllers.DeleteMSubLessons(c))
+	app.Get("/", c.Bind(controllers.GetMSubLessons))
+	app.Get("/:id", c.Bind(controllers.GetMSubLessonByID))
+	app.Post("/bulk-create", c.Bind(controllers.BulkCreateMSubLessons))
+	app.Put("/bulk-update", c.Bind(controllers.BulkUpdateMSubLessons))
+	app.Delete("/bulk-delete", c.Bind(controllers.BulkDeleteMSubLessons))
+	app.Post("/", c.Bind(controllers.CreateMSubLessons))
+	app.Put("/:id", c.Bind(controllers.UpdateMSubLessons))
+	app.Delete("/:id", c.Bind(controllers.DeleteMSubLessons))
 }
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MWebhookSubscriptionRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("webhooks", middleware.JWTMiddleware(), middleware.RequireRole(constant.RolePlatformAdmin))
+	app.Get("/", c.Bind(controllers.GetMWebhookSubscriptions))
+	app.Get("/:id", c.Bind(controllers.GetMWebhookSubscriptionByID))
+	app.Post("/", c.Bind(controllers.CreateMWebhookSubscription))
+	app.Put("/:id", c.Bind(controllers.UpdateMWebhookSubscription))
+	app.Delete("/:id", c.Bind(controllers.DeleteMWebhookSubscription))
+	app.Post("/:id/test", c.Bind(controllers.TestMWebhookSubscription))
+}
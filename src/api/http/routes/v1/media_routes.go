@@ -0,0 +1,41 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+	"jk-api/internal/storage"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func MediaRoutes(router fiber.Router, c *container.AppContainer) {
+	// Files in local storage are public like objects in a public bucket, so
+	// they are served before the JWT middleware of the group below. Protected
+	// files are left to the signed route.
+	if config.AppConfig.MediaDriver == constant.MediaDriverLocal {
+		router.Static("/media/files", config.AppConfig.MediaLocalDir, fiber.Static{
+			// The normalized path, so escaped slashes can't slip past.
+			Next: func(c *fiber.Ctx) bool {
+				return strings.Contains(string(c.Request().URI().Path()), "/"+storage.ProtectedPrefix)
+			},
+		})
+	}
+
+	// Signed links carry their own authorization so video players, which
+	// can't send a bearer token, can stream them.
+	router.Get("/media/protected/:id", c.Bind(controllers.ServeProtectedMedia))
+
+	app := router.Group("media", middleware.JWTMiddleware())
+
+	uploaders := middleware.RequireRole("super", "teacher")
+	app.Post("/", uploaders, c.Bind(controllers.UploadMedia))
+	app.Post("/uploads", uploaders, c.Bind(controllers.CreateMediaUpload))
+	app.Get("/uploads/:id", uploaders, c.Bind(controllers.GetMediaUpload))
+	app.Put("/uploads/:id", uploaders, c.Bind(controllers.AppendMediaUpload))
+	app.Get("/signed-url", c.Bind(controllers.SignMediaURL))
+	app.Get("/:id", c.Bind(controllers.GetMediaByID))
+}
@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func NotificationRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("notifications", middleware.JWTMiddleware())
+	app.Get("/", c.Bind(controllers.GetMyNotifications))
+	app.Get("/unread_count", c.Bind(controllers.CountUnreadNotifications))
+	app.Get("/preferences", c.Bind(controllers.GetNotificationPreferences))
+	app.Put("/preferences", c.Bind(controllers.UpdateNotificationPreferences))
+	app.Put("/read_all", c.Bind(controllers.MarkAllNotificationsRead))
+	app.Put("/:id/read", c.Bind(controllers.MarkNotificationRead))
+	app.Put("/:id/unread", c.Bind(controllers.MarkNotificationUnread))
+}
@@ -13,9 +13,9 @@ import (
 func PermissionRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("permissions", middleware.JWTMiddleware())
 
-	app.Get("/", controllers.GetPermissions(c))
-	app.Get("/:id", controllers.GetPermissionByID(c))
-	app.Post("/", controllers.CreatePermissions(c))
-	app.Put("/:id", controllers.UpdatePermissions(c))
-	app.Delete("/:id", controllers.DeletePermissions(c))
+	app.Get("/", c.Bind(controllers.GetPermissions))
+	app.Get("/:id", c.Bind(controllers.GetPermissionByID))
+	app.Post("/", c.Bind(controllers.CreatePermissions))
+	app.Put("/:id", c.Bind(controllers.UpdatePermissions))
+	app.Delete("/:id", c.Bind(controllers.DeletePermissions))
 }
@@ -12,9 +12,9 @@ import (
 
 func RoleRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("roles", middleware.JWTMiddleware())
-	app.Get("/", controllers.GetRoles(c))
-	app.Post("/", controllers.CreateRoles(c))
-	app.Get("/:id", controllers.GetRoleByID(c))
-	app.Put("/:id", controllers.UpdateRoles(c))
-	app.Delete("/:id", controllers.DeleteRoles(c))
+	app.Get("/", c.Bind(controllers.GetRoles))
+	app.Post("/", c.Bind(controllers.CreateRoles))
+	app.Get("/:id", c.Bind(controllers.GetRoleByID))
+	app.Put("/:id", c.Bind(controllers.UpdateRoles))
+	app.Delete("/:id", c.Bind(controllers.DeleteRoles))
 }
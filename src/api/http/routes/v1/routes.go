@@ -27,4 +27,20 @@ func Setup(app *fiber.App, c *container.AppContainer) {
 	TCodeAnswerRoute(api, c)
 	TWonderingScoreRoute(api, c)
 	TEssayAnswerRoute(api, c)
+	TEventOutboxRoutes(api, c)
+	MWebhookSubscriptionRoutes(api, c)
+	TWebhookDeliveryRoutes(api, c)
+	NotificationRoutes(api, c)
+	TClassCourseRoutes(api, c)
+	MSchoolRoutes(api, c)
+	DepartmentRoutes(api, c)
+	TGuardianLinkRoutes(api, c)
+	ContentWorkflowRoutes(api, c)
+	TCourseVersionRoutes(api, c)
+	MediaRoutes(api, c)
+	SearchRoutes(api, c)
+	TGenerationHistoryRoutes(api, c)
+	MPromptTemplateRoutes(api, c)
+	TTutorConversationRoutes(api, c)
+	MQuizRoutes(api, c)
 }
@@ -0,0 +1,15 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func SearchRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("search", middleware.JWTMiddleware())
+
+	app.Get("/", c.Bind(controllers.SearchContent))
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TClassCourseRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("t_class_courses", middleware.JWTMiddleware())
+	app.Get("/due", middleware.RequireRole("student"), c.Bind(controllers.GetMyDueItems))
+
+	teacher := middleware.RequireRole("super", "teacher")
+	app.Get("/", teacher, c.Bind(controllers.GetClassCourses))
+	app.Post("/", teacher, c.Bind(controllers.AssignClassCourse))
+	app.Get("/:id", teacher, c.Bind(controllers.GetClassCourseByID))
+	app.Put("/:id", teacher, c.Bind(controllers.UpdateClassCourse))
+	app.Delete("/:id", teacher, c.Bind(controllers.UnassignClassCourse))
+	app.Get("/:id/overdue", teacher, c.Bind(controllers.GetClassCourseOverdueReport))
+}
@@ -12,7 +12,7 @@ import (
 
 func TCodeAnswerRoute(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("t_code_answer", middleware.JWTMiddleware())
-	app.Get("/code_questions/:codeQuestionID", controllers.GetTCodeAnswersByCodeQuestionID(c))
-	app.Post("/", controllers.CreateTCodeAnswer(c))
+	app.Get("/code_questions/:codeQuestionID", c.Bind(controllers.GetTCodeAnswersByCodeQuestionID))
+	app.Post("/", c.Bind(controllers.CreateTCodeAnswer))
 	
 }
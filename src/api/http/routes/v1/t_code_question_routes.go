@@ -12,8 +12,8 @@ import (
 
 func TCodeQuestionRoute(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("code_questions", middleware.JWTMiddleware())
-	app.Get("/sub_lesson/:subLessonID", controllers.GetTcodeQuestionsBySubLessonID(c))
-	app.Get("/:id", controllers.GetTCodeQuestionByID(c))
-	app.Post("/", controllers.CreateTCodeQuestions(c))
+	app.Get("/sub_lesson/:subLessonID", c.Bind(controllers.GetTcodeQuestionsBySubLessonID))
+	app.Get("/:id", c.Bind(controllers.GetTCodeQuestionByID))
+	app.Post("/", c.Bind(controllers.CreateTCodeQuestions))
 	
 }
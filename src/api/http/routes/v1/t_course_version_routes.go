@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TCourseVersionRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("course_versions", middleware.JWTMiddleware())
+
+	authors := middleware.RequireRole("super", "teacher")
+	app.Get("/", authors, c.Bind(controllers.GetCourseVersions))
+	app.Get("/diff", authors, c.Bind(controllers.DiffCourseVersions))
+	app.Get("/:id", authors, c.Bind(controllers.GetCourseVersionByID))
+	app.Post("/:id/rollback", middleware.RequirePermission(constant.PermContentPublish), c.Bind(controllers.RollbackCourseVersion))
+}
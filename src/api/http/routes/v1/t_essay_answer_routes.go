@@ -12,7 +12,8 @@ import (
 
 func TEssayAnswerRoute(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("t_essay_answer", middleware.JWTMiddleware())
-	app.Get("/essay_questions/:essayQuestionID", controllers.GetTEssayAnswersByEssayQuestionIDAndUserID(c))
-	app.Post("/", controllers.CreateTEssayAnswer(c))
+	app.Get("/essay_questions/:essayQuestionID", c.Bind(controllers.GetTEssayAnswersByEssayQuestionIDAndUserID))
+	app.Post("/", c.Bind(controllers.CreateTEssayAnswer))
+	app.Put("/:id/approve", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ApproveTEssayAnswer))
 	
 }
@@ -12,8 +12,8 @@ import (
 
 func EssayQuestionRoute(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("essay_questions", middleware.JWTMiddleware())
-	app.Get("/code_questions/:codeQuestionID", controllers.GetEssayQuestionsByCodeQuestionID(c))
-	app.Get("/:id", controllers.GetEssayQuestionByID(c))
-	app.Post("/", controllers.CreateEssayQuestions(c))
+	app.Get("/code_questions/:codeQuestionID", c.Bind(controllers.GetEssayQuestionsByCodeQuestionID))
+	app.Get("/:id", c.Bind(controllers.GetEssayQuestionByID))
+	app.Post("/", c.Bind(controllers.CreateEssayQuestions))
 	
 }
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TEventOutboxRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("event_outbox", middleware.JWTMiddleware(), middleware.RequireRole(constant.RolePlatformAdmin))
+	app.Get("/", c.Bind(controllers.GetTEventOutboxes))
+	app.Get("/:id", c.Bind(controllers.GetTEventOutboxByID))
+	app.Post("/:id/retry", c.Bind(controllers.RetryTEventOutbox))
+}
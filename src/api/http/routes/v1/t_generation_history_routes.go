@@ -0,0 +1,19 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TGenerationHistoryRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("generations", middleware.JWTMiddleware(), middleware.RequireRole("super", "teacher"))
+
+	app.Get("/", c.Bind(controllers.GetGenerations))
+	app.Get("/:id", c.Bind(controllers.GetGenerationByID))
+	app.Post("/", c.Bind(controllers.GenerateContent))
+	app.Post("/:id/accept", c.Bind(controllers.AcceptGeneration))
+	app.Post("/:id/discard", c.Bind(controllers.DiscardGeneration))
+}
@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TGuardianLinkRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("guardians", middleware.JWTMiddleware())
+
+	guardian := middleware.RequireRole(constant.RoleGuardian)
+	app.Post("/invites", guardian, c.Bind(controllers.InviteGuardianStudent))
+	app.Get("/children", guardian, c.Bind(controllers.GetGuardianChildren))
+	app.Get("/children/:studentID/dashboard", guardian, c.Bind(controllers.GetGuardianChildDashboard))
+
+	app.Get("/invites", c.Bind(controllers.GetGuardianLinks))
+	app.Put("/invites/:id/accept", c.Bind(controllers.AcceptGuardianInvite))
+	app.Put("/invites/:id/reject", c.Bind(controllers.RejectGuardianInvite))
+	app.Delete("/invites/:id", c.Bind(controllers.RevokeGuardianLink))
+}
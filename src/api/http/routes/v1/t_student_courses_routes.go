@@ -12,7 +12,8 @@ import (
 
 func TStudentCourseRoute(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("t_student_courses", middleware.JWTMiddleware())
-	app.Get("/my_courses", controllers.GetMyCourse(c))
-	app.Get("/:id", controllers.GetTStudentCourseByID(c))
-	app.Post("/:id/enroll", controllers.EnrollCourse(c))
+	app.Get("/my_courses", c.Bind(controllers.GetMyCourse))
+	app.Get("/:id", c.Bind(controllers.GetTStudentCourseByID))
+	app.Post("/:id/enroll", c.Bind(controllers.EnrollCourse))
+	app.Put("/:id/version", c.Bind(controllers.MigrateCourseVersion))
 }
@@ -12,5 +12,13 @@ import (
 
 func TStudentProgressRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("t_student_progress", middleware.JWTMiddleware())
-	app.Post("/complete", controllers.CompleteTStudentProgress(c))
+	app.Post("/start", c.Bind(controllers.StartTStudentProgress))
+	app.Post("/complete", c.Bind(controllers.CompleteTStudentProgress))
+	app.Post("/needs_review", c.Bind(controllers.SubmitTStudentProgressForReview))
+	app.Get("/courses/:courseID", c.Bind(controllers.GetMyProgressTree))
+
+	app.Post("/approve", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ApproveTStudentProgress))
+	app.Post("/reset", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ResetTStudentProgress))
+	app.Get("/users/:userID/courses/:courseID", middleware.RequireRole("super", "teacher"), c.Bind(controllers.GetStudentProgressTree))
+	app.Post("/recompute", middleware.RequireRole("super"), c.Bind(controllers.RecomputeTStudentProgress))
 }
@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TTutorConversationRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("tutor/conversations", middleware.JWTMiddleware())
+
+	app.Get("/", c.Bind(controllers.GetTutorConversations))
+	app.Get("/:id", c.Bind(controllers.GetTutorConversationByID))
+	app.Post("/", c.Bind(controllers.CreateTutorConversation))
+	app.Post("/:id/messages", c.Bind(controllers.SendTutorMessage))
+}
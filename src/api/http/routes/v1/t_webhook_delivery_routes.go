@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"jk-api/api/http/controllers/v1"
+	"jk-api/api/http/middleware"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+func TWebhookDeliveryRoutes(router fiber.Router, c *container.AppContainer) {
+	app := router.Group("webhook_deliveries", middleware.JWTMiddleware(), middleware.RequireRole(constant.RolePlatformAdmin))
+	app.Get("/", c.Bind(controllers.GetTWebhookDeliveries))
+	app.Get("/:id", c.Bind(controllers.GetTWebhookDeliveryByID))
+	app.Post("/:id/replay", c.Bind(controllers.ReplayTWebhookDelivery))
+}
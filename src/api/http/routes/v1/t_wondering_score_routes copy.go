@@ -12,6 +12,6 @@ import (
 
 func TWonderingScoreRoute(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("t_wondering_score", middleware.JWTMiddleware())
-	app.Post("/", controllers.CreateTWonderingScore(c))
-	app.Get("/sub_lesson/:subLessonID", controllers.GetTWonderingScoresBySubLessonID(c))
+	app.Post("/", c.Bind(controllers.CreateTWonderingScore))
+	app.Get("/sub_lesson/:subLessonID", c.Bind(controllers.GetTWonderingScoresBySubLessonID))
 }
@@ -0,0 +1,398 @@
+package routes
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/container"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang-jwt/jwt/v5"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// Lessons and sub-lessons belong to a school through their course. A teacher
+// of another school gets a 404 for them, whether or not the controller asked
+// for the request scope itself.
+func TestCrossSchoolContentIsNotFound(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		path      string
+		body      string
+		claims    jwt.MapClaims
+		wantQuery string
+		wantArg   any
+	}{
+		{
+			name:      "read lesson",
+			method:    fiber.MethodGet,
+			path:      "/api/v1/m_lessons/5",
+			claims:    jwt.MapClaims{"user_id": 1, "roles": []string{"teacher"}, "school_id": 2},
+			wantQuery: "m_lesson.course_id IN (SELECT m_course.id FROM m_course WHERE (m_course.school_id = $",
+			wantArg:   int64(2),
+		},
+		{
+			name:      "edit lesson",
+			method:    fiber.MethodPut,
+			path:      "/api/v1/m_lessons/5",
+			body:      `{"title":"Diambil alih"}`,
+			claims:    jwt.MapClaims{"user_id": 1, "roles": []string{"teacher"}, "school_id": 2},
+			wantQuery: "m_lesson.course_id IN (SELECT m_course.id FROM m_course WHERE (m_course.school_id = $",
+			wantArg:   int64(2),
+		},
+		{
+			name:      "read sub-lesson",
+			method:    fiber.MethodGet,
+			path:      "/api/v1/m_sub_lessons/7",
+			claims:    jwt.MapClaims{"user_id": 1, "roles": []string{"teacher"}, "school_id": 3},
+			wantQuery: "m_sub_lesson.lesson_id IN (SELECT m_lesson.id FROM m_lesson WHERE m_lesson.course_id IN (SELECT m_course.id FROM m_course WHERE (m_course.school_id = $",
+			wantArg:   int64(3),
+		},
+	}
+
+	db := openRecordingDB(t)
+	app := newScopedTestApp(t)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.reset()
+
+			resp, err := app.Test(newTestRequest(t, tt.method, tt.path, tt.body, tt.claims))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusNotFound {
+				body, _ := io.ReadAll(resp.Body)
+				t.Fatalf("status = %d (%s), want 404", resp.StatusCode, body)
+			}
+
+			if !db.ran(tt.wantQuery, tt.wantArg) {
+				t.Errorf("no query scoped by %q with %v, ran:\n%s", tt.wantQuery, tt.wantArg, strings.Join(db.queries(), "\n"))
+			}
+		})
+	}
+}
+
+// A student's progress tree only holds published courses of their school.
+func TestProgressTreeIsScopedAndPublished(t *testing.T) {
+	db := openRecordingDB(t)
+	app := newScopedTestApp(t)
+
+	claims := jwt.MapClaims{"user_id": 1, "roles": []string{"student"}, "school_id": 2}
+	resp, err := app.Test(newTestRequest(t, fiber.MethodGet, "/api/v1/t_student_progress/courses/9", "", claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusNotFound {
+		t.Fatalf("status = %d, want 404", resp.StatusCode)
+	}
+
+	if !db.ran("(m_course.school_id = $", int64(2)) {
+		t.Errorf("course tree not scoped by school, ran:\n%s", strings.Join(db.queries(), "\n"))
+	}
+	if !db.ran("m_course.status = $", constant.ContentPublished) {
+		t.Errorf("course tree not limited to published courses, ran:\n%s", strings.Join(db.queries(), "\n"))
+	}
+}
+
+// Students only see sub-lessons of published lessons in published courses;
+// teachers preview drafts too.
+func TestSubLessonsArePublishedForStudents(t *testing.T) {
+	db := openRecordingDB(t)
+	app := newScopedTestApp(t)
+
+	const published = "FROM m_lesson WHERE m_lesson.status = $"
+
+	tests := []struct {
+		name          string
+		path          string
+		role          string
+		wantPublished bool
+	}{
+		{"student lists", "/api/v1/m_sub_lessons", "student", true},
+		{"student reads", "/api/v1/m_sub_lessons/7", "student", true},
+		{"teacher lists", "/api/v1/m_sub_lessons", "teacher", false},
+		{"teacher reads", "/api/v1/m_sub_lessons/7", "teacher", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db.reset()
+
+			claims := jwt.MapClaims{"user_id": 1, "roles": []string{tt.role}, "school_id": 2}
+			if _, err := app.Test(newTestRequest(t, fiber.MethodGet, tt.path, "", claims)); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := db.ran(published, constant.ContentPublished); got != tt.wantPublished {
+				t.Errorf("limited to published = %v, want %v, ran:\n%s", got, tt.wantPublished, strings.Join(db.queries(), "\n"))
+			}
+		})
+	}
+}
+
+// The course list filters and sorts through the whitelist of MCourse, and
+// counts its total under the same filters.
+func TestCourseListUsesQuerySpec(t *testing.T) {
+	db := openRecordingDB(t)
+	app := newScopedTestApp(t)
+	claims := jwt.MapClaims{"user_id": 1, "roles": []string{"teacher"}, "school_id": 2}
+
+	resp, err := app.Test(newTestRequest(t, fiber.MethodGet, "/api/v1/m_courses?filter[course_name][contains]=aljabar&sort=-created_at", "", claims))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.StatusCode != fiber.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		t.Fatalf("status = %d (%s), want 200", resp.StatusCode, body)
+	}
+	for _, fragment := range []string{"SELECT count(*) FROM \"m_course\"", "ORDER BY m_course.created_at DESC"} {
+		if !db.ran(fragment, "%aljabar%") {
+			t.Errorf("no query %q filtered by course_name, ran:\n%s", fragment, strings.Join(db.queries(), "\n"))
+		}
+	}
+
+	for _, query := range []string{"filter[password]=x", "sort=password"} {
+		resp, err := app.Test(newTestRequest(t, fiber.MethodGet, "/api/v1/m_courses?"+query, "", claims))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusBadRequest {
+			t.Errorf("GET /m_courses?%s = %d, want 400", query, resp.StatusCode)
+		}
+	}
+}
+
+func TestPlatformAdminContentIsNotScoped(t *testing.T) {
+	db := openRecordingDB(t)
+	app := newScopedTestApp(t)
+
+	claims := jwt.MapClaims{"user_id": 1, "roles": []string{constant.RolePlatformAdmin}}
+	if _, err := app.Test(newTestRequest(t, fiber.MethodGet, "/api/v1/m_lessons/5", "", claims)); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, query := range db.queries() {
+		if strings.Contains(query, "m_course") {
+			t.Errorf("platform admin query scoped by school: %s", query)
+		}
+	}
+	if len(db.queries()) == 0 {
+		t.Error("no query ran")
+	}
+}
+
+// Webhooks and the outbox carry events of every school, so a school's own
+// admin may not reach them.
+func TestEventRoutesArePlatformAdminOnly(t *testing.T) {
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	app := fiber.New()
+	api := app.Group("/api/v1")
+	cn := &container.AppContainer{}
+	MWebhookSubscriptionRoutes(api, cn)
+	TWebhookDeliveryRoutes(api, cn)
+	TEventOutboxRoutes(api, cn)
+
+	claims := jwt.MapClaims{"user_id": 1, "roles": []string{"super"}, "school_id": 2}
+	for _, path := range []string{"/api/v1/webhooks", "/api/v1/webhook_deliveries", "/api/v1/event_outbox"} {
+		resp, err := app.Test(newTestRequest(t, fiber.MethodGet, path, "", claims))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.StatusCode != fiber.StatusForbidden {
+			t.Errorf("GET %s as a school admin = %d, want 403", path, resp.StatusCode)
+		}
+	}
+}
+
+func newScopedTestApp(t *testing.T) *fiber.App {
+	t.Helper()
+	t.Setenv("JWT_SECRET", "test-secret")
+
+	cn := &container.AppContainer{
+		MCourseHandler:    container.InitMCourseContainer(),
+		MLessonHandler:    container.InitMLessonContainer(),
+		MSubLessonHandler: container.InitMSubLessonContainer(),
+
+		TStudentProgressHandler: container.InitTStudentProgressContainer(),
+	}
+
+	app := fiber.New()
+	api := app.Group("/api/v1")
+	MCourses(api, cn)
+	MLessonRoutes(api, cn)
+	MSubLessonRoutes(api, cn)
+	TStudentProgressRoutes(api, cn)
+	return app
+}
+
+func newTestRequest(t *testing.T, method string, path string, body string, claims jwt.MapClaims) *http.Request {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test-secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+// recordingDB stands in for Postgres: every query finds nothing, and the
+// statements are kept so tests can check how they were scoped.
+type recordingDB struct {
+	mu    sync.Mutex
+	calls []recordedQuery
+}
+
+type recordedQuery struct {
+	query string
+	args  []driver.NamedValue
+}
+
+var registerRecordingDriver sync.Once
+
+// openRecordingDB points config.DB at a fresh recordingDB for the test.
+func openRecordingDB(t *testing.T) *recordingDB {
+	t.Helper()
+
+	recorder := &recordingDB{}
+	registerRecordingDriver.Do(func() {
+		sql.Register("recording", recordingDriver{})
+	})
+	sqlDB, err := sql.Open("recording", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	activeRecorder = recorder
+
+	db, err := gorm.Open(postgres.New(postgres.Config{Conn: sqlDB}), &gorm.Config{
+		DisableAutomaticPing: true,
+		Logger:               logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	previous := config.DB
+	config.DB = db
+	t.Cleanup(func() {
+		config.DB = previous
+		sqlDB.Close()
+	})
+	return recorder
+}
+
+func (r *recordingDB) record(query string, args []driver.NamedValue) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = append(r.calls, recordedQuery{query: query, args: args})
+}
+
+func (r *recordingDB) reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.calls = nil
+}
+
+func (r *recordingDB) queries() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	queries := make([]string, len(r.calls))
+	for i, call := range r.calls {
+		queries[i] = call.query
+	}
+	return queries
+}
+
+// ran reports whether a statement containing fragment was run with arg.
+func (r *recordingDB) ran(fragment string, arg any) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, call := range r.calls {
+		if !strings.Contains(call.query, fragment) {
+			continue
+		}
+		for _, value := range call.args {
+			if value.Value == arg {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+var activeRecorder *recordingDB
+
+type recordingDriver struct{}
+
+func (recordingDriver) Open(name string) (driver.Conn, error) {
+	return recordingConn{}, nil
+}
+
+type recordingConn struct{}
+
+func (recordingConn) Prepare(query string) (driver.Stmt, error) {
+	return nil, driver.ErrSkip
+}
+
+func (recordingConn) Close() error {
+	return nil
+}
+
+func (recordingConn) Begin() (driver.Tx, error) {
+	return recordingTx{}, nil
+}
+
+func (recordingConn) CheckNamedValue(*driver.NamedValue) error {
+	return nil
+}
+
+func (recordingConn) QueryContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Rows, error) {
+	activeRecorder.record(query, args)
+	return emptyRows{}, nil
+}
+
+func (recordingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	activeRecorder.record(query, args)
+	return driver.RowsAffected(0), nil
+}
+
+type recordingTx struct{}
+
+func (recordingTx) Commit() error {
+	return nil
+}
+
+func (recordingTx) Rollback() error {
+	return nil
+}
+
+type emptyRows struct{}
+
+func (emptyRows) Columns() []string {
+	return nil
+}
+
+func (emptyRows) Close() error {
+	return nil
+}
+
+func (emptyRows) Next(dest []driver.Value) error {
+	return io.EOF
+}
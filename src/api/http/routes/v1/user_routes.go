@@ -13,12 +13,14 @@ import (
 func UserRoutes(router fiber.Router, c *container.AppContainer) {
 	app := router.Group("users", middleware.JWTMiddleware())
 
-	app.Get("/", controllers.GetUsers(c))
-	app.Get("/:id", controllers.GetUserByID(c))
-	app.Post("/bulk-create", controllers.BulkCreateUsers(c))
-	app.Put("/bulk-update", controllers.BulkUpdateUsers(c))
-	app.Delete("/bulk-delete", controllers.BulkDeleteUsers(c))
-	app.Post("/", controllers.CreateUsers(c))
-	app.Put("/:id", controllers.UpdateUsers(c))
-	app.Delete("/:id", controllers.DeleteUsers(c))
+	app.Get("/", c.Bind(controllers.GetUsers))
+	app.Get("/:id", c.Bind(controllers.GetUserByID))
+	app.Post("/bulk-create", c.Bind(controllers.BulkCreateUsers))
+	app.Post("/import", middleware.RequireRole("super", "teacher"), c.Bind(controllers.ImportUsers))
+	app.Put("/bulk-update", c.Bind(controllers.BulkUpdateUsers))
+	app.Delete("/bulk-delete", c.Bind(controllers.BulkDeleteUsers))
+	app.Post("/", c.Bind(controllers.CreateUsers))
+	app.Put("/:id", c.Bind(controllers.UpdateUsers))
+	app.Put("/:id/approve", middleware.RequireRole("super"), c.Bind(controllers.ApproveTeacher))
+	app.Delete("/:id", c.Bind(controllers.DeleteUsers))
 }
@@ -2,6 +2,7 @@ package bootstrap
 
 import (
 	"jk-api/internal/config"
+	"jk-api/internal/container"
 	"jk-api/internal/database/migrations"
 	"jk-api/internal/database/seeders"
 )
@@ -14,7 +15,13 @@ func Setup() {
 	// InitNeo4j()
 
 	//runMigrate()
-	InitFiber()
+	cn := container.NewAppContainer()
+	InitEventRelay(cn)
+	InitWebhookDispatcher(cn)
+	InitNotificationDispatcher(cn)
+	InitGuardianDigest(cn)
+	InitMediaUploadCleanup(cn)
+	InitFiber(cn)
 }
 
 func runMigrate() {
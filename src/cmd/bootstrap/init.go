@@ -1,9 +1,11 @@
 package bootstrap
 
 import (
+	"context"
 	"jk-api/api/http/routes/v1"
 	"jk-api/internal/config"
 	"jk-api/internal/container"
+	"time"
 )
 
 func InitLogger() {
@@ -30,9 +32,127 @@ func InitPostgres() {
 	config.Logger.Info("✅ Postgres initialized")
 }
 
-func InitFiber() {
+// InitEventRelay starts the background loop that dispatches outbox events.
+func InitEventRelay(cn *container.AppContainer) {
+	cfg := config.AppConfig
+
+	go func() {
+		ticker := time.NewTicker(cfg.EventRelayInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			_, err := cn.TEventOutboxHandler.RelayTEventOutboxHandler(
+				context.Background(),
+				cfg.EventRelayBatchSize,
+				cfg.EventRelayMaxAttempts,
+			)
+			if err != nil {
+				config.Logger.Errorf("❌ Event relay batch failed: %v", err)
+			}
+		}
+	}()
+
+	config.Logger.Infof("✅ Event relay started (every %s)", cfg.EventRelayInterval)
+}
+
+// InitWebhookDispatcher starts the background loop that sends queued webhook
+// deliveries. Each batch's lease covers every send timing out, so a slow
+// batch is never picked up twice.
+func InitWebhookDispatcher(cn *container.AppContainer) {
+	cfg := config.AppConfig
+	lease := time.Duration(cfg.WebhookBatchSize)*cfg.WebhookTimeout + time.Minute
+
+	go func() {
+		ticker := time.NewTicker(cfg.WebhookInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			_, err := cn.TWebhookDeliveryHandler.DeliverPendingTWebhookDeliveriesHandler(
+				context.Background(),
+				cfg.WebhookBatchSize,
+				cfg.WebhookMaxAttempts,
+				lease,
+			)
+			if err != nil {
+				config.Logger.Errorf("❌ Webhook delivery batch failed: %v", err)
+			}
+		}
+	}()
+
+	config.Logger.Infof("✅ Webhook dispatcher started (every %s)", cfg.WebhookInterval)
+}
+
+// InitNotificationDispatcher starts the background loop that sends queued
+// push and omni-channel notifications. Each batch's lease covers every send
+// timing out, so a slow batch is never picked up twice.
+func InitNotificationDispatcher(cn *container.AppContainer) {
+	cfg := config.AppConfig
+	lease := time.Duration(cfg.NotificationBatchSize)*cfg.NotificationTimeout + time.Minute
+
+	go func() {
+		ticker := time.NewTicker(cfg.NotificationInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			_, err := cn.NotificationHandler.DeliverPendingNotificationsHandler(
+				context.Background(),
+				cfg.NotificationBatchSize,
+				cfg.NotificationMaxAttempts,
+				lease,
+			)
+			if err != nil {
+				config.Logger.Errorf("❌ Notification delivery batch failed: %v", err)
+			}
+		}
+	}()
+
+	config.Logger.Infof("✅ Notification dispatcher started (every %s)", cfg.NotificationInterval)
+}
+
+// InitGuardianDigest starts the background loop that raises the weekly
+// guardian digests. Each guardian gets one digest per ISO week, sent on the
+// first tick of the week.
+func InitGuardianDigest(cn *container.AppContainer) {
+	cfg := config.AppConfig
+
+	go func() {
+		ticker := time.NewTicker(cfg.GuardianDigestInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			_, err := cn.TGuardianLinkHandler.PublishWeeklyDigestsHandler(time.Now())
+			if err != nil {
+				config.Logger.Errorf("❌ Guardian digest failed: %v", err)
+			}
+		}
+	}()
+
+	config.Logger.Infof("✅ Guardian digest started (every %s)", cfg.GuardianDigestInterval)
+}
+
+// InitMediaUploadCleanup starts the background loop that drops expired
+// resumable uploads and their staging files.
+func InitMediaUploadCleanup(cn *container.AppContainer) {
+	cfg := config.AppConfig
+
+	go func() {
+		ticker := time.NewTicker(cfg.MediaCleanupInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			_, err := cn.MediaHandler.CleanupExpiredUploadsHandler(time.Now())
+			if err != nil {
+				config.Logger.Errorf("❌ Media upload cleanup failed: %v", err)
+			}
+		}
+	}()
+
+	config.Logger.Infof("✅ Media upload cleanup started (every %s)", cfg.MediaCleanupInterval)
+}
+
+func InitFiber(cn *container.AppContainer) {
 	app := config.InitFiberApp()
-	routes.Setup(app, container.NewAppContainer())
+	routes.Setup(app, cn)
 	config.Logger.Infof("✅ REST API started on port %s", config.AppConfig.AppPort)
 
 	if err := app.Listen(":" + config.AppConfig.AppPort); err != nil {
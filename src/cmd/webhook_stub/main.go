@@ -0,0 +1,72 @@
+// Command webhook_stub is a local receiver for testing outgoing webhooks. It
+// verifies the signature of every request with WEBHOOK_STUB_SECRET and logs
+// the outcome.
+//
+//	WEBHOOK_STUB_SECRET=<secret> go run ./cmd/webhook_stub
+//
+// Set WEBHOOK_STUB_FAIL=true to answer 500 and exercise the retry path.
+package main
+
+import (
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"jk-api/internal/webhook"
+)
+
+func main() {
+	addr := getEnv("WEBHOOK_STUB_ADDR", ":9090")
+	secret := os.Getenv("WEBHOOK_STUB_SECRET")
+	fail := os.Getenv("WEBHOOK_STUB_FAIL") == "true"
+
+	if secret == "" {
+		log.Println("⚠️ WEBHOOK_STUB_SECRET is empty, signatures will not be verified")
+	}
+
+	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		event := r.Header.Get(webhook.HeaderEvent)
+		delivery := r.Header.Get(webhook.HeaderDelivery)
+
+		if secret != "" {
+			err := webhook.Verify(
+				secret,
+				r.Header.Get(webhook.HeaderTimestamp),
+				r.Header.Get(webhook.HeaderSignature),
+				body,
+				5*time.Minute,
+			)
+			if err != nil {
+				log.Printf("❌ delivery %s (%s): %v", delivery, event, err)
+				http.Error(w, err.Error(), http.StatusUnauthorized)
+				return
+			}
+		}
+
+		log.Printf("📩 delivery %s (%s): %s", delivery, event, body)
+
+		if fail {
+			http.Error(w, "stub configured to fail", http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	log.Printf("✅ Webhook stub listening on %s", addr)
+	log.Fatal(http.ListenAndServe(addr, nil))
+}
+
+func getEnv(key, defaultValue string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return defaultValue
+}
@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/sirupsen/logrus"
@@ -27,11 +29,58 @@ type Config struct {
 
 	GCPBucketName string
 
+	MediaDriver          string
+	MediaLocalDir        string
+	MediaPublicURL       string
+	MediaUploadDir       string
+	MediaMaxSize         int64
+	MediaUploadTTL       time.Duration
+	MediaCleanupInterval time.Duration
+	MediaProtectedURL    string
+	MediaSigningKey      string
+	MediaURLTTL          time.Duration
+	GCSEndpoint          string
+	GCSPublicURL         string
+	GCSToken             string
+	GCSTimeout           time.Duration
+
 	Neo4jURI      string
 	Neo4jUser     string
 	Neo4jPassword string
 
-	OmniChannelURI string
+	OmniChannelURI   string
+	OmniChannelToken string
+
+	EventRelayInterval    time.Duration
+	EventRelayBatchSize   int
+	EventRelayMaxAttempts int
+
+	WebhookInterval    time.Duration
+	WebhookBatchSize   int
+	WebhookMaxAttempts int
+	WebhookTimeout     time.Duration
+
+	GuardianDigestInterval time.Duration
+
+	NotificationInAppDriver string
+	NotificationPushDriver  string
+	NotificationOmniDriver  string
+	NotificationTimeout     time.Duration
+	NotificationInterval    time.Duration
+	NotificationBatchSize   int
+	NotificationMaxAttempts int
+	FCMURL                  string
+	FCMToken                string
+
+	LLMDriver  string
+	LLMURL     string
+	LLMToken   string
+	LLMModel   string
+	LLMTimeout time.Duration
+
+	TutorMessagesPerHour int
+	TutorTokensPerDay    int
+	TutorMaxReplyTokens  int
 }
 
 func LoadConfig() error {
@@ -53,11 +102,58 @@ func LoadConfig() error {
 
 		GCPBucketName: getEnv("GCP_BUCKET_NAME", ""),
 
+		MediaDriver:          getEnv("MEDIA_DRIVER", "local"),
+		MediaLocalDir:        getEnv("MEDIA_LOCAL_DIR", "storage/media"),
+		MediaPublicURL:       getEnv("MEDIA_PUBLIC_URL", "/api/v1/media/files"),
+		MediaUploadDir:       getEnv("MEDIA_UPLOAD_DIR", "storage/uploads"),
+		MediaMaxSize:         int64(getEnvInt("MEDIA_MAX_SIZE", 50*1024*1024)),
+		MediaUploadTTL:       getEnvDuration("MEDIA_UPLOAD_TTL", 24*time.Hour),
+		MediaCleanupInterval: getEnvDuration("MEDIA_CLEANUP_INTERVAL", time.Hour),
+		MediaProtectedURL:    getEnv("MEDIA_PROTECTED_URL", "/api/v1/media/protected"),
+		MediaSigningKey:      getEnv("MEDIA_SIGNING_KEY", os.Getenv("JWT_SECRET")),
+		MediaURLTTL:          getEnvDuration("MEDIA_URL_TTL", 15*time.Minute),
+		GCSEndpoint:          getEnv("GCS_ENDPOINT", "https://storage.googleapis.com"),
+		GCSPublicURL:         getEnv("GCS_PUBLIC_URL", "https://storage.googleapis.com"),
+		GCSToken:             getEnv("GCS_TOKEN", ""),
+		GCSTimeout:           getEnvDuration("GCS_TIMEOUT", 60*time.Second),
+
 		Neo4jURI:      getEnv("NEO4J_URI", "bolt://localhost:7687"),
 		Neo4jUser:     getEnv("NEO4J_USER", "neo4j"),
 		Neo4jPassword: getEnv("NEO4J_PASSWORD", "password"),
 
-		OmniChannelURI: getEnv("OMNI_CHANNEL_URI", "http://localhost:3000"),
+		OmniChannelURI:   getEnv("OMNI_CHANNEL_URI", "http://localhost:3000"),
+		OmniChannelToken: getEnv("OMNI_CHANNEL_TOKEN", ""),
+
+		EventRelayInterval:    getEnvDuration("EVENT_RELAY_INTERVAL", 5*time.Second),
+		EventRelayBatchSize:   getEnvInt("EVENT_RELAY_BATCH_SIZE", 50),
+		EventRelayMaxAttempts: getEnvInt("EVENT_RELAY_MAX_ATTEMPTS", 10),
+
+		WebhookInterval:    getEnvDuration("WEBHOOK_INTERVAL", 5*time.Second),
+		WebhookBatchSize:   getEnvInt("WEBHOOK_BATCH_SIZE", 20),
+		WebhookMaxAttempts: getEnvInt("WEBHOOK_MAX_ATTEMPTS", 8),
+		WebhookTimeout:     getEnvDuration("WEBHOOK_TIMEOUT", 10*time.Second),
+
+		GuardianDigestInterval: getEnvDuration("GUARDIAN_DIGEST_INTERVAL", time.Hour),
+
+		NotificationInAppDriver: getEnv("NOTIFICATION_IN_APP_DRIVER", "database"),
+		NotificationPushDriver:  getEnv("NOTIFICATION_PUSH_DRIVER", "fake"),
+		NotificationOmniDriver:  getEnv("NOTIFICATION_OMNI_DRIVER", "fake"),
+		NotificationTimeout:     getEnvDuration("NOTIFICATION_TIMEOUT", 10*time.Second),
+		NotificationInterval:    getEnvDuration("NOTIFICATION_INTERVAL", 5*time.Second),
+		NotificationBatchSize:   getEnvInt("NOTIFICATION_BATCH_SIZE", 50),
+		NotificationMaxAttempts: getEnvInt("NOTIFICATION_MAX_ATTEMPTS", 5),
+		FCMURL:                  getEnv("FCM_URL", ""),
+		FCMToken:                getEnv("FCM_TOKEN", ""),
+
+		LLMDriver:  getEnv("LLM_DRIVER", "stub"),
+		LLMURL:     getEnv("LLM_URL", "https://api.openai.com/v1/chat/completions"),
+		LLMToken:   getEnv("LLM_TOKEN", ""),
+		LLMModel:   getEnv("LLM_MODEL", "gpt-4o-mini"),
+		LLMTimeout: getEnvDuration("LLM_TIMEOUT", 60*time.Second),
+
+		TutorMessagesPerHour: getEnvInt("TUTOR_MESSAGES_PER_HOUR", 30),
+		TutorTokensPerDay:    getEnvInt("TUTOR_TOKENS_PER_DAY", 50000),
+		TutorMaxReplyTokens:  getEnvInt("TUTOR_MAX_REPLY_TOKENS", 600),
 	}
 
 	return nil
@@ -74,6 +170,20 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	if value, err := strconv.Atoi(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	if value, err := time.ParseDuration(os.Getenv(key)); err == nil && value > 0 {
+		return value
+	}
+	return defaultValue
+}
+
 func getDsn() string {
 	host := AppConfig.PostgresHost
 	user := AppConfig.PostgresUser
@@ -0,0 +1,10 @@
+package constant
+
+// Due states of a lesson in a class course assignment, from a student's
+// point of view.
+const (
+	DueStatusUpcoming      = "upcoming"
+	DueStatusOverdue       = "overdue"
+	DueStatusCompleted     = "completed"
+	DueStatusCompletedLate = "completed_late"
+)
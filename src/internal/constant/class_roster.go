@@ -0,0 +1,21 @@
+package constant
+
+const (
+	JoinRequestPending  = "pending"
+	JoinRequestApproved = "approved"
+	JoinRequestRejected = "rejected"
+)
+
+const (
+	RosterJoinRequested   = "join_requested"
+	RosterJoined          = "joined"
+	RosterJoinRejected    = "join_rejected"
+	RosterMovedIn         = "moved_in"
+	RosterMovedOut        = "moved_out"
+	RosterStudentAdded    = "student_added"
+	RosterStudentRemoved  = "student_removed"
+	RosterTeacherAssigned = "teacher_assigned"
+	RosterTeacherRemoved  = "teacher_removed"
+	RosterCodeRegenerated = "code_regenerated"
+	RosterSettingsChanged = "join_settings_changed"
+)
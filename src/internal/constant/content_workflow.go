@@ -0,0 +1,40 @@
+package constant
+
+// Editorial states of a course, lesson or material. Only published content
+// is visible to students.
+const (
+	ContentDraft     = "draft"
+	ContentInReview  = "in_review"
+	ContentApproved  = "approved"
+	ContentPublished = "published"
+	ContentArchived  = "archived"
+)
+
+// Content types that go through the editorial workflow.
+const (
+	ContentTypeCourse   = "course"
+	ContentTypeLesson   = "lesson"
+	ContentTypeMaterial = "material"
+)
+
+// Workflow actions. Each one moves content into a single target state.
+const (
+	ContentActionSubmit         = "submit"
+	ContentActionApprove        = "approve"
+	ContentActionRequestChanges = "request_changes"
+	ContentActionPublish        = "publish"
+	ContentActionUnpublish      = "unpublish"
+	ContentActionArchive        = "archive"
+	ContentActionRestore        = "restore"
+	// ContentActionRollback is recorded when a course is restored from an
+	// older version. It is not one of the workflow routes.
+	ContentActionRollback = "rollback"
+)
+
+// Permissions guarding the workflow actions.
+const (
+	PermContentSubmit  = "content.submit"
+	PermContentReview  = "content.review"
+	PermContentPublish = "content.publish"
+	PermContentArchive = "content.archive"
+)
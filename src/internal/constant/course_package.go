@@ -0,0 +1,8 @@
+package constant
+
+// What a course import does when the school already has a course with the
+// same name.
+const (
+	CourseImportConflictRename = "rename"
+	CourseImportConflictFail   = "fail"
+)
@@ -12,3 +12,5 @@ const (
 	RetroTopic           = "sprint_retrospectives"
 	SprintDailyTopic     = "sprint_dailies"
 )
+
+const NotificationUserTopic = "notifications_user_%d"
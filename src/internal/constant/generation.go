@@ -0,0 +1,30 @@
+package constant
+
+// Kinds of content the generation pipeline writes.
+const (
+	GenerationKindMaterial      = "material"
+	GenerationKindCodeQuestion  = "code_question"
+	GenerationKindEssayQuestion = "essay_question"
+)
+
+// GenerationKinds lists every kind of content that can be generated.
+var GenerationKinds = []string{
+	GenerationKindMaterial,
+	GenerationKindCodeQuestion,
+	GenerationKindEssayQuestion,
+}
+
+// States of a generation run. A run that produced drafts is reviewed by a
+// teacher, who either accepts or discards them.
+const (
+	GenerationDrafted   = "drafted"
+	GenerationFailed    = "failed"
+	GenerationAccepted  = "accepted"
+	GenerationDiscarded = "discarded"
+)
+
+// Providers the generation pipeline can call.
+const (
+	LLMProviderStub   = "stub"
+	LLMProviderOpenAI = "openai"
+)
@@ -0,0 +1,8 @@
+package constant
+
+const (
+	GuardianLinkPending  = "pending"
+	GuardianLinkAccepted = "accepted"
+	GuardianLinkRejected = "rejected"
+	GuardianLinkRevoked  = "revoked"
+)
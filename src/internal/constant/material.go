@@ -0,0 +1,8 @@
+package constant
+
+// Formats a material document can be rendered to.
+const (
+	MaterialFormatHTML     = "html"
+	MaterialFormatText     = "text"
+	MaterialFormatMarkdown = "markdown"
+)
@@ -0,0 +1,45 @@
+package constant
+
+// Storage backends for uploaded media.
+const (
+	MediaDriverLocal = "local"
+	MediaDriverGCS   = "gcs"
+)
+
+// Scopes of signed media links. Enrolled links are re-checked against the
+// student's enrollment on every request; preview links are issued to staff.
+const (
+	MediaScopeEnrolled = "enrolled"
+	MediaScopePreview  = "preview"
+)
+
+// Content that can reference protected media.
+const (
+	MediaContentMaterial     = "material"
+	MediaContentCodeQuestion = "code_question"
+)
+
+// MediaTypes lists the accepted upload types, detected from the file content,
+// with the extension they are stored under.
+var MediaTypes = map[string]string{
+	"image/jpeg":      ".jpg",
+	"image/png":       ".png",
+	"image/gif":       ".gif",
+	"image/webp":      ".webp",
+	"video/mp4":       ".mp4",
+	"video/webm":      ".webm",
+	"application/pdf": ".pdf",
+}
+
+// MediaVariant is a thumbnail size generated for uploaded images. Images are
+// scaled so their longest side fits MaxSide.
+type MediaVariant struct {
+	Name    string
+	MaxSide int
+}
+
+var MediaVariants = []MediaVariant{
+	{Name: "thumb", MaxSide: 160},
+	{Name: "small", MaxSide: 480},
+	{Name: "medium", MaxSide: 1024},
+}
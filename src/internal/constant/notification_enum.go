@@ -5,4 +5,42 @@ const (
 	NotifWarning = "warning"
 	NotifImportant = "important"
 	NotifUrgent = "urgent"
-)
\ No newline at end of file
+)
+
+const (
+	NotificationChannelInApp = "in_app"
+	NotificationChannelPush  = "push"
+	NotificationChannelOmni  = "omni"
+)
+
+const (
+	NotificationDeliveryPending   = "pending"
+	NotificationDeliverySucceeded = "succeeded"
+	NotificationDeliveryDead      = "dead"
+)
+
+const (
+	NotificationEssayReviewed   = "essay_reviewed"
+	NotificationTeacherApproved = "teacher_approved"
+	NotificationCoursePublished = "course_published"
+	NotificationGuardianInvite  = "guardian_invite"
+	NotificationGuardianDigest  = "guardian_digest"
+)
+
+// NotificationTypes lists every notification a user can set preferences for.
+var NotificationTypes = []string{
+	NotificationEssayReviewed,
+	NotificationTeacherApproved,
+	NotificationCoursePublished,
+	NotificationGuardianInvite,
+	NotificationGuardianDigest,
+}
+
+func IsNotificationType(name string) bool {
+	for _, t := range NotificationTypes {
+		if t == name {
+			return true
+		}
+	}
+	return false
+}
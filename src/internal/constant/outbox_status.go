@@ -0,0 +1,7 @@
+package constant
+
+const (
+	OutboxPending    = "pending"
+	OutboxDispatched = "dispatched"
+	OutboxDead       = "dead"
+)
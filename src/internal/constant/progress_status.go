@@ -0,0 +1,32 @@
+package constant
+
+const (
+	ProgressNotStarted  = "not_started"
+	ProgressInProgress  = "in_progress"
+	ProgressCompleted   = "completed"
+	ProgressNeedsReview = "needs_review"
+)
+
+// ProgressTransitions lists the statuses a student may move each progress
+// status to. Resetting back to not_started is handled separately and is
+// teacher-only, and so is leaving needs_review: a teacher approves it.
+var ProgressTransitions = map[string][]string{
+	ProgressNotStarted:  {ProgressInProgress, ProgressCompleted, ProgressNeedsReview},
+	ProgressInProgress:  {ProgressCompleted, ProgressNeedsReview},
+	ProgressNeedsReview: {},
+	ProgressCompleted:   {ProgressNeedsReview},
+}
+
+func IsValidProgressStatus(status string) bool {
+	_, ok := ProgressTransitions[status]
+	return ok
+}
+
+func CanTransitionProgress(from, to string) bool {
+	for _, next := range ProgressTransitions[from] {
+		if next == to {
+			return true
+		}
+	}
+	return false
+}
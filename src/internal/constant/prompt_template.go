@@ -0,0 +1,41 @@
+package constant
+
+// Types of prompt template variables. The generation pipeline fills in the
+// variables describing where a run happens; the others are supplied by the
+// caller.
+const (
+	PromptVarCourse        = "course"
+	PromptVarLevel         = "level"
+	PromptVarLesson        = "lesson"
+	PromptVarSubLesson     = "sub_lesson"
+	PromptVarTopic         = "topic"
+	PromptVarCount         = "count"
+	PromptVarContext       = "context"
+	PromptVarCodeQuestion  = "code_question"
+	PromptVarStudentAnswer = "student_answer"
+	PromptVarText          = "text"
+	PromptVarNumber        = "number"
+)
+
+// PromptVarTypes lists every variable type a template can declare.
+var PromptVarTypes = []string{
+	PromptVarCourse,
+	PromptVarLevel,
+	PromptVarLesson,
+	PromptVarSubLesson,
+	PromptVarTopic,
+	PromptVarCount,
+	PromptVarContext,
+	PromptVarCodeQuestion,
+	PromptVarStudentAnswer,
+	PromptVarText,
+	PromptVarNumber,
+}
+
+// PromptInputVarTypes are the variable types whose values come from the
+// caller rather than the pipeline.
+var PromptInputVarTypes = []string{
+	PromptVarStudentAnswer,
+	PromptVarText,
+	PromptVarNumber,
+}
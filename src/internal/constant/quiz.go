@@ -0,0 +1,45 @@
+package constant
+
+// Types of quiz items. True/false items get the options "Benar" and "Salah"
+// and are graded like single choice items.
+const (
+	QuizItemSingleChoice   = "single_choice"
+	QuizItemMultipleChoice = "multiple_choice"
+	QuizItemTrueFalse      = "true_false"
+	QuizItemNumeric        = "numeric"
+	QuizItemShortAnswer    = "short_answer"
+)
+
+// QuizItemTypes lists every type of quiz item.
+var QuizItemTypes = []string{
+	QuizItemSingleChoice,
+	QuizItemMultipleChoice,
+	QuizItemTrueFalse,
+	QuizItemNumeric,
+	QuizItemShortAnswer,
+}
+
+// Which attempts of a student count towards their course score. "latest"
+// is the older name of "last" and is still accepted.
+const (
+	QuizScoreHighest = "highest"
+	QuizScoreFirst   = "first"
+	QuizScoreLast    = "last"
+	QuizScoreLatest  = "latest"
+	QuizScoreAverage = "average"
+)
+
+// QuizScorePolicies lists every score policy a quiz may use.
+var QuizScorePolicies = []string{
+	QuizScoreHighest,
+	QuizScoreFirst,
+	QuizScoreLast,
+	QuizScoreLatest,
+	QuizScoreAverage,
+}
+
+// States of a quiz attempt.
+const (
+	QuizAttemptInProgress = "in_progress"
+	QuizAttemptSubmitted  = "submitted"
+)
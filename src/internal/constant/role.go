@@ -0,0 +1,18 @@
+package constant
+
+// Role IDs as seeded in the roles table.
+const (
+	RoleIDSuper   int64 = 1
+	RoleIDTeacher int64 = 2
+	RoleIDStudent int64 = 3
+	// RoleIDGuardian is seeded after platform_admin, which takes id 4.
+	RoleIDGuardian int64 = 5
+)
+
+// RolePlatformAdmin works across every school. All other roles are scoped to
+// the school on their token.
+const RolePlatformAdmin = "platform_admin"
+
+// RoleGuardian is a parent or guardian with read-only access to the students
+// they are linked to.
+const RoleGuardian = "guardian"
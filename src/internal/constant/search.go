@@ -0,0 +1,19 @@
+package constant
+
+// Kinds of content returned by search.
+const (
+	SearchTypeCourse       = "course"
+	SearchTypeLesson       = "lesson"
+	SearchTypeSubLesson    = "sub_lesson"
+	SearchTypeMaterial     = "material"
+	SearchTypeCodeQuestion = "code_question"
+)
+
+// SearchTypes lists every kind of content search can return.
+var SearchTypes = []string{
+	SearchTypeCourse,
+	SearchTypeLesson,
+	SearchTypeSubLesson,
+	SearchTypeMaterial,
+	SearchTypeCodeQuestion,
+}
@@ -0,0 +1,7 @@
+package constant
+
+// Authors of a message in a tutor conversation.
+const (
+	TutorRoleStudent = "user"
+	TutorRoleTutor   = "assistant"
+)
@@ -0,0 +1,31 @@
+package constant
+
+// Columns the user import understands. Headers in the uploaded file are
+// matched against these names and their aliases unless a mapping is given.
+const (
+	UserImportFieldName      = "name"
+	UserImportFieldEmail     = "email"
+	UserImportFieldRole      = "role"
+	UserImportFieldClassCode = "class_code"
+	UserImportFieldPassword  = "password"
+)
+
+const (
+	UserImportActionCreate = "create"
+	UserImportActionUpdate = "update"
+)
+
+// UserImportColumnAliases maps each import field to the header spellings we
+// accept, already normalised to lower case with single spaces.
+var UserImportColumnAliases = map[string][]string{
+	UserImportFieldName:      {"name", "nama", "nama lengkap", "full name"},
+	UserImportFieldEmail:     {"email", "e-mail", "alamat email"},
+	UserImportFieldRole:      {"role", "peran"},
+	UserImportFieldClassCode: {"class code", "kode kelas", "class", "kelas"},
+	UserImportFieldPassword:  {"password", "kata sandi"},
+}
+
+func IsUserImportField(field string) bool {
+	_, ok := UserImportColumnAliases[field]
+	return ok
+}
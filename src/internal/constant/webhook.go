@@ -0,0 +1,29 @@
+package constant
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliverySucceeded = "succeeded"
+	WebhookDeliveryDead      = "dead"
+)
+
+// WebhookPingEvent is sent by the subscription test endpoint only.
+const WebhookPingEvent = "webhook.ping"
+
+// WebhookEvents lists the domain events that can be delivered to webhook
+// subscribers. A subscription with no event filter receives all of them.
+var WebhookEvents = []string{
+	"course.enrolled",
+	"sub_lesson.completed",
+	"course.completed",
+	"badge.earned",
+	"quiz.submitted",
+}
+
+func IsWebhookEvent(name string) bool {
+	for _, event := range WebhookEvents {
+		if event == name {
+			return true
+		}
+	}
+	return false
+}
@@ -23,6 +23,25 @@ type AppContainer struct {
 	TCodeAnswerHandler *handlers.TCodeAnswerHandler
 	TWonderingScoreHandler *handlers.TWonderingScoreHandler
 	TEssayAnswerHandler *handlers.TEssayAnswerHandler
+	TEventOutboxHandler *handlers.TEventOutboxHandler
+	MWebhookSubscriptionHandler *handlers.MWebhookSubscriptionHandler
+	TWebhookDeliveryHandler *handlers.TWebhookDeliveryHandler
+	NotificationHandler *handlers.NotificationHandler
+	TClassRosterHandler *handlers.TClassRosterHandler
+	UserImportHandler *handlers.UserImportHandler
+	TClassCourseHandler *handlers.TClassCourseHandler
+	MSchoolHandler *handlers.MSchoolHandler
+	DepartmentHandler *handlers.DepartmentHandler
+	TGuardianLinkHandler *handlers.TGuardianLinkHandler
+	ContentWorkflowHandler *handlers.ContentWorkflowHandler
+	TCourseVersionHandler *handlers.TCourseVersionHandler
+	CoursePackageHandler *handlers.CoursePackageHandler
+	MediaHandler *handlers.MediaHandler
+	SearchHandler *handlers.SearchHandler
+	TGenerationHistoryHandler *handlers.TGenerationHistoryHandler
+	MPromptTemplateHandler *handlers.MPromptTemplateHandler
+	TTutorConversationHandler *handlers.TTutorConversationHandler
+	MQuizHandler *handlers.MQuizHandler
 }
 
 func NewAppContainer() *AppContainer {
@@ -45,5 +64,24 @@ func NewAppContainer() *AppContainer {
 		TCodeAnswerHandler: InitTCodeAnswerContainer(),
 		TWonderingScoreHandler: InitTWonderingScoreContainer(),
 		TEssayAnswerHandler: InitTEssayAnswerContainer(),
+		TEventOutboxHandler: InitTEventOutboxContainer(),
+		MWebhookSubscriptionHandler: InitMWebhookSubscriptionContainer(),
+		TWebhookDeliveryHandler: InitTWebhookDeliveryContainer(),
+		NotificationHandler: InitNotificationContainer(),
+		TClassRosterHandler: InitTClassRosterContainer(),
+		UserImportHandler: InitUserImportContainer(),
+		TClassCourseHandler: InitTClassCourseContainer(),
+		MSchoolHandler: InitMSchoolContainer(),
+		DepartmentHandler: InitDepartmentContainer(),
+		TGuardianLinkHandler: InitTGuardianLinkContainer(),
+		ContentWorkflowHandler: InitContentWorkflowContainer(),
+		TCourseVersionHandler: InitTCourseVersionContainer(),
+		CoursePackageHandler: InitCoursePackageContainer(),
+		MediaHandler: InitMediaContainer(),
+		SearchHandler: InitSearchContainer(),
+		TGenerationHistoryHandler: InitTGenerationHistoryContainer(),
+		MPromptTemplateHandler: InitMPromptTemplateContainer(),
+		TTutorConversationHandler: InitTTutorConversationContainer(),
+		MQuizHandler: InitMQuizContainer(),
 	}
 }
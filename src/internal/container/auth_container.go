@@ -9,6 +9,7 @@ import (
 func InitAuthContainer() *handlers.AuthHandler {
 	userRepo := sql.NewUserRepository()
 	refreshRepo := sql.NewRefreshTokenRepository()
-	service := services.NewAuthService(userRepo, refreshRepo)
+	schoolRepo := sql.NewMSchoolRepository()
+	service := services.NewAuthService(userRepo, refreshRepo, schoolRepo)
 	return handlers.NewAuthHandler(service)
 }
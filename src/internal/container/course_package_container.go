@@ -0,0 +1,12 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitCoursePackageContainer() *handlers.CoursePackageHandler {
+	service := services.NewCoursePackageService(sql.NewMCourseRepository(), sql.NewMLevelRepository())
+	return handlers.NewCoursePackageHandler(service)
+}
@@ -0,0 +1,13 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitDepartmentContainer() *handlers.DepartmentHandler {
+	repo := sql.NewDepartmentRepository()
+	service := services.NewDepartmentService(repo)
+	return handlers.NewDepartmentHandler(service)
+}
@@ -10,6 +10,6 @@ import (
 
 func InitMBadgeSettingsContainer() *handlers.MBadgeSettingsHandler {
 	repo := sql.NewMBadgeSettingsRepository()
-	service := services.NewMBadgeSettingsService(repo)
+	service := services.NewMBadgeSettingsService(repo, sql.NewMMediaRepository())
 	return handlers.NewMBadgeSettingsHandler(service)
 }
@@ -10,6 +10,6 @@ import (
 
 func InitMClassContainer() *handlers.MClassHandler {
 	repo := sql.NewMClassRepository()
-	service := services.NewMClassService(repo)
+	service := services.NewMClassService(repo, sql.NewTClassRosterRepository(), sql.NewDepartmentRepository())
 	return handlers.NewMClassHandler(service)
 }
@@ -10,6 +10,6 @@ import (
 
 func InitMLessonContainer() *handlers.MLessonHandler {
 	repo := sql.NewMLessonRepository()
-	service := services.NewMLessonService(repo)
+	service := services.NewMLessonService(repo, sql.NewMMediaRepository())
 	return handlers.NewMLessonHandler(service)
 }
@@ -0,0 +1,13 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitMPromptTemplateContainer() *handlers.MPromptTemplateHandler {
+	repo := sql.NewMPromptTemplateRepository()
+	service := services.NewMPromptTemplateService(repo)
+	return handlers.NewMPromptTemplateHandler(service)
+}
@@ -0,0 +1,25 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/signedurl"
+	"jk-api/internal/storage"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitMediaContainer() *handlers.MediaHandler {
+	cfg := config.AppConfig
+
+	var store storage.Storage = storage.NewLocalStorage(cfg.MediaLocalDir, cfg.MediaPublicURL)
+	if cfg.MediaDriver == constant.MediaDriverGCS {
+		store = storage.NewGCSStorage(cfg.GCSEndpoint, cfg.GCSPublicURL, cfg.GCPBucketName, cfg.GCSToken, cfg.GCSTimeout)
+	}
+
+	signer := signedurl.NewSigner(cfg.MediaSigningKey, cfg.MediaProtectedURL, cfg.MediaURLTTL)
+
+	service := services.NewMediaService(sql.NewMMediaRepository(), store, signer, cfg.MediaUploadDir, cfg.MediaMaxSize, cfg.MediaUploadTTL)
+	return handlers.NewMediaHandler(service)
+}
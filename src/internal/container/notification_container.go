@@ -0,0 +1,40 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/events"
+	"jk-api/internal/notification"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitNotificationContainer() *handlers.NotificationHandler {
+	cfg := config.AppConfig
+	repo := sql.NewNotificationRepository()
+
+	var inApp notification.Channel = notification.NewInAppChannel(repo)
+	if cfg.NotificationInAppDriver == "fake" {
+		inApp = notification.NewFake(constant.NotificationChannelInApp)
+	}
+
+	var push notification.Channel = notification.NewFake(constant.NotificationChannelPush)
+	if cfg.NotificationPushDriver == "fcm" {
+		push = notification.NewPushChannel(cfg.FCMURL, cfg.FCMToken, cfg.NotificationTimeout)
+	}
+
+	var omni notification.Channel = notification.NewFake(constant.NotificationChannelOmni)
+	if cfg.NotificationOmniDriver == "http" {
+		omni = notification.NewOmniChannel(cfg.OmniChannelURI, cfg.OmniChannelToken, cfg.NotificationTimeout)
+	}
+
+	service := services.NewNotificationService(repo, inApp, push, omni)
+	events.SubscribeAsync(events.EssayApprovedEvent, service.OnNotificationEvent)
+	events.SubscribeAsync(events.TeacherApprovedEvent, service.OnNotificationEvent)
+	events.SubscribeAsync(events.CoursePublishedEvent, service.OnNotificationEvent)
+	events.SubscribeAsync(events.GuardianInvitedEvent, service.OnNotificationEvent)
+	events.SubscribeAsync(events.GuardianLinkAcceptedEvent, service.OnNotificationEvent)
+	events.SubscribeAsync(events.GuardianWeeklyDigestEvent, service.OnNotificationEvent)
+	return handlers.NewNotificationHandler(service)
+}
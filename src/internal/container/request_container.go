@@ -0,0 +1,37 @@
+package container
+
+import (
+	"context"
+	"reflect"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Controller builds a fiber handler on top of the container's handlers.
+type Controller func(cn *AppContainer) fiber.Handler
+
+// WithContext returns a copy of the container whose handlers run their
+// queries under ctx, and with it under the tenant scope ctx carries.
+func (c *AppContainer) WithContext(ctx context.Context) *AppContainer {
+	bound := *c
+	fields := reflect.ValueOf(&bound).Elem()
+	for i := 0; i < fields.NumField(); i++ {
+		field := fields.Field(i)
+		if field.IsNil() {
+			continue
+		}
+		withContext := field.MethodByName("WithContext")
+		field.Set(withContext.Call([]reflect.Value{reflect.ValueOf(ctx)})[0])
+	}
+	return &bound
+}
+
+// Bind builds controller for every request against the container bound to
+// the request's user context. The JWT middleware puts the caller's tenant
+// scope there, so routes registered through Bind are scoped without the
+// controller having to remember it.
+func (c *AppContainer) Bind(controller Controller) fiber.Handler {
+	return func(ctx *fiber.Ctx) error {
+		return controller(c.WithContext(ctx.UserContext()))(ctx)
+	}
+}
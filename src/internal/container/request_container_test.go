@@ -0,0 +1,27 @@
+package container
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+// Bind rebinds every handler through its WithContext method, so a handler
+// without one would be served unscoped or panic.
+func TestEveryHandlerBindsToContext(t *testing.T) {
+	contextType := reflect.TypeOf((*context.Context)(nil)).Elem()
+
+	containerType := reflect.TypeOf(AppContainer{})
+	for i := 0; i < containerType.NumField(); i++ {
+		field := containerType.Field(i)
+		method, ok := field.Type.MethodByName("WithContext")
+		if !ok {
+			t.Errorf("%s has no WithContext method", field.Name)
+			continue
+		}
+		if method.Type.NumIn() != 2 || method.Type.In(1) != contextType ||
+			method.Type.NumOut() != 1 || method.Type.Out(0) != field.Type {
+			t.Errorf("%s.WithContext is %s, want func(context.Context) %s", field.Name, method.Type, field.Type)
+		}
+	}
+}
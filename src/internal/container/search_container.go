@@ -0,0 +1,13 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitSearchContainer() *handlers.SearchHandler {
+	repo := sql.NewSearchRepository()
+	service := services.NewSearchService(repo)
+	return handlers.NewSearchHandler(service)
+}
@@ -0,0 +1,16 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/events"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitTClassCourseContainer() *handlers.TClassCourseHandler {
+	repo := sql.NewTClassCourseRepository()
+	service := services.NewTClassCourseService(repo, sql.NewTClassRosterRepository())
+	events.Subscribe(events.ClassStudentJoinedEvent, service.OnClassStudentJoined)
+	events.Subscribe(events.SubLessonCompletedEvent, service.OnSubLessonCompleted)
+	return handlers.NewTClassCourseHandler(service)
+}
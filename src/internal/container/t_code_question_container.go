@@ -10,6 +10,6 @@ import (
 
 func InitTCodeQuestionContainer() *handlers.TCodeQuestionHandler {
 	repo := sql.NewTCodeQuestionRepository()
-	service := services.NewTCodeQuestionService(repo)
+	service := services.NewTCodeQuestionService(repo, sql.NewMMediaRepository())
 	return handlers.NewTCodeQuestionHandler(service)
 }
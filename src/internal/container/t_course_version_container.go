@@ -0,0 +1,16 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/events"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitTCourseVersionContainer() *handlers.TCourseVersionHandler {
+	repo := sql.NewTCourseVersionRepository()
+	service := services.NewTCourseVersionService(repo)
+	events.Subscribe(events.CoursePublishedEvent, service.OnCoursePublished)
+	events.Subscribe(events.CourseEnrolledEvent, service.OnCourseEnrolled)
+	return handlers.NewTCourseVersionHandler(service)
+}
@@ -0,0 +1,23 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/generation"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitTGenerationHistoryContainer() *handlers.TGenerationHistoryHandler {
+	cfg := config.AppConfig
+	repo := sql.NewTGenerationHistoryRepository()
+
+	var provider generation.LLMProvider = generation.NewStub()
+	if cfg.LLMDriver == constant.LLMProviderOpenAI {
+		provider = generation.NewOpenAI(cfg.LLMURL, cfg.LLMToken, cfg.LLMModel, cfg.LLMTimeout)
+	}
+
+	service := services.NewTGenerationHistoryService(repo, sql.NewMPromptTemplateRepository(), provider)
+	return handlers.NewTGenerationHistoryHandler(service)
+}
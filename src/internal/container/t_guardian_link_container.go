@@ -0,0 +1,14 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitTGuardianLinkContainer() *handlers.TGuardianLinkHandler {
+	repo := sql.NewTGuardianLinkRepository()
+	dueItems := services.NewTClassCourseService(sql.NewTClassCourseRepository(), sql.NewTClassRosterRepository())
+	service := services.NewTGuardianLinkService(repo, dueItems)
+	return handlers.NewTGuardianLinkHandler(service)
+}
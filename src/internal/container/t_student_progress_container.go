@@ -4,6 +4,7 @@ package container
 
 import (
 	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/events"
 	"jk-api/pkg/repository/query/sql"
 	"jk-api/pkg/services/v1"
 )
@@ -11,5 +12,8 @@ import (
 func InitTStudentProgressContainer() *handlers.TStudentProgressHandler {
 	repo := sql.NewTStudentProgressRepository()
 	service := services.NewTStudentProgressService(repo)
+	events.Subscribe(events.CourseStructureChangedEvent, service.OnCourseStructureChanged)
+	events.Subscribe(events.EnrollmentVersionChangedEvent, service.OnEnrollmentVersionChanged)
+	events.Subscribe(events.QuizSubmittedEvent, service.OnQuizSubmitted)
 	return handlers.NewTStudentProgressHandler(service)
 }
@@ -0,0 +1,23 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/tutor"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitTTutorConversationContainer() *handlers.TTutorConversationHandler {
+	cfg := config.AppConfig
+	repo := sql.NewTTutorConversationRepository()
+
+	var provider tutor.ChatProvider = tutor.NewStub()
+	if cfg.LLMDriver == constant.LLMProviderOpenAI {
+		provider = tutor.NewOpenAI(cfg.LLMURL, cfg.LLMToken, cfg.LLMModel, cfg.LLMTimeout)
+	}
+
+	service := services.NewTTutorConversationService(repo, provider)
+	return handlers.NewTTutorConversationHandler(service)
+}
@@ -0,0 +1,22 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/events"
+	"jk-api/internal/webhook"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitTWebhookDeliveryContainer() *handlers.TWebhookDeliveryHandler {
+	repo := sql.NewTWebhookDeliveryRepository()
+	subscriptionRepo := sql.NewMWebhookSubscriptionRepository()
+	sender := webhook.NewHTTPSender(config.AppConfig.WebhookTimeout)
+	service := services.NewTWebhookDeliveryService(repo, subscriptionRepo, sender)
+	for _, name := range constant.WebhookEvents {
+		events.SubscribeAsync(name, service.OnWebhookEvent)
+	}
+	return handlers.NewTWebhookDeliveryHandler(service)
+}
@@ -10,6 +10,6 @@ import (
 
 func InitUserContainer() *handlers.UserHandler {
 	repo := sql.NewUserRepository()
-	service := services.NewUserService(repo)
+	service := services.NewUserService(repo, sql.NewDepartmentRepository())
 	return handlers.NewUserHandler(service)
 }
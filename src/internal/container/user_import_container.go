@@ -0,0 +1,12 @@
+package container
+
+import (
+	"jk-api/api/http/controllers/v1/handlers"
+	"jk-api/pkg/repository/query/sql"
+	"jk-api/pkg/services/v1"
+)
+
+func InitUserImportContainer() *handlers.UserImportHandler {
+	service := services.NewUserImportService(sql.NewUserRepository(), sql.NewTClassRosterRepository())
+	return handlers.NewUserImportHandler(service)
+}
@@ -0,0 +1,204 @@
+// Package coursepkg reads and writes portable course packages: a zip holding
+// a JSON manifest, one Tiptap document per material and one question bank per
+// sub-lesson. Media is referenced by URL and never embedded. Packages written
+// before materials were documents hold Markdown or HTML files instead.
+package coursepkg
+
+import (
+	"fmt"
+	"jk-api/internal/richtext"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// Format identifies a course package manifest.
+	Format = "jk-course"
+	// FormatVersion is the newest manifest version this build understands.
+	FormatVersion = 1
+
+	ManifestFile = "manifest.json"
+)
+
+var keyPattern = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// Manifest describes a course tree. Keys identify items inside the package
+// only; importers never reuse them as database ids.
+type Manifest struct {
+	Format     string    `json:"format"`
+	Version    int       `json:"version"`
+	ExportedAt time.Time `json:"exported_at"`
+	Course     Course    `json:"course"`
+	// Media lists every image and video URL the content points to.
+	Media []string `json:"media"`
+}
+
+type Course struct {
+	Key          string   `json:"key"`
+	CourseName   string   `json:"course_name"`
+	Description  string   `json:"description"`
+	ImgThumbnail string   `json:"img_thumbnail,omitempty"`
+	Status       string   `json:"status"`
+	Lessons      []Lesson `json:"lessons"`
+}
+
+type Lesson struct {
+	Key          string `json:"key"`
+	Title        string `json:"title"`
+	Description  string `json:"description"`
+	Position     int    `json:"position"`
+	ImgThumbnail string `json:"img_thumbnail,omitempty"`
+	// Level is the level name; ids differ between installations.
+	Level      string      `json:"level"`
+	Status     string      `json:"status"`
+	SubLessons []SubLesson `json:"sub_lessons"`
+}
+
+type SubLesson struct {
+	Key           string     `json:"key"`
+	Title         string     `json:"title"`
+	OrderPosition int        `json:"order_position"`
+	Materials     []Material `json:"materials"`
+	// QuestionBank is the package path of the sub-lesson's questions.
+	QuestionBank string `json:"question_bank,omitempty"`
+
+	Questions []CodeQuestion `json:"-"`
+}
+
+type Material struct {
+	Key             string `json:"key"`
+	Title           string `json:"title"`
+	File            string `json:"file"`
+	URLVideo        string `json:"url_video,omitempty"`
+	ContentPosition int    `json:"content_position"`
+	PromptLLM       string `json:"prompt_llm,omitempty"`
+	Status          string `json:"status"`
+
+	Body string `json:"-"`
+}
+
+// QuestionBank is the content of a question bank file.
+type QuestionBank struct {
+	Questions []CodeQuestion `json:"questions"`
+}
+
+type CodeQuestion struct {
+	Key            string          `json:"key"`
+	CodeQuestion   string          `json:"code_question"`
+	Image          string          `json:"image,omitempty"`
+	Score          int             `json:"score"`
+	Hint           string          `json:"hint,omitempty"`
+	EssayQuestions []EssayQuestion `json:"essay_questions"`
+}
+
+type EssayQuestion struct {
+	Key           string `json:"key"`
+	EssayQuestion string `json:"essay_question"`
+	Answer        string `json:"answer"`
+	Answer2       string `json:"answer_2,omitempty"`
+	Answer3       string `json:"answer_3,omitempty"`
+	Answer4       string `json:"answer_4,omitempty"`
+}
+
+// Validate reports everything wrong with the manifest that can be checked
+// without a database. An empty result means the tree can be imported.
+func (m *Manifest) Validate() []string {
+	var problems []string
+	addProblem := func(format string, args ...interface{}) {
+		problems = append(problems, fmt.Sprintf(format, args...))
+	}
+
+	seen := make(map[string]string)
+	checkKey := func(kind string, key string) {
+		if !keyPattern.MatchString(key) {
+			addProblem("%s: key %q tidak valid", kind, key)
+			return
+		}
+		if other, ok := seen[key]; ok {
+			addProblem("%s: key %q sudah dipakai oleh %s", kind, key, other)
+			return
+		}
+		seen[key] = kind
+	}
+	checkText := func(kind string, key string, value string, max int) {
+		switch {
+		case value == "":
+			addProblem("%s %s: judul wajib diisi", kind, key)
+		case len(value) > max:
+			addProblem("%s %s: judul maksimal %d karakter", kind, key, max)
+		}
+	}
+
+	course := m.Course
+	checkKey("course", course.Key)
+	checkText("course", course.Key, course.CourseName, 100)
+
+	for _, lesson := range course.Lessons {
+		checkKey("lesson", lesson.Key)
+		checkText("lesson", lesson.Key, lesson.Title, 100)
+		if lesson.Level == "" {
+			addProblem("lesson %s: level wajib diisi", lesson.Key)
+		}
+
+		for _, subLesson := range lesson.SubLessons {
+			checkKey("sub_lesson", subLesson.Key)
+			checkText("sub_lesson", subLesson.Key, subLesson.Title, 100)
+
+			for _, material := range subLesson.Materials {
+				checkKey("material", material.Key)
+				checkText("material", material.Key, material.Title, 150)
+				if strings.HasSuffix(material.File, ".json") {
+					if _, err := richtext.Parse([]byte(material.Body)); err != nil {
+						addProblem("material %s: %v", material.Key, err)
+					}
+				}
+			}
+			for _, question := range subLesson.Questions {
+				checkKey("code_question", question.Key)
+				if question.CodeQuestion == "" {
+					addProblem("code_question %s: soal wajib diisi", question.Key)
+				}
+				for _, essay := range question.EssayQuestions {
+					checkKey("essay_question", essay.Key)
+					if essay.EssayQuestion == "" {
+						addProblem("essay_question %s: soal wajib diisi", essay.Key)
+					}
+				}
+			}
+		}
+	}
+
+	return problems
+}
+
+// collectMedia lists the distinct media URLs referenced by the tree.
+func (m *Manifest) collectMedia() []string {
+	seen := make(map[string]bool)
+	add := func(url string) {
+		if url != "" {
+			seen[url] = true
+		}
+	}
+
+	add(m.Course.ImgThumbnail)
+	for _, lesson := range m.Course.Lessons {
+		add(lesson.ImgThumbnail)
+		for _, subLesson := range lesson.SubLessons {
+			for _, material := range subLesson.Materials {
+				add(material.URLVideo)
+			}
+			for _, question := range subLesson.Questions {
+				add(question.Image)
+			}
+		}
+	}
+
+	media := make([]string, 0, len(seen))
+	for url := range seen {
+		media = append(media, url)
+	}
+	sort.Strings(media)
+	return media
+}
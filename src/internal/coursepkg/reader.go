@@ -0,0 +1,102 @@
+package coursepkg
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// maxUnpackedSize caps how much a package may expand to so a crafted zip
+// can't exhaust memory.
+const maxUnpackedSize = 64 << 20
+
+// Read decodes a package and loads the material bodies and question banks
+// its manifest points to.
+func Read(data []byte) (*Manifest, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("paket bukan file zip yang valid")
+	}
+
+	var total uint64
+	entries := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		total += f.UncompressedSize64
+		entries[f.Name] = f
+	}
+	if total > maxUnpackedSize {
+		return nil, fmt.Errorf("isi paket melebihi %d MB", maxUnpackedSize>>20)
+	}
+
+	raw, err := readEntry(entries, ManifestFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, fmt.Errorf("%s tidak valid: %w", ManifestFile, err)
+	}
+	if m.Format != Format {
+		return nil, fmt.Errorf("format paket %q tidak dikenal", m.Format)
+	}
+	if m.Version < 1 || m.Version > FormatVersion {
+		return nil, fmt.Errorf("versi paket %d tidak didukung", m.Version)
+	}
+
+	for i := range m.Course.Lessons {
+		lesson := &m.Course.Lessons[i]
+		for j := range lesson.SubLessons {
+			subLesson := &lesson.SubLessons[j]
+
+			for k := range subLesson.Materials {
+				material := &subLesson.Materials[k]
+				body, err := readEntry(entries, material.File)
+				if err != nil {
+					return nil, err
+				}
+				material.Body = string(body)
+			}
+
+			if subLesson.QuestionBank == "" {
+				continue
+			}
+			raw, err := readEntry(entries, subLesson.QuestionBank)
+			if err != nil {
+				return nil, err
+			}
+			var bank QuestionBank
+			if err := json.Unmarshal(raw, &bank); err != nil {
+				return nil, fmt.Errorf("%s tidak valid: %w", subLesson.QuestionBank, err)
+			}
+			subLesson.Questions = bank.Questions
+		}
+	}
+
+	return &m, nil
+}
+
+func readEntry(entries map[string]*zip.File, name string) ([]byte, error) {
+	f, ok := entries[name]
+	if !ok {
+		return nil, fmt.Errorf("file %q tidak ditemukan di paket", name)
+	}
+
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+
+	// The declared size can lie; never read past the overall cap.
+	data, err := io.ReadAll(io.LimitReader(rc, maxUnpackedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("file %q rusak: %w", name, err)
+	}
+	if len(data) > maxUnpackedSize {
+		return nil, fmt.Errorf("isi paket melebihi %d MB", maxUnpackedSize>>20)
+	}
+	return data, nil
+}
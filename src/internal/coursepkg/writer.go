@@ -0,0 +1,87 @@
+package coursepkg
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"strings"
+	"time"
+)
+
+// Write encodes m as a package. Material bodies and question banks are
+// written as separate files and the manifest is updated to point at them.
+func Write(w io.Writer, m *Manifest) error {
+	m.Format = Format
+	m.Version = FormatVersion
+	if m.ExportedAt.IsZero() {
+		m.ExportedAt = time.Now()
+	}
+	m.Media = m.collectMedia()
+
+	files := make(map[string][]byte)
+	var order []string
+	addFile := func(name string, data []byte) {
+		files[name] = data
+		order = append(order, name)
+	}
+
+	for i := range m.Course.Lessons {
+		lesson := &m.Course.Lessons[i]
+		for j := range lesson.SubLessons {
+			subLesson := &lesson.SubLessons[j]
+
+			for k := range subLesson.Materials {
+				material := &subLesson.Materials[k]
+				material.File = "materials/" + material.Key + materialExt(material.Body)
+				addFile(material.File, []byte(material.Body))
+			}
+
+			subLesson.QuestionBank = ""
+			if len(subLesson.Questions) > 0 {
+				data, err := json.MarshalIndent(QuestionBank{Questions: subLesson.Questions}, "", "  ")
+				if err != nil {
+					return err
+				}
+				subLesson.QuestionBank = "questions/" + subLesson.Key + ".json"
+				addFile(subLesson.QuestionBank, data)
+			}
+		}
+	}
+
+	manifest, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	zw := zip.NewWriter(w)
+	if err := writeEntry(zw, ManifestFile, manifest); err != nil {
+		return err
+	}
+	for _, name := range order {
+		if err := writeEntry(zw, name, files[name]); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+func writeEntry(zw *zip.Writer, name string, data []byte) error {
+	f, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(data)
+	return err
+}
+
+// materialExt names Tiptap documents .json. Bodies from before documents
+// keep HTML recognisable and are otherwise stored as Markdown.
+func materialExt(body string) string {
+	if strings.HasPrefix(strings.TrimSpace(body), "{") {
+		return ".json"
+	}
+	if strings.HasPrefix(strings.TrimSpace(body), "<") {
+		return ".html"
+	}
+	return ".md"
+}
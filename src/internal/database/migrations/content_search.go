@@ -0,0 +1,103 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// ContentSearch adds full-text search over course content. Every searchable
+// table gets a generated search_vector column with a GIN index, so the
+// vectors stay in step with the rows without any application code.
+//
+// Content is written in Indonesian, English or both, so text is indexed with
+// both stemmers: jk_search_vector concatenates the two vectors and
+// jk_search_query ORs the two parses of a query. Indonesian stemming needs
+// Postgres 13 or later; older servers fall back to the simple configuration.
+// It must run after AutoMigrate and is safe to re-run.
+func ContentSearch(db *gorm.DB) error {
+	log.Println("🔄 Running Content Search Migration...")
+
+	var configExists bool
+	checkConfigSQL := `SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'jk_indonesian')`
+	if err := db.Raw(checkConfigSQL).Scan(&configExists).Error; err != nil {
+		log.Printf("⚠️ Could not check text search configuration: %v", err)
+		return err
+	}
+
+	if !configExists {
+		var indonesian bool
+		checkIndonesianSQL := `SELECT EXISTS (SELECT 1 FROM pg_ts_config WHERE cfgname = 'indonesian')`
+		if err := db.Raw(checkIndonesianSQL).Scan(&indonesian).Error; err != nil {
+			log.Printf("⚠️ Could not check text search configuration: %v", err)
+			return err
+		}
+
+		source := "pg_catalog.indonesian"
+		if !indonesian {
+			log.Println("⚠️ Indonesian text search is not available, falling back to simple")
+			source = "pg_catalog.simple"
+		}
+
+		createConfigSQL := fmt.Sprintf(`CREATE TEXT SEARCH CONFIGURATION jk_indonesian (COPY = %s)`, source)
+		if err := db.Exec(createConfigSQL).Error; err != nil {
+			log.Printf("❌ Failed to create text search configuration: %v", err)
+			return err
+		}
+	}
+
+	functionsSQL := []string{
+		`CREATE OR REPLACE FUNCTION jk_search_vector(body text) RETURNS tsvector
+			LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+			SELECT to_tsvector('public.jk_indonesian'::regconfig, COALESCE(body, ''))
+				|| to_tsvector('pg_catalog.english'::regconfig, COALESCE(body, ''))
+		$$`,
+		`CREATE OR REPLACE FUNCTION jk_search_query(query text) RETURNS tsquery
+			LANGUAGE sql IMMUTABLE PARALLEL SAFE AS $$
+			SELECT websearch_to_tsquery('public.jk_indonesian'::regconfig, query)
+				|| websearch_to_tsquery('pg_catalog.english'::regconfig, query)
+		$$`,
+	}
+	for _, functionSQL := range functionsSQL {
+		if err := db.Exec(functionSQL).Error; err != nil {
+			log.Printf("❌ Failed to create search function: %v", err)
+			return err
+		}
+	}
+
+	// Titles weigh more than bodies when ranking.
+	tables := []struct {
+		name   string
+		vector string
+	}{
+		{"m_course", `setweight(jk_search_vector(course_name), 'A') || setweight(jk_search_vector(description), 'B')`},
+		{"m_lesson", `setweight(jk_search_vector(title), 'A') || setweight(jk_search_vector(description), 'B')`},
+		{"m_sub_lesson", `setweight(jk_search_vector(title), 'A')`},
+		{"m_materials", `setweight(jk_search_vector(title), 'A') || setweight(jk_search_vector(materials_text), 'B')`},
+		{"t_code_question", `setweight(jk_search_vector(code_question), 'B') || setweight(jk_search_vector(hint), 'C')`},
+	}
+
+	for _, table := range tables {
+		addColumnSQL := fmt.Sprintf(
+			`ALTER TABLE %s ADD COLUMN IF NOT EXISTS search_vector tsvector GENERATED ALWAYS AS (%s) STORED`,
+			table.name, table.vector,
+		)
+		if err := db.Exec(addColumnSQL).Error; err != nil {
+			log.Printf("❌ Failed to add search vector to %s: %v", table.name, err)
+			return err
+		}
+
+		addIndexSQL := fmt.Sprintf(
+			`CREATE INDEX IF NOT EXISTS idx_%s_search_vector ON %s USING GIN (search_vector)`,
+			table.name, table.name,
+		)
+		if err := db.Exec(addIndexSQL).Error; err != nil {
+			log.Printf("❌ Failed to index search vector of %s: %v", table.name, err)
+			return err
+		}
+	}
+
+	log.Println("✅ Content Search Migration Completed.")
+	return nil
+}
@@ -0,0 +1,63 @@
+package migrations
+
+import (
+	"fmt"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// EditorialWorkflow adds the status column to courses, lessons and materials
+// before AutoMigrate does, so existing rows can be backfilled exactly once.
+// Courses keep their published flag. Lessons and materials were shown to
+// students regardless of the flag, so they start out published to avoid
+// hiding live content.
+func EditorialWorkflow(db *gorm.DB) error {
+	log.Println("🔄 Running Editorial Workflow Migration...")
+
+	tables := []struct {
+		name     string
+		backfill string
+	}{
+		{"m_course", `UPDATE m_course SET status = CASE WHEN published THEN 'published' ELSE 'draft' END`},
+		{"m_lesson", `UPDATE m_lesson SET status = 'published', published = TRUE`},
+		{"m_materials", `UPDATE m_materials SET status = 'published', published = TRUE`},
+	}
+
+	for _, table := range tables {
+		var tableExists bool
+		checkTableSQL := `SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = ?)`
+		if err := db.Raw(checkTableSQL, table.name).Scan(&tableExists).Error; err != nil {
+			log.Printf("⚠️ Could not check if table exists: %v", err)
+			return err
+		}
+		if !tableExists {
+			continue
+		}
+
+		var columnExists bool
+		checkColumnSQL := `SELECT EXISTS (SELECT 1 FROM information_schema.columns WHERE table_name = ? AND column_name = 'status')`
+		if err := db.Raw(checkColumnSQL, table.name).Scan(&columnExists).Error; err != nil {
+			log.Printf("⚠️ Could not check if column exists: %v", err)
+			return err
+		}
+		if columnExists {
+			continue
+		}
+
+		err := db.Transaction(func(tx *gorm.DB) error {
+			addColumnSQL := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'draft'`, table.name)
+			if err := tx.Exec(addColumnSQL).Error; err != nil {
+				return err
+			}
+			return tx.Exec(table.backfill).Error
+		})
+		if err != nil {
+			log.Printf("❌ Failed to backfill %s status: %v", table.name, err)
+			return err
+		}
+	}
+
+	log.Println("✅ Editorial Workflow Migration Completed.")
+	return nil
+}
@@ -13,13 +13,28 @@ func Migrate() {
 		log.Fatalf("❌ Failed to create sequences: %v", err)
 	}
 
+	if err := DedupeStudentProgress(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if err := EditorialWorkflow(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if err := RichMaterials(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
 	// if err := SetupJoinTable(db); err != nil {
 	// 	log.Fatalf("❌ Failed to setup join table: %v", err)
 	// }
 
 	err := db.AutoMigrate(
 		&models.RefreshToken{},
+		&models.MSchool{},
+		&models.Department{},
 		&models.User{},
+		&models.MMedia{},
 		&models.MLevel{},
 		&models.Role{},
 		&models.MClass{},
@@ -38,12 +53,32 @@ func Migrate() {
 		&models.TEssayAnswer{},
 		&models.TGenerationHistory{},
 		&models.Permission{},
+		&models.TEventOutbox{},
+		&models.MWebhookSubscription{},
+		&models.TWebhookDelivery{},
+		&models.Notification{},
+		&models.MNotificationPreference{},
+		&models.TNotificationDelivery{},
+		&models.TClassJoinRequest{},
+		&models.TClassRosterLog{},
+		&models.TClassCourse{},
+		&models.TClassLessonDeadline{},
+		&models.TGuardianLink{},
+		&models.TContentTransition{},
+		&models.TContentComment{},
+		&models.TCourseVersion{},
+		&models.TMediaUpload{},
+		&models.MPromptTemplate{},
+		&models.TPromptTemplateVersion{},
+		&models.TPromptEvaluation{},
+		&models.TTutorConversation{},
+		&models.TTutorMessage{},
+		&models.MQuiz{},
+		&models.MQuizItem{},
+		&models.TQuizAttempt{},
+		&models.TQuizAnswer{},
 	)
 
-	// if err := NotificationPriorityEnum(db); err != nil {
-	// 	log.Fatal("❌ Migration failed:", err)
-	// }
-
 	// if err := AddBacklogPriorityCheck(db); err != nil {
 	// 	log.Fatal("❌ Migration failed:", err)
 	// }
@@ -52,5 +87,25 @@ func Migrate() {
 		log.Fatalf("❌ Migration failed: %v", err)
 	}
 
+	if err := StudentProgressLifecycle(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if err := NotificationPriorityEnum(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if err := SchoolTenancy(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if err := ContentSearch(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
+	if err := PromptTemplates(db); err != nil {
+		log.Fatalf("❌ Migration failed: %v", err)
+	}
+
 	log.Println("✅ Migration complete")
 }
@@ -0,0 +1,112 @@
+package migrations
+
+import (
+	"fmt"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/generation"
+	"log"
+	"strings"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// PromptTemplates seeds a shared default template for every kind of content
+// that has none, then turns the free-text prompt_llm of materials into
+// templates: one per school and distinct text, without variables, that the
+// materials then reference. It must run after AutoMigrate and is safe to
+// re-run, as converted materials are skipped.
+func PromptTemplates(db *gorm.DB) error {
+	log.Println("🔄 Running Prompt Templates Migration...")
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		for _, kind := range constant.GenerationKinds {
+			var exists bool
+			checkDefaultSQL := `SELECT EXISTS (SELECT 1 FROM m_prompt_template WHERE kind = ? AND is_default AND school_id IS NULL)`
+			if err := tx.Raw(checkDefaultSQL, kind).Scan(&exists).Error; err != nil {
+				return err
+			}
+			if exists {
+				continue
+			}
+
+			builtin := generation.DefaultTemplates[kind]
+			if _, err := createPromptTemplate(tx, builtin.Name, kind, nil, true, builtin.Body, builtin.Variables); err != nil {
+				return err
+			}
+		}
+
+		var prompts []struct {
+			SchoolID *int64
+			Prompt   string
+		}
+		legacySQL := `SELECT DISTINCT c.school_id, m.prompt_llm AS prompt
+			FROM m_materials m
+			JOIN m_sub_lesson sl ON sl.id = m.sub_lesson_id
+			JOIN m_lesson l ON l.id = sl.lesson_id
+			JOIN m_course c ON c.id = l.course_id
+			WHERE m.prompt_template_version_id IS NULL AND TRIM(COALESCE(m.prompt_llm, '')) <> ''
+			ORDER BY c.school_id, m.prompt_llm`
+		if err := tx.Raw(legacySQL).Scan(&prompts).Error; err != nil {
+			return err
+		}
+
+		for i, prompt := range prompts {
+			// The text was never a template, so braces are kept literal.
+			body := strings.ReplaceAll(prompt.Prompt, "{{", `{{"{{"}}`)
+			name := fmt.Sprintf("Prompt lama %d", i+1)
+
+			versionID, err := createPromptTemplate(tx, name, constant.GenerationKindMaterial, prompt.SchoolID, false, body, nil)
+			if err != nil {
+				return err
+			}
+
+			linkSQL := `UPDATE m_materials m SET prompt_template_version_id = ?
+				FROM m_sub_lesson sl, m_lesson l, m_course c
+				WHERE sl.id = m.sub_lesson_id AND l.id = sl.lesson_id AND c.id = l.course_id
+					AND m.prompt_template_version_id IS NULL AND m.prompt_llm = ?
+					AND c.school_id IS NOT DISTINCT FROM ?`
+			if err := tx.Exec(linkSQL, versionID, prompt.Prompt, prompt.SchoolID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Failed to migrate prompt templates: %v", err)
+		return err
+	}
+
+	log.Println("✅ Prompt Templates Migration Completed.")
+	return nil
+}
+
+func createPromptTemplate(tx *gorm.DB, name string, kind string, schoolID *int64, isDefault bool, body string, vars []models.PromptVariable) (int64, error) {
+	template := models.MPromptTemplate{
+		Name:           name,
+		Kind:           kind,
+		IsDefault:      isDefault,
+		CurrentVersion: 1,
+		SchoolID:       schoolID,
+		IsActive:       true,
+	}
+	if err := tx.Create(&template).Error; err != nil {
+		return 0, err
+	}
+
+	if vars == nil {
+		vars = []models.PromptVariable{}
+	}
+	version := models.TPromptTemplateVersion{
+		TemplateID: template.ID,
+		Version:    1,
+		Body:       body,
+		Variables:  datatypes.JSONSlice[models.PromptVariable](vars),
+		ChangeNote: "Dibuat otomatis saat migrasi",
+	}
+	if err := tx.Omit("Template").Create(&version).Error; err != nil {
+		return 0, err
+	}
+	return version.ID, nil
+}
@@ -0,0 +1,79 @@
+package migrations
+
+import (
+	"jk-api/internal/richtext"
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// RichMaterials turns m_materials.materials from text into a jsonb Tiptap
+// document before AutoMigrate tries to, which Postgres would refuse for text
+// that isn't JSON. Plain text becomes paragraphs and documents the editor
+// stored as text are validated; anything else keeps its text. The plain text
+// goes to materials_text.
+//
+// The search vector is built from materials, so it is dropped here and
+// ContentSearch adds it back over materials_text. Runs once: later runs find
+// the column already converted.
+func RichMaterials(db *gorm.DB) error {
+	log.Println("🔄 Running Rich Materials Migration...")
+
+	var dataType string
+	checkColumnSQL := `SELECT data_type FROM information_schema.columns WHERE table_name = 'm_materials' AND column_name = 'materials'`
+	if err := db.Raw(checkColumnSQL).Scan(&dataType).Error; err != nil {
+		log.Printf("⚠️ Could not check materials column: %v", err)
+		return err
+	}
+	if dataType == "" || dataType == "jsonb" {
+		log.Println("✅ Rich Materials Migration Completed.")
+		return nil
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		statements := []string{
+			`ALTER TABLE m_materials DROP COLUMN IF EXISTS search_vector`,
+			`ALTER TABLE m_materials ADD COLUMN IF NOT EXISTS materials_text text`,
+			`UPDATE m_materials SET materials_text = materials`,
+			`ALTER TABLE m_materials ALTER COLUMN materials TYPE jsonb USING NULL::jsonb`,
+		}
+		for _, statement := range statements {
+			if err := tx.Exec(statement).Error; err != nil {
+				return err
+			}
+		}
+
+		var rows []struct {
+			ID   int64
+			Text *string
+		}
+		if err := tx.Raw(`SELECT id, materials_text AS text FROM m_materials`).Scan(&rows).Error; err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			var legacy []byte
+			if row.Text != nil {
+				legacy = []byte(*row.Text)
+			}
+			doc := richtext.FromLegacy(legacy)
+			data, err := doc.JSON()
+			if err != nil {
+				return err
+			}
+
+			updateSQL := `UPDATE m_materials SET materials = ?, materials_text = ? WHERE id = ?`
+			if err := tx.Exec(updateSQL, string(data), richtext.PlainText(doc), row.ID).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		log.Printf("❌ Failed to convert materials to documents: %v", err)
+		return err
+	}
+
+	log.Println("✅ Rich Materials Migration Completed.")
+	return nil
+}
@@ -0,0 +1,77 @@
+package migrations
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// SchoolTenancy turns the free-text m_class.school_name into m_school rows and
+// backfills school_id on classes and their members. Teachers are only moved
+// into a school when every class they teach belongs to the same one. It must
+// run after AutoMigrate and is safe to re-run.
+func SchoolTenancy(db *gorm.DB) error {
+	log.Println("🔄 Running School Tenancy Migration...")
+
+	insertSchoolsSQL := `
+		INSERT INTO m_school (name, is_active, created_at)
+		SELECT DISTINCT TRIM(c.school_name), TRUE, NOW()
+		FROM m_class c
+		WHERE c.school_id IS NULL
+			AND COALESCE(TRIM(c.school_name), '') <> ''
+			AND NOT EXISTS (
+				SELECT 1 FROM m_school s
+				WHERE LOWER(s.name) = LOWER(TRIM(c.school_name)) AND s.deleted_at IS NULL
+			)`
+
+	if err := db.Exec(insertSchoolsSQL).Error; err != nil {
+		log.Printf("❌ Failed to create schools from class names: %v", err)
+		return err
+	}
+
+	classSQL := `
+		UPDATE m_class c
+		SET school_id = s.id
+		FROM m_school s
+		WHERE c.school_id IS NULL
+			AND s.deleted_at IS NULL
+			AND LOWER(s.name) = LOWER(TRIM(c.school_name))`
+
+	if err := db.Exec(classSQL).Error; err != nil {
+		log.Printf("❌ Failed to backfill class schools: %v", err)
+		return err
+	}
+
+	studentSQL := `
+		UPDATE users u
+		SET school_id = c.school_id
+		FROM m_class c
+		WHERE u.school_id IS NULL
+			AND u.class_id = c.id
+			AND c.school_id IS NOT NULL`
+
+	if err := db.Exec(studentSQL).Error; err != nil {
+		log.Printf("❌ Failed to backfill student schools: %v", err)
+		return err
+	}
+
+	teacherSQL := `
+		UPDATE users u
+		SET school_id = t.school_id
+		FROM (
+			SELECT ct.user_id, MIN(c.school_id) AS school_id
+			FROM m_class_teachers ct
+			JOIN m_class c ON c.id = ct.m_class_id
+			GROUP BY ct.user_id
+			HAVING COUNT(DISTINCT c.school_id) = 1 AND COUNT(*) = COUNT(c.school_id)
+		) t
+		WHERE u.school_id IS NULL AND u.id = t.user_id`
+
+	if err := db.Exec(teacherSQL).Error; err != nil {
+		log.Printf("❌ Failed to backfill teacher schools: %v", err)
+		return err
+	}
+
+	log.Println("✅ School Tenancy Migration Completed")
+	return nil
+}
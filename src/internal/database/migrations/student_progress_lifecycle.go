@@ -0,0 +1,104 @@
+package migrations
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+)
+
+// DedupeStudentProgress removes duplicate (user_id, sub_lesson_id) rows so the
+// unique index on t_student_progress can be created. It must run before
+// AutoMigrate. The most advanced row is kept (completed first, then the oldest).
+func DedupeStudentProgress(db *gorm.DB) error {
+	log.Println("🔄 Running Student Progress Dedupe Migration...")
+
+	var tableExists bool
+	checkTableSQL := `SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_name = 't_student_progress')`
+	if err := db.Raw(checkTableSQL).Scan(&tableExists).Error; err != nil {
+		log.Printf("⚠️ Could not check if table exists: %v", err)
+		return err
+	}
+
+	if !tableExists {
+		log.Println("✅ Student Progress Dedupe Migration Completed (skipped - table not found)")
+		return nil
+	}
+
+	dedupeSQL := `
+		DELETE FROM t_student_progress
+		WHERE id IN (
+			SELECT id FROM (
+				SELECT id, ROW_NUMBER() OVER (
+					PARTITION BY user_id, sub_lesson_id
+					ORDER BY (status = 'completed') DESC, id ASC
+				) AS rn
+				FROM t_student_progress
+			) ranked
+			WHERE ranked.rn > 1
+		)`
+
+	result := db.Exec(dedupeSQL)
+	if result.Error != nil {
+		log.Printf("❌ Failed to dedupe student progress: %v", result.Error)
+		return result.Error
+	}
+
+	log.Printf("✅ Student Progress Dedupe Migration Completed (%d duplicate rows removed)", result.RowsAffected)
+	return nil
+}
+
+// StudentProgressLifecycle backfills lifecycle timestamps for rows written before
+// the state machine existed and restricts status to the known states.
+func StudentProgressLifecycle(db *gorm.DB) error {
+	log.Println("🔄 Running Student Progress Lifecycle Migration...")
+
+	backfillSQL := `
+		UPDATE t_student_progress
+		SET completed_at = COALESCE(updated_at, created_at),
+			started_at = COALESCE(started_at, created_at),
+			last_viewed_at = COALESCE(last_viewed_at, updated_at, created_at)
+		WHERE status = 'completed' AND completed_at IS NULL`
+
+	if err := db.Exec(backfillSQL).Error; err != nil {
+		log.Printf("❌ Failed to backfill student progress timestamps: %v", err)
+		return err
+	}
+
+	normalizeSQL := `
+		UPDATE t_student_progress
+		SET status = 'not_started'
+		WHERE status IS NULL OR status NOT IN ('not_started','in_progress','completed','needs_review')`
+
+	if err := db.Exec(normalizeSQL).Error; err != nil {
+		log.Printf("❌ Failed to normalize student progress status: %v", err)
+		return err
+	}
+
+	var constraintExists bool
+	checkConstraintSQL := `
+		SELECT EXISTS (
+			SELECT 1 FROM information_schema.check_constraints
+			WHERE constraint_name = 'chk_t_student_progress_status'
+		)`
+
+	if err := db.Raw(checkConstraintSQL).Scan(&constraintExists).Error; err != nil {
+		log.Printf("⚠️ Could not check constraint existence: %v", err)
+		constraintExists = false
+	}
+
+	if !constraintExists {
+		addConstraintSQL := `
+			ALTER TABLE t_student_progress
+			ADD CONSTRAINT chk_t_student_progress_status
+			CHECK (status IN ('not_started','in_progress','completed','needs_review'))`
+
+		if err := db.Exec(addConstraintSQL).Error; err != nil {
+			log.Printf("❌ Failed to add status constraint: %v", err)
+			return err
+		}
+		log.Println("✅ Added status check constraint to t_student_progress")
+	}
+
+	log.Println("✅ Student Progress Lifecycle Migration Completed")
+	return nil
+}
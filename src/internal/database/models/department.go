@@ -1,20 +1,60 @@
 package models
 
 import (
+	"jk-api/internal/queryspec"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+// Department groups users and classes of a school. Departments form a tree
+// through ParentID; reports and permission scopes cover a whole subtree.
 type Department struct {
-	ID        int64     `gorm:"primaryKey;autoIncrement:false;type:bigint;default:nextval('departments_seq'::regclass)" json:"id"`
-	Name      string    `gorm:"size:255;unique:uni_departments_name;not null" json:"name" validate:"required,min=3"`
-	Code      *string   `gorm:"size:255;unique:uni_departments_code" json:"code"`
-	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
-	DeletedAt  gorm.DeletedAt `gorm:"column:deleted_at;index:idx_departments_deleted_at" json:"deleted_at"`
+	ID        int64          `gorm:"primaryKey;autoIncrement:false;type:bigint;default:nextval('departments_seq'::regclass)" json:"id"`
+	SchoolID  *int64         `gorm:"column:school_id;uniqueIndex:uni_departments_name,where:deleted_at IS NULL;uniqueIndex:uni_departments_code,where:deleted_at IS NULL" json:"school_id"`
+	ParentID  *int64         `gorm:"column:parent_id;index:idx_departments_parent_id" json:"parent_id"`
+	Name      string         `gorm:"size:255;not null;uniqueIndex:uni_departments_name,where:deleted_at IS NULL" json:"name" validate:"required,min=3"`
+	Code      *string        `gorm:"size:255;uniqueIndex:uni_departments_code,where:deleted_at IS NULL" json:"code"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time      `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index:idx_departments_deleted_at" json:"deleted_at"`
+
+	// Relations
+	Parent   *Department  `gorm:"foreignKey:ParentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:RESTRICT;" json:"parent,omitempty"`
+	Children []Department `gorm:"foreignKey:ParentID;references:ID" json:"children,omitempty"`
 }
 
 func (*Department) TableName() string {
 	return "departments"
 }
+
+func (*Department) SchoolColumn() string {
+	return "departments.school_id"
+}
+
+func (d *Department) AssignSchool(schoolID int64) {
+	d.SchoolID = &schoolID
+}
+
+func (*Department) DepartmentCondition(subtree string) string {
+	return "departments.id IN (" + subtree + ")"
+}
+
+// AssignDepartment files new top-level departments under the caller's own.
+func (d *Department) AssignDepartment(departmentID int64) {
+	if d.ParentID == nil {
+		d.ParentID = &departmentID
+	}
+}
+
+// QueryFields lists the fields list endpoints may filter and sort Department by.
+func (*Department) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "departments.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"school_id":  {Column: "departments.school_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"parent_id":  {Column: "departments.parent_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"name":       {Column: "departments.name", Kind: queryspec.String, Filter: true, Sort: true},
+		"code":       {Column: "departments.code", Kind: queryspec.String, Filter: true, Sort: true},
+		"created_at": {Column: "departments.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -6,12 +6,17 @@ type MBadgeSettings struct {
 	ID         int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
 	Name       string     `gorm:"size:100" json:"name"`
 	Image      string     `gorm:"type:text" json:"image"`
+	// ImageMediaID references the uploaded badge image; Image keeps its URL
+	// for older clients.
+	ImageMediaID *int64   `gorm:"column:image_media_id;index" json:"image_media_id"`
 	MinScore   int        `gorm:"column:min_score" json:"min_score"`
 	MaxScore   int        `gorm:"column:max_score" json:"max_score"`
 	IsActive   bool       `gorm:"column:isactive;default:true" json:"isactive"`
 	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt  time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
+	ImageMedia *MMedia `gorm:"foreignKey:ImageMediaID;references:ID;constraint:OnDelete:SET NULL" json:"image_media,omitempty"`
+
 	
 
 	
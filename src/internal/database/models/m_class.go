@@ -9,8 +9,13 @@ import (
 type MClass struct {
     ID         int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
     ClassName  string     `gorm:"column:class_name;size:100" json:"class_name"`
+    SchoolID   *int64     `gorm:"column:school_id;index:idx_class_school_id" json:"school_id"`
+    DepartmentID *int64   `gorm:"column:department_id;index:idx_class_department_id" json:"department_id"`
+    // Deprecated: kept for display, the owning school is SchoolID.
     SchoolName string     `gorm:"column:school_name;size:100" json:"school_name"`
     ClassCode  string     `gorm:"column:class_code;size:50;uniqueIndex" json:"class_code"`
+    ClassCodeExpiresAt *time.Time `gorm:"column:class_code_expires_at" json:"class_code_expires_at"`
+    AutoApproveJoin    bool       `gorm:"column:auto_approve_join;not null;default:false" json:"auto_approve_join"`
     IsActive   bool       `gorm:"column:isactive;default:true" json:"isactive"`
     CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
     UpdatedAt  *time.Time `gorm:"autoUpdateTime" json:"updated_at"`
@@ -21,8 +26,28 @@ type MClass struct {
     Teachers []User `gorm:"many2many:m_class_teachers;" json:"teachers"`
     // Menampilkan daftar siswa di kelas ini
     Students []User `gorm:"foreignKey:ClassID" json:"students"`
+    School   *MSchool `gorm:"foreignKey:SchoolID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"school,omitempty"`
+    Department *Department `gorm:"foreignKey:DepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"department,omitempty"`
 }
 
 func (*MClass) TableName() string {
 	return "m_class"
 }
+
+func (*MClass) SchoolColumn() string {
+	return "m_class.school_id"
+}
+
+func (m *MClass) AssignSchool(schoolID int64) {
+	m.SchoolID = &schoolID
+}
+
+func (*MClass) DepartmentCondition(subtree string) string {
+	return "m_class.department_id IN (" + subtree + ")"
+}
+
+func (m *MClass) AssignDepartment(departmentID int64) {
+	if m.DepartmentID == nil {
+		m.DepartmentID = &departmentID
+	}
+}
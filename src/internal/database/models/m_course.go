@@ -1,13 +1,25 @@
 package models
 
-import "time"
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
 
 type MCourse struct {
 	ID           int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
 	CourseName   string     `gorm:"column:course_name;size:100" json:"course_name"`
 	Description  string     `gorm:"type:text" json:"description"`
 	ImgThumbnail string     `gorm:"column:img_thumbnail;type:text" json:"img_thumbnail"`
+	// ImgThumbnailMediaID references the uploaded thumbnail; ImgThumbnail
+	// keeps its URL for older clients.
+	ImgThumbnailMediaID *int64 `gorm:"column:img_thumbnail_media_id;index" json:"img_thumbnail_media_id"`
+	// Status is the editorial state; Published mirrors Status == published.
+	Status       string     `gorm:"column:status;size:20;not null;default:draft;index" json:"status"`
 	Published    bool       `gorm:"default:false" json:"published"`
+	// IsTemplate lists the course in the template gallery teachers clone from.
+	IsTemplate   bool       `gorm:"column:is_template;default:false;index" json:"is_template"`
+	// SchoolID marks a course private to one school; NULL courses are shared.
+	SchoolID     *int64     `gorm:"column:school_id;index:idx_course_school_id" json:"school_id"`
 	IsActive     bool       `gorm:"column:isactive;default:true" json:"isactive"`
 	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt    time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
@@ -15,8 +27,35 @@ type MCourse struct {
 
 	// Relations
 	Lessons *[]MLesson `gorm:"foreignKey:CourseID;references:ID" json:"lessons"`
+	ImgThumbnailMedia *MMedia `gorm:"foreignKey:ImgThumbnailMediaID;references:ID;constraint:OnDelete:SET NULL" json:"img_thumbnail_media,omitempty"`
 }
 
 func (*MCourse) TableName() string {
 	return "m_course"
 }
+
+func (*MCourse) SchoolColumn() string {
+	return "m_course.school_id"
+}
+
+// SharedAcrossSchools marks courses without a school as public.
+func (*MCourse) SharedAcrossSchools() {}
+
+func (m *MCourse) AssignSchool(schoolID int64) {
+	m.SchoolID = &schoolID
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MCourse by.
+func (*MCourse) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":          {Column: "m_course.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"course_name": {Column: "m_course.course_name", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":      {Column: "m_course.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"published":   {Column: "m_course.published", Kind: queryspec.Bool, Filter: true},
+		"is_template": {Column: "m_course.is_template", Kind: queryspec.Bool, Filter: true},
+		"school_id":   {Column: "m_course.school_id", Kind: queryspec.Int, Filter: true},
+		"isactive":    {Column: "m_course.isactive", Kind: queryspec.Bool, Filter: true},
+		"created_at":  {Column: "m_course.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":  {Column: "m_course.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
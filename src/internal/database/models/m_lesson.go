@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
 
 type MLesson struct {
 	ID           int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
@@ -10,6 +13,11 @@ type MLesson struct {
 	Description  string     `gorm:"type:text" json:"description"`
 	Position     int        `json:"position"`
 	ImgThumbnail string     `gorm:"column:img_thumbnail;type:text" json:"img_thumbnail"`
+	// ImgThumbnailMediaID references the uploaded thumbnail; ImgThumbnail
+	// keeps its URL for older clients.
+	ImgThumbnailMediaID *int64 `gorm:"column:img_thumbnail_media_id;index" json:"img_thumbnail_media_id"`
+	// Status is the editorial state; Published mirrors Status == published.
+	Status       string     `gorm:"column:status;size:20;not null;default:draft;index" json:"status"`
 	Published    bool       `gorm:"default:false" json:"published"`
 	IsActive     bool       `gorm:"column:isactive;default:true" json:"isactive"`
 	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
@@ -20,8 +28,33 @@ type MLesson struct {
 	Course *MCourse `gorm:"foreignKey:CourseID;references:ID" json:"course"`
 	Level  *MLevel  `gorm:"foreignKey:LevelID;references:ID" json:"level"`
 	SubLessons []MSubLesson `gorm:"foreignKey:LessonID;references:ID" json:"sub_lessons"`
+	ImgThumbnailMedia *MMedia `gorm:"foreignKey:ImgThumbnailMediaID;references:ID;constraint:OnDelete:SET NULL" json:"img_thumbnail_media,omitempty"`
 }
 
 func (*MLesson) TableName() string {
 	return "m_lesson"
 }
+
+// ParentColumn scopes lessons through the school of their course.
+func (*MLesson) ParentColumn() string {
+	return "m_lesson.course_id"
+}
+
+func (*MLesson) Parent() any {
+	return &MCourse{}
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MLesson by.
+func (*MLesson) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "m_lesson.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"course_id":  {Column: "m_lesson.course_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"level_id":   {Column: "m_lesson.level_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"title":      {Column: "m_lesson.title", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":     {Column: "m_lesson.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"published":  {Column: "m_lesson.published", Kind: queryspec.Bool, Filter: true},
+		"isactive":   {Column: "m_lesson.isactive", Kind: queryspec.Bool, Filter: true},
+		"created_at": {Column: "m_lesson.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at": {Column: "m_lesson.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
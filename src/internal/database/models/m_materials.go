@@ -1,15 +1,31 @@
 package models
 
-import "time"
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
 
 type MMaterials struct {
 	ID              int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
 	SubLessonID    int64      `gorm:"column:sub_lesson_id" json:"sub_lesson_id"`
 	Title           string     `gorm:"size:150" json:"title"`
-	Materials       string     `gorm:"type:text" json:"materials"`
+	// Materials is a Tiptap document checked by richtext.Parse, and
+	// MaterialsText its plain text, kept for search and AI prompts.
+	Materials       datatypes.JSON `gorm:"column:materials;type:jsonb" json:"materials"`
+	MaterialsText   string     `gorm:"column:materials_text;type:text" json:"materials_text"`
 	URLVideo        string     `gorm:"column:url_video;type:text" json:"url_video"`
+	// VideoMediaID references an uploaded video. Unlike URLVideo it can be
+	// protected and is then played through a signed link.
+	VideoMediaID    *int64     `gorm:"column:video_media_id;index" json:"video_media_id"`
 	ContentPosition int        `gorm:"column:content_position" json:"content_position"`
+	// PromptLLM is the free-text prompt of materials written before prompt
+	// templates; new materials reference PromptTemplateVersionID instead.
 	PromptLLM       string     `gorm:"column:prompt_llm;type:text" json:"prompt_llm"`
+	PromptTemplateVersionID *int64 `gorm:"column:prompt_template_version_id;index" json:"prompt_template_version_id"`
+	// Status is the editorial state; Published mirrors Status == published.
+	Status          string     `gorm:"column:status;size:20;not null;default:draft;index" json:"status"`
 	Published       bool       `gorm:"default:false" json:"published"`
 	IsActive        bool       `gorm:"column:isactive;default:true" json:"isactive"`
 	CreatedAt       time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
@@ -17,8 +33,34 @@ type MMaterials struct {
 	DeletedAt       time.Time  `gorm:"column:deleted_at;index" json:"deleted_at,omitempty"`
 
 	SubLesson *MSubLesson `gorm:"foreignKey:SubLessonID;references:ID" json:"sub_lesson"`
+	VideoMedia *MMedia `gorm:"foreignKey:VideoMediaID;references:ID;constraint:OnDelete:SET NULL" json:"video_media,omitempty"`
+	PromptTemplateVersion *TPromptTemplateVersion `gorm:"foreignKey:PromptTemplateVersionID;references:ID;constraint:OnDelete:SET NULL" json:"prompt_template_version,omitempty"`
 }
 
 func (*MMaterials) TableName() string {
 	return "m_materials"
 }
+
+// ParentColumn scopes materials through their sub-lesson's course.
+func (*MMaterials) ParentColumn() string {
+	return "m_materials.sub_lesson_id"
+}
+
+func (*MMaterials) Parent() any {
+	return &MSubLesson{}
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MMaterials by.
+func (*MMaterials) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":               {Column: "m_materials.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"sub_lesson_id":    {Column: "m_materials.sub_lesson_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"title":            {Column: "m_materials.title", Kind: queryspec.String, Filter: true, Sort: true},
+		"content_position": {Column: "m_materials.content_position", Kind: queryspec.Int, Filter: true, Sort: true},
+		"status":           {Column: "m_materials.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"published":        {Column: "m_materials.published", Kind: queryspec.Bool, Filter: true},
+		"isactive":         {Column: "m_materials.isactive", Kind: queryspec.Bool, Filter: true},
+		"created_at":       {Column: "m_materials.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":       {Column: "m_materials.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,40 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// MMedia is an uploaded file. Files are stored once per content hash and
+// protection, so uploading the same bytes again returns the existing row.
+// Protected media has no public URL and is only served through signed links.
+type MMedia struct {
+	ID         int64                          `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	Hash       string                         `gorm:"column:hash;size:64;not null;uniqueIndex:uni_media_hash,priority:1" json:"hash"`
+	Protected  bool                           `gorm:"column:protected;not null;default:false;uniqueIndex:uni_media_hash,priority:2" json:"protected"`
+	MimeType   string                         `gorm:"column:mime_type;size:100;not null" json:"mime_type"`
+	Size       int64                          `gorm:"column:size;not null" json:"size"`
+	Width      int                            `gorm:"column:width" json:"width,omitempty"`
+	Height     int                            `gorm:"column:height" json:"height,omitempty"`
+	StorageKey string                         `gorm:"column:storage_key;type:text;not null" json:"-"`
+	URL        string                         `gorm:"column:url;type:text;not null" json:"url"`
+	Variants   datatypes.JSONSlice[MediaFile] `gorm:"column:variants;type:jsonb" json:"variants"`
+	UploadedBy *int64                         `gorm:"column:uploaded_by;index" json:"uploaded_by"`
+	CreatedAt  time.Time                      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+// MediaFile is a resized copy of an image.
+type MediaFile struct {
+	Name       string `json:"name"`
+	MimeType   string `json:"mime_type"`
+	Width      int    `json:"width"`
+	Height     int    `json:"height"`
+	Size       int64  `json:"size"`
+	StorageKey string `json:"storage_key"`
+	URL        string `json:"url"`
+}
+
+func (*MMedia) TableName() string {
+	return "m_media"
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// MNotificationPreference stores which channels a user wants for one
+// notification type. A missing row means the defaults apply.
+type MNotificationPreference struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	UserID    int64      `gorm:"column:user_id;not null;uniqueIndex:idx_notification_preference_user_type,priority:1" json:"user_id"`
+	Type      string     `gorm:"column:type;size:50;not null;uniqueIndex:idx_notification_preference_user_type,priority:2" json:"type"`
+	InApp     bool       `gorm:"column:in_app;not null" json:"in_app"`
+	Push      bool       `gorm:"column:push;not null" json:"push"`
+	Omni      bool       `gorm:"column:omni;not null" json:"omni"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (*MNotificationPreference) TableName() string {
+	return "m_notification_preference"
+}
+
+// DefaultNotificationPreference is used for types the user never configured.
+func DefaultNotificationPreference(userID int64, notificationType string) MNotificationPreference {
+	return MNotificationPreference{
+		UserID: userID,
+		Type:   notificationType,
+		InApp:  true,
+		Push:   true,
+		Omni:   false,
+	}
+}
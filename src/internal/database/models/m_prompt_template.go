@@ -0,0 +1,59 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// MPromptTemplate is a reusable prompt for the generation pipeline. Its text
+// lives in immutable versions; CurrentVersion is the one used by default.
+type MPromptTemplate struct {
+	ID          int64  `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	Name        string `gorm:"column:name;size:150;not null" json:"name"`
+	Kind        string `gorm:"column:kind;size:20;not null;index" json:"kind"`
+	Description string `gorm:"column:description;type:text" json:"description"`
+	// IsDefault marks the template used for its kind when a run doesn't pick
+	// one. A school's default wins over the shared one.
+	IsDefault      bool `gorm:"column:is_default;default:false" json:"is_default"`
+	CurrentVersion int  `gorm:"column:current_version;not null;default:1" json:"current_version"`
+	// SchoolID marks a template private to one school; NULL templates are
+	// shared.
+	SchoolID  *int64    `gorm:"column:school_id;index" json:"school_id"`
+	IsActive  bool      `gorm:"column:isactive;default:true" json:"isactive"`
+	CreatedBy *int64    `gorm:"column:created_by" json:"created_by"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Current is the version numbered CurrentVersion, loaded by the service.
+	Current *TPromptTemplateVersion `gorm:"-" json:"current,omitempty"`
+}
+
+func (*MPromptTemplate) TableName() string {
+	return "m_prompt_template"
+}
+
+func (*MPromptTemplate) SchoolColumn() string {
+	return "m_prompt_template.school_id"
+}
+
+// SharedAcrossSchools marks templates without a school as available to all.
+func (*MPromptTemplate) SharedAcrossSchools() {}
+
+func (m *MPromptTemplate) AssignSchool(schoolID int64) {
+	m.SchoolID = &schoolID
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MPromptTemplate by.
+func (*MPromptTemplate) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":              {Column: "m_prompt_template.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"name":            {Column: "m_prompt_template.name", Kind: queryspec.String, Filter: true, Sort: true},
+		"kind":            {Column: "m_prompt_template.kind", Kind: queryspec.String, Filter: true, Sort: true},
+		"is_default":      {Column: "m_prompt_template.is_default", Kind: queryspec.Bool, Filter: true},
+		"current_version": {Column: "m_prompt_template.current_version", Kind: queryspec.Int, Filter: true, Sort: true},
+		"school_id":       {Column: "m_prompt_template.school_id", Kind: queryspec.Int, Filter: true},
+		"isactive":        {Column: "m_prompt_template.isactive", Kind: queryspec.Bool, Filter: true},
+		"created_at":      {Column: "m_prompt_template.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":      {Column: "m_prompt_template.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// MQuiz is a quiz attached to a sub-lesson. Students only see published
+// quizzes. MaxAttempts of 0 allows any number of attempts, and ScorePolicy
+// tells which attempts count towards the course score.
+type MQuiz struct {
+	ID             int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	SubLessonID    int64     `gorm:"column:sub_lesson_id;not null;index" json:"sub_lesson_id"`
+	Title          string    `gorm:"column:title;size:150;not null" json:"title"`
+	Description    string    `gorm:"column:description;type:text" json:"description"`
+	Status         string    `gorm:"column:status;size:20;not null;default:draft;index" json:"status"`
+	ShuffleItems   bool      `gorm:"column:shuffle_items;default:false" json:"shuffle_items"`
+	ShuffleOptions bool      `gorm:"column:shuffle_options;default:false" json:"shuffle_options"`
+	MaxAttempts    int       `gorm:"column:max_attempts;not null;default:0" json:"max_attempts"`
+	ScorePolicy    string    `gorm:"column:score_policy;size:20;not null;default:highest" json:"score_policy"`
+	CreatedBy      *int64    `gorm:"column:created_by" json:"created_by"`
+	CreatedAt      time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Foreign Key Relationships
+	SubLesson *MSubLesson `gorm:"foreignKey:SubLessonID;references:ID;constraint:OnDelete:CASCADE" json:"sub_lesson,omitempty"`
+	Items     []MQuizItem `gorm:"foreignKey:QuizID;references:ID" json:"items,omitempty"`
+}
+
+func (*MQuiz) TableName() string {
+	return "m_quiz"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MQuiz by.
+func (*MQuiz) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":            {Column: "m_quiz.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"sub_lesson_id": {Column: "m_quiz.sub_lesson_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"title":         {Column: "m_quiz.title", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":        {Column: "m_quiz.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"created_at":    {Column: "m_quiz.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":    {Column: "m_quiz.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// MQuizItem is one question of a quiz, worth Points when answered right.
+// Choice and true/false items keep their answer in Options; numeric items
+// in NumericAnswer, give or take Tolerance; short answer items in
+// AcceptedAnswers, any of which is right.
+type MQuizItem struct {
+	ID              int64                           `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	QuizID          int64                           `gorm:"column:quiz_id;not null;index" json:"quiz_id"`
+	Type            string                          `gorm:"column:type;size:20;not null" json:"type"`
+	Prompt          string                          `gorm:"column:prompt;type:text;not null" json:"prompt"`
+	Options         datatypes.JSONSlice[QuizOption] `gorm:"column:options;type:jsonb" json:"options"`
+	NumericAnswer   *float64                        `gorm:"column:numeric_answer" json:"numeric_answer,omitempty"`
+	Tolerance       float64                         `gorm:"column:tolerance;not null;default:0" json:"tolerance"`
+	AcceptedAnswers datatypes.JSONSlice[string]     `gorm:"column:accepted_answers;type:jsonb" json:"accepted_answers"`
+	CaseSensitive   bool                            `gorm:"column:case_sensitive;default:false" json:"case_sensitive"`
+	Points          int                             `gorm:"column:points;not null;default:1" json:"points"`
+	Position        int                             `gorm:"column:position;not null;default:0" json:"position"`
+	Explanation     string                          `gorm:"column:explanation;type:text" json:"explanation"`
+	CreatedAt       time.Time                       `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time                       `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Foreign Key Relationships
+	Quiz *MQuiz `gorm:"foreignKey:QuizID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+// QuizOption is a choice of a choice or true/false item. IDs are numbered
+// from 1 within the item and kept when options are edited, so answers keep
+// pointing at the same option.
+type QuizOption struct {
+	ID      int    `json:"id"`
+	Text    string `json:"text"`
+	Correct bool   `json:"correct"`
+}
+
+func (*MQuizItem) TableName() string {
+	return "m_quiz_item"
+}
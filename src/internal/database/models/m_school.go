@@ -0,0 +1,35 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MSchool is a tenant. Users, classes and private courses belong to one.
+type MSchool struct {
+	ID        int64          `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	Name      string         `gorm:"column:name;size:150;not null" json:"name"`
+	Code      *string        `gorm:"column:code;size:50;uniqueIndex" json:"code"`
+	Address   string         `gorm:"column:address;type:text" json:"address"`
+	IsActive  bool           `gorm:"column:is_active;not null;default:true" json:"is_active"`
+	CreatedAt time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt *time.Time     `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"column:deleted_at;index" json:"deleted_at"`
+}
+
+func (*MSchool) TableName() string {
+	return "m_school"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MSchool by.
+func (*MSchool) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "m_school.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"name":       {Column: "m_school.name", Kind: queryspec.String, Filter: true, Sort: true},
+		"code":       {Column: "m_school.code", Kind: queryspec.String, Filter: true, Sort: true},
+		"is_active":  {Column: "m_school.is_active", Kind: queryspec.Bool, Filter: true},
+		"created_at": {Column: "m_school.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -1,6 +1,9 @@
 package models
 
-import "time"
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
 
 type MSubLesson struct {
 	ID            int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
@@ -21,3 +24,25 @@ type MSubLesson struct {
 func (*MSubLesson) TableName() string {
 	return "m_sub_lesson"
 }
+
+// ParentColumn scopes sub-lessons through their lesson's course.
+func (*MSubLesson) ParentColumn() string {
+	return "m_sub_lesson.lesson_id"
+}
+
+func (*MSubLesson) Parent() any {
+	return &MLesson{}
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MSubLesson by.
+func (*MSubLesson) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":             {Column: "m_sub_lesson.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"lesson_id":      {Column: "m_sub_lesson.lesson_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"title":          {Column: "m_sub_lesson.title", Kind: queryspec.String, Filter: true, Sort: true},
+		"order_position": {Column: "m_sub_lesson.order_position", Kind: queryspec.Int, Filter: true, Sort: true},
+		"isactive":       {Column: "m_sub_lesson.isactive", Kind: queryspec.Bool, Filter: true},
+		"created_at":     {Column: "m_sub_lesson.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":     {Column: "m_sub_lesson.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
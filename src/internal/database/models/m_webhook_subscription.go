@@ -0,0 +1,52 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MWebhookSubscription struct {
+	ID        int64                       `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	Name      string                      `gorm:"column:name;size:100;not null" json:"name"`
+	URL       string                      `gorm:"column:url;type:text;not null" json:"url"`
+	Secret    string                      `gorm:"column:secret;size:128;not null" json:"-"`
+	Events    datatypes.JSONSlice[string] `gorm:"column:events;type:jsonb" json:"events"`
+	IsActive  bool                        `gorm:"column:is_active;not null;default:true" json:"is_active"`
+	CreatedBy *int64                      `gorm:"column:created_by" json:"created_by"`
+	CreatedAt time.Time                   `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt *time.Time                  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt gorm.DeletedAt              `gorm:"column:deleted_at;index" json:"deleted_at"`
+}
+
+func (*MWebhookSubscription) TableName() string {
+	return "m_webhook_subscription"
+}
+
+// Subscribes reports whether the subscription wants eventName. An empty
+// filter means every webhook event.
+func (m *MWebhookSubscription) Subscribes(eventName string) bool {
+	if len(m.Events) == 0 {
+		return true
+	}
+	for _, event := range m.Events {
+		if event == eventName {
+			return true
+		}
+	}
+	return false
+}
+
+// QueryFields lists the fields list endpoints may filter and sort MWebhookSubscription by.
+func (*MWebhookSubscription) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "m_webhook_subscription.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"name":       {Column: "m_webhook_subscription.name", Kind: queryspec.String, Filter: true, Sort: true},
+		"url":        {Column: "m_webhook_subscription.url", Kind: queryspec.String, Filter: true, Sort: true},
+		"is_active":  {Column: "m_webhook_subscription.is_active", Kind: queryspec.Bool, Filter: true},
+		"created_by": {Column: "m_webhook_subscription.created_by", Kind: queryspec.Int, Filter: true, Sort: true},
+		"created_at": {Column: "m_webhook_subscription.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,37 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type Notification struct {
+	ID        int64             `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	UserID    int64             `gorm:"column:user_id;not null;index:idx_notifications_user_read,priority:1;uniqueIndex:idx_notifications_user_dedupe,priority:1" json:"user_id"`
+	Type      string            `gorm:"column:type;size:50;not null" json:"type"`
+	Title     string            `gorm:"column:title;size:255;not null" json:"title"`
+	Body      string            `gorm:"column:body;type:text" json:"body"`
+	Priority  string            `gorm:"column:priority;size:20;not null;default:info" json:"priority"`
+	Data      datatypes.JSONMap `gorm:"column:data;type:jsonb" json:"data"`
+	DedupeKey *string           `gorm:"column:dedupe_key;size:100;uniqueIndex:idx_notifications_user_dedupe,priority:2" json:"-"`
+	ReadAt    *time.Time        `gorm:"column:read_at;index:idx_notifications_user_read,priority:2" json:"read_at"`
+	CreatedAt time.Time         `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt *time.Time        `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (*Notification) TableName() string {
+	return "notifications"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort Notification by.
+func (*Notification) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "notifications.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"type":       {Column: "notifications.type", Kind: queryspec.String, Filter: true, Sort: true},
+		"priority":   {Column: "notifications.priority", Kind: queryspec.String, Filter: true, Sort: true},
+		"read_at":    {Column: "notifications.read_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"created_at": {Column: "notifications.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,54 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// TClassCourse assigns a course to a class. Every student of the class is
+// enrolled in the course for as long as the assignment exists.
+type TClassCourse struct {
+	ID         int64          `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ClassID    int64          `gorm:"column:class_id;not null;uniqueIndex:idx_class_course_active,where:deleted_at IS NULL" json:"class_id"`
+	CourseID   int64          `gorm:"column:course_id;not null;index;uniqueIndex:idx_class_course_active,where:deleted_at IS NULL" json:"course_id"`
+	AssignedBy int64          `gorm:"column:assigned_by" json:"assigned_by"`
+	StartAt    *time.Time     `gorm:"column:start_at" json:"start_at"`
+	EndAt      *time.Time     `gorm:"column:end_at" json:"end_at"`
+	CreatedAt  time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt  *time.Time     `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	DeletedAt  gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+
+	Class           *MClass                `gorm:"foreignKey:ClassID;references:ID" json:"class,omitempty"`
+	Course          *MCourse               `gorm:"foreignKey:CourseID;references:ID" json:"course,omitempty"`
+	LessonDeadlines []TClassLessonDeadline `gorm:"foreignKey:ClassCourseID;references:ID" json:"lesson_deadlines"`
+}
+
+func (*TClassCourse) TableName() string {
+	return "t_class_course"
+}
+
+// DueAt returns when lessonID is due for this assignment: its own deadline
+// if it has one, otherwise the end of the assignment.
+func (c *TClassCourse) DueAt(lessonID int64) *time.Time {
+	for i := range c.LessonDeadlines {
+		if c.LessonDeadlines[i].LessonID == lessonID {
+			return &c.LessonDeadlines[i].DueAt
+		}
+	}
+	return c.EndAt
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TClassCourse by.
+func (*TClassCourse) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":          {Column: "t_class_course.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"class_id":    {Column: "t_class_course.class_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"course_id":   {Column: "t_class_course.course_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"assigned_by": {Column: "t_class_course.assigned_by", Kind: queryspec.Int, Filter: true, Sort: true},
+		"start_at":    {Column: "t_class_course.start_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"end_at":      {Column: "t_class_course.end_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"created_at":  {Column: "t_class_course.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
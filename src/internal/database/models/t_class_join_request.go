@@ -0,0 +1,38 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+type TClassJoinRequest struct {
+	ID        int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ClassID   int64      `gorm:"column:class_id;not null;index:idx_class_join_request_class_status,priority:1" json:"class_id"`
+	UserID    int64      `gorm:"column:user_id;not null;index" json:"user_id"`
+	Status    string     `gorm:"column:status;size:20;not null;default:pending;index:idx_class_join_request_class_status,priority:2" json:"status"`
+	DecidedBy *int64     `gorm:"column:decided_by" json:"decided_by"`
+	DecidedAt *time.Time `gorm:"column:decided_at" json:"decided_at"`
+	Note      *string    `gorm:"column:note;type:text" json:"note"`
+	CreatedAt time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	User  *User   `gorm:"foreignKey:UserID;references:ID" json:"user,omitempty"`
+	Class *MClass `gorm:"foreignKey:ClassID;references:ID" json:"class,omitempty"`
+}
+
+func (*TClassJoinRequest) TableName() string {
+	return "t_class_join_request"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TClassJoinRequest by.
+func (*TClassJoinRequest) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "t_class_join_request.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"class_id":   {Column: "t_class_join_request.class_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"user_id":    {Column: "t_class_join_request.user_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"status":     {Column: "t_class_join_request.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"decided_by": {Column: "t_class_join_request.decided_by", Kind: queryspec.Int, Filter: true, Sort: true},
+		"decided_at": {Column: "t_class_join_request.decided_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"created_at": {Column: "t_class_join_request.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type TClassLessonDeadline struct {
+	ID            int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ClassCourseID int64     `gorm:"column:class_course_id;not null;uniqueIndex:idx_class_lesson_deadline" json:"class_course_id"`
+	LessonID      int64     `gorm:"column:lesson_id;not null;uniqueIndex:idx_class_lesson_deadline" json:"lesson_id"`
+	DueAt         time.Time `gorm:"column:due_at;not null" json:"due_at"`
+	CreatedAt     time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Lesson *MLesson `gorm:"foreignKey:LessonID;references:ID" json:"lesson,omitempty"`
+}
+
+func (*TClassLessonDeadline) TableName() string {
+	return "t_class_lesson_deadline"
+}
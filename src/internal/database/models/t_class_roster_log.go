@@ -0,0 +1,36 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// TClassRosterLog is an append-only record of every change to a class's
+// students, teachers or join settings.
+type TClassRosterLog struct {
+	ID           int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ClassID      int64     `gorm:"column:class_id;not null;index:idx_class_roster_log_class" json:"class_id"`
+	UserID       *int64    `gorm:"column:user_id;index" json:"user_id"`
+	ActorID      *int64    `gorm:"column:actor_id" json:"actor_id"`
+	Action       string    `gorm:"column:action;size:50;not null" json:"action"`
+	OtherClassID *int64    `gorm:"column:other_class_id" json:"other_class_id"`
+	Note         *string   `gorm:"column:note;type:text" json:"note"`
+	CreatedAt    time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+}
+
+func (*TClassRosterLog) TableName() string {
+	return "t_class_roster_log"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TClassRosterLog by.
+func (*TClassRosterLog) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":             {Column: "t_class_roster_log.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"class_id":       {Column: "t_class_roster_log.class_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"user_id":        {Column: "t_class_roster_log.user_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"actor_id":       {Column: "t_class_roster_log.actor_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"action":         {Column: "t_class_roster_log.action", Kind: queryspec.String, Filter: true, Sort: true},
+		"other_class_id": {Column: "t_class_roster_log.other_class_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"created_at":     {Column: "t_class_roster_log.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -19,3 +19,13 @@ type TCodeAnswer struct {
 func (*TCodeAnswer) TableName() string {
 	return "t_code_answer"
 }
+
+// ParentColumn scopes answers through the school of the student who wrote
+// them, so shared courses do not expose one school's answers to another.
+func (*TCodeAnswer) ParentColumn() string {
+	return "t_code_answer.user_id"
+}
+
+func (*TCodeAnswer) Parent() any {
+	return &User{}
+}
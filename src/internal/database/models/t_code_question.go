@@ -7,8 +7,14 @@ type CodeQuestion struct {
 	SubLessonID  int64      `gorm:"column:sub_lesson_id" json:"sub_lesson_id"`
 	CodeQuestion string     `gorm:"column:code_question;type:text" json:"code_question"`
 	Image        string     `gorm:"type:text" json:"image"`
+	// ImageMediaID references the uploaded image; Image keeps its URL for
+	// older clients.
+	ImageMediaID *int64     `gorm:"column:image_media_id;index" json:"image_media_id"`
 	Score        int        `gorm:"column:score" json:"score"`
 	Hint         string     `gorm:"type:text" json:"hint"`
+	// Status is published for questions written by teachers and draft for
+	// generated ones until a teacher accepts them.
+	Status       string     `gorm:"column:status;size:20;not null;default:published;index" json:"status"`
 	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt    *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
@@ -17,8 +23,18 @@ type CodeQuestion struct {
 	CodeAnswers []TCodeAnswer `gorm:"foreignKey:CodeQuestionID;references:ID" json:"code_answers"`
 	EssayQuestions []EssayQuestion `gorm:"foreignKey:CodeQuestionID;references:ID" json:"essay_questions"`
 	CodeHistoryLogs []TCodeHistoryLogs `gorm:"foreignKey:CodeQuestionID;references:ID" json:"code_history_logs"`
+	ImageMedia *MMedia `gorm:"foreignKey:ImageMediaID;references:ID;constraint:OnDelete:SET NULL" json:"image_media,omitempty"`
 }
 
 func (*CodeQuestion) TableName() string {
 	return "t_code_question"
 }
+
+// ParentColumn scopes code questions through their sub-lesson's course.
+func (*CodeQuestion) ParentColumn() string {
+	return "t_code_question.sub_lesson_id"
+}
+
+func (*CodeQuestion) Parent() any {
+	return &MSubLesson{}
+}
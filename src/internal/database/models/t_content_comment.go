@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// TContentComment is review feedback left on a course, lesson or material.
+type TContentComment struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ContentType string    `gorm:"column:content_type;size:20;not null;index:idx_content_comment_content" json:"content_type"`
+	ContentID   int64     `gorm:"column:content_id;not null;index:idx_content_comment_content" json:"content_id"`
+	AuthorID    int64     `gorm:"column:author_id;not null" json:"author_id"`
+	Body        string    `gorm:"column:body;type:text;not null" json:"body"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Author *User `gorm:"foreignKey:AuthorID;references:ID" json:"author,omitempty"`
+}
+
+func (*TContentComment) TableName() string {
+	return "t_content_comment"
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+// TContentTransition is an append-only record of every editorial state change
+// of a course, lesson or material.
+type TContentTransition struct {
+	ID          int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ContentType string    `gorm:"column:content_type;size:20;not null;index:idx_content_transition_content" json:"content_type"`
+	ContentID   int64     `gorm:"column:content_id;not null;index:idx_content_transition_content" json:"content_id"`
+	Action      string    `gorm:"column:action;size:30;not null" json:"action"`
+	FromStatus  string    `gorm:"column:from_status;size:20;not null" json:"from_status"`
+	ToStatus    string    `gorm:"column:to_status;size:20;not null" json:"to_status"`
+	ActorID     int64     `gorm:"column:actor_id;not null" json:"actor_id"`
+	Comment     *string   `gorm:"column:comment;type:text" json:"comment"`
+	CreatedAt   time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Actor *User `gorm:"foreignKey:ActorID;references:ID" json:"actor,omitempty"`
+}
+
+func (*TContentTransition) TableName() string {
+	return "t_content_transition"
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"encoding/json"
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TCourseVersion is an immutable snapshot of a course taken when it is
+// published. Enrollments are pinned to one so later edits don't change the
+// course under students who already started it.
+type TCourseVersion struct {
+	ID           int64                              `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	CourseID     int64                              `gorm:"column:course_id;not null;uniqueIndex:uni_course_version" json:"course_id"`
+	Version      int                                `gorm:"column:version;not null;uniqueIndex:uni_course_version" json:"version"`
+	Snapshot     datatypes.JSONType[CourseSnapshot] `gorm:"column:snapshot;type:jsonb;not null" json:"snapshot"`
+	SubLessonIDs datatypes.JSONSlice[int64]         `gorm:"column:sub_lesson_ids;type:jsonb;not null" json:"sub_lesson_ids"`
+	PublishedBy  *int64                             `gorm:"column:published_by" json:"published_by"`
+	CreatedAt    time.Time                          `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Course *MCourse `gorm:"foreignKey:CourseID;references:ID;constraint:OnDelete:CASCADE" json:"course,omitempty"`
+}
+
+func (*TCourseVersion) TableName() string {
+	return "t_course_version"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TCourseVersion by.
+func (*TCourseVersion) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":           {Column: "t_course_version.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"version":      {Column: "t_course_version.version", Kind: queryspec.Int, Filter: true, Sort: true},
+		"published_by": {Column: "t_course_version.published_by", Kind: queryspec.Int, Filter: true, Sort: true},
+		"created_at":   {Column: "t_course_version.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
+
+// CourseSnapshot is the published outline and content of a course. Only
+// published lessons and materials are included.
+type CourseSnapshot struct {
+	CourseName   string           `json:"course_name"`
+	Description  string           `json:"description"`
+	ImgThumbnail string           `json:"img_thumbnail"`
+	Lessons      []LessonSnapshot `json:"lessons"`
+}
+
+type LessonSnapshot struct {
+	ID           int64               `json:"id"`
+	LevelID      int64               `json:"level_id"`
+	Title        string              `json:"title"`
+	Description  string              `json:"description"`
+	Position     int                 `json:"position"`
+	ImgThumbnail string              `json:"img_thumbnail"`
+	SubLessons   []SubLessonSnapshot `json:"sub_lessons"`
+}
+
+type SubLessonSnapshot struct {
+	ID            int64              `json:"id"`
+	Title         string             `json:"title"`
+	OrderPosition int                `json:"order_position"`
+	Materials     []MaterialSnapshot `json:"materials"`
+}
+
+type MaterialSnapshot struct {
+	ID    int64  `json:"id"`
+	Title string `json:"title"`
+	// Materials is the document of the material. Snapshots taken while
+	// materials were plain text hold a string.
+	Materials       json.RawMessage `json:"materials"`
+	URLVideo        string          `json:"url_video"`
+	VideoMediaID    *int64          `json:"video_media_id,omitempty"`
+	ContentPosition int             `json:"content_position"`
+	PromptLLM       string          `json:"prompt_llm"`
+	// PromptTemplateVersionID is the prompt template version the material
+	// was generated with.
+	PromptTemplateVersionID *int64 `json:"prompt_template_version_id,omitempty"`
+}
@@ -23,3 +23,13 @@ type TEssayAnswer struct {
 func (*TEssayAnswer) TableName() string {
 	return "t_essay_answer"
 }
+
+// ParentColumn scopes answers through the school of the student who wrote
+// them, so shared courses do not expose one school's answers to another.
+func (*TEssayAnswer) ParentColumn() string {
+	return "t_essay_answer.user_id"
+}
+
+func (*TEssayAnswer) Parent() any {
+	return &User{}
+}
@@ -10,6 +10,7 @@ type EssayQuestion struct {
 	Answer2        string     `gorm:"column:answer_2;type:text" json:"answer_2"`
 	Answer3        string     `gorm:"column:answer_3;type:text" json:"answer_3"`
 	Answer4        string     `gorm:"column:answer_4;type:text" json:"answer_4"`
+	Status         string     `gorm:"column:status;size:20;not null;default:published;index" json:"status"`
 	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt      *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
@@ -20,3 +21,12 @@ type EssayQuestion struct {
 func (*EssayQuestion) TableName() string {
 	return "t_essay_question"
 }
+
+// ParentColumn scopes essay questions through their code question's course.
+func (*EssayQuestion) ParentColumn() string {
+	return "t_essay_question.code_question_id"
+}
+
+func (*EssayQuestion) Parent() any {
+	return &CodeQuestion{}
+}
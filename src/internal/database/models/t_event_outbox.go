@@ -0,0 +1,38 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type TEventOutbox struct {
+	ID           int64          `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	EventName    string         `gorm:"column:event_name;size:100;not null;index:idx_event_outbox_event_name" json:"event_name"`
+	Payload      datatypes.JSON `gorm:"column:payload;type:jsonb;not null" json:"payload"`
+	Status       string         `gorm:"column:status;size:20;not null;default:pending;index:idx_event_outbox_pending,priority:1" json:"status"`
+	Attempts     int            `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	LastError    *string        `gorm:"column:last_error;type:text" json:"last_error"`
+	AvailableAt  time.Time      `gorm:"column:available_at;not null;index:idx_event_outbox_pending,priority:2" json:"available_at"`
+	DispatchedAt *time.Time     `gorm:"column:dispatched_at" json:"dispatched_at"`
+	CreatedAt    time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    *time.Time     `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (*TEventOutbox) TableName() string {
+	return "t_event_outbox"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TEventOutbox by.
+func (*TEventOutbox) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":            {Column: "t_event_outbox.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"event_name":    {Column: "t_event_outbox.event_name", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":        {Column: "t_event_outbox.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"attempts":      {Column: "t_event_outbox.attempts", Kind: queryspec.Int, Filter: true, Sort: true},
+		"available_at":  {Column: "t_event_outbox.available_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"dispatched_at": {Column: "t_event_outbox.dispatched_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"created_at":    {Column: "t_event_outbox.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -1,16 +1,41 @@
 package models
 
-import "time"
+import (
+	"jk-api/internal/queryspec"
+	"time"
 
+	"gorm.io/datatypes"
+)
+
+// TGenerationHistory logs one run of the generation pipeline, successful or
+// not. Result holds the raw provider output and GenerationTime how long the
+// provider took, in milliseconds.
 type TGenerationHistory struct {
-	ID             int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
-	SubLessonID    int64      `gorm:"column:sub_lesson_id" json:"sub_lesson_id"`
-	TopicUsed      string     `gorm:"column:topic_used;type:text" json:"topic_used"`
-	Result         string     `gorm:"type:text" json:"result"`
-	GenerationTime int        `gorm:"column:generation_time" json:"generation_time"`
-	IsActive       bool       `gorm:"column:isactive;default:true" json:"isactive"`
-	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt      *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	ID             int64  `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	SubLessonID    int64  `gorm:"column:sub_lesson_id" json:"sub_lesson_id"`
+	TopicUsed      string `gorm:"column:topic_used;type:text" json:"topic_used"`
+	Result         string `gorm:"type:text" json:"result"`
+	GenerationTime int    `gorm:"column:generation_time" json:"generation_time"`
+	// Kind is what was generated; essay questions are generated for
+	// CodeQuestionID.
+	Kind           string `gorm:"column:kind;size:20;index" json:"kind"`
+	CodeQuestionID *int64 `gorm:"column:code_question_id" json:"code_question_id"`
+	// Prompt is the body of PromptTemplateVersionID rendered with Variables,
+	// the values the caller filled in.
+	PromptTemplateVersionID *int64            `gorm:"column:prompt_template_version_id;index" json:"prompt_template_version_id"`
+	Variables               datatypes.JSONMap `gorm:"column:variables;type:jsonb" json:"variables"`
+	Prompt                  string            `gorm:"column:prompt;type:text" json:"prompt"`
+	Provider                string            `gorm:"column:provider;size:50" json:"provider"`
+	Model                   string            `gorm:"column:model;size:100" json:"model"`
+	Status                  string            `gorm:"column:status;size:20;index" json:"status"`
+	Error                   string            `gorm:"column:error;type:text" json:"error,omitempty"`
+	// ItemIDs are the drafts written by the run, in the table Kind points at.
+	ItemIDs    datatypes.JSONSlice[int64] `gorm:"column:item_ids;type:jsonb" json:"item_ids"`
+	CreatedBy  *int64                     `gorm:"column:created_by" json:"created_by"`
+	ReviewedBy *int64                     `gorm:"column:reviewed_by" json:"reviewed_by"`
+	IsActive   bool                       `gorm:"column:isactive;default:true" json:"isactive"`
+	CreatedAt  time.Time                  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt  *time.Time                 `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 	// Foreign Key Relationships
 	SubLesson *MSubLesson `gorm:"foreignKey:SubLessonID;references:ID" json:"sub_lesson"`
 }
@@ -18,3 +43,16 @@ type TGenerationHistory struct {
 func (*TGenerationHistory) TableName() string {
 	return "t_generation_history"
 }
+
+// QueryFields lists the fields list endpoints may filter and sort TGenerationHistory by.
+func (*TGenerationHistory) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":            {Column: "t_generation_history.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"sub_lesson_id": {Column: "t_generation_history.sub_lesson_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"kind":          {Column: "t_generation_history.kind", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":        {Column: "t_generation_history.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"provider":      {Column: "t_generation_history.provider", Kind: queryspec.String, Filter: true},
+		"created_by":    {Column: "t_generation_history.created_by", Kind: queryspec.Int, Filter: true},
+		"created_at":    {Column: "t_generation_history.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// TGuardianLink connects a guardian to a student. The guardian invites, and
+// the student or the student's school accepts. Only accepted links grant
+// access to the student's data.
+type TGuardianLink struct {
+	ID           int64      `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	GuardianID   int64      `gorm:"column:guardian_id;not null;uniqueIndex:uni_guardian_link,priority:1" json:"guardian_id"`
+	StudentID    int64      `gorm:"column:student_id;not null;uniqueIndex:uni_guardian_link,priority:2;index" json:"student_id"`
+	Relationship string     `gorm:"column:relationship;size:50" json:"relationship"`
+	Status       string     `gorm:"column:status;size:20;not null;default:pending;index" json:"status"`
+	DecidedBy    *int64     `gorm:"column:decided_by" json:"decided_by"`
+	DecidedAt    *time.Time `gorm:"column:decided_at" json:"decided_at"`
+	// LastDigestWeek is the ISO week (e.g. 2026-W42) of the last weekly
+	// digest sent for this link.
+	LastDigestWeek *string    `gorm:"column:last_digest_week;size:10" json:"last_digest_week"`
+	CreatedAt      time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	Guardian *User `gorm:"foreignKey:GuardianID;references:ID;constraint:OnDelete:CASCADE" json:"guardian,omitempty"`
+	Student  *User `gorm:"foreignKey:StudentID;references:ID;constraint:OnDelete:CASCADE" json:"student,omitempty"`
+}
+
+func (*TGuardianLink) TableName() string {
+	return "t_guardian_link"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TGuardianLink by.
+func (*TGuardianLink) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":           {Column: "t_guardian_link.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"guardian_id":  {Column: "t_guardian_link.guardian_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"student_id":   {Column: "t_guardian_link.student_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"relationship": {Column: "t_guardian_link.relationship", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":       {Column: "t_guardian_link.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"created_at":   {Column: "t_guardian_link.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// TMediaUpload is a resumable upload. Chunks are appended to a staging file
+// until Offset reaches Size, then the file becomes an MMedia.
+type TMediaUpload struct {
+	ID         string     `gorm:"primaryKey;size:36" json:"id"`
+	Filename   string     `gorm:"column:filename;size:255" json:"filename"`
+	Size       int64      `gorm:"column:size;not null" json:"size"`
+	Offset     int64      `gorm:"column:offset;not null;default:0" json:"offset"`
+	Protected  bool       `gorm:"column:protected;not null;default:false" json:"protected"`
+	UploadedBy int64      `gorm:"column:uploaded_by;not null;index" json:"uploaded_by"`
+	MediaID    *int64     `gorm:"column:media_id" json:"media_id"`
+	ExpiresAt  time.Time  `gorm:"column:expires_at;not null;index" json:"expires_at"`
+	CreatedAt  time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt  *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	Media *MMedia `gorm:"foreignKey:MediaID;references:ID;constraint:OnDelete:SET NULL" json:"media,omitempty"`
+}
+
+func (*TMediaUpload) TableName() string {
+	return "t_media_upload"
+}
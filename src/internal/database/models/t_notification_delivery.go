@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TNotificationDelivery is one push or omni-channel send of a notification
+// to a single user. Rows are keyed on the message's dedupe key so a retried
+// event queues each send once.
+type TNotificationDelivery struct {
+	ID            int64             `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	UserID        int64             `gorm:"column:user_id;not null;uniqueIndex:idx_notification_delivery_dedupe,priority:1" json:"user_id"`
+	Channel       string            `gorm:"column:channel;size:20;not null;uniqueIndex:idx_notification_delivery_dedupe,priority:2" json:"channel"`
+	DedupeKey     *string           `gorm:"column:dedupe_key;size:100;uniqueIndex:idx_notification_delivery_dedupe,priority:3" json:"dedupe_key"`
+	Type          string            `gorm:"column:type;size:50;not null" json:"type"`
+	Title         string            `gorm:"column:title;size:255;not null" json:"title"`
+	Body          string            `gorm:"column:body;type:text" json:"body"`
+	Priority      string            `gorm:"column:priority;size:20;not null;default:info" json:"priority"`
+	Data          datatypes.JSONMap `gorm:"column:data;type:jsonb" json:"data"`
+	Status        string            `gorm:"column:status;size:20;not null;default:pending;index:idx_notification_delivery_pending,priority:1" json:"status"`
+	Attempts      int               `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	LastError     *string           `gorm:"column:last_error;type:text" json:"last_error"`
+	NextAttemptAt time.Time         `gorm:"column:next_attempt_at;not null;index:idx_notification_delivery_pending,priority:2" json:"next_attempt_at"`
+	DeliveredAt   *time.Time        `gorm:"column:delivered_at" json:"delivered_at"`
+	CreatedAt     time.Time         `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt     *time.Time        `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+}
+
+func (*TNotificationDelivery) TableName() string {
+	return "t_notification_delivery"
+}
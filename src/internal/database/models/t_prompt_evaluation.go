@@ -0,0 +1,36 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// TPromptEvaluation is a note a teacher left on how well a version of a
+// prompt template works, optionally with a rating from 1 to 5.
+type TPromptEvaluation struct {
+	ID         int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	TemplateID int64     `gorm:"column:template_id;not null;index" json:"template_id"`
+	VersionID  int64     `gorm:"column:version_id;not null;index" json:"version_id"`
+	Rating     *int      `gorm:"column:rating" json:"rating"`
+	Notes      string    `gorm:"column:notes;type:text;not null" json:"notes"`
+	CreatedBy  int64     `gorm:"column:created_by;not null" json:"created_by"`
+	CreatedAt  time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Template *MPromptTemplate        `gorm:"foreignKey:TemplateID;references:ID;constraint:OnDelete:CASCADE" json:"template,omitempty"`
+	Version  *TPromptTemplateVersion `gorm:"foreignKey:VersionID;references:ID;constraint:OnDelete:CASCADE" json:"version,omitempty"`
+}
+
+func (*TPromptEvaluation) TableName() string {
+	return "t_prompt_evaluation"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TPromptEvaluation by.
+func (*TPromptEvaluation) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "t_prompt_evaluation.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"version_id": {Column: "t_prompt_evaluation.version_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"rating":     {Column: "t_prompt_evaluation.rating", Kind: queryspec.Int, Filter: true, Sort: true},
+		"created_by": {Column: "t_prompt_evaluation.created_by", Kind: queryspec.Int, Filter: true},
+		"created_at": {Column: "t_prompt_evaluation.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
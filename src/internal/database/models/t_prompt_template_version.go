@@ -0,0 +1,46 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TPromptTemplateVersion is one immutable revision of a prompt template.
+// Materials and generation runs reference the version they were made with.
+type TPromptTemplateVersion struct {
+	ID         int64                               `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	TemplateID int64                               `gorm:"column:template_id;not null;uniqueIndex:uni_prompt_template_version" json:"template_id"`
+	Version    int                                 `gorm:"column:version;not null;uniqueIndex:uni_prompt_template_version" json:"version"`
+	Body       string                              `gorm:"column:body;type:text;not null" json:"body"`
+	Variables  datatypes.JSONSlice[PromptVariable] `gorm:"column:variables;type:jsonb;not null" json:"variables"`
+	ChangeNote string                              `gorm:"column:change_note;type:text" json:"change_note"`
+	CreatedBy  *int64                              `gorm:"column:created_by" json:"created_by"`
+	CreatedAt  time.Time                           `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	Template *MPromptTemplate `gorm:"foreignKey:TemplateID;references:ID;constraint:OnDelete:CASCADE" json:"template,omitempty"`
+}
+
+func (*TPromptTemplateVersion) TableName() string {
+	return "t_prompt_template_version"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TPromptTemplateVersion by.
+func (*TPromptTemplateVersion) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":         {Column: "t_prompt_template_version.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"version":    {Column: "t_prompt_template_version.version", Kind: queryspec.Int, Filter: true, Sort: true},
+		"created_by": {Column: "t_prompt_template_version.created_by", Kind: queryspec.Int, Filter: true},
+		"created_at": {Column: "t_prompt_template_version.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
+
+// PromptVariable is a placeholder of a template body, written {{.name}}.
+// Type decides where its value comes from and how it is checked.
+type PromptVariable struct {
+	Name        string `json:"name"`
+	Type        string `json:"type"`
+	Required    bool   `json:"required"`
+	Description string `json:"description,omitempty"`
+}
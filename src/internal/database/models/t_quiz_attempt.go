@@ -0,0 +1,87 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TQuizAttempt is one attempt of a student at a quiz. ItemOrder and
+// OptionOrder fix the order the student sees items and, per item, options
+// in, shuffled when the attempt starts if the quiz asks for it.
+type TQuizAttempt struct {
+	ID          int64                               `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	QuizID      int64                               `gorm:"column:quiz_id;not null;uniqueIndex:uni_quiz_attempt_number" json:"quiz_id"`
+	UserID      int64                               `gorm:"column:user_id;not null;uniqueIndex:uni_quiz_attempt_number;index" json:"user_id"`
+	Number      int                                 `gorm:"column:number;not null;uniqueIndex:uni_quiz_attempt_number" json:"number"`
+	Status      string                              `gorm:"column:status;size:20;not null;index" json:"status"`
+	ItemOrder   datatypes.JSONSlice[int64]          `gorm:"column:item_order;type:jsonb" json:"item_order"`
+	OptionOrder datatypes.JSONType[map[int64][]int] `gorm:"column:option_order;type:jsonb" json:"-"`
+	Score       int                                 `gorm:"column:score;not null;default:0" json:"score"`
+	MaxScore    int                                 `gorm:"column:max_score;not null;default:0" json:"max_score"`
+	// SchoolID is the school of the student, whose teachers may see the
+	// attempt.
+	SchoolID    *int64     `gorm:"column:school_id;index" json:"school_id"`
+	StartedAt   time.Time  `gorm:"column:started_at;not null" json:"started_at"`
+	SubmittedAt *time.Time `gorm:"column:submitted_at" json:"submitted_at"`
+	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt   time.Time  `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Foreign Key Relationships
+	Quiz    *MQuiz        `gorm:"foreignKey:QuizID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	User    *User         `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	Answers []TQuizAnswer `gorm:"foreignKey:AttemptID;references:ID" json:"answers,omitempty"`
+}
+
+func (*TQuizAttempt) TableName() string {
+	return "t_quiz_attempt"
+}
+
+func (*TQuizAttempt) SchoolColumn() string {
+	return "t_quiz_attempt.school_id"
+}
+
+func (a *TQuizAttempt) AssignSchool(schoolID int64) {
+	a.SchoolID = &schoolID
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TQuizAttempt by.
+func (*TQuizAttempt) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":           {Column: "t_quiz_attempt.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"user_id":      {Column: "t_quiz_attempt.user_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"number":       {Column: "t_quiz_attempt.number", Kind: queryspec.Int, Filter: true, Sort: true},
+		"status":       {Column: "t_quiz_attempt.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"score":        {Column: "t_quiz_attempt.score", Kind: queryspec.Int, Filter: true, Sort: true},
+		"started_at":   {Column: "t_quiz_attempt.started_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"submitted_at": {Column: "t_quiz_attempt.submitted_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
+
+// TQuizAnswer is the graded answer to one item of an attempt.
+type TQuizAnswer struct {
+	ID        int64                            `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	AttemptID int64                            `gorm:"column:attempt_id;not null;uniqueIndex:uni_quiz_answer_item" json:"attempt_id"`
+	ItemID    int64                            `gorm:"column:item_id;not null;uniqueIndex:uni_quiz_answer_item;index" json:"item_id"`
+	Response  datatypes.JSONType[QuizResponse] `gorm:"column:response;type:jsonb" json:"response"`
+	IsCorrect bool                             `gorm:"column:is_correct;default:false" json:"is_correct"`
+	Points    int                              `gorm:"column:points;not null;default:0" json:"points"`
+	CreatedAt time.Time                        `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+
+	// Foreign Key Relationships
+	Attempt *TQuizAttempt `gorm:"foreignKey:AttemptID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+	Item    *MQuizItem    `gorm:"foreignKey:ItemID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (*TQuizAnswer) TableName() string {
+	return "t_quiz_answer"
+}
+
+// QuizResponse is what a student answered: options for choice and
+// true/false items, a number for numeric items and text for short answers.
+type QuizResponse struct {
+	OptionIDs []int    `json:"option_ids,omitempty"`
+	Number    *float64 `json:"number,omitempty"`
+	Text      *string  `json:"text,omitempty"`
+}
@@ -9,6 +9,9 @@ type TStudentCourse struct {
 	ProgressPercentage float64    `gorm:"column:progress_percentage" json:"progress_percentage"`
 	TotalScore         int        `gorm:"column:total_score" json:"total_score"`
 	BadgeID            int64      `gorm:"column:badge_id" json:"badge_id"`
+	// CourseVersionID pins the enrollment to the course as it was published.
+	// Legacy enrollments without one follow the live course.
+	CourseVersionID    *int64     `gorm:"column:course_version_id;index" json:"course_version_id"`
 	CreatedAt          time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
 	UpdatedAt          *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 	DeletedAt          *time.Time `gorm:"column:deleted_at" json:"deleted_at"`
@@ -17,6 +20,7 @@ type TStudentCourse struct {
 	User   *User           `gorm:"foreignKey:UserID;references:ID" json:"user"`
 	Course *MCourse        `gorm:"foreignKey:CourseID;references:ID" json:"course"`
 	Badge  *MBadgeSettings `gorm:"foreignKey:BadgeID;references:ID" json:"badge"`
+	CourseVersion *TCourseVersion `gorm:"foreignKey:CourseVersionID;references:ID" json:"course_version,omitempty"`
 }
 
 func (*TStudentCourse) TableName() string {
@@ -3,18 +3,31 @@ package models
 import "time"
 
 type TStudentProgress struct {
-	ID          int64      `gorm:"primaryKey;autoIncrement:true;type:serial" json:"id"`
-	UserID      int64      `gorm:"column:user_id" json:"user_id"`
-	SubLessonID int64      `gorm:"column:sub_lesson_id" json:"sub_lesson_id"`
-	Status      string     `gorm:"size:50" json:"status"`
-	CreatedAt   time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
-	UpdatedAt   *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+	ID           int64      `gorm:"primaryKey;autoIncrement:true;type:serial" json:"id"`
+	UserID       int64      `gorm:"column:user_id;uniqueIndex:idx_student_progress_user_sub_lesson" json:"user_id"`
+	SubLessonID  int64      `gorm:"column:sub_lesson_id;uniqueIndex:idx_student_progress_user_sub_lesson" json:"sub_lesson_id"`
+	Status       string     `gorm:"size:50;not null;default:not_started" json:"status"`
+	StartedAt    *time.Time `gorm:"column:started_at" json:"started_at"`
+	CompletedAt  *time.Time `gorm:"column:completed_at" json:"completed_at"`
+	LastViewedAt *time.Time `gorm:"column:last_viewed_at" json:"last_viewed_at"`
+	IsLate       bool       `gorm:"column:is_late;not null;default:false" json:"is_late"`
+	CreatedAt    time.Time  `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt    *time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
 
 	// Foreign Key Relationships
-	User      *User      `gorm:"foreignKey:UserID;references:ID" json:"user"`
+	User      *User       `gorm:"foreignKey:UserID;references:ID" json:"user"`
 	SubLesson *MSubLesson `gorm:"foreignKey:SubLessonID;references:ID" json:"sub_lesson"`
 }
 
 func (*TStudentProgress) TableName() string {
 	return "t_student_progress"
 }
+
+// ParentColumn scopes progress through the school of its student.
+func (*TStudentProgress) ParentColumn() string {
+	return "t_student_progress.user_id"
+}
+
+func (*TStudentProgress) Parent() any {
+	return &User{}
+}
@@ -0,0 +1,57 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+)
+
+// TTutorConversation is a student's chat with the AI tutor about a
+// sub-lesson, optionally about one material or code question of it.
+// TokensUsed adds up the tokens of every reply.
+type TTutorConversation struct {
+	ID             int64  `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	UserID         int64  `gorm:"column:user_id;not null;index" json:"user_id"`
+	SubLessonID    int64  `gorm:"column:sub_lesson_id;not null;index" json:"sub_lesson_id"`
+	MaterialID     *int64 `gorm:"column:material_id" json:"material_id"`
+	CodeQuestionID *int64 `gorm:"column:code_question_id" json:"code_question_id"`
+	Title          string `gorm:"column:title;size:150" json:"title"`
+	TokensUsed     int    `gorm:"column:tokens_used;not null;default:0" json:"tokens_used"`
+	// SchoolID is the school of the student, whose teachers may read the
+	// transcript.
+	SchoolID  *int64    `gorm:"column:school_id;index" json:"school_id"`
+	CreatedAt time.Time `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt time.Time `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	// Foreign Key Relationships
+	User         *User           `gorm:"foreignKey:UserID;references:ID;constraint:OnDelete:CASCADE" json:"user,omitempty"`
+	SubLesson    *MSubLesson     `gorm:"foreignKey:SubLessonID;references:ID;constraint:OnDelete:CASCADE" json:"sub_lesson,omitempty"`
+	Material     *MMaterials     `gorm:"foreignKey:MaterialID;references:ID;constraint:OnDelete:SET NULL" json:"-"`
+	CodeQuestion *CodeQuestion   `gorm:"foreignKey:CodeQuestionID;references:ID;constraint:OnDelete:SET NULL" json:"-"`
+	Messages     []TTutorMessage `gorm:"foreignKey:ConversationID;references:ID" json:"messages,omitempty"`
+}
+
+func (*TTutorConversation) TableName() string {
+	return "t_tutor_conversation"
+}
+
+func (*TTutorConversation) SchoolColumn() string {
+	return "t_tutor_conversation.school_id"
+}
+
+func (c *TTutorConversation) AssignSchool(schoolID int64) {
+	c.SchoolID = &schoolID
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TTutorConversation by.
+func (*TTutorConversation) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":               {Column: "t_tutor_conversation.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"user_id":          {Column: "t_tutor_conversation.user_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"sub_lesson_id":    {Column: "t_tutor_conversation.sub_lesson_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"material_id":      {Column: "t_tutor_conversation.material_id", Kind: queryspec.Int, Filter: true},
+		"code_question_id": {Column: "t_tutor_conversation.code_question_id", Kind: queryspec.Int, Filter: true},
+		"tokens_used":      {Column: "t_tutor_conversation.tokens_used", Kind: queryspec.Int, Filter: true, Sort: true},
+		"created_at":       {Column: "t_tutor_conversation.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":       {Column: "t_tutor_conversation.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -0,0 +1,28 @@
+package models
+
+import "time"
+
+// TTutorMessage is one message of a tutor conversation. UserID repeats the
+// student of the conversation so their usage can be limited without a
+// join. Token counts are only set on replies; a reply the provider failed
+// to finish keeps its Error.
+type TTutorMessage struct {
+	ID               int64     `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	ConversationID   int64     `gorm:"column:conversation_id;not null;index" json:"conversation_id"`
+	UserID           int64     `gorm:"column:user_id;not null;index:idx_tutor_message_user_created" json:"user_id"`
+	Role             string    `gorm:"column:role;size:20;not null" json:"role"`
+	Content          string    `gorm:"column:content;type:text" json:"content"`
+	PromptTokens     int       `gorm:"column:prompt_tokens;not null;default:0" json:"prompt_tokens"`
+	CompletionTokens int       `gorm:"column:completion_tokens;not null;default:0" json:"completion_tokens"`
+	Provider         string    `gorm:"column:provider;size:50" json:"provider,omitempty"`
+	Model            string    `gorm:"column:model;size:100" json:"model,omitempty"`
+	Error            string    `gorm:"column:error;type:text" json:"error,omitempty"`
+	CreatedAt        time.Time `gorm:"column:created_at;autoCreateTime;index:idx_tutor_message_user_created" json:"created_at"`
+
+	// Foreign Key Relationships
+	Conversation *TTutorConversation `gorm:"foreignKey:ConversationID;references:ID;constraint:OnDelete:CASCADE" json:"-"`
+}
+
+func (*TTutorMessage) TableName() string {
+	return "t_tutor_message"
+}
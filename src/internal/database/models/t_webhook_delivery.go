@@ -0,0 +1,47 @@
+package models
+
+import (
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type TWebhookDelivery struct {
+	ID             int64          `gorm:"primaryKey;autoIncrement:true" json:"id"`
+	SubscriptionID int64          `gorm:"column:subscription_id;not null;index:idx_webhook_delivery_subscription;uniqueIndex:idx_webhook_delivery_outbox,priority:1,where:replay_of_id IS NULL" json:"subscription_id"`
+	OutboxID       *int64         `gorm:"column:outbox_id;uniqueIndex:idx_webhook_delivery_outbox,priority:2" json:"outbox_id"`
+	ReplayOfID     *int64         `gorm:"column:replay_of_id" json:"replay_of_id"`
+	EventName      string         `gorm:"column:event_name;size:100;not null" json:"event_name"`
+	Payload        datatypes.JSON `gorm:"column:payload;type:jsonb;not null" json:"payload"`
+	Status         string         `gorm:"column:status;size:20;not null;default:pending;index:idx_webhook_delivery_pending,priority:1" json:"status"`
+	Attempts       int            `gorm:"column:attempts;not null;default:0" json:"attempts"`
+	ResponseStatus *int           `gorm:"column:response_status" json:"response_status"`
+	LastError      *string        `gorm:"column:last_error;type:text" json:"last_error"`
+	NextAttemptAt  time.Time      `gorm:"column:next_attempt_at;not null;index:idx_webhook_delivery_pending,priority:2" json:"next_attempt_at"`
+	DeliveredAt    *time.Time     `gorm:"column:delivered_at" json:"delivered_at"`
+	CreatedAt      time.Time      `gorm:"column:created_at;autoCreateTime" json:"created_at"`
+	UpdatedAt      *time.Time     `gorm:"column:updated_at;autoUpdateTime" json:"updated_at"`
+
+	Subscription *MWebhookSubscription `gorm:"foreignKey:SubscriptionID" json:"subscription,omitempty"`
+}
+
+func (*TWebhookDelivery) TableName() string {
+	return "t_webhook_delivery"
+}
+
+// QueryFields lists the fields list endpoints may filter and sort TWebhookDelivery by.
+func (*TWebhookDelivery) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":              {Column: "t_webhook_delivery.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"subscription_id": {Column: "t_webhook_delivery.subscription_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"outbox_id":       {Column: "t_webhook_delivery.outbox_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"event_name":      {Column: "t_webhook_delivery.event_name", Kind: queryspec.String, Filter: true, Sort: true},
+		"status":          {Column: "t_webhook_delivery.status", Kind: queryspec.String, Filter: true, Sort: true},
+		"attempts":        {Column: "t_webhook_delivery.attempts", Kind: queryspec.Int, Filter: true, Sort: true},
+		"response_status": {Column: "t_webhook_delivery.response_status", Kind: queryspec.Int, Filter: true, Sort: true},
+		"next_attempt_at": {Column: "t_webhook_delivery.next_attempt_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"delivered_at":    {Column: "t_webhook_delivery.delivered_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"created_at":      {Column: "t_webhook_delivery.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
@@ -1,6 +1,7 @@
 package models
 
 import (
+	"jk-api/internal/queryspec"
 	"fmt"
 	"time"
 
@@ -14,6 +15,10 @@ type User struct {
 	ID     			  int64 		 `gorm:"primaryKey;autoIncrement:false;type:bigint;default:nextval('users_seq'::regclass)" json:"id"`
 	RoleID 			  int64 		 `gorm:"column:role_id" json:"role_id"`
 	ClassID           *int64         `gorm:"column:class_id" json:"class_id"`
+	SchoolID          *int64         `gorm:"column:school_id;index:idx_users_school_id" json:"school_id"`
+	DepartmentID      *int64         `gorm:"column:department_id;index:idx_users_department_id" json:"department_id"`
+	// ScopeDepartmentID limits what the user may manage to a department subtree.
+	ScopeDepartmentID *int64         `gorm:"column:scope_department_id" json:"scope_department_id"`
 	Code              *string        `gorm:"column:code;size:50;unique;index:idx_users_code" json:"code"`
 	Name              string         `gorm:"column:name;size:255;not null;index:idx_users_name" json:"name"`
 	Email             string         `gorm:"column:email;size:255;not null;unique;index:idx_users_email" json:"email"`
@@ -30,12 +35,35 @@ type User struct {
 	HasRoles []Role `gorm:"many2many:user_has_roles;constraint:OnDelete:CASCADE;" json:"has_roles"`
 	HasClass *MClass `gorm:"foreignKey:ClassID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"class"`
 	TeachingClasses []MClass `gorm:"many2many:m_class_teachers;" json:"teaching_classes"`
+	School *MSchool `gorm:"foreignKey:SchoolID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"school,omitempty"`
+	Department *Department `gorm:"foreignKey:DepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"department,omitempty"`
+	ScopeDepartment *Department `gorm:"foreignKey:ScopeDepartmentID;references:ID;constraint:OnUpdate:CASCADE,OnDelete:SET NULL;" json:"scope_department,omitempty"`
 }
 
 func (*User) TableName() string {
 	return TableNameUser
 }
 
+func (*User) SchoolColumn() string {
+	return "users.school_id"
+}
+
+func (u *User) AssignSchool(schoolID int64) {
+	u.SchoolID = &schoolID
+}
+
+// DepartmentCondition also matches students through the department of their
+// class.
+func (*User) DepartmentCondition(subtree string) string {
+	return "(users.department_id IN (" + subtree + ") OR users.class_id IN (SELECT id FROM m_class WHERE department_id IN (" + subtree + ")))"
+}
+
+func (u *User) AssignDepartment(departmentID int64) {
+	if u.DepartmentID == nil && u.ClassID == nil {
+		u.DepartmentID = &departmentID
+	}
+}
+
 func (u *User) GenerateUserCode() string {
 	prefix := "KRY"
 	code := fmt.Sprintf("%s%04d", prefix, u.ID)
@@ -61,3 +89,21 @@ func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
 
 	return nil
 }
+
+// QueryFields lists the fields list endpoints may filter and sort User by.
+func (*User) QueryFields() queryspec.Fields {
+	return queryspec.Fields{
+		"id":                   {Column: "users.id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"role_id":              {Column: "users.role_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"class_id":             {Column: "users.class_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"school_id":            {Column: "users.school_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"department_id":        {Column: "users.department_id", Kind: queryspec.Int, Filter: true, Sort: true},
+		"code":                 {Column: "users.code", Kind: queryspec.String, Filter: true, Sort: true},
+		"name":                 {Column: "users.name", Kind: queryspec.String, Filter: true, Sort: true},
+		"email":                {Column: "users.email", Kind: queryspec.String, Filter: true, Sort: true},
+		"is_approved_by_admin": {Column: "users.is_approved_by_admin", Kind: queryspec.Bool, Filter: true},
+		"isactive":             {Column: "users.isactive", Kind: queryspec.Bool, Filter: true},
+		"created_at":           {Column: "users.created_at", Kind: queryspec.Time, Filter: true, Sort: true},
+		"updated_at":           {Column: "users.updated_at", Kind: queryspec.Time, Filter: true, Sort: true},
+	}
+}
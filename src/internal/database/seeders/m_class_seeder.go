@@ -42,5 +42,17 @@ func SeedClasses(db *gorm.DB) error {
 		},
 	}
 
+	var schools []models.MSchool
+	if err := db.Find(&schools).Error; err != nil {
+		return err
+	}
+	for i := range classes {
+		for _, school := range schools {
+			if school.Name == classes[i].SchoolName {
+				classes[i].SchoolID = &school.ID
+			}
+		}
+	}
+
 	return db.Create(&classes).Error
 }
@@ -1,6 +1,7 @@
 package seeders
 
 import (
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 
 	"gorm.io/gorm"
@@ -19,6 +20,7 @@ func SeedCourses(db *gorm.DB) error {
 			CourseName:   "Basic Programming",
 			Description:  "Belajar dasar-dasar pemrograman dari nol.",
 			ImgThumbnail: "basic_programming.png",
+			Status:       constant.ContentPublished,
 			Published:    true,
 			IsActive:     true,
 		},
@@ -26,6 +28,7 @@ func SeedCourses(db *gorm.DB) error {
 			CourseName:   "Web Development",
 			Description:  "Mempelajari HTML, CSS, dan JavaScript untuk membuat website.",
 			ImgThumbnail: "web_dev.png",
+			Status:       constant.ContentPublished,
 			Published:    true,
 			IsActive:     true,
 		},
@@ -33,6 +36,7 @@ func SeedCourses(db *gorm.DB) error {
 			CourseName:   "Backend Development (Golang)",
 			Description:  "Belajar membuat API menggunakan Golang dan Fiber.",
 			ImgThumbnail: "golang_backend.png",
+			Status:       constant.ContentPublished,
 			Published:    true,
 			IsActive:     true,
 		},
@@ -40,6 +44,7 @@ func SeedCourses(db *gorm.DB) error {
 			CourseName:   "Mobile Development (Flutter)",
 			Description:  "Membuat aplikasi mobile menggunakan Flutter.",
 			ImgThumbnail: "flutter.png",
+			Status:       constant.ContentDraft,
 			Published:    false,
 			IsActive:     true,
 		},
@@ -47,6 +52,7 @@ func SeedCourses(db *gorm.DB) error {
 			CourseName:   "Database Design",
 			Description:  "Mempelajari perancangan database menggunakan PostgreSQL.",
 			ImgThumbnail: "database.png",
+			Status:       constant.ContentPublished,
 			Published:    true,
 			IsActive:     true,
 		},
@@ -1,6 +1,7 @@
 package seeders
 
 import (
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 
 	"gorm.io/gorm"
@@ -41,6 +42,12 @@ func SeedLessons(db *gorm.DB) error {
 		},
 	}
 
+	// Data contoh langsung terbit supaya bisa dibuka siswa
+	for i := range lessons {
+		lessons[i].Status = constant.ContentPublished
+		lessons[i].Published = true
+	}
+
 	for _, lesson := range lessons {
 		if err := db.Create(&lesson).Error; err != nil {
 			return err
@@ -1,7 +1,9 @@
 package seeders
 
 import (
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
+	"jk-api/internal/richtext"
 
 	"gorm.io/gorm"
 )
@@ -18,7 +20,7 @@ func SeedMaterials(db *gorm.DB) error {
 		{
 			SubLessonID:     1,
 			Title:           "Pengantar Materi",
-			Materials:       "Materi dasar untuk memahami konsep awal.",
+			MaterialsText:   "Materi dasar untuk memahami konsep awal.",
 			URLVideo:        "https://www.youtube.com/watch?v=video1",
 			ContentPosition: 1,
 			PromptLLM:       "Jelaskan materi ini dengan bahasa sederhana",
@@ -26,7 +28,7 @@ func SeedMaterials(db *gorm.DB) error {
 		{
 			SubLessonID:     1,
 			Title:           "Pendalaman Materi",
-			Materials:       "Penjelasan lebih dalam terkait materi sebelumnya.",
+			MaterialsText:   "Penjelasan lebih dalam terkait materi sebelumnya.",
 			URLVideo:        "https://www.youtube.com/watch?v=video2",
 			ContentPosition: 2,
 			PromptLLM:       "Buatkan contoh kasus dari materi ini",
@@ -34,13 +36,24 @@ func SeedMaterials(db *gorm.DB) error {
 		{
 			SubLessonID:     2,
 			Title:           "Latihan Pemahaman",
-			Materials:       "Latihan soal untuk menguji pemahaman.",
+			MaterialsText:   "Latihan soal untuk menguji pemahaman.",
 			URLVideo:        "",
 			ContentPosition: 1,
 			PromptLLM:       "Buatkan soal latihan dari materi ini",
 		},
 	}
 
+	// Data contoh langsung terbit supaya bisa dibuka siswa
+	for i := range materials {
+		doc, err := richtext.FromText(materials[i].MaterialsText).JSON()
+		if err != nil {
+			return err
+		}
+		materials[i].Materials = doc
+		materials[i].Status = constant.ContentPublished
+		materials[i].Published = true
+	}
+
 	for _, m := range materials {
 		var existing models.MMaterials
 
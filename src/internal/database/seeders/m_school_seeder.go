@@ -0,0 +1,24 @@
+package seeders
+
+import (
+	"jk-api/internal/database/models"
+
+	"gorm.io/gorm"
+)
+
+func SeedSchools(db *gorm.DB) error {
+	var count int64
+	db.Model(&models.MSchool{}).Count(&count)
+
+	if count > 0 {
+		return nil
+	}
+
+	codes := []string{"SMKN1JKT", "SMKN2BDG"}
+	schools := []models.MSchool{
+		{Name: "SMK Negeri 1 Jakarta", Code: &codes[0], IsActive: true},
+		{Name: "SMK Negeri 2 Bandung", Code: &codes[1], IsActive: true},
+	}
+
+	return db.Create(&schools).Error
+}
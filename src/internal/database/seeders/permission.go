@@ -27,6 +27,7 @@ func SeedPermissions(db *gorm.DB) error {
 		"t_essay_questions":      {"create", "update", "delete", "view", "viewOwn"},
 		"t_essay_answers":        {"create", "update", "delete", "view", "viewOwn"},
 		"t_code_history_logs":    {"create", "update", "delete", "view", "viewOwn"},
+		"content":                {"submit", "review", "publish", "archive"},
 	}
 
 	for module, actions := range permissionsMap {
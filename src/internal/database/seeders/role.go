@@ -1,6 +1,7 @@
 package seeders
 
 import (
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"time"
 
@@ -9,7 +10,7 @@ import (
 )
 
 func SeedRoles(db *gorm.DB) error {
-	roleNames := []string{"super", "teacher", "student"}
+	roleNames := []string{"super", "teacher", "student", constant.RolePlatformAdmin, constant.RoleGuardian}
 
 	var roles []models.Role
 
@@ -33,22 +34,35 @@ func SeedRoles(db *gorm.DB) error {
 		roles = append(roles, role)
 	}
 
-	// 🔥 Assign semua permission ke SUPER saja
-	var superRole models.Role
-	if err := db.Where("name = ?", "super").First(&superRole).Error; err != nil {
+	// 🔥 Assign semua permission ke SUPER dan PLATFORM ADMIN saja
+	var permissions []models.Permission
+	if err := db.Find(&permissions).Error; err != nil {
 		return err
 	}
 
-	var permissions []models.Permission
-	if err := db.Find(&permissions).Error; err != nil {
+	for _, name := range []string{"super", constant.RolePlatformAdmin} {
+		var role models.Role
+		if err := db.Where("name = ?", name).First(&role).Error; err != nil {
+			return err
+		}
+
+		if err := db.Model(&role).Association("HasPermissions").Replace(&permissions); err != nil {
+			return err
+		}
+	}
+
+	// Guru boleh mengajukan konten untuk direview, tapi tidak menerbitkannya
+	var teacherRole models.Role
+	if err := db.Where("name = ?", "teacher").First(&teacherRole).Error; err != nil {
 		return err
 	}
 
-	if err := db.Model(&superRole).Association("HasPermissions").Replace(&permissions); err != nil {
+	var submitPermission models.Permission
+	if err := db.Where("name = ?", constant.PermContentSubmit).First(&submitPermission).Error; err != nil {
 		return err
 	}
 
-	return nil
+	return db.Model(&teacherRole).Association("HasPermissions").Append(&submitPermission)
 }
 
 func SeedAdmin(db *gorm.DB) error {
@@ -57,9 +71,15 @@ func SeedAdmin(db *gorm.DB) error {
 		return err
 	}
 
+	// Admin bawaan adalah admin platform supaya tetap bisa melihat semua sekolah
+	var platformRole models.Role
+	if err := db.Where("name = ?", constant.RolePlatformAdmin).First(&platformRole).Error; err != nil {
+		return err
+	}
+
 	var existing models.User
 	if err := db.Where("email = ?", "admin@mail.com").First(&existing).Error; err == nil {
-		return nil
+		return db.Model(&existing).Association("HasRoles").Append(&platformRole)
 	} else if err != gorm.ErrRecordNotFound {
 		return err
 	}
@@ -81,7 +101,7 @@ func SeedAdmin(db *gorm.DB) error {
 		return err
 	}
 
-	if err := db.Model(&admin).Association("HasRoles").Append(&superRole); err != nil {
+	if err := db.Model(&admin).Association("HasRoles").Append(&superRole, &platformRole); err != nil {
 		return err
 	}
 
@@ -7,6 +7,7 @@ func InitSeeder(db *gorm.DB) {
 	SeedRoles(db)
 	SeedAdmin(db)
 	SeedUsers(db)
+	SeedSchools(db)
 	SeedClasses(db)
 	SeedBadgeSettings(db)
 	SeedCourses(db)
@@ -0,0 +1,23 @@
+package events
+
+import "time"
+
+const (
+	ClassStudentJoinedEvent = "class.student_joined"
+)
+
+// ClassStudentJoined is raised whenever a student becomes a member of a
+// class, whether by join code, approval, move, import or a roster edit.
+type ClassStudentJoined struct {
+	ClassID  int64     `json:"class_id"`
+	UserID   int64     `json:"user_id"`
+	JoinedAt time.Time `json:"joined_at"`
+}
+
+func (ClassStudentJoined) Name() string {
+	return ClassStudentJoinedEvent
+}
+
+func init() {
+	register[ClassStudentJoined](ClassStudentJoinedEvent)
+}
@@ -0,0 +1,67 @@
+package events
+
+import "time"
+
+const (
+	CourseStructureChangedEvent   = "course.structure_changed"
+	CoursePublishedEvent          = "course.published"
+	EnrollmentVersionChangedEvent = "course.enrollment_version_changed"
+)
+
+// CourseStructureChanged is raised when lessons or sub-lessons are added to,
+// moved between or removed from the listed courses.
+type CourseStructureChanged struct {
+	CourseIDs []int64 `json:"course_ids"`
+}
+
+func (CourseStructureChanged) Name() string {
+	return CourseStructureChangedEvent
+}
+
+// NewCourseStructureChanged builds the event with duplicate and zero IDs removed.
+func NewCourseStructureChanged(courseIDs ...int64) CourseStructureChanged {
+	seen := make(map[int64]bool, len(courseIDs))
+	unique := make([]int64, 0, len(courseIDs))
+	for _, id := range courseIDs {
+		if id == 0 || seen[id] {
+			continue
+		}
+		seen[id] = true
+		unique = append(unique, id)
+	}
+	return CourseStructureChanged{CourseIDs: unique}
+}
+
+// CoursePublished is raised when a course is made visible to students.
+// SchoolID is nil for a shared course, which is announced to every school.
+type CoursePublished struct {
+	CourseID    int64     `json:"course_id"`
+	CourseName  string    `json:"course_name"`
+	SchoolID    *int64    `json:"school_id"`
+	PublishedAt time.Time `json:"published_at"`
+	PublishedBy int64     `json:"published_by,omitempty"`
+}
+
+func (CoursePublished) Name() string {
+	return CoursePublishedEvent
+}
+
+// EnrollmentVersionChanged is raised when an enrollment is pinned to another
+// version of its course.
+type EnrollmentVersionChanged struct {
+	UserID          int64  `json:"user_id"`
+	CourseID        int64  `json:"course_id"`
+	StudentCourseID int64  `json:"student_course_id"`
+	FromVersionID   *int64 `json:"from_version_id"`
+	ToVersionID     int64  `json:"to_version_id"`
+}
+
+func (EnrollmentVersionChanged) Name() string {
+	return EnrollmentVersionChangedEvent
+}
+
+func init() {
+	register[CourseStructureChanged](CourseStructureChangedEvent)
+	register[CoursePublished](CoursePublishedEvent)
+	register[EnrollmentVersionChanged](EnrollmentVersionChangedEvent)
+}
@@ -0,0 +1,129 @@
+package events
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event is a domain event raised by a service.
+type Event interface {
+	Name() string
+}
+
+// Handler reacts to an event inside the transaction that published it.
+// Returning an error rolls back the publisher's transaction.
+type Handler func(tx *gorm.DB, event Event) error
+
+// AsyncHandler reacts to an event after the publishing transaction has
+// committed. It is invoked by the outbox relay and may run more than once for
+// the same event, so it must be idempotent.
+type AsyncHandler func(ctx context.Context, event Event) error
+
+var (
+	mu            sync.RWMutex
+	handlers      = map[string][]Handler{}
+	asyncHandlers = map[string][]AsyncHandler{}
+	decoders      = map[string]func(payload []byte) (Event, error){}
+)
+
+// register makes an event type decodable from its outbox payload.
+func register[T Event](name string) {
+	decoders[name] = func(payload []byte) (Event, error) {
+		var event T
+		if err := json.Unmarshal(payload, &event); err != nil {
+			return nil, err
+		}
+		return event, nil
+	}
+}
+
+func Subscribe(name string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+	handlers[name] = append(handlers[name], handler)
+}
+
+func SubscribeAsync(name string, handler AsyncHandler) {
+	mu.Lock()
+	defer mu.Unlock()
+	asyncHandlers[name] = append(asyncHandlers[name], handler)
+}
+
+// Publish runs every in-transaction handler subscribed to the event using tx
+// and then records the event in the outbox on the same transaction, so async
+// handlers only see events whose transaction committed.
+func Publish(tx *gorm.DB, event Event) error {
+	mu.RLock()
+	subscribed := append([]Handler(nil), handlers[event.Name()]...)
+	mu.RUnlock()
+
+	for _, handler := range subscribed {
+		if err := handler(tx, event); err != nil {
+			return err
+		}
+	}
+
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("failed to encode event %s: %w", event.Name(), err)
+	}
+
+	return tx.Create(&models.TEventOutbox{
+		EventName:   event.Name(),
+		Payload:     payload,
+		Status:      constant.OutboxPending,
+		AvailableAt: time.Now(),
+	}).Error
+}
+
+// Dispatch decodes an outbox payload and runs the async handlers for it.
+// A panicking handler is reported as an error so the relay can retry it.
+func Dispatch(ctx context.Context, name string, payload []byte) (err error) {
+	mu.RLock()
+	decode, ok := decoders[name]
+	subscribed := append([]AsyncHandler(nil), asyncHandlers[name]...)
+	mu.RUnlock()
+
+	if !ok {
+		return fmt.Errorf("unknown event %s", name)
+	}
+
+	event, err := decode(payload)
+	if err != nil {
+		return fmt.Errorf("failed to decode event %s: %w", name, err)
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler for event %s panicked: %v", name, r)
+		}
+	}()
+
+	for _, handler := range subscribed {
+		if err := handler(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type eventIDKey struct{}
+
+// WithEventID attaches the outbox id of the event being dispatched to ctx so
+// async handlers can deduplicate their own side effects.
+func WithEventID(ctx context.Context, id int64) context.Context {
+	return context.WithValue(ctx, eventIDKey{}, id)
+}
+
+// EventID returns the outbox id set by WithEventID.
+func EventID(ctx context.Context) (int64, bool) {
+	id, ok := ctx.Value(eventIDKey{}).(int64)
+	return id, ok
+}
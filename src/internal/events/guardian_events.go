@@ -0,0 +1,61 @@
+package events
+
+import "time"
+
+const (
+	GuardianInvitedEvent      = "guardian.invited"
+	GuardianLinkAcceptedEvent = "guardian.link_accepted"
+	GuardianWeeklyDigestEvent = "guardian.weekly_digest"
+)
+
+type GuardianInvited struct {
+	LinkID       int64     `json:"link_id"`
+	GuardianID   int64     `json:"guardian_id"`
+	GuardianName string    `json:"guardian_name"`
+	StudentID    int64     `json:"student_id"`
+	InvitedAt    time.Time `json:"invited_at"`
+}
+
+func (GuardianInvited) Name() string {
+	return GuardianInvitedEvent
+}
+
+type GuardianLinkAccepted struct {
+	LinkID      int64     `json:"link_id"`
+	GuardianID  int64     `json:"guardian_id"`
+	StudentID   int64     `json:"student_id"`
+	StudentName string    `json:"student_name"`
+	AcceptedBy  int64     `json:"accepted_by"`
+	AcceptedAt  time.Time `json:"accepted_at"`
+}
+
+func (GuardianLinkAccepted) Name() string {
+	return GuardianLinkAcceptedEvent
+}
+
+// GuardianDigestChild summarises one student's week for a guardian.
+type GuardianDigestChild struct {
+	StudentID       int64   `json:"student_id"`
+	StudentName     string  `json:"student_name"`
+	AverageProgress float64 `json:"average_progress"`
+	EssaysReviewed  int     `json:"essays_reviewed"`
+	Upcoming        int     `json:"upcoming"`
+	Overdue         int     `json:"overdue"`
+}
+
+// GuardianWeeklyDigest is raised once per guardian per ISO week.
+type GuardianWeeklyDigest struct {
+	GuardianID int64                 `json:"guardian_id"`
+	Week       string                `json:"week"`
+	Children   []GuardianDigestChild `json:"children"`
+}
+
+func (GuardianWeeklyDigest) Name() string {
+	return GuardianWeeklyDigestEvent
+}
+
+func init() {
+	register[GuardianInvited](GuardianInvitedEvent)
+	register[GuardianLinkAccepted](GuardianLinkAcceptedEvent)
+	register[GuardianWeeklyDigest](GuardianWeeklyDigestEvent)
+}
@@ -0,0 +1,121 @@
+package events
+
+import "time"
+
+const (
+	SubLessonCompletedEvent = "sub_lesson.completed"
+	EssayApprovedEvent      = "essay.approved"
+	UserRegisteredEvent     = "user.registered"
+	CourseEnrolledEvent     = "course.enrolled"
+	CourseCompletedEvent    = "course.completed"
+	BadgeEarnedEvent        = "badge.earned"
+	TeacherApprovedEvent    = "teacher.approved"
+	QuizSubmittedEvent      = "quiz.submitted"
+)
+
+type SubLessonCompleted struct {
+	UserID      int64     `json:"user_id"`
+	SubLessonID int64     `json:"sub_lesson_id"`
+	CourseID    int64     `json:"course_id"`
+	CompletedAt time.Time `json:"completed_at"`
+}
+
+func (SubLessonCompleted) Name() string {
+	return SubLessonCompletedEvent
+}
+
+type EssayApproved struct {
+	EssayAnswerID   int64     `json:"essay_answer_id"`
+	EssayQuestionID int64     `json:"essay_question_id"`
+	UserID          int64     `json:"user_id"`
+	ApprovedBy      int64     `json:"approved_by"`
+	ApprovedAt      time.Time `json:"approved_at"`
+}
+
+func (EssayApproved) Name() string {
+	return EssayApprovedEvent
+}
+
+type UserRegistered struct {
+	UserID       int64     `json:"user_id"`
+	Email        string    `json:"email"`
+	Role         string    `json:"role"`
+	RegisteredAt time.Time `json:"registered_at"`
+}
+
+func (UserRegistered) Name() string {
+	return UserRegisteredEvent
+}
+
+type CourseEnrolled struct {
+	UserID          int64     `json:"user_id"`
+	CourseID        int64     `json:"course_id"`
+	StudentCourseID int64     `json:"student_course_id"`
+	EnrolledAt      time.Time `json:"enrolled_at"`
+}
+
+func (CourseEnrolled) Name() string {
+	return CourseEnrolledEvent
+}
+
+type CourseCompleted struct {
+	UserID          int64     `json:"user_id"`
+	CourseID        int64     `json:"course_id"`
+	StudentCourseID int64     `json:"student_course_id"`
+	CompletedAt     time.Time `json:"completed_at"`
+}
+
+func (CourseCompleted) Name() string {
+	return CourseCompletedEvent
+}
+
+type BadgeEarned struct {
+	UserID          int64     `json:"user_id"`
+	CourseID        int64     `json:"course_id"`
+	StudentCourseID int64     `json:"student_course_id"`
+	BadgeID         int64     `json:"badge_id"`
+	BadgeName       string    `json:"badge_name"`
+	EarnedAt        time.Time `json:"earned_at"`
+}
+
+func (BadgeEarned) Name() string {
+	return BadgeEarnedEvent
+}
+
+type TeacherApproved struct {
+	UserID     int64     `json:"user_id"`
+	ApprovedBy int64     `json:"approved_by"`
+	ApprovedAt time.Time `json:"approved_at"`
+}
+
+func (TeacherApproved) Name() string {
+	return TeacherApprovedEvent
+}
+
+// QuizSubmitted is raised when a quiz attempt is graded. ScoreDelta is how
+// much the score counted for the quiz, and so the course score, changed.
+type QuizSubmitted struct {
+	UserID      int64     `json:"user_id"`
+	QuizID      int64     `json:"quiz_id"`
+	AttemptID   int64     `json:"attempt_id"`
+	CourseID    int64     `json:"course_id"`
+	Score       int       `json:"score"`
+	MaxScore    int       `json:"max_score"`
+	ScoreDelta  int       `json:"score_delta"`
+	SubmittedAt time.Time `json:"submitted_at"`
+}
+
+func (QuizSubmitted) Name() string {
+	return QuizSubmittedEvent
+}
+
+func init() {
+	register[SubLessonCompleted](SubLessonCompletedEvent)
+	register[EssayApproved](EssayApprovedEvent)
+	register[UserRegistered](UserRegisteredEvent)
+	register[CourseEnrolled](CourseEnrolledEvent)
+	register[CourseCompleted](CourseCompletedEvent)
+	register[BadgeEarned](BadgeEarnedEvent)
+	register[TeacherApproved](TeacherApprovedEvent)
+	register[QuizSubmitted](QuizSubmittedEvent)
+}
@@ -0,0 +1,87 @@
+package generation
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jk-api/internal/constant"
+	"net/http"
+	"time"
+)
+
+// OpenAI calls a chat completions endpoint. Any service that accepts the
+// OpenAI request body and a bearer token can be used.
+type OpenAI struct {
+	url    string
+	token  string
+	model  string
+	client *http.Client
+}
+
+func NewOpenAI(url string, token string, model string, timeout time.Duration) *OpenAI {
+	return &OpenAI{
+		url:    url,
+		token:  token,
+		model:  model,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *OpenAI) Name() string {
+	return constant.LLMProviderOpenAI
+}
+
+func (p *OpenAI) Generate(ctx context.Context, req Request) (*Response, error) {
+	body, err := json.Marshal(map[string]any{
+		"model": p.model,
+		"messages": []map[string]string{
+			{"role": "system", "content": req.System},
+			{"role": "user", "content": req.Prompt},
+		},
+		"response_format": map[string]string{"type": "json_object"},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s responded with status %d: %s", httpReq.URL.Host, resp.StatusCode, message)
+	}
+
+	var completion struct {
+		Model   string `json:"model"`
+		Choices []struct {
+			Message struct {
+				Content string `json:"content"`
+			} `json:"message"`
+		} `json:"choices"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&completion); err != nil {
+		return nil, err
+	}
+	if len(completion.Choices) == 0 {
+		return nil, fmt.Errorf("%s returned no choices", httpReq.URL.Host)
+	}
+
+	model := completion.Model
+	if model == "" {
+		model = p.model
+	}
+	return &Response{Text: completion.Choices[0].Message.Content, Model: model}, nil
+}
@@ -0,0 +1,93 @@
+package generation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalidOutput is returned when the answer of a provider doesn't hold
+// the items its system prompt asked for.
+var ErrInvalidOutput = errors.New("hasil generasi tidak valid")
+
+// Material is a generated material. Content is plain text with blank lines
+// between paragraphs.
+type Material struct {
+	Title   string `json:"title"`
+	Content string `json:"content"`
+}
+
+type CodeQuestion struct {
+	Question string `json:"question"`
+	Hint     string `json:"hint"`
+	Score    int    `json:"score"`
+}
+
+// EssayQuestion is a generated essay question with up to four accepted
+// answers.
+type EssayQuestion struct {
+	Question string   `json:"question"`
+	Answers  []string `json:"answers"`
+}
+
+func ParseMaterials(text string) ([]Material, error) {
+	items, err := parseItems[Material](text)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.Title) == "" || strings.TrimSpace(item.Content) == "" {
+			return nil, fmt.Errorf("%w: materi tanpa judul atau isi", ErrInvalidOutput)
+		}
+	}
+	return items, nil
+}
+
+func ParseCodeQuestions(text string) ([]CodeQuestion, error) {
+	items, err := parseItems[CodeQuestion](text)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.Question) == "" {
+			return nil, fmt.Errorf("%w: soal kosong", ErrInvalidOutput)
+		}
+	}
+	return items, nil
+}
+
+func ParseEssayQuestions(text string) ([]EssayQuestion, error) {
+	items, err := parseItems[EssayQuestion](text)
+	if err != nil {
+		return nil, err
+	}
+	for _, item := range items {
+		if strings.TrimSpace(item.Question) == "" || len(item.Answers) == 0 {
+			return nil, fmt.Errorf("%w: soal esai tanpa pertanyaan atau jawaban", ErrInvalidOutput)
+		}
+	}
+	return items, nil
+}
+
+// parseItems reads {"items": [...]}. Models sometimes wrap their answer in a
+// Markdown code fence even when asked not to, so a fence is removed first.
+func parseItems[T any](text string) ([]T, error) {
+	text = strings.TrimSpace(text)
+	if strings.HasPrefix(text, "```") {
+		text = strings.TrimPrefix(text, "```json")
+		text = strings.TrimPrefix(text, "```")
+		text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	}
+
+	var answer struct {
+		Items []T `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(text), &answer); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidOutput, err)
+	}
+	if len(answer.Items) == 0 {
+		return nil, fmt.Errorf("%w: tidak ada item", ErrInvalidOutput)
+	}
+	return answer.Items, nil
+}
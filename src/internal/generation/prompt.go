@@ -0,0 +1,102 @@
+package generation
+
+import (
+	"fmt"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+)
+
+// DefaultTemplate is a built-in prompt template. They are stored as the
+// shared default template of their kind when the database is migrated.
+type DefaultTemplate struct {
+	Name      string
+	Body      string
+	Variables []models.PromptVariable
+}
+
+// DefaultTemplates holds the built-in template of every kind.
+var DefaultTemplates = map[string]DefaultTemplate{
+	constant.GenerationKindMaterial: {
+		Name: "Materi bawaan",
+		Body: `Buat {{.count}} materi pembelajaran tentang "{{.topic}}" untuk sub-bab "{{.sub_lesson}}" pada bab "{{.lesson}}" di kursus "{{.course}}"{{if .level}} tingkat {{.level}}{{end}}.
+Gunakan bahasa yang mudah dipahami siswa dan sertakan contoh.
+{{if .context}}
+Materi yang sudah ada di sub-bab ini, jangan diulang:
+{{.context}}
+{{end}}`,
+		Variables: []models.PromptVariable{
+			{Name: "count", Type: constant.PromptVarCount, Required: true},
+			{Name: "topic", Type: constant.PromptVarTopic, Required: true},
+			{Name: "sub_lesson", Type: constant.PromptVarSubLesson},
+			{Name: "lesson", Type: constant.PromptVarLesson},
+			{Name: "course", Type: constant.PromptVarCourse},
+			{Name: "level", Type: constant.PromptVarLevel},
+			{Name: "context", Type: constant.PromptVarContext},
+		},
+	},
+	constant.GenerationKindCodeQuestion: {
+		Name: "Soal kode bawaan",
+		Body: `Buat {{.count}} soal pemrograman tentang "{{.topic}}" untuk sub-bab "{{.sub_lesson}}" di kursus "{{.course}}"{{if .level}} tingkat {{.level}}{{end}}.
+Setiap soal harus bisa dikerjakan dengan menulis program singkat.
+{{if .context}}
+Soal harus sesuai dengan materi berikut:
+{{.context}}
+{{end}}`,
+		Variables: []models.PromptVariable{
+			{Name: "count", Type: constant.PromptVarCount, Required: true},
+			{Name: "topic", Type: constant.PromptVarTopic, Required: true},
+			{Name: "sub_lesson", Type: constant.PromptVarSubLesson},
+			{Name: "course", Type: constant.PromptVarCourse},
+			{Name: "level", Type: constant.PromptVarLevel},
+			{Name: "context", Type: constant.PromptVarContext},
+		},
+	},
+	constant.GenerationKindEssayQuestion: {
+		Name: "Soal esai bawaan",
+		Body: `Buat {{.count}} soal esai tentang "{{.topic}}" yang menguji pemahaman siswa atas soal pemrograman berikut:
+{{.code_question}}
+{{if .student_answer}}
+Gunakan jawaban siswa berikut sebagai contoh kesalahan yang sering terjadi:
+{{.student_answer}}
+{{end}}{{if .context}}
+Materi sub-bab "{{.sub_lesson}}":
+{{.context}}
+{{end}}`,
+		Variables: []models.PromptVariable{
+			{Name: "count", Type: constant.PromptVarCount, Required: true},
+			{Name: "topic", Type: constant.PromptVarTopic, Required: true},
+			{Name: "code_question", Type: constant.PromptVarCodeQuestion, Required: true},
+			{Name: "student_answer", Type: constant.PromptVarStudentAnswer, Description: "Jawaban siswa yang dijadikan contoh"},
+			{Name: "sub_lesson", Type: constant.PromptVarSubLesson},
+			{Name: "context", Type: constant.PromptVarContext},
+		},
+	},
+}
+
+// systemPrompts fix the answer format of each kind. Unlike templates they
+// can't be changed, as the parsers depend on them.
+var systemPrompts = map[string]string{
+	constant.GenerationKindMaterial: `Kamu adalah guru pemrograman yang menulis materi untuk siswa sekolah. ` +
+		`Jawab hanya dengan objek JSON {"items": [{"title": string, "content": string}]}. ` +
+		`Pisahkan paragraf pada content dengan baris kosong.`,
+	constant.GenerationKindCodeQuestion: `Kamu adalah guru pemrograman yang menyusun soal latihan untuk siswa sekolah. ` +
+		`Jawab hanya dengan objek JSON {"items": [{"question": string, "hint": string, "score": number}]}.`,
+	constant.GenerationKindEssayQuestion: `Kamu adalah guru pemrograman yang menyusun soal esai untuk siswa sekolah. ` +
+		`Jawab hanya dengan objek JSON {"items": [{"question": string, "answers": [string]}]} ` +
+		`dengan satu sampai empat contoh jawaban yang benar per soal.`,
+}
+
+// NewRequest wraps a rendered prompt into a request for kind.
+func NewRequest(kind string, prompt string, topic string, count int) (*Request, error) {
+	system, ok := systemPrompts[kind]
+	if !ok {
+		return nil, fmt.Errorf("jenis generasi %q tidak dikenal", kind)
+	}
+	return &Request{
+		Kind:   kind,
+		Topic:  topic,
+		Count:  count,
+		System: system,
+		Prompt: prompt,
+	}, nil
+}
@@ -0,0 +1,29 @@
+// Package generation drafts materials and questions with a language model.
+// Prompts are rendered from templates, sent to an LLMProvider and its JSON
+// answer is parsed into items teachers review before students see them.
+package generation
+
+import "context"
+
+// LLMProvider sends a prompt to a language model and returns its answer.
+type LLMProvider interface {
+	Name() string
+	Generate(ctx context.Context, req Request) (*Response, error)
+}
+
+// Request is one prompt. Kind, Topic and Count repeat what the prompt asks
+// for so providers that don't read prompts, like Stub, can still answer.
+type Request struct {
+	Kind   string
+	Topic  string
+	Count  int
+	System string
+	Prompt string
+}
+
+// Response is the raw answer of the model, expected to be the JSON object
+// described by the system prompt of the kind.
+type Response struct {
+	Text  string
+	Model string
+}
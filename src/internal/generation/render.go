@@ -0,0 +1,201 @@
+package generation
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"math"
+	"regexp"
+	"slices"
+	"strings"
+	"text/template"
+	"text/template/parse"
+	"unicode/utf8"
+)
+
+var (
+	// ErrInvalidTemplate is returned for template bodies that don't parse or
+	// use variables they don't declare.
+	ErrInvalidTemplate = errors.New("template prompt tidak valid")
+	// ErrInvalidVariables is returned when the values given to a template
+	// don't match its variables.
+	ErrInvalidVariables = errors.New("variabel prompt tidak valid")
+)
+
+var variableName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,39}$`)
+
+// maxValueLength caps the values callers fill in, in characters.
+var maxValueLength = map[string]int{
+	constant.PromptVarStudentAnswer: 10000,
+	constant.PromptVarText:          2000,
+}
+
+// ValidateTemplate checks that body parses and only refers to the declared
+// variables, written {{.name}}. Besides variables, bodies may use if, else
+// and the built-in functions of text/template.
+func ValidateTemplate(body string, vars []models.PromptVariable) error {
+	declared := make(map[string]bool, len(vars))
+	for _, v := range vars {
+		if !variableName.MatchString(v.Name) {
+			return fmt.Errorf("%w: nama variabel %q tidak valid", ErrInvalidTemplate, v.Name)
+		}
+		if declared[v.Name] {
+			return fmt.Errorf("%w: variabel %q dideklarasikan dua kali", ErrInvalidTemplate, v.Name)
+		}
+		if !slices.Contains(constant.PromptVarTypes, v.Type) {
+			return fmt.Errorf("%w: tipe variabel %q tidak dikenal", ErrInvalidTemplate, v.Type)
+		}
+		declared[v.Name] = true
+	}
+
+	tmpl, err := parseTemplate(body)
+	if err != nil {
+		return err
+	}
+	return checkNode(tmpl.Tree.Root, declared)
+}
+
+// Render validates values against the variables of a template and renders
+// its body. Optional variables without a value render empty.
+func Render(body string, vars []models.PromptVariable, values map[string]any) (string, error) {
+	if err := ValidateTemplate(body, vars); err != nil {
+		return "", err
+	}
+
+	for name := range values {
+		if !slices.ContainsFunc(vars, func(v models.PromptVariable) bool { return v.Name == name }) {
+			return "", fmt.Errorf("%w: variabel %q tidak ada di template", ErrInvalidVariables, name)
+		}
+	}
+
+	data := make(map[string]any, len(vars))
+	for _, v := range vars {
+		value, err := checkValue(v, values[v.Name])
+		if err != nil {
+			return "", err
+		}
+		data[v.Name] = value
+	}
+
+	tmpl, err := parseTemplate(body)
+	if err != nil {
+		return "", err
+	}
+	var prompt strings.Builder
+	if err := tmpl.Execute(&prompt, data); err != nil {
+		return "", fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return strings.TrimSpace(prompt.String()), nil
+}
+
+func parseTemplate(body string) (*template.Template, error) {
+	tmpl, err := template.New("prompt").Option("missingkey=error").Parse(body)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidTemplate, err)
+	}
+	return tmpl, nil
+}
+
+// checkNode walks a parsed body. range, with and nested templates change
+// what a field refers to, so they are not allowed.
+func checkNode(node parse.Node, declared map[string]bool) error {
+	switch n := node.(type) {
+	case nil:
+		return nil
+	case *parse.ListNode:
+		if n == nil {
+			return nil
+		}
+		for _, child := range n.Nodes {
+			if err := checkNode(child, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.ActionNode:
+		return checkNode(n.Pipe, declared)
+	case *parse.IfNode:
+		for _, child := range []parse.Node{n.Pipe, n.List, n.ElseList} {
+			if err := checkNode(child, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.PipeNode:
+		if n == nil {
+			return nil
+		}
+		for _, cmd := range n.Cmds {
+			if err := checkNode(cmd, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.CommandNode:
+		for _, arg := range n.Args {
+			if err := checkNode(arg, declared); err != nil {
+				return err
+			}
+		}
+	case *parse.FieldNode:
+		if !declared[n.Ident[0]] {
+			return fmt.Errorf("%w: variabel %q belum dideklarasikan", ErrInvalidTemplate, n.Ident[0])
+		}
+	case *parse.TextNode, *parse.IdentifierNode, *parse.StringNode, *parse.NumberNode,
+		*parse.BoolNode, *parse.NilNode, *parse.DotNode, *parse.CommentNode:
+		return nil
+	default:
+		return fmt.Errorf("%w: %s tidak didukung", ErrInvalidTemplate, node.String())
+	}
+	return nil
+}
+
+// checkValue checks the value of v against its type and returns it in the
+// form templates get it. Numbers arrive from JSON as float64 or json.Number.
+func checkValue(v models.PromptVariable, value any) (any, error) {
+	numeric := v.Type == constant.PromptVarNumber || v.Type == constant.PromptVarCount
+	if value == nil || value == "" {
+		if v.Required {
+			return nil, fmt.Errorf("%w: %s wajib diisi", ErrInvalidVariables, v.Name)
+		}
+		if numeric {
+			return 0, nil
+		}
+		return "", nil
+	}
+
+	if numeric {
+		var number float64
+		switch n := value.(type) {
+		case int:
+			number = float64(n)
+		case int64:
+			number = float64(n)
+		case float64:
+			number = n
+		case json.Number:
+			f, err := n.Float64()
+			if err != nil {
+				return nil, fmt.Errorf("%w: %s harus berupa angka", ErrInvalidVariables, v.Name)
+			}
+			number = f
+		default:
+			return nil, fmt.Errorf("%w: %s harus berupa angka", ErrInvalidVariables, v.Name)
+		}
+		if v.Type == constant.PromptVarCount {
+			if number != math.Trunc(number) || number < 1 {
+				return nil, fmt.Errorf("%w: %s harus bilangan bulat positif", ErrInvalidVariables, v.Name)
+			}
+			return int(number), nil
+		}
+		return number, nil
+	}
+
+	text, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s harus berupa teks", ErrInvalidVariables, v.Name)
+	}
+	if limit, ok := maxValueLength[v.Type]; ok && utf8.RuneCountInString(text) > limit {
+		return nil, fmt.Errorf("%w: %s maksimal %d karakter", ErrInvalidVariables, v.Name, limit)
+	}
+	return text, nil
+}
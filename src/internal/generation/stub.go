@@ -0,0 +1,66 @@
+package generation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"jk-api/internal/constant"
+)
+
+// Stub answers without calling a model, so the pipeline can be developed and
+// tested offline. Its answer only depends on the request: the same prompt
+// always yields the same items.
+type Stub struct{}
+
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+func (*Stub) Name() string {
+	return constant.LLMProviderStub
+}
+
+func (*Stub) Generate(ctx context.Context, req Request) (*Response, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	h := fnv.New32a()
+	h.Write([]byte(req.System))
+	h.Write([]byte(req.Prompt))
+	seed := h.Sum32()
+
+	count := max(req.Count, 1)
+	var items []any
+	for i := 0; i < count; i++ {
+		n := int(seed%1000) + i
+		switch req.Kind {
+		case constant.GenerationKindCodeQuestion:
+			items = append(items, CodeQuestion{
+				Question: fmt.Sprintf("Tulis program yang menerapkan %s (latihan %d).", req.Topic, n),
+				Hint:     fmt.Sprintf("Mulailah dari contoh paling sederhana tentang %s.", req.Topic),
+				Score:    10 * (1 + i%3),
+			})
+		case constant.GenerationKindEssayQuestion:
+			items = append(items, EssayQuestion{
+				Question: fmt.Sprintf("Jelaskan cara kerja %s pada program di atas (soal %d).", req.Topic, n),
+				Answers: []string{
+					fmt.Sprintf("%s digunakan untuk menyelesaikan masalah pada program.", req.Topic),
+					fmt.Sprintf("Program memakai %s agar kodenya lebih mudah dibaca.", req.Topic),
+				},
+			})
+		default:
+			items = append(items, Material{
+				Title:   fmt.Sprintf("%s: bagian %d", req.Topic, i+1),
+				Content: fmt.Sprintf("Materi %d membahas %s.\n\nBaca contoh berikut, lalu coba ubah sendiri untuk melihat hasilnya.", n, req.Topic),
+			})
+		}
+	}
+
+	text, err := json.Marshal(map[string]any{"items": items})
+	if err != nil {
+		return nil, err
+	}
+	return &Response{Text: string(text), Model: constant.LLMProviderStub}, nil
+}
@@ -1,31 +1,22 @@
 package helper
 
 import (
-	"strings"
+	"encoding/json"
+	"jk-api/internal/richtext"
 
 	"gorm.io/datatypes"
 )
 
+// ExtractTextFromTiptap returns the text of a Tiptap document at any depth,
+// one line per block.
 func ExtractTextFromTiptap(content datatypes.JSONMap) string {
-	text := ""
-
-	if contentArray, ok := content["content"].([]interface{}); ok {
-		for _, item := range contentArray {
-			if itemMap, ok := item.(map[string]interface{}); ok {
-				if innerContent, exists := itemMap["content"]; exists {
-					if innerArray, ok := innerContent.([]interface{}); ok {
-						for _, inner := range innerArray {
-							if innerMap, ok := inner.(map[string]interface{}); ok {
-								if t, ok := innerMap["text"].(string); ok {
-									text += t + " "
-								}
-							}
-						}
-					}
-				}
-			}
-		}
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return ""
 	}
-
-	return strings.TrimSpace(text)
+	var doc richtext.Node
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return ""
+	}
+	return richtext.PlainText(&doc)
 }
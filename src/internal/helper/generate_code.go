@@ -1,6 +1,7 @@
 package helper
 
 import (
+	"crypto/rand"
 	"fmt"
 	"strings"
 )
@@ -15,3 +16,33 @@ func GenerateCode(name string, id int64) string {
 
 	return code
 }
+
+const classCodeAlphabet = "ABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GenerateClassCode returns a random join code without look-alike
+// characters (0/O, 1/I).
+func GenerateClassCode(length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = classCodeAlphabet[int(b)%len(classCodeAlphabet)]
+	}
+	return string(buf), nil
+}
+
+const passwordAlphabet = "abcdefghjkmnpqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789"
+
+// GeneratePassword returns a random default password that is easy to read
+// back from a printed credentials sheet.
+func GeneratePassword(length int) (string, error) {
+	buf := make([]byte, length)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	for i, b := range buf {
+		buf[i] = passwordAlphabet[int(b)%len(passwordAlphabet)]
+	}
+	return string(buf), nil
+}
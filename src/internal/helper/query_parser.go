@@ -3,6 +3,8 @@ package helper
 import (
 	"encoding/json"
 	"fmt"
+	"jk-api/internal/queryspec"
+	"net/url"
 	"strconv"
 	"strings"
 
@@ -87,3 +89,20 @@ func ParseQueryStringArray(c *fiber.Ctx, key string) ([]string, error) {
 
 	return list, nil
 }
+
+// ParseQuerySpec parses the filter, sort and paging parameters of a list request
+// against the fields model exposes. sort and order are used when the request
+// doesn't give them, as the plain sort and order parameters did before.
+func ParseQuerySpec(c *fiber.Ctx, model queryspec.Queryable, sort string, order string) (queryspec.Spec, error) {
+	query, err := url.ParseQuery(string(c.Request().URI().QueryString()))
+	if err != nil {
+		return queryspec.Spec{}, fmt.Errorf("%w: %v", queryspec.ErrInvalid, err)
+	}
+	if query.Get("sort") == "" {
+		query.Set("sort", sort)
+	}
+	if query.Get("order") == "" {
+		query.Set("order", order)
+	}
+	return queryspec.Parse(query, model.QueryFields())
+}
@@ -0,0 +1,87 @@
+// Package imaging decodes uploaded images and scales them down into
+// thumbnail variants using only the standard library.
+package imaging
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	_ "image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// Decode reads a JPEG, PNG or GIF image.
+func Decode(r io.Reader) (image.Image, string, error) {
+	return image.Decode(r)
+}
+
+// Fit scales img down so its longest side is maxSide, keeping the aspect
+// ratio. Images that already fit are returned as they are.
+func Fit(img image.Image, maxSide int) image.Image {
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+	if w <= maxSide && h <= maxSide {
+		return img
+	}
+
+	dw, dh := maxSide, maxSide
+	if w > h {
+		dh = max(1, h*maxSide/w)
+	} else {
+		dw = max(1, w*maxSide/h)
+	}
+	return resize(img, dw, dh)
+}
+
+// resize shrinks img to dw x dh by averaging every source pixel that falls
+// into a destination pixel, which avoids the aliasing of nearest-neighbour
+// sampling when thumbnails are much smaller than the original.
+func resize(img image.Image, dw int, dh int) image.Image {
+	src := img.Bounds()
+	sw, sh := src.Dx(), src.Dy()
+	dst := image.NewNRGBA(image.Rect(0, 0, dw, dh))
+
+	for y := 0; y < dh; y++ {
+		y0 := src.Min.Y + y*sh/dh
+		y1 := max(y0+1, src.Min.Y+(y+1)*sh/dh)
+		for x := 0; x < dw; x++ {
+			x0 := src.Min.X + x*sw/dw
+			x1 := max(x0+1, src.Min.X+(x+1)*sw/dw)
+
+			var r, g, b, a, n uint64
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					c := color.NRGBA64Model.Convert(img.At(sx, sy)).(color.NRGBA64)
+					r += uint64(c.R)
+					g += uint64(c.G)
+					b += uint64(c.B)
+					a += uint64(c.A)
+					n++
+				}
+			}
+			dst.SetNRGBA(x, y, color.NRGBA{
+				R: uint8(r / n >> 8),
+				G: uint8(g / n >> 8),
+				B: uint8(b / n >> 8),
+				A: uint8(a / n >> 8),
+			})
+		}
+	}
+	return dst
+}
+
+// Encode writes img in format ("jpeg", "png" or "gif"). GIF variants are
+// written as PNG since only the first frame is kept anyway; the returned
+// MIME type says which encoding was used.
+func Encode(w io.Writer, img image.Image, format string) (string, error) {
+	switch format {
+	case "jpeg":
+		return "image/jpeg", jpeg.Encode(w, img, &jpeg.Options{Quality: 85})
+	case "png", "gif":
+		return "image/png", png.Encode(w, img)
+	default:
+		return "", fmt.Errorf("unsupported image format %q", format)
+	}
+}
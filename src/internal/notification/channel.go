@@ -0,0 +1,27 @@
+package notification
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrAlreadyDelivered is returned by the in-app channel when the message's
+// dedupe key was already stored for the user.
+var ErrAlreadyDelivered = errors.New("notification already delivered")
+
+// Message is a notification addressed to a single user.
+type Message struct {
+	UserID    int64
+	Type      string
+	Title     string
+	Body      string
+	Priority  string
+	Data      map[string]string
+	DedupeKey string
+}
+
+// Channel delivers a message over one medium (in-app inbox, push, ...).
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"context"
+	"fmt"
+	"jk-api/internal/config"
+	"sync"
+)
+
+// Fake is an in-memory channel for local development. It records every
+// message it is given instead of delivering it.
+type Fake struct {
+	name string
+
+	mu   sync.Mutex
+	sent []Message
+	seen map[string]bool
+}
+
+func NewFake(name string) *Fake {
+	return &Fake{name: name, seen: map[string]bool{}}
+}
+
+func (f *Fake) Name() string {
+	return f.name
+}
+
+// Send behaves like the in-app channel for repeated dedupe keys so the
+// service's retry handling can be exercised without a database.
+func (f *Fake) Send(ctx context.Context, msg Message) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if msg.DedupeKey != "" {
+		key := fmt.Sprintf("%d/%s", msg.UserID, msg.DedupeKey)
+		if f.seen[key] {
+			return ErrAlreadyDelivered
+		}
+		f.seen[key] = true
+	}
+
+	f.sent = append(f.sent, msg)
+	config.Logger.Infof("📨 [%s fake] user %d: %s", f.name, msg.UserID, msg.Title)
+	return nil
+}
+
+// Sent returns a copy of the messages recorded so far.
+func (f *Fake) Sent() []Message {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]Message(nil), f.sent...)
+}
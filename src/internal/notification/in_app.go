@@ -0,0 +1,54 @@
+package notification
+
+import (
+	"context"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+)
+
+// InboxRepository is the part of the notification repository the in-app
+// channel needs.
+type InboxRepository interface {
+	InsertNotificationIgnoreDuplicate(data *models.Notification) (bool, error)
+}
+
+// InAppChannel stores the message in the user's notification inbox.
+type InAppChannel struct {
+	repo InboxRepository
+}
+
+func NewInAppChannel(repo InboxRepository) *InAppChannel {
+	return &InAppChannel{repo: repo}
+}
+
+func (c *InAppChannel) Name() string {
+	return constant.NotificationChannelInApp
+}
+
+func (c *InAppChannel) Send(ctx context.Context, msg Message) error {
+	data := make(map[string]interface{}, len(msg.Data))
+	for key, value := range msg.Data {
+		data[key] = value
+	}
+
+	row := &models.Notification{
+		UserID:   msg.UserID,
+		Type:     msg.Type,
+		Title:    msg.Title,
+		Body:     msg.Body,
+		Priority: msg.Priority,
+		Data:     data,
+	}
+	if msg.DedupeKey != "" {
+		row.DedupeKey = &msg.DedupeKey
+	}
+
+	created, err := c.repo.InsertNotificationIgnoreDuplicate(row)
+	if err != nil {
+		return err
+	}
+	if !created {
+		return ErrAlreadyDelivered
+	}
+	return nil
+}
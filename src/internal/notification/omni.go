@@ -0,0 +1,65 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"jk-api/internal/constant"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OmniChannel forwards the message to the omni-channel service, which fans
+// it out to the user's registered devices and messaging apps.
+type OmniChannel struct {
+	baseURL string
+	token   string
+	client  *http.Client
+}
+
+// omniTopicMessage is the body of the omni-channel service's topic endpoint.
+type omniTopicMessage struct {
+	Notification omniNotification `json:"notification"`
+	Topic        string           `json:"topic"`
+}
+
+type omniNotification struct {
+	Title string `json:"title"`
+	Body  string `json:"body"`
+}
+
+func NewOmniChannel(baseURL string, token string, timeout time.Duration) *OmniChannel {
+	return &OmniChannel{
+		baseURL: strings.TrimRight(baseURL, "/"),
+		token:   token,
+		client:  &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *OmniChannel) Name() string {
+	return constant.NotificationChannelOmni
+}
+
+func (c *OmniChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(omniTopicMessage{
+		Notification: omniNotification{
+			Title: msg.Title,
+			Body:  msg.Body,
+		},
+		Topic: fmt.Sprintf(constant.NotificationUserTopic, msg.UserID),
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL+"/notifications/topic", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Omni-Token", c.token)
+
+	return doRequest(c.client, req)
+}
@@ -0,0 +1,71 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jk-api/internal/constant"
+	"net/http"
+	"time"
+)
+
+// PushChannel sends FCM HTTP v1 style messages to the user's topic. Any
+// endpoint that accepts the same body and bearer token can be used.
+type PushChannel struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+func NewPushChannel(url string, token string, timeout time.Duration) *PushChannel {
+	return &PushChannel{
+		url:    url,
+		token:  token,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (c *PushChannel) Name() string {
+	return constant.NotificationChannelPush
+}
+
+func (c *PushChannel) Send(ctx context.Context, msg Message) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"message": map[string]interface{}{
+			"topic": fmt.Sprintf(constant.NotificationUserTopic, msg.UserID),
+			"notification": map[string]string{
+				"title": msg.Title,
+				"body":  msg.Body,
+			},
+			"data": msg.Data,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+c.token)
+
+	return doRequest(c.client, req)
+}
+
+func doRequest(client *http.Client, req *http.Request) error {
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return fmt.Errorf("%s responded with status %d: %s", req.URL.Host, resp.StatusCode, body)
+	}
+	return nil
+}
@@ -0,0 +1,205 @@
+package queryspec
+
+import (
+	"database/sql/driver"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Cursor marks a row of a sorted list: the values of its sort columns, in
+// order, and whether the page wanted lies before or after it.
+//
+// Paging seeks from those values instead of skipping rows, so it stays right
+// for any sort and doesn't repeat or lose rows when others are inserted. The
+// encoded form also records the sort it was taken under and is refused when
+// used with another one.
+type Cursor struct {
+	Values   []any
+	Backward bool
+}
+
+type encodedCursor struct {
+	Sort     string    `json:"s"`
+	Values   []*string `json:"v"`
+	Backward bool      `json:"b,omitempty"`
+}
+
+// Page describes where a page of a list sits. A cursor is empty when there
+// is nothing more in its direction.
+type Page struct {
+	Total      int64  `json:"total"`
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor"`
+	PrevCursor string `json:"prev_cursor"`
+}
+
+// Keyset restricts db to the rows past the cursor, in the direction it
+// points to. NULLs sort as the largest value, as Postgres does by default.
+func (s Spec) Keyset(db *gorm.DB) *gorm.DB {
+	if s.Cursor == nil {
+		return db
+	}
+
+	var (
+		terms     []string
+		args      []any
+		equal     []string
+		equalArgs []any
+		backward  = s.Cursor.Backward
+	)
+	for i, sort := range s.Sorts {
+		column, value := sort.Column, s.Cursor.Values[i]
+
+		var past string
+		var pastArgs []any
+		switch greater := sort.Desc == backward; {
+		case greater && value == nil:
+			// Nothing sorts after NULL.
+		case greater:
+			past, pastArgs = "("+column+" > ? OR "+column+" IS NULL)", []any{value}
+		case value == nil:
+			past = column + " IS NOT NULL"
+		default:
+			past, pastArgs = column+" < ?", []any{value}
+		}
+
+		if past != "" {
+			terms = append(terms, strings.Join(append(append([]string{}, equal...), past), " AND "))
+			args = append(append(args, equalArgs...), pastArgs...)
+		}
+
+		if value == nil {
+			equal = append(equal, column+" IS NULL")
+		} else {
+			equal = append(equal, column+" = ?")
+			equalArgs = append(equalArgs, value)
+		}
+	}
+
+	if len(terms) == 0 {
+		return db.Where("1 = 0")
+	}
+	return db.Where("("+strings.Join(terms, " OR ")+")", args...)
+}
+
+// Page returns the cursors around a page fetched with s. first and last are
+// the sort values of its first and last row, and more tells whether a row
+// was left over beyond the limit in the direction of the fetch.
+func (s Spec) Page(first, last []any, more bool) (Page, error) {
+	page := Page{Limit: s.Limit}
+	if s.Limit == 0 || first == nil {
+		return page, nil
+	}
+
+	backward := s.Cursor != nil && s.Cursor.Backward
+	var err error
+	if more || backward {
+		if page.NextCursor, err = s.encode(last, false); err != nil {
+			return Page{}, err
+		}
+	}
+	if (more && backward) || (s.Cursor != nil && !backward) {
+		if page.PrevCursor, err = s.encode(first, true); err != nil {
+			return Page{}, err
+		}
+	}
+	return page, nil
+}
+
+func (s Spec) encode(values []any, backward bool) (string, error) {
+	cursor := encodedCursor{Sort: signature(s.Sorts), Backward: backward}
+	for _, value := range values {
+		formatted, err := formatValue(value)
+		if err != nil {
+			return "", err
+		}
+		cursor.Values = append(cursor.Values, formatted)
+	}
+
+	raw, err := json.Marshal(cursor)
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func decodeCursor(raw string, sorts []Sort) (*Cursor, error) {
+	invalid := fmt.Errorf("%w: cursor tidak valid", ErrInvalid)
+
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, invalid
+	}
+	var encoded encodedCursor
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return nil, invalid
+	}
+	if encoded.Sort != signature(sorts) {
+		return nil, fmt.Errorf("%w: cursor dibuat untuk urutan yang berbeda", ErrInvalid)
+	}
+	if len(sorts) == 0 || len(encoded.Values) != len(sorts) {
+		return nil, invalid
+	}
+
+	cursor := &Cursor{Backward: encoded.Backward}
+	for i, value := range encoded.Values {
+		if value == nil {
+			cursor.Values = append(cursor.Values, nil)
+			continue
+		}
+		parsed, err := parseValue(sorts[i].Kind, *value)
+		if err != nil {
+			return nil, invalid
+		}
+		cursor.Values = append(cursor.Values, parsed)
+	}
+	return cursor, nil
+}
+
+func signature(sorts []Sort) string {
+	parts := make([]string, len(sorts))
+	for i, sort := range sorts {
+		parts[i] = sort.Column
+		if sort.Desc {
+			parts[i] = "-" + sort.Column
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// formatValue writes a sort value read from a row the way parseValue reads
+// it back.
+func formatValue(value any) (*string, error) {
+	rv := reflect.ValueOf(value)
+	for rv.IsValid() && rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return nil, nil
+		}
+		rv = rv.Elem()
+	}
+	if !rv.IsValid() {
+		return nil, nil
+	}
+	value = rv.Interface()
+
+	if t, ok := value.(time.Time); ok {
+		formatted := t.Format(time.RFC3339Nano)
+		return &formatted, nil
+	}
+	if valuer, ok := value.(driver.Valuer); ok {
+		v, err := valuer.Value()
+		if err != nil {
+			return nil, err
+		}
+		return formatValue(v)
+	}
+
+	formatted := fmt.Sprint(value)
+	return &formatted, nil
+}
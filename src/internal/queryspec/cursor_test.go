@@ -0,0 +1,166 @@
+package queryspec
+
+import (
+	"encoding/base64"
+	"errors"
+	"net/url"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestCursorRoundTrip(t *testing.T) {
+	created := time.Date(2024, 5, 1, 8, 30, 15, 123456789, time.UTC)
+	title := "Aljabar"
+
+	tests := []struct {
+		name     string
+		sort     string
+		values   []any
+		backward bool
+		want     []any
+	}{
+		{"id only", "", []any{int64(42)}, false, []any{int64(42)}},
+		{"time and id", "-created_at", []any{created, int64(7)}, false, []any{created, int64(7)}},
+		{"pointers", "title", []any{&title, new(int64)}, true, []any{title, int64(0)}},
+		{"null sort value", "title", []any{(*string)(nil), int64(3)}, true, []any{nil, int64(3)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(url.Values{"sort": {tt.sort}}, testFields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			raw, err := spec.encode(tt.values, tt.backward)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			next, err := Parse(url.Values{"sort": {tt.sort}, "cursor": {raw}}, testFields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if next.Cursor.Backward != tt.backward {
+				t.Errorf("backward = %v, want %v", next.Cursor.Backward, tt.backward)
+			}
+			for i, value := range next.Cursor.Values {
+				if got, ok := value.(time.Time); ok {
+					if !got.Equal(tt.want[i].(time.Time)) {
+						t.Errorf("value %d = %v, want %v", i, got, tt.want[i])
+					}
+					continue
+				}
+				if !reflect.DeepEqual(value, tt.want[i]) {
+					t.Errorf("value %d = %#v, want %#v", i, value, tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestCursorRejects(t *testing.T) {
+	spec, err := Parse(url.Values{"sort": {"-created_at"}}, testFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	valid, err := spec.encode([]any{time.Now(), int64(1)}, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encode := func(raw string) string {
+		return base64.RawURLEncoding.EncodeToString([]byte(raw))
+	}
+
+	tests := []struct {
+		name   string
+		sort   string
+		cursor string
+	}{
+		{"sort direction changed", "created_at", valid},
+		{"sort field changed", "-title", valid},
+		{"sort dropped", "", valid},
+		{"malformed base64", "-created_at", "not*base64!"},
+		{"padded base64", "-created_at", valid + "=="},
+		{"not json", "-created_at", encode("created_at")},
+		{"too few values", "-created_at", encode(`{"s":"-m_course.created_at,-m_course.id","v":["2024-05-01T00:00:00Z"]}`)},
+		{"value of the wrong kind", "-created_at", encode(`{"s":"-m_course.created_at,-m_course.id","v":["kemarin","1"]}`)},
+		{"column outside the whitelist", "-created_at", encode(`{"s":"-m_course.secret,-m_course.id","v":["x","1"]}`)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(url.Values{"sort": {tt.sort}, "cursor": {tt.cursor}}, testFields)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("err = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestPageCursors(t *testing.T) {
+	first, last := []any{int64(1)}, []any{int64(10)}
+
+	tests := []struct {
+		name     string
+		cursor   *Cursor
+		more     bool
+		wantNext bool
+		wantPrev bool
+	}{
+		{"first page with more", nil, true, true, false},
+		{"only page", nil, false, false, false},
+		{"middle page forward", &Cursor{Values: []any{int64(0)}}, true, true, true},
+		{"last page forward", &Cursor{Values: []any{int64(0)}}, false, false, true},
+		{"middle page backward", &Cursor{Values: []any{int64(11)}, Backward: true}, true, true, true},
+		{"first page reached backward", &Cursor{Values: []any{int64(11)}, Backward: true}, false, true, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := Spec{Sorts: []Sort{{Column: "m_course.id", Kind: Int}}, Limit: 10, Cursor: tt.cursor}
+			page, err := spec.Page(first, last, tt.more)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if (page.NextCursor != "") != tt.wantNext || (page.PrevCursor != "") != tt.wantPrev {
+				t.Errorf("next = %q, prev = %q", page.NextCursor, page.PrevCursor)
+			}
+		})
+	}
+}
+
+func TestKeyset(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sorts := []Sort{{Column: "title", Kind: String}, {Column: "id", Kind: Int}}
+
+	tests := []struct {
+		name   string
+		cursor *Cursor
+		want   string
+	}{
+		{"forward", &Cursor{Values: []any{"b", int64(5)}}, `((title > 'b' OR title IS NULL) OR title = 'b' AND (id > 5 OR id IS NULL))`},
+		{"backward", &Cursor{Values: []any{"b", int64(5)}, Backward: true}, `(title < 'b' OR title = 'b' AND id < 5)`},
+		{"forward from null", &Cursor{Values: []any{nil, int64(5)}}, `(title IS NULL AND (id > 5 OR id IS NULL))`},
+		{"backward from null", &Cursor{Values: []any{nil, int64(5)}, Backward: true}, `(title IS NOT NULL OR title IS NULL AND id < 5)`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec := Spec{Sorts: sorts, Cursor: tt.cursor}
+			query := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				return spec.Keyset(tx.Table("m_course")).Find(&[]map[string]any{})
+			})
+			if !strings.Contains(query, tt.want) {
+				t.Errorf("query = %s, want %s", query, tt.want)
+			}
+		})
+	}
+}
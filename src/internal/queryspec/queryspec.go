@@ -0,0 +1,292 @@
+// Package queryspec turns list query strings into filters and sorts over a
+// whitelist of fields. Models declare the fields they expose; anything else
+// is rejected, and values only ever reach SQL as bind parameters.
+//
+// Filters are written as filter[field][op]=value, or filter[field]=value for
+// eq. "in" takes a comma separated list. Sorts are a comma separated list of
+// fields, each descending when prefixed with "-":
+//
+//	?filter[status]=published&filter[id][in]=1,2,3&sort=-created_at,title
+//
+// Lists are paged with limit and an opaque cursor taken from the next_cursor
+// or prev_cursor of the previous response; see cursor.go.
+package queryspec
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrInvalid wraps every error caused by the query string itself.
+var ErrInvalid = errors.New("query tidak valid")
+
+type Kind int
+
+const (
+	String Kind = iota
+	Int
+	Bool
+	Time
+)
+
+type Op string
+
+const (
+	Eq       Op = "eq"
+	Ne       Op = "ne"
+	In       Op = "in"
+	Gt       Op = "gt"
+	Gte      Op = "gte"
+	Lt       Op = "lt"
+	Lte      Op = "lte"
+	Contains Op = "contains"
+)
+
+// ops lists the operators that make sense for each kind.
+var ops = map[Kind][]Op{
+	String: {Eq, Ne, In, Contains},
+	Int:    {Eq, Ne, In, Gt, Gte, Lt, Lte},
+	Bool:   {Eq, Ne},
+	Time:   {Eq, Ne, Gt, Gte, Lt, Lte},
+}
+
+// Field is a column a list endpoint may expose under a public name.
+type Field struct {
+	Column string
+	Kind   Kind
+	Filter bool
+	Sort   bool
+}
+
+// Fields maps public field names to columns.
+type Fields map[string]Field
+
+// Queryable is implemented by models that can be listed with a Spec.
+type Queryable interface {
+	QueryFields() Fields
+}
+
+type Filter struct {
+	Column string
+	Op     Op
+	Values []any
+}
+
+type Sort struct {
+	Column string
+	Kind   Kind
+	Desc   bool
+}
+
+// Spec is a parsed, validated query. Limit is zero when the whole list was
+// asked for.
+type Spec struct {
+	Filters []Filter
+	Sorts   []Sort
+	Limit   int
+	Cursor  *Cursor
+}
+
+// Parse reads the filters, sorts and paging in query. The legacy "order"
+// parameter still sets the direction of sort fields without a prefix. When
+// fields has an id it always ends the sort, so rows with equal sort values
+// keep a stable order across pages.
+func Parse(query url.Values, fields Fields) (Spec, error) {
+	var spec Spec
+
+	for key, values := range query {
+		name, op, ok := filterKey(key)
+		if !ok {
+			continue
+		}
+
+		field, ok := fields[name]
+		if !ok || !field.Filter {
+			return Spec{}, fmt.Errorf("%w: field %s tidak bisa difilter", ErrInvalid, name)
+		}
+		if !allowed(field.Kind, op) {
+			return Spec{}, fmt.Errorf("%w: operator %s tidak berlaku untuk %s", ErrInvalid, op, name)
+		}
+
+		for _, raw := range values {
+			filter, err := parseFilter(field, op, raw)
+			if err != nil {
+				return Spec{}, fmt.Errorf("%w: %s: %v", ErrInvalid, name, err)
+			}
+			spec.Filters = append(spec.Filters, filter)
+		}
+	}
+
+	desc := strings.EqualFold(query.Get("order"), "desc")
+	for _, name := range strings.Split(query.Get("sort"), ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		sort := Sort{Desc: desc}
+		switch name[0] {
+		case '-':
+			sort.Desc, name = true, name[1:]
+		case '+':
+			sort.Desc, name = false, name[1:]
+		}
+
+		field, ok := fields[name]
+		if !ok || !field.Sort {
+			return Spec{}, fmt.Errorf("%w: field %s tidak bisa diurutkan", ErrInvalid, name)
+		}
+		sort.Column, sort.Kind = field.Column, field.Kind
+		spec.Sorts = append(spec.Sorts, sort)
+	}
+	spec.Sorts = withTiebreak(spec.Sorts, fields)
+
+	if raw := query.Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 0 {
+			return Spec{}, fmt.Errorf("%w: limit harus bilangan bulat positif", ErrInvalid)
+		}
+		spec.Limit = limit
+	}
+
+	if raw := query.Get("cursor"); raw != "" {
+		cursor, err := decodeCursor(raw, spec.Sorts)
+		if err != nil {
+			return Spec{}, err
+		}
+		spec.Cursor = cursor
+	}
+
+	return spec, nil
+}
+
+// Where applies the filters to db.
+func (s Spec) Where(db *gorm.DB) *gorm.DB {
+	for _, f := range s.Filters {
+		switch f.Op {
+		case Eq:
+			db = db.Where(f.Column+" = ?", f.Values[0])
+		case Ne:
+			db = db.Where(f.Column+" <> ?", f.Values[0])
+		case In:
+			db = db.Where(f.Column+" IN ?", f.Values)
+		case Gt:
+			db = db.Where(f.Column+" > ?", f.Values[0])
+		case Gte:
+			db = db.Where(f.Column+" >= ?", f.Values[0])
+		case Lt:
+			db = db.Where(f.Column+" < ?", f.Values[0])
+		case Lte:
+			db = db.Where(f.Column+" <= ?", f.Values[0])
+		case Contains:
+			db = db.Where(f.Column+" ILIKE ?", f.Values[0])
+		}
+	}
+	return db
+}
+
+// Order applies the sorts to db, reversed when paging backwards.
+func (s Spec) Order(db *gorm.DB) *gorm.DB {
+	backward := s.Cursor != nil && s.Cursor.Backward
+	for _, sort := range s.Sorts {
+		db = db.Order(clause.OrderByColumn{Column: clause.Column{Name: sort.Column, Raw: true}, Desc: sort.Desc != backward})
+	}
+	return db
+}
+
+// withTiebreak appends the id field to sorts unless it is already there.
+func withTiebreak(sorts []Sort, fields Fields) []Sort {
+	id, ok := fields["id"]
+	if !ok {
+		return sorts
+	}
+	desc := false
+	for _, sort := range sorts {
+		if sort.Column == id.Column {
+			return sorts
+		}
+		desc = sort.Desc
+	}
+	return append(sorts, Sort{Column: id.Column, Kind: id.Kind, Desc: desc})
+}
+
+// filterKey splits filter[name] and filter[name][op].
+func filterKey(key string) (string, Op, bool) {
+	rest, ok := strings.CutPrefix(key, "filter[")
+	if !ok {
+		return "", "", false
+	}
+
+	name, rest, ok := strings.Cut(rest, "]")
+	if !ok || name == "" {
+		return "", "", false
+	}
+	if rest == "" {
+		return name, Eq, true
+	}
+
+	op, ok := strings.CutPrefix(rest, "[")
+	if !ok || !strings.HasSuffix(op, "]") {
+		return "", "", false
+	}
+	return name, Op(strings.TrimSuffix(op, "]")), true
+}
+
+func allowed(kind Kind, op Op) bool {
+	for _, candidate := range ops[kind] {
+		if candidate == op {
+			return true
+		}
+	}
+	return false
+}
+
+func parseFilter(field Field, op Op, raw string) (Filter, error) {
+	filter := Filter{Column: field.Column, Op: op}
+
+	if op == Contains {
+		filter.Values = []any{"%" + escapeLike(raw) + "%"}
+		return filter, nil
+	}
+
+	parts := []string{raw}
+	if op == In {
+		parts = strings.Split(raw, ",")
+	}
+	for _, part := range parts {
+		value, err := parseValue(field.Kind, strings.TrimSpace(part))
+		if err != nil {
+			return Filter{}, err
+		}
+		filter.Values = append(filter.Values, value)
+	}
+	return filter, nil
+}
+
+func parseValue(kind Kind, raw string) (any, error) {
+	switch kind {
+	case Int:
+		return strconv.ParseInt(raw, 10, 64)
+	case Bool:
+		return strconv.ParseBool(raw)
+	case Time:
+		if t, err := time.Parse(time.RFC3339, raw); err == nil {
+			return t, nil
+		}
+		return time.Parse(time.DateOnly, raw)
+	default:
+		return raw, nil
+	}
+}
+
+// escapeLike keeps % and _ in a contains filter literal.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,165 @@
+package queryspec
+
+import (
+	"errors"
+	"net/url"
+	"reflect"
+	"testing"
+	"time"
+)
+
+var testFields = Fields{
+	"id":         {Column: "m_course.id", Kind: Int, Filter: true, Sort: true},
+	"title":      {Column: "m_course.title", Kind: String, Filter: true, Sort: true},
+	"status":     {Column: "m_course.status", Kind: String, Filter: true},
+	"isactive":   {Column: "m_course.isactive", Kind: Bool, Filter: true},
+	"created_at": {Column: "m_course.created_at", Kind: Time, Filter: true, Sort: true},
+	"secret":     {Column: "m_course.secret", Kind: String},
+}
+
+func TestParseFilters(t *testing.T) {
+	day := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []Filter
+	}{
+		{"eq without op", "filter[status]=published", []Filter{{Column: "m_course.status", Op: Eq, Values: []any{"published"}}}},
+		{"explicit eq", "filter[status][eq]=draft", []Filter{{Column: "m_course.status", Op: Eq, Values: []any{"draft"}}}},
+		{"ne", "filter[status][ne]=draft", []Filter{{Column: "m_course.status", Op: Ne, Values: []any{"draft"}}}},
+		{"in", "filter[id][in]=1, 2,3", []Filter{{Column: "m_course.id", Op: In, Values: []any{int64(1), int64(2), int64(3)}}}},
+		{"gt", "filter[id][gt]=10", []Filter{{Column: "m_course.id", Op: Gt, Values: []any{int64(10)}}}},
+		{"lte", "filter[id][lte]=10", []Filter{{Column: "m_course.id", Op: Lte, Values: []any{int64(10)}}}},
+		{"bool", "filter[isactive]=true", []Filter{{Column: "m_course.isactive", Op: Eq, Values: []any{true}}}},
+		{"date", "filter[created_at][gte]=2024-05-01", []Filter{{Column: "m_course.created_at", Op: Gte, Values: []any{day}}}},
+		{"rfc3339", "filter[created_at][lt]=2024-05-01T00:00:00Z", []Filter{{Column: "m_course.created_at", Op: Lt, Values: []any{day}}}},
+		{"contains escapes wildcards", "filter[title][contains]=50%25_off", []Filter{{Column: "m_course.title", Op: Contains, Values: []any{`%50\%\_off%`}}}},
+		{"unrelated keys ignored", "page=2&filters=x&filter[]=y", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			spec, err := Parse(mustQuery(t, tt.query), testFields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec.Filters, tt.want) {
+				t.Errorf("filters = %#v, want %#v", spec.Filters, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseRejects(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+	}{
+		{"unknown field", "filter[password]=x"},
+		{"field not filterable", "filter[secret]=x"},
+		{"unknown op", "filter[status][like]=x"},
+		{"op not for kind", "filter[status][gt]=a"},
+		{"contains on int", "filter[id][contains]=1"},
+		{"range on bool", "filter[isactive][lt]=true"},
+		{"bad int", "filter[id]=abc"},
+		{"bad int in list", "filter[id][in]=1,x"},
+		{"bad bool", "filter[isactive]=maybe"},
+		{"bad time", "filter[created_at][gt]=yesterday"},
+		{"unknown sort", "sort=password"},
+		{"sort not sortable", "sort=status"},
+		{"negative limit", "limit=-1"},
+		{"bad limit", "limit=ten"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(mustQuery(t, tt.query), testFields)
+			if !errors.Is(err, ErrInvalid) {
+				t.Errorf("err = %v, want ErrInvalid", err)
+			}
+		})
+	}
+}
+
+func TestParseSorts(t *testing.T) {
+	tests := []struct {
+		name   string
+		query  string
+		fields Fields
+		want   []Sort
+	}{
+		{
+			name:  "none",
+			query: "",
+			want:  []Sort{{Column: "m_course.id", Kind: Int}},
+		},
+		{
+			name:  "desc then asc, id tiebreak follows the last",
+			query: "sort=-created_at,title",
+			want: []Sort{
+				{Column: "m_course.created_at", Kind: Time, Desc: true},
+				{Column: "m_course.title", Kind: String},
+				{Column: "m_course.id", Kind: Int},
+			},
+		},
+		{
+			name:  "legacy order applies to unprefixed fields",
+			query: "sort=title,%2Bcreated_at&order=DESC",
+			want: []Sort{
+				{Column: "m_course.title", Kind: String, Desc: true},
+				{Column: "m_course.created_at", Kind: Time},
+				{Column: "m_course.id", Kind: Int},
+			},
+		},
+		{
+			name:  "explicit id is not repeated",
+			query: "sort=-id,title",
+			want: []Sort{
+				{Column: "m_course.id", Kind: Int, Desc: true},
+				{Column: "m_course.title", Kind: String},
+			},
+		},
+		{
+			name:   "no tiebreak without an id field",
+			query:  "sort=title",
+			fields: Fields{"title": testFields["title"]},
+			want:   []Sort{{Column: "m_course.title", Kind: String}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := tt.fields
+			if fields == nil {
+				fields = testFields
+			}
+			spec, err := Parse(mustQuery(t, tt.query), fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(spec.Sorts, tt.want) {
+				t.Errorf("sorts = %+v, want %+v", spec.Sorts, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseLimit(t *testing.T) {
+	spec, err := Parse(mustQuery(t, "limit=25"), testFields)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if spec.Limit != 25 || spec.Cursor != nil {
+		t.Errorf("limit = %d, cursor = %v", spec.Limit, spec.Cursor)
+	}
+}
+
+func mustQuery(t *testing.T, raw string) url.Values {
+	t.Helper()
+	query, err := url.ParseQuery(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return query
+}
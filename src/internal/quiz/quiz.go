@@ -0,0 +1,219 @@
+// Package quiz checks quiz items and grades answers to them. Grading only
+// depends on the item and the response, so attempts can be regraded.
+package quiz
+
+import (
+	"errors"
+	"fmt"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"math"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrInvalidItem wraps every error caused by an item that can't be stored.
+var ErrInvalidItem = errors.New("soal kuis tidak valid")
+
+// ErrInvalidResponse wraps every error caused by an answer that doesn't fit
+// its item.
+var ErrInvalidResponse = errors.New("jawaban kuis tidak valid")
+
+const (
+	maxPrompt   = 5000
+	maxOption   = 500
+	maxOptions  = 10
+	maxAccepted = 20
+	maxAnswer   = 500
+	maxPoints   = 1000
+)
+
+// Prepare validates item and clears the fields its type doesn't use.
+// Options keep the ID they had in previous, the stored options of the item
+// when it is edited; new options are numbered after the highest one.
+func Prepare(item *models.MQuizItem, previous []models.QuizOption) error {
+	item.Prompt = strings.TrimSpace(item.Prompt)
+	if item.Prompt == "" || utf8.RuneCountInString(item.Prompt) > maxPrompt {
+		return fmt.Errorf("%w: prompt wajib diisi, maksimal %d karakter", ErrInvalidItem, maxPrompt)
+	}
+	if item.Points < 1 || item.Points > maxPoints {
+		return fmt.Errorf("%w: points harus antara 1 dan %d", ErrInvalidItem, maxPoints)
+	}
+
+	switch item.Type {
+	case constant.QuizItemSingleChoice, constant.QuizItemMultipleChoice, constant.QuizItemTrueFalse:
+		if err := prepareOptions(item, previous); err != nil {
+			return err
+		}
+		item.NumericAnswer = nil
+		item.Tolerance = 0
+		item.AcceptedAnswers = []string{}
+	case constant.QuizItemNumeric:
+		if item.NumericAnswer == nil || math.IsNaN(*item.NumericAnswer) || math.IsInf(*item.NumericAnswer, 0) {
+			return fmt.Errorf("%w: numeric_answer wajib diisi", ErrInvalidItem)
+		}
+		if item.Tolerance < 0 || math.IsNaN(item.Tolerance) || math.IsInf(item.Tolerance, 0) {
+			return fmt.Errorf("%w: tolerance tidak boleh negatif", ErrInvalidItem)
+		}
+		item.Options = []models.QuizOption{}
+		item.AcceptedAnswers = []string{}
+	case constant.QuizItemShortAnswer:
+		var accepted []string
+		for _, answer := range item.AcceptedAnswers {
+			answer = strings.TrimSpace(answer)
+			if answer == "" || utf8.RuneCountInString(answer) > maxAnswer {
+				return fmt.Errorf("%w: jawaban wajib diisi, maksimal %d karakter", ErrInvalidItem, maxAnswer)
+			}
+			accepted = append(accepted, answer)
+		}
+		if len(accepted) == 0 || len(accepted) > maxAccepted {
+			return fmt.Errorf("%w: accepted_answers harus berisi 1 sampai %d jawaban", ErrInvalidItem, maxAccepted)
+		}
+		item.AcceptedAnswers = accepted
+		item.Options = []models.QuizOption{}
+		item.NumericAnswer = nil
+		item.Tolerance = 0
+	default:
+		return fmt.Errorf("%w: type harus salah satu dari %s", ErrInvalidItem, strings.Join(constant.QuizItemTypes, ", "))
+	}
+	return nil
+}
+
+// TrueFalseOptions returns the options of a true/false item whose answer is
+// answer.
+func TrueFalseOptions(answer bool) []models.QuizOption {
+	return []models.QuizOption{
+		{ID: 1, Text: "Benar", Correct: answer},
+		{ID: 2, Text: "Salah", Correct: !answer},
+	}
+}
+
+func prepareOptions(item *models.MQuizItem, previous []models.QuizOption) error {
+	if len(item.Options) < 2 || len(item.Options) > maxOptions {
+		return fmt.Errorf("%w: options harus berisi 2 sampai %d pilihan", ErrInvalidItem, maxOptions)
+	}
+	if item.Type == constant.QuizItemTrueFalse && len(item.Options) != 2 {
+		return fmt.Errorf("%w: soal benar/salah harus memiliki 2 pilihan", ErrInvalidItem)
+	}
+
+	known := map[int]bool{}
+	next := 0
+	for _, option := range previous {
+		known[option.ID] = true
+		next = max(next, option.ID)
+	}
+
+	used := map[int]bool{}
+	correct := 0
+	for i := range item.Options {
+		option := &item.Options[i]
+		option.Text = strings.TrimSpace(option.Text)
+		if option.Text == "" || utf8.RuneCountInString(option.Text) > maxOption {
+			return fmt.Errorf("%w: teks pilihan wajib diisi, maksimal %d karakter", ErrInvalidItem, maxOption)
+		}
+		if !known[option.ID] || used[option.ID] {
+			next++
+			option.ID = next
+		}
+		used[option.ID] = true
+		if option.Correct {
+			correct++
+		}
+	}
+
+	if item.Type == constant.QuizItemMultipleChoice {
+		if correct == 0 {
+			return fmt.Errorf("%w: minimal satu pilihan harus benar", ErrInvalidItem)
+		}
+	} else if correct != 1 {
+		return fmt.Errorf("%w: tepat satu pilihan harus benar", ErrInvalidItem)
+	}
+	return nil
+}
+
+// CheckResponse makes sure a response only names options of the item and
+// keeps text answers short. Unanswered items are fine.
+func CheckResponse(item models.MQuizItem, response models.QuizResponse) error {
+	for _, id := range response.OptionIDs {
+		if !slices.ContainsFunc(item.Options, func(o models.QuizOption) bool { return o.ID == id }) {
+			return fmt.Errorf("%w: pilihan %d tidak ada pada soal %d", ErrInvalidResponse, id, item.ID)
+		}
+	}
+	if response.Number != nil && (math.IsNaN(*response.Number) || math.IsInf(*response.Number, 0)) {
+		return fmt.Errorf("%w: angka pada soal %d tidak valid", ErrInvalidResponse, item.ID)
+	}
+	if response.Text != nil && utf8.RuneCountInString(*response.Text) > maxAnswer {
+		return fmt.Errorf("%w: jawaban soal %d maksimal %d karakter", ErrInvalidResponse, item.ID, maxAnswer)
+	}
+	return nil
+}
+
+// Grade tells whether response answers item right. Choice items need
+// exactly the correct options, numeric items a number within the tolerance
+// and short answers one of the accepted answers, compared without case
+// unless the item is case sensitive and ignoring extra spaces.
+func Grade(item models.MQuizItem, response models.QuizResponse) bool {
+	switch item.Type {
+	case constant.QuizItemSingleChoice, constant.QuizItemMultipleChoice, constant.QuizItemTrueFalse:
+		chosen := map[int]bool{}
+		for _, id := range response.OptionIDs {
+			chosen[id] = true
+		}
+		if len(chosen) == 0 || (item.Type != constant.QuizItemMultipleChoice && len(chosen) != 1) {
+			return false
+		}
+		for _, option := range item.Options {
+			if option.Correct != chosen[option.ID] {
+				return false
+			}
+		}
+		return true
+	case constant.QuizItemNumeric:
+		if response.Number == nil || item.NumericAnswer == nil {
+			return false
+		}
+		return math.Abs(*response.Number-*item.NumericAnswer) <= item.Tolerance+1e-9
+	case constant.QuizItemShortAnswer:
+		if response.Text == nil {
+			return false
+		}
+		answer := normalize(*response.Text, item.CaseSensitive)
+		return answer != "" && slices.ContainsFunc(item.AcceptedAnswers, func(accepted string) bool {
+			return normalize(accepted, item.CaseSensitive) == answer
+		})
+	default:
+		return false
+	}
+}
+
+func normalize(s string, caseSensitive bool) string {
+	s = strings.Join(strings.Fields(s), " ")
+	if !caseSensitive {
+		s = strings.ToLower(s)
+	}
+	return s
+}
+
+// KeptScore returns the score that counts for a student given the scores of
+// their submitted attempts, oldest first, or 0 when there are none. The
+// average is rounded to the nearest point.
+func KeptScore(policy string, scores []int) int {
+	if len(scores) == 0 {
+		return 0
+	}
+	switch policy {
+	case constant.QuizScoreFirst:
+		return scores[0]
+	case constant.QuizScoreLast, constant.QuizScoreLatest:
+		return scores[len(scores)-1]
+	case constant.QuizScoreAverage:
+		total := 0
+		for _, score := range scores {
+			total += score
+		}
+		return int(math.Round(float64(total) / float64(len(scores))))
+	default:
+		return slices.Max(scores)
+	}
+}
@@ -0,0 +1,253 @@
+package quiz
+
+import (
+	"errors"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestGrade(t *testing.T) {
+	single := models.MQuizItem{Type: constant.QuizItemSingleChoice, Options: []models.QuizOption{
+		{ID: 1, Text: "3"}, {ID: 2, Text: "4", Correct: true}, {ID: 3, Text: "5"},
+	}}
+	multiple := models.MQuizItem{Type: constant.QuizItemMultipleChoice, Options: []models.QuizOption{
+		{ID: 1, Text: "2", Correct: true}, {ID: 2, Text: "4"}, {ID: 3, Text: "5", Correct: true},
+	}}
+	trueFalse := models.MQuizItem{Type: constant.QuizItemTrueFalse, Options: TrueFalseOptions(false)}
+	numeric := models.MQuizItem{Type: constant.QuizItemNumeric, NumericAnswer: ptr(3.14), Tolerance: 0.01}
+	exact := models.MQuizItem{Type: constant.QuizItemNumeric, NumericAnswer: ptr(0.3)}
+	short := models.MQuizItem{Type: constant.QuizItemShortAnswer, AcceptedAnswers: []string{"Ibu Kota", "Jakarta"}}
+	caseSensitive := models.MQuizItem{Type: constant.QuizItemShortAnswer, AcceptedAnswers: []string{"NaCl"}, CaseSensitive: true}
+
+	tests := []struct {
+		name     string
+		item     models.MQuizItem
+		response models.QuizResponse
+		want     bool
+	}{
+		{"single right", single, options(2), true},
+		{"single wrong", single, options(1), false},
+		{"single with two picks", single, options(2, 3), false},
+		{"single unanswered", single, options(), false},
+		{"multiple all right", multiple, options(3, 1), true},
+		{"multiple repeated pick", multiple, options(1, 1, 3), true},
+		{"multiple missing one", multiple, options(1), false},
+		{"multiple with a wrong one", multiple, options(1, 2, 3), false},
+		{"multiple unanswered", multiple, options(), false},
+		{"true/false right", trueFalse, options(2), true},
+		{"true/false wrong", trueFalse, options(1), false},
+		{"true/false both", trueFalse, options(1, 2), false},
+		{"numeric exact", numeric, number(3.14), true},
+		{"numeric within tolerance", numeric, number(3.15), true},
+		{"numeric outside tolerance", numeric, number(3.16), false},
+		{"numeric float rounding", exact, number(0.1 + 0.2), true},
+		{"numeric unanswered", numeric, models.QuizResponse{}, false},
+		{"short exact", short, text("Jakarta"), true},
+		{"short ignores case and spaces", short, text("  ibu   KOTA "), true},
+		{"short wrong", short, text("Bandung"), false},
+		{"short blank", short, text("   "), false},
+		{"short unanswered", short, models.QuizResponse{}, false},
+		{"case sensitive right", caseSensitive, text("NaCl"), true},
+		{"case sensitive wrong case", caseSensitive, text("nacl"), false},
+		{"unknown type", models.MQuizItem{Type: "essay"}, text("x"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Grade(tt.item, tt.response); got != tt.want {
+				t.Errorf("Grade = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPrepare(t *testing.T) {
+	tests := []struct {
+		name    string
+		item    models.MQuizItem
+		wantErr bool
+	}{
+		{"single choice", choiceItem(constant.QuizItemSingleChoice, true, false), false},
+		{"single with two correct", choiceItem(constant.QuizItemSingleChoice, true, true), true},
+		{"single with none correct", choiceItem(constant.QuizItemSingleChoice, false, false), true},
+		{"multiple with two correct", choiceItem(constant.QuizItemMultipleChoice, true, true), false},
+		{"multiple with none correct", choiceItem(constant.QuizItemMultipleChoice, false, false), true},
+		{"one option", choiceItem(constant.QuizItemSingleChoice, true), true},
+		{"true/false", models.MQuizItem{Type: constant.QuizItemTrueFalse, Prompt: "Air mendidih pada 100°C", Points: 1, Options: TrueFalseOptions(true)}, false},
+		{"true/false with three options", choiceItem(constant.QuizItemTrueFalse, true, false, false), true},
+		{"blank option", models.MQuizItem{Type: constant.QuizItemSingleChoice, Prompt: "p", Points: 1, Options: []models.QuizOption{{Text: "a", Correct: true}, {Text: " "}}}, true},
+		{"numeric", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "p", Points: 1, NumericAnswer: ptr(2)}, false},
+		{"numeric without answer", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "p", Points: 1}, true},
+		{"numeric NaN", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "p", Points: 1, NumericAnswer: ptr(math.NaN())}, true},
+		{"numeric negative tolerance", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "p", Points: 1, NumericAnswer: ptr(2), Tolerance: -1}, true},
+		{"short answer", models.MQuizItem{Type: constant.QuizItemShortAnswer, Prompt: "p", Points: 1, AcceptedAnswers: []string{" Jakarta "}}, false},
+		{"short answer without answers", models.MQuizItem{Type: constant.QuizItemShortAnswer, Prompt: "p", Points: 1}, true},
+		{"short answer blank answer", models.MQuizItem{Type: constant.QuizItemShortAnswer, Prompt: "p", Points: 1, AcceptedAnswers: []string{"a", ""}}, true},
+		{"blank prompt", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "  ", Points: 1, NumericAnswer: ptr(2)}, true},
+		{"long prompt", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: strings.Repeat("x", maxPrompt+1), Points: 1, NumericAnswer: ptr(2)}, true},
+		{"zero points", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "p", NumericAnswer: ptr(2)}, true},
+		{"too many points", models.MQuizItem{Type: constant.QuizItemNumeric, Prompt: "p", Points: maxPoints + 1, NumericAnswer: ptr(2)}, true},
+		{"unknown type", models.MQuizItem{Type: "essay", Prompt: "p", Points: 1}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Prepare(&tt.item, nil)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidItem) {
+				t.Errorf("err = %v, want ErrInvalidItem", err)
+			}
+		})
+	}
+}
+
+func TestPrepareClearsUnusedFields(t *testing.T) {
+	item := models.MQuizItem{
+		Type:            constant.QuizItemShortAnswer,
+		Prompt:          " Ibu kota Indonesia? ",
+		Points:          2,
+		Options:         []models.QuizOption{{Text: "a"}},
+		NumericAnswer:   ptr(1),
+		Tolerance:       1,
+		AcceptedAnswers: []string{" Jakarta "},
+	}
+	if err := Prepare(&item, nil); err != nil {
+		t.Fatal(err)
+	}
+	if item.Prompt != "Ibu kota Indonesia?" || len(item.Options) != 0 || item.NumericAnswer != nil || item.Tolerance != 0 {
+		t.Errorf("item = %+v", item)
+	}
+	if len(item.AcceptedAnswers) != 1 || item.AcceptedAnswers[0] != "Jakarta" {
+		t.Errorf("accepted answers = %q", item.AcceptedAnswers)
+	}
+}
+
+func TestPrepareKeepsOptionIDs(t *testing.T) {
+	previous := []models.QuizOption{{ID: 1, Text: "a"}, {ID: 2, Text: "b"}, {ID: 5, Text: "c"}}
+	item := models.MQuizItem{Type: constant.QuizItemSingleChoice, Prompt: "p", Points: 1, Options: []models.QuizOption{
+		{ID: 5, Text: "c", Correct: true}, // kept
+		{ID: 5, Text: "c again"},          // duplicate, renumbered
+		{ID: 9, Text: "forged"},           // unknown, renumbered
+		{Text: "new"},                     // new
+		{ID: 1, Text: "a"},                // kept
+	}}
+	if err := Prepare(&item, previous); err != nil {
+		t.Fatal(err)
+	}
+
+	want := []int{5, 6, 7, 8, 1}
+	for i, option := range item.Options {
+		if option.ID != want[i] {
+			t.Errorf("option %d id = %d, want %d", i, option.ID, want[i])
+		}
+	}
+}
+
+func TestCheckResponse(t *testing.T) {
+	item := models.MQuizItem{ID: 3, Type: constant.QuizItemSingleChoice, Options: TrueFalseOptions(true)}
+
+	tests := []struct {
+		name     string
+		response models.QuizResponse
+		wantErr  bool
+	}{
+		{"unanswered", models.QuizResponse{}, false},
+		{"known option", options(1), false},
+		{"unknown option", options(3), true},
+		{"number", number(2), false},
+		{"infinite number", number(math.Inf(1)), true},
+		{"text", text("Benar"), false},
+		{"long text", text(strings.Repeat("x", maxAnswer+1)), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := CheckResponse(item, tt.response)
+			if tt.wantErr != (err != nil) {
+				t.Fatalf("err = %v, want error %v", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, ErrInvalidResponse) {
+				t.Errorf("err = %v, want ErrInvalidResponse", err)
+			}
+		})
+	}
+}
+
+func TestKeptScore(t *testing.T) {
+	scores := []int{6, 9, 4, 7}
+
+	tests := []struct {
+		policy string
+		scores []int
+		want   int
+	}{
+		{constant.QuizScoreHighest, scores, 9},
+		{constant.QuizScoreFirst, scores, 6},
+		{constant.QuizScoreLast, scores, 7},
+		{constant.QuizScoreLatest, scores, 7},
+		{constant.QuizScoreAverage, scores, 7},
+		{constant.QuizScoreAverage, []int{1, 2}, 2},
+		{constant.QuizScoreAverage, []int{1, 1, 2}, 1},
+		{constant.QuizScoreHighest, []int{5}, 5},
+		{constant.QuizScoreFirst, []int{5}, 5},
+		{constant.QuizScoreLast, []int{5}, 5},
+		{constant.QuizScoreAverage, []int{5}, 5},
+		{constant.QuizScoreHighest, nil, 0},
+		{constant.QuizScoreFirst, nil, 0},
+		{constant.QuizScoreLast, nil, 0},
+		{constant.QuizScoreAverage, nil, 0},
+	}
+
+	for _, tt := range tests {
+		if got := KeptScore(tt.policy, tt.scores); got != tt.want {
+			t.Errorf("KeptScore(%s, %v) = %d, want %d", tt.policy, tt.scores, got, tt.want)
+		}
+	}
+}
+
+// The course score moves by the change in the kept score on each submission,
+// so the deltas of a run of attempts must add up to the final kept score.
+func TestKeptScoreDeltasAddUp(t *testing.T) {
+	scores := []int{6, 9, 4, 7, 10, 0}
+
+	for _, policy := range constant.QuizScorePolicies {
+		t.Run(policy, func(t *testing.T) {
+			total := 0
+			for i := range scores {
+				total += KeptScore(policy, scores[:i+1]) - KeptScore(policy, scores[:i])
+			}
+			if want := KeptScore(policy, scores); total != want {
+				t.Errorf("deltas add up to %d, want %d", total, want)
+			}
+		})
+	}
+}
+
+func choiceItem(itemType string, correct ...bool) models.MQuizItem {
+	item := models.MQuizItem{Type: itemType, Prompt: "Pilih jawaban", Points: 1}
+	for i, isCorrect := range correct {
+		item.Options = append(item.Options, models.QuizOption{Text: string(rune('a' + i)), Correct: isCorrect})
+	}
+	return item
+}
+
+func options(ids ...int) models.QuizResponse {
+	return models.QuizResponse{OptionIDs: ids}
+}
+
+func number(n float64) models.QuizResponse {
+	return models.QuizResponse{Number: &n}
+}
+
+func text(s string) models.QuizResponse {
+	return models.QuizResponse{Text: &s}
+}
+
+func ptr(f float64) *float64 {
+	return &f
+}
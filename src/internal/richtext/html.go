@@ -0,0 +1,156 @@
+package richtext
+
+import (
+	"fmt"
+	"html"
+	"strings"
+)
+
+// markTags maps marks to the HTML elements they render as. Links are built
+// separately because of their attributes.
+var markTags = map[string]string{
+	"bold":        "strong",
+	"italic":      "em",
+	"underline":   "u",
+	"strike":      "s",
+	"code":        "code",
+	"subscript":   "sub",
+	"superscript": "sup",
+	"highlight":   "mark",
+}
+
+// markOrder fixes the nesting of marks so equal text renders equally.
+var markOrder = []string{"link", "bold", "italic", "underline", "strike", "highlight", "subscript", "superscript", "code"}
+
+// HTML renders a validated document as HTML. Every text and attribute is
+// escaped and only the elements of the schema are written, so the result can
+// be embedded without further sanitizing.
+func HTML(doc *Node) string {
+	var b strings.Builder
+	writeHTML(&b, doc)
+	return b.String()
+}
+
+func writeHTML(b *strings.Builder, n *Node) {
+	switch n.Type {
+	case "doc":
+		writeHTMLChildren(b, n)
+	case "paragraph":
+		b.WriteString("<p" + alignStyle(n) + ">")
+		writeHTMLChildren(b, n)
+		b.WriteString("</p>")
+	case "heading":
+		level := attrInt(n, "level", 1)
+		fmt.Fprintf(b, "<h%d%s>", level, alignStyle(n))
+		writeHTMLChildren(b, n)
+		fmt.Fprintf(b, "</h%d>", level)
+	case "blockquote":
+		b.WriteString("<blockquote>")
+		writeHTMLChildren(b, n)
+		b.WriteString("</blockquote>")
+	case "bulletList":
+		b.WriteString("<ul>")
+		writeHTMLChildren(b, n)
+		b.WriteString("</ul>")
+	case "orderedList":
+		if start := attrInt(n, "start", 1); start != 1 {
+			fmt.Fprintf(b, `<ol start="%d">`, start)
+		} else {
+			b.WriteString("<ol>")
+		}
+		writeHTMLChildren(b, n)
+		b.WriteString("</ol>")
+	case "listItem":
+		b.WriteString("<li>")
+		writeHTMLChildren(b, n)
+		b.WriteString("</li>")
+	case "codeBlock":
+		if language, ok := n.Attrs["language"].(string); ok {
+			b.WriteString(`<pre><code class="language-` + html.EscapeString(language) + `">`)
+		} else {
+			b.WriteString("<pre><code>")
+		}
+		writeHTMLChildren(b, n)
+		b.WriteString("</code></pre>")
+	case "horizontalRule":
+		b.WriteString("<hr>")
+	case "hardBreak":
+		b.WriteString("<br>")
+	case "image":
+		b.WriteString(`<img src="` + html.EscapeString(attrString(n, "src")) + `"`)
+		b.WriteString(` alt="` + html.EscapeString(attrString(n, "alt")) + `"`)
+		if title := attrString(n, "title"); title != "" {
+			b.WriteString(` title="` + html.EscapeString(title) + `"`)
+		}
+		b.WriteString(">")
+	case "text":
+		writeHTMLText(b, n)
+	}
+}
+
+func writeHTMLChildren(b *strings.Builder, n *Node) {
+	for _, child := range n.Content {
+		writeHTML(b, child)
+	}
+}
+
+func writeHTMLText(b *strings.Builder, n *Node) {
+	ordered := orderedMarks(n.Marks)
+	for _, mark := range ordered {
+		if mark.Type == "link" {
+			b.WriteString(`<a href="` + html.EscapeString(markString(mark, "href")) + `" rel="noopener noreferrer nofollow"`)
+			if markString(mark, "target") != "" {
+				b.WriteString(` target="_blank"`)
+			}
+			b.WriteString(">")
+			continue
+		}
+		b.WriteString("<" + markTags[mark.Type] + ">")
+	}
+
+	b.WriteString(html.EscapeString(n.Text))
+
+	for i := len(ordered) - 1; i >= 0; i-- {
+		if ordered[i].Type == "link" {
+			b.WriteString("</a>")
+			continue
+		}
+		b.WriteString("</" + markTags[ordered[i].Type] + ">")
+	}
+}
+
+func orderedMarks(list []*Mark) []*Mark {
+	ordered := make([]*Mark, 0, len(list))
+	for _, name := range markOrder {
+		for _, mark := range list {
+			if mark.Type == name {
+				ordered = append(ordered, mark)
+			}
+		}
+	}
+	return ordered
+}
+
+func alignStyle(n *Node) string {
+	if align := attrString(n, "textAlign"); align != "" && align != "left" {
+		return ` style="text-align: ` + html.EscapeString(align) + `"`
+	}
+	return ""
+}
+
+func attrString(n *Node, name string) string {
+	s, _ := n.Attrs[name].(string)
+	return s
+}
+
+func markString(m *Mark, name string) string {
+	s, _ := m.Attrs[name].(string)
+	return s
+}
+
+func attrInt(n *Node, name string, fallback int64) int64 {
+	if v, ok := n.Attrs[name].(int64); ok {
+		return v
+	}
+	return fallback
+}
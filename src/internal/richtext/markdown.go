@@ -0,0 +1,173 @@
+package richtext
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Markdown renders a validated document as CommonMark. Underline, highlight,
+// subscript and superscript have no Markdown form and are left out.
+func Markdown(doc *Node) string {
+	return strings.TrimSpace(markdownBlocks(doc.Content)) + "\n"
+}
+
+func markdownBlocks(blocks []*Node) string {
+	var parts []string
+	for _, n := range blocks {
+		if block := markdownBlock(n); block != "" {
+			parts = append(parts, block)
+		}
+	}
+	return strings.Join(parts, "\n\n")
+}
+
+func markdownBlock(n *Node) string {
+	switch n.Type {
+	case "paragraph":
+		return markdownInline(n.Content)
+	case "heading":
+		return strings.Repeat("#", int(attrInt(n, "level", 1))) + " " + markdownInline(n.Content)
+	case "blockquote":
+		return prefixLines(markdownBlocks(n.Content), "> ", "> ")
+	case "bulletList":
+		items := make([]string, 0, len(n.Content))
+		for _, item := range n.Content {
+			items = append(items, prefixLines(markdownBlocks(item.Content), "- ", "  "))
+		}
+		return strings.Join(items, "\n")
+	case "orderedList":
+		start := attrInt(n, "start", 1)
+		items := make([]string, 0, len(n.Content))
+		for i, item := range n.Content {
+			marker := fmt.Sprintf("%d. ", start+int64(i))
+			items = append(items, prefixLines(markdownBlocks(item.Content), marker, strings.Repeat(" ", len(marker))))
+		}
+		return strings.Join(items, "\n")
+	case "codeBlock":
+		var code strings.Builder
+		for _, child := range n.Content {
+			code.WriteString(child.Text)
+		}
+		text := strings.TrimSuffix(code.String(), "\n")
+		fence := codeFence(text, "```")
+		return fence + attrString(n, "language") + "\n" + text + "\n" + fence
+	case "horizontalRule":
+		return "---"
+	case "image":
+		return markdownImage(n)
+	}
+	return ""
+}
+
+func markdownInline(content []*Node) string {
+	var b strings.Builder
+	for _, n := range content {
+		switch n.Type {
+		case "text":
+			b.WriteString(markdownText(n))
+		case "hardBreak":
+			b.WriteString("\\\n")
+		case "image":
+			b.WriteString(markdownImage(n))
+		}
+	}
+	return b.String()
+}
+
+func markdownText(n *Node) string {
+	has := make(map[string]*Mark, len(n.Marks))
+	for _, mark := range n.Marks {
+		has[mark.Type] = mark
+	}
+
+	var text string
+	if has["code"] != nil {
+		fence := codeFence(n.Text, "`")
+		text = fence + n.Text + fence
+		if strings.HasPrefix(n.Text, "`") || strings.HasSuffix(n.Text, "`") {
+			text = fence + " " + n.Text + " " + fence
+		}
+	} else {
+		text = escapeMarkdown(n.Text)
+	}
+
+	// Emphasis can't start or end with whitespace, so it wraps the trimmed
+	// text only.
+	lead := text[:len(text)-len(strings.TrimLeft(text, " "))]
+	trail := text[len(strings.TrimRight(text, " ")):]
+	core := strings.TrimSpace(text)
+	if core == "" {
+		return text
+	}
+	if has["strike"] != nil {
+		core = "~~" + core + "~~"
+	}
+	if has["italic"] != nil {
+		core = "_" + core + "_"
+	}
+	if has["bold"] != nil {
+		core = "**" + core + "**"
+	}
+	if link := has["link"]; link != nil {
+		core = "[" + core + "](" + markdownURL(markString(link, "href")) + ")"
+	}
+	return lead + core + trail
+}
+
+func markdownImage(n *Node) string {
+	image := "![" + escapeMarkdown(attrString(n, "alt")) + "](" + markdownURL(attrString(n, "src"))
+	if title := attrString(n, "title"); title != "" {
+		image += ` "` + strings.ReplaceAll(title, `"`, `\"`) + `"`
+	}
+	return image + ")"
+}
+
+// markdownURL writes a link destination in angle brackets, which allows
+// spaces and parentheses, escaping the brackets themselves.
+func markdownURL(u string) string {
+	return "<" + strings.NewReplacer("<", "%3C", ">", "%3E").Replace(u) + ">"
+}
+
+// codeFence returns a run of fence characters longer than any run in text.
+func codeFence(text string, fence string) string {
+	for strings.Contains(text, fence) {
+		fence += fence[:1]
+	}
+	return fence
+}
+
+var (
+	markdownSpecial   = regexp.MustCompile("[\\\\`*_\\[\\]<>~|]")
+	markdownLineStart = regexp.MustCompile(`(?m)^[ \t]*([#+=-]|\d+[.)])`)
+)
+
+// escapeMarkdown keeps text from being read as Markdown syntax.
+func escapeMarkdown(text string) string {
+	text = markdownSpecial.ReplaceAllString(text, `\$0`)
+	return markdownLineStart.ReplaceAllStringFunc(text, func(match string) string {
+		trimmed := strings.TrimLeft(match, " \t")
+		indent := match[:len(match)-len(trimmed)]
+		if last := len(trimmed) - 1; trimmed[last] == '.' || trimmed[last] == ')' {
+			return indent + trimmed[:last] + `\` + trimmed[last:]
+		}
+		return indent + `\` + trimmed
+	})
+}
+
+// prefixLines puts first before the first line of text and rest before the
+// others, as list items and quotes need.
+func prefixLines(text string, first string, rest string) string {
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		prefix := rest
+		if i == 0 {
+			prefix = first
+		}
+		if line == "" {
+			prefix = strings.TrimRight(prefix, " ")
+		}
+		lines[i] = prefix + line
+	}
+	return strings.Join(lines, "\n")
+}
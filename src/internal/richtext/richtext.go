@@ -0,0 +1,134 @@
+// Package richtext handles the Tiptap (ProseMirror) JSON documents materials
+// are written in. Documents are checked against the nodes and marks the editor
+// is configured with, and can be rendered to sanitized HTML, plain text and
+// Markdown on the server.
+package richtext
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrInvalid wraps every error caused by a document that doesn't fit the
+// schema.
+var ErrInvalid = errors.New("dokumen tidak valid")
+
+// Node is a node of a document. Text is only set on text nodes, which are
+// the only ones carrying marks.
+type Node struct {
+	Type    string         `json:"type"`
+	Attrs   map[string]any `json:"attrs,omitempty"`
+	Content []*Node        `json:"content,omitempty"`
+	Marks   []*Mark        `json:"marks,omitempty"`
+	Text    string         `json:"text,omitempty"`
+}
+
+// Mark is formatting applied to a text node, such as bold or a link.
+type Mark struct {
+	Type  string         `json:"type"`
+	Attrs map[string]any `json:"attrs,omitempty"`
+}
+
+// Parse reads a document and validates it against the schema. Attributes the
+// schema doesn't know are dropped, so the result can be stored as is.
+//
+// Materials used to be plain text, and older clients still send a JSON
+// string. A string holding a serialized document is parsed as one; any other
+// string becomes paragraphs.
+func Parse(raw []byte) (*Node, error) {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || bytes.Equal(raw, []byte("null")) {
+		return FromText(""), nil
+	}
+
+	if raw[0] == '"' {
+		var text string
+		if err := json.Unmarshal(raw, &text); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+		}
+		if !strings.HasPrefix(strings.TrimSpace(text), "{") {
+			return FromText(text), nil
+		}
+		raw = []byte(text)
+	}
+
+	doc, err := decode(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateDoc(doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// FromLegacy turns content stored before documents were validated into a
+// valid document: plain text, a JSON string or a document. Content that
+// doesn't fit the schema keeps its text.
+func FromLegacy(raw []byte) *Node {
+	if doc, err := Parse(raw); err == nil {
+		return doc
+	}
+
+	text := string(raw)
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		text = s
+	}
+	if doc, err := decode([]byte(text)); err == nil {
+		return FromText(PlainText(doc))
+	}
+	return FromText(text)
+}
+
+// FromText builds a document from plain text. Blank lines separate
+// paragraphs and single line breaks are kept as hard breaks.
+func FromText(text string) *Node {
+	doc := &Node{Type: "doc", Content: []*Node{}}
+
+	text = strings.ReplaceAll(text, "\r\n", "\n")
+	for _, block := range strings.Split(text, "\n\n") {
+		block = strings.Trim(block, "\n")
+		if strings.TrimSpace(block) == "" {
+			continue
+		}
+
+		paragraph := &Node{Type: "paragraph"}
+		for i, line := range strings.Split(block, "\n") {
+			if i > 0 {
+				paragraph.Content = append(paragraph.Content, &Node{Type: "hardBreak"})
+			}
+			if line != "" {
+				paragraph.Content = append(paragraph.Content, &Node{Type: "text", Text: line})
+			}
+		}
+		doc.Content = append(doc.Content, paragraph)
+	}
+
+	if len(doc.Content) == 0 {
+		doc.Content = append(doc.Content, &Node{Type: "paragraph"})
+	}
+	return doc
+}
+
+// JSON returns the document in the form it is stored in.
+func (n *Node) JSON() ([]byte, error) {
+	return json.Marshal(n)
+}
+
+func decode(raw []byte) (*Node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+
+	var doc Node
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalid, err)
+	}
+	if decoder.More() {
+		return nil, fmt.Errorf("%w: data tambahan setelah dokumen", ErrInvalid)
+	}
+	return &doc, nil
+}
@@ -0,0 +1,293 @@
+package richtext
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxDepth and maxNodes bound the work a single document can cause.
+	maxDepth = 32
+	maxNodes = 50_000
+)
+
+// content is what a node may contain.
+type content int
+
+const (
+	noContent content = iota
+	blockContent
+	inlineContent
+	// plainContent is unmarked text only, as in code blocks.
+	plainContent
+	listContent
+)
+
+// attr checks an attribute value and returns it in its stored form.
+type attr func(value any) (any, error)
+
+type nodeSpec struct {
+	content content
+	block   bool
+	inline  bool
+	attrs   map[string]attr
+}
+
+// nodes is the allow-list of node types, matching the extensions enabled in
+// the material editor.
+var nodes = map[string]nodeSpec{
+	"doc":            {content: blockContent},
+	"paragraph":      {content: inlineContent, block: true, attrs: map[string]attr{"textAlign": textAlignAttr}},
+	"heading":        {content: inlineContent, block: true, attrs: map[string]attr{"level": headingLevelAttr, "textAlign": textAlignAttr}},
+	"blockquote":     {content: blockContent, block: true},
+	"bulletList":     {content: listContent, block: true},
+	"orderedList":    {content: listContent, block: true, attrs: map[string]attr{"start": listStartAttr}},
+	"listItem":       {content: blockContent},
+	"codeBlock":      {content: plainContent, block: true, attrs: map[string]attr{"language": languageAttr}},
+	"horizontalRule": {block: true},
+	"image":          {block: true, inline: true, attrs: map[string]attr{"src": imageSrcAttr, "alt": stringAttr, "title": stringAttr}},
+	"hardBreak":      {inline: true},
+	"text":           {inline: true},
+}
+
+// marks is the allow-list of mark types.
+var marks = map[string]map[string]attr{
+	"bold":        nil,
+	"italic":      nil,
+	"underline":   nil,
+	"strike":      nil,
+	"code":        nil,
+	"subscript":   nil,
+	"superscript": nil,
+	"highlight":   nil,
+	"link":        {"href": linkHrefAttr, "target": linkTargetAttr},
+}
+
+// required lists the attributes a node or mark is useless without.
+var required = map[string][]string{
+	"heading": {"level"},
+	"image":   {"src"},
+	"link":    {"href"},
+}
+
+func validateDoc(doc *Node) error {
+	if doc.Type != "doc" {
+		return fmt.Errorf("%w: dokumen harus diawali node doc", ErrInvalid)
+	}
+	count := 0
+	return validateNode(doc, 0, &count)
+}
+
+func validateNode(n *Node, depth int, count *int) error {
+	*count++
+	if *count > maxNodes {
+		return fmt.Errorf("%w: dokumen terlalu besar", ErrInvalid)
+	}
+	if depth > maxDepth {
+		return fmt.Errorf("%w: dokumen terlalu dalam", ErrInvalid)
+	}
+
+	spec, ok := nodes[n.Type]
+	if !ok {
+		return fmt.Errorf("%w: node %q tidak diizinkan", ErrInvalid, n.Type)
+	}
+
+	attrs, err := checkAttrs(n.Type, n.Attrs, spec.attrs)
+	if err != nil {
+		return err
+	}
+	n.Attrs = attrs
+
+	if n.Type == "text" {
+		if n.Text == "" {
+			return fmt.Errorf("%w: node text tidak boleh kosong", ErrInvalid)
+		}
+		if len(n.Content) > 0 {
+			return fmt.Errorf("%w: node text tidak boleh berisi node lain", ErrInvalid)
+		}
+		return validateMarks(n)
+	}
+	if n.Text != "" || len(n.Marks) > 0 {
+		return fmt.Errorf("%w: hanya node text yang boleh berisi teks dan mark", ErrInvalid)
+	}
+
+	for _, child := range n.Content {
+		if child == nil {
+			return fmt.Errorf("%w: node kosong di dalam %s", ErrInvalid, n.Type)
+		}
+		childSpec, ok := nodes[child.Type]
+		if !ok {
+			return fmt.Errorf("%w: node %q tidak diizinkan", ErrInvalid, child.Type)
+		}
+
+		var allowed bool
+		switch spec.content {
+		case blockContent:
+			allowed = childSpec.block
+		case inlineContent:
+			allowed = childSpec.inline
+		case plainContent:
+			allowed = child.Type == "text" && len(child.Marks) == 0
+		case listContent:
+			allowed = child.Type == "listItem"
+		}
+		if !allowed {
+			return fmt.Errorf("%w: node %s tidak boleh berada di dalam %s", ErrInvalid, child.Type, n.Type)
+		}
+
+		if err := validateNode(child, depth+1, count); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func validateMarks(n *Node) error {
+	seen := make(map[string]bool, len(n.Marks))
+	for _, mark := range n.Marks {
+		if mark == nil {
+			return fmt.Errorf("%w: mark kosong", ErrInvalid)
+		}
+		specs, ok := marks[mark.Type]
+		if !ok {
+			return fmt.Errorf("%w: mark %q tidak diizinkan", ErrInvalid, mark.Type)
+		}
+		if seen[mark.Type] {
+			return fmt.Errorf("%w: mark %s dipakai lebih dari sekali", ErrInvalid, mark.Type)
+		}
+		seen[mark.Type] = true
+
+		attrs, err := checkAttrs(mark.Type, mark.Attrs, specs)
+		if err != nil {
+			return err
+		}
+		mark.Attrs = attrs
+	}
+	return nil
+}
+
+// checkAttrs keeps the attributes in specs, checked and converted, and drops
+// the rest. Null values are dropped too.
+func checkAttrs(owner string, values map[string]any, specs map[string]attr) (map[string]any, error) {
+	var attrs map[string]any
+	for name, check := range specs {
+		value, ok := values[name]
+		if !ok || value == nil {
+			continue
+		}
+		checked, err := check(value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: atribut %s pada %s %v", ErrInvalid, name, owner, err)
+		}
+		if attrs == nil {
+			attrs = make(map[string]any)
+		}
+		attrs[name] = checked
+	}
+
+	for _, name := range required[owner] {
+		if _, ok := attrs[name]; !ok {
+			return nil, fmt.Errorf("%w: atribut %s pada %s wajib diisi", ErrInvalid, name, owner)
+		}
+	}
+	return attrs, nil
+}
+
+func stringAttr(value any) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("harus berupa teks")
+	}
+	return s, nil
+}
+
+func intAttr(value any, min int64, max int64) (int64, error) {
+	number, ok := value.(json.Number)
+	if !ok {
+		// Nodes built in code rather than decoded.
+		switch v := value.(type) {
+		case int:
+			number = json.Number(fmt.Sprint(v))
+		case int64:
+			number = json.Number(fmt.Sprint(v))
+		default:
+			return 0, fmt.Errorf("harus berupa angka")
+		}
+	}
+	n, err := number.Int64()
+	if err != nil || n < min || n > max {
+		return 0, fmt.Errorf("harus antara %d dan %d", min, max)
+	}
+	return n, nil
+}
+
+func headingLevelAttr(value any) (any, error) {
+	return intAttr(value, 1, 6)
+}
+
+func listStartAttr(value any) (any, error) {
+	return intAttr(value, 0, 1_000_000)
+}
+
+func textAlignAttr(value any) (any, error) {
+	switch value {
+	case "left", "center", "right", "justify":
+		return value, nil
+	}
+	return nil, fmt.Errorf("tidak dikenal")
+}
+
+var languagePattern = regexp.MustCompile(`^[A-Za-z0-9_+#.-]{1,32}$`)
+
+func languageAttr(value any) (any, error) {
+	s, ok := value.(string)
+	if !ok || !languagePattern.MatchString(s) {
+		return nil, fmt.Errorf("tidak dikenal")
+	}
+	return s, nil
+}
+
+func linkHrefAttr(value any) (any, error) {
+	return safeURL(value, "http", "https", "mailto")
+}
+
+func imageSrcAttr(value any) (any, error) {
+	return safeURL(value, "http", "https")
+}
+
+func linkTargetAttr(value any) (any, error) {
+	if value != "_blank" {
+		return nil, fmt.Errorf("hanya boleh _blank")
+	}
+	return value, nil
+}
+
+// safeURL accepts absolute URLs with one of schemes and relative ones, so
+// nothing like javascript: ends up in rendered HTML.
+func safeURL(value any, schemes ...string) (any, error) {
+	s, ok := value.(string)
+	if !ok {
+		return nil, fmt.Errorf("harus berupa teks")
+	}
+	s = strings.TrimSpace(s)
+	if s == "" || strings.ContainsFunc(s, func(r rune) bool { return r < 0x20 || r == 0x7f }) {
+		return nil, fmt.Errorf("bukan URL yang valid")
+	}
+
+	u, err := url.Parse(s)
+	if err != nil {
+		return nil, fmt.Errorf("bukan URL yang valid")
+	}
+	if u.Scheme == "" {
+		return s, nil
+	}
+	for _, scheme := range schemes {
+		if strings.EqualFold(u.Scheme, scheme) {
+			return s, nil
+		}
+	}
+	return nil, fmt.Errorf("skema %s tidak diizinkan", u.Scheme)
+}
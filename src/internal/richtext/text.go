@@ -0,0 +1,52 @@
+package richtext
+
+import "strings"
+
+// PlainText returns the text of a document with one line per block. It walks
+// the whole tree and doesn't need a validated document, so it also reads
+// whatever the editor sent before validation existed. Search indexes and AI
+// prompts are built from it.
+func PlainText(doc *Node) string {
+	var lines []string
+	var line strings.Builder
+
+	flush := func() {
+		if text := strings.TrimSpace(line.String()); text != "" {
+			lines = append(lines, text)
+		}
+		line.Reset()
+	}
+
+	var walk func(n *Node)
+	walk = func(n *Node) {
+		if n == nil {
+			return
+		}
+		switch n.Type {
+		case "text":
+			line.WriteString(n.Text)
+		case "hardBreak":
+			line.WriteString("\n")
+		case "image":
+			if alt, ok := n.Attrs["alt"].(string); ok && alt != "" {
+				line.WriteString(alt)
+			}
+		default:
+			spec, known := nodes[n.Type]
+			block := !known || spec.block || n.Type == "listItem"
+			if block {
+				flush()
+			}
+			for _, child := range n.Content {
+				walk(child)
+			}
+			if block {
+				flush()
+			}
+		}
+	}
+	walk(doc)
+	flush()
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,32 @@
+package spreadsheet
+
+import (
+	"bytes"
+	"encoding/csv"
+)
+
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// readCSV accepts comma or semicolon separated files; spreadsheet apps in
+// locales that use a decimal comma export with semicolons.
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, utf8BOM)
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+	reader.Comma = sniffDelimiter(data)
+
+	return reader.ReadAll()
+}
+
+func sniffDelimiter(data []byte) rune {
+	firstLine := data
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		firstLine = data[:i]
+	}
+	if bytes.Count(firstLine, []byte{';'}) > bytes.Count(firstLine, []byte{','}) {
+		return ';'
+	}
+	return ','
+}
@@ -0,0 +1,59 @@
+// Package spreadsheet reads tabular uploads (CSV and XLSX) into plain rows of
+// cells so importers don't need to care which format a school sent.
+package spreadsheet
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+)
+
+// MaxRows caps how many rows a single upload may contain.
+const MaxRows = 5000
+
+// Read parses r as CSV or XLSX based on the extension of filename. Empty rows
+// are kept so row numbers line up with what the user sees in their editor.
+func Read(filename string, r io.Reader) ([][]string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	switch strings.ToLower(filepath.Ext(filename)) {
+	case ".csv", ".txt":
+		rows, err = readCSV(data)
+	case ".xlsx":
+		rows, err = readXLSX(bytes.NewReader(data), int64(len(data)))
+	default:
+		return nil, fmt.Errorf("format file %q tidak didukung, gunakan .csv atau .xlsx", filepath.Ext(filename))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows = trimTrailingEmpty(rows)
+	if len(rows) > MaxRows+1 {
+		return nil, fmt.Errorf("file berisi %d baris, maksimal %d", len(rows)-1, MaxRows)
+	}
+	return rows, nil
+}
+
+// IsEmptyRow reports whether every cell in row is blank.
+func IsEmptyRow(row []string) bool {
+	for _, cell := range row {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func trimTrailingEmpty(rows [][]string) [][]string {
+	for len(rows) > 0 && IsEmptyRow(rows[len(rows)-1]) {
+		rows = rows[:len(rows)-1]
+	}
+	return rows
+}
@@ -0,0 +1,222 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// maxXLSXPartSize bounds how much a single decompressed part may expand to.
+const maxXLSXPartSize = 64 << 20
+
+// maxColumns is the number of columns Excel allows, A to XFD.
+const maxColumns = 16384
+
+// maxCells bounds the cells of a sheet, counting the blanks before each
+// row's last cell, so a few far-off cells can't take up all memory.
+const maxCells = 1 << 20
+
+type xlsxWorkbook struct {
+	Sheets []struct {
+		Name string `xml:"name,attr"`
+		RID  string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+	} `xml:"sheets>sheet"`
+}
+
+type xlsxRelationships struct {
+	Relationships []struct {
+		ID     string `xml:"Id,attr"`
+		Target string `xml:"Target,attr"`
+	} `xml:"Relationship"`
+}
+
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+type xlsxSharedStrings struct {
+	Items []xlsxText `xml:"si"`
+}
+
+type xlsxSheet struct {
+	Rows []struct {
+		R     int `xml:"r,attr"`
+		Cells []struct {
+			R      string    `xml:"r,attr"`
+			T      string    `xml:"t,attr"`
+			V      string    `xml:"v"`
+			Inline *xlsxText `xml:"is"`
+		} `xml:"c"`
+	} `xml:"sheetData>row"`
+}
+
+// readXLSX returns the cells of the first worksheet as text. Formulas yield
+// their cached value; numeric cells come back as stored (dates stay serials).
+func readXLSX(r io.ReaderAt, size int64) ([][]string, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("file xlsx tidak valid: %w", err)
+	}
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+
+	var shared xlsxSharedStrings
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if err := decodePart(f, &shared); err != nil {
+			return nil, err
+		}
+	}
+
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("file xlsx tidak valid: sheet %s tidak ditemukan", sheetPath)
+	}
+	var sheet xlsxSheet
+	if err := decodePart(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var rows [][]string
+	total := 0
+	for _, row := range sheet.Rows {
+		index := row.R - 1
+		if index < len(rows) {
+			index = len(rows)
+		}
+		if index > MaxRows+1 {
+			return nil, fmt.Errorf("file berisi lebih dari %d baris", MaxRows)
+		}
+		for len(rows) < index {
+			rows = append(rows, nil)
+		}
+
+		var cells []string
+		for i, c := range row.Cells {
+			col := i
+			if c.R != "" {
+				if n, ok := columnIndex(c.R); ok {
+					col = n
+				}
+			}
+			if col >= maxColumns {
+				return nil, fmt.Errorf("file xlsx tidak valid: sel %q melewati kolom XFD", c.R)
+			}
+			if col >= len(cells) {
+				total += col + 1 - len(cells)
+				if total > maxCells {
+					return nil, fmt.Errorf("file berisi lebih dari %d sel", maxCells)
+				}
+			}
+			for len(cells) <= col {
+				cells = append(cells, "")
+			}
+
+			switch c.T {
+			case "s":
+				n, err := strconv.Atoi(strings.TrimSpace(c.V))
+				if err != nil || n < 0 || n >= len(shared.Items) {
+					return nil, fmt.Errorf("file xlsx tidak valid: shared string %q", c.V)
+				}
+				cells[col] = shared.Items[n].String()
+			case "inlineStr":
+				if c.Inline != nil {
+					cells[col] = c.Inline.String()
+				}
+			case "b":
+				cells[col] = map[string]string{"1": "TRUE", "0": "FALSE"}[c.V]
+			default:
+				cells[col] = c.V
+			}
+		}
+		rows = append(rows, cells)
+	}
+	return rows, nil
+}
+
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook xlsxWorkbook
+	f, ok := files["xl/workbook.xml"]
+	if !ok {
+		return "", fmt.Errorf("file xlsx tidak valid: workbook tidak ditemukan")
+	}
+	if err := decodePart(f, &workbook); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("file xlsx tidak memiliki sheet")
+	}
+
+	var rels xlsxRelationships
+	if f, ok := files["xl/_rels/workbook.xml.rels"]; ok {
+		if err := decodePart(f, &rels); err != nil {
+			return "", err
+		}
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].RID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "xl/worksheets/sheet1.xml", nil
+}
+
+func decodePart(f *zip.File, v interface{}) error {
+	rc, err := f.Open()
+	if err != nil {
+		return fmt.Errorf("file xlsx tidak valid: %w", err)
+	}
+	defer rc.Close()
+
+	if err := xml.NewDecoder(io.LimitReader(rc, maxXLSXPartSize)).Decode(v); err != nil {
+		return fmt.Errorf("file xlsx tidak valid: %s: %w", f.Name, err)
+	}
+	return nil
+}
+
+// columnIndex converts a cell reference such as "AB12" to a zero-based
+// column index. Columns past XFD come back as maxColumns, however long the
+// reference, so they can't overflow.
+func columnIndex(ref string) (int, bool) {
+	n := 0
+	for i, r := range ref {
+		if r >= 'A' && r <= 'Z' {
+			n = min(n*26+int(r-'A'+1), maxColumns+1)
+			continue
+		}
+		if i == 0 {
+			return 0, false
+		}
+		break
+	}
+	return n - 1, n > 0
+}
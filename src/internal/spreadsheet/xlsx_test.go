@@ -0,0 +1,105 @@
+package spreadsheet
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestColumnIndex(t *testing.T) {
+	tests := []struct {
+		ref    string
+		want   int
+		wantOK bool
+	}{
+		{"A1", 0, true},
+		{"Z9", 25, true},
+		{"AA1", 26, true},
+		{"AB12", 27, true},
+		{"XFD1", maxColumns - 1, true},
+		{"XFE1", maxColumns, true},
+		{"ZZZZZZZ1", maxColumns, true},
+		{strings.Repeat("Z", 40) + "1", maxColumns, true},
+		{"1", 0, false},
+		{"", -1, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := columnIndex(tt.ref)
+		if ok != tt.wantOK || (ok && got != tt.want) {
+			t.Errorf("columnIndex(%q) = %d, %v, want %d, %v", tt.ref, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestReadXLSX(t *testing.T) {
+	rows, err := Read("siswa.xlsx", bytes.NewReader(xlsxFile(t,
+		`<row r="1"><c r="A1" t="inlineStr"><is><t>name</t></is></c><c r="C1" t="s"><v>0</v></c></row>`+
+			`<row r="3"><c r="B3"><v>42</v></c><c r="XFD3" t="b"><v>1</v></c></row>`,
+	)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := make([]string, maxColumns)
+	last[1], last[maxColumns-1] = "42", "TRUE"
+	want := [][]string{{"name", "", "email"}, nil, last}
+	if !reflect.DeepEqual(rows, want) {
+		t.Errorf("rows = %.80q", rows)
+	}
+}
+
+func TestReadXLSXRejectsColumnsPastXFD(t *testing.T) {
+	for _, ref := range []string{"XFE1", "ZZZZZZZ1", strings.Repeat("Z", 40) + "1"} {
+		sheet := fmt.Sprintf(`<row r="1"><c r="%s"><v>1</v></c></row>`, ref)
+		_, err := Read("siswa.xlsx", bytes.NewReader(xlsxFile(t, sheet)))
+		if err == nil || !strings.Contains(err.Error(), "XFD") {
+			t.Errorf("%s: err = %v, want a column error", ref, err)
+		}
+	}
+}
+
+// xlsxFile builds a workbook whose only sheet holds rows, with "email" as
+// its one shared string.
+func xlsxFile(t *testing.T, rows string) []byte {
+	t.Helper()
+
+	parts := map[string]string{
+		"xl/workbook.xml": `<workbook xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="Siswa" r:id="rId1"/></sheets></workbook>`,
+		"xl/_rels/workbook.xml.rels": `<Relationships><Relationship Id="rId1" Target="worksheets/sheet1.xml"/></Relationships>`,
+		"xl/sharedStrings.xml":       `<sst><si><t>email</t></si></sst>`,
+		"xl/worksheets/sheet1.xml":   `<worksheet><sheetData>` + rows + `</sheetData></worksheet>`,
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for name, content := range parts {
+		w, err := zw.Create(name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, err := w.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestReadXLSXRejectsTooManyCells(t *testing.T) {
+	var rows strings.Builder
+	for r := 1; r <= maxCells/maxColumns+1; r++ {
+		fmt.Fprintf(&rows, `<row r="%d"><c r="XFD%d"><v>1</v></c></row>`, r, r)
+	}
+
+	_, err := Read("siswa.xlsx", bytes.NewReader(xlsxFile(t, rows.String())))
+	if err == nil || !strings.Contains(err.Error(), "sel") {
+		t.Errorf("err = %v, want a cell limit error", err)
+	}
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jk-api/internal/constant"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// metadataTokenURL hands out access tokens for the service account of the
+// machine the API runs on.
+const metadataTokenURL = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default/token"
+
+// GCSStorage talks to the Cloud Storage JSON API. Any server implementing the
+// same upload, download and delete endpoints (e.g. an emulator) works as
+// well. Objects under ProtectedPrefix must be left out of any public IAM
+// binding on the bucket.
+type GCSStorage struct {
+	endpoint  string
+	publicURL string
+	bucket    string
+	token     string
+	client    *http.Client
+
+	mu        sync.Mutex
+	cached    string
+	expiresAt time.Time
+}
+
+// NewGCSStorage creates a GCS backend. Without a static token it asks the
+// metadata server for one.
+func NewGCSStorage(endpoint string, publicURL string, bucket string, token string, timeout time.Duration) *GCSStorage {
+	return &GCSStorage{
+		endpoint:  strings.TrimRight(endpoint, "/"),
+		publicURL: strings.TrimRight(publicURL, "/"),
+		bucket:    bucket,
+		token:     token,
+		client:    &http.Client{Timeout: timeout},
+	}
+}
+
+func (s *GCSStorage) Name() string {
+	return constant.MediaDriverGCS
+}
+
+func (s *GCSStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		s.endpoint, url.PathEscape(s.bucket), url.QueryEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", contentType)
+
+	return s.do(ctx, req)
+}
+
+func (s *GCSStorage) Read(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error) {
+	if err := checkKey(key); err != nil {
+		return nil, err
+	}
+
+	endpoint := fmt.Sprintf("%s/storage/v1/b/%s/o/%s?alt=media", s.endpoint, url.PathEscape(s.bucket), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", offset, offset+length-1))
+
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	// Downloads stream for as long as the client reads, so they don't use
+	// the client timeout meant for uploads and deletes.
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusPartialContent {
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, &statusError{status: resp.StatusCode, body: string(body)}
+	}
+	return resp.Body, nil
+}
+
+func (s *GCSStorage) Delete(ctx context.Context, key string) error {
+	if err := checkKey(key); err != nil {
+		return err
+	}
+
+	endpoint := fmt.Sprintf("%s/storage/v1/b/%s/o/%s", s.endpoint, url.PathEscape(s.bucket), url.PathEscape(key))
+	req, err := http.NewRequestWithContext(ctx, http.MethodDelete, endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	err = s.do(ctx, req)
+	if err, ok := err.(*statusError); ok && err.status == http.StatusNotFound {
+		return nil
+	}
+	return err
+}
+
+func (s *GCSStorage) URL(key string) string {
+	return s.publicURL + "/" + s.bucket + "/" + key
+}
+
+type statusError struct {
+	status int
+	body   string
+}
+
+func (e *statusError) Error() string {
+	return fmt.Sprintf("gcs responded with status %d: %s", e.status, e.body)
+}
+
+func (s *GCSStorage) do(ctx context.Context, req *http.Request) error {
+	token, err := s.accessToken(ctx)
+	if err != nil {
+		return err
+	}
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return &statusError{status: resp.StatusCode, body: string(body)}
+	}
+	return nil
+}
+
+// accessToken returns the static token when one is configured and otherwise
+// a cached metadata server token, refreshed a minute before it expires.
+func (s *GCSStorage) accessToken(ctx context.Context) (string, error) {
+	if s.token != "" {
+		return s.token, nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.cached != "" && time.Now().Before(s.expiresAt) {
+		return s.cached, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataTokenURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch gcs token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch gcs token: metadata server responded with status %d", resp.StatusCode)
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return "", fmt.Errorf("fetch gcs token: %w", err)
+	}
+
+	s.cached = token.AccessToken
+	s.expiresAt = time.Now().Add(time.Duration(token.ExpiresIn)*time.Second - time.Minute)
+	return s.cached, nil
+}
@@ -0,0 +1,45 @@
+// Package storage keeps uploaded media in an object store. Keys are slash
+// separated paths; the backend decides where the bytes live and how they are
+// served.
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ProtectedPrefix starts the key of every object that may only be read
+// through a signed URL. Backends must not serve it publicly.
+const ProtectedPrefix = "protected/"
+
+// Storage is an object store for media files.
+type Storage interface {
+	Name() string
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	// Read streams length bytes of the object starting at offset.
+	Read(ctx context.Context, key string, offset int64, length int64) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+	// URL is the public address of the object stored under key.
+	URL(key string) string
+}
+
+// IsProtected reports whether key belongs to a protected object.
+func IsProtected(key string) bool {
+	return strings.HasPrefix(key, ProtectedPrefix)
+}
+
+// checkKey rejects keys that could escape the store, such as absolute paths
+// or ".." segments.
+func checkKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == "" || part == "." || part == ".." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+// Package tenant carries the school (and optionally the department subtree) a
+// request is acting for so the shared query builder can scope owned tables
+// without every repository having to remember a WHERE clause.
+package tenant
+
+import (
+	"context"
+	"database/sql"
+)
+
+// Scope describes which school, and optionally which department subtree, a
+// request may see.
+type Scope struct {
+	// SchoolID is the caller's school. Nil means the caller does not belong to
+	// any school and only sees rows that are not owned by one either.
+	SchoolID *int64
+	// AllSchools lifts scoping entirely. It is only set for platform admins.
+	AllSchools bool
+	// DepartmentID further limits the caller to a department and everything
+	// below it. Nil means the whole school.
+	DepartmentID *int64
+}
+
+// Owned is implemented by models that belong to a school. SchoolColumn returns
+// the table-qualified column holding the owning school id.
+type Owned interface {
+	SchoolColumn() string
+}
+
+// Shared is implemented by owned models whose rows without a school are
+// visible to every school, e.g. public courses.
+type Shared interface {
+	Owned
+	SharedAcrossSchools()
+}
+
+// Assignable is implemented by owned models that should be stamped with the
+// caller's school when they are created without one.
+type Assignable interface {
+	Owned
+	AssignSchool(schoolID int64)
+}
+
+// Nested is implemented by models that belong to a school through a parent
+// row, e.g. lessons through their course. ParentColumn returns the
+// table-qualified column referencing the parent and Parent a pointer to a
+// zero parent model, which is itself Owned or Nested.
+type Nested interface {
+	ParentColumn() string
+	Parent() any
+}
+
+// DepartmentOwned is implemented by models that can be limited to a
+// department subtree. DepartmentCondition wraps subtree, a query selecting
+// the department ids the caller may see, into a WHERE condition.
+type DepartmentOwned interface {
+	DepartmentCondition(subtree string) string
+}
+
+// DepartmentAssignable is implemented by department-owned models that should
+// be placed in the caller's department when they are created without one.
+type DepartmentAssignable interface {
+	DepartmentOwned
+	AssignDepartment(departmentID int64)
+}
+
+// DepartmentSubtreeSQL selects the department named @department_id and every
+// department below it.
+const DepartmentSubtreeSQL = `WITH RECURSIVE department_subtree AS (
+	SELECT id FROM departments WHERE id = @department_id AND deleted_at IS NULL
+	UNION
+	SELECT d.id FROM departments d
+	JOIN department_subtree s ON d.parent_id = s.id
+	WHERE d.deleted_at IS NULL
+) SELECT id FROM department_subtree`
+
+// DepartmentArg binds @department_id in DepartmentSubtreeSQL.
+func DepartmentArg(departmentID int64) sql.NamedArg {
+	return sql.Named("department_id", departmentID)
+}
+
+type contextKey struct{}
+
+// WithScope returns a copy of ctx carrying scope.
+func WithScope(ctx context.Context, scope Scope) context.Context {
+	return context.WithValue(ctx, contextKey{}, scope)
+}
+
+// FromContext returns the scope stored in ctx. Contexts without a scope
+// (background jobs, login, migrations) are not scoped at all.
+func FromContext(ctx context.Context) (Scope, bool) {
+	if ctx == nil {
+		return Scope{}, false
+	}
+	scope, ok := ctx.Value(contextKey{}).(Scope)
+	return scope, ok
+}
@@ -0,0 +1,135 @@
+package tutor
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"jk-api/internal/constant"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// OpenAI streams from a chat completions endpoint. Any service that accepts
+// the OpenAI request body and a bearer token can be used.
+type OpenAI struct {
+	url    string
+	token  string
+	model  string
+	client *http.Client
+}
+
+func NewOpenAI(url string, token string, model string, timeout time.Duration) *OpenAI {
+	return &OpenAI{
+		url:    url,
+		token:  token,
+		model:  model,
+		client: &http.Client{Timeout: timeout},
+	}
+}
+
+func (p *OpenAI) Name() string {
+	return constant.LLMProviderOpenAI
+}
+
+func (p *OpenAI) Stream(ctx context.Context, req ChatRequest, onDelta func(text string) error) (*ChatResponse, error) {
+	messages := []map[string]string{{"role": "system", "content": req.System}}
+	for _, m := range req.Messages {
+		messages = append(messages, map[string]string{"role": m.Role, "content": m.Content})
+	}
+
+	payload := map[string]any{
+		"model":          p.model,
+		"messages":       messages,
+		"stream":         true,
+		"stream_options": map[string]bool{"include_usage": true},
+	}
+	if req.MaxTokens > 0 {
+		payload["max_tokens"] = req.MaxTokens
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "text/event-stream")
+	httpReq.Header.Set("Authorization", "Bearer "+p.token)
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 512))
+		return nil, fmt.Errorf("%s responded with status %d: %s", httpReq.URL.Host, resp.StatusCode, message)
+	}
+
+	result := &ChatResponse{Model: p.model}
+	var text strings.Builder
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		data, ok := strings.CutPrefix(scanner.Text(), "data:")
+		if !ok {
+			continue
+		}
+		data = strings.TrimSpace(data)
+		if data == "[DONE]" {
+			break
+		}
+
+		var chunk struct {
+			Model   string `json:"model"`
+			Choices []struct {
+				Delta struct {
+					Content string `json:"content"`
+				} `json:"delta"`
+			} `json:"choices"`
+			Usage *struct {
+				PromptTokens     int `json:"prompt_tokens"`
+				CompletionTokens int `json:"completion_tokens"`
+			} `json:"usage"`
+		}
+		if err := json.Unmarshal([]byte(data), &chunk); err != nil {
+			return nil, err
+		}
+
+		if chunk.Model != "" {
+			result.Model = chunk.Model
+		}
+		if chunk.Usage != nil {
+			result.PromptTokens = chunk.Usage.PromptTokens
+			result.CompletionTokens = chunk.Usage.CompletionTokens
+		}
+		for _, choice := range chunk.Choices {
+			if choice.Delta.Content == "" {
+				continue
+			}
+			if err := onDelta(choice.Delta.Content); err != nil {
+				return nil, err
+			}
+			text.WriteString(choice.Delta.Content)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	result.Text = text.String()
+	if result.PromptTokens == 0 && result.CompletionTokens == 0 {
+		result.PromptTokens = req.PromptTokens()
+		result.CompletionTokens = EstimateTokens(result.Text)
+	}
+	return result, nil
+}
@@ -0,0 +1,73 @@
+package tutor
+
+import "strings"
+
+// Limits on the grounding put into the system prompt, in characters.
+const (
+	maxMaterialContext = 6000
+	maxQuestionContext = 2000
+	maxErrorContext    = 2000
+)
+
+const (
+	materialHeading = "### Materi"
+	questionHeading = "### Soal yang sedang dikerjakan"
+	errorHeading    = "### Error terakhir siswa"
+)
+
+// Grounding is what the tutor knows about where the student is stuck.
+// Everything but SubLesson may be empty.
+type Grounding struct {
+	Course    string
+	Lesson    string
+	SubLesson string
+	Material  string
+	Question  string
+	Hint      string
+	LastError string
+}
+
+// SystemPrompt tells the model to act as a tutor for the sub-lesson and
+// holds the grounding it should answer from.
+func SystemPrompt(g Grounding) string {
+	var b strings.Builder
+	b.WriteString("Kamu adalah tutor pemrograman yang sabar untuk siswa")
+	if g.Course != "" {
+		b.WriteString(" kursus \"" + g.Course + "\"")
+	}
+	b.WriteString(". Siswa sedang mempelajari sub-bab \"" + g.SubLesson + "\"")
+	if g.Lesson != "" {
+		b.WriteString(" pada bab \"" + g.Lesson + "\"")
+	}
+	b.WriteString(".\n")
+	b.WriteString("Jawab dalam bahasa Indonesia yang sederhana dan berdasarkan konteks di bawah. ")
+	b.WriteString("Bimbing siswa dengan pertanyaan dan petunjuk bertahap; jangan memberikan jawaban lengkap soal. ")
+	b.WriteString("Jika pertanyaan tidak berhubungan dengan pelajaran, arahkan kembali ke materi.\n")
+
+	writeSection(&b, materialHeading, truncate(g.Material, maxMaterialContext))
+	question := g.Question
+	if g.Hint != "" {
+		question += "\nPetunjuk dari guru: " + g.Hint
+	}
+	writeSection(&b, questionHeading, truncate(strings.TrimSpace(question), maxQuestionContext))
+	writeSection(&b, errorHeading, truncate(g.LastError, maxErrorContext))
+
+	return b.String()
+}
+
+func writeSection(b *strings.Builder, heading string, text string) {
+	if strings.TrimSpace(text) == "" {
+		return
+	}
+	b.WriteString("\n" + heading + "\n" + strings.TrimSpace(text) + "\n")
+}
+
+// section returns the text under heading in a system prompt.
+func section(system string, heading string) string {
+	_, rest, ok := strings.Cut(system, heading+"\n")
+	if !ok {
+		return ""
+	}
+	text, _, _ := strings.Cut(rest, "\n### ")
+	return strings.TrimSpace(text)
+}
@@ -0,0 +1,57 @@
+// Package tutor answers students' questions about a sub-lesson with a
+// language model. Answers are grounded in the material, the question the
+// student works on and their latest error, and are streamed as the model
+// writes them.
+package tutor
+
+import (
+	"context"
+	"unicode/utf8"
+)
+
+// ChatProvider sends a conversation to a language model and streams its
+// reply: onDelta is called with every piece of text as it arrives, and an
+// error it returns stops the reply.
+type ChatProvider interface {
+	Name() string
+	Stream(ctx context.Context, req ChatRequest, onDelta func(text string) error) (*ChatResponse, error)
+}
+
+// Message is one turn of a conversation; Role is constant.TutorRoleStudent
+// or constant.TutorRoleTutor.
+type Message struct {
+	Role    string
+	Content string
+}
+
+// ChatRequest is a conversation ending with the student's question.
+// MaxTokens caps the length of the reply.
+type ChatRequest struct {
+	System    string
+	Messages  []Message
+	MaxTokens int
+}
+
+// ChatResponse is the complete reply. Token counts are those reported by the
+// model, or estimated when it reports none.
+type ChatResponse struct {
+	Text             string
+	Model            string
+	PromptTokens     int
+	CompletionTokens int
+}
+
+// EstimateTokens approximates the number of tokens of text, at about four
+// characters a token.
+func EstimateTokens(text string) int {
+	return (utf8.RuneCountInString(text) + 3) / 4
+}
+
+// PromptTokens estimates the tokens a request takes before the reply.
+func (r ChatRequest) PromptTokens() int {
+	tokens := EstimateTokens(r.System)
+	for _, m := range r.Messages {
+		tokens += EstimateTokens(m.Content) + 4
+	}
+	return tokens
+}
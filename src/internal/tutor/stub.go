@@ -0,0 +1,64 @@
+package tutor
+
+import (
+	"context"
+	"fmt"
+	"jk-api/internal/constant"
+	"strings"
+)
+
+// Stub replies without calling a model, so the tutor can be developed and
+// tested offline. The reply only depends on the request and points the
+// student back to their error or the material.
+type Stub struct{}
+
+func NewStub() *Stub {
+	return &Stub{}
+}
+
+func (*Stub) Name() string {
+	return constant.LLMProviderStub
+}
+
+func (*Stub) Stream(ctx context.Context, req ChatRequest, onDelta func(text string) error) (*ChatResponse, error) {
+	question := ""
+	if len(req.Messages) > 0 {
+		question = req.Messages[len(req.Messages)-1].Content
+	}
+
+	reply := fmt.Sprintf("Pertanyaanmu: %q. Baca kembali materi sub-bab ini dan coba pecah masalahnya menjadi langkah kecil.", truncate(question, 100))
+	if lastError := section(req.System, errorHeading); lastError != "" {
+		reply += fmt.Sprintf(" Perhatikan pesan error terakhirmu: %q; cari baris yang disebutkan di sana.", truncate(lastError, 200))
+	}
+
+	words := strings.SplitAfter(reply, " ")
+	if req.MaxTokens > 0 && len(words) > req.MaxTokens {
+		words = words[:req.MaxTokens]
+	}
+
+	var text strings.Builder
+	for _, word := range words {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		if err := onDelta(word); err != nil {
+			return nil, err
+		}
+		text.WriteString(word)
+	}
+
+	return &ChatResponse{
+		Text:             text.String(),
+		Model:            constant.LLMProviderStub,
+		PromptTokens:     req.PromptTokens(),
+		CompletionTokens: EstimateTokens(text.String()),
+	}, nil
+}
+
+func truncate(s string, limit int) string {
+	runes := []rune(s)
+	if len(runes) <= limit {
+		return s
+	}
+	return string(runes[:limit]) + "…"
+}
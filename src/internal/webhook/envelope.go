@@ -0,0 +1,15 @@
+package webhook
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// Envelope is the JSON body of every webhook request. ID is stable across
+// retries and replays of the same event, so receivers can deduplicate on it.
+type Envelope struct {
+	ID         string          `json:"id"`
+	Event      string          `json:"event"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
@@ -0,0 +1,129 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// maxResponseBody is how much of a response is read, and thrown away, so the
+// connection can be reused.
+const maxResponseBody = 2048
+
+// ErrBlockedAddress is returned when a webhook URL resolves to an address
+// inside the platform's network.
+var ErrBlockedAddress = errors.New("alamat webhook tidak diizinkan")
+
+// blockedPrefixes are ranges beyond the loopback, private and link-local
+// ones the net package knows of: shared address space, where some clouds put
+// their metadata service, and 0.0.0.0/8.
+var blockedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// Request is a single signed webhook call.
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	DeliveryID int64
+	Body       []byte
+}
+
+// Response is what the receiver answered. Its body is not kept, so a
+// webhook can't be used to read pages off an internal address.
+type Response struct {
+	StatusCode int
+}
+
+// Sender delivers a webhook request. Implementations return a non-nil error
+// only when no HTTP response was received; callers decide whether a status
+// code counts as success.
+type Sender interface {
+	Send(ctx context.Context, req Request) (*Response, error)
+}
+
+type HTTPSender struct {
+	Client *http.Client
+}
+
+// NewHTTPSender returns a sender that only connects to public addresses. The
+// check runs on the resolved address of every connection, redirects
+// included, so DNS names pointing inside the network are refused too.
+func NewHTTPSender(timeout time.Duration) *HTTPSender {
+	dialer := &net.Dialer{Timeout: timeout, Control: refuseBlocked}
+	return &HTTPSender{Client: &http.Client{
+		Timeout: timeout,
+		Transport: &http.Transport{
+			DialContext:         dialer.DialContext,
+			TLSHandshakeTimeout: timeout,
+			MaxIdleConns:        10,
+			IdleConnTimeout:     90 * time.Second,
+		},
+	}}
+}
+
+// Blocked reports whether ip is loopback, private, link-local (which holds
+// the 169.254.169.254 metadata service) or otherwise not a public address.
+func Blocked(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if ip.IsLoopback() || ip.IsPrivate() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast() || ip.IsUnspecified() {
+		return true
+	}
+	for _, prefix := range blockedPrefixes {
+		if prefix.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+func refuseBlocked(network string, address string, _ syscall.RawConn) error {
+	addrPort, err := netip.ParseAddrPort(address)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, address)
+	}
+	if Blocked(addrPort.Addr()) {
+		return fmt.Errorf("%w: %s", ErrBlockedAddress, addrPort.Addr())
+	}
+	return nil
+}
+
+func (s *HTTPSender) Send(ctx context.Context, req Request) (*Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, req.URL, bytes.NewReader(req.Body))
+	if err != nil {
+		return nil, err
+	}
+
+	timestamp := time.Now().Unix()
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("User-Agent", "bajapro-webhook/1.0")
+	httpReq.Header.Set(HeaderEvent, req.Event)
+	httpReq.Header.Set(HeaderDelivery, strconv.FormatInt(req.DeliveryID, 10))
+	httpReq.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	httpReq.Header.Set(HeaderSignature, Sign(req.Secret, timestamp, req.Body))
+
+	resp, err := s.Client.Do(httpReq)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	io.Copy(io.Discard, io.LimitReader(resp.Body, maxResponseBody))
+	return &Response{StatusCode: resp.StatusCode}, nil
+}
+
+// Succeeded reports whether the receiver accepted the delivery.
+func (r *Response) Succeeded() bool {
+	return r != nil && r.StatusCode >= 200 && r.StatusCode < 300
+}
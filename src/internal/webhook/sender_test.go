@@ -0,0 +1,161 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestHTTPSenderSend(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantSucceeded bool
+	}{
+		{"ok", http.StatusOK, true},
+		{"accepted", http.StatusAccepted, true},
+		{"no content", http.StatusNoContent, true},
+		{"redirect", http.StatusNotModified, false},
+		{"client error", http.StatusBadRequest, false},
+		{"server error", http.StatusInternalServerError, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var verifyErr error
+			var event, delivery string
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				event = r.Header.Get(HeaderEvent)
+				delivery = r.Header.Get(HeaderDelivery)
+				verifyErr = Verify("s3cret", r.Header.Get(HeaderTimestamp), r.Header.Get(HeaderSignature), body, time.Minute)
+				w.WriteHeader(tt.status)
+			}))
+			defer server.Close()
+
+			resp, err := loopbackSender().Send(context.Background(), Request{
+				URL:        server.URL,
+				Secret:     "s3cret",
+				Event:      "course.published",
+				DeliveryID: 42,
+				Body:       []byte(`{"id":1}`),
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if verifyErr != nil {
+				t.Errorf("receiver rejected signature: %v", verifyErr)
+			}
+			if event != "course.published" || delivery != "42" {
+				t.Errorf("headers event = %q, delivery = %q", event, delivery)
+			}
+			if resp.StatusCode != tt.status || resp.Succeeded() != tt.wantSucceeded {
+				t.Errorf("status = %d, succeeded = %v, want %d, %v", resp.StatusCode, resp.Succeeded(), tt.status, tt.wantSucceeded)
+			}
+		})
+	}
+}
+
+func TestHTTPSenderRefusesInternalAddresses(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	port := server.URL[strings.LastIndex(server.URL, ":"):]
+	for _, url := range []string{server.URL, "http://localhost" + port, "http://[::1]" + port} {
+		resp, err := NewHTTPSender(time.Second).Send(context.Background(), Request{URL: url})
+		if !errors.Is(err, ErrBlockedAddress) || resp != nil {
+			t.Errorf("%s: resp = %v, err = %v, want ErrBlockedAddress", url, resp, err)
+		}
+	}
+	if calls != 0 {
+		t.Errorf("internal server was called %d times", calls)
+	}
+}
+
+// A public receiver redirecting to an internal address is refused when the
+// redirect is followed, as every connection goes through the same check.
+func TestHTTPSenderRefusesRedirectsInside(t *testing.T) {
+	internal := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("redirect to an internal address was followed")
+	}))
+	defer internal.Close()
+	public := httptest.NewServer(http.RedirectHandler(internal.URL, http.StatusTemporaryRedirect))
+	defer public.Close()
+
+	sender := NewHTTPSender(time.Second)
+	transport := sender.Client.Transport.(*http.Transport)
+	guarded := transport.DialContext
+	// Let the sender reach the "public" server only; everything else goes
+	// through the guard as usual.
+	transport.DialContext = func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if "http://"+address == public.URL {
+			return (&net.Dialer{}).DialContext(ctx, network, address)
+		}
+		return guarded(ctx, network, address)
+	}
+
+	_, err := sender.Send(context.Background(), Request{URL: public.URL, Body: []byte(`{}`)})
+	if !errors.Is(err, ErrBlockedAddress) {
+		t.Errorf("err = %v, want ErrBlockedAddress", err)
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"10.1.2.3", true},
+		{"172.16.0.1", true},
+		{"192.168.1.1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"fd00:ec2::254", true},
+		{"100.100.100.200", true},
+		{"0.0.0.0", true},
+		{"::", true},
+		{"::ffff:127.0.0.1", true},
+		{"224.0.0.1", true},
+		{"8.8.8.8", false},
+		{"2606:4700:4700::1111", false},
+		{"172.32.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := Blocked(netip.MustParseAddr(tt.ip)); got != tt.want {
+			t.Errorf("Blocked(%s) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestHTTPSenderUnreachable(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	url := server.URL
+	server.Close()
+
+	resp, err := loopbackSender().Send(context.Background(), Request{URL: url})
+	if err == nil {
+		t.Fatal("expected an error for a closed server")
+	}
+	if resp.Succeeded() {
+		t.Error("nil response reported success")
+	}
+}
+
+// loopbackSender is a sender without the guard against internal addresses,
+// so it can reach httptest servers.
+func loopbackSender() *HTTPSender {
+	return &HTTPSender{Client: &http.Client{Timeout: time.Second}}
+}
@@ -0,0 +1,70 @@
+package webhook
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"event":"course.published"}`)
+	now := time.Now().Unix()
+	signature := Sign(secret, now, body)
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      []byte
+		tolerance time.Duration
+		wantErr   bool
+	}{
+		{"round trip", secret, strconv.FormatInt(now, 10), signature, body, 5 * time.Minute, false},
+		{"tampered body", secret, strconv.FormatInt(now, 10), signature, []byte(`{"event":"course.deleted"}`), 5 * time.Minute, true},
+		{"wrong secret", "other", strconv.FormatInt(now, 10), signature, body, 5 * time.Minute, true},
+		{"timestamp swapped", secret, strconv.FormatInt(now+1, 10), signature, body, 5 * time.Minute, true},
+		{"stale timestamp", secret, strconv.FormatInt(now-600, 10), Sign(secret, now-600, body), body, 5 * time.Minute, true},
+		{"future timestamp", secret, strconv.FormatInt(now+600, 10), Sign(secret, now+600, body), body, 5 * time.Minute, true},
+		{"stale timestamp without tolerance", secret, strconv.FormatInt(now-600, 10), Sign(secret, now-600, body), body, 0, false},
+		{"malformed timestamp", secret, "yesterday", signature, body, 5 * time.Minute, true},
+		{"missing prefix", secret, strconv.FormatInt(now, 10), strings.TrimPrefix(signature, signaturePrefix), body, 5 * time.Minute, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Verify(tt.secret, tt.timestamp, tt.signature, tt.body, tt.tolerance)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignIsDeterministic(t *testing.T) {
+	body := []byte(`{}`)
+	a := Sign("s3cret", 1700000000, body)
+	b := Sign("s3cret", 1700000000, body)
+	if a != b {
+		t.Errorf("Sign() = %q then %q", a, b)
+	}
+	if !strings.HasPrefix(a, signaturePrefix) || len(a) != len(signaturePrefix)+64 {
+		t.Errorf("Sign() = %q, want sha256= followed by 64 hex chars", a)
+	}
+}
+
+func TestGenerateSecret(t *testing.T) {
+	a, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := GenerateSecret()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(a) != 64 || a == b {
+		t.Errorf("GenerateSecret() = %q, %q", a, b)
+	}
+}
@@ -0,0 +1,22 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+
+	"gorm.io/gorm"
+)
+
+type ContentWorkflowRepository interface {
+	WithTx(tx *gorm.DB) ContentWorkflowRepository
+
+	FindContentStatus(contentType string, id int64) (string, error)
+	LockContentStatus(contentType string, id int64) (string, error)
+	UpdateContentStatus(contentType string, id int64, status string) error
+	FindCourseByID(id int64) (*models.MCourse, error)
+
+	InsertContentTransition(data *models.TContentTransition) (*models.TContentTransition, error)
+	FindContentTransitions(contentType string, id int64) ([]models.TContentTransition, error)
+
+	InsertContentComment(data *models.TContentComment) (*models.TContentComment, error)
+	FindContentComments(contentType string, id int64) ([]models.TContentComment, error)
+}
@@ -2,10 +2,18 @@ package sql
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 
 	"gorm.io/gorm"
 )
 
+// DepartmentSummary counts what sits inside a department subtree.
+type DepartmentSummary struct {
+	Members  int64
+	Classes  int64
+	Students int64
+}
+
 type DepartmentRepository interface {
 	WithTx(tx *gorm.DB) DepartmentRepository
 	WithPreloads(preloads ...string) DepartmentRepository
@@ -14,8 +22,8 @@ type DepartmentRepository interface {
 	WithJoins(joins ...string) DepartmentRepository
 	WithWhere(query interface{}, args ...interface{}) DepartmentRepository
 	WithOrder(order string) DepartmentRepository
+	WithSpec(spec queryspec.Spec) DepartmentRepository
 	WithLimit(limit int) DepartmentRepository
-	WithCursor(cursor int) DepartmentRepository
 	WithUnscoped() DepartmentRepository
 
 	InsertDepartment(data *models.Department) (*models.Department, error)
@@ -26,6 +34,12 @@ type DepartmentRepository interface {
 	RemoveManyDepartments(ids []int64) error
 
 	FindDepartment() ([]models.Department, error)
+	FindDepartmentPage() ([]models.Department, queryspec.Page, error)
 	FindDepartmentByID(id int64) (*models.Department, error)
 	FindDepartmentsByIDs(ids []int64) ([]*models.Department, error)
+	CountDepartments() (int64, error)
+	CountDepartmentChildren(id int64) (int64, error)
+	FindDepartmentSubtreeIDs(id int64) ([]int64, error)
+	SummarizeDepartments(ids []int64) (*DepartmentSummary, error)
+	DetachDepartment(id int64) error
 }
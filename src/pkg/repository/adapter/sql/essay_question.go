@@ -15,7 +15,6 @@ type EssayQuestionRepository interface {
 	WithWhere(query interface{}, args ...interface{}) EssayQuestionRepository
 	WithOrder(order string) EssayQuestionRepository
 	WithLimit(limit int) EssayQuestionRepository
-	WithCursor(cursor int) EssayQuestionRepository
 
 	FindEssayQuestionByID(id int64) (*models.EssayQuestion, error)
 	FindEssayQuestionsByCodeQuestionID(codeQuestionID int64) ([]models.EssayQuestion, error)
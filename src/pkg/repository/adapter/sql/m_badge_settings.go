@@ -15,7 +15,6 @@ type MBadgeSettingsRepository interface {
 	WithWhere(query interface{}, args ...interface{}) MBadgeSettingsRepository
 	WithOrder(order string) MBadgeSettingsRepository
 	WithLimit(limit int) MBadgeSettingsRepository
-	WithCursor(cursor int) MBadgeSettingsRepository
 
 	InsertMBadgeSettings(data *models.MBadgeSettings) (*models.MBadgeSettings, error)
 	UpdateMBadgeSettings(id int64, updates map[string]interface{}) (*models.MBadgeSettings, error)
@@ -15,7 +15,6 @@ type MClassRepository interface {
 	WithWhere(query interface{}, args ...interface{}) MClassRepository
 	WithOrder(order string) MClassRepository
 	WithLimit(limit int) MClassRepository
-	WithCursor(cursor int) MClassRepository
 
 	InsertMClass(data *models.MClass) (*models.MClass, error)
 	UpdateMClass(id int64, updates map[string]interface{}) (*models.MClass, error)
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 
 	"gorm.io/gorm"
 )
@@ -14,8 +15,8 @@ type MCourseRepository interface {
 	WithJoins(joins ...string) MCourseRepository
 	WithWhere(query interface{}, args ...interface{}) MCourseRepository
 	WithOrder(order string) MCourseRepository
+	WithSpec(spec queryspec.Spec) MCourseRepository
 	WithLimit(limit int) MCourseRepository
-	WithCursor(cursor int) MCourseRepository
 
 	InsertMCourse(data *models.MCourse) (*models.MCourse, error)
 	UpdateMCourse(id int64, updates map[string]interface{}) (*models.MCourse, error)
@@ -24,5 +25,10 @@ type MCourseRepository interface {
 	RemoveManyMCourses(ids []int64) error
 
 	FindMCourse() ([]models.MCourse, error)
+	FindMCoursePage() ([]models.MCourse, queryspec.Page, error)
+	CountMCourses() (int64, error)
 	FindMCourseByID(id int64) (*models.MCourse, error)
+
+	FindCourseTree(id int64) (*models.MCourse, error)
+	CountLevelsByIDs(ids []int64) (int64, error)
 }
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 
 	"gorm.io/gorm"
 )
@@ -14,8 +15,8 @@ type MLessonRepository interface {
 	WithJoins(joins ...string) MLessonRepository
 	WithWhere(query interface{}, args ...interface{}) MLessonRepository
 	WithOrder(order string) MLessonRepository
+	WithSpec(spec queryspec.Spec) MLessonRepository
 	WithLimit(limit int) MLessonRepository
-	WithCursor(cursor int) MLessonRepository
 	WithUnscoped() MLessonRepository
 
 	InsertMLesson(data *models.MLesson) (*models.MLesson, error)
@@ -26,6 +27,9 @@ type MLessonRepository interface {
 	RemoveManyMLessons(ids []int64) error
 
 	FindMLesson() ([]models.MLesson, error)
+	FindMLessonPage() ([]models.MLesson, queryspec.Page, error)
+	CountMLessons() (int64, error)
 	FindMLessonByID(id int64) (*models.MLesson, error)
 	FindMLessonsByIDs(ids []int64) ([]*models.MLesson, error)
+	FindCourseIDsByLessonIDs(ids []int64) ([]int64, error)
 }
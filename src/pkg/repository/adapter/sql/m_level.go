@@ -15,7 +15,6 @@ type MLevelRepository interface {
 	WithWhere(query interface{}, args ...interface{}) MLevelRepository
 	WithOrder(order string) MLevelRepository
 	WithLimit(limit int) MLevelRepository
-	WithCursor(cursor int) MLevelRepository
 
 	InsertMLevel(data *models.MLevel) (*models.MLevel, error)
 	UpdateMLevel(id int64, updates map[string]interface{}) (*models.MLevel, error)
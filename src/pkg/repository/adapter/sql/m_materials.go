@@ -2,6 +2,7 @@ package sql
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 
 	"gorm.io/gorm"
 )
@@ -14,8 +15,8 @@ type MMaterialRepository interface {
 	WithJoins(joins ...string) MMaterialRepository
 	WithWhere(query interface{}, args ...interface{}) MMaterialRepository
 	WithOrder(order string) MMaterialRepository
+	WithSpec(spec queryspec.Spec) MMaterialRepository
 	WithLimit(limit int) MMaterialRepository
-	WithCursor(cursor int) MMaterialRepository
 	WithUnscoped() MMaterialRepository
 
 	InsertMMaterial(data *models.MMaterials) (*models.MMaterials, error)
@@ -26,6 +27,8 @@ type MMaterialRepository interface {
 	RemoveManyMMaterials(ids []int64) error
 
 	FindMMaterials() ([]models.MMaterials, error)
+	FindMMaterialPage() ([]models.MMaterials, queryspec.Page, error)
+	CountMMaterials() (int64, error)
 	FindMMaterialByID(id int64) (*models.MMaterials, error)
 	FindMMaterialsByIDs(ids []int64) ([]*models.MMaterials, error)
 }
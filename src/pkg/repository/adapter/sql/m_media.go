@@ -0,0 +1,28 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type MMediaRepository interface {
+	WithTx(tx *gorm.DB) MMediaRepository
+
+	InsertMedia(data *models.MMedia) (*models.MMedia, error)
+	FindMediaByID(id int64) (*models.MMedia, error)
+	FindMediaByHash(hash string, protected bool) (*models.MMedia, error)
+
+	InsertUpload(data *models.TMediaUpload) (*models.TMediaUpload, error)
+	FindUploadByID(id string) (*models.TMediaUpload, error)
+	LockUpload(id string) (*models.TMediaUpload, error)
+	UpdateUpload(id string, updates map[string]interface{}) error
+	FindExpiredUploads(before time.Time, limit int) ([]models.TMediaUpload, error)
+	RemoveUpload(id string) error
+
+	FindMaterialWithCourse(id int64) (*models.MMaterials, error)
+	FindCodeQuestionWithCourse(id int64) (*models.CodeQuestion, error)
+	FindVisibleCourse(id int64) (*models.MCourse, error)
+	HasActiveEnrollment(userID int64, courseID int64) (bool, error)
+}
@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+type MPromptTemplateRepository interface {
+	WithTx(tx *gorm.DB) MPromptTemplateRepository
+	WithWhere(query interface{}, args ...interface{}) MPromptTemplateRepository
+	WithOrder(order string) MPromptTemplateRepository
+	WithSpec(spec queryspec.Spec) MPromptTemplateRepository
+	WithLimit(limit int) MPromptTemplateRepository
+
+	InsertPromptTemplate(data *models.MPromptTemplate) (*models.MPromptTemplate, error)
+	UpdatePromptTemplate(id int64, updates map[string]interface{}) (*models.MPromptTemplate, error)
+	FindPromptTemplateByID(id int64) (*models.MPromptTemplate, error)
+	FindDefaultPromptTemplate(kind string) (*models.MPromptTemplate, error)
+	FindPromptTemplatePage() ([]models.MPromptTemplate, queryspec.Page, error)
+	CountPromptTemplates() (int64, error)
+	ClearDefaultPromptTemplates(kind string, schoolID *int64, exceptID int64) error
+
+	InsertPromptTemplateVersion(data *models.TPromptTemplateVersion) (*models.TPromptTemplateVersion, error)
+	FindPromptTemplateVersion(templateID int64, version int) (*models.TPromptTemplateVersion, error)
+	FindPromptTemplateVersionByID(id int64) (*models.TPromptTemplateVersion, error)
+	FindPromptTemplateVersionPage(templateID int64, spec queryspec.Spec) ([]models.TPromptTemplateVersion, queryspec.Page, error)
+	CountPromptTemplateVersions(templateID int64, spec queryspec.Spec) (int64, error)
+
+	InsertPromptEvaluation(data *models.TPromptEvaluation) (*models.TPromptEvaluation, error)
+	FindPromptEvaluationPage(templateID int64, spec queryspec.Spec) ([]models.TPromptEvaluation, queryspec.Page, error)
+	CountPromptEvaluations(templateID int64, spec queryspec.Spec) (int64, error)
+}
@@ -0,0 +1,82 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+// QuizAttemptSummary sums up the submitted attempts at a quiz.
+type QuizAttemptSummary struct {
+	Attempts     int64
+	Students     int64
+	AverageScore float64
+}
+
+// QuizItemStat counts the submitted answers to an item of a quiz.
+type QuizItemStat struct {
+	ItemID  int64
+	Answers int64
+	Correct int64
+	Points  int64
+}
+
+// QuizOptionPick counts how often an option of an item was chosen.
+type QuizOptionPick struct {
+	ItemID   int64
+	OptionID int
+	Picks    int64
+}
+
+// QuizWrongResponse counts a wrong numeric or short answer to an item.
+type QuizWrongResponse struct {
+	ItemID   int64
+	Response string
+	Count    int64
+}
+
+type MQuizRepository interface {
+	WithTx(tx *gorm.DB) MQuizRepository
+	WithWhere(query interface{}, args ...interface{}) MQuizRepository
+	WithOrder(order string) MQuizRepository
+	WithSpec(spec queryspec.Spec) MQuizRepository
+	WithLimit(limit int) MQuizRepository
+	WithPublishedOnly() MQuizRepository
+
+	InsertQuiz(data *models.MQuiz) (*models.MQuiz, error)
+	UpdateQuiz(id int64, updates map[string]interface{}) (*models.MQuiz, error)
+	RemoveQuiz(id int64) error
+	FindQuizByID(id int64) (*models.MQuiz, error)
+	FindQuizPage() ([]models.MQuiz, queryspec.Page, error)
+	CountQuizzes() (int64, error)
+
+	InsertQuizItem(data *models.MQuizItem) (*models.MQuizItem, error)
+	SaveQuizItem(data *models.MQuizItem) (*models.MQuizItem, error)
+	RemoveQuizItem(quizID int64, id int64) error
+	FindQuizItem(quizID int64, id int64) (*models.MQuizItem, error)
+	FindQuizItems(quizID int64) ([]models.MQuizItem, error)
+
+	InsertAttempt(data *models.TQuizAttempt) (*models.TQuizAttempt, error)
+	UpdateAttempt(id int64, updates map[string]interface{}) error
+	FindAttemptByID(id int64) (*models.TQuizAttempt, error)
+	LockAttempt(id int64) (*models.TQuizAttempt, error)
+	LockUserAttempts(quizID int64, userID int64) error
+	FindAttemptPage() ([]models.TQuizAttempt, queryspec.Page, error)
+	CountAttempts() (int64, error)
+	FindInProgressAttempt(quizID int64, userID int64) (*models.TQuizAttempt, error)
+	CountUserAttempts(quizID int64, userID int64) (int64, error)
+	CountSubmittedAttempts(quizID int64) (int64, error)
+	FindSubmittedScores(quizID int64, userID int64) ([]int, error)
+
+	InsertAnswers(data []models.TQuizAnswer) error
+	FindAnswers(attemptID int64) ([]models.TQuizAnswer, error)
+
+	FindAttemptSummary(quizID int64) (QuizAttemptSummary, error)
+	FindItemStats(quizID int64) ([]QuizItemStat, error)
+	FindOptionPicks(quizID int64) ([]QuizOptionPick, error)
+	FindWrongResponses(quizID int64, limit int) ([]QuizWrongResponse, error)
+
+	FindSubLessonByID(id int64) (*models.MSubLesson, error)
+	FindCourseByID(id int64) (*models.MCourse, error)
+}
@@ -0,0 +1,27 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+type MSchoolRepository interface {
+	WithTx(tx *gorm.DB) MSchoolRepository
+	WithWhere(query interface{}, args ...interface{}) MSchoolRepository
+	WithOrder(order string) MSchoolRepository
+	WithSpec(spec queryspec.Spec) MSchoolRepository
+	WithLimit(limit int) MSchoolRepository
+
+	InsertMSchool(data *models.MSchool) (*models.MSchool, error)
+	UpdateMSchool(id int64, updates map[string]interface{}) (*models.MSchool, error)
+	RemoveMSchool(id int64) error
+
+	FindMSchools() ([]models.MSchool, error)
+	FindMSchoolPage() ([]models.MSchool, queryspec.Page, error)
+	FindMSchoolByID(id int64) (*models.MSchool, error)
+	FindMSchoolByCode(code string) (*models.MSchool, error)
+	CountMSchools() (int64, error)
+	CountMSchoolMembers(id int64) (int64, error)
+}
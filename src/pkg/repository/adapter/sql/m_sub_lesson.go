@@ -2,6 +2,7 @@ package sql
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 
 	"gorm.io/gorm"
 )
@@ -14,8 +15,8 @@ type MSubLessonRepository interface {
 	WithJoins(joins ...string) MSubLessonRepository
 	WithWhere(query interface{}, args ...interface{}) MSubLessonRepository
 	WithOrder(order string) MSubLessonRepository
+	WithSpec(spec queryspec.Spec) MSubLessonRepository
 	WithLimit(limit int) MSubLessonRepository
-	WithCursor(cursor int) MSubLessonRepository
 	WithUnscoped() MSubLessonRepository
 
 	InsertMSubLesson(data *models.MSubLesson) (*models.MSubLesson, error)
@@ -26,6 +27,10 @@ type MSubLessonRepository interface {
 	RemoveManyMSubLessons(ids []int64) error
 
 	FindMSubLessons() ([]models.MSubLesson, error)
+	FindMSubLessonPage() ([]models.MSubLesson, queryspec.Page, error)
+	CountMSubLessons() (int64, error)
 	FindMSubLessonByID(id int64) (*models.MSubLesson, error)
 	FindMSubLessonsByIDs(ids []int64) ([]*models.MSubLesson, error)
+	FindCourseIDsBySubLessonIDs(ids []int64) ([]int64, error)
+	FindCourseIDsByLessonIDs(lessonIDs []int64) ([]int64, error)
 }
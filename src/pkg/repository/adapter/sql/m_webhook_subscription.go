@@ -0,0 +1,26 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+type MWebhookSubscriptionRepository interface {
+	WithTx(tx *gorm.DB) MWebhookSubscriptionRepository
+	WithWhere(query interface{}, args ...interface{}) MWebhookSubscriptionRepository
+	WithOrder(order string) MWebhookSubscriptionRepository
+	WithSpec(spec queryspec.Spec) MWebhookSubscriptionRepository
+	WithLimit(limit int) MWebhookSubscriptionRepository
+
+	InsertMWebhookSubscription(data *models.MWebhookSubscription) (*models.MWebhookSubscription, error)
+	UpdateMWebhookSubscription(id int64, updates map[string]interface{}) (*models.MWebhookSubscription, error)
+	RemoveMWebhookSubscription(id int64) error
+
+	FindMWebhookSubscriptions() ([]models.MWebhookSubscription, error)
+	FindMWebhookSubscriptionPage() ([]models.MWebhookSubscription, queryspec.Page, error)
+	FindMWebhookSubscriptionByID(id int64) (*models.MWebhookSubscription, error)
+	FindActiveMWebhookSubscriptions() ([]models.MWebhookSubscription, error)
+	CountMWebhookSubscriptions() (int64, error)
+}
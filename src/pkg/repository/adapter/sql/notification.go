@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository interface {
+	WithTx(tx *gorm.DB) NotificationRepository
+	WithWhere(query interface{}, args ...interface{}) NotificationRepository
+	WithOrder(order string) NotificationRepository
+	WithSpec(spec queryspec.Spec) NotificationRepository
+	WithLimit(limit int) NotificationRepository
+
+	InsertNotificationIgnoreDuplicate(data *models.Notification) (bool, error)
+	UpdateNotificationReadAt(userID int64, id int64, readAt *time.Time) (*models.Notification, error)
+	MarkAllNotificationsRead(userID int64, readAt time.Time) (int64, error)
+
+	FindNotifications() ([]models.Notification, error)
+	FindNotificationPage() ([]models.Notification, queryspec.Page, error)
+	CountNotifications() (int64, error)
+
+	FindPreferencesByUser(userID int64) ([]models.MNotificationPreference, error)
+	FindPreferencesByType(userIDs []int64, notificationType string) ([]models.MNotificationPreference, error)
+	UpsertPreference(data *models.MNotificationPreference) error
+	FindActiveUserIDsByRole(roleID int64, schoolID *int64) ([]int64, error)
+
+	InsertDeliveriesIgnoreDuplicates(data []*models.TNotificationDelivery) error
+	ClaimPendingDeliveries(limit int, now time.Time, leaseUntil time.Time) ([]models.TNotificationDelivery, error)
+	UpdateDelivery(id int64, updates map[string]interface{}) error
+}
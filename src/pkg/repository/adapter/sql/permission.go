@@ -15,7 +15,6 @@ type PermissionRepository interface {
 	WithWhere(query interface{}, args ...interface{}) PermissionRepository
 	WithOrder(order string) PermissionRepository
 	WithLimit(limit int) PermissionRepository
-	WithCursor(cursor int) PermissionRepository
 
 	InsertPermission(data *models.Permission) (*models.Permission, error)
 	UpdatePermission(id int64, updates map[string]interface{}) (*models.Permission, error)
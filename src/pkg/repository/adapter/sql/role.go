@@ -15,7 +15,6 @@ type RoleRepository interface {
 	WithWhere(query interface{}, args ...interface{}) RoleRepository
 	WithOrder(order string) RoleRepository
 	WithLimit(limit int) RoleRepository
-	WithCursor(cursor int) RoleRepository
 
 	InsertRole(data *models.Role) (*models.Role, error)
 	UpdateRole(id int64, updates map[string]interface{}) (*models.Role, error)
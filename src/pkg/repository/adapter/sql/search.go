@@ -0,0 +1,51 @@
+package sql
+
+import "gorm.io/gorm"
+
+// SearchQuery is a full-text search over published course content. Types,
+// CourseID and LevelID narrow the hits when set.
+type SearchQuery struct {
+	Query    string
+	Types    []string
+	CourseID *int64
+	LevelID  *int64
+	Limit    int
+	Offset   int
+}
+
+// SearchHit is one piece of content matching a search. Snippet is an excerpt
+// with each match between SearchMarkStart and SearchMarkStop.
+type SearchHit struct {
+	Type        string
+	ID          int64
+	CourseID    int64
+	LessonID    *int64
+	SubLessonID *int64
+	LevelID     *int64
+	Title       string
+	Snippet     string
+	Rank        float64
+}
+
+// SearchFacet counts the hits in one course or level.
+type SearchFacet struct {
+	ID    int64
+	Name  string
+	Count int64
+}
+
+// Markers around the matches in a snippet. They can't occur in content, so
+// the snippet can be escaped before they are turned into markup.
+const (
+	SearchMarkStart = "\x02"
+	SearchMarkStop  = "\x03"
+)
+
+type SearchRepository interface {
+	WithTx(tx *gorm.DB) SearchRepository
+
+	SearchContent(query SearchQuery) ([]SearchHit, error)
+	CountSearchHits(query SearchQuery) (int64, error)
+	CountSearchHitsByCourse(query SearchQuery) ([]SearchFacet, error)
+	CountSearchHitsByLevel(query SearchQuery) ([]SearchFacet, error)
+}
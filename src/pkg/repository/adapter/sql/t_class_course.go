@@ -0,0 +1,45 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+// LessonCompletion counts a student's completed sub-lessons within a lesson.
+type LessonCompletion struct {
+	UserID    int64
+	LessonID  int64
+	Completed int64
+	Late      int64
+}
+
+type TClassCourseRepository interface {
+	WithTx(tx *gorm.DB) TClassCourseRepository
+	WithPreloads(preloads ...string) TClassCourseRepository
+	WithWhere(query interface{}, args ...interface{}) TClassCourseRepository
+	WithOrder(order string) TClassCourseRepository
+	WithSpec(spec queryspec.Spec) TClassCourseRepository
+	WithLimit(limit int) TClassCourseRepository
+
+	InsertClassCourse(data *models.TClassCourse) (*models.TClassCourse, error)
+	UpdateClassCourse(id int64, updates map[string]interface{}) (*models.TClassCourse, error)
+	RemoveClassCourse(id int64) error
+	FindClassCourseByID(id int64) (*models.TClassCourse, error)
+	FindClassCourses() ([]models.TClassCourse, error)
+	FindClassCoursePage() ([]models.TClassCourse, queryspec.Page, error)
+	CountClassCourses() (int64, error)
+	FindActiveClassCourse(classID int64, courseID int64) (*models.TClassCourse, error)
+	ReplaceLessonDeadlines(classCourseID int64, deadlines []models.TClassLessonDeadline) error
+
+	FindCourseByID(courseID int64) (*models.MCourse, error)
+	FindCourseLessons(courseID int64) ([]models.MLesson, error)
+	FindUserByID(userID int64) (*models.User, error)
+	FindClassStudents(classID int64) ([]models.User, error)
+	EnrollStudents(courseID int64, userIDs []int64) ([]models.TStudentCourse, error)
+
+	CountSubLessonsByLesson(lessonIDs []int64) (map[int64]int64, error)
+	FindLessonCompletions(userIDs []int64, lessonIDs []int64) ([]LessonCompletion, error)
+	RefreshProgressLateFlags(classCourseID int64, userID int64, subLessonID int64) error
+}
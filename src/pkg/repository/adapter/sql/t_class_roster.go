@@ -0,0 +1,45 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+type TClassRosterRepository interface {
+	WithTx(tx *gorm.DB) TClassRosterRepository
+	WithPreloads(preloads ...string) TClassRosterRepository
+	WithWhere(query interface{}, args ...interface{}) TClassRosterRepository
+	WithOrder(order string) TClassRosterRepository
+	WithSpec(spec queryspec.Spec) TClassRosterRepository
+	WithLimit(limit int) TClassRosterRepository
+
+	FindClassByCode(code string) (*models.MClass, error)
+	FindClassByID(classID int64) (*models.MClass, error)
+	LockClass(classID int64) (*models.MClass, error)
+	UpdateClass(classID int64, updates map[string]interface{}) (*models.MClass, error)
+	FindUserByID(userID int64) (*models.User, error)
+	LockUser(userID int64) (*models.User, error)
+	SetUserSchool(userID int64, schoolID *int64) error
+	SetUserClass(userID int64, classID *int64) error
+
+	IsClassTeacher(classID int64, userID int64) (bool, error)
+	FindClassTeacherIDs(classID int64) ([]int64, error)
+	FindClassStudentIDs(classID int64) ([]int64, error)
+	AddClassTeacher(classID int64, userID int64) error
+	RemoveClassTeacher(classID int64, userID int64) error
+
+	InsertJoinRequest(data *models.TClassJoinRequest) (*models.TClassJoinRequest, error)
+	LockJoinRequest(id int64) (*models.TClassJoinRequest, error)
+	FindPendingJoinRequest(classID int64, userID int64) (*models.TClassJoinRequest, error)
+	UpdateJoinRequest(id int64, updates map[string]interface{}) (*models.TClassJoinRequest, error)
+	FindJoinRequests() ([]models.TClassJoinRequest, error)
+	FindJoinRequestPage() ([]models.TClassJoinRequest, queryspec.Page, error)
+	CountJoinRequests() (int64, error)
+
+	InsertRosterLogs(data []*models.TClassRosterLog) error
+	FindRosterLogs() ([]models.TClassRosterLog, error)
+	FindRosterLogPage() ([]models.TClassRosterLog, queryspec.Page, error)
+	CountRosterLogs() (int64, error)
+}
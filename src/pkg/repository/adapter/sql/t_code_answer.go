@@ -15,7 +15,6 @@ type TCodeAnswerRepository interface {
 	WithWhere(query interface{}, args ...interface{}) TCodeAnswerRepository
 	WithOrder(order string) TCodeAnswerRepository
 	WithLimit(limit int) TCodeAnswerRepository
-	WithCursor(cursor int) TCodeAnswerRepository
 
 	FindTCodeAnswersByCodeQuestionID(codeQuestionID int64) ([]models.TCodeAnswer, error)
 	CreateTCodeAnswer(data *models.TCodeAnswer) (*models.TCodeAnswer, error)
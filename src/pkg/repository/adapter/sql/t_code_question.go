@@ -15,7 +15,6 @@ type TCodeQuestionRepository interface {
 	WithWhere(query interface{}, args ...interface{}) TCodeQuestionRepository
 	WithOrder(order string) TCodeQuestionRepository
 	WithLimit(limit int) TCodeQuestionRepository
-	WithCursor(cursor int) TCodeQuestionRepository
 
 	FindTCodeQuestionByID(id int64) (*models.CodeQuestion, error)
 	FindTCodeQuestionsBySubLessonID(subLessonID int64) ([]models.CodeQuestion, error)
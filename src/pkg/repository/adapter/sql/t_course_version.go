@@ -0,0 +1,38 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+type TCourseVersionRepository interface {
+	WithTx(tx *gorm.DB) TCourseVersionRepository
+	WithWhere(query interface{}, args ...interface{}) TCourseVersionRepository
+	WithOrder(order string) TCourseVersionRepository
+	WithSpec(spec queryspec.Spec) TCourseVersionRepository
+	WithLimit(limit int) TCourseVersionRepository
+
+	InsertCourseVersion(data *models.TCourseVersion) (*models.TCourseVersion, error)
+	FindCourseVersionByID(id int64) (*models.TCourseVersion, error)
+	FindLatestCourseVersion(courseID int64) (*models.TCourseVersion, error)
+	FindCourseVersions() ([]models.TCourseVersion, error)
+	FindCourseVersionPage() ([]models.TCourseVersion, queryspec.Page, error)
+	CountCourseVersions() (int64, error)
+
+	FindCourseByID(id int64) (*models.MCourse, error)
+	FindCourseTree(id int64) (*models.MCourse, error)
+	UpdateCourseContent(id int64, updates map[string]interface{}) error
+	SaveLesson(data *models.MLesson) error
+	SaveSubLesson(data *models.MSubLesson) error
+	SaveMaterial(data *models.MMaterials) error
+	ArchiveLessons(ids []int64) error
+	ArchiveMaterials(ids []int64) error
+	RemoveSubLessons(ids []int64) error
+	InsertContentTransition(data *models.TContentTransition) error
+
+	LockStudentCourse(id int64) (*models.TStudentCourse, error)
+	FindVisibleUserByID(userID int64) (*models.User, error)
+	UpdateStudentCourseVersion(id int64, versionID int64) error
+}
@@ -15,8 +15,9 @@ type TEssayAnswerRepository interface {
 	WithWhere(query interface{}, args ...interface{}) TEssayAnswerRepository
 	WithOrder(order string) TEssayAnswerRepository
 	WithLimit(limit int) TEssayAnswerRepository
-	WithCursor(cursor int) TEssayAnswerRepository
 
 	FindTEssayAnswersByEssayQuestionIDAndUserID(essayQuestionID, userID int64) (*models.TEssayAnswer, error)
 	CreateTEssayAnswer(data *models.TEssayAnswer) (*models.TEssayAnswer, error)
+	FindTEssayAnswerByID(id int64) (*models.TEssayAnswer, error)
+	UpdateTEssayAnswer(id int64, updates map[string]interface{}) (*models.TEssayAnswer, error)
 }
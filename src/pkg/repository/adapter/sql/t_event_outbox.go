@@ -0,0 +1,25 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TEventOutboxRepository interface {
+	WithTx(tx *gorm.DB) TEventOutboxRepository
+	WithWhere(query interface{}, args ...interface{}) TEventOutboxRepository
+	WithOrder(order string) TEventOutboxRepository
+	WithSpec(spec queryspec.Spec) TEventOutboxRepository
+	WithLimit(limit int) TEventOutboxRepository
+
+	ClaimPendingTEventOutboxes(limit int, now time.Time) ([]models.TEventOutbox, error)
+	UpdateTEventOutbox(id int64, updates map[string]interface{}) (*models.TEventOutbox, error)
+
+	FindTEventOutboxes() ([]models.TEventOutbox, error)
+	FindTEventOutboxPage() ([]models.TEventOutbox, queryspec.Page, error)
+	FindTEventOutboxByID(id int64) (*models.TEventOutbox, error)
+	CountTEventOutboxes() (int64, error)
+}
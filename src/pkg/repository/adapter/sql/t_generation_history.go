@@ -0,0 +1,35 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+
+	"gorm.io/gorm"
+)
+
+type TGenerationHistoryRepository interface {
+	WithTx(tx *gorm.DB) TGenerationHistoryRepository
+	WithWhere(query interface{}, args ...interface{}) TGenerationHistoryRepository
+	WithOrder(order string) TGenerationHistoryRepository
+	WithSpec(spec queryspec.Spec) TGenerationHistoryRepository
+	WithLimit(limit int) TGenerationHistoryRepository
+
+	InsertGenerationHistory(data *models.TGenerationHistory) (*models.TGenerationHistory, error)
+	UpdateGenerationHistory(id int64, updates map[string]interface{}) error
+	FindGenerationHistoryByID(id int64) (*models.TGenerationHistory, error)
+	LockGenerationHistory(id int64) (*models.TGenerationHistory, error)
+	FindGenerationHistoryPage() ([]models.TGenerationHistory, queryspec.Page, error)
+	CountGenerationHistories() (int64, error)
+
+	FindCourseByID(id int64) (*models.MCourse, error)
+	FindSubLessonByID(id int64) (*models.MSubLesson, error)
+	FindCodeQuestionByID(id int64) (*models.CodeQuestion, error)
+	InsertMaterials(data []*models.MMaterials) error
+	InsertCodeQuestions(data []*models.CodeQuestion) error
+	InsertEssayQuestions(data []*models.EssayQuestion) error
+	PublishCodeQuestions(ids []int64) error
+	PublishEssayQuestions(ids []int64) error
+	RemoveDraftMaterials(ids []int64) error
+	RemoveDraftCodeQuestions(ids []int64) error
+	RemoveDraftEssayQuestions(ids []int64) error
+}
@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TGuardianLinkRepository interface {
+	WithTx(tx *gorm.DB) TGuardianLinkRepository
+	WithPreloads(preloads ...string) TGuardianLinkRepository
+	WithWhere(query interface{}, args ...interface{}) TGuardianLinkRepository
+	WithOrder(order string) TGuardianLinkRepository
+	WithSpec(spec queryspec.Spec) TGuardianLinkRepository
+	WithLimit(limit int) TGuardianLinkRepository
+
+	InsertGuardianLink(data *models.TGuardianLink) (*models.TGuardianLink, error)
+	LockGuardianLink(id int64) (*models.TGuardianLink, error)
+	FindGuardianLink(guardianID int64, studentID int64) (*models.TGuardianLink, error)
+	UpdateGuardianLink(id int64, updates map[string]interface{}) (*models.TGuardianLink, error)
+	FindGuardianLinks() ([]models.TGuardianLink, error)
+	FindGuardianLinkPage() ([]models.TGuardianLink, queryspec.Page, error)
+	CountGuardianLinks() (int64, error)
+	FindDigestLinks(week string) ([]models.TGuardianLink, error)
+	MarkDigestSent(guardianID int64, week string) error
+
+	FindUserByID(userID int64) (*models.User, error)
+	FindVisibleUserByID(userID int64) (*models.User, error)
+	FindStudentByLogin(login string) (*models.User, error)
+	FindStudentCourses(studentID int64) ([]models.TStudentCourse, error)
+	FindReviewedEssays(studentID int64, since *time.Time, limit int) ([]models.TEssayAnswer, error)
+}
@@ -15,9 +15,9 @@ type TStudentCourseRepository interface {
 	WithWhere(query interface{}, args ...interface{}) TStudentCourseRepository
 	WithOrder(order string) TStudentCourseRepository
 	WithLimit(limit int) TStudentCourseRepository
-	WithCursor(cursor int) TStudentCourseRepository
 
 	EnrollCourse(data *models.TStudentCourse) (*models.TStudentCourse, error)
+	FindCourseByID(courseID int64) (*models.MCourse, error)
 	FindMyCourse(UserID int64) ([]models.TStudentCourse, error)
 	FindByID(id int64, userID int64) (*models.TStudentCourse, error)
 }
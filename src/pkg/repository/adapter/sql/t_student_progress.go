@@ -15,17 +15,42 @@ type TStudentProgressRepository interface {
 	WithWhere(query interface{}, args ...interface{}) TStudentProgressRepository
 	WithOrder(order string) TStudentProgressRepository
 	WithLimit(limit int) TStudentProgressRepository
-	WithCursor(cursor int) TStudentProgressRepository
 
-	CompleteTStudentProgress(
+	FirstOrCreateTStudentProgress(
 		data *models.TStudentProgress,
 	) (*models.TStudentProgress, error)
 
+	UpdateTStudentProgress(
+		id int64,
+		updates map[string]interface{},
+	) (*models.TStudentProgress, error)
+
+	ResetByUserAndCourse(
+		userID int64,
+		courseID int64,
+		subLessonIDs []int64,
+	) error
+
 	FindByUserAndSubLesson(
 		userID int64,
 		subLessonID int64,
 	) (*models.TStudentProgress, error)
 
+	FindByUserAndCourse(
+		userID int64,
+		courseID int64,
+	) ([]models.TStudentProgress, error)
+
+	FindCourseTree(
+		courseID int64,
+		publishedOnly bool,
+	) (*models.MCourse, error)
+
+	IsEnrolled(
+		userID int64,
+		courseID int64,
+	) (bool, error)
+
 	FindCourseIDBySubLesson(
 		subLessonID int64,
 	) (int64, error)
@@ -39,6 +64,57 @@ type TStudentProgressRepository interface {
 		courseID int64,
 	) (int64, error)
 
+	CountCompletedByCourseGroupedByUser(
+		courseID int64,
+	) (map[int64]int64, error)
+
+	FindCourseVersionSubLessonIDs(
+		versionID int64,
+	) ([]int64, error)
+
+	CountCompletedBySubLessons(
+		userID int64,
+		subLessonIDs []int64,
+	) (int64, error)
+
+	CountCompletedBySubLessonsGroupedByUser(
+		subLessonIDs []int64,
+	) (map[int64]int64, error)
+
+	LockStudentCourse(
+		userID int64,
+		courseID int64,
+	) (*models.TStudentCourse, error)
+
+	LockStudentCoursesByCourse(
+		courseID int64,
+	) ([]models.TStudentCourse, error)
+
+	FindEnrolledCourseIDs() ([]int64, error)
+
+	FindStudentByID(
+		id int64,
+	) (*models.User, error)
+
+	IsStudentTeacher(
+		studentID int64,
+		teacherID int64,
+	) (bool, error)
+
+	FindBadgeByScore(
+		score int,
+	) (*models.MBadgeSettings, error)
+
+	UpdateStudentCourseBadge(
+		studentCourseID int64,
+		badgeID int64,
+	) error
+
+	AddStudentCourseScore(
+		studentCourseID int64,
+		delta int,
+	) error
+
 	UpdateStudentCourseProgress(
 		userID int64,
 		courseID int64,
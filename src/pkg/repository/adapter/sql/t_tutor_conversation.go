@@ -0,0 +1,33 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TTutorConversationRepository interface {
+	WithTx(tx *gorm.DB) TTutorConversationRepository
+	WithWhere(query interface{}, args ...interface{}) TTutorConversationRepository
+	WithOrder(order string) TTutorConversationRepository
+	WithSpec(spec queryspec.Spec) TTutorConversationRepository
+	WithLimit(limit int) TTutorConversationRepository
+
+	InsertConversation(data *models.TTutorConversation) (*models.TTutorConversation, error)
+	FindConversationByID(id int64) (*models.TTutorConversation, error)
+	FindConversationPage() ([]models.TTutorConversation, queryspec.Page, error)
+	CountConversations() (int64, error)
+	AddConversationTokens(id int64, tokens int) error
+
+	InsertMessage(data *models.TTutorMessage) (*models.TTutorMessage, error)
+	FindMessages(conversationID int64) ([]models.TTutorMessage, error)
+	FindRecentMessages(conversationID int64, limit int) ([]models.TTutorMessage, error)
+	CountStudentMessagesSince(userID int64, since time.Time) (int64, error)
+	SumTokensSince(userID int64, since time.Time) (int64, error)
+
+	FindCourseByID(id int64) (*models.MCourse, error)
+	FindSubLessonByID(id int64) (*models.MSubLesson, error)
+	FindLatestErrorLog(userID int64, codeQuestionIDs []int64) (*models.TCodeHistoryLogs, error)
+}
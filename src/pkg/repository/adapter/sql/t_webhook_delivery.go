@@ -0,0 +1,29 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TWebhookDeliveryRepository interface {
+	WithTx(tx *gorm.DB) TWebhookDeliveryRepository
+	WithPreloads(preloads ...string) TWebhookDeliveryRepository
+	WithWhere(query interface{}, args ...interface{}) TWebhookDeliveryRepository
+	WithOrder(order string) TWebhookDeliveryRepository
+	WithSpec(spec queryspec.Spec) TWebhookDeliveryRepository
+	WithLimit(limit int) TWebhookDeliveryRepository
+
+	InsertTWebhookDelivery(data *models.TWebhookDelivery) (*models.TWebhookDelivery, error)
+	InsertManyTWebhookDeliveriesIgnoreDuplicates(data []*models.TWebhookDelivery) error
+	ClaimPendingTWebhookDeliveries(limit int, now time.Time, leaseUntil time.Time) ([]models.TWebhookDelivery, error)
+	UpdateTWebhookDelivery(id int64, updates map[string]interface{}) (*models.TWebhookDelivery, error)
+	RecordTWebhookDeliveryAttempt(id int64, updates map[string]interface{}) error
+
+	FindTWebhookDeliveries() ([]models.TWebhookDelivery, error)
+	FindTWebhookDeliveryPage() ([]models.TWebhookDelivery, queryspec.Page, error)
+	FindTWebhookDeliveryByID(id int64) (*models.TWebhookDelivery, error)
+	CountTWebhookDeliveries() (int64, error)
+}
@@ -15,7 +15,6 @@ type TWonderingScoreRepository interface {
 	WithWhere(query interface{}, args ...interface{}) TWonderingScoreRepository
 	WithOrder(order string) TWonderingScoreRepository
 	WithLimit(limit int) TWonderingScoreRepository
-	WithCursor(cursor int) TWonderingScoreRepository
 
 	FindTWonderingScoresBySubLessonIDAndUserID(subLessonID, userID int64) (*models.TWonderingScore, error)
 	CreateTWonderingScore(data *models.TWonderingScore) (*models.TWonderingScore, error)
@@ -2,6 +2,7 @@ package sql
 
 import (
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 
 	"gorm.io/gorm"
 )
@@ -14,8 +15,8 @@ type UserRepository interface {
 	WithJoins(joins ...string) UserRepository
 	WithWhere(query interface{}, args ...interface{}) UserRepository
 	WithOrder(order string) UserRepository
+	WithSpec(spec queryspec.Spec) UserRepository
 	WithLimit(limit int) UserRepository
-	WithCursor(cursor int) UserRepository
 	WithUnscoped() UserRepository
 
 	InsertUser(data *models.User) (*models.User, error)
@@ -27,6 +28,9 @@ type UserRepository interface {
 	RemoveManyUsers(ids []int64) error
 
 	FindUser() ([]models.User, error)
+	FindUserPage() ([]models.User, queryspec.Page, error)
+	CountUsers() (int64, error)
 	FindUserByID(id int64) (*models.User, error)
 	FindUserByEmail(email string) (*models.User, error)
+	CountRolesByName(roleIDs []int64, name string) (int64, error)
 }
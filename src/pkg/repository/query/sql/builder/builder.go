@@ -1,10 +1,17 @@
 package builder
 
 import (
+	"database/sql"
 	"fmt"
 	"jk-api/internal/helper"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/tenant"
+	"reflect"
+	"slices"
+	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
 )
 
 type QueryBuilder[T any] struct {
@@ -14,8 +21,8 @@ type QueryBuilder[T any] struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	orderBy      string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 	associations []string
 	replacements map[string]interface{}
 	unscoped     bool
@@ -47,13 +54,15 @@ func (qb *QueryBuilder[T]) WithOrder(order string) *QueryBuilder[T] {
 	return qb
 }
 
-func (qb *QueryBuilder[T]) WithLimit(l int) *QueryBuilder[T] {
-	qb.limit = &l
+// WithSpec adds the filters of a parsed list query. Its sorts, if any, take
+// the place of WithOrder, and its limit and cursor the place of WithLimit.
+func (qb *QueryBuilder[T]) WithSpec(spec queryspec.Spec) *QueryBuilder[T] {
+	qb.spec = spec
 	return qb
 }
 
-func (qb *QueryBuilder[T]) WithCursor(c int) *QueryBuilder[T] {
-	qb.cursor = &c
+func (qb *QueryBuilder[T]) WithLimit(l int) *QueryBuilder[T] {
+	qb.limit = &l
 	return qb
 }
 
@@ -83,6 +92,51 @@ func (qb *QueryBuilder[T]) FindAll() ([]T, error) {
 	return results, nil
 }
 
+// FindPage finds one page of rows under the spec given to WithSpec, along
+// with the cursors to its neighbours. Page.Total is left for the caller.
+func (qb *QueryBuilder[T]) FindPage() ([]T, queryspec.Page, error) {
+	var results []T
+	tx := qb.buildQuery()
+	if qb.spec.Limit > 0 {
+		tx = tx.Limit(qb.spec.Limit + 1)
+	}
+	if err := tx.Find(&results).Error; err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	more := qb.spec.Limit > 0 && len(results) > qb.spec.Limit
+	if more {
+		results = results[:qb.spec.Limit]
+	}
+	if qb.spec.Cursor != nil && qb.spec.Cursor.Backward {
+		slices.Reverse(results)
+	}
+	if len(results) == 0 {
+		return results, queryspec.Page{Limit: qb.spec.Limit}, nil
+	}
+
+	first, err := qb.sortValues(&results[0])
+	if err != nil {
+		return nil, queryspec.Page{}, err
+	}
+	last, err := qb.sortValues(&results[len(results)-1])
+	if err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	page, err := qb.spec.Page(first, last, more)
+	if err != nil {
+		return nil, queryspec.Page{}, err
+	}
+	return results, page, nil
+}
+
+// Query returns the built query without running it, e.g. to use as a
+// subquery.
+func (qb *QueryBuilder[T]) Query() *gorm.DB {
+	return qb.buildQuery().Model(new(T))
+}
+
 func (qb *QueryBuilder[T]) FindOne() (*T, error) {
 	var model T
 	tx := qb.buildQuery()
@@ -120,10 +174,20 @@ func (qb *QueryBuilder[T]) FindFirst() (*T, error) {
 }
 
 func (qb *QueryBuilder[T]) Create(data *T) error {
+	qb.assignTenant(data)
+	if err := qb.checkTenantParents([]*T{data}); err != nil {
+		return err
+	}
 	return qb.db.Create(data).Error
 }
 
 func (qb *QueryBuilder[T]) CreateMany(data []*T) error {
+	for _, item := range data {
+		qb.assignTenant(item)
+	}
+	if err := qb.checkTenantParents(data); err != nil {
+		return err
+	}
 	return qb.db.Create(data).Error
 }
 
@@ -135,7 +199,10 @@ func (qb *QueryBuilder[T]) UpdateByID(id int64, updates map[string]interface{})
 		findDB = findDB.Unscoped()
 	}
 
-	if err := findDB.First(&model, id).Error; err != nil {
+	if err := qb.applyTenant(findDB, true).First(&model, id).Error; err != nil {
+		return nil, err
+	}
+	if err := qb.checkTenantParentUpdate(updates); err != nil {
 		return nil, err
 	}
 	if err := qb.updateAssociations(&model); err != nil {
@@ -147,7 +214,7 @@ func (qb *QueryBuilder[T]) UpdateByID(id int64, updates map[string]interface{})
 		freshDB = freshDB.Unscoped()
 	}
 
-	if err := freshDB.Model(&model).Updates(updates).Error; err != nil {
+	if err := freshDB.Model(&model).Updates(qb.stripTenantColumn(updates)).Error; err != nil {
 		return nil, err
 	}
 	return &model, nil
@@ -163,8 +230,12 @@ func (qb *QueryBuilder[T]) UpdateMany(ids []int64, updates map[string]interface{
 	}
 
 	db = qb.applyWhere(db)
+	db = qb.applyTenant(db, true)
 
-	if err = db.Model(new(T)).Updates(updates).Error; err != nil {
+	if err = qb.checkTenantParentUpdate(updates); err != nil {
+		return nil, err
+	}
+	if err = db.Model(new(T)).Updates(qb.stripTenantColumn(updates)).Error; err != nil {
 		return nil, err
 	}
 
@@ -173,15 +244,16 @@ func (qb *QueryBuilder[T]) UpdateMany(ids []int64, updates map[string]interface{
 
 func (qb *QueryBuilder[T]) Delete(id any) error {
 	db := qb.db
+	scoped := qb.applyTenant(db, true)
 
 	switch v := id.(type) {
 	case []int64:
-		if err := db.Where("id IN ?", v).Delete(new(T)).Error; err != nil {
+		if err := scoped.Where("id IN ?", v).Delete(new(T)).Error; err != nil {
 			return err
 		}
 	default:
 		var model T
-		if err := db.First(&model, id).Error; err != nil {
+		if err := scoped.First(&model, id).Error; err != nil {
 			return err
 		}
 		if err := qb.deleteAssociations(&model); err != nil {
@@ -216,6 +288,7 @@ func (qb *QueryBuilder[T]) Delete(id any) error {
 
 func (qb *QueryBuilder[T]) DeleteWhere() error {
 	db := qb.applyWhere(qb.db)
+	db = qb.applyTenant(db, true)
 
 	if qb.unscoped {
 		db = db.Unscoped()
@@ -224,9 +297,11 @@ func (qb *QueryBuilder[T]) DeleteWhere() error {
 	return db.Delete(new(T)).Error
 }
 
+// Count counts every row matching the filters, ignoring the order, limit
+// and cursor.
 func (qb *QueryBuilder[T]) Count() (int64, error) {
 	var count int64
-	tx := qb.buildQuery()
+	tx := qb.filteredQuery()
 	if err := tx.Model(new(T)).Count(&count).Error; err != nil {
 		return 0, err
 	}
@@ -236,8 +311,25 @@ func (qb *QueryBuilder[T]) Count() (int64, error) {
 // ---------- Internal Utilities ----------
 
 func (qb *QueryBuilder[T]) buildQuery() *gorm.DB {
+	tx := qb.filteredQuery()
+	tx = qb.spec.Keyset(tx)
+
+	if len(qb.spec.Sorts) > 0 {
+		tx = qb.spec.Order(tx)
+	} else if qb.orderBy != "" {
+		tx = tx.Order(qb.orderBy)
+	}
+	if qb.spec.Limit > 0 {
+		tx = tx.Limit(qb.spec.Limit)
+	} else if qb.limit != nil {
+		tx = tx.Limit(*qb.limit)
+	}
+
+	return tx
+}
+
+func (qb *QueryBuilder[T]) filteredQuery() *gorm.DB {
 	tx := qb.db
-	fmt.Println("qb cursor", qb.cursor)
 	if qb.unscoped {
 		tx = tx.Unscoped()
 	}
@@ -247,18 +339,29 @@ func (qb *QueryBuilder[T]) buildQuery() *gorm.DB {
 		tx = tx.Joins(join)
 	}
 	tx = qb.applyWhere(tx)
+	tx = qb.spec.Where(tx)
+	tx = qb.applyTenant(tx, false)
 
-	if qb.orderBy != "" {
-		tx = tx.Order(qb.orderBy)
-	}
-	if qb.limit != nil {
-		tx = tx.Limit(*qb.limit)
-	}
-	if qb.cursor != nil {
-		tx = tx.Where("id > ?", *qb.cursor)
+	return tx
+}
+
+// sortValues reads the values of the spec's sort columns from row.
+func (qb *QueryBuilder[T]) sortValues(row *T) ([]any, error) {
+	stmt := &gorm.Statement{DB: qb.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return nil, err
 	}
 
-	return tx
+	values := make([]any, len(qb.spec.Sorts))
+	for i, sort := range qb.spec.Sorts {
+		column := sort.Column[strings.LastIndex(sort.Column, ".")+1:]
+		field := stmt.Schema.LookUpField(column)
+		if field == nil {
+			return nil, fmt.Errorf("kolom %s tidak ada di %s", column, stmt.Schema.Table)
+		}
+		values[i], _ = field.ValueOf(qb.db.Statement.Context, reflect.ValueOf(row).Elem())
+	}
+	return values, nil
 }
 
 func (qb *QueryBuilder[T]) applyPreload(tx *gorm.DB) *gorm.DB {
@@ -275,6 +378,185 @@ func (qb *QueryBuilder[T]) applyWhere(tx *gorm.DB) *gorm.DB {
 	return tx
 }
 
+// applyTenant restricts tx to the school in the request scope when T is owned
+// by a school, directly or through its parent rows, and to the caller's
+// department subtree when T is owned by a department. Rows shared across
+// schools can be read from any school but only written outside of a school
+// scope.
+func (qb *QueryBuilder[T]) applyTenant(tx *gorm.DB, write bool) *gorm.DB {
+	scope, ok := tenant.FromContext(qb.db.Statement.Context)
+	if !ok || scope.AllSchools {
+		return tx
+	}
+
+	if owned, ok := any(new(T)).(tenant.DepartmentOwned); ok && scope.DepartmentID != nil {
+		condition := owned.DepartmentCondition(tenant.DepartmentSubtreeSQL)
+		tx = tx.Where(condition, sql.Named("department_id", *scope.DepartmentID))
+	}
+
+	if condition, args, ok := schoolCondition(any(new(T)), scope, write); ok {
+		tx = tx.Where(condition, args...)
+	}
+	return tx
+}
+
+// schoolCondition returns the WHERE condition limiting model to the school in
+// scope. A nested model is limited to the parent rows that are themselves in
+// scope, so lessons follow their course and sub-lessons their lesson.
+func schoolCondition(model any, scope tenant.Scope, write bool) (string, []any, bool) {
+	if nested, ok := model.(tenant.Nested); ok {
+		parent := nested.Parent()
+		condition, args, ok := schoolCondition(parent, scope, write)
+		if !ok {
+			return "", nil, false
+		}
+		table := parent.(schema.Tabler).TableName()
+		return fmt.Sprintf("%s IN (SELECT %s.id FROM %s WHERE %s)", nested.ParentColumn(), table, table, condition), args, true
+	}
+
+	owned, ok := model.(tenant.Owned)
+	if !ok {
+		return "", nil, false
+	}
+
+	column := owned.SchoolColumn()
+	if scope.SchoolID == nil {
+		return column + " IS NULL", nil, true
+	}
+	if _, shared := owned.(tenant.Shared); shared && !write {
+		return "(" + column + " = ? OR " + column + " IS NULL)", []any{*scope.SchoolID}, true
+	}
+	return column + " = ?", []any{*scope.SchoolID}, true
+}
+
+// checkTenantParents makes sure nested rows are only created under parents
+// the caller may write to, e.g. no lessons in another school's course. It
+// returns gorm.ErrRecordNotFound otherwise, as a lookup of the parent would.
+func (qb *QueryBuilder[T]) checkTenantParents(data []*T) error {
+	scope, ok := tenant.FromContext(qb.db.Statement.Context)
+	if !ok || scope.AllSchools {
+		return nil
+	}
+	nested, ok := any(new(T)).(tenant.Nested)
+	if !ok {
+		return nil
+	}
+
+	stmt := &gorm.Statement{DB: qb.db}
+	if err := stmt.Parse(new(T)); err != nil {
+		return err
+	}
+	column := nested.ParentColumn()
+	field := stmt.Schema.LookUpField(column[strings.LastIndex(column, ".")+1:])
+	if field == nil {
+		return fmt.Errorf("kolom %s tidak ada di %s", column, stmt.Schema.Table)
+	}
+
+	var ids []any
+	for _, item := range data {
+		id, zero := field.ValueOf(qb.db.Statement.Context, reflect.ValueOf(item).Elem())
+		if !zero && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	return qb.checkParentIDs(nested, scope, ids)
+}
+
+// checkTenantParentUpdate applies the same rule to updates moving rows under
+// another parent, e.g. a lesson into another school's course.
+func (qb *QueryBuilder[T]) checkTenantParentUpdate(updates map[string]interface{}) error {
+	scope, ok := tenant.FromContext(qb.db.Statement.Context)
+	if !ok || scope.AllSchools {
+		return nil
+	}
+	nested, ok := any(new(T)).(tenant.Nested)
+	if !ok {
+		return nil
+	}
+
+	column := nested.ParentColumn()
+	id, exists := updates[column[strings.LastIndex(column, ".")+1:]]
+	if !exists || id == nil {
+		return nil
+	}
+	if value := reflect.ValueOf(id); value.Kind() == reflect.Pointer {
+		if value.IsNil() {
+			return nil
+		}
+		id = value.Elem().Interface()
+	}
+	return qb.checkParentIDs(nested, scope, []any{id})
+}
+
+// checkParentIDs returns gorm.ErrRecordNotFound unless every id is a parent
+// row the caller may write to.
+func (qb *QueryBuilder[T]) checkParentIDs(nested tenant.Nested, scope tenant.Scope, ids []any) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	parent := nested.Parent()
+	condition, args, _ := schoolCondition(parent, scope, true)
+	var count int64
+	err := qb.db.Session(&gorm.Session{NewDB: true}).
+		Model(parent).
+		Where("id IN ?", ids).
+		Where(condition, args...).
+		Count(&count).
+		Error
+	if err != nil {
+		return err
+	}
+	if count != int64(len(ids)) {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// assignTenant stamps data with the school in the request scope so a school
+// can't create rows on behalf of another one. Rows created without a
+// department land in the caller's department.
+func (qb *QueryBuilder[T]) assignTenant(data *T) {
+	scope, ok := tenant.FromContext(qb.db.Statement.Context)
+	if !ok || scope.AllSchools {
+		return
+	}
+	if assignable, ok := any(data).(tenant.Assignable); ok && scope.SchoolID != nil {
+		assignable.AssignSchool(*scope.SchoolID)
+	}
+	if assignable, ok := any(data).(tenant.DepartmentAssignable); ok && scope.DepartmentID != nil {
+		assignable.AssignDepartment(*scope.DepartmentID)
+	}
+}
+
+// stripTenantColumn drops the owning school from updates made inside a school
+// scope; moving rows between schools is reserved for platform admins.
+func (qb *QueryBuilder[T]) stripTenantColumn(updates map[string]interface{}) map[string]interface{} {
+	scope, ok := tenant.FromContext(qb.db.Statement.Context)
+	if !ok || scope.AllSchools {
+		return updates
+	}
+
+	owned, ok := any(new(T)).(tenant.Owned)
+	if !ok {
+		return updates
+	}
+
+	column := owned.SchoolColumn()
+	column = column[strings.LastIndex(column, ".")+1:]
+	if _, exists := updates[column]; !exists {
+		return updates
+	}
+
+	stripped := make(map[string]interface{}, len(updates))
+	for key, value := range updates {
+		if key != column {
+			stripped[key] = value
+		}
+	}
+	return stripped
+}
+
 func (qb *QueryBuilder[T]) updateAssociations(model any) error {
 	for _, relation := range qb.associations {
 		if data, ok := qb.replacements[relation]; ok {
@@ -0,0 +1,149 @@
+package sql
+
+import (
+	"fmt"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type contentWorkflowRepository struct {
+	db *gorm.DB
+}
+
+func NewContentWorkflowRepository() adapter.ContentWorkflowRepository {
+	return &contentWorkflowRepository{db: config.DB}
+}
+
+func (repo *contentWorkflowRepository) WithTx(tx *gorm.DB) adapter.ContentWorkflowRepository {
+	return &contentWorkflowRepository{db: tx}
+}
+
+// --- 🔧 Content ---
+
+// FindContentStatus returns the editorial status of the content. Courses go
+// through the query builder so only courses visible to the caller are found.
+func (repo *contentWorkflowRepository) FindContentStatus(contentType string, id int64) (string, error) {
+	return findContentStatus(repo.db, contentType, id, false)
+}
+
+// LockContentStatus is FindContentStatus that also locks the row for the rest
+// of the transaction.
+func (repo *contentWorkflowRepository) LockContentStatus(contentType string, id int64) (string, error) {
+	return findContentStatus(repo.db, contentType, id, true)
+}
+
+// UpdateContentStatus sets the status and keeps the published flag in step.
+// Shared courses can only be changed by platform admins.
+func (repo *contentWorkflowRepository) UpdateContentStatus(contentType string, id int64, status string) error {
+	updates := map[string]interface{}{
+		"status":    status,
+		"published": status == constant.ContentPublished,
+	}
+
+	var err error
+	switch contentType {
+	case constant.ContentTypeCourse:
+		_, err = builder.NewQueryBuilder[models.MCourse](repo.db).UpdateByID(id, updates)
+	case constant.ContentTypeLesson:
+		_, err = builder.NewQueryBuilder[models.MLesson](repo.db).UpdateByID(id, updates)
+	case constant.ContentTypeMaterial:
+		_, err = builder.NewQueryBuilder[models.MMaterials](repo.db).UpdateByID(id, updates)
+	default:
+		err = fmt.Errorf("unknown content type %q", contentType)
+	}
+	return err
+}
+
+func (repo *contentWorkflowRepository) FindCourseByID(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(id)
+}
+
+func findContentStatus(db *gorm.DB, contentType string, id int64, lock bool) (string, error) {
+	switch contentType {
+	case constant.ContentTypeCourse:
+		data, err := findContent[models.MCourse](db, id, lock)
+		if err != nil {
+			return "", err
+		}
+		return data.Status, nil
+	case constant.ContentTypeLesson:
+		data, err := findContent[models.MLesson](db, id, lock)
+		if err != nil {
+			return "", err
+		}
+		return data.Status, nil
+	case constant.ContentTypeMaterial:
+		data, err := findContent[models.MMaterials](db, id, lock)
+		if err != nil {
+			return "", err
+		}
+		return data.Status, nil
+	}
+	return "", fmt.Errorf("unknown content type %q", contentType)
+}
+
+func findContent[T any](db *gorm.DB, id int64, lock bool) (*T, error) {
+	qb := builder.NewQueryBuilder[T](db)
+	if lock {
+		qb = qb.WithWhere(func(tx *gorm.DB) *gorm.DB {
+			return tx.Clauses(clause.Locking{Strength: "UPDATE"})
+		})
+	}
+	return qb.FindByID(id)
+}
+
+// --- 🔧 Transitions ---
+
+func (repo *contentWorkflowRepository) InsertContentTransition(data *models.TContentTransition) (*models.TContentTransition, error) {
+	if err := repo.db.Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *contentWorkflowRepository) FindContentTransitions(contentType string, id int64) ([]models.TContentTransition, error) {
+	var data []models.TContentTransition
+
+	err := repo.db.
+		Preload("Actor").
+		Where("content_type = ? AND content_id = ?", contentType, id).
+		Order("created_at ASC, id ASC").
+		Find(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// --- 🔧 Comments ---
+
+func (repo *contentWorkflowRepository) InsertContentComment(data *models.TContentComment) (*models.TContentComment, error) {
+	if err := repo.db.Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *contentWorkflowRepository) FindContentComments(contentType string, id int64) ([]models.TContentComment, error) {
+	var data []models.TContentComment
+
+	err := repo.db.
+		Preload("Author").
+		Where("content_type = ? AND content_id = ?", contentType, id).
+		Order("created_at ASC, id ASC").
+		Find(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
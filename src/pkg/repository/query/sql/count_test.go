@@ -0,0 +1,85 @@
+package sql
+
+import (
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"net/url"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+// The total of a list page must be counted under the same filters as the
+// page itself.
+func TestCountsApplyTheSpec(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields queryspec.Fields
+		count  func(db *gorm.DB, spec queryspec.Spec) (int64, error)
+		want   string
+	}{
+		{"departments", (*models.Department)(nil).QueryFields(), func(db *gorm.DB, spec queryspec.Spec) (int64, error) {
+			return (&departmentRepository{db: db}).WithSpec(spec).CountDepartments()
+		}, "departments.id = 7"},
+		{"schools", (*models.MSchool)(nil).QueryFields(), func(db *gorm.DB, spec queryspec.Spec) (int64, error) {
+			return (&mSchoolRepository{db: db}).WithSpec(spec).CountMSchools()
+		}, "m_school.id = 7"},
+		{"webhook subscriptions", (*models.MWebhookSubscription)(nil).QueryFields(), func(db *gorm.DB, spec queryspec.Spec) (int64, error) {
+			return (&mWebhookSubscriptionRepository{db: db}).WithSpec(spec).CountMWebhookSubscriptions()
+		}, "m_webhook_subscription.id = 7"},
+		{"notifications", (*models.Notification)(nil).QueryFields(), func(db *gorm.DB, spec queryspec.Spec) (int64, error) {
+			return (&notificationRepository{db: db}).WithSpec(spec).CountNotifications()
+		}, "notifications.id = 7"},
+		{"event outbox", (*models.TEventOutbox)(nil).QueryFields(), func(db *gorm.DB, spec queryspec.Spec) (int64, error) {
+			return (&tEventOutboxRepository{db: db}).WithSpec(spec).CountTEventOutboxes()
+		}, "t_event_outbox.id = 7"},
+		{"webhook deliveries", (*models.TWebhookDelivery)(nil).QueryFields(), func(db *gorm.DB, spec queryspec.Spec) (int64, error) {
+			return (&tWebhookDeliveryRepository{db: db}).WithSpec(spec).CountTWebhookDeliveries()
+		}, "t_webhook_delivery.id = 7"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db, queries := openDryRunDB(t)
+
+			spec, err := queryspec.Parse(url.Values{"filter[id]": {"7"}}, tt.fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := tt.count(db, spec); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(*queries) != 1 || !strings.Contains((*queries)[0], tt.want) {
+				t.Errorf("queries = %q, want a count filtered by %s", *queries, tt.want)
+			}
+		})
+	}
+}
+
+// openDryRunDB returns a Postgres dialect that runs nothing and the
+// statements it would have run, with their values inlined.
+func openDryRunDB(t *testing.T) (*gorm.DB, *[]string) {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var queries []string
+	err = db.Callback().Query().After("gorm:query").Register("test:record", func(tx *gorm.DB) {
+		query := tx.Dialector.Explain(tx.Statement.SQL.String(), tx.Statement.Vars...)
+		queries = append(queries, query)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db, &queries
+}
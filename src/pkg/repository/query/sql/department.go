@@ -1,8 +1,11 @@
 package sql
 
 import (
+	"database/sql"
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/tenant"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
 
@@ -17,8 +20,8 @@ type departmentRepository struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 	unscoped     bool
 }
 
@@ -77,15 +80,15 @@ func (repo *departmentRepository) WithOrder(order string) adapter.DepartmentRepo
 	return clone
 }
 
-func (repo *departmentRepository) WithLimit(limit int) adapter.DepartmentRepository {
+func (repo *departmentRepository) WithSpec(spec queryspec.Spec) adapter.DepartmentRepository {
 	clone := repo.clone()
-	clone.limit = &limit
+	clone.spec = spec
 	return clone
 }
 
-func (repo *departmentRepository) WithCursor(cursor int) adapter.DepartmentRepository {
+func (repo *departmentRepository) WithLimit(limit int) adapter.DepartmentRepository {
 	clone := repo.clone()
-	clone.cursor = &cursor
+	clone.limit = &limit
 	return clone
 }
 
@@ -108,7 +111,8 @@ func (repo *departmentRepository) getQueryBuilder() *builder.QueryBuilder[models
 		WithAssociations(repo.associations...).
 		WithReplacements(repo.replacements).
 		WithJoins(repo.joins...).
-		WithOrder(repo.order)
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
 
 	for _, w := range repo.whereClauses {
 		qb = qb.WithWhere(w)
@@ -116,9 +120,6 @@ func (repo *departmentRepository) getQueryBuilder() *builder.QueryBuilder[models
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -159,6 +160,10 @@ func (repo *departmentRepository) FindDepartment() ([]models.Department, error)
 	return repo.getQueryBuilder().FindAll()
 }
 
+func (repo *departmentRepository) FindDepartmentPage() ([]models.Department, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
 func (repo *departmentRepository) FindDepartmentByID(id int64) (*models.Department, error) {
 	return repo.getQueryBuilder().FindByID(id)
 }
@@ -172,3 +177,78 @@ func (repo *departmentRepository) FindDepartmentsByIDs(ids []int64) ([]*models.D
 		return db.Where("id IN ?", ids)
 	}).FindAllPtr()
 }
+
+func (repo *departmentRepository) CountDepartments() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
+func (repo *departmentRepository) CountDepartmentChildren(id int64) (int64, error) {
+	var count int64
+
+	err := repo.db.
+		Model(&models.Department{}).
+		Where("parent_id = ?", id).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// FindDepartmentSubtreeIDs returns id and the ids of every department below it.
+func (repo *departmentRepository) FindDepartmentSubtreeIDs(id int64) ([]int64, error) {
+	var ids []int64
+
+	err := repo.db.
+		Raw(tenant.DepartmentSubtreeSQL, sql.Named("department_id", id)).
+		Scan(&ids).
+		Error
+
+	return ids, err
+}
+
+func (repo *departmentRepository) SummarizeDepartments(ids []int64) (*adapter.DepartmentSummary, error) {
+	summary := &adapter.DepartmentSummary{}
+	if len(ids) == 0 {
+		return summary, nil
+	}
+
+	err := repo.db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE department_id IN ? AND deleted_at IS NULL) AS members,
+			(SELECT COUNT(*) FROM m_class WHERE department_id IN ? AND deleted_at IS NULL) AS classes,
+			(SELECT COUNT(*) FROM users u
+				JOIN m_class c ON c.id = u.class_id
+				WHERE c.department_id IN ? AND c.deleted_at IS NULL AND u.deleted_at IS NULL) AS students
+	`, ids, ids, ids).Scan(summary).Error
+
+	if err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+// DetachDepartment unlinks users and classes from a department that is about
+// to be removed.
+func (repo *departmentRepository) DetachDepartment(id int64) error {
+	if err := repo.db.
+		Model(&models.User{}).
+		Where("department_id = ?", id).
+		Update("department_id", nil).
+		Error; err != nil {
+		return err
+	}
+
+	if err := repo.db.
+		Model(&models.User{}).
+		Where("scope_department_id = ?", id).
+		Update("scope_department_id", nil).
+		Error; err != nil {
+		return err
+	}
+
+	return repo.db.
+		Model(&models.MClass{}).
+		Where("department_id = ?", id).
+		Update("department_id", nil).
+		Error
+}
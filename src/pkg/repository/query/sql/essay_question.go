@@ -18,7 +18,6 @@ type essayQuestionRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewEssayQuestionRepository() adapter.EssayQuestionRepository {
@@ -82,12 +81,6 @@ func (repo *essayQuestionRepository) WithLimit(limit int) adapter.EssayQuestionR
 	return clone
 }
 
-func (repo *essayQuestionRepository) WithCursor(cursor int) adapter.EssayQuestionRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *essayQuestionRepository) getQueryBuilder() *builder.QueryBuilder[models.EssayQuestion] {
@@ -104,9 +97,6 @@ func (repo *essayQuestionRepository) getQueryBuilder() *builder.QueryBuilder[mod
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
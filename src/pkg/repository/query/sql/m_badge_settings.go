@@ -18,7 +18,6 @@ type mBadgeSettingsRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewMBadgeSettingsRepository() adapter.MBadgeSettingsRepository {
@@ -82,12 +81,6 @@ func (repo *mBadgeSettingsRepository) WithLimit(limit int) adapter.MBadgeSetting
 	return clone
 }
 
-func (repo *mBadgeSettingsRepository) WithCursor(cursor int) adapter.MBadgeSettingsRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *mBadgeSettingsRepository) getQueryBuilder() *builder.QueryBuilder[models.MBadgeSettings] {
@@ -104,9 +97,6 @@ func (repo *mBadgeSettingsRepository) getQueryBuilder() *builder.QueryBuilder[mo
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
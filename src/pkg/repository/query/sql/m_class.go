@@ -18,7 +18,6 @@ type mClassRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewMClassRepository() adapter.MClassRepository {
@@ -82,12 +81,6 @@ func (repo *mClassRepository) WithLimit(limit int) adapter.MClassRepository {
 	return clone
 }
 
-func (repo *mClassRepository) WithCursor(cursor int) adapter.MClassRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *mClassRepository) getQueryBuilder() *builder.QueryBuilder[models.MClass] {
@@ -104,9 +97,6 @@ func (repo *mClassRepository) getQueryBuilder() *builder.QueryBuilder[models.MCl
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -3,6 +3,7 @@ package sql
 import (
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
 
@@ -17,8 +18,8 @@ type mCourseRepository struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 }
 
 func NewMCourseRepository() adapter.MCourseRepository {
@@ -76,15 +77,15 @@ func (repo *mCourseRepository) WithOrder(order string) adapter.MCourseRepository
 	return clone
 }
 
-func (repo *mCourseRepository) WithLimit(limit int) adapter.MCourseRepository {
+func (repo *mCourseRepository) WithSpec(spec queryspec.Spec) adapter.MCourseRepository {
 	clone := repo.clone()
-	clone.limit = &limit
+	clone.spec = spec
 	return clone
 }
 
-func (repo *mCourseRepository) WithCursor(cursor int) adapter.MCourseRepository {
+func (repo *mCourseRepository) WithLimit(limit int) adapter.MCourseRepository {
 	clone := repo.clone()
-	clone.cursor = &cursor
+	clone.limit = &limit
 	return clone
 }
 
@@ -96,7 +97,8 @@ func (repo *mCourseRepository) getQueryBuilder() *builder.QueryBuilder[models.MC
 		WithAssociations(repo.associations...).
 		WithReplacements(repo.replacements).
 		WithJoins(repo.joins...).
-		WithOrder(repo.order)
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
 
 	for _, w := range repo.whereClauses {
 		qb = qb.WithWhere(w)
@@ -104,9 +106,6 @@ func (repo *mCourseRepository) getQueryBuilder() *builder.QueryBuilder[models.MC
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -142,6 +141,33 @@ func (repo *mCourseRepository) FindMCourse() ([]models.MCourse, error) {
 	return repo.getQueryBuilder().FindAll()
 }
 
+func (repo *mCourseRepository) FindMCoursePage() ([]models.MCourse, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *mCourseRepository) CountMCourses() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
 func (repo *mCourseRepository) FindMCourseByID(id int64) (*models.MCourse, error) {
 	return repo.getQueryBuilder().FindByID(id)
 }
+
+// FindCourseTree loads a course with everything a deep copy or export needs:
+// lessons with their level, sub-lessons, materials and code questions with
+// their essay questions.
+func (repo *mCourseRepository) FindCourseTree(id int64) (*models.MCourse, error) {
+	return repo.getQueryBuilder().
+		WithPreloads(
+			"Lessons.Level",
+			"Lessons.SubLessons.Materials",
+			"Lessons.SubLessons.CodeQuestions.EssayQuestions",
+		).
+		FindByID(id)
+}
+
+func (repo *mCourseRepository) CountLevelsByIDs(ids []int64) (int64, error) {
+	var count int64
+	err := repo.db.Model(&models.MLevel{}).Where("id IN ?", ids).Count(&count).Error
+	return count, err
+}
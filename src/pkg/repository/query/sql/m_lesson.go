@@ -3,6 +3,7 @@ package sql
 import (
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
 
@@ -17,8 +18,8 @@ type mLessonRepository struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 	unscoped     bool
 }
 
@@ -77,15 +78,15 @@ func (repo *mLessonRepository) WithOrder(order string) adapter.MLessonRepository
 	return clone
 }
 
-func (repo *mLessonRepository) WithLimit(limit int) adapter.MLessonRepository {
+func (repo *mLessonRepository) WithSpec(spec queryspec.Spec) adapter.MLessonRepository {
 	clone := repo.clone()
-	clone.limit = &limit
+	clone.spec = spec
 	return clone
 }
 
-func (repo *mLessonRepository) WithCursor(cursor int) adapter.MLessonRepository {
+func (repo *mLessonRepository) WithLimit(limit int) adapter.MLessonRepository {
 	clone := repo.clone()
-	clone.cursor = &cursor
+	clone.limit = &limit
 	return clone
 }
 
@@ -108,7 +109,8 @@ func (repo *mLessonRepository) getQueryBuilder() *builder.QueryBuilder[models.ML
 		WithAssociations(repo.associations...).
 		WithReplacements(repo.replacements).
 		WithJoins(repo.joins...).
-		WithOrder(repo.order)
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
 
 	for _, w := range repo.whereClauses {
 		qb = qb.WithWhere(w)
@@ -116,9 +118,6 @@ func (repo *mLessonRepository) getQueryBuilder() *builder.QueryBuilder[models.ML
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -159,6 +158,14 @@ func (repo *mLessonRepository) FindMLesson() ([]models.MLesson, error) {
 	return repo.getQueryBuilder().FindAll()
 }
 
+func (repo *mLessonRepository) FindMLessonPage() ([]models.MLesson, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *mLessonRepository) CountMLessons() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
 func (repo *mLessonRepository) FindMLessonByID(id int64) (*models.MLesson, error) {
 	return repo.getQueryBuilder().FindByID(id)
 }
@@ -172,3 +179,19 @@ func (repo *mLessonRepository) FindMLessonsByIDs(ids []int64) ([]*models.MLesson
 		return db.Where("id IN ?", ids)
 	}).FindAllPtr()
 }
+
+func (repo *mLessonRepository) FindCourseIDsByLessonIDs(ids []int64) ([]int64, error) {
+	var courseIDs []int64
+	if len(ids) == 0 {
+		return courseIDs, nil
+	}
+
+	err := repo.db.
+		Model(&models.MLesson{}).
+		Where("id IN ?", ids).
+		Distinct().
+		Pluck("course_id", &courseIDs).
+		Error
+
+	return courseIDs, err
+}
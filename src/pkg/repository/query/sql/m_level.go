@@ -18,7 +18,6 @@ type mLevelRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewMLevelRepository() adapter.MLevelRepository {
@@ -82,12 +81,6 @@ func (repo *mLevelRepository) WithLimit(limit int) adapter.MLevelRepository {
 	return clone
 }
 
-func (repo *mLevelRepository) WithCursor(cursor int) adapter.MLevelRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *mLevelRepository) getQueryBuilder() *builder.QueryBuilder[models.MLevel] {
@@ -104,9 +97,6 @@ func (repo *mLevelRepository) getQueryBuilder() *builder.QueryBuilder[models.MLe
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -3,6 +3,7 @@ package sql
 import (
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
 
@@ -17,8 +18,8 @@ type mMaterialRepository struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 	unscoped     bool
 }
 
@@ -77,15 +78,15 @@ func (repo *mMaterialRepository) WithOrder(order string) adapter.MMaterialReposi
 	return clone
 }
 
-func (repo *mMaterialRepository) WithLimit(limit int) adapter.MMaterialRepository {
+func (repo *mMaterialRepository) WithSpec(spec queryspec.Spec) adapter.MMaterialRepository {
 	clone := repo.clone()
-	clone.limit = &limit
+	clone.spec = spec
 	return clone
 }
 
-func (repo *mMaterialRepository) WithCursor(cursor int) adapter.MMaterialRepository {
+func (repo *mMaterialRepository) WithLimit(limit int) adapter.MMaterialRepository {
 	clone := repo.clone()
-	clone.cursor = &cursor
+	clone.limit = &limit
 	return clone
 }
 
@@ -108,7 +109,8 @@ func (repo *mMaterialRepository) getQueryBuilder() *builder.QueryBuilder[models.
 		WithAssociations(repo.associations...).
 		WithReplacements(repo.replacements).
 		WithJoins(repo.joins...).
-		WithOrder(repo.order)
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
 
 	for _, w := range repo.whereClauses {
 		qb = qb.WithWhere(w)
@@ -116,9 +118,6 @@ func (repo *mMaterialRepository) getQueryBuilder() *builder.QueryBuilder[models.
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -159,6 +158,14 @@ func (repo *mMaterialRepository) FindMMaterials() ([]models.MMaterials, error) {
 	return repo.getQueryBuilder().FindAll()
 }
 
+func (repo *mMaterialRepository) FindMMaterialPage() ([]models.MMaterials, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *mMaterialRepository) CountMMaterials() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
 func (repo *mMaterialRepository) FindMMaterialByID(id int64) (*models.MMaterials, error) {
 	return repo.getQueryBuilder().FindByID(id)
 }
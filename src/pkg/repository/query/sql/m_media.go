@@ -0,0 +1,147 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/database/models"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mMediaRepository struct {
+	db *gorm.DB
+}
+
+func NewMMediaRepository() adapter.MMediaRepository {
+	return &mMediaRepository{db: config.DB}
+}
+
+func (repo *mMediaRepository) WithTx(tx *gorm.DB) adapter.MMediaRepository {
+	return &mMediaRepository{db: tx}
+}
+
+func (repo *mMediaRepository) InsertMedia(data *models.MMedia) (*models.MMedia, error) {
+	if err := builder.NewQueryBuilder[models.MMedia](repo.db).Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mMediaRepository) FindMediaByID(id int64) (*models.MMedia, error) {
+	return builder.NewQueryBuilder[models.MMedia](repo.db).FindByID(id)
+}
+
+// FindMediaByHash returns nil when no media with hash and protection exists
+// yet.
+func (repo *mMediaRepository) FindMediaByHash(hash string, protected bool) (*models.MMedia, error) {
+	var data models.MMedia
+
+	err := repo.db.Where("hash = ? AND protected = ?", hash, protected).First(&data).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *mMediaRepository) InsertUpload(data *models.TMediaUpload) (*models.TMediaUpload, error) {
+	if err := builder.NewQueryBuilder[models.TMediaUpload](repo.db).Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mMediaRepository) FindUploadByID(id string) (*models.TMediaUpload, error) {
+	var data models.TMediaUpload
+
+	err := repo.db.Preload("Media").Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// LockUpload serialises chunks sent to the same upload.
+func (repo *mMediaRepository) LockUpload(id string) (*models.TMediaUpload, error) {
+	var data models.TMediaUpload
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ?", id).
+		First(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *mMediaRepository) UpdateUpload(id string, updates map[string]interface{}) error {
+	return repo.db.Model(&models.TMediaUpload{}).Where("id = ?", id).Updates(updates).Error
+}
+
+func (repo *mMediaRepository) FindExpiredUploads(before time.Time, limit int) ([]models.TMediaUpload, error) {
+	var data []models.TMediaUpload
+
+	err := repo.db.
+		Where("expires_at < ?", before).
+		Order("expires_at ASC").
+		Limit(limit).
+		Find(&data).
+		Error
+
+	return data, err
+}
+
+func (repo *mMediaRepository) RemoveUpload(id string) error {
+	return repo.db.Where("id = ?", id).Delete(&models.TMediaUpload{}).Error
+}
+
+// FindMaterialWithCourse loads a material with the lesson and course it
+// belongs to, regardless of the school in the request scope.
+func (repo *mMediaRepository) FindMaterialWithCourse(id int64) (*models.MMaterials, error) {
+	var data models.MMaterials
+
+	err := repo.db.Preload("SubLesson.Lesson.Course").Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindCodeQuestionWithCourse loads a code question with the lesson and
+// course it belongs to, regardless of the school in the request scope.
+func (repo *mMediaRepository) FindCodeQuestionWithCourse(id int64) (*models.CodeQuestion, error) {
+	var data models.CodeQuestion
+
+	err := repo.db.Preload("SubLesson.Lesson.Course").Where("id = ?", id).First(&data).Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindVisibleCourse returns the course if the school in the request scope
+// can see it.
+func (repo *mMediaRepository) FindVisibleCourse(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(id)
+}
+
+func (repo *mMediaRepository) HasActiveEnrollment(userID int64, courseID int64) (bool, error) {
+	var count int64
+
+	err := repo.db.
+		Model(&models.TStudentCourse{}).
+		Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userID, courseID).
+		Count(&count).
+		Error
+
+	return count > 0, err
+}
@@ -0,0 +1,214 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mPromptTemplateRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewMPromptTemplateRepository() adapter.MPromptTemplateRepository {
+	return &mPromptTemplateRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *mPromptTemplateRepository) clone() *mPromptTemplateRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *mPromptTemplateRepository) WithTx(tx *gorm.DB) adapter.MPromptTemplateRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *mPromptTemplateRepository) WithWhere(query interface{}, args ...interface{}) adapter.MPromptTemplateRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *mPromptTemplateRepository) WithOrder(order string) adapter.MPromptTemplateRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *mPromptTemplateRepository) WithSpec(spec queryspec.Spec) adapter.MPromptTemplateRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *mPromptTemplateRepository) WithLimit(limit int) adapter.MPromptTemplateRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *mPromptTemplateRepository) queryBuilder(paginate bool) *builder.QueryBuilder[models.MPromptTemplate] {
+	qb := builder.NewQueryBuilder[models.MPromptTemplate](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 Templates ---
+
+// InsertPromptTemplate stamps the template with the caller's school.
+func (repo *mPromptTemplateRepository) InsertPromptTemplate(data *models.MPromptTemplate) (*models.MPromptTemplate, error) {
+	if err := builder.NewQueryBuilder[models.MPromptTemplate](repo.db).Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// UpdatePromptTemplate goes through the query builder so shared templates
+// can only be changed by platform admins.
+func (repo *mPromptTemplateRepository) UpdatePromptTemplate(id int64, updates map[string]interface{}) (*models.MPromptTemplate, error) {
+	return builder.NewQueryBuilder[models.MPromptTemplate](repo.db).UpdateByID(id, updates)
+}
+
+// FindPromptTemplateByID only finds shared templates and those of the
+// caller's school.
+func (repo *mPromptTemplateRepository) FindPromptTemplateByID(id int64) (*models.MPromptTemplate, error) {
+	return builder.NewQueryBuilder[models.MPromptTemplate](repo.db).FindByID(id)
+}
+
+// FindDefaultPromptTemplate prefers the default of the caller's school over
+// the shared one and returns nil when there is neither.
+func (repo *mPromptTemplateRepository) FindDefaultPromptTemplate(kind string) (*models.MPromptTemplate, error) {
+	data, err := builder.NewQueryBuilder[models.MPromptTemplate](repo.db).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("m_prompt_template.kind = ? AND m_prompt_template.is_default AND m_prompt_template.isactive", kind)
+		}).
+		WithOrder("m_prompt_template.school_id IS NULL, m_prompt_template.id").
+		FindFirst()
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return data, err
+}
+
+func (repo *mPromptTemplateRepository) FindPromptTemplatePage() ([]models.MPromptTemplate, queryspec.Page, error) {
+	return repo.queryBuilder(true).FindPage()
+}
+
+func (repo *mPromptTemplateRepository) CountPromptTemplates() (int64, error) {
+	return repo.queryBuilder(false).Count()
+}
+
+// ClearDefaultPromptTemplates unmarks the other defaults of kind owned by
+// the same school, or the other shared ones when schoolID is nil.
+func (repo *mPromptTemplateRepository) ClearDefaultPromptTemplates(kind string, schoolID *int64, exceptID int64) error {
+	query := repo.db.
+		Model(&models.MPromptTemplate{}).
+		Where("kind = ? AND is_default AND id <> ?", kind, exceptID)
+
+	if schoolID == nil {
+		query = query.Where("school_id IS NULL")
+	} else {
+		query = query.Where("school_id = ?", *schoolID)
+	}
+	return query.Update("is_default", false).Error
+}
+
+// --- 🔧 Versions ---
+
+func (repo *mPromptTemplateRepository) InsertPromptTemplateVersion(data *models.TPromptTemplateVersion) (*models.TPromptTemplateVersion, error) {
+	if err := repo.db.Omit(clause.Associations).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mPromptTemplateRepository) FindPromptTemplateVersion(templateID int64, version int) (*models.TPromptTemplateVersion, error) {
+	var data models.TPromptTemplateVersion
+
+	err := repo.db.
+		Where("template_id = ? AND version = ?", templateID, version).
+		First(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *mPromptTemplateRepository) FindPromptTemplateVersionByID(id int64) (*models.TPromptTemplateVersion, error) {
+	return builder.NewQueryBuilder[models.TPromptTemplateVersion](repo.db).FindByID(id)
+}
+
+func (repo *mPromptTemplateRepository) FindPromptTemplateVersionPage(templateID int64, spec queryspec.Spec) ([]models.TPromptTemplateVersion, queryspec.Page, error) {
+	return repo.versionQuery(templateID, spec).FindPage()
+}
+
+func (repo *mPromptTemplateRepository) CountPromptTemplateVersions(templateID int64, spec queryspec.Spec) (int64, error) {
+	return repo.versionQuery(templateID, spec).Count()
+}
+
+func (repo *mPromptTemplateRepository) versionQuery(templateID int64, spec queryspec.Spec) *builder.QueryBuilder[models.TPromptTemplateVersion] {
+	return builder.NewQueryBuilder[models.TPromptTemplateVersion](repo.db).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("t_prompt_template_version.template_id = ?", templateID)
+		}).
+		WithSpec(spec)
+}
+
+// --- 🔧 Evaluations ---
+
+func (repo *mPromptTemplateRepository) InsertPromptEvaluation(data *models.TPromptEvaluation) (*models.TPromptEvaluation, error) {
+	if err := repo.db.Omit(clause.Associations).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mPromptTemplateRepository) FindPromptEvaluationPage(templateID int64, spec queryspec.Spec) ([]models.TPromptEvaluation, queryspec.Page, error) {
+	return repo.evaluationQuery(templateID, spec).FindPage()
+}
+
+func (repo *mPromptTemplateRepository) CountPromptEvaluations(templateID int64, spec queryspec.Spec) (int64, error) {
+	return repo.evaluationQuery(templateID, spec).Count()
+}
+
+func (repo *mPromptTemplateRepository) evaluationQuery(templateID int64, spec queryspec.Spec) *builder.QueryBuilder[models.TPromptEvaluation] {
+	return builder.NewQueryBuilder[models.TPromptEvaluation](repo.db).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("t_prompt_evaluation.template_id = ?", templateID)
+		}).
+		WithPreloads("Version").
+		WithSpec(spec)
+}
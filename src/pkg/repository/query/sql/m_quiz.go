@@ -0,0 +1,460 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type mQuizRepository struct {
+	db            *gorm.DB
+	whereClauses  []func(*gorm.DB) *gorm.DB
+	order         string
+	spec          queryspec.Spec
+	limit         *int
+	publishedOnly bool
+}
+
+func NewMQuizRepository() adapter.MQuizRepository {
+	return &mQuizRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *mQuizRepository) clone() *mQuizRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *mQuizRepository) WithTx(tx *gorm.DB) adapter.MQuizRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *mQuizRepository) WithWhere(query interface{}, args ...interface{}) adapter.MQuizRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *mQuizRepository) WithOrder(order string) adapter.MQuizRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *mQuizRepository) WithSpec(spec queryspec.Spec) adapter.MQuizRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *mQuizRepository) WithLimit(limit int) adapter.MQuizRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// WithPublishedOnly limits quiz lookups to published quizzes of published
+// lessons and courses, the ones students see.
+func (repo *mQuizRepository) WithPublishedOnly() adapter.MQuizRepository {
+	clone := repo.clone()
+	clone.publishedOnly = true
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+// quizQueryBuilder only finds quizzes of courses the caller can see: public
+// courses and the private courses of their school.
+func (repo *mQuizRepository) quizQueryBuilder(paginate bool) *builder.QueryBuilder[models.MQuiz] {
+	courses := builder.NewQueryBuilder[models.MCourse](repo.db)
+	if repo.publishedOnly {
+		courses = courses.WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("m_course.status = ?", constant.ContentPublished)
+		})
+	}
+
+	subLessons := repo.db.
+		Table("m_sub_lesson").
+		Select("m_sub_lesson.id").
+		Joins("JOIN m_lesson ON m_lesson.id = m_sub_lesson.lesson_id").
+		Where("m_lesson.course_id IN (?)", courses.Query().Select("m_course.id"))
+	if repo.publishedOnly {
+		subLessons = subLessons.Where("m_lesson.status = ?", constant.ContentPublished)
+	}
+
+	qb := builder.NewQueryBuilder[models.MQuiz](repo.db).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("m_quiz.sub_lesson_id IN (?)", subLessons)
+		})
+	if repo.publishedOnly {
+		qb = qb.WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("m_quiz.status = ?", constant.ContentPublished)
+		})
+	}
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+func (repo *mQuizRepository) attemptQueryBuilder(paginate bool) *builder.QueryBuilder[models.TQuizAttempt] {
+	qb := builder.NewQueryBuilder[models.TQuizAttempt](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// submittedAttempts selects the submitted attempts at a quiz by students of
+// the caller's school.
+func (repo *mQuizRepository) submittedAttempts(quizID int64) *gorm.DB {
+	return builder.NewQueryBuilder[models.TQuizAttempt](repo.db).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("t_quiz_attempt.quiz_id = ? AND t_quiz_attempt.status = ?", quizID, constant.QuizAttemptSubmitted)
+		}).
+		Query().
+		Select("t_quiz_attempt.id")
+}
+
+// --- 🔧 Quizzes ---
+
+func (repo *mQuizRepository) InsertQuiz(data *models.MQuiz) (*models.MQuiz, error) {
+	if err := repo.db.Omit(clause.Associations).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mQuizRepository) UpdateQuiz(id int64, updates map[string]interface{}) (*models.MQuiz, error) {
+	if err := repo.db.Model(&models.MQuiz{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+		return nil, err
+	}
+	return repo.FindQuizByID(id)
+}
+
+func (repo *mQuizRepository) RemoveQuiz(id int64) error {
+	return repo.db.Delete(&models.MQuiz{}, id).Error
+}
+
+func (repo *mQuizRepository) FindQuizByID(id int64) (*models.MQuiz, error) {
+	return repo.quizQueryBuilder(false).FindByID(id)
+}
+
+func (repo *mQuizRepository) FindQuizPage() ([]models.MQuiz, queryspec.Page, error) {
+	return repo.quizQueryBuilder(true).FindPage()
+}
+
+func (repo *mQuizRepository) CountQuizzes() (int64, error) {
+	return repo.quizQueryBuilder(false).Count()
+}
+
+// --- 🔧 Items ---
+
+func (repo *mQuizRepository) InsertQuizItem(data *models.MQuizItem) (*models.MQuizItem, error) {
+	if err := repo.db.Omit(clause.Associations).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mQuizRepository) SaveQuizItem(data *models.MQuizItem) (*models.MQuizItem, error) {
+	if err := repo.db.Omit(clause.Associations).Save(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mQuizRepository) RemoveQuizItem(quizID int64, id int64) error {
+	result := repo.db.Where("quiz_id = ?", quizID).Delete(&models.MQuizItem{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+func (repo *mQuizRepository) FindQuizItem(quizID int64, id int64) (*models.MQuizItem, error) {
+	var data models.MQuizItem
+	if err := repo.db.Where("quiz_id = ?", quizID).First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *mQuizRepository) FindQuizItems(quizID int64) ([]models.MQuizItem, error) {
+	var data []models.MQuizItem
+
+	err := repo.db.
+		Where("quiz_id = ?", quizID).
+		Order("position, id").
+		Find(&data).
+		Error
+
+	return data, err
+}
+
+// --- 🔧 Attempts ---
+
+// InsertAttempt stamps the attempt with the student's school.
+func (repo *mQuizRepository) InsertAttempt(data *models.TQuizAttempt) (*models.TQuizAttempt, error) {
+	if err := builder.NewQueryBuilder[models.TQuizAttempt](repo.db).Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mQuizRepository) UpdateAttempt(id int64, updates map[string]interface{}) error {
+	return repo.db.
+		Model(&models.TQuizAttempt{}).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+}
+
+// FindAttemptByID only finds attempts of the caller's school; the where
+// clauses narrow it down further, e.g. to the caller's own.
+func (repo *mQuizRepository) FindAttemptByID(id int64) (*models.TQuizAttempt, error) {
+	return repo.attemptQueryBuilder(false).FindByID(id)
+}
+
+// LockAttempt is FindAttemptByID holding the row until the transaction
+// ends, so an attempt is only graded once.
+func (repo *mQuizRepository) LockAttempt(id int64) (*models.TQuizAttempt, error) {
+	var data models.TQuizAttempt
+
+	err := repo.attemptQueryBuilder(false).
+		Query().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&data, id).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// LockUserAttempts holds every attempt of the student at the quiz until the
+// transaction ends, in id order, so their submissions are graded one after
+// the other against each other's scores.
+func (repo *mQuizRepository) LockUserAttempts(quizID int64, userID int64) error {
+	var ids []int64
+
+	return repo.db.
+		Model(&models.TQuizAttempt{}).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Order("id").
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Pluck("id", &ids).
+		Error
+}
+
+func (repo *mQuizRepository) FindAttemptPage() ([]models.TQuizAttempt, queryspec.Page, error) {
+	return repo.attemptQueryBuilder(true).FindPage()
+}
+
+func (repo *mQuizRepository) CountAttempts() (int64, error) {
+	return repo.attemptQueryBuilder(false).Count()
+}
+
+// FindInProgressAttempt returns the student's unsubmitted attempt at the
+// quiz, or nil when there is none.
+func (repo *mQuizRepository) FindInProgressAttempt(quizID int64, userID int64) (*models.TQuizAttempt, error) {
+	var data models.TQuizAttempt
+	err := repo.db.
+		Where("quiz_id = ? AND user_id = ? AND status = ?", quizID, userID, constant.QuizAttemptInProgress).
+		Order("number DESC").
+		First(&data).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *mQuizRepository) CountUserAttempts(quizID int64, userID int64) (int64, error) {
+	var count int64
+
+	err := repo.db.
+		Model(&models.TQuizAttempt{}).
+		Where("quiz_id = ? AND user_id = ?", quizID, userID).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// CountSubmittedAttempts counts submitted attempts at the quiz by students
+// of every school.
+func (repo *mQuizRepository) CountSubmittedAttempts(quizID int64) (int64, error) {
+	var count int64
+
+	err := repo.db.
+		Model(&models.TQuizAttempt{}).
+		Where("quiz_id = ? AND status = ?", quizID, constant.QuizAttemptSubmitted).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+// FindSubmittedScores returns the scores of the student's submitted
+// attempts at the quiz, oldest first.
+func (repo *mQuizRepository) FindSubmittedScores(quizID int64, userID int64) ([]int, error) {
+	var scores []int
+
+	err := repo.db.
+		Model(&models.TQuizAttempt{}).
+		Where("quiz_id = ? AND user_id = ? AND status = ?", quizID, userID, constant.QuizAttemptSubmitted).
+		Order("submitted_at, id").
+		Pluck("score", &scores).
+		Error
+
+	return scores, err
+}
+
+// --- 🔧 Answers ---
+
+func (repo *mQuizRepository) InsertAnswers(data []models.TQuizAnswer) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return repo.db.Omit(clause.Associations).Create(&data).Error
+}
+
+func (repo *mQuizRepository) FindAnswers(attemptID int64) ([]models.TQuizAnswer, error) {
+	var data []models.TQuizAnswer
+
+	err := repo.db.
+		Where("attempt_id = ?", attemptID).
+		Order("id").
+		Find(&data).
+		Error
+
+	return data, err
+}
+
+// --- 🔧 Analytics ---
+
+// FindAttemptSummary counts the submitted attempts of the caller's school
+// and the students who made them, along with their average score.
+func (repo *mQuizRepository) FindAttemptSummary(quizID int64) (adapter.QuizAttemptSummary, error) {
+	var data adapter.QuizAttemptSummary
+
+	err := repo.db.
+		Model(&models.TQuizAttempt{}).
+		Select("COUNT(*) AS attempts, COUNT(DISTINCT user_id) AS students, COALESCE(AVG(score), 0) AS average_score").
+		Where("id IN (?)", repo.submittedAttempts(quizID)).
+		Scan(&data).
+		Error
+
+	return data, err
+}
+
+// FindItemStats counts the answers, right answers and points awarded per
+// item over the submitted attempts of the caller's school.
+func (repo *mQuizRepository) FindItemStats(quizID int64) ([]adapter.QuizItemStat, error) {
+	var data []adapter.QuizItemStat
+
+	err := repo.db.
+		Model(&models.TQuizAnswer{}).
+		Select("item_id, COUNT(*) AS answers, COUNT(*) FILTER (WHERE is_correct) AS correct, COALESCE(SUM(points), 0) AS points").
+		Where("attempt_id IN (?)", repo.submittedAttempts(quizID)).
+		Group("item_id").
+		Scan(&data).
+		Error
+
+	return data, err
+}
+
+func (repo *mQuizRepository) FindOptionPicks(quizID int64) ([]adapter.QuizOptionPick, error) {
+	var data []adapter.QuizOptionPick
+
+	err := repo.db.
+		Table("t_quiz_answer").
+		Select("t_quiz_answer.item_id, picked.value::int AS option_id, COUNT(*) AS picks").
+		Joins("CROSS JOIN LATERAL jsonb_array_elements_text(COALESCE(t_quiz_answer.response->'option_ids', '[]'::jsonb)) AS picked(value)").
+		Where("t_quiz_answer.attempt_id IN (?)", repo.submittedAttempts(quizID)).
+		Group("t_quiz_answer.item_id, picked.value").
+		Scan(&data).
+		Error
+
+	return data, err
+}
+
+// FindWrongResponses returns, per item, the limit most common wrong numeric
+// and short answers, compared without case and surrounding spaces.
+func (repo *mQuizRepository) FindWrongResponses(quizID int64, limit int) ([]adapter.QuizWrongResponse, error) {
+	var data []adapter.QuizWrongResponse
+
+	responses := repo.db.
+		Table("t_quiz_answer").
+		Select(`item_id,
+			COALESCE(LOWER(TRIM(response->>'text')), response->>'number') AS response,
+			COUNT(*) AS count,
+			ROW_NUMBER() OVER (PARTITION BY item_id ORDER BY COUNT(*) DESC, COALESCE(LOWER(TRIM(response->>'text')), response->>'number')) AS rank`).
+		Where("attempt_id IN (?) AND NOT is_correct", repo.submittedAttempts(quizID)).
+		Where("COALESCE(TRIM(response->>'text'), response->>'number', '') <> ''").
+		Group("item_id, COALESCE(LOWER(TRIM(response->>'text')), response->>'number')")
+
+	err := repo.db.
+		Table("(?) AS wrong", responses).
+		Select("item_id, response, count").
+		Where("rank <= ?", limit).
+		Order("item_id, rank").
+		Scan(&data).
+		Error
+
+	return data, err
+}
+
+// --- 🔧 Course content ---
+
+// FindSubLessonByID loads the sub-lesson with its lesson.
+func (repo *mQuizRepository) FindSubLessonByID(id int64) (*models.MSubLesson, error) {
+	return builder.NewQueryBuilder[models.MSubLesson](repo.db).
+		WithPreloads("Lesson").
+		FindByID(id)
+}
+
+// FindCourseByID only finds public courses and the private courses of the
+// caller's school.
+func (repo *mQuizRepository) FindCourseByID(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(id)
+}
@@ -0,0 +1,138 @@
+package sql
+
+import (
+	"jk-api/internal/config"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+)
+
+type mSchoolRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewMSchoolRepository() adapter.MSchoolRepository {
+	return &mSchoolRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *mSchoolRepository) clone() *mSchoolRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *mSchoolRepository) WithTx(tx *gorm.DB) adapter.MSchoolRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *mSchoolRepository) WithWhere(query interface{}, args ...interface{}) adapter.MSchoolRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *mSchoolRepository) WithOrder(order string) adapter.MSchoolRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *mSchoolRepository) WithSpec(spec queryspec.Spec) adapter.MSchoolRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *mSchoolRepository) WithLimit(limit int) adapter.MSchoolRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *mSchoolRepository) getQueryBuilder() *builder.QueryBuilder[models.MSchool] {
+	qb := builder.NewQueryBuilder[models.MSchool](repo.db).
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 CRUD Methods ---
+
+func (repo *mSchoolRepository) InsertMSchool(data *models.MSchool) (*models.MSchool, error) {
+	if err := repo.getQueryBuilder().Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mSchoolRepository) UpdateMSchool(id int64, updates map[string]interface{}) (*models.MSchool, error) {
+	return repo.getQueryBuilder().UpdateByID(id, updates)
+}
+
+func (repo *mSchoolRepository) RemoveMSchool(id int64) error {
+	return repo.getQueryBuilder().Delete(id)
+}
+
+func (repo *mSchoolRepository) FindMSchools() ([]models.MSchool, error) {
+	return repo.getQueryBuilder().FindAll()
+}
+
+func (repo *mSchoolRepository) FindMSchoolPage() ([]models.MSchool, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *mSchoolRepository) FindMSchoolByID(id int64) (*models.MSchool, error) {
+	return repo.getQueryBuilder().FindByID(id)
+}
+
+func (repo *mSchoolRepository) FindMSchoolByCode(code string) (*models.MSchool, error) {
+	var data models.MSchool
+
+	err := repo.db.
+		Where("UPPER(code) = UPPER(?) AND is_active = ?", code, true).
+		First(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *mSchoolRepository) CountMSchools() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
+// CountMSchoolMembers counts the users and classes still owned by a school.
+func (repo *mSchoolRepository) CountMSchoolMembers(id int64) (int64, error) {
+	var count int64
+
+	err := repo.db.Raw(`
+		SELECT
+			(SELECT COUNT(*) FROM users WHERE school_id = ? AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM m_class WHERE school_id = ? AND deleted_at IS NULL)
+	`, id, id).Scan(&count).Error
+
+	return count, err
+}
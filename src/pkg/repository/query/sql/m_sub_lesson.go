@@ -3,6 +3,7 @@ package sql
 import (
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
 
@@ -17,8 +18,8 @@ type mSubLessonRepository struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 	unscoped     bool
 }
 
@@ -77,15 +78,15 @@ func (repo *mSubLessonRepository) WithOrder(order string) adapter.MSubLessonRepo
 	return clone
 }
 
-func (repo *mSubLessonRepository) WithLimit(limit int) adapter.MSubLessonRepository {
+func (repo *mSubLessonRepository) WithSpec(spec queryspec.Spec) adapter.MSubLessonRepository {
 	clone := repo.clone()
-	clone.limit = &limit
+	clone.spec = spec
 	return clone
 }
 
-func (repo *mSubLessonRepository) WithCursor(cursor int) adapter.MSubLessonRepository {
+func (repo *mSubLessonRepository) WithLimit(limit int) adapter.MSubLessonRepository {
 	clone := repo.clone()
-	clone.cursor = &cursor
+	clone.limit = &limit
 	return clone
 }
 
@@ -108,7 +109,8 @@ func (repo *mSubLessonRepository) getQueryBuilder() *builder.QueryBuilder[models
 		WithAssociations(repo.associations...).
 		WithReplacements(repo.replacements).
 		WithJoins(repo.joins...).
-		WithOrder(repo.order)
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
 
 	for _, w := range repo.whereClauses {
 		qb = qb.WithWhere(w)
@@ -116,9 +118,6 @@ func (repo *mSubLessonRepository) getQueryBuilder() *builder.QueryBuilder[models
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -159,6 +158,14 @@ func (repo *mSubLessonRepository) FindMSubLessons() ([]models.MSubLesson, error)
 	return repo.getQueryBuilder().FindAll()
 }
 
+func (repo *mSubLessonRepository) FindMSubLessonPage() ([]models.MSubLesson, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *mSubLessonRepository) CountMSubLessons() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
 func (repo *mSubLessonRepository) FindMSubLessonByID(id int64) (*models.MSubLesson, error) {
 	return repo.getQueryBuilder().FindByID(id)
 }
@@ -172,3 +179,36 @@ func (repo *mSubLessonRepository) FindMSubLessonsByIDs(ids []int64) ([]*models.M
 		return db.Where("id IN ?", ids)
 	}).FindAllPtr()
 }
+
+func (repo *mSubLessonRepository) FindCourseIDsBySubLessonIDs(ids []int64) ([]int64, error) {
+	var courseIDs []int64
+	if len(ids) == 0 {
+		return courseIDs, nil
+	}
+
+	err := repo.db.
+		Table("m_sub_lesson").
+		Joins("JOIN m_lesson ON m_lesson.id = m_sub_lesson.lesson_id").
+		Where("m_sub_lesson.id IN ?", ids).
+		Distinct().
+		Pluck("m_lesson.course_id", &courseIDs).
+		Error
+
+	return courseIDs, err
+}
+
+func (repo *mSubLessonRepository) FindCourseIDsByLessonIDs(lessonIDs []int64) ([]int64, error) {
+	var courseIDs []int64
+	if len(lessonIDs) == 0 {
+		return courseIDs, nil
+	}
+
+	err := repo.db.
+		Table("m_lesson").
+		Where("id IN ?", lessonIDs).
+		Distinct().
+		Pluck("course_id", &courseIDs).
+		Error
+
+	return courseIDs, err
+}
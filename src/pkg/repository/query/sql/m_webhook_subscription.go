@@ -0,0 +1,127 @@
+package sql
+
+import (
+	"jk-api/internal/config"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+)
+
+type mWebhookSubscriptionRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewMWebhookSubscriptionRepository() adapter.MWebhookSubscriptionRepository {
+	return &mWebhookSubscriptionRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *mWebhookSubscriptionRepository) clone() *mWebhookSubscriptionRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *mWebhookSubscriptionRepository) WithTx(tx *gorm.DB) adapter.MWebhookSubscriptionRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *mWebhookSubscriptionRepository) WithWhere(query interface{}, args ...interface{}) adapter.MWebhookSubscriptionRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *mWebhookSubscriptionRepository) WithOrder(order string) adapter.MWebhookSubscriptionRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *mWebhookSubscriptionRepository) WithSpec(spec queryspec.Spec) adapter.MWebhookSubscriptionRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *mWebhookSubscriptionRepository) WithLimit(limit int) adapter.MWebhookSubscriptionRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *mWebhookSubscriptionRepository) getQueryBuilder() *builder.QueryBuilder[models.MWebhookSubscription] {
+	qb := builder.NewQueryBuilder[models.MWebhookSubscription](repo.db).
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 CRUD Methods ---
+
+func (repo *mWebhookSubscriptionRepository) InsertMWebhookSubscription(data *models.MWebhookSubscription) (*models.MWebhookSubscription, error) {
+	if err := repo.getQueryBuilder().Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *mWebhookSubscriptionRepository) UpdateMWebhookSubscription(id int64, updates map[string]interface{}) (*models.MWebhookSubscription, error) {
+	return repo.getQueryBuilder().UpdateByID(id, updates)
+}
+
+func (repo *mWebhookSubscriptionRepository) RemoveMWebhookSubscription(id int64) error {
+	return repo.getQueryBuilder().Delete(id)
+}
+
+func (repo *mWebhookSubscriptionRepository) FindMWebhookSubscriptions() ([]models.MWebhookSubscription, error) {
+	return repo.getQueryBuilder().FindAll()
+}
+
+func (repo *mWebhookSubscriptionRepository) FindMWebhookSubscriptionPage() ([]models.MWebhookSubscription, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *mWebhookSubscriptionRepository) FindMWebhookSubscriptionByID(id int64) (*models.MWebhookSubscription, error) {
+	return repo.getQueryBuilder().FindByID(id)
+}
+
+func (repo *mWebhookSubscriptionRepository) FindActiveMWebhookSubscriptions() ([]models.MWebhookSubscription, error) {
+	var rows []models.MWebhookSubscription
+
+	err := repo.db.
+		Where("is_active = ?", true).
+		Order("id ASC").
+		Find(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (repo *mWebhookSubscriptionRepository) CountMWebhookSubscriptions() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
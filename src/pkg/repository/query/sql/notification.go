@@ -0,0 +1,260 @@
+package sql
+
+import (
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type notificationRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewNotificationRepository() adapter.NotificationRepository {
+	return &notificationRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *notificationRepository) clone() *notificationRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *notificationRepository) WithTx(tx *gorm.DB) adapter.NotificationRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *notificationRepository) WithWhere(query interface{}, args ...interface{}) adapter.NotificationRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *notificationRepository) WithOrder(order string) adapter.NotificationRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *notificationRepository) WithSpec(spec queryspec.Spec) adapter.NotificationRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *notificationRepository) WithLimit(limit int) adapter.NotificationRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *notificationRepository) getQueryBuilder() *builder.QueryBuilder[models.Notification] {
+	qb := builder.NewQueryBuilder[models.Notification](repo.db).
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 CRUD Methods ---
+
+// InsertNotificationIgnoreDuplicate reports false when a notification with the
+// same user and dedupe key already exists.
+func (repo *notificationRepository) InsertNotificationIgnoreDuplicate(data *models.Notification) (bool, error) {
+	result := repo.db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(data)
+
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
+
+func (repo *notificationRepository) UpdateNotificationReadAt(userID int64, id int64, readAt *time.Time) (*models.Notification, error) {
+	var data models.Notification
+
+	err := repo.db.
+		Where("id = ? AND user_id = ?", id, userID).
+		First(&data).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	err = repo.db.
+		Model(&data).
+		Updates(map[string]interface{}{
+			"read_at":    readAt,
+			"updated_at": time.Now(),
+		}).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &data, nil
+}
+
+func (repo *notificationRepository) MarkAllNotificationsRead(userID int64, readAt time.Time) (int64, error) {
+	result := repo.db.
+		Model(&models.Notification{}).
+		Where("user_id = ? AND read_at IS NULL", userID).
+		Updates(map[string]interface{}{
+			"read_at":    readAt,
+			"updated_at": time.Now(),
+		})
+
+	return result.RowsAffected, result.Error
+}
+
+func (repo *notificationRepository) FindNotifications() ([]models.Notification, error) {
+	return repo.getQueryBuilder().FindAll()
+}
+
+func (repo *notificationRepository) FindNotificationPage() ([]models.Notification, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *notificationRepository) CountNotifications() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
+func (repo *notificationRepository) FindPreferencesByUser(userID int64) ([]models.MNotificationPreference, error) {
+	var rows []models.MNotificationPreference
+
+	err := repo.db.
+		Where("user_id = ?", userID).
+		Find(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (repo *notificationRepository) FindPreferencesByType(userIDs []int64, notificationType string) ([]models.MNotificationPreference, error) {
+	var rows []models.MNotificationPreference
+
+	if len(userIDs) == 0 {
+		return rows, nil
+	}
+
+	err := repo.db.
+		Where("user_id IN ? AND type = ?", userIDs, notificationType).
+		Find(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (repo *notificationRepository) UpsertPreference(data *models.MNotificationPreference) error {
+	return repo.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "type"}},
+			DoUpdates: clause.AssignmentColumns([]string{"in_app", "push", "omni", "updated_at"}),
+		}).
+		Create(data).
+		Error
+}
+
+// FindActiveUserIDsByRole lists active users with the role. A non-nil
+// schoolID limits the result to that school's users.
+func (repo *notificationRepository) FindActiveUserIDsByRole(roleID int64, schoolID *int64) ([]int64, error) {
+	var ids []int64
+
+	query := repo.db.
+		Model(&models.User{}).
+		Where("role_id = ? AND isactive = ?", roleID, true)
+	if schoolID != nil {
+		query = query.Where("school_id = ?", *schoolID)
+	}
+
+	err := query.
+		Order("id ASC").
+		Pluck("id", &ids).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (repo *notificationRepository) InsertDeliveriesIgnoreDuplicates(data []*models.TNotificationDelivery) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return repo.db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(data).
+		Error
+}
+
+// ClaimPendingDeliveries takes up to limit due deliveries and pushes their
+// next attempt out to leaseUntil in a single statement, so the sends can run
+// without holding row locks. A worker that dies mid-send leaves the rows to be
+// picked up again once the lease runs out.
+func (repo *notificationRepository) ClaimPendingDeliveries(limit int, now time.Time, leaseUntil time.Time) ([]models.TNotificationDelivery, error) {
+	var rows []models.TNotificationDelivery
+
+	due := repo.db.
+		Model(&models.TNotificationDelivery{}).
+		Select("id").
+		Where("status = ? AND next_attempt_at <= ?", constant.NotificationDeliveryPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	err := repo.db.
+		Model(&rows).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil,
+			"updated_at":      now,
+		}).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
+
+func (repo *notificationRepository) UpdateDelivery(id int64, updates map[string]interface{}) error {
+	return repo.db.
+		Model(&models.TNotificationDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+}
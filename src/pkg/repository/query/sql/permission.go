@@ -18,7 +18,6 @@ type permissionRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewPermissionRepository() adapter.PermissionRepository {
@@ -82,12 +81,6 @@ func (repo *permissionRepository) WithLimit(limit int) adapter.PermissionReposit
 	return clone
 }
 
-func (repo *permissionRepository) WithCursor(cursor int) adapter.PermissionRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- Builder Helper ---
 
 func (repo *permissionRepository) getQueryBuilder() *builder.QueryBuilder[models.Permission] {
@@ -104,9 +97,6 @@ func (repo *permissionRepository) getQueryBuilder() *builder.QueryBuilder[models
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
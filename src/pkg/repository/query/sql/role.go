@@ -18,7 +18,6 @@ type roleRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewRoleRepository() adapter.RoleRepository {
@@ -82,12 +81,6 @@ func (repo *roleRepository) WithLimit(limit int) adapter.RoleRepository {
 	return clone
 }
 
-func (repo *roleRepository) WithCursor(cursor int) adapter.RoleRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *roleRepository) getQueryBuilder() *builder.QueryBuilder[models.Role] {
@@ -104,9 +97,6 @@ func (repo *roleRepository) getQueryBuilder() *builder.QueryBuilder[models.Role]
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -0,0 +1,195 @@
+package sql
+
+import (
+	"fmt"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type searchRepository struct {
+	db *gorm.DB
+}
+
+func NewSearchRepository() adapter.SearchRepository {
+	return &searchRepository{db: config.DB}
+}
+
+func (repo *searchRepository) WithTx(tx *gorm.DB) adapter.SearchRepository {
+	return &searchRepository{db: tx}
+}
+
+// searchHitsSQL selects every published piece of content matching @query in
+// the courses listed by @courses. Lessons, sub-lessons, materials and code
+// questions only count when the lesson above them is published too, and
+// generated questions once a teacher accepted them. The
+// search_vector columns and jk_search_query come from the ContentSearch
+// migration.
+const searchHitsSQL = `WITH search AS (
+	SELECT jk_search_query(@query) AS query
+), courses AS (
+	@courses
+), hits AS (
+	SELECT 'course' AS type, c.id, c.id AS course_id, NULL::bigint AS lesson_id, NULL::bigint AS sub_lesson_id,
+		NULL::bigint AS level_id, c.course_name AS title, c.description AS body,
+		ts_rank_cd(c.search_vector, s.query) AS rank
+	FROM m_course c
+	JOIN courses vc ON vc.id = c.id
+	CROSS JOIN search s
+	WHERE c.search_vector @@ s.query
+	UNION ALL
+	SELECT 'lesson', l.id, l.course_id, l.id, NULL, l.level_id, l.title, l.description,
+		ts_rank_cd(l.search_vector, s.query)
+	FROM m_lesson l
+	JOIN courses c ON c.id = l.course_id
+	CROSS JOIN search s
+	WHERE l.status = @published AND l.search_vector @@ s.query
+	UNION ALL
+	SELECT 'sub_lesson', sl.id, l.course_id, l.id, sl.id, l.level_id, sl.title, '',
+		ts_rank_cd(sl.search_vector, s.query)
+	FROM m_sub_lesson sl
+	JOIN m_lesson l ON l.id = sl.lesson_id
+	JOIN courses c ON c.id = l.course_id
+	CROSS JOIN search s
+	WHERE l.status = @published AND sl.search_vector @@ s.query
+	UNION ALL
+	SELECT 'material', m.id, l.course_id, l.id, sl.id, l.level_id, m.title, m.materials_text,
+		ts_rank_cd(m.search_vector, s.query)
+	FROM m_materials m
+	JOIN m_sub_lesson sl ON sl.id = m.sub_lesson_id
+	JOIN m_lesson l ON l.id = sl.lesson_id
+	JOIN courses c ON c.id = l.course_id
+	CROSS JOIN search s
+	WHERE m.status = @published AND l.status = @published AND m.search_vector @@ s.query
+	UNION ALL
+	SELECT 'code_question', q.id, l.course_id, l.id, sl.id, l.level_id, sl.title, q.code_question,
+		ts_rank_cd(q.search_vector, s.query)
+	FROM t_code_question q
+	JOIN m_sub_lesson sl ON sl.id = q.sub_lesson_id
+	JOIN m_lesson l ON l.id = sl.lesson_id
+	JOIN courses c ON c.id = l.course_id
+	CROSS JOIN search s
+	WHERE q.status = @published AND l.status = @published AND q.search_vector @@ s.query
+)
+`
+
+// searchHeadline asks ts_headline for up to two short fragments around the
+// matches.
+var searchHeadline = fmt.Sprintf(
+	`StartSel="%s", StopSel="%s", MaxWords=30, MinWords=10, MaxFragments=2, FragmentDelimiter=" … "`,
+	adapter.SearchMarkStart, adapter.SearchMarkStop,
+)
+
+// SearchContent returns one page of hits, best first. Snippets are only
+// built for the rows on the page.
+func (repo *searchRepository) SearchContent(query adapter.SearchQuery) ([]adapter.SearchHit, error) {
+	where, args := repo.searchArgs(query, true, true)
+	args["limit"] = query.Limit
+	args["offset"] = query.Offset
+	args["headline"] = searchHeadline
+
+	var hits []adapter.SearchHit
+	err := repo.db.Raw(searchHitsSQL+`
+		SELECT h.type, h.id, h.course_id, h.lesson_id, h.sub_lesson_id, h.level_id, h.title, h.rank,
+			ts_headline('public.jk_indonesian', COALESCE(NULLIF(h.body, ''), h.title), s.query, @headline) AS snippet
+		FROM (
+			SELECT * FROM hits WHERE `+where+`
+			ORDER BY rank DESC, type, id
+			LIMIT @limit OFFSET @offset
+		) h
+		CROSS JOIN search s
+		ORDER BY h.rank DESC, h.type, h.id`, args).
+		Scan(&hits).
+		Error
+
+	return hits, err
+}
+
+func (repo *searchRepository) CountSearchHits(query adapter.SearchQuery) (int64, error) {
+	where, args := repo.searchArgs(query, true, true)
+
+	var count int64
+	err := repo.db.Raw(searchHitsSQL+`SELECT COUNT(*) FROM hits WHERE `+where, args).
+		Scan(&count).
+		Error
+
+	return count, err
+}
+
+// CountSearchHitsByCourse counts the hits per course under every filter but
+// the course one, so the other courses can still be offered.
+func (repo *searchRepository) CountSearchHitsByCourse(query adapter.SearchQuery) ([]adapter.SearchFacet, error) {
+	where, args := repo.searchArgs(query, false, true)
+
+	var facets []adapter.SearchFacet
+	err := repo.db.Raw(searchHitsSQL+`
+		SELECT c.id, c.course_name AS name, COUNT(*) AS count
+		FROM hits
+		JOIN m_course c ON c.id = hits.course_id
+		WHERE `+where+`
+		GROUP BY c.id, c.course_name
+		ORDER BY count DESC, name`, args).
+		Scan(&facets).
+		Error
+
+	return facets, err
+}
+
+// CountSearchHitsByLevel counts the hits per level under every filter but
+// the level one. Courses span levels, so course hits aren't counted.
+func (repo *searchRepository) CountSearchHitsByLevel(query adapter.SearchQuery) ([]adapter.SearchFacet, error) {
+	where, args := repo.searchArgs(query, true, false)
+
+	var facets []adapter.SearchFacet
+	err := repo.db.Raw(searchHitsSQL+`
+		SELECT lv.id, lv.level_name AS name, COUNT(*) AS count
+		FROM hits
+		JOIN m_levels lv ON lv.id = hits.level_id
+		WHERE `+where+`
+		GROUP BY lv.id, lv.level_name
+		ORDER BY count DESC, name`, args).
+		Scan(&facets).
+		Error
+
+	return facets, err
+}
+
+// searchArgs builds the filter over hits and the named arguments of
+// searchHitsSQL. The course and level filters can be left out for facets.
+func (repo *searchRepository) searchArgs(query adapter.SearchQuery, byCourse bool, byLevel bool) (string, map[string]interface{}) {
+	// Published courses the caller may see; the query builder adds the
+	// school scope.
+	courses := builder.NewQueryBuilder[models.MCourse](repo.db.Session(&gorm.Session{NewDB: true})).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("m_course.status = ?", constant.ContentPublished)
+		}).
+		Query().
+		Select("m_course.id")
+
+	args := map[string]interface{}{
+		"query":     query.Query,
+		"courses":   courses,
+		"published": constant.ContentPublished,
+	}
+
+	conditions := []string{"TRUE"}
+	if len(query.Types) > 0 {
+		conditions = append(conditions, "hits.type IN @types")
+		args["types"] = query.Types
+	}
+	if byCourse && query.CourseID != nil {
+		conditions = append(conditions, "hits.course_id = @course_id")
+		args["course_id"] = *query.CourseID
+	}
+	if byLevel && query.LevelID != nil {
+		conditions = append(conditions, "hits.level_id = @level_id")
+		args["level_id"] = *query.LevelID
+	}
+
+	return strings.Join(conditions, " AND "), args
+}
@@ -0,0 +1,338 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+)
+
+type tClassCourseRepository struct {
+	db           *gorm.DB
+	preloads     []string
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTClassCourseRepository() adapter.TClassCourseRepository {
+	return &tClassCourseRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tClassCourseRepository) clone() *tClassCourseRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tClassCourseRepository) WithTx(tx *gorm.DB) adapter.TClassCourseRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tClassCourseRepository) WithPreloads(preloads ...string) adapter.TClassCourseRepository {
+	clone := repo.clone()
+	clone.preloads = append(clone.preloads, preloads...)
+	return clone
+}
+
+func (repo *tClassCourseRepository) WithWhere(query interface{}, args ...interface{}) adapter.TClassCourseRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tClassCourseRepository) WithOrder(order string) adapter.TClassCourseRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tClassCourseRepository) WithSpec(spec queryspec.Spec) adapter.TClassCourseRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tClassCourseRepository) WithLimit(limit int) adapter.TClassCourseRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🧱 Builder ---
+
+func (repo *tClassCourseRepository) getQueryBuilder(paginate bool) *builder.QueryBuilder[models.TClassCourse] {
+	qb := builder.NewQueryBuilder[models.TClassCourse](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithPreloads(repo.preloads...).WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 Assignments ---
+
+func (repo *tClassCourseRepository) InsertClassCourse(data *models.TClassCourse) (*models.TClassCourse, error) {
+	if err := repo.db.Omit("LessonDeadlines").Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tClassCourseRepository) UpdateClassCourse(id int64, updates map[string]interface{}) (*models.TClassCourse, error) {
+	return builder.NewQueryBuilder[models.TClassCourse](repo.db).UpdateByID(id, updates)
+}
+
+func (repo *tClassCourseRepository) RemoveClassCourse(id int64) error {
+	return builder.NewQueryBuilder[models.TClassCourse](repo.db).Delete(id)
+}
+
+func (repo *tClassCourseRepository) FindClassCourseByID(id int64) (*models.TClassCourse, error) {
+	var data models.TClassCourse
+
+	db := repo.db.Preload("LessonDeadlines", func(db *gorm.DB) *gorm.DB {
+		return db.Order("due_at ASC")
+	})
+	for _, p := range repo.preloads {
+		db = db.Preload(p)
+	}
+
+	if err := db.First(&data, id).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tClassCourseRepository) FindClassCourses() ([]models.TClassCourse, error) {
+	return repo.getQueryBuilder(true).FindAll()
+}
+
+func (repo *tClassCourseRepository) FindClassCoursePage() ([]models.TClassCourse, queryspec.Page, error) {
+	return repo.getQueryBuilder(true).FindPage()
+}
+
+func (repo *tClassCourseRepository) CountClassCourses() (int64, error) {
+	return repo.getQueryBuilder(false).Count()
+}
+
+// FindActiveClassCourse returns nil when the course isn't assigned to the class.
+func (repo *tClassCourseRepository) FindActiveClassCourse(classID int64, courseID int64) (*models.TClassCourse, error) {
+	var data models.TClassCourse
+
+	err := repo.db.
+		Where("class_id = ? AND course_id = ?", classID, courseID).
+		Preload("LessonDeadlines").
+		First(&data).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tClassCourseRepository) ReplaceLessonDeadlines(classCourseID int64, deadlines []models.TClassLessonDeadline) error {
+	err := repo.db.
+		Where("class_course_id = ?", classCourseID).
+		Delete(&models.TClassLessonDeadline{}).
+		Error
+	if err != nil || len(deadlines) == 0 {
+		return err
+	}
+
+	for i := range deadlines {
+		deadlines[i].ID = 0
+		deadlines[i].ClassCourseID = classCourseID
+	}
+	return repo.db.Create(&deadlines).Error
+}
+
+// --- 🔧 Courses & Members ---
+
+// FindCourseByID goes through the query builder so another school's private
+// courses can't be assigned.
+func (repo *tClassCourseRepository) FindCourseByID(courseID int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(courseID)
+}
+
+func (repo *tClassCourseRepository) FindCourseLessons(courseID int64) ([]models.MLesson, error) {
+	var data []models.MLesson
+
+	err := repo.db.
+		Where("course_id = ?", courseID).
+		Order("position ASC, id ASC").
+		Find(&data).
+		Error
+
+	return data, err
+}
+
+func (repo *tClassCourseRepository) FindUserByID(userID int64) (*models.User, error) {
+	var data models.User
+
+	if err := repo.db.First(&data, userID).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tClassCourseRepository) FindClassStudents(classID int64) ([]models.User, error) {
+	var data []models.User
+
+	err := repo.db.
+		Select("id", "name", "email", "class_id", "role_id").
+		Where("class_id = ? AND role_id = ?", classID, constant.RoleIDStudent).
+		Order("name ASC, id ASC").
+		Find(&data).
+		Error
+
+	return data, err
+}
+
+// EnrollStudents enrolls every listed student who isn't enrolled in the
+// course yet and returns only the new enrollments.
+func (repo *tClassCourseRepository) EnrollStudents(courseID int64, userIDs []int64) ([]models.TStudentCourse, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	var enrolled []int64
+	err := repo.db.
+		Model(&models.TStudentCourse{}).
+		Where("course_id = ? AND user_id IN ? AND deleted_at IS NULL", courseID, userIDs).
+		Pluck("user_id", &enrolled).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	skip := make(map[int64]bool, len(enrolled))
+	for _, id := range enrolled {
+		skip[id] = true
+	}
+
+	var data []models.TStudentCourse
+	for _, id := range userIDs {
+		if skip[id] {
+			continue
+		}
+		skip[id] = true
+		data = append(data, models.TStudentCourse{UserID: id, CourseID: courseID})
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	if err := repo.db.Create(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// --- 🔧 Progress ---
+
+func (repo *tClassCourseRepository) CountSubLessonsByLesson(lessonIDs []int64) (map[int64]int64, error) {
+	counts := make(map[int64]int64, len(lessonIDs))
+	if len(lessonIDs) == 0 {
+		return counts, nil
+	}
+
+	var rows []struct {
+		LessonID int64
+		Total    int64
+	}
+	err := repo.db.
+		Table("m_sub_lesson").
+		Select("lesson_id, COUNT(*) AS total").
+		Where("lesson_id IN ?", lessonIDs).
+		Group("lesson_id").
+		Scan(&rows).
+		Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		counts[row.LessonID] = row.Total
+	}
+	return counts, nil
+}
+
+func (repo *tClassCourseRepository) FindLessonCompletions(userIDs []int64, lessonIDs []int64) ([]adapter.LessonCompletion, error) {
+	if len(userIDs) == 0 || len(lessonIDs) == 0 {
+		return nil, nil
+	}
+
+	var rows []adapter.LessonCompletion
+	err := repo.db.
+		Table("t_student_progress").
+		Select(`
+			t_student_progress.user_id,
+			m_sub_lesson.lesson_id,
+			COUNT(*) AS completed,
+			COUNT(*) FILTER (WHERE t_student_progress.is_late) AS late
+		`).
+		Joins("JOIN m_sub_lesson ON m_sub_lesson.id = t_student_progress.sub_lesson_id").
+		Where("t_student_progress.user_id IN ?", userIDs).
+		Where("m_sub_lesson.lesson_id IN ?", lessonIDs).
+		Where("t_student_progress.status = ?", constant.ProgressCompleted).
+		Group("t_student_progress.user_id, m_sub_lesson.lesson_id").
+		Scan(&rows).
+		Error
+
+	return rows, err
+}
+
+// RefreshProgressLateFlags recomputes is_late on completed progress for the
+// students of an assignment's class. A sub-lesson is late when it was
+// completed after its lesson deadline, or after the assignment's end date if
+// the lesson has none. userID and subLessonID narrow the update when non-zero.
+func (repo *tClassCourseRepository) RefreshProgressLateFlags(classCourseID int64, userID int64, subLessonID int64) error {
+	query := `
+		UPDATE t_student_progress AS p
+		SET is_late = COALESCE(p.completed_at > COALESCE(d.due_at, cc.end_at), false)
+		FROM m_sub_lesson AS sl
+		JOIN m_lesson AS l ON l.id = sl.lesson_id
+		JOIN t_class_course AS cc ON cc.course_id = l.course_id
+		JOIN users AS u ON u.class_id = cc.class_id
+		LEFT JOIN t_class_lesson_deadline AS d ON d.class_course_id = cc.id AND d.lesson_id = l.id
+		WHERE sl.id = p.sub_lesson_id
+			AND u.id = p.user_id
+			AND cc.id = ?
+			AND p.status = ?`
+	args := []interface{}{classCourseID, constant.ProgressCompleted}
+
+	if userID != 0 {
+		query += " AND p.user_id = ?"
+		args = append(args, userID)
+	}
+	if subLessonID != 0 {
+		query += " AND p.sub_lesson_id = ?"
+		args = append(args, subLessonID)
+	}
+
+	return repo.db.Exec(query, args...).Error
+}
@@ -0,0 +1,311 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tClassRosterRepository struct {
+	db           *gorm.DB
+	preloads     []string
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTClassRosterRepository() adapter.TClassRosterRepository {
+	return &tClassRosterRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tClassRosterRepository) clone() *tClassRosterRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tClassRosterRepository) WithTx(tx *gorm.DB) adapter.TClassRosterRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tClassRosterRepository) WithPreloads(preloads ...string) adapter.TClassRosterRepository {
+	clone := repo.clone()
+	clone.preloads = append(clone.preloads, preloads...)
+	return clone
+}
+
+func (repo *tClassRosterRepository) WithWhere(query interface{}, args ...interface{}) adapter.TClassRosterRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tClassRosterRepository) WithOrder(order string) adapter.TClassRosterRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tClassRosterRepository) WithSpec(spec queryspec.Spec) adapter.TClassRosterRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tClassRosterRepository) WithLimit(limit int) adapter.TClassRosterRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func rosterQueryBuilder[T any](repo *tClassRosterRepository, paginate bool) *builder.QueryBuilder[T] {
+	qb := builder.NewQueryBuilder[T](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithPreloads(repo.preloads...).WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 Class & Membership ---
+
+func (repo *tClassRosterRepository) FindClassByCode(code string) (*models.MClass, error) {
+	var data models.MClass
+
+	err := repo.db.
+		Where("UPPER(class_code) = UPPER(?)", code).
+		First(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindClassByID goes through the query builder so the class is only found
+// when it belongs to the caller's school.
+func (repo *tClassRosterRepository) FindClassByID(classID int64) (*models.MClass, error) {
+	return builder.NewQueryBuilder[models.MClass](repo.db).FindByID(classID)
+}
+
+func (repo *tClassRosterRepository) LockClass(classID int64) (*models.MClass, error) {
+	var data models.MClass
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&data, classID).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tClassRosterRepository) UpdateClass(classID int64, updates map[string]interface{}) (*models.MClass, error) {
+	return builder.NewQueryBuilder[models.MClass](repo.db).UpdateByID(classID, updates)
+}
+
+func (repo *tClassRosterRepository) FindUserByID(userID int64) (*models.User, error) {
+	var data models.User
+
+	if err := repo.db.First(&data, userID).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// LockUser serialises concurrent roster changes for the same student.
+func (repo *tClassRosterRepository) LockUser(userID int64) (*models.User, error) {
+	var data models.User
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&data, userID).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tClassRosterRepository) SetUserSchool(userID int64, schoolID *int64) error {
+	return repo.db.
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("school_id", schoolID).
+		Error
+}
+
+func (repo *tClassRosterRepository) SetUserClass(userID int64, classID *int64) error {
+	return repo.db.
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("class_id", classID).
+		Error
+}
+
+func (repo *tClassRosterRepository) IsClassTeacher(classID int64, userID int64) (bool, error) {
+	var count int64
+
+	err := repo.db.
+		Table("m_class_teachers").
+		Where("m_class_id = ? AND user_id = ?", classID, userID).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repo *tClassRosterRepository) FindClassTeacherIDs(classID int64) ([]int64, error) {
+	var ids []int64
+
+	err := repo.db.
+		Table("m_class_teachers").
+		Where("m_class_id = ?", classID).
+		Order("user_id ASC").
+		Pluck("user_id", &ids).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (repo *tClassRosterRepository) FindClassStudentIDs(classID int64) ([]int64, error) {
+	var ids []int64
+
+	err := repo.db.
+		Model(&models.User{}).
+		Where("class_id = ? AND role_id = ?", classID, constant.RoleIDStudent).
+		Order("id ASC").
+		Pluck("id", &ids).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+func (repo *tClassRosterRepository) AddClassTeacher(classID int64, userID int64) error {
+	return repo.db.
+		Model(&models.MClass{ID: classID}).
+		Association("Teachers").
+		Append(&models.User{ID: userID})
+}
+
+func (repo *tClassRosterRepository) RemoveClassTeacher(classID int64, userID int64) error {
+	return repo.db.
+		Model(&models.MClass{ID: classID}).
+		Association("Teachers").
+		Delete(&models.User{ID: userID})
+}
+
+// --- 🔧 Join Requests ---
+
+func (repo *tClassRosterRepository) InsertJoinRequest(data *models.TClassJoinRequest) (*models.TClassJoinRequest, error) {
+	if err := repo.db.Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tClassRosterRepository) LockJoinRequest(id int64) (*models.TClassJoinRequest, error) {
+	var data models.TClassJoinRequest
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&data, id).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindPendingJoinRequest returns nil when the student has no open request.
+func (repo *tClassRosterRepository) FindPendingJoinRequest(classID int64, userID int64) (*models.TClassJoinRequest, error) {
+	var data models.TClassJoinRequest
+
+	err := repo.db.
+		Where("class_id = ? AND user_id = ? AND status = ?", classID, userID, constant.JoinRequestPending).
+		First(&data).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tClassRosterRepository) UpdateJoinRequest(id int64, updates map[string]interface{}) (*models.TClassJoinRequest, error) {
+	return builder.NewQueryBuilder[models.TClassJoinRequest](repo.db).UpdateByID(id, updates)
+}
+
+func (repo *tClassRosterRepository) FindJoinRequests() ([]models.TClassJoinRequest, error) {
+	return rosterQueryBuilder[models.TClassJoinRequest](repo, true).FindAll()
+}
+
+func (repo *tClassRosterRepository) FindJoinRequestPage() ([]models.TClassJoinRequest, queryspec.Page, error) {
+	return rosterQueryBuilder[models.TClassJoinRequest](repo, true).FindPage()
+}
+
+func (repo *tClassRosterRepository) CountJoinRequests() (int64, error) {
+	return rosterQueryBuilder[models.TClassJoinRequest](repo, false).Count()
+}
+
+// --- 🔧 Roster Log ---
+
+func (repo *tClassRosterRepository) InsertRosterLogs(data []*models.TClassRosterLog) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return repo.db.Create(data).Error
+}
+
+func (repo *tClassRosterRepository) FindRosterLogs() ([]models.TClassRosterLog, error) {
+	return rosterQueryBuilder[models.TClassRosterLog](repo, true).FindAll()
+}
+
+func (repo *tClassRosterRepository) FindRosterLogPage() ([]models.TClassRosterLog, queryspec.Page, error) {
+	return rosterQueryBuilder[models.TClassRosterLog](repo, true).FindPage()
+}
+
+func (repo *tClassRosterRepository) CountRosterLogs() (int64, error) {
+	return rosterQueryBuilder[models.TClassRosterLog](repo, false).Count()
+}
@@ -18,7 +18,6 @@ type tCodeAnswerRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewTCodeAnswerRepository() adapter.TCodeAnswerRepository {
@@ -82,12 +81,6 @@ func (repo *tCodeAnswerRepository) WithLimit(limit int) adapter.TCodeAnswerRepos
 	return clone
 }
 
-func (repo *tCodeAnswerRepository) WithCursor(cursor int) adapter.TCodeAnswerRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *tCodeAnswerRepository) getQueryBuilder() *builder.QueryBuilder[models.TCodeAnswer] {
@@ -104,9 +97,6 @@ func (repo *tCodeAnswerRepository) getQueryBuilder() *builder.QueryBuilder[model
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -18,7 +18,6 @@ type tCodeQuestionRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewTCodeQuestionRepository() adapter.TCodeQuestionRepository {
@@ -82,12 +81,6 @@ func (repo *tCodeQuestionRepository) WithLimit(limit int) adapter.TCodeQuestionR
 	return clone
 }
 
-func (repo *tCodeQuestionRepository) WithCursor(cursor int) adapter.TCodeQuestionRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *tCodeQuestionRepository) getQueryBuilder() *builder.QueryBuilder[models.CodeQuestion] {
@@ -104,9 +97,6 @@ func (repo *tCodeQuestionRepository) getQueryBuilder() *builder.QueryBuilder[mod
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -0,0 +1,230 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tCourseVersionRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTCourseVersionRepository() adapter.TCourseVersionRepository {
+	return &tCourseVersionRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tCourseVersionRepository) clone() *tCourseVersionRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tCourseVersionRepository) WithTx(tx *gorm.DB) adapter.TCourseVersionRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tCourseVersionRepository) WithWhere(query interface{}, args ...interface{}) adapter.TCourseVersionRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tCourseVersionRepository) WithOrder(order string) adapter.TCourseVersionRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tCourseVersionRepository) WithSpec(spec queryspec.Spec) adapter.TCourseVersionRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tCourseVersionRepository) WithLimit(limit int) adapter.TCourseVersionRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *tCourseVersionRepository) queryBuilder(paginate bool) *builder.QueryBuilder[models.TCourseVersion] {
+	qb := builder.NewQueryBuilder[models.TCourseVersion](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 Versions ---
+
+func (repo *tCourseVersionRepository) InsertCourseVersion(data *models.TCourseVersion) (*models.TCourseVersion, error) {
+	if err := repo.db.Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tCourseVersionRepository) FindCourseVersionByID(id int64) (*models.TCourseVersion, error) {
+	return builder.NewQueryBuilder[models.TCourseVersion](repo.db).FindByID(id)
+}
+
+// FindLatestCourseVersion returns nil when the course was never published
+// with versioning.
+func (repo *tCourseVersionRepository) FindLatestCourseVersion(courseID int64) (*models.TCourseVersion, error) {
+	var data models.TCourseVersion
+
+	err := repo.db.
+		Where("course_id = ?", courseID).
+		Order("version DESC").
+		First(&data).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tCourseVersionRepository) FindCourseVersions() ([]models.TCourseVersion, error) {
+	return repo.queryBuilder(true).FindAll()
+}
+
+func (repo *tCourseVersionRepository) FindCourseVersionPage() ([]models.TCourseVersion, queryspec.Page, error) {
+	return repo.queryBuilder(true).FindPage()
+}
+
+func (repo *tCourseVersionRepository) CountCourseVersions() (int64, error) {
+	return repo.queryBuilder(false).Count()
+}
+
+// --- 🔧 Course content ---
+
+// FindCourseByID only finds public courses and the private courses of the
+// caller's school.
+func (repo *tCourseVersionRepository) FindCourseByID(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(id)
+}
+
+// FindCourseTree loads the course with every lesson, sub-lesson and material
+// regardless of their editorial status.
+func (repo *tCourseVersionRepository) FindCourseTree(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).
+		WithPreloads("Lessons.SubLessons.Materials").
+		FindByID(id)
+}
+
+// UpdateCourseContent goes through the query builder so shared courses can
+// only be changed by platform admins.
+func (repo *tCourseVersionRepository) UpdateCourseContent(id int64, updates map[string]interface{}) error {
+	_, err := builder.NewQueryBuilder[models.MCourse](repo.db).UpdateByID(id, updates)
+	return err
+}
+
+// SaveLesson updates the lesson, or creates it when it has no id.
+func (repo *tCourseVersionRepository) SaveLesson(data *models.MLesson) error {
+	return repo.db.Omit(clause.Associations).Save(data).Error
+}
+
+func (repo *tCourseVersionRepository) SaveSubLesson(data *models.MSubLesson) error {
+	return repo.db.Omit(clause.Associations).Save(data).Error
+}
+
+func (repo *tCourseVersionRepository) SaveMaterial(data *models.MMaterials) error {
+	return repo.db.Omit(clause.Associations).Save(data).Error
+}
+
+func (repo *tCourseVersionRepository) ArchiveLessons(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.
+		Model(&models.MLesson{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": constant.ContentArchived, "published": false}).
+		Error
+}
+
+func (repo *tCourseVersionRepository) ArchiveMaterials(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.
+		Model(&models.MMaterials{}).
+		Where("id IN ?", ids).
+		Updates(map[string]interface{}{"status": constant.ContentArchived, "published": false}).
+		Error
+}
+
+func (repo *tCourseVersionRepository) RemoveSubLessons(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.Where("id IN ?", ids).Delete(&models.MSubLesson{}).Error
+}
+
+func (repo *tCourseVersionRepository) InsertContentTransition(data *models.TContentTransition) error {
+	return repo.db.Create(data).Error
+}
+
+// --- 🔧 Enrollments ---
+
+func (repo *tCourseVersionRepository) LockStudentCourse(id int64) (*models.TStudentCourse, error) {
+	var data models.TStudentCourse
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("deleted_at IS NULL").
+		First(&data, id).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindVisibleUserByID goes through the query builder so the user is only
+// found when it belongs to the caller's school.
+func (repo *tCourseVersionRepository) FindVisibleUserByID(userID int64) (*models.User, error) {
+	return builder.NewQueryBuilder[models.User](repo.db).FindByID(userID)
+}
+
+func (repo *tCourseVersionRepository) UpdateStudentCourseVersion(id int64, versionID int64) error {
+	return repo.db.
+		Model(&models.TStudentCourse{}).
+		Where("id = ?", id).
+		Update("course_version_id", versionID).
+		Error
+}
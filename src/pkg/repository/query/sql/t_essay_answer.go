@@ -18,7 +18,6 @@ type tEssayAnswerRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewTEssayAnswerRepository() adapter.TEssayAnswerRepository {
@@ -82,12 +81,6 @@ func (repo *tEssayAnswerRepository) WithLimit(limit int) adapter.TEssayAnswerRep
 	return clone
 }
 
-func (repo *tEssayAnswerRepository) WithCursor(cursor int) adapter.TEssayAnswerRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *tEssayAnswerRepository) getQueryBuilder() *builder.QueryBuilder[models.TEssayAnswer] {
@@ -104,9 +97,6 @@ func (repo *tEssayAnswerRepository) getQueryBuilder() *builder.QueryBuilder[mode
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -127,4 +117,12 @@ func (repo *tEssayAnswerRepository) FindTEssayAnswersByEssayQuestionIDAndUserID(
 		}).
 		WithPreloads("EssayQuestion", "User").
 		FindOne()
-}
\ No newline at end of file
+}
+
+func (repo *tEssayAnswerRepository) FindTEssayAnswerByID(id int64) (*models.TEssayAnswer, error) {
+	return repo.getQueryBuilder().FindByID(id)
+}
+
+func (repo *tEssayAnswerRepository) UpdateTEssayAnswer(id int64, updates map[string]interface{}) (*models.TEssayAnswer, error) {
+	return repo.getQueryBuilder().UpdateByID(id, updates)
+}
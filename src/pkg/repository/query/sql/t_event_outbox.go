@@ -0,0 +1,124 @@
+package sql
+
+import (
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tEventOutboxRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTEventOutboxRepository() adapter.TEventOutboxRepository {
+	return &tEventOutboxRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tEventOutboxRepository) clone() *tEventOutboxRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tEventOutboxRepository) WithTx(tx *gorm.DB) adapter.TEventOutboxRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tEventOutboxRepository) WithWhere(query interface{}, args ...interface{}) adapter.TEventOutboxRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tEventOutboxRepository) WithOrder(order string) adapter.TEventOutboxRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tEventOutboxRepository) WithSpec(spec queryspec.Spec) adapter.TEventOutboxRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tEventOutboxRepository) WithLimit(limit int) adapter.TEventOutboxRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *tEventOutboxRepository) getQueryBuilder() *builder.QueryBuilder[models.TEventOutbox] {
+	qb := builder.NewQueryBuilder[models.TEventOutbox](repo.db).
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 CRUD Methods ---
+
+// ClaimPendingTEventOutboxes locks up to limit due events. SKIP LOCKED lets
+// several relay instances run side by side without picking the same rows.
+func (repo *tEventOutboxRepository) ClaimPendingTEventOutboxes(limit int, now time.Time) ([]models.TEventOutbox, error) {
+	var rows []models.TEventOutbox
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+		Where("status = ?", constant.OutboxPending).
+		Where("available_at <= ?", now).
+		Order("id ASC").
+		Limit(limit).
+		Find(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+func (repo *tEventOutboxRepository) UpdateTEventOutbox(id int64, updates map[string]interface{}) (*models.TEventOutbox, error) {
+	return repo.getQueryBuilder().UpdateByID(id, updates)
+}
+
+func (repo *tEventOutboxRepository) FindTEventOutboxes() ([]models.TEventOutbox, error) {
+	return repo.getQueryBuilder().FindAll()
+}
+
+func (repo *tEventOutboxRepository) FindTEventOutboxPage() ([]models.TEventOutbox, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *tEventOutboxRepository) FindTEventOutboxByID(id int64) (*models.TEventOutbox, error) {
+	return repo.getQueryBuilder().FindByID(id)
+}
+
+func (repo *tEventOutboxRepository) CountTEventOutboxes() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
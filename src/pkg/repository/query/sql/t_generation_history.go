@@ -0,0 +1,226 @@
+package sql
+
+import (
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tGenerationHistoryRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTGenerationHistoryRepository() adapter.TGenerationHistoryRepository {
+	return &tGenerationHistoryRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tGenerationHistoryRepository) clone() *tGenerationHistoryRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tGenerationHistoryRepository) WithTx(tx *gorm.DB) adapter.TGenerationHistoryRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tGenerationHistoryRepository) WithWhere(query interface{}, args ...interface{}) adapter.TGenerationHistoryRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tGenerationHistoryRepository) WithOrder(order string) adapter.TGenerationHistoryRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tGenerationHistoryRepository) WithSpec(spec queryspec.Spec) adapter.TGenerationHistoryRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tGenerationHistoryRepository) WithLimit(limit int) adapter.TGenerationHistoryRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *tGenerationHistoryRepository) queryBuilder(paginate bool) *builder.QueryBuilder[models.TGenerationHistory] {
+	qb := builder.NewQueryBuilder[models.TGenerationHistory](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 History ---
+
+func (repo *tGenerationHistoryRepository) InsertGenerationHistory(data *models.TGenerationHistory) (*models.TGenerationHistory, error) {
+	if err := repo.db.Omit(clause.Associations).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tGenerationHistoryRepository) UpdateGenerationHistory(id int64, updates map[string]interface{}) error {
+	return repo.db.
+		Model(&models.TGenerationHistory{}).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+}
+
+func (repo *tGenerationHistoryRepository) FindGenerationHistoryByID(id int64) (*models.TGenerationHistory, error) {
+	return builder.NewQueryBuilder[models.TGenerationHistory](repo.db).FindByID(id)
+}
+
+// LockGenerationHistory keeps two teachers from reviewing the same run at
+// once.
+func (repo *tGenerationHistoryRepository) LockGenerationHistory(id int64) (*models.TGenerationHistory, error) {
+	var data models.TGenerationHistory
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&data, id).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tGenerationHistoryRepository) FindGenerationHistoryPage() ([]models.TGenerationHistory, queryspec.Page, error) {
+	return repo.queryBuilder(true).FindPage()
+}
+
+func (repo *tGenerationHistoryRepository) CountGenerationHistories() (int64, error) {
+	return repo.queryBuilder(false).Count()
+}
+
+// --- 🔧 Course content ---
+
+// FindCourseByID only finds public courses and the private courses of the
+// caller's school.
+func (repo *tGenerationHistoryRepository) FindCourseByID(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(id)
+}
+
+// FindSubLessonByID loads the sub-lesson with its lesson, level and
+// materials, which prompts are built from.
+func (repo *tGenerationHistoryRepository) FindSubLessonByID(id int64) (*models.MSubLesson, error) {
+	return builder.NewQueryBuilder[models.MSubLesson](repo.db).
+		WithPreloads("Lesson.Level", "Materials").
+		FindByID(id)
+}
+
+func (repo *tGenerationHistoryRepository) FindCodeQuestionByID(id int64) (*models.CodeQuestion, error) {
+	return builder.NewQueryBuilder[models.CodeQuestion](repo.db).FindByID(id)
+}
+
+func (repo *tGenerationHistoryRepository) InsertMaterials(data []*models.MMaterials) error {
+	return repo.db.Omit(clause.Associations).Create(data).Error
+}
+
+func (repo *tGenerationHistoryRepository) InsertCodeQuestions(data []*models.CodeQuestion) error {
+	return repo.db.Omit(clause.Associations).Create(data).Error
+}
+
+func (repo *tGenerationHistoryRepository) InsertEssayQuestions(data []*models.EssayQuestion) error {
+	return repo.db.Omit(clause.Associations).Create(data).Error
+}
+
+func (repo *tGenerationHistoryRepository) PublishCodeQuestions(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.
+		Model(&models.CodeQuestion{}).
+		Where("id IN ? AND status = ?", ids, constant.ContentDraft).
+		Update("status", constant.ContentPublished).
+		Error
+}
+
+func (repo *tGenerationHistoryRepository) PublishEssayQuestions(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.
+		Model(&models.EssayQuestion{}).
+		Where("id IN ? AND status = ?", ids, constant.ContentDraft).
+		Update("status", constant.ContentPublished).
+		Error
+}
+
+// RemoveDraftMaterials only removes the materials that never left draft;
+// the ones a teacher already moved on in the workflow are kept.
+func (repo *tGenerationHistoryRepository) RemoveDraftMaterials(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.
+		Where("id IN ? AND status = ?", ids, constant.ContentDraft).
+		Delete(&models.MMaterials{}).
+		Error
+}
+
+// RemoveDraftCodeQuestions removes the code questions that are still drafts
+// together with the essay questions written for them.
+func (repo *tGenerationHistoryRepository) RemoveDraftCodeQuestions(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	drafts := repo.db.
+		Model(&models.CodeQuestion{}).
+		Select("id").
+		Where("id IN ? AND status = ?", ids, constant.ContentDraft)
+
+	if err := repo.db.Where("code_question_id IN (?)", drafts).Delete(&models.EssayQuestion{}).Error; err != nil {
+		return err
+	}
+	return repo.db.
+		Where("id IN ? AND status = ?", ids, constant.ContentDraft).
+		Delete(&models.CodeQuestion{}).
+		Error
+}
+
+func (repo *tGenerationHistoryRepository) RemoveDraftEssayQuestions(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return repo.db.
+		Where("id IN ? AND status = ?", ids, constant.ContentDraft).
+		Delete(&models.EssayQuestion{}).
+		Error
+}
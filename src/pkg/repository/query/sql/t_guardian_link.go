@@ -0,0 +1,249 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tGuardianLinkRepository struct {
+	db           *gorm.DB
+	preloads     []string
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTGuardianLinkRepository() adapter.TGuardianLinkRepository {
+	return &tGuardianLinkRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tGuardianLinkRepository) clone() *tGuardianLinkRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tGuardianLinkRepository) WithTx(tx *gorm.DB) adapter.TGuardianLinkRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tGuardianLinkRepository) WithPreloads(preloads ...string) adapter.TGuardianLinkRepository {
+	clone := repo.clone()
+	clone.preloads = append(clone.preloads, preloads...)
+	return clone
+}
+
+func (repo *tGuardianLinkRepository) WithWhere(query interface{}, args ...interface{}) adapter.TGuardianLinkRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tGuardianLinkRepository) WithOrder(order string) adapter.TGuardianLinkRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tGuardianLinkRepository) WithSpec(spec queryspec.Spec) adapter.TGuardianLinkRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tGuardianLinkRepository) WithLimit(limit int) adapter.TGuardianLinkRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *tGuardianLinkRepository) queryBuilder(paginate bool) *builder.QueryBuilder[models.TGuardianLink] {
+	qb := builder.NewQueryBuilder[models.TGuardianLink](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithPreloads(repo.preloads...).WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 Links ---
+
+func (repo *tGuardianLinkRepository) InsertGuardianLink(data *models.TGuardianLink) (*models.TGuardianLink, error) {
+	if err := repo.db.Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tGuardianLinkRepository) LockGuardianLink(id int64) (*models.TGuardianLink, error) {
+	var data models.TGuardianLink
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&data, id).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindGuardianLink returns nil when the guardian was never linked to the
+// student.
+func (repo *tGuardianLinkRepository) FindGuardianLink(guardianID int64, studentID int64) (*models.TGuardianLink, error) {
+	var data models.TGuardianLink
+
+	err := repo.db.
+		Where("guardian_id = ? AND student_id = ?", guardianID, studentID).
+		First(&data).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tGuardianLinkRepository) UpdateGuardianLink(id int64, updates map[string]interface{}) (*models.TGuardianLink, error) {
+	return builder.NewQueryBuilder[models.TGuardianLink](repo.db).UpdateByID(id, updates)
+}
+
+func (repo *tGuardianLinkRepository) FindGuardianLinks() ([]models.TGuardianLink, error) {
+	return repo.queryBuilder(true).FindAll()
+}
+
+func (repo *tGuardianLinkRepository) FindGuardianLinkPage() ([]models.TGuardianLink, queryspec.Page, error) {
+	return repo.queryBuilder(true).FindPage()
+}
+
+func (repo *tGuardianLinkRepository) CountGuardianLinks() (int64, error) {
+	return repo.queryBuilder(false).Count()
+}
+
+// FindDigestLinks returns the accepted links whose digest for week has not
+// been sent yet, ordered by guardian.
+func (repo *tGuardianLinkRepository) FindDigestLinks(week string) ([]models.TGuardianLink, error) {
+	var data []models.TGuardianLink
+
+	err := repo.db.
+		Preload("Student").
+		Where("status = ?", constant.GuardianLinkAccepted).
+		Where("last_digest_week IS DISTINCT FROM ?", week).
+		Order("guardian_id ASC, student_id ASC").
+		Find(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tGuardianLinkRepository) MarkDigestSent(guardianID int64, week string) error {
+	return repo.db.
+		Model(&models.TGuardianLink{}).
+		Where("guardian_id = ? AND status = ?", guardianID, constant.GuardianLinkAccepted).
+		Update("last_digest_week", week).
+		Error
+}
+
+// --- 🔧 Students ---
+
+func (repo *tGuardianLinkRepository) FindUserByID(userID int64) (*models.User, error) {
+	var data models.User
+
+	if err := repo.db.First(&data, userID).Error; err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// FindVisibleUserByID goes through the query builder so the user is only
+// found when it belongs to the caller's school.
+func (repo *tGuardianLinkRepository) FindVisibleUserByID(userID int64) (*models.User, error) {
+	return builder.NewQueryBuilder[models.User](repo.db).FindByID(userID)
+}
+
+// FindStudentByLogin looks a student up by email or user code across every
+// school; guardians are not bound to the school of their children.
+func (repo *tGuardianLinkRepository) FindStudentByLogin(login string) (*models.User, error) {
+	var data models.User
+
+	err := repo.db.
+		Where("(LOWER(email) = LOWER(?) OR UPPER(code) = UPPER(?)) AND role_id = ?", login, login, constant.RoleIDStudent).
+		First(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+func (repo *tGuardianLinkRepository) FindStudentCourses(studentID int64) ([]models.TStudentCourse, error) {
+	var data []models.TStudentCourse
+
+	err := repo.db.
+		Preload("Course").
+		Preload("Badge").
+		Where("user_id = ? AND deleted_at IS NULL", studentID).
+		Order("id ASC").
+		Find(&data).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// FindReviewedEssays returns the student's essay answers a teacher has
+// approved, newest first. A nil since returns all of them.
+func (repo *tGuardianLinkRepository) FindReviewedEssays(studentID int64, since *time.Time, limit int) ([]models.TEssayAnswer, error) {
+	var data []models.TEssayAnswer
+
+	query := repo.db.
+		Preload("EssayQuestion").
+		Where("user_id = ? AND is_approved_by_teacher = ?", studentID, true)
+	if since != nil {
+		query = query.Where("updated_at >= ?", *since)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+
+	if err := query.Order("updated_at DESC").Find(&data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
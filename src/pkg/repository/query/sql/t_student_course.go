@@ -18,7 +18,6 @@ type tStudentCourseRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewTStudentCourseRepository() adapter.TStudentCourseRepository {
@@ -82,12 +81,6 @@ func (repo *tStudentCourseRepository) WithLimit(limit int) adapter.TStudentCours
 	return clone
 }
 
-func (repo *tStudentCourseRepository) WithCursor(cursor int) adapter.TStudentCourseRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *tStudentCourseRepository) getQueryBuilder() *builder.QueryBuilder[models.TStudentCourse] {
@@ -104,9 +97,6 @@ func (repo *tStudentCourseRepository) getQueryBuilder() *builder.QueryBuilder[mo
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -119,12 +109,18 @@ func (repo *tStudentCourseRepository) EnrollCourse(data *models.TStudentCourse)
 	return data, nil
 }
 
+// FindCourseByID only finds public courses and the private courses of the
+// caller's school.
+func (repo *tStudentCourseRepository) FindCourseByID(courseID int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(courseID)
+}
+
 func (repo *tStudentCourseRepository) FindByID(id int64, userID int64) (*models.TStudentCourse, error) {
 	return repo.getQueryBuilder().
 		WithWhere(func(db *gorm.DB) *gorm.DB {
 			return db.Where("id = ? AND user_id = ?", id, userID)
 		}).
-		WithPreloads("Course.Lessons.SubLessons.Materials", "Badge").
+		WithPreloads("Course.Lessons.SubLessons.Materials", "Badge", "CourseVersion").
 		FindOne()
 }
 
@@ -2,11 +2,14 @@ package sql
 
 import (
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type tStudentProgressRepository struct {
@@ -18,7 +21,6 @@ type tStudentProgressRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewTStudentProgressRepository() adapter.TStudentProgressRepository {
@@ -82,12 +84,6 @@ func (repo *tStudentProgressRepository) WithLimit(limit int) adapter.TStudentPro
 	return clone
 }
 
-func (repo *tStudentProgressRepository) WithCursor(cursor int) adapter.TStudentProgressRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *tStudentProgressRepository) getQueryBuilder() *builder.QueryBuilder[models.TStudentProgress] {
@@ -104,19 +100,63 @@ func (repo *tStudentProgressRepository) getQueryBuilder() *builder.QueryBuilder[
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
 // --- 🔧 CRUD Methods ---
 
-func (repo *tStudentProgressRepository) CompleteTStudentProgress(data *models.TStudentProgress) (*models.TStudentProgress, error) {
-	if err := repo.getQueryBuilder().Create(data); err != nil {
+func (repo *tStudentProgressRepository) FirstOrCreateTStudentProgress(data *models.TStudentProgress) (*models.TStudentProgress, error) {
+	err := repo.db.
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "user_id"}, {Name: "sub_lesson_id"}},
+			DoNothing: true,
+		}).
+		Create(data).
+		Error
+
+	if err != nil {
 		return nil, err
 	}
-	return data, nil
+
+	return repo.FindByUserAndSubLesson(data.UserID, data.SubLessonID)
+}
+
+func (repo *tStudentProgressRepository) UpdateTStudentProgress(id int64, updates map[string]interface{}) (*models.TStudentProgress, error) {
+	return repo.getQueryBuilder().UpdateByID(id, updates)
+}
+
+func (repo *tStudentProgressRepository) ResetByUserAndCourse(
+	userID int64,
+	courseID int64,
+	subLessonIDs []int64,
+) error {
+
+	query := repo.db.
+		Model(&models.TStudentProgress{}).
+		Where("user_id = ?", userID).
+		Where(`
+			sub_lesson_id IN (
+				SELECT m_sub_lesson.id
+				FROM m_sub_lesson
+				JOIN m_lesson ON m_lesson.id = m_sub_lesson.lesson_id
+				WHERE m_lesson.course_id = ?
+			)
+		`, courseID)
+
+	if len(subLessonIDs) > 0 {
+		query = query.Where("sub_lesson_id IN ?", subLessonIDs)
+	}
+
+	return query.
+		Updates(map[string]interface{}{
+			"status":         constant.ProgressNotStarted,
+			"started_at":     nil,
+			"completed_at":   nil,
+			"last_viewed_at": nil,
+			"is_late":        false,
+			"updated_at":     time.Now(),
+		}).
+		Error
 }
 
 func (repo *tStudentProgressRepository) FindByUserAndSubLesson(
@@ -133,6 +173,81 @@ func (repo *tStudentProgressRepository) FindByUserAndSubLesson(
 		FindFirst()
 }
 
+func (repo *tStudentProgressRepository) FindByUserAndCourse(
+	userID int64,
+	courseID int64,
+) ([]models.TStudentProgress, error) {
+
+	return repo.getQueryBuilder().
+		WithJoins(
+			"JOIN m_sub_lesson ON m_sub_lesson.id = t_student_progress.sub_lesson_id",
+			"JOIN m_lesson ON m_lesson.id = m_sub_lesson.lesson_id",
+		).
+		WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.
+				Where("t_student_progress.user_id = ?", userID).
+				Where("m_lesson.course_id = ?", courseID)
+		}).
+		FindAll()
+}
+
+// FindCourseTree loads a course of the caller's school, or a shared one,
+// with its lessons and sub-lessons. When publishedOnly is set the course and
+// the lessons loaded must be published.
+func (repo *tStudentProgressRepository) FindCourseTree(
+	courseID int64,
+	publishedOnly bool,
+) (*models.MCourse, error) {
+
+	var course models.MCourse
+
+	qb := builder.NewQueryBuilder[models.MCourse](repo.db)
+	if publishedOnly {
+		qb = qb.WithWhere(func(db *gorm.DB) *gorm.DB {
+			return db.Where("m_course.status = ?", constant.ContentPublished)
+		})
+	}
+
+	err := qb.Query().
+		Preload("Lessons", func(db *gorm.DB) *gorm.DB {
+			if publishedOnly {
+				db = db.Where("status = ?", constant.ContentPublished)
+			}
+			return db.Order("position ASC, id ASC")
+		}).
+		Preload("Lessons.SubLessons", func(db *gorm.DB) *gorm.DB {
+			return db.Order("order_position ASC, id ASC")
+		}).
+		First(&course, courseID).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return &course, nil
+}
+
+// IsEnrolled tells whether the user is enrolled in the course.
+func (repo *tStudentProgressRepository) IsEnrolled(
+	userID int64,
+	courseID int64,
+) (bool, error) {
+
+	var count int64
+
+	err := repo.db.
+		Model(&models.TStudentCourse{}).
+		Where("user_id = ? AND course_id = ? AND deleted_at IS NULL", userID, courseID).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
 func (repo *tStudentProgressRepository) CountTotalSubLessonByCourse(
 	courseID int64,
 ) (int64, error) {
@@ -172,7 +287,7 @@ func (repo *tStudentProgressRepository) CountCompletedByCourse(
 		`).
 		Where("t_student_progress.user_id = ?", userID).
 		Where("m_lesson.course_id = ?", courseID).
-		Where("t_student_progress.status = ?", "completed").
+		Where("t_student_progress.status = ?", constant.ProgressCompleted).
 		Count(&total).
 		Error
 
@@ -183,6 +298,252 @@ func (repo *tStudentProgressRepository) CountCompletedByCourse(
 	return total, nil
 }
 
+func (repo *tStudentProgressRepository) CountCompletedByCourseGroupedByUser(
+	courseID int64,
+) (map[int64]int64, error) {
+
+	var rows []struct {
+		UserID int64
+		Total  int64
+	}
+
+	err := repo.db.
+		Table("t_student_progress").
+		Select("t_student_progress.user_id, COUNT(*) AS total").
+		Joins(`
+			JOIN m_sub_lesson
+				ON m_sub_lesson.id = t_student_progress.sub_lesson_id
+		`).
+		Joins(`
+			JOIN m_lesson
+				ON m_lesson.id = m_sub_lesson.lesson_id
+		`).
+		Where("m_lesson.course_id = ?", courseID).
+		Where("t_student_progress.status = ?", constant.ProgressCompleted).
+		Group("t_student_progress.user_id").
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[int64]int64, len(rows))
+	for _, row := range rows {
+		result[row.UserID] = row.Total
+	}
+
+	return result, nil
+}
+
+func (repo *tStudentProgressRepository) FindCourseVersionSubLessonIDs(
+	versionID int64,
+) ([]int64, error) {
+
+	var version models.TCourseVersion
+
+	err := repo.db.
+		Select("id", "sub_lesson_ids").
+		First(&version, versionID).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return version.SubLessonIDs, nil
+}
+
+func (repo *tStudentProgressRepository) CountCompletedBySubLessons(
+	userID int64,
+	subLessonIDs []int64,
+) (int64, error) {
+
+	var total int64
+	if len(subLessonIDs) == 0 {
+		return 0, nil
+	}
+
+	err := repo.db.
+		Model(&models.TStudentProgress{}).
+		Where("user_id = ?", userID).
+		Where("sub_lesson_id IN ?", subLessonIDs).
+		Where("status = ?", constant.ProgressCompleted).
+		Count(&total).
+		Error
+
+	if err != nil {
+		return 0, err
+	}
+
+	return total, nil
+}
+
+func (repo *tStudentProgressRepository) CountCompletedBySubLessonsGroupedByUser(
+	subLessonIDs []int64,
+) (map[int64]int64, error) {
+
+	result := map[int64]int64{}
+	if len(subLessonIDs) == 0 {
+		return result, nil
+	}
+
+	var rows []struct {
+		UserID int64
+		Total  int64
+	}
+
+	err := repo.db.
+		Table("t_student_progress").
+		Select("user_id, COUNT(*) AS total").
+		Where("sub_lesson_id IN ?", subLessonIDs).
+		Where("status = ?", constant.ProgressCompleted).
+		Group("user_id").
+		Scan(&rows).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		result[row.UserID] = row.Total
+	}
+
+	return result, nil
+}
+
+// LockStudentCourse takes a row lock on the enrollment so concurrent
+// recomputations for the same student and course are serialized. It returns
+// nil when the student is not enrolled.
+func (repo *tStudentProgressRepository) LockStudentCourse(
+	userID int64,
+	courseID int64,
+) (*models.TStudentCourse, error) {
+
+	var enrollments []models.TStudentCourse
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("user_id = ?", userID).
+		Where("course_id = ?", courseID).
+		Order("id ASC").
+		Find(&enrollments).
+		Error
+
+	if err != nil || len(enrollments) == 0 {
+		return nil, err
+	}
+
+	return &enrollments[0], nil
+}
+
+func (repo *tStudentProgressRepository) LockStudentCoursesByCourse(
+	courseID int64,
+) ([]models.TStudentCourse, error) {
+
+	var enrollments []models.TStudentCourse
+
+	err := repo.db.
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("course_id = ?", courseID).
+		Order("id ASC").
+		Find(&enrollments).
+		Error
+
+	if err != nil {
+		return nil, err
+	}
+
+	return enrollments, nil
+}
+
+func (repo *tStudentProgressRepository) FindBadgeByScore(score int) (*models.MBadgeSettings, error) {
+	var badges []models.MBadgeSettings
+
+	err := repo.db.
+		Where("isactive = ?", true).
+		Where("min_score <= ?", score).
+		Where("max_score >= ?", score).
+		Order("min_score DESC").
+		Limit(1).
+		Find(&badges).
+		Error
+
+	if err != nil || len(badges) == 0 {
+		return nil, err
+	}
+
+	return &badges[0], nil
+}
+
+func (repo *tStudentProgressRepository) UpdateStudentCourseBadge(
+	studentCourseID int64,
+	badgeID int64,
+) error {
+
+	return repo.db.
+		Model(&models.TStudentCourse{}).
+		Where("id = ?", studentCourseID).
+		Update("badge_id", badgeID).
+		Error
+}
+
+func (repo *tStudentProgressRepository) AddStudentCourseScore(
+	studentCourseID int64,
+	delta int,
+) error {
+
+	return repo.db.
+		Model(&models.TStudentCourse{}).
+		Where("id = ?", studentCourseID).
+		Update("total_score", gorm.Expr("COALESCE(total_score, 0) + ?", delta)).
+		Error
+}
+
+// FindStudentByID only finds users of the caller's school.
+func (repo *tStudentProgressRepository) FindStudentByID(
+	id int64,
+) (*models.User, error) {
+
+	return builder.NewQueryBuilder[models.User](repo.db).FindByID(id)
+}
+
+// IsStudentTeacher tells whether teacherID teaches the class the student is
+// in.
+func (repo *tStudentProgressRepository) IsStudentTeacher(
+	studentID int64,
+	teacherID int64,
+) (bool, error) {
+
+	var count int64
+
+	err := repo.db.
+		Table("users").
+		Joins("JOIN m_class_teachers ON m_class_teachers.m_class_id = users.class_id").
+		Where("users.id = ? AND m_class_teachers.user_id = ? AND users.deleted_at IS NULL", studentID, teacherID).
+		Count(&count).
+		Error
+
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+func (repo *tStudentProgressRepository) FindEnrolledCourseIDs() ([]int64, error) {
+	var courseIDs []int64
+
+	err := repo.db.
+		Model(&models.TStudentCourse{}).
+		Distinct().
+		Order("course_id ASC").
+		Pluck("course_id", &courseIDs).
+		Error
+
+	return courseIDs, err
+}
+
 func (repo *tStudentProgressRepository) FindCourseIDBySubLesson(
 	subLessonID int64,
 ) (int64, error) {
@@ -214,4 +575,4 @@ func (repo *tStudentProgressRepository) UpdateStudentCourseProgress(
 		Where("course_id = ?", courseID).
 		Update("progress_percentage", percentage).
 		Error
-}
\ No newline at end of file
+}
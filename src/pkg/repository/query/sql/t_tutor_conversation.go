@@ -0,0 +1,223 @@
+package sql
+
+import (
+	"errors"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tTutorConversationRepository struct {
+	db           *gorm.DB
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTTutorConversationRepository() adapter.TTutorConversationRepository {
+	return &tTutorConversationRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tTutorConversationRepository) clone() *tTutorConversationRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tTutorConversationRepository) WithTx(tx *gorm.DB) adapter.TTutorConversationRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tTutorConversationRepository) WithWhere(query interface{}, args ...interface{}) adapter.TTutorConversationRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tTutorConversationRepository) WithOrder(order string) adapter.TTutorConversationRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tTutorConversationRepository) WithSpec(spec queryspec.Spec) adapter.TTutorConversationRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tTutorConversationRepository) WithLimit(limit int) adapter.TTutorConversationRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *tTutorConversationRepository) queryBuilder(paginate bool) *builder.QueryBuilder[models.TTutorConversation] {
+	qb := builder.NewQueryBuilder[models.TTutorConversation](repo.db)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	qb = qb.WithSpec(repo.spec)
+	if !paginate {
+		return qb
+	}
+
+	qb = qb.WithOrder(repo.order)
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 Conversations ---
+
+// InsertConversation stamps the conversation with the student's school.
+func (repo *tTutorConversationRepository) InsertConversation(data *models.TTutorConversation) (*models.TTutorConversation, error) {
+	if err := builder.NewQueryBuilder[models.TTutorConversation](repo.db).Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// FindConversationByID only finds conversations of the caller's school;
+// the where clauses narrow it down further, e.g. to the caller's own.
+func (repo *tTutorConversationRepository) FindConversationByID(id int64) (*models.TTutorConversation, error) {
+	return repo.queryBuilder(false).FindByID(id)
+}
+
+func (repo *tTutorConversationRepository) FindConversationPage() ([]models.TTutorConversation, queryspec.Page, error) {
+	return repo.queryBuilder(true).FindPage()
+}
+
+func (repo *tTutorConversationRepository) CountConversations() (int64, error) {
+	return repo.queryBuilder(false).Count()
+}
+
+func (repo *tTutorConversationRepository) AddConversationTokens(id int64, tokens int) error {
+	return repo.db.
+		Model(&models.TTutorConversation{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{
+			"tokens_used": gorm.Expr("tokens_used + ?", tokens),
+			"updated_at":  time.Now(),
+		}).
+		Error
+}
+
+// --- 🔧 Messages ---
+
+func (repo *tTutorConversationRepository) InsertMessage(data *models.TTutorMessage) (*models.TTutorMessage, error) {
+	if err := repo.db.Omit(clause.Associations).Create(data).Error; err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+func (repo *tTutorConversationRepository) FindMessages(conversationID int64) ([]models.TTutorMessage, error) {
+	var data []models.TTutorMessage
+
+	err := repo.db.
+		Where("conversation_id = ?", conversationID).
+		Order("id").
+		Find(&data).
+		Error
+
+	return data, err
+}
+
+// FindRecentMessages returns the last limit messages of a conversation,
+// oldest first.
+func (repo *tTutorConversationRepository) FindRecentMessages(conversationID int64, limit int) ([]models.TTutorMessage, error) {
+	var data []models.TTutorMessage
+
+	err := repo.db.
+		Where("conversation_id = ?", conversationID).
+		Order("id DESC").
+		Limit(limit).
+		Find(&data).
+		Error
+
+	slices.Reverse(data)
+	return data, err
+}
+
+func (repo *tTutorConversationRepository) CountStudentMessagesSince(userID int64, since time.Time) (int64, error) {
+	var count int64
+
+	err := repo.db.
+		Model(&models.TTutorMessage{}).
+		Where("user_id = ? AND role = ? AND created_at >= ?", userID, constant.TutorRoleStudent, since).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
+func (repo *tTutorConversationRepository) SumTokensSince(userID int64, since time.Time) (int64, error) {
+	var total int64
+
+	err := repo.db.
+		Model(&models.TTutorMessage{}).
+		Select("COALESCE(SUM(prompt_tokens + completion_tokens), 0)").
+		Where("user_id = ? AND created_at >= ?", userID, since).
+		Scan(&total).
+		Error
+
+	return total, err
+}
+
+// --- 🔧 Course content ---
+
+// FindCourseByID only finds public courses and the private courses of the
+// caller's school.
+func (repo *tTutorConversationRepository) FindCourseByID(id int64) (*models.MCourse, error) {
+	return builder.NewQueryBuilder[models.MCourse](repo.db).FindByID(id)
+}
+
+// FindSubLessonByID loads the sub-lesson with its lesson, materials and code
+// questions, which replies are grounded in.
+func (repo *tTutorConversationRepository) FindSubLessonByID(id int64) (*models.MSubLesson, error) {
+	return builder.NewQueryBuilder[models.MSubLesson](repo.db).
+		WithPreloads("Lesson", "Materials", "CodeQuestions").
+		FindByID(id)
+}
+
+// FindLatestErrorLog returns the student's latest failed run of one of the
+// code questions, or nil when there is none.
+func (repo *tTutorConversationRepository) FindLatestErrorLog(userID int64, codeQuestionIDs []int64) (*models.TCodeHistoryLogs, error) {
+	if len(codeQuestionIDs) == 0 {
+		return nil, nil
+	}
+
+	var data models.TCodeHistoryLogs
+	err := repo.db.
+		Where("user_id = ? AND code_question_id IN ? AND is_error", userID, codeQuestionIDs).
+		Order("created_at DESC, id DESC").
+		First(&data).
+		Error
+
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
@@ -0,0 +1,194 @@
+package sql
+
+import (
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
+	adapter "jk-api/pkg/repository/adapter/sql"
+	"jk-api/pkg/repository/query/sql/builder"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type tWebhookDeliveryRepository struct {
+	db           *gorm.DB
+	preloads     []string
+	whereClauses []func(*gorm.DB) *gorm.DB
+	order        string
+	spec         queryspec.Spec
+	limit        *int
+}
+
+func NewTWebhookDeliveryRepository() adapter.TWebhookDeliveryRepository {
+	return &tWebhookDeliveryRepository{db: config.DB}
+}
+
+// --- 🔁 Chainable Configs ---
+
+func (repo *tWebhookDeliveryRepository) clone() *tWebhookDeliveryRepository {
+	clone := *repo
+	return &clone
+}
+
+func (repo *tWebhookDeliveryRepository) WithTx(tx *gorm.DB) adapter.TWebhookDeliveryRepository {
+	clone := repo.clone()
+	clone.db = tx
+	return clone
+}
+
+func (repo *tWebhookDeliveryRepository) WithPreloads(preloads ...string) adapter.TWebhookDeliveryRepository {
+	clone := repo.clone()
+	clone.preloads = append(clone.preloads, preloads...)
+	return clone
+}
+
+func (repo *tWebhookDeliveryRepository) WithWhere(query interface{}, args ...interface{}) adapter.TWebhookDeliveryRepository {
+	clone := repo.clone()
+	clone.whereClauses = append(clone.whereClauses, func(db *gorm.DB) *gorm.DB {
+		return db.Where(query, args...)
+	})
+	return clone
+}
+
+func (repo *tWebhookDeliveryRepository) WithOrder(order string) adapter.TWebhookDeliveryRepository {
+	clone := repo.clone()
+	clone.order = order
+	return clone
+}
+
+func (repo *tWebhookDeliveryRepository) WithSpec(spec queryspec.Spec) adapter.TWebhookDeliveryRepository {
+	clone := repo.clone()
+	clone.spec = spec
+	return clone
+}
+
+func (repo *tWebhookDeliveryRepository) WithLimit(limit int) adapter.TWebhookDeliveryRepository {
+	clone := repo.clone()
+	clone.limit = &limit
+	return clone
+}
+
+// --- 🔧 Query Builder Helper ---
+
+func (repo *tWebhookDeliveryRepository) getQueryBuilder() *builder.QueryBuilder[models.TWebhookDelivery] {
+	qb := builder.NewQueryBuilder[models.TWebhookDelivery](repo.db).
+		WithPreloads(repo.preloads...).
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
+
+	for _, w := range repo.whereClauses {
+		qb = qb.WithWhere(w)
+	}
+	if repo.limit != nil {
+		qb = qb.WithLimit(*repo.limit)
+	}
+	return qb
+}
+
+// --- 🔧 CRUD Methods ---
+
+func (repo *tWebhookDeliveryRepository) InsertTWebhookDelivery(data *models.TWebhookDelivery) (*models.TWebhookDelivery, error) {
+	if err := repo.getQueryBuilder().Create(data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// InsertManyTWebhookDeliveriesIgnoreDuplicates skips rows that already exist
+// for the same subscription and outbox event, so fan-out can be re-run safely.
+func (repo *tWebhookDeliveryRepository) InsertManyTWebhookDeliveriesIgnoreDuplicates(data []*models.TWebhookDelivery) error {
+	if len(data) == 0 {
+		return nil
+	}
+	return repo.db.
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(data).
+		Error
+}
+
+// ClaimPendingTWebhookDeliveries takes up to limit due deliveries, counts the
+// attempt and pushes their next attempt out to leaseUntil in a single
+// statement, so the receivers are called without holding row locks. A worker
+// that dies mid-send leaves the rows to be picked up again once the lease
+// runs out. Deliveries come with their subscription, unless it was deleted.
+func (repo *tWebhookDeliveryRepository) ClaimPendingTWebhookDeliveries(limit int, now time.Time, leaseUntil time.Time) ([]models.TWebhookDelivery, error) {
+	var rows []models.TWebhookDelivery
+
+	due := repo.db.
+		Model(&models.TWebhookDelivery{}).
+		Select("id").
+		Where("status = ? AND next_attempt_at <= ?", constant.WebhookDeliveryPending, now).
+		Order("id ASC").
+		Limit(limit).
+		Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"})
+
+	err := repo.db.
+		Model(&rows).
+		Clauses(clause.Returning{}).
+		Where("id IN (?)", due).
+		Updates(map[string]interface{}{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": leaseUntil,
+			"updated_at":      now,
+		}).
+		Error
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return rows, nil
+	}
+
+	var subscriptionIDs []int64
+	for _, row := range rows {
+		subscriptionIDs = append(subscriptionIDs, row.SubscriptionID)
+	}
+	var subscriptions []models.MWebhookSubscription
+	if err := repo.db.Where("id IN ?", subscriptionIDs).Find(&subscriptions).Error; err != nil {
+		return nil, err
+	}
+	for i := range rows {
+		for j := range subscriptions {
+			if subscriptions[j].ID == rows[i].SubscriptionID {
+				rows[i].Subscription = &subscriptions[j]
+			}
+		}
+	}
+
+	sort.Slice(rows, func(i, j int) bool { return rows[i].ID < rows[j].ID })
+	return rows, nil
+}
+
+func (repo *tWebhookDeliveryRepository) UpdateTWebhookDelivery(id int64, updates map[string]interface{}) (*models.TWebhookDelivery, error) {
+	return repo.getQueryBuilder().UpdateByID(id, updates)
+}
+
+// RecordTWebhookDeliveryAttempt writes the outcome of a claimed delivery in
+// one statement.
+func (repo *tWebhookDeliveryRepository) RecordTWebhookDeliveryAttempt(id int64, updates map[string]interface{}) error {
+	return repo.db.
+		Model(&models.TWebhookDelivery{}).
+		Where("id = ?", id).
+		Updates(updates).
+		Error
+}
+
+func (repo *tWebhookDeliveryRepository) FindTWebhookDeliveries() ([]models.TWebhookDelivery, error) {
+	return repo.getQueryBuilder().FindAll()
+}
+
+func (repo *tWebhookDeliveryRepository) FindTWebhookDeliveryPage() ([]models.TWebhookDelivery, queryspec.Page, error) {
+	return repo.getQueryBuilder().FindPage()
+}
+
+func (repo *tWebhookDeliveryRepository) FindTWebhookDeliveryByID(id int64) (*models.TWebhookDelivery, error) {
+	return repo.getQueryBuilder().FindByID(id)
+}
+
+func (repo *tWebhookDeliveryRepository) CountTWebhookDeliveries() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
@@ -18,7 +18,6 @@ type tWonderingScoreRepository struct {
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
 	limit        *int
-	cursor       *int
 }
 
 func NewTWonderingScoreRepository() adapter.TWonderingScoreRepository {
@@ -82,12 +81,6 @@ func (repo *tWonderingScoreRepository) WithLimit(limit int) adapter.TWonderingSc
 	return clone
 }
 
-func (repo *tWonderingScoreRepository) WithCursor(cursor int) adapter.TWonderingScoreRepository {
-	clone := repo.clone()
-	clone.cursor = &cursor
-	return clone
-}
-
 // --- 🔧 Query Builder Helper ---
 
 func (repo *tWonderingScoreRepository) getQueryBuilder() *builder.QueryBuilder[models.TWonderingScore] {
@@ -104,9 +97,6 @@ func (repo *tWonderingScoreRepository) getQueryBuilder() *builder.QueryBuilder[m
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
+	"jk-api/internal/queryspec"
 	adapter "jk-api/pkg/repository/adapter/sql"
 	"jk-api/pkg/repository/query/sql/builder"
 
@@ -19,8 +20,8 @@ type userRepository struct {
 	joins        []string
 	whereClauses []func(*gorm.DB) *gorm.DB
 	order        string
+	spec         queryspec.Spec
 	limit        *int
-	cursor       *int
 	unscoped     bool
 }
 
@@ -79,15 +80,15 @@ func (repo *userRepository) WithOrder(order string) adapter.UserRepository {
 	return clone
 }
 
-func (repo *userRepository) WithLimit(limit int) adapter.UserRepository {
+func (repo *userRepository) WithSpec(spec queryspec.Spec) adapter.UserRepository {
 	clone := repo.clone()
-	clone.limit = &limit
+	clone.spec = spec
 	return clone
 }
 
-func (repo *userRepository) WithCursor(cursor int) adapter.UserRepository {
+func (repo *userRepository) WithLimit(limit int) adapter.UserRepository {
 	clone := repo.clone()
-	clone.cursor = &cursor
+	clone.limit = &limit
 	return clone
 }
 
@@ -110,7 +111,8 @@ func (repo *userRepository) getQueryBuilder() *builder.QueryBuilder[models.User]
 		WithAssociations(repo.associations...).
 		WithReplacements(repo.replacements).
 		WithJoins(repo.joins...).
-		WithOrder(repo.order)
+		WithOrder(repo.order).
+		WithSpec(repo.spec)
 
 	for _, where := range repo.whereClauses {
 		qb = qb.WithWhere(where)
@@ -118,9 +120,6 @@ func (repo *userRepository) getQueryBuilder() *builder.QueryBuilder[models.User]
 	if repo.limit != nil {
 		qb = qb.WithLimit(*repo.limit)
 	}
-	if repo.cursor != nil {
-		qb = qb.WithCursor(*repo.cursor)
-	}
 	return qb
 }
 
@@ -213,6 +212,23 @@ func (repo *userRepository) FindUser() ([]models.User, error) {
 	return data, nil
 }
 
+func (repo *userRepository) FindUserPage() ([]models.User, queryspec.Page, error) {
+	data, page, err := repo.getQueryBuilder().FindPage()
+	if err != nil {
+		return nil, queryspec.Page{}, err
+	}
+	for i := range data {
+		if !data[i].IsPasswordDefault {
+			data[i].Password = ""
+		}
+	}
+	return data, page, nil
+}
+
+func (repo *userRepository) CountUsers() (int64, error) {
+	return repo.getQueryBuilder().Count()
+}
+
 func (repo *userRepository) FindUserByID(id int64) (*models.User, error) {
 	data, err := repo.getQueryBuilder().FindByID(id)
 	if err != nil {
@@ -224,6 +240,18 @@ func (repo *userRepository) FindUserByID(id int64) (*models.User, error) {
 	return data, nil
 }
 
+func (repo *userRepository) CountRolesByName(roleIDs []int64, name string) (int64, error) {
+	var count int64
+
+	err := repo.db.
+		Model(&models.Role{}).
+		Where("id IN ? AND name = ?", roleIDs, name).
+		Count(&count).
+		Error
+
+	return count, err
+}
+
 func (repo *userRepository) FindUserByEmail(email string) (*models.User, error) {
 	return repo.getQueryBuilder().
 		WithWhere(func(db *gorm.DB) *gorm.DB {
@@ -4,20 +4,28 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strings"
 	"time"
 
 	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/bcrypt_err"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
 	"jk-api/pkg/repository/adapter/sql"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
+	"gorm.io/gorm"
 )
 
 type AuthService interface {
+	WithTx(tx *gorm.DB) AuthService
+	GetDB() *gorm.DB
+
 	Login(email, password string) (*models.User, error)
 	Register(req *dto.RegisterRequest) (*models.User, error)
 	GetProfile(token string) (*models.User, error)
@@ -31,21 +39,41 @@ type AuthService interface {
 type authService struct {
 	repo sql.UserRepository
 	refreshTokenRepo  sql.RefreshTokenRepository
+	schoolRepo        sql.MSchoolRepository
+	tx   *gorm.DB
 }
 
 func NewAuthService(
 	userRepo sql.UserRepository,
 	refreshRepo sql.RefreshTokenRepository,
+	schoolRepo sql.MSchoolRepository,
 ) *authService {
 	return &authService{
 		repo: userRepo,
 		refreshTokenRepo: refreshRepo,
+		schoolRepo:       schoolRepo,
+	}
+}
+
+func (s *authService) WithTx(tx *gorm.DB) AuthService {
+	return &authService{
+		repo:             s.repo.WithTx(tx),
+		refreshTokenRepo: s.refreshTokenRepo.WithTx(tx),
+		schoolRepo:       s.schoolRepo.WithTx(tx),
+		tx:               tx,
 	}
 }
 
+func (s *authService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
 func (s *authService) Login(email, password string) (*models.User, error) {
 	user, err := s.repo.
-		WithPreloads("HasRoles.HasPermissions").
+		WithPreloads("HasRoles.HasPermissions", "School").
 		FindUserByEmail(email)
 
 	if err != nil {
@@ -60,6 +88,11 @@ func (s *authService) Login(email, password string) (*models.User, error) {
 		return nil, fmt.Errorf("akun tidak aktif")
 	}
 
+	// ❌ Cek sekolah aktif
+	if user.School != nil && !user.School.IsActive {
+		return nil, fmt.Errorf("sekolah %s tidak aktif", user.School.Name)
+	}
+
 	// ❌ Cek approval teacher
 	if user.HasRoles[0].ID == 2 && !user.IsApprovedByAdmin {
 		return nil, fmt.Errorf("akun teacher belum di-approve admin")
@@ -89,15 +122,30 @@ func (s *authService) Register(req *dto.RegisterRequest) (*models.User, error) {
 	case "student":
 		roleID = 3
 		isApproved = true
+	case constant.RoleGuardian:
+		roleID = constant.RoleIDGuardian
+		isApproved = true
 	default:
 		return nil, fmt.Errorf("role tidak valid")
 	}
 
+	// Kode sekolah opsional, tanpa kode user belum terikat ke sekolah mana pun
+	var schoolID *int64
+	if code := strings.TrimSpace(req.SchoolCode); code != "" {
+		school, err := s.schoolRepo.FindMSchoolByCode(code)
+		if err != nil {
+			return nil, fmt.Errorf("kode sekolah tidak ditemukan")
+		}
+		schoolID = &school.ID
+	}
+
 	user := models.User{
 		Name:              req.Name,
 		Email:             req.Email,
 		Password:          string(hashedPassword),
 		RoleID:            roleID,
+		HasRoles:          []models.Role{{ID: roleID}},
+		SchoolID:          schoolID,
 		IsApprovedByAdmin: isApproved,
 		IsActive:          true,
 	}
@@ -107,6 +155,16 @@ func (s *authService) Register(req *dto.RegisterRequest) (*models.User, error) {
 		return nil, gorm_err.TranslateGormError(err)
 	}
 
+	err = events.Publish(s.GetDB(), events.UserRegistered{
+		UserID:       createdUser.ID,
+		Email:        createdUser.Email,
+		Role:         req.Role,
+		RegisteredAt: createdUser.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
 	return createdUser, nil
 }
 
@@ -125,7 +183,7 @@ func (s *authService) GetProfile(token string) (user *models.User, err error) {
 	if !ok {
 		return nil, err
 	}
-	user, err = s.repo.WithPreloads("HasRoles.HasPermissions", "HasClass").FindUserByID(int64(userID))
+	user, err = s.repo.WithPreloads("HasRoles.HasPermissions", "HasClass", "School").FindUserByID(int64(userID))
 	if err != nil {
 		return nil, err
 	}
@@ -149,6 +207,8 @@ func (s *authService) GenerateAccessToken(user *models.User) (string, error) {
 		"name":       user.Name,
 		"roles":      roles,
 		"permissions": permissions,
+		"school_id":  user.SchoolID,
+		"department_scope_id": user.ScopeDepartmentID,
 		"exp":        time.Now().Add(7 * 24 * time.Hour).Unix(),
 	}
 
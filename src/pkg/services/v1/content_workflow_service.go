@@ -0,0 +1,215 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type ContentWorkflowService interface {
+	WithTx(tx *gorm.DB) ContentWorkflowService
+
+	TransitionContent(actorID int64, contentType string, id int64, action string, comment *string) (*models.TContentTransition, error)
+	AddContentComment(authorID int64, contentType string, id int64, body string) (*models.TContentComment, error)
+	GetContentComments(contentType string, id int64) ([]models.TContentComment, error)
+	GetContentTransitions(contentType string, id int64) ([]models.TContentTransition, error)
+	GetDB() *gorm.DB
+}
+
+type contentWorkflowService struct {
+	repo sql.ContentWorkflowRepository
+	tx   *gorm.DB
+}
+
+func NewContentWorkflowService(repo sql.ContentWorkflowRepository) ContentWorkflowService {
+	return &contentWorkflowService{repo: repo}
+}
+
+func (s *contentWorkflowService) WithTx(tx *gorm.DB) ContentWorkflowService {
+	return &contentWorkflowService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *contentWorkflowService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// contentTransition lists the states an action may start from and the state
+// it moves content into. An empty from allows every state except the target.
+type contentTransition struct {
+	from []string
+	to   string
+}
+
+var contentTransitions = map[string]contentTransition{
+	constant.ContentActionSubmit:         {from: []string{constant.ContentDraft}, to: constant.ContentInReview},
+	constant.ContentActionApprove:        {from: []string{constant.ContentInReview}, to: constant.ContentApproved},
+	constant.ContentActionRequestChanges: {from: []string{constant.ContentInReview, constant.ContentApproved}, to: constant.ContentDraft},
+	constant.ContentActionPublish:        {from: []string{constant.ContentApproved}, to: constant.ContentPublished},
+	constant.ContentActionUnpublish:      {from: []string{constant.ContentPublished}, to: constant.ContentDraft},
+	constant.ContentActionArchive:        {to: constant.ContentArchived},
+	constant.ContentActionRestore:        {from: []string{constant.ContentArchived}, to: constant.ContentDraft},
+}
+
+// TransitionContent applies action to the content and records who did it.
+// The row is locked so two reviewers can't move the same content at once.
+func (s *contentWorkflowService) TransitionContent(actorID int64, contentType string, id int64, action string, comment *string) (*models.TContentTransition, error) {
+	if err := validateContentType(contentType); err != nil {
+		return nil, err
+	}
+
+	transition, ok := contentTransitions[action]
+	if !ok {
+		return nil, fmt.Errorf("aksi %s tidak dikenal", action)
+	}
+
+	comment = trimmedComment(comment)
+	if action == constant.ContentActionRequestChanges && comment == nil {
+		return nil, fmt.Errorf("alasan perubahan wajib diisi")
+	}
+
+	current, err := s.repo.LockContentStatus(contentType, id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if !transitionAllowed(transition, current) {
+		return nil, fmt.Errorf("%s berstatus %s tidak dapat di-%s", contentType, current, action)
+	}
+
+	if err := s.repo.UpdateContentStatus(contentType, id, transition.to); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	data, err := s.repo.InsertContentTransition(&models.TContentTransition{
+		ContentType: contentType,
+		ContentID:   id,
+		Action:      action,
+		FromStatus:  current,
+		ToStatus:    transition.to,
+		ActorID:     actorID,
+		Comment:     comment,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if contentType == constant.ContentTypeCourse && transition.to == constant.ContentPublished {
+		course, err := s.repo.FindCourseByID(id)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		err = events.Publish(s.GetDB(), events.CoursePublished{
+			CourseID:    course.ID,
+			CourseName:  course.CourseName,
+			SchoolID:    course.SchoolID,
+			PublishedAt: time.Now(),
+			PublishedBy: actorID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return data, nil
+}
+
+func (s *contentWorkflowService) AddContentComment(authorID int64, contentType string, id int64, body string) (*models.TContentComment, error) {
+	if err := s.findContent(contentType, id); err != nil {
+		return nil, err
+	}
+
+	body = strings.TrimSpace(body)
+	if body == "" {
+		return nil, fmt.Errorf("komentar wajib diisi")
+	}
+
+	data, err := s.repo.InsertContentComment(&models.TContentComment{
+		ContentType: contentType,
+		ContentID:   id,
+		AuthorID:    authorID,
+		Body:        body,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *contentWorkflowService) GetContentComments(contentType string, id int64) ([]models.TContentComment, error) {
+	if err := s.findContent(contentType, id); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.FindContentComments(contentType, id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *contentWorkflowService) GetContentTransitions(contentType string, id int64) ([]models.TContentTransition, error) {
+	if err := s.findContent(contentType, id); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.FindContentTransitions(contentType, id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// findContent makes sure the content exists and is visible to the caller.
+func (s *contentWorkflowService) findContent(contentType string, id int64) error {
+	if err := validateContentType(contentType); err != nil {
+		return err
+	}
+	if _, err := s.repo.FindContentStatus(contentType, id); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return nil
+}
+
+func validateContentType(contentType string) error {
+	switch contentType {
+	case constant.ContentTypeCourse, constant.ContentTypeLesson, constant.ContentTypeMaterial:
+		return nil
+	}
+	return fmt.Errorf("tipe konten %s tidak dikenal", contentType)
+}
+
+func transitionAllowed(transition contentTransition, current string) bool {
+	if len(transition.from) == 0 {
+		return current != transition.to
+	}
+	for _, status := range transition.from {
+		if status == current {
+			return true
+		}
+	}
+	return false
+}
+
+func trimmedComment(comment *string) *string {
+	if comment == nil {
+		return nil
+	}
+	trimmed := strings.TrimSpace(*comment)
+	if trimmed == "" {
+		return nil
+	}
+	return &trimmed
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/coursepkg"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/pkg/repository/adapter/sql"
+	"sort"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// maxRenameAttempts bounds the search for a free course name on import.
+const maxRenameAttempts = 100
+
+// ErrCourseImportInvalid is returned when a package is imported while it still
+// has problems. Nothing is written; the report lists them.
+var ErrCourseImportInvalid = errors.New("paket kursus tidak valid, tidak ada data yang disimpan")
+
+type CoursePackageService interface {
+	WithTx(tx *gorm.DB) CoursePackageService
+
+	ExportCourse(id int64) (*coursepkg.Manifest, error)
+	ImportCourse(manifest *coursepkg.Manifest, options dto.CourseImportOptionsDto) (*dto.CourseImportReportDto, error)
+	GetDB() *gorm.DB
+}
+
+type coursePackageService struct {
+	courseRepo sql.MCourseRepository
+	levelRepo  sql.MLevelRepository
+	tx         *gorm.DB
+}
+
+func NewCoursePackageService(courseRepo sql.MCourseRepository, levelRepo sql.MLevelRepository) CoursePackageService {
+	return &coursePackageService{courseRepo: courseRepo, levelRepo: levelRepo}
+}
+
+func (s *coursePackageService) WithTx(tx *gorm.DB) CoursePackageService {
+	return &coursePackageService{
+		courseRepo: s.courseRepo.WithTx(tx),
+		levelRepo:  s.levelRepo.WithTx(tx),
+		tx:         tx,
+	}
+}
+
+func (s *coursePackageService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// ExportCourse turns a course tree into a package manifest. Archived lessons
+// and materials are left out.
+func (s *coursePackageService) ExportCourse(id int64) (*coursepkg.Manifest, error) {
+	course, err := s.courseRepo.FindCourseTree(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	manifest := &coursepkg.Manifest{
+		Course: coursepkg.Course{
+			Key:          fmt.Sprintf("course-%d", course.ID),
+			CourseName:   course.CourseName,
+			Description:  course.Description,
+			ImgThumbnail: course.ImgThumbnail,
+			Status:       course.Status,
+			Lessons:      []coursepkg.Lesson{},
+		},
+	}
+
+	var lessons []models.MLesson
+	if course.Lessons != nil {
+		lessons = *course.Lessons
+	}
+	sort.SliceStable(lessons, func(i, j int) bool { return lessons[i].Position < lessons[j].Position })
+
+	for _, l := range lessons {
+		if l.Status == constant.ContentArchived {
+			continue
+		}
+
+		lesson := coursepkg.Lesson{
+			Key:          fmt.Sprintf("lesson-%d", l.ID),
+			Title:        l.Title,
+			Description:  l.Description,
+			Position:     l.Position,
+			ImgThumbnail: l.ImgThumbnail,
+			Status:       l.Status,
+			SubLessons:   []coursepkg.SubLesson{},
+		}
+		if l.Level != nil {
+			lesson.Level = l.Level.LevelName
+		}
+
+		subLessons := l.SubLessons
+		sort.SliceStable(subLessons, func(i, j int) bool { return subLessons[i].OrderPosition < subLessons[j].OrderPosition })
+		for _, sl := range subLessons {
+			lesson.SubLessons = append(lesson.SubLessons, exportSubLesson(sl))
+		}
+
+		manifest.Course.Lessons = append(manifest.Course.Lessons, lesson)
+	}
+
+	return manifest, nil
+}
+
+func exportSubLesson(source models.MSubLesson) coursepkg.SubLesson {
+	subLesson := coursepkg.SubLesson{
+		Key:           fmt.Sprintf("sub-lesson-%d", source.ID),
+		Title:         source.Title,
+		OrderPosition: source.OrderPosition,
+		Materials:     []coursepkg.Material{},
+	}
+
+	materials := source.Materials
+	sort.SliceStable(materials, func(i, j int) bool { return materials[i].ContentPosition < materials[j].ContentPosition })
+	for _, m := range materials {
+		if m.Status == constant.ContentArchived {
+			continue
+		}
+		subLesson.Materials = append(subLesson.Materials, coursepkg.Material{
+			Key:             fmt.Sprintf("material-%d", m.ID),
+			Title:           m.Title,
+			URLVideo:        m.URLVideo,
+			ContentPosition: m.ContentPosition,
+			PromptLLM:       m.PromptLLM,
+			Status:          m.Status,
+			Body:            string(m.Materials),
+		})
+	}
+
+	// Generated questions no teacher accepted yet are left out, as the
+	// package has no status for them.
+	for _, q := range source.CodeQuestions {
+		if q.Status != constant.ContentPublished {
+			continue
+		}
+		question := coursepkg.CodeQuestion{
+			Key:            fmt.Sprintf("code-question-%d", q.ID),
+			CodeQuestion:   q.CodeQuestion,
+			Image:          q.Image,
+			Score:          q.Score,
+			Hint:           q.Hint,
+			EssayQuestions: []coursepkg.EssayQuestion{},
+		}
+		for _, e := range q.EssayQuestions {
+			if e.Status != constant.ContentPublished {
+				continue
+			}
+			question.EssayQuestions = append(question.EssayQuestions, coursepkg.EssayQuestion{
+				Key:           fmt.Sprintf("essay-question-%d", e.ID),
+				EssayQuestion: e.EssayQuestion,
+				Answer:        e.Answer,
+				Answer2:       e.Answer2,
+				Answer3:       e.Answer3,
+				Answer4:       e.Answer4,
+			})
+		}
+		subLesson.Questions = append(subLesson.Questions, question)
+	}
+
+	return subLesson
+}
+
+// ImportCourse validates manifest against this installation and, unless this
+// is a dry run, creates the course tree as drafts so it goes through review
+// before students see it. Every item gets a fresh id; package keys are only
+// reported back. Callers should run it inside a transaction.
+func (s *coursePackageService) ImportCourse(manifest *coursepkg.Manifest, options dto.CourseImportOptionsDto) (*dto.CourseImportReportDto, error) {
+	if options.OnConflict == "" {
+		options.OnConflict = constant.CourseImportConflictRename
+	}
+	if options.OnConflict != constant.CourseImportConflictRename && options.OnConflict != constant.CourseImportConflictFail {
+		return nil, fmt.Errorf("on_conflict %q tidak dikenal", options.OnConflict)
+	}
+
+	report := &dto.CourseImportReportDto{
+		DryRun:        options.DryRun,
+		FormatVersion: manifest.Version,
+		CourseName:    manifest.Course.CourseName,
+		Media:         manifest.Media,
+		Errors:        manifest.Validate(),
+	}
+
+	levelIDs, err := s.resolveLevels(manifest, report)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Course.CourseName != "" {
+		name, err := s.resolveCourseName(manifest.Course.CourseName, options.OnConflict)
+		if err != nil {
+			return nil, err
+		}
+		if name == "" {
+			report.Errors = append(report.Errors, fmt.Sprintf("kursus %q sudah ada", manifest.Course.CourseName))
+		} else {
+			report.Renamed = name != manifest.Course.CourseName
+			report.CourseName = name
+		}
+	}
+
+	countImportedItems(manifest, report)
+
+	if options.DryRun {
+		return report, nil
+	}
+	if len(report.Errors) > 0 {
+		return report, ErrCourseImportInvalid
+	}
+
+	course, err := buildImportedCourse(manifest, report.CourseName, levelIDs, options.SchoolID)
+	if err != nil {
+		return nil, err
+	}
+	data, err := s.courseRepo.InsertMCourse(course)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	report.CourseID = data.ID
+	report.Keys = importedKeys(manifest, data)
+	return report, nil
+}
+
+// resolveLevels matches lesson levels by name since level ids differ between
+// installations.
+func (s *coursePackageService) resolveLevels(manifest *coursepkg.Manifest, report *dto.CourseImportReportDto) (map[string]int64, error) {
+	levels, err := s.levelRepo.FindMLevel()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	byName := make(map[string]int64, len(levels))
+	for _, level := range levels {
+		byName[strings.ToLower(strings.TrimSpace(level.LevelName))] = level.ID
+	}
+
+	levelIDs := make(map[string]int64)
+	for _, lesson := range manifest.Course.Lessons {
+		if lesson.Level == "" {
+			continue
+		}
+		id, ok := byName[strings.ToLower(strings.TrimSpace(lesson.Level))]
+		if !ok {
+			report.Errors = append(report.Errors, fmt.Sprintf("lesson %s: level %q tidak ditemukan", lesson.Key, lesson.Level))
+			continue
+		}
+		levelIDs[lesson.Key] = id
+	}
+	return levelIDs, nil
+}
+
+// resolveCourseName returns name when no visible course uses it yet. On a
+// conflict it either finds a free "name (n)" or, with the fail strategy,
+// returns an empty name.
+func (s *coursePackageService) resolveCourseName(name string, onConflict string) (string, error) {
+	exists := func(candidate string) (bool, error) {
+		courses, err := s.courseRepo.
+			WithWhere("LOWER(m_course.course_name) = LOWER(?)", candidate).
+			WithLimit(1).
+			FindMCourse()
+		if err != nil {
+			return false, gorm_err.TranslateGormError(err)
+		}
+		return len(courses) > 0, nil
+	}
+
+	taken, err := exists(name)
+	if err != nil || !taken {
+		return name, err
+	}
+	if onConflict == constant.CourseImportConflictFail {
+		return "", nil
+	}
+
+	for n := 2; n <= maxRenameAttempts; n++ {
+		candidate := fmt.Sprintf("%s (%d)", name, n)
+		taken, err := exists(candidate)
+		if err != nil {
+			return "", err
+		}
+		if !taken {
+			return candidate, nil
+		}
+	}
+	return "", fmt.Errorf("tidak ada nama kursus yang tersedia untuk %q", name)
+}
+
+func countImportedItems(manifest *coursepkg.Manifest, report *dto.CourseImportReportDto) {
+	for _, lesson := range manifest.Course.Lessons {
+		report.Lessons++
+		for _, subLesson := range lesson.SubLessons {
+			report.SubLessons++
+			report.Materials += len(subLesson.Materials)
+			for _, question := range subLesson.Questions {
+				report.CodeQuestions++
+				report.EssayQuestions += len(question.EssayQuestions)
+			}
+		}
+	}
+}
+
+func buildImportedCourse(manifest *coursepkg.Manifest, name string, levelIDs map[string]int64, schoolID *int64) (*models.MCourse, error) {
+	course := &models.MCourse{
+		CourseName:   name,
+		Description:  manifest.Course.Description,
+		ImgThumbnail: manifest.Course.ImgThumbnail,
+		Status:       constant.ContentDraft,
+		SchoolID:     schoolID,
+		IsActive:     true,
+	}
+
+	lessons := make([]models.MLesson, 0, len(manifest.Course.Lessons))
+	for _, l := range manifest.Course.Lessons {
+		lesson := models.MLesson{
+			LevelID:      levelIDs[l.Key],
+			Title:        l.Title,
+			Description:  l.Description,
+			Position:     l.Position,
+			ImgThumbnail: l.ImgThumbnail,
+			Status:       constant.ContentDraft,
+			IsActive:     true,
+		}
+
+		for _, sl := range l.SubLessons {
+			subLesson := models.MSubLesson{
+				Title:         sl.Title,
+				OrderPosition: sl.OrderPosition,
+				IsActive:      true,
+			}
+			for _, m := range sl.Materials {
+				material := models.MMaterials{
+					Title:           m.Title,
+					URLVideo:        m.URLVideo,
+					ContentPosition: m.ContentPosition,
+					PromptLLM:       m.PromptLLM,
+					Status:          constant.ContentDraft,
+					IsActive:        true,
+				}
+				// Packages from before documents hold Markdown or HTML.
+				if err := restoreMaterialContent(&material, []byte(m.Body)); err != nil {
+					return nil, err
+				}
+				subLesson.Materials = append(subLesson.Materials, material)
+			}
+			for _, q := range sl.Questions {
+				question := models.CodeQuestion{
+					CodeQuestion: q.CodeQuestion,
+					Image:        q.Image,
+					Score:        q.Score,
+					Hint:         q.Hint,
+				}
+				for _, e := range q.EssayQuestions {
+					question.EssayQuestions = append(question.EssayQuestions, models.EssayQuestion{
+						EssayQuestion: e.EssayQuestion,
+						Answer:        e.Answer,
+						Answer2:       e.Answer2,
+						Answer3:       e.Answer3,
+						Answer4:       e.Answer4,
+					})
+				}
+				subLesson.CodeQuestions = append(subLesson.CodeQuestions, question)
+			}
+			lesson.SubLessons = append(lesson.SubLessons, subLesson)
+		}
+
+		lessons = append(lessons, lesson)
+	}
+	course.Lessons = &lessons
+
+	return course, nil
+}
+
+// importedKeys pairs each package key with the id it was inserted as. The
+// course was built in manifest order, so both trees line up.
+func importedKeys(manifest *coursepkg.Manifest, course *models.MCourse) map[string]int64 {
+	keys := map[string]int64{manifest.Course.Key: course.ID}
+	if course.Lessons == nil {
+		return keys
+	}
+
+	for i, lesson := range *course.Lessons {
+		l := manifest.Course.Lessons[i]
+		keys[l.Key] = lesson.ID
+		for j, subLesson := range lesson.SubLessons {
+			sl := l.SubLessons[j]
+			keys[sl.Key] = subLesson.ID
+			for k, material := range subLesson.Materials {
+				keys[sl.Materials[k].Key] = material.ID
+			}
+			for k, question := range subLesson.CodeQuestions {
+				q := sl.Questions[k]
+				keys[q.Key] = question.ID
+				for e, essay := range question.EssayQuestions {
+					keys[q.EssayQuestions[e].Key] = essay.ID
+				}
+			}
+		}
+	}
+	return keys
+}
@@ -0,0 +1,335 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type DepartmentService interface {
+	WithTx(tx *gorm.DB) DepartmentService
+
+	CreateDepartment(input *models.Department) (*models.Department, error)
+	BulkCreateDepartments(data []*models.Department) ([]*models.Department, error)
+	UpdateDepartment(id int64, updates map[string]interface{}) (*models.Department, error)
+	DeleteDepartment(id int64) error
+	BulkDeleteDepartments(ids []int64) error
+	GetAllDepartments(filter dto.DepartmentFilterDto) ([]models.Department, queryspec.Page, error)
+	GetDepartmentByID(id int64, filter dto.DepartmentFilterDto) (*models.Department, error)
+	GetDepartmentTree() ([]*models.Department, error)
+	GetDepartmentSummary(id int64) (*dto.DepartmentSummaryDto, error)
+	GetDB() *gorm.DB
+}
+
+type departmentService struct {
+	repo sql.DepartmentRepository
+	tx   *gorm.DB
+}
+
+func NewDepartmentService(repo sql.DepartmentRepository) DepartmentService {
+	return &departmentService{repo: repo}
+}
+
+func (s *departmentService) WithTx(tx *gorm.DB) DepartmentService {
+	return &departmentService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *departmentService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+func (s *departmentService) CreateDepartment(input *models.Department) (*models.Department, error) {
+	if err := s.prepareDepartment(input); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.InsertDepartment(input)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *departmentService) BulkCreateDepartments(data []*models.Department) ([]*models.Department, error) {
+	for _, department := range data {
+		if err := s.prepareDepartment(department); err != nil {
+			return nil, err
+		}
+	}
+
+	created, err := s.repo.InsertManyDepartments(data)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return created, nil
+}
+
+// UpdateDepartment moves a department when parent_id changes. A department
+// can't be moved below itself or into another school.
+func (s *departmentService) UpdateDepartment(id int64, updates map[string]interface{}) (*models.Department, error) {
+	if name, ok := updates["name"].(string); ok {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("nama department wajib diisi")
+		}
+		updates["name"] = name
+	}
+	if code, ok := updates["code"].(string); ok {
+		updates["code"] = normalizeDepartmentCode(&code)
+	}
+
+	if parentID, ok := updates["parent_id"].(int64); ok {
+		current, err := s.repo.FindDepartmentByID(id)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		if err := s.validateParent(current, parentID); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := s.repo.UpdateDepartment(id, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// DeleteDepartment refuses to remove a department that still has children.
+// Users and classes in it are detached.
+func (s *departmentService) DeleteDepartment(id int64) error {
+	if _, err := s.repo.FindDepartmentByID(id); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	children, err := s.repo.CountDepartmentChildren(id)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if children > 0 {
+		return fmt.Errorf("department %d masih memiliki sub-department", id)
+	}
+
+	if err := s.repo.DetachDepartment(id); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	err = s.repo.RemoveDepartment(id)
+	return gorm_err.TranslateGormError(err)
+}
+
+func (s *departmentService) BulkDeleteDepartments(ids []int64) error {
+	for _, id := range ids {
+		if err := s.DeleteDepartment(id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *departmentService) GetAllDepartments(filter dto.DepartmentFilterDto) ([]models.Department, queryspec.Page, error) {
+	repo := s.repo
+	if filter.Name != "" {
+		repo = repo.WithWhere("departments.name ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.ParentID != 0 {
+		repo = repo.WithWhere("departments.parent_id = ?", filter.ParentID)
+	}
+	if filter.RootOnly {
+		repo = repo.WithWhere("departments.parent_id IS NULL")
+	}
+	if filter.ShowDeleted {
+		repo = repo.WithUnscoped().WithWhere("departments.deleted_at IS NOT NULL")
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountDepartments()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	if filter.Preload {
+		repo = repo.WithPreloads("Parent", "Children")
+	}
+
+	data, page, err := repo.FindDepartmentPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *departmentService) GetDepartmentByID(id int64, filter dto.DepartmentFilterDto) (*models.Department, error) {
+	repo := s.repo
+	if filter.Preload {
+		repo = repo.WithPreloads("Parent", "Children")
+	}
+
+	data, err := repo.FindDepartmentByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// GetDepartmentTree nests every visible department under its parent. A
+// department whose parent is out of the caller's scope becomes a root.
+func (s *departmentService) GetDepartmentTree() ([]*models.Department, error) {
+	departments, err := s.repo.WithOrder("departments.name ASC").FindDepartment()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	nodes := make(map[int64]*models.Department, len(departments))
+	for i := range departments {
+		nodes[departments[i].ID] = &departments[i]
+	}
+
+	var roots []*models.Department
+	children := make(map[int64][]*models.Department)
+	for i := range departments {
+		node := &departments[i]
+		if node.ParentID != nil && nodes[*node.ParentID] != nil {
+			children[*node.ParentID] = append(children[*node.ParentID], node)
+			continue
+		}
+		roots = append(roots, node)
+	}
+
+	var attach func(node *models.Department) *models.Department
+	attach = func(node *models.Department) *models.Department {
+		for _, child := range children[node.ID] {
+			node.Children = append(node.Children, *attach(child))
+		}
+		return node
+	}
+	for _, root := range roots {
+		attach(root)
+	}
+	return roots, nil
+}
+
+func (s *departmentService) GetDepartmentSummary(id int64) (*dto.DepartmentSummaryDto, error) {
+	department, err := s.repo.FindDepartmentByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	ids, err := s.repo.FindDepartmentSubtreeIDs(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	summary, err := s.repo.SummarizeDepartments(ids)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return &dto.DepartmentSummaryDto{
+		Department:    *department,
+		DepartmentIDs: ids,
+		Members:       summary.Members,
+		Classes:       summary.Classes,
+		Students:      summary.Students,
+	}, nil
+}
+
+// prepareDepartment validates a new department. Children always belong to
+// the school of their parent.
+func (s *departmentService) prepareDepartment(input *models.Department) error {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return fmt.Errorf("nama department wajib diisi")
+	}
+	input.Code = normalizeDepartmentCode(input.Code)
+
+	if input.ParentID == nil {
+		return nil
+	}
+
+	parent, err := s.repo.FindDepartmentByID(*input.ParentID)
+	if err != nil {
+		return fmt.Errorf("parent department %d tidak ditemukan", *input.ParentID)
+	}
+	input.SchoolID = parent.SchoolID
+	return nil
+}
+
+func (s *departmentService) validateParent(department *models.Department, parentID int64) error {
+	parent, err := s.repo.FindDepartmentByID(parentID)
+	if err != nil {
+		return fmt.Errorf("parent department %d tidak ditemukan", parentID)
+	}
+	if !sameSchool(parent.SchoolID, department.SchoolID) {
+		return fmt.Errorf("parent department harus berada di sekolah yang sama")
+	}
+
+	subtree, err := s.repo.FindDepartmentSubtreeIDs(department.ID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	for _, id := range subtree {
+		if id == parentID {
+			return fmt.Errorf("department tidak dapat dipindah ke bawah dirinya sendiri")
+		}
+	}
+	return nil
+}
+
+// checkDepartment makes sure departmentID is visible to the caller before
+// users or classes are placed in it.
+func checkDepartment(repo sql.DepartmentRepository, departmentID *int64) error {
+	if departmentID == nil {
+		return nil
+	}
+	if _, err := repo.FindDepartmentByID(*departmentID); err != nil {
+		return fmt.Errorf("department %d tidak ditemukan", *departmentID)
+	}
+	return nil
+}
+
+// checkDepartmentUpdate runs checkDepartment for every department column set
+// in updates.
+func checkDepartmentUpdate(repo sql.DepartmentRepository, updates map[string]interface{}, columns ...string) error {
+	for _, column := range columns {
+		if id, ok := updates[column].(int64); ok {
+			if err := checkDepartment(repo, &id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func sameSchool(a *int64, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+func normalizeDepartmentCode(code *string) *string {
+	if code == nil {
+		return nil
+	}
+	normalized := strings.ToUpper(strings.TrimSpace(*code))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
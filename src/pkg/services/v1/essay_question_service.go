@@ -1,7 +1,9 @@
 package services
 
 import (
+	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
 	"jk-api/pkg/repository/adapter/sql"
@@ -11,8 +13,8 @@ import (
 
 type EssayQuestionService interface {
 	WithTx(tx *gorm.DB) EssayQuestionService
-	GetEssayQuestionsByCodeQuestionID(codeQuestionID int64) ([]models.EssayQuestion, error)
-	GetEssayQuestionByID(id int64) (*models.EssayQuestion, error)
+	GetEssayQuestionsByCodeQuestionID(codeQuestionID int64, filter dto.EssayQuestionFilterDto) ([]models.EssayQuestion, error)
+	GetEssayQuestionByID(id int64, filter dto.EssayQuestionFilterDto) (*models.EssayQuestion, error)
 	CreateEssayQuestion(data *models.EssayQuestion) (*models.EssayQuestion, error)
 	GetDB() *gorm.DB
 }
@@ -40,8 +42,12 @@ func (s *essayQuestionService) GetDB() *gorm.DB {
 	return config.DB
 }
 
-func (s *essayQuestionService) GetEssayQuestionsByCodeQuestionID(codeQuestionID int64) ([]models.EssayQuestion, error) {
-	data, err := s.repo.FindEssayQuestionsByCodeQuestionID(codeQuestionID)
+func (s *essayQuestionService) GetEssayQuestionsByCodeQuestionID(codeQuestionID int64, filter dto.EssayQuestionFilterDto) ([]models.EssayQuestion, error) {
+	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere("t_essay_question.status = ?", constant.ContentPublished)
+	}
+	data, err := repo.FindEssayQuestionsByCodeQuestionID(codeQuestionID)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
@@ -56,8 +62,12 @@ func (s *essayQuestionService) CreateEssayQuestion(data *models.EssayQuestion) (
 	return data, nil
 }
 
-func (s *essayQuestionService) GetEssayQuestionByID(id int64) (*models.EssayQuestion, error) {
-	data, err := s.repo.FindEssayQuestionByID(id)
+func (s *essayQuestionService) GetEssayQuestionByID(id int64, filter dto.EssayQuestionFilterDto) (*models.EssayQuestion, error) {
+	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere("t_essay_question.status = ?", constant.ContentPublished)
+	}
+	data, err := repo.FindEssayQuestionByID(id)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
@@ -22,18 +22,20 @@ type MBadgeSettingsService interface {
 }
 
 type mBadgeSettingsService struct {
-	repo sql.MBadgeSettingsRepository
-	tx   *gorm.DB
+	repo  sql.MBadgeSettingsRepository
+	media sql.MMediaRepository
+	tx    *gorm.DB
 }
 
-func NewMBadgeSettingsService(repo sql.MBadgeSettingsRepository) MBadgeSettingsService {
-	return &mBadgeSettingsService{repo: repo}
+func NewMBadgeSettingsService(repo sql.MBadgeSettingsRepository, media sql.MMediaRepository) MBadgeSettingsService {
+	return &mBadgeSettingsService{repo: repo, media: media}
 }
 
 func (s *mBadgeSettingsService) WithTx(tx *gorm.DB) MBadgeSettingsService {
 	return &mBadgeSettingsService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:  s.repo.WithTx(tx),
+		media: s.media.WithTx(tx),
+		tx:    tx,
 	}
 }
 
@@ -45,6 +47,10 @@ func (s *mBadgeSettingsService) GetDB() *gorm.DB {
 }
 
 func (s *mBadgeSettingsService) CreateMBadgeSettings(input *models.MBadgeSettings) (*models.MBadgeSettings, error) {
+	if err := fillMediaURL(s.media, input.ImageMediaID, &input.Image); err != nil {
+		return nil, err
+	}
+
 	data, err := s.repo.InsertMBadgeSettings(input)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -72,6 +78,10 @@ func (s *mBadgeSettingsService) UpdateMBadgeSettings(
 		delete(payload, key)
 	}
 
+	if err := syncMediaURL(s.media, payload, "image_media_id", "image"); err != nil {
+		return nil, err
+	}
+
 	updated, err := repo.UpdateMBadgeSettings(id, payload)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -3,9 +3,14 @@ package services
 import (
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/helper"
+	"jk-api/internal/tenant"
 	"jk-api/pkg/repository/adapter/sql"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -22,18 +27,22 @@ type MClassService interface {
 }
 
 type mClassService struct {
-	repo sql.MClassRepository
-	tx   *gorm.DB
+	repo           sql.MClassRepository
+	rosterRepo     sql.TClassRosterRepository
+	departmentRepo sql.DepartmentRepository
+	tx             *gorm.DB
 }
 
-func NewMClassService(repo sql.MClassRepository) MClassService {
-	return &mClassService{repo: repo}
+func NewMClassService(repo sql.MClassRepository, rosterRepo sql.TClassRosterRepository, departmentRepo sql.DepartmentRepository) MClassService {
+	return &mClassService{repo: repo, rosterRepo: rosterRepo, departmentRepo: departmentRepo}
 }
 
 func (s *mClassService) WithTx(tx *gorm.DB) MClassService {
 	return &mClassService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:           s.repo.WithTx(tx),
+		rosterRepo:     s.rosterRepo.WithTx(tx),
+		departmentRepo: s.departmentRepo.WithTx(tx),
+		tx:             tx,
 	}
 }
 
@@ -45,6 +54,17 @@ func (s *mClassService) GetDB() *gorm.DB {
 }
 
 func (s *mClassService) CreateMClass(input *models.MClass) (*models.MClass, error) {
+	if err := checkDepartment(s.departmentRepo, input.DepartmentID); err != nil {
+		return nil, err
+	}
+	if input.ClassCode == "" {
+		code, err := helper.GenerateClassCode(classCodeLength)
+		if err != nil {
+			return nil, err
+		}
+		input.ClassCode = code
+	}
+
 	data, err := s.repo.InsertMClass(input)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -58,6 +78,10 @@ func (s *mClassService) UpdateMClass(
 	associations map[string]interface{},
 ) (*models.MClass, error) {
 
+	if err := checkDepartmentUpdate(s.departmentRepo, payload, "department_id"); err != nil {
+		return nil, err
+	}
+
 	repo := s.repo
 
 	if len(associations) > 0 {
@@ -72,17 +96,104 @@ func (s *mClassService) UpdateMClass(
 		delete(payload, key)
 	}
 
+	before, err := s.rosterSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+
 	updated, err := repo.UpdateMClass(id, payload)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
 
+	after, err := s.rosterSnapshot(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.logRosterChanges(updated, before, after); err != nil {
+		return nil, err
+	}
+
 	return updated, nil
 }
 
 func (s *mClassService) DeleteMClass(id int64) error {
-	err := s.repo.WithAssociations("Teachers", "Students").RemoveMClass(id)
-	return gorm_err.TranslateGormError(err)
+	class, err := s.repo.FindMClassByID(id)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	before, err := s.rosterSnapshot(id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.repo.WithAssociations("Teachers", "Students").RemoveMClass(id); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	return s.logRosterChanges(class, before, classRoster{})
+}
+
+type classRoster struct {
+	teachers []int64
+	students []int64
+}
+
+func (s *mClassService) rosterSnapshot(classID int64) (classRoster, error) {
+	teachers, err := s.rosterRepo.FindClassTeacherIDs(classID)
+	if err != nil {
+		return classRoster{}, gorm_err.TranslateGormError(err)
+	}
+	students, err := s.rosterRepo.FindClassStudentIDs(classID)
+	if err != nil {
+		return classRoster{}, gorm_err.TranslateGormError(err)
+	}
+	return classRoster{teachers: teachers, students: students}, nil
+}
+
+// logRosterChanges records membership changes made through the class
+// Teachers/Students associations rather than the roster endpoints, and makes
+// sure new members belong to the class's school.
+func (s *mClassService) logRosterChanges(class *models.MClass, before classRoster, after classRoster) error {
+	classID := class.ID
+	logs := rosterDiffLogs(classID, before.teachers, after.teachers, constant.RosterTeacherAssigned, constant.RosterTeacherRemoved)
+	logs = append(logs, rosterDiffLogs(classID, before.students, after.students, constant.RosterStudentAdded, constant.RosterStudentRemoved)...)
+	if len(logs) == 0 {
+		return nil
+	}
+
+	for _, log := range logs {
+		if log.Action != constant.RosterTeacherAssigned && log.Action != constant.RosterStudentAdded {
+			continue
+		}
+		member, err := s.rosterRepo.FindUserByID(*log.UserID)
+		if err != nil {
+			return gorm_err.TranslateGormError(err)
+		}
+		if err := enterClassSchool(s.rosterRepo, member, class); err != nil {
+			return err
+		}
+	}
+	if err := s.rosterRepo.InsertRosterLogs(logs); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	now := time.Now()
+	for _, log := range logs {
+		if log.Action != constant.RosterStudentAdded {
+			continue
+		}
+		err := events.Publish(s.GetDB(), events.ClassStudentJoined{
+			ClassID:  classID,
+			UserID:   *log.UserID,
+			JoinedAt: now,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 func (s *mClassService) GetAllMClasses(filter dto.MClassFilterDto) ([]models.MClass, error) {
@@ -90,6 +201,10 @@ func (s *mClassService) GetAllMClasses(filter dto.MClassFilterDto) ([]models.MCl
 	if filter.Preload {
 		repo = repo.WithPreloads("Teachers", "Students")
 	}
+	if filter.DepartmentID != 0 {
+		condition := (&models.MClass{}).DepartmentCondition(tenant.DepartmentSubtreeSQL)
+		repo = repo.WithWhere(condition, tenant.DepartmentArg(filter.DepartmentID))
+	}
 	data, err := repo.FindMClass()
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
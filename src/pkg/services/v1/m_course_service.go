@@ -1,11 +1,16 @@
 package services
 
 import (
+	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/repository/adapter/sql"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -16,24 +21,27 @@ type MCourseService interface {
 	CreateMCourse(input *models.MCourse) (*models.MCourse, error)
 	UpdateMCourse(id int64, updates map[string]interface{}, associations map[string]interface{}) (*models.MCourse, error)
 	DeleteMCourse(id int64) error
-	GetAllMCourses(filter dto.MCourseFilterDto) ([]models.MCourse, error)
+	GetAllMCourses(filter dto.MCourseFilterDto) ([]models.MCourse, queryspec.Page, error)
 	GetMCourseByID(id int64, filter dto.MCourseFilterDto) (*models.MCourse, error)
+	CloneMCourse(id int64, actorID int64, input *dto.CloneMCourseDto) (*models.MCourse, error)
 	GetDB() *gorm.DB
 }
 
 type mCourseService struct {
-	repo sql.MCourseRepository
-	tx   *gorm.DB
+	repo  sql.MCourseRepository
+	media sql.MMediaRepository
+	tx    *gorm.DB
 }
 
-func NewMCourseService(repo sql.MCourseRepository) MCourseService {
-	return &mCourseService{repo: repo}
+func NewMCourseService(repo sql.MCourseRepository, media sql.MMediaRepository) MCourseService {
+	return &mCourseService{repo: repo, media: media}
 }
 
 func (s *mCourseService) WithTx(tx *gorm.DB) MCourseService {
 	return &mCourseService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:  s.repo.WithTx(tx),
+		media: s.media.WithTx(tx),
+		tx:    tx,
 	}
 }
 
@@ -45,6 +53,10 @@ func (s *mCourseService) GetDB() *gorm.DB {
 }
 
 func (s *mCourseService) CreateMCourse(input *models.MCourse) (*models.MCourse, error) {
+	if err := fillMediaURL(s.media, input.ImgThumbnailMediaID, &input.ImgThumbnail); err != nil {
+		return nil, err
+	}
+
 	data, err := s.repo.InsertMCourse(input)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -72,6 +84,10 @@ func (s *mCourseService) UpdateMCourse(
 		delete(payload, key)
 	}
 
+	if err := syncMediaURL(s.media, payload, "img_thumbnail_media_id", "img_thumbnail"); err != nil {
+		return nil, err
+	}
+
 	updated, err := repo.UpdateMCourse(id, payload)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -85,20 +101,43 @@ func (s *mCourseService) DeleteMCourse(id int64) error {
 	return gorm_err.TranslateGormError(err)
 }
 
-func (s *mCourseService) GetAllMCourses(filter dto.MCourseFilterDto) ([]models.MCourse, error) {
+func (s *mCourseService) GetAllMCourses(filter dto.MCourseFilterDto) ([]models.MCourse, queryspec.Page, error) {
 	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere("m_course.status = ?", constant.ContentPublished)
+	}
+	if filter.Template {
+		repo = repo.WithWhere("m_course.is_template = ?", true)
+	}
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountMCourses()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
 	if filter.Preload {
 		repo = repo.WithPreloads("Lessons")
 	}
-	data, err := repo.FindMCourse()
+
+	data, page, err := repo.FindMCoursePage()
 	if err != nil {
-		return nil, gorm_err.TranslateGormError(err)
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
-	return data, nil
+	if !filter.Preview {
+		for i := range data {
+			publishedLessons(&data[i])
+		}
+	}
+	page.Total = total
+	return data, page, nil
 }
 
 func (s *mCourseService) GetMCourseByID(id int64, filter dto.MCourseFilterDto) (*models.MCourse, error) {
 	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere("m_course.status = ?", constant.ContentPublished)
+	}
 	if filter.Preload {
 		repo = repo.WithPreloads("Lessons")
 	}
@@ -106,5 +145,201 @@ func (s *mCourseService) GetMCourseByID(id int64, filter dto.MCourseFilterDto) (
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+	if !filter.Preview {
+		publishedLessons(data)
+	}
+	return data, nil
+}
+
+// CloneMCourse deep-copies a course with its lessons, sub-lessons, materials
+// and code questions including their essay questions. Archived lessons and
+// materials are left behind. Badges are platform-wide score bands rather than
+// course content, so the copy awards the same ones without duplicating them.
+func (s *mCourseService) CloneMCourse(id int64, actorID int64, input *dto.CloneMCourseDto) (*models.MCourse, error) {
+	source, err := s.repo.FindCourseTree(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if err := s.checkLevelMap(input.LevelMap); err != nil {
+		return nil, err
+	}
+
+	clone := cloneCourseTree(source, input)
+	data, err := s.repo.InsertMCourse(clone)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	// A copy that keeps its published state still needs its first version.
+	if data.Status == constant.ContentPublished {
+		err = events.Publish(s.GetDB(), events.CoursePublished{
+			CourseID:    data.ID,
+			CourseName:  data.CourseName,
+			SchoolID:    data.SchoolID,
+			PublishedAt: time.Now(),
+			PublishedBy: actorID,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return data, nil
 }
+
+func (s *mCourseService) checkLevelMap(levelMap map[int64]int64) error {
+	if len(levelMap) == 0 {
+		return nil
+	}
+
+	seen := make(map[int64]bool, len(levelMap))
+	ids := make([]int64, 0, len(levelMap))
+	for _, to := range levelMap {
+		if !seen[to] {
+			seen[to] = true
+			ids = append(ids, to)
+		}
+	}
+
+	count, err := s.repo.CountLevelsByIDs(ids)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if count != int64(len(ids)) {
+		return fmt.Errorf("level tujuan tidak ditemukan")
+	}
+	return nil
+}
+
+// cloneCourseTree builds an unsaved copy of source so a single insert creates
+// the whole tree.
+func cloneCourseTree(source *models.MCourse, input *dto.CloneMCourseDto) *models.MCourse {
+	reset := input.ResetPublished == nil || *input.ResetPublished
+	status := func(current string) string {
+		if reset {
+			return constant.ContentDraft
+		}
+		return current
+	}
+
+	name := source.CourseName + " (salinan)"
+	if input.CourseName != nil && *input.CourseName != "" {
+		name = *input.CourseName
+	}
+	schoolID := source.SchoolID
+	if input.SchoolID != nil {
+		schoolID = input.SchoolID
+	}
+
+	course := &models.MCourse{
+		CourseName:          name,
+		Description:         source.Description,
+		ImgThumbnail:        source.ImgThumbnail,
+		ImgThumbnailMediaID: source.ImgThumbnailMediaID,
+		Status:              status(source.Status),
+		SchoolID:            schoolID,
+		IsActive:            source.IsActive,
+	}
+	course.Published = course.Status == constant.ContentPublished
+
+	lessons := make([]models.MLesson, 0)
+	if source.Lessons != nil {
+		for _, l := range *source.Lessons {
+			if l.Status == constant.ContentArchived {
+				continue
+			}
+
+			levelID := l.LevelID
+			if to, ok := input.LevelMap[l.LevelID]; ok {
+				levelID = to
+			}
+
+			lesson := models.MLesson{
+				LevelID:             levelID,
+				Title:               l.Title,
+				Description:         l.Description,
+				Position:            l.Position,
+				ImgThumbnail:        l.ImgThumbnail,
+				ImgThumbnailMediaID: l.ImgThumbnailMediaID,
+				Status:              status(l.Status),
+				IsActive:            l.IsActive,
+			}
+			lesson.Published = lesson.Status == constant.ContentPublished
+
+			for _, sl := range l.SubLessons {
+				lesson.SubLessons = append(lesson.SubLessons, cloneSubLesson(sl, status))
+			}
+			lessons = append(lessons, lesson)
+		}
+	}
+	course.Lessons = &lessons
+
+	return course
+}
+
+func cloneSubLesson(source models.MSubLesson, status func(string) string) models.MSubLesson {
+	subLesson := models.MSubLesson{
+		Title:         source.Title,
+		OrderPosition: source.OrderPosition,
+		IsActive:      source.IsActive,
+	}
+
+	for _, m := range source.Materials {
+		if m.Status == constant.ContentArchived {
+			continue
+		}
+		material := models.MMaterials{
+			Title:                   m.Title,
+			Materials:               m.Materials,
+			MaterialsText:           m.MaterialsText,
+			URLVideo:                m.URLVideo,
+			VideoMediaID:            m.VideoMediaID,
+			ContentPosition:         m.ContentPosition,
+			PromptLLM:               m.PromptLLM,
+			PromptTemplateVersionID: m.PromptTemplateVersionID,
+			Status:                  status(m.Status),
+			IsActive:                m.IsActive,
+		}
+		material.Published = material.Status == constant.ContentPublished
+		subLesson.Materials = append(subLesson.Materials, material)
+	}
+
+	for _, q := range source.CodeQuestions {
+		question := models.CodeQuestion{
+			CodeQuestion: q.CodeQuestion,
+			Image:        q.Image,
+			ImageMediaID: q.ImageMediaID,
+			Score:        q.Score,
+			Hint:         q.Hint,
+			Status:       q.Status,
+		}
+		for _, e := range q.EssayQuestions {
+			question.EssayQuestions = append(question.EssayQuestions, models.EssayQuestion{
+				EssayQuestion: e.EssayQuestion,
+				Answer:        e.Answer,
+				Answer2:       e.Answer2,
+				Answer3:       e.Answer3,
+				Answer4:       e.Answer4,
+				Status:        e.Status,
+			})
+		}
+		subLesson.CodeQuestions = append(subLesson.CodeQuestions, question)
+	}
+
+	return subLesson
+}
+
+// publishedLessons drops the preloaded lessons students may not see yet.
+func publishedLessons(course *models.MCourse) {
+	if course.Lessons == nil {
+		return
+	}
+	lessons := make([]models.MLesson, 0, len(*course.Lessons))
+	for _, lesson := range *course.Lessons {
+		if lesson.Status == constant.ContentPublished {
+			lessons = append(lessons, lesson)
+		}
+	}
+	course.Lessons = &lessons
+}
@@ -1,11 +1,13 @@
 package services
 
 import (
-	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/repository/adapter/sql"
 	"time"
 
@@ -18,7 +20,7 @@ type MLessonService interface {
 	CreateMLesson(input *models.MLesson) (*models.MLesson, error)
 	UpdateMLesson(id int64, updates map[string]interface{}) (*models.MLesson, error)
 	DeleteMLesson(id int64) error
-	GetAllMLessons(filter dto.MLessonFilterDto) ([]models.MLesson, error)
+	GetAllMLessons(filter dto.MLessonFilterDto) ([]models.MLesson, queryspec.Page, error)
 	GetMLessonByID(id int64, filter dto.MLessonFilterDto) (*models.MLesson, error)
 	GetMLessonsByIDs(ids []int64) ([]*models.MLesson, error)
 	GetDB() *gorm.DB
@@ -27,19 +29,26 @@ type MLessonService interface {
 	BulkDeleteMLessons(ids []int64) error
 }
 
+// publishedLessonSQL limits lessons to published ones in a published course.
+const publishedLessonSQL = `m_lesson.status = ? AND m_lesson.course_id IN (
+	SELECT id FROM m_course WHERE status = ?
+)`
+
 type mLessonService struct {
-	repo sql.MLessonRepository
-	tx   *gorm.DB
+	repo  sql.MLessonRepository
+	media sql.MMediaRepository
+	tx    *gorm.DB
 }
 
-func NewMLessonService(repo sql.MLessonRepository) MLessonService {
-	return &mLessonService{repo: repo}
+func NewMLessonService(repo sql.MLessonRepository, media sql.MMediaRepository) MLessonService {
+	return &mLessonService{repo: repo, media: media}
 }
 
 func (s *mLessonService) WithTx(tx *gorm.DB) MLessonService {
 	return &mLessonService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:  s.repo.WithTx(tx),
+		media: s.media.WithTx(tx),
+		tx:    tx,
 	}
 }
 
@@ -54,6 +63,10 @@ func (s *mLessonService) CreateMLesson(input *models.MLesson) (*models.MLesson,
 	input.CreatedAt = time.Now()
 	input.UpdatedAt = time.Now()
 
+	if err := fillMediaURL(s.media, input.ImgThumbnailMediaID, &input.ImgThumbnail); err != nil {
+		return nil, err
+	}
+
 	data, err := s.repo.InsertMLesson(input)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -72,47 +85,78 @@ func (s *mLessonService) UpdateMLesson(id int64, updates map[string]interface{})
 		return nil, gorm_err.TranslateGormError(err)
 	}
 
+	beforeCourseIDs, err := s.affectedCourseIDs([]int64{id}, updates)
+	if err != nil {
+		return nil, err
+	}
+
 	updates["updated_at"] = time.Now()
-	fmt.Println(updates)
+	if err := syncMediaURL(s.media, updates, "img_thumbnail_media_id", "img_thumbnail"); err != nil {
+		return nil, err
+	}
 
 	data, err := s.repo.UpdateMLesson(id, updates)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+
+	afterCourseIDs, err := s.affectedCourseIDs([]int64{id}, updates)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publishStructureChanged(append(beforeCourseIDs, afterCourseIDs...)...); err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
 func (s *mLessonService) DeleteMLesson(id int64) error {
-	err := s.repo.RemoveMLesson(id)
-	return gorm_err.TranslateGormError(err)
+	courseIDs, err := s.repo.FindCourseIDsByLessonIDs([]int64{id})
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	if err := s.repo.RemoveMLesson(id); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.publishStructureChanged(courseIDs...)
 }
 
-func (s *mLessonService) GetAllMLessons(filter dto.MLessonFilterDto) ([]models.MLesson, error) {
+func (s *mLessonService) GetAllMLessons(filter dto.MLessonFilterDto) ([]models.MLesson, queryspec.Page, error) {
 	repo := s.repo
-
-	if filter.Preload {
-		repo = repo.WithPreloads("Course", "Level")
+	if !filter.Preview {
+		repo = repo.WithWhere(publishedLessonSQL, constant.ContentPublished, constant.ContentPublished)
+	}
+	if filter.Name != "" {
+		repo = repo.WithWhere("m_lesson.title ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.ShowDeleted {
+		repo = repo.WithUnscoped().WithWhere("m_lesson.deleted_at IS NOT NULL")
 	}
+	repo = repo.WithSpec(filter.Spec)
 
-	if filter.Sort != "" && filter.Order != "" {
-		orderClause := filter.Sort + " " + filter.Order
-		repo = repo.WithOrder(orderClause)
+	total, err := repo.CountMLessons()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
 
-	if filter.ShowDeleted {
-		repo = repo.WithUnscoped().WithWhere("m_lessons.deleted_at IS NOT NULL")
+	if filter.Preload {
+		repo = repo.WithPreloads("Course", "Level")
 	}
-	fmt.Println("filter", filter)
 
-	data, err := repo.FindMLesson()
+	data, page, err := repo.FindMLessonPage()
 	if err != nil {
-		return nil, gorm_err.TranslateGormError(err)
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
-	return data, nil
+	page.Total = total
+	return data, page, nil
 }
 
 func (s *mLessonService) GetMLessonByID(id int64, filter dto.MLessonFilterDto) (*models.MLesson, error) {
 	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere(publishedLessonSQL, constant.ContentPublished, constant.ContentPublished)
+	}
 	if filter.Preload {
 		repo = repo.WithPreloads("Course", "Level")
 	}
@@ -124,6 +168,12 @@ func (s *mLessonService) GetMLessonByID(id int64, filter dto.MLessonFilterDto) (
 }
 
 func (s *mLessonService) BulkCreateMLessons(data []*models.MLesson) ([]*models.MLesson, error) {
+	for _, lesson := range data {
+		if err := fillMediaURL(s.media, lesson.ImgThumbnailMediaID, &lesson.ImgThumbnail); err != nil {
+			return nil, err
+		}
+	}
+
 	datas, err := s.repo.InsertManyMLessons(data)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -138,8 +188,24 @@ func (s *mLessonService) BulkUpdateMLessons(ids []int64, updates map[string]inte
 		repo = s.repo.WithUnscoped()
 	}
 
-	err := repo.UpdateManyMLessons(ids, updates)
-	return gorm_err.TranslateGormError(err)
+	if err := syncMediaURL(s.media, updates, "img_thumbnail_media_id", "img_thumbnail"); err != nil {
+		return err
+	}
+
+	beforeCourseIDs, err := s.affectedCourseIDs(ids, updates)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.UpdateManyMLessons(ids, updates); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	afterCourseIDs, err := s.affectedCourseIDs(ids, updates)
+	if err != nil {
+		return err
+	}
+	return s.publishStructureChanged(append(beforeCourseIDs, afterCourseIDs...)...)
 }
 
 func (s *mLessonService) GetMLessonsByIDs(ids []int64) ([]*models.MLesson, error) {
@@ -151,6 +217,37 @@ func (s *mLessonService) GetMLessonsByIDs(ids []int64) ([]*models.MLesson, error
 }
 
 func (s *mLessonService) BulkDeleteMLessons(ids []int64) error {
-	err := s.repo.RemoveManyMLessons(ids)
-	return gorm_err.TranslateGormError(err)
+	courseIDs, err := s.repo.FindCourseIDsByLessonIDs(ids)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	if err := s.repo.RemoveManyMLessons(ids); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.publishStructureChanged(courseIDs...)
+}
+
+// affectedCourseIDs returns the courses owning the lessons when the updates
+// can change which course their sub-lessons count towards, and nil otherwise.
+func (s *mLessonService) affectedCourseIDs(ids []int64, updates map[string]interface{}) ([]int64, error) {
+	_, movesCourse := updates["course_id"]
+	_, togglesDeleted := updates["deleted_at"]
+	if !movesCourse && !togglesDeleted {
+		return nil, nil
+	}
+
+	courseIDs, err := s.repo.FindCourseIDsByLessonIDs(ids)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return courseIDs, nil
+}
+
+func (s *mLessonService) publishStructureChanged(courseIDs ...int64) error {
+	event := events.NewCourseStructureChanged(courseIDs...)
+	if len(event.CourseIDs) == 0 {
+		return nil
+	}
+	return events.Publish(s.GetDB(), event)
 }
\ No newline at end of file
@@ -1,25 +1,32 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/richtext"
 	"jk-api/pkg/repository/adapter/sql"
 	"time"
 
 	"gorm.io/gorm"
 )
 
+var ErrMaterialFormat = errors.New("format materi tidak dikenal")
+
 type MMaterialService interface {
 	WithTx(tx *gorm.DB) MMaterialService
 
 	CreateMMaterial(input *models.MMaterials) (*models.MMaterials, error)
 	UpdateMMaterial(id int64, updates map[string]interface{}) (*models.MMaterials, error)
 	DeleteMMaterial(id int64) error
-	GetAllMMaterials(filter dto.MMaterialFilterDto) ([]models.MMaterials, error)
+	GetAllMMaterials(filter dto.MMaterialFilterDto) ([]models.MMaterials, queryspec.Page, error)
 	GetMMaterialByID(id int64, filter dto.MMaterialFilterDto) (*models.MMaterials, error)
+	RenderMMaterial(id int64, format string, filter dto.MMaterialFilterDto) (*dto.RenderedMMaterialDto, error)
 	GetMMaterialsByIDs(ids []int64) ([]*models.MMaterials, error)
 	GetDB() *gorm.DB
 	BulkCreateMMaterials(data []*models.MMaterials) ([]*models.MMaterials, error)
@@ -27,19 +34,32 @@ type MMaterialService interface {
 	BulkDeleteMMaterials(ids []int64) error
 }
 
+// publishedMaterialSQL limits materials to published ones whose lesson and
+// course are published too.
+const publishedMaterialSQL = `m_materials.status = ? AND m_materials.sub_lesson_id IN (
+	SELECT sl.id FROM m_sub_lesson sl
+	JOIN m_lesson l ON l.id = sl.lesson_id
+	JOIN m_course c ON c.id = l.course_id
+	WHERE l.status = ? AND c.status = ?
+)`
+
 type mMaterialService struct {
-	repo sql.MMaterialRepository
-	tx   *gorm.DB
+	repo    sql.MMaterialRepository
+	media   sql.MMediaRepository
+	prompts sql.MPromptTemplateRepository
+	tx      *gorm.DB
 }
 
-func NewMMaterialService(repo sql.MMaterialRepository) MMaterialService {
-	return &mMaterialService{repo: repo}
+func NewMMaterialService(repo sql.MMaterialRepository, media sql.MMediaRepository, prompts sql.MPromptTemplateRepository) MMaterialService {
+	return &mMaterialService{repo: repo, media: media, prompts: prompts}
 }
 
 func (s *mMaterialService) WithTx(tx *gorm.DB) MMaterialService {
 	return &mMaterialService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:    s.repo.WithTx(tx),
+		media:   s.media.WithTx(tx),
+		prompts: s.prompts.WithTx(tx),
+		tx:      tx,
 	}
 }
 
@@ -51,6 +71,20 @@ func (s *mMaterialService) GetDB() *gorm.DB {
 }
 
 func (s *mMaterialService) CreateMMaterial(input *models.MMaterials) (*models.MMaterials, error) {
+	if err := setMaterialContent(input, input.Materials); err != nil {
+		return nil, err
+	}
+	if input.VideoMediaID != nil {
+		if err := checkVideoMedia(s.media, *input.VideoMediaID); err != nil {
+			return nil, err
+		}
+	}
+	if input.PromptTemplateVersionID != nil {
+		if err := checkMaterialPrompt(s.prompts, *input.PromptTemplateVersionID); err != nil {
+			return nil, err
+		}
+	}
+
 	input.CreatedAt = time.Now()
 	input.UpdatedAt = time.Now()
 
@@ -72,6 +106,20 @@ func (s *mMaterialService) UpdateMMaterial(id int64, updates map[string]interfac
 		return nil, gorm_err.TranslateGormError(err)
 	}
 
+	if id, ok := updates["video_media_id"].(int64); ok {
+		if err := checkVideoMedia(s.media, id); err != nil {
+			return nil, err
+		}
+	}
+	if id, ok := updates["prompt_template_version_id"].(int64); ok {
+		if err := checkMaterialPrompt(s.prompts, id); err != nil {
+			return nil, err
+		}
+	}
+	if err := prepareMaterialUpdates(updates); err != nil {
+		return nil, err
+	}
+
 	updates["updated_at"] = time.Now()
 	fmt.Println(updates)
 
@@ -87,36 +135,41 @@ func (s *mMaterialService) DeleteMMaterial(id int64) error {
 	return gorm_err.TranslateGormError(err)
 }
 
-func (s *mMaterialService) GetAllMMaterials(filter dto.MMaterialFilterDto) ([]models.MMaterials, error) {
+func (s *mMaterialService) GetAllMMaterials(filter dto.MMaterialFilterDto) ([]models.MMaterials, queryspec.Page, error) {
 	repo := s.repo
-
-	if filter.Preload {
-		repo = repo.WithPreloads("SubLesson")
+	if !filter.Preview {
+		repo = repo.WithWhere(publishedMaterialSQL, constant.ContentPublished, constant.ContentPublished, constant.ContentPublished)
 	}
-
 	if filter.Name != "" {
-		repo = repo.WithWhere("title ILIKE ?", "%"+filter.Name+"%")
+		repo = repo.WithWhere("m_materials.title ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.ShowDeleted {
+		repo = repo.WithUnscoped().WithWhere("m_materials.deleted_at IS NOT NULL")
 	}
+	repo = repo.WithSpec(filter.Spec)
 
-	if filter.Sort != "" && filter.Order != "" {
-		orderClause := filter.Sort + " " + filter.Order
-		repo = repo.WithOrder(orderClause)
+	total, err := repo.CountMMaterials()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
 
-	if filter.ShowDeleted {
-		repo = repo.WithUnscoped().WithWhere("m_materials.deleted_at IS NOT NULL")
+	if filter.Preload {
+		repo = repo.WithPreloads("SubLesson")
 	}
-	fmt.Println("filter", filter)
 
-	data, err := repo.FindMMaterials()
+	data, page, err := repo.FindMMaterialPage()
 	if err != nil {
-		return nil, gorm_err.TranslateGormError(err)
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
-	return data, nil
+	page.Total = total
+	return data, page, nil
 }
 
 func (s *mMaterialService) GetMMaterialByID(id int64, filter dto.MMaterialFilterDto) (*models.MMaterials, error) {
 	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere(publishedMaterialSQL, constant.ContentPublished, constant.ContentPublished, constant.ContentPublished)
+	}
 	if filter.Preload {
 		repo = repo.WithPreloads("SubLesson")
 	}
@@ -127,7 +180,50 @@ func (s *mMaterialService) GetMMaterialByID(id int64, filter dto.MMaterialFilter
 	return data, nil
 }
 
+// RenderMMaterial renders the document of a material as sanitized HTML, plain
+// text or Markdown.
+func (s *mMaterialService) RenderMMaterial(id int64, format string, filter dto.MMaterialFilterDto) (*dto.RenderedMMaterialDto, error) {
+	var render func(*richtext.Node) string
+	switch format {
+	case constant.MaterialFormatHTML:
+		render = richtext.HTML
+	case constant.MaterialFormatText:
+		render = richtext.PlainText
+	case constant.MaterialFormatMarkdown:
+		render = richtext.Markdown
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrMaterialFormat, format)
+	}
+
+	data, err := s.GetMMaterialByID(id, filter)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := richtext.Parse(data.Materials)
+	if err != nil {
+		return nil, err
+	}
+
+	return &dto.RenderedMMaterialDto{ID: data.ID, Format: format, Content: render(doc)}, nil
+}
+
 func (s *mMaterialService) BulkCreateMMaterials(data []*models.MMaterials) ([]*models.MMaterials, error) {
+	for _, material := range data {
+		if err := setMaterialContent(material, material.Materials); err != nil {
+			return nil, err
+		}
+		if material.VideoMediaID != nil {
+			if err := checkVideoMedia(s.media, *material.VideoMediaID); err != nil {
+				return nil, err
+			}
+		}
+		if material.PromptTemplateVersionID != nil {
+			if err := checkMaterialPrompt(s.prompts, *material.PromptTemplateVersionID); err != nil {
+				return nil, err
+			}
+		}
+	}
+
 	datas, err := s.repo.InsertManyMMaterials(data)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -142,6 +238,20 @@ func (s *mMaterialService) BulkUpdateMMaterials(ids []int64, updates map[string]
 		repo = s.repo.WithUnscoped()
 	}
 
+	if id, ok := updates["video_media_id"].(int64); ok {
+		if err := checkVideoMedia(s.media, id); err != nil {
+			return err
+		}
+	}
+	if id, ok := updates["prompt_template_version_id"].(int64); ok {
+		if err := checkMaterialPrompt(s.prompts, id); err != nil {
+			return err
+		}
+	}
+	if err := prepareMaterialUpdates(updates); err != nil {
+		return err
+	}
+
 	err := repo.UpdateManyMMaterials(ids, updates)
 	return gorm_err.TranslateGormError(err)
 }
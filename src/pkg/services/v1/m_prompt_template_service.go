@@ -0,0 +1,372 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/generation"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	maxPromptTemplateName = 150
+	maxPromptTemplateBody = 8000
+	maxPromptEvaluation   = 5000
+)
+
+// ErrPromptTemplateInvalid is returned for templates, versions and
+// evaluations that can't be stored as given.
+var ErrPromptTemplateInvalid = errors.New("data template prompt tidak valid")
+
+type MPromptTemplateService interface {
+	WithTx(tx *gorm.DB) MPromptTemplateService
+
+	CreatePromptTemplate(actorID int64, input *dto.CreatePromptTemplateDto) (*models.MPromptTemplate, error)
+	UpdatePromptTemplate(id int64, input *dto.UpdatePromptTemplateDto) (*models.MPromptTemplate, error)
+	GetPromptTemplates(filter dto.PromptTemplateFilterDto) ([]models.MPromptTemplate, queryspec.Page, error)
+	GetPromptTemplateByID(id int64) (*models.MPromptTemplate, error)
+	CreatePromptTemplateVersion(actorID int64, id int64, input *dto.CreatePromptTemplateVersionDto) (*models.TPromptTemplateVersion, error)
+	GetPromptTemplateVersions(id int64, spec queryspec.Spec) ([]models.TPromptTemplateVersion, queryspec.Page, error)
+	GetPromptTemplateVersion(id int64, version int) (*models.TPromptTemplateVersion, error)
+	RenderPromptTemplate(id int64, input *dto.RenderPromptTemplateDto) (*dto.RenderedPromptTemplateDto, error)
+	CreatePromptEvaluation(actorID int64, id int64, input *dto.CreatePromptEvaluationDto) (*models.TPromptEvaluation, error)
+	GetPromptEvaluations(id int64, spec queryspec.Spec) ([]models.TPromptEvaluation, queryspec.Page, error)
+	GetDB() *gorm.DB
+}
+
+type mPromptTemplateService struct {
+	repo sql.MPromptTemplateRepository
+	tx   *gorm.DB
+}
+
+func NewMPromptTemplateService(repo sql.MPromptTemplateRepository) MPromptTemplateService {
+	return &mPromptTemplateService{repo: repo}
+}
+
+func (s *mPromptTemplateService) WithTx(tx *gorm.DB) MPromptTemplateService {
+	return &mPromptTemplateService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *mPromptTemplateService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+func (s *mPromptTemplateService) CreatePromptTemplate(actorID int64, input *dto.CreatePromptTemplateDto) (*models.MPromptTemplate, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" || utf8.RuneCountInString(name) > maxPromptTemplateName {
+		return nil, fmt.Errorf("%w: name wajib diisi, maksimal %d karakter", ErrPromptTemplateInvalid, maxPromptTemplateName)
+	}
+	if !slices.Contains(constant.GenerationKinds, input.Kind) {
+		return nil, fmt.Errorf("%w: kind harus salah satu dari %s", ErrPromptTemplateInvalid, strings.Join(constant.GenerationKinds, ", "))
+	}
+	if err := validatePromptBody(input.Body, input.Variables); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.InsertPromptTemplate(&models.MPromptTemplate{
+		Name:           name,
+		Kind:           input.Kind,
+		Description:    input.Description,
+		IsDefault:      input.IsDefault,
+		CurrentVersion: 1,
+		IsActive:       true,
+		CreatedBy:      &actorID,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if data.IsDefault {
+		if err := s.repo.ClearDefaultPromptTemplates(data.Kind, data.SchoolID, data.ID); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	version, err := s.repo.InsertPromptTemplateVersion(&models.TPromptTemplateVersion{
+		TemplateID: data.ID,
+		Version:    1,
+		Body:       input.Body,
+		Variables:  datatypes.JSONSlice[models.PromptVariable](promptVariables(input.Variables)),
+		ChangeNote: input.ChangeNote,
+		CreatedBy:  &actorID,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	data.Current = version
+	return data, nil
+}
+
+// UpdatePromptTemplate only changes templates the caller may write: shared
+// ones are reserved to platform admins.
+func (s *mPromptTemplateService) UpdatePromptTemplate(id int64, input *dto.UpdatePromptTemplateDto) (*models.MPromptTemplate, error) {
+	updates := map[string]interface{}{}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" || utf8.RuneCountInString(name) > maxPromptTemplateName {
+			return nil, fmt.Errorf("%w: name wajib diisi, maksimal %d karakter", ErrPromptTemplateInvalid, maxPromptTemplateName)
+		}
+		updates["name"] = name
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.IsDefault != nil {
+		updates["is_default"] = *input.IsDefault
+	}
+	if input.IsActive != nil {
+		updates["isactive"] = *input.IsActive
+	}
+	if input.CurrentVersion != nil {
+		if _, err := s.repo.FindPromptTemplateVersion(id, *input.CurrentVersion); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		updates["current_version"] = *input.CurrentVersion
+	}
+	if len(updates) == 0 {
+		return s.GetPromptTemplateByID(id)
+	}
+
+	data, err := s.repo.UpdatePromptTemplate(id, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if data.IsDefault {
+		if err := s.repo.ClearDefaultPromptTemplates(data.Kind, data.SchoolID, data.ID); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+	return s.GetPromptTemplateByID(id)
+}
+
+func (s *mPromptTemplateService) GetPromptTemplates(filter dto.PromptTemplateFilterDto) ([]models.MPromptTemplate, queryspec.Page, error) {
+	repo := s.repo
+	if filter.Kind != "" {
+		repo = repo.WithWhere("m_prompt_template.kind = ?", filter.Kind)
+	}
+	if filter.Name != "" {
+		repo = repo.WithWhere("m_prompt_template.name ILIKE ?", "%"+filter.Name+"%")
+	}
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountPromptTemplates()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindPromptTemplatePage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// GetPromptTemplateByID returns the template with its current version.
+func (s *mPromptTemplateService) GetPromptTemplateByID(id int64) (*models.MPromptTemplate, error) {
+	data, err := s.repo.FindPromptTemplateByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	data.Current, err = s.repo.FindPromptTemplateVersion(data.ID, data.CurrentVersion)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// CreatePromptTemplateVersion stores a new version and makes it current.
+// Earlier versions are kept, as materials and runs still refer to them.
+func (s *mPromptTemplateService) CreatePromptTemplateVersion(actorID int64, id int64, input *dto.CreatePromptTemplateVersionDto) (*models.TPromptTemplateVersion, error) {
+	if _, err := s.repo.FindPromptTemplateByID(id); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := validatePromptBody(input.Body, input.Variables); err != nil {
+		return nil, err
+	}
+
+	count, err := s.repo.CountPromptTemplateVersions(id, queryspec.Spec{})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	next := int(count) + 1
+
+	if _, err := s.repo.UpdatePromptTemplate(id, map[string]interface{}{"current_version": next}); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	data, err := s.repo.InsertPromptTemplateVersion(&models.TPromptTemplateVersion{
+		TemplateID: id,
+		Version:    next,
+		Body:       input.Body,
+		Variables:  datatypes.JSONSlice[models.PromptVariable](promptVariables(input.Variables)),
+		ChangeNote: input.ChangeNote,
+		CreatedBy:  &actorID,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *mPromptTemplateService) GetPromptTemplateVersions(id int64, spec queryspec.Spec) ([]models.TPromptTemplateVersion, queryspec.Page, error) {
+	if _, err := s.repo.FindPromptTemplateByID(id); err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	total, err := s.repo.CountPromptTemplateVersions(id, spec)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := s.repo.FindPromptTemplateVersionPage(id, spec)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *mPromptTemplateService) GetPromptTemplateVersion(id int64, version int) (*models.TPromptTemplateVersion, error) {
+	if _, err := s.repo.FindPromptTemplateByID(id); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	data, err := s.repo.FindPromptTemplateVersion(id, version)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// RenderPromptTemplate previews a version. Unlike a generation run, every
+// variable is filled in by the caller.
+func (s *mPromptTemplateService) RenderPromptTemplate(id int64, input *dto.RenderPromptTemplateDto) (*dto.RenderedPromptTemplateDto, error) {
+	version, err := s.findVersion(id, input.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt, err := generation.Render(version.Body, version.Variables, input.Variables)
+	if err != nil {
+		return nil, err
+	}
+	return &dto.RenderedPromptTemplateDto{
+		TemplateID: id,
+		VersionID:  version.ID,
+		Version:    version.Version,
+		Prompt:     prompt,
+	}, nil
+}
+
+func (s *mPromptTemplateService) CreatePromptEvaluation(actorID int64, id int64, input *dto.CreatePromptEvaluationDto) (*models.TPromptEvaluation, error) {
+	notes := strings.TrimSpace(input.Notes)
+	if notes == "" || utf8.RuneCountInString(notes) > maxPromptEvaluation {
+		return nil, fmt.Errorf("%w: notes wajib diisi, maksimal %d karakter", ErrPromptTemplateInvalid, maxPromptEvaluation)
+	}
+	if input.Rating != nil && (*input.Rating < 1 || *input.Rating > 5) {
+		return nil, fmt.Errorf("%w: rating harus antara 1 dan 5", ErrPromptTemplateInvalid)
+	}
+
+	version, err := s.findVersion(id, input.Version)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.InsertPromptEvaluation(&models.TPromptEvaluation{
+		TemplateID: id,
+		VersionID:  version.ID,
+		Rating:     input.Rating,
+		Notes:      notes,
+		CreatedBy:  actorID,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	data.Version = version
+	return data, nil
+}
+
+func (s *mPromptTemplateService) GetPromptEvaluations(id int64, spec queryspec.Spec) ([]models.TPromptEvaluation, queryspec.Page, error) {
+	if _, err := s.repo.FindPromptTemplateByID(id); err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	total, err := s.repo.CountPromptEvaluations(id, spec)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := s.repo.FindPromptEvaluationPage(id, spec)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// findVersion returns a version of a visible template, the current one
+// when number is nil.
+func (s *mPromptTemplateService) findVersion(id int64, number *int) (*models.TPromptTemplateVersion, error) {
+	template, err := s.repo.FindPromptTemplateByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	version := template.CurrentVersion
+	if number != nil {
+		version = *number
+	}
+
+	data, err := s.repo.FindPromptTemplateVersion(id, version)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// checkMaterialPrompt makes sure materials only reference versions of
+// material templates the caller can see.
+func checkMaterialPrompt(prompts sql.MPromptTemplateRepository, versionID int64) error {
+	version, err := prompts.FindPromptTemplateVersionByID(versionID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	template, err := prompts.FindPromptTemplateByID(version.TemplateID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if template.Kind != constant.GenerationKindMaterial {
+		return fmt.Errorf("%w: template %d bukan template materi", ErrPromptTemplateInvalid, template.ID)
+	}
+	return nil
+}
+
+func validatePromptBody(body string, vars []models.PromptVariable) error {
+	if strings.TrimSpace(body) == "" || utf8.RuneCountInString(body) > maxPromptTemplateBody {
+		return fmt.Errorf("%w: body wajib diisi, maksimal %d karakter", ErrPromptTemplateInvalid, maxPromptTemplateBody)
+	}
+	return generation.ValidateTemplate(body, vars)
+}
+
+// promptVariables keeps versions without variables from storing null.
+func promptVariables(vars []models.PromptVariable) []models.PromptVariable {
+	if vars == nil {
+		return []models.PromptVariable{}
+	}
+	return vars
+}
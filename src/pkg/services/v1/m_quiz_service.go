@@ -0,0 +1,774 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/quiz"
+	"jk-api/pkg/repository/adapter/sql"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	maxQuizTitle    = 150
+	maxQuizAttempts = 100
+	maxQuizItems    = 200
+	// quizWrongAnswers is how many common wrong answers analytics list per
+	// item.
+	quizWrongAnswers = 5
+)
+
+var (
+	ErrQuizInvalid           = errors.New("kuis tidak valid")
+	ErrQuizInUse             = errors.New("kuis sudah dikerjakan siswa")
+	ErrQuizAttemptsExhausted = errors.New("kesempatan mengerjakan kuis sudah habis")
+	ErrQuizAttemptSubmitted  = errors.New("percobaan kuis sudah dikumpulkan")
+)
+
+type MQuizService interface {
+	WithTx(tx *gorm.DB) MQuizService
+
+	CreateQuiz(actorID int64, input *dto.CreateQuizDto) (*models.MQuiz, error)
+	UpdateQuiz(id int64, input *dto.UpdateQuizDto) (*models.MQuiz, error)
+	DeleteQuiz(id int64) error
+	GetQuizzes(filter dto.QuizFilterDto) ([]models.MQuiz, queryspec.Page, error)
+	GetQuizByID(id int64, preview bool) (*models.MQuiz, error)
+
+	CreateQuizItem(quizID int64, input *dto.QuizItemDto) (*models.MQuizItem, error)
+	UpdateQuizItem(quizID int64, id int64, input *dto.QuizItemDto) (*models.MQuizItem, error)
+	DeleteQuizItem(quizID int64, id int64) error
+
+	StartAttempt(actorID int64, quizID int64, preview bool) (*dto.QuizAttemptDto, error)
+	SubmitAttempt(actorID int64, id int64, input *dto.SubmitQuizAttemptDto) (*dto.QuizAttemptDto, error)
+	GetAttempts(quizID int64, filter dto.QuizFilterDto) ([]models.TQuizAttempt, queryspec.Page, error)
+	GetAttemptByID(id int64, filter dto.QuizFilterDto) (*dto.QuizAttemptDto, error)
+	GetQuizAnalytics(id int64) (*dto.QuizAnalyticsDto, error)
+	GetDB() *gorm.DB
+}
+
+type mQuizService struct {
+	repo sql.MQuizRepository
+	tx   *gorm.DB
+}
+
+func NewMQuizService(repo sql.MQuizRepository) MQuizService {
+	return &mQuizService{repo: repo}
+}
+
+func (s *mQuizService) WithTx(tx *gorm.DB) MQuizService {
+	return &mQuizService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *mQuizService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// --- 🔧 Quizzes ---
+
+// CreateQuiz adds a draft quiz to a sub-lesson of a course the caller can
+// see.
+func (s *mQuizService) CreateQuiz(actorID int64, input *dto.CreateQuizDto) (*models.MQuiz, error) {
+	if _, err := s.findSubLesson(input.SubLessonID); err != nil {
+		return nil, err
+	}
+
+	data := &models.MQuiz{
+		SubLessonID:    input.SubLessonID,
+		Title:          strings.TrimSpace(input.Title),
+		Description:    input.Description,
+		Status:         constant.ContentDraft,
+		ShuffleItems:   input.ShuffleItems,
+		ShuffleOptions: input.ShuffleOptions,
+		MaxAttempts:    input.MaxAttempts,
+		ScorePolicy:    input.ScorePolicy,
+		CreatedBy:      &actorID,
+	}
+	if data.ScorePolicy == "" {
+		data.ScorePolicy = constant.QuizScoreHighest
+	}
+	if err := checkQuiz(data); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.InsertQuiz(data)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// UpdateQuiz changes a quiz. Only quizzes with items can be published, and
+// the score policy is fixed once students submitted an attempt, as their
+// course scores were counted with it.
+func (s *mQuizService) UpdateQuiz(id int64, input *dto.UpdateQuizDto) (*models.MQuiz, error) {
+	data, err := s.findQuiz(id, true)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	if input.Title != nil {
+		data.Title = strings.TrimSpace(*input.Title)
+		updates["title"] = data.Title
+	}
+	if input.Description != nil {
+		updates["description"] = *input.Description
+	}
+	if input.ShuffleItems != nil {
+		updates["shuffle_items"] = *input.ShuffleItems
+	}
+	if input.ShuffleOptions != nil {
+		updates["shuffle_options"] = *input.ShuffleOptions
+	}
+	if input.MaxAttempts != nil {
+		data.MaxAttempts = *input.MaxAttempts
+		updates["max_attempts"] = data.MaxAttempts
+	}
+	if input.ScorePolicy != nil && *input.ScorePolicy != data.ScorePolicy {
+		submitted, err := s.repo.CountSubmittedAttempts(id)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		if submitted > 0 {
+			return nil, fmt.Errorf("%w: score_policy tidak bisa diubah", ErrQuizInUse)
+		}
+		data.ScorePolicy = *input.ScorePolicy
+		updates["score_policy"] = data.ScorePolicy
+	}
+	if err := checkQuiz(data); err != nil {
+		return nil, err
+	}
+
+	if input.Status != nil {
+		if !slices.Contains([]string{constant.ContentDraft, constant.ContentPublished, constant.ContentArchived}, *input.Status) {
+			return nil, fmt.Errorf("%w: status tidak dikenal", ErrQuizInvalid)
+		}
+		if *input.Status == constant.ContentPublished {
+			items, err := s.repo.FindQuizItems(id)
+			if err != nil {
+				return nil, gorm_err.TranslateGormError(err)
+			}
+			if len(items) == 0 {
+				return nil, fmt.Errorf("%w: kuis tanpa soal tidak bisa diterbitkan", ErrQuizInvalid)
+			}
+		}
+		updates["status"] = *input.Status
+	}
+
+	if len(updates) == 0 {
+		return data, nil
+	}
+	data, err = s.repo.UpdateQuiz(id, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// DeleteQuiz removes a quiz no student has submitted yet; quizzes that
+// counted towards course scores are archived instead.
+func (s *mQuizService) DeleteQuiz(id int64) error {
+	if _, err := s.findQuiz(id, true); err != nil {
+		return err
+	}
+	if err := s.checkUnused(id); err != nil {
+		return err
+	}
+	return gorm_err.TranslateGormError(s.repo.RemoveQuiz(id))
+}
+
+func (s *mQuizService) GetQuizzes(filter dto.QuizFilterDto) ([]models.MQuiz, queryspec.Page, error) {
+	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithPublishedOnly()
+	}
+	if filter.SubLessonID != 0 {
+		repo = repo.WithWhere("m_quiz.sub_lesson_id = ?", filter.SubLessonID)
+	}
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountQuizzes()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindQuizPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// GetQuizByID returns a quiz. Teachers get its items with their answers;
+// students only see items through their attempts.
+func (s *mQuizService) GetQuizByID(id int64, preview bool) (*models.MQuiz, error) {
+	data, err := s.findQuiz(id, preview)
+	if err != nil {
+		return nil, err
+	}
+	if !preview {
+		return data, nil
+	}
+
+	data.Items, err = s.repo.FindQuizItems(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// --- 🔧 Items ---
+
+func (s *mQuizService) CreateQuizItem(quizID int64, input *dto.QuizItemDto) (*models.MQuizItem, error) {
+	if _, err := s.findQuiz(quizID, true); err != nil {
+		return nil, err
+	}
+
+	items, err := s.repo.FindQuizItems(quizID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if len(items) >= maxQuizItems {
+		return nil, fmt.Errorf("%w: kuis maksimal berisi %d soal", ErrQuizInvalid, maxQuizItems)
+	}
+
+	data := &models.MQuizItem{QuizID: quizID, Points: 1, Position: len(items) + 1}
+	if err := applyQuizItem(data, input, nil); err != nil {
+		return nil, err
+	}
+
+	data, err = s.repo.InsertQuizItem(data)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// UpdateQuizItem rewrites an item. Attempts already submitted keep the
+// grade they got.
+func (s *mQuizService) UpdateQuizItem(quizID int64, id int64, input *dto.QuizItemDto) (*models.MQuizItem, error) {
+	if _, err := s.findQuiz(quizID, true); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.FindQuizItem(quizID, id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := applyQuizItem(data, input, data.Options); err != nil {
+		return nil, err
+	}
+
+	data, err = s.repo.SaveQuizItem(data)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// DeleteQuizItem removes an item of a quiz no student has submitted yet,
+// as that would drop their answers to it.
+func (s *mQuizService) DeleteQuizItem(quizID int64, id int64) error {
+	if _, err := s.findQuiz(quizID, true); err != nil {
+		return err
+	}
+	if err := s.checkUnused(quizID); err != nil {
+		return err
+	}
+	return gorm_err.TranslateGormError(s.repo.RemoveQuizItem(quizID, id))
+}
+
+// --- 🔧 Attempts ---
+
+// StartAttempt returns the student's unsubmitted attempt at the quiz or
+// starts a new one, as long as attempts are left. The order of items and
+// options is fixed when the attempt starts.
+func (s *mQuizService) StartAttempt(actorID int64, quizID int64, preview bool) (*dto.QuizAttemptDto, error) {
+	data, err := s.findQuiz(quizID, preview)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindQuizItems(quizID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	attempt, err := s.repo.FindInProgressAttempt(quizID, actorID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if attempt != nil {
+		return attemptView(data, attempt, items, nil), nil
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("%w: kuis belum memiliki soal", ErrQuizInvalid)
+	}
+	count, err := s.repo.CountUserAttempts(quizID, actorID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if data.MaxAttempts > 0 && count >= int64(data.MaxAttempts) {
+		return nil, ErrQuizAttemptsExhausted
+	}
+
+	itemOrder := make([]int64, 0, len(items))
+	optionOrder := map[int64][]int{}
+	for _, item := range items {
+		itemOrder = append(itemOrder, item.ID)
+		if len(item.Options) == 0 {
+			continue
+		}
+		ids := make([]int, 0, len(item.Options))
+		for _, option := range item.Options {
+			ids = append(ids, option.ID)
+		}
+		if data.ShuffleOptions && item.Type != constant.QuizItemTrueFalse {
+			rand.Shuffle(len(ids), func(i, j int) { ids[i], ids[j] = ids[j], ids[i] })
+		}
+		optionOrder[item.ID] = ids
+	}
+	if data.ShuffleItems {
+		rand.Shuffle(len(itemOrder), func(i, j int) { itemOrder[i], itemOrder[j] = itemOrder[j], itemOrder[i] })
+	}
+
+	attempt, err = s.repo.InsertAttempt(&models.TQuizAttempt{
+		QuizID:      quizID,
+		UserID:      actorID,
+		Number:      int(count) + 1,
+		Status:      constant.QuizAttemptInProgress,
+		ItemOrder:   itemOrder,
+		OptionOrder: datatypes.NewJSONType(optionOrder),
+		StartedAt:   time.Now(),
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return attemptView(data, attempt, items, nil), nil
+}
+
+// SubmitAttempt grades the student's answers to their attempt and adds the
+// change in their kept score, per the quiz's score policy, to their course
+// score through QuizSubmitted. All of the student's attempts at the quiz are
+// locked first, so two attempts submitted at once don't both count against
+// the same previous scores.
+func (s *mQuizService) SubmitAttempt(actorID int64, id int64, input *dto.SubmitQuizAttemptDto) (*dto.QuizAttemptDto, error) {
+	own := s.repo.WithWhere("t_quiz_attempt.user_id = ?", actorID)
+	attempt, err := own.FindAttemptByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.repo.LockUserAttempts(attempt.QuizID, actorID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	attempt, err = own.FindAttemptByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if attempt.Status != constant.QuizAttemptInProgress {
+		return nil, ErrQuizAttemptSubmitted
+	}
+
+	data, err := s.findQuiz(attempt.QuizID, true)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindQuizItems(data.ID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	byID := map[int64]models.MQuizItem{}
+	for _, item := range items {
+		if slices.Contains(attempt.ItemOrder, item.ID) {
+			byID[item.ID] = item
+		}
+	}
+
+	responses := map[int64]models.QuizResponse{}
+	for _, answer := range input.Answers {
+		item, ok := byID[answer.ItemID]
+		if !ok {
+			return nil, fmt.Errorf("%w: soal %d tidak ada di percobaan ini", quiz.ErrInvalidResponse, answer.ItemID)
+		}
+		if _, ok := responses[item.ID]; ok {
+			return nil, fmt.Errorf("%w: soal %d dijawab lebih dari sekali", quiz.ErrInvalidResponse, item.ID)
+		}
+		response := models.QuizResponse{OptionIDs: answer.OptionIDs, Number: answer.Number, Text: answer.Text}
+		if err := quiz.CheckResponse(item, response); err != nil {
+			return nil, err
+		}
+		responses[item.ID] = response
+	}
+
+	var answers []models.TQuizAnswer
+	score, maxScore := 0, 0
+	for _, itemID := range attempt.ItemOrder {
+		item, ok := byID[itemID]
+		if !ok {
+			continue
+		}
+		response := responses[itemID]
+		answer := models.TQuizAnswer{
+			AttemptID: attempt.ID,
+			ItemID:    itemID,
+			Response:  datatypes.NewJSONType(response),
+			IsCorrect: quiz.Grade(item, response),
+		}
+		if answer.IsCorrect {
+			answer.Points = item.Points
+		}
+		score += answer.Points
+		maxScore += item.Points
+		answers = append(answers, answer)
+	}
+
+	previous, err := s.repo.FindSubmittedScores(data.ID, actorID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	delta := quiz.KeptScore(data.ScorePolicy, append(previous, score)) - quiz.KeptScore(data.ScorePolicy, previous)
+
+	if err := s.repo.InsertAnswers(answers); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	now := time.Now()
+	err = s.repo.UpdateAttempt(attempt.ID, map[string]interface{}{
+		"status":       constant.QuizAttemptSubmitted,
+		"score":        score,
+		"max_score":    maxScore,
+		"submitted_at": now,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	attempt.Status = constant.QuizAttemptSubmitted
+	attempt.Score = score
+	attempt.MaxScore = maxScore
+	attempt.SubmittedAt = &now
+
+	subLesson, err := s.repo.FindSubLessonByID(data.SubLessonID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if subLesson.Lesson == nil {
+		return nil, gorm_err.TranslateGormError(gorm.ErrRecordNotFound)
+	}
+	err = events.Publish(s.GetDB(), events.QuizSubmitted{
+		UserID:      actorID,
+		QuizID:      data.ID,
+		AttemptID:   attempt.ID,
+		CourseID:    subLesson.Lesson.CourseID,
+		Score:       score,
+		MaxScore:    maxScore,
+		ScoreDelta:  delta,
+		SubmittedAt: now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return attemptView(data, attempt, items, answers), nil
+}
+
+func (s *mQuizService) GetAttempts(quizID int64, filter dto.QuizFilterDto) ([]models.TQuizAttempt, queryspec.Page, error) {
+	if _, err := s.findQuiz(quizID, filter.Preview); err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	repo := s.repo.WithWhere("t_quiz_attempt.quiz_id = ?", quizID)
+	if filter.UserID != 0 {
+		repo = repo.WithWhere("t_quiz_attempt.user_id = ?", filter.UserID)
+	}
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountAttempts()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindAttemptPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// GetAttemptByID returns an attempt with its items and, once submitted, the
+// graded answers. Students only find their own attempts; teachers those of
+// their school.
+func (s *mQuizService) GetAttemptByID(id int64, filter dto.QuizFilterDto) (*dto.QuizAttemptDto, error) {
+	repo := s.repo
+	if filter.UserID != 0 {
+		repo = repo.WithWhere("t_quiz_attempt.user_id = ?", filter.UserID)
+	}
+	attempt, err := repo.FindAttemptByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	data, err := s.findQuiz(attempt.QuizID, true)
+	if err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindQuizItems(data.ID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	var answers []models.TQuizAnswer
+	if attempt.Status == constant.QuizAttemptSubmitted {
+		answers, err = s.repo.FindAnswers(attempt.ID)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+	return attemptView(data, attempt, items, answers), nil
+}
+
+// --- 🔧 Analytics ---
+
+// GetQuizAnalytics sums up the submitted attempts at a quiz by students of
+// the caller's school: how often each item was answered right, how often
+// each option was picked and the most common wrong answers.
+func (s *mQuizService) GetQuizAnalytics(id int64) (*dto.QuizAnalyticsDto, error) {
+	if _, err := s.findQuiz(id, true); err != nil {
+		return nil, err
+	}
+	items, err := s.repo.FindQuizItems(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	summary, err := s.repo.FindAttemptSummary(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	stats, err := s.repo.FindItemStats(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	picks, err := s.repo.FindOptionPicks(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	wrong, err := s.repo.FindWrongResponses(id, quizWrongAnswers)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	statByItem := map[int64]sql.QuizItemStat{}
+	for _, stat := range stats {
+		statByItem[stat.ItemID] = stat
+	}
+	type pickKey struct {
+		itemID   int64
+		optionID int
+	}
+	pickCount := map[pickKey]int64{}
+	for _, pick := range picks {
+		pickCount[pickKey{pick.ItemID, pick.OptionID}] = pick.Picks
+	}
+	wrongByItem := map[int64][]dto.QuizWrongAnswerDto{}
+	for _, w := range wrong {
+		wrongByItem[w.ItemID] = append(wrongByItem[w.ItemID], dto.QuizWrongAnswerDto{Response: w.Response, Count: w.Count})
+	}
+
+	data := &dto.QuizAnalyticsDto{
+		QuizID:       id,
+		Attempts:     summary.Attempts,
+		Students:     summary.Students,
+		AverageScore: summary.AverageScore,
+		Items:        make([]dto.QuizItemAnalyticsDto, 0, len(items)),
+	}
+	for _, item := range items {
+		data.MaxScore += item.Points
+
+		stat := statByItem[item.ID]
+		row := dto.QuizItemAnalyticsDto{
+			ItemID:      item.ID,
+			Type:        item.Type,
+			Prompt:      item.Prompt,
+			Answers:     stat.Answers,
+			Correct:     stat.Correct,
+			CommonWrong: wrongByItem[item.ID],
+		}
+		if stat.Answers > 0 {
+			row.CorrectRate = float64(stat.Correct) / float64(stat.Answers)
+			row.AveragePoint = float64(stat.Points) / float64(stat.Answers)
+		}
+		for _, option := range item.Options {
+			row.Options = append(row.Options, dto.QuizOptionAnalyticsDto{
+				ID:      option.ID,
+				Text:    option.Text,
+				Correct: option.Correct,
+				Picks:   pickCount[pickKey{item.ID, option.ID}],
+			})
+		}
+		data.Items = append(data.Items, row)
+	}
+	return data, nil
+}
+
+// --- 🔧 Helpers ---
+
+// findQuiz returns a quiz of a course the caller can see. Unless
+// previewing, the quiz, its lesson and its course must be published.
+func (s *mQuizService) findQuiz(id int64, preview bool) (*models.MQuiz, error) {
+	repo := s.repo
+	if !preview {
+		repo = repo.WithPublishedOnly()
+	}
+	data, err := repo.FindQuizByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// findSubLesson returns a sub-lesson of a course the caller can see.
+func (s *mQuizService) findSubLesson(id int64) (*models.MSubLesson, error) {
+	subLesson, err := s.repo.FindSubLessonByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if subLesson.Lesson == nil {
+		return nil, gorm_err.TranslateGormError(gorm.ErrRecordNotFound)
+	}
+	if _, err := s.repo.FindCourseByID(subLesson.Lesson.CourseID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return subLesson, nil
+}
+
+func (s *mQuizService) checkUnused(quizID int64) error {
+	submitted, err := s.repo.CountSubmittedAttempts(quizID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if submitted > 0 {
+		return fmt.Errorf("%w: arsipkan kuis ini sebagai gantinya", ErrQuizInUse)
+	}
+	return nil
+}
+
+func checkQuiz(data *models.MQuiz) error {
+	if data.Title == "" || utf8.RuneCountInString(data.Title) > maxQuizTitle {
+		return fmt.Errorf("%w: title wajib diisi, maksimal %d karakter", ErrQuizInvalid, maxQuizTitle)
+	}
+	if data.MaxAttempts < 0 || data.MaxAttempts > maxQuizAttempts {
+		return fmt.Errorf("%w: max_attempts harus antara 0 dan %d", ErrQuizInvalid, maxQuizAttempts)
+	}
+	if !slices.Contains(constant.QuizScorePolicies, data.ScorePolicy) {
+		return fmt.Errorf("%w: score_policy harus salah satu dari %s", ErrQuizInvalid, strings.Join(constant.QuizScorePolicies, ", "))
+	}
+	return nil
+}
+
+// applyQuizItem copies input onto item and checks it; previous are the
+// options the item had, whose IDs are kept.
+func applyQuizItem(item *models.MQuizItem, input *dto.QuizItemDto, previous []models.QuizOption) error {
+	item.Type = input.Type
+	item.Prompt = input.Prompt
+	item.Options = input.Options
+	item.NumericAnswer = input.NumericAnswer
+	item.Tolerance = input.Tolerance
+	item.AcceptedAnswers = input.AcceptedAnswers
+	item.CaseSensitive = input.CaseSensitive
+	item.Explanation = input.Explanation
+	if input.Points != nil {
+		item.Points = *input.Points
+	}
+	if input.Position != nil {
+		item.Position = *input.Position
+	}
+
+	if item.Type == constant.QuizItemTrueFalse {
+		if input.TrueFalseAnswer == nil {
+			return fmt.Errorf("%w: true_false_answer wajib diisi", quiz.ErrInvalidItem)
+		}
+		item.Options = quiz.TrueFalseOptions(*input.TrueFalseAnswer)
+		previous = item.Options
+	}
+	return quiz.Prepare(item, previous)
+}
+
+// attemptView lays out an attempt in the order it was shuffled into. Items
+// added after the attempt started are left out, and so are the right
+// answers until it is submitted.
+func attemptView(data *models.MQuiz, attempt *models.TQuizAttempt, items []models.MQuizItem, answers []models.TQuizAnswer) *dto.QuizAttemptDto {
+	byID := map[int64]models.MQuizItem{}
+	for _, item := range items {
+		byID[item.ID] = item
+	}
+	answerByItem := map[int64]models.TQuizAnswer{}
+	for _, answer := range answers {
+		answerByItem[answer.ItemID] = answer
+	}
+	optionOrder := attempt.OptionOrder.Data()
+
+	view := &dto.QuizAttemptDto{TQuizAttempt: *attempt, Title: data.Title, Items: []dto.QuizAttemptItemDto{}}
+	for _, itemID := range attempt.ItemOrder {
+		item, ok := byID[itemID]
+		if !ok {
+			continue
+		}
+		row := dto.QuizAttemptItemDto{ID: item.ID, Type: item.Type, Prompt: item.Prompt, Points: item.Points}
+
+		options := slices.Clone([]models.QuizOption(item.Options))
+		order := optionOrder[item.ID]
+		slices.SortStableFunc(options, func(a, b models.QuizOption) int {
+			return optionRank(order, a.ID) - optionRank(order, b.ID)
+		})
+		for _, option := range options {
+			row.Options = append(row.Options, dto.QuizAttemptOption{ID: option.ID, Text: option.Text})
+		}
+
+		if answer, ok := answerByItem[item.ID]; ok {
+			row.Answer = &dto.QuizAttemptAnswerDto{
+				Response:      answer.Response.Data(),
+				IsCorrect:     answer.IsCorrect,
+				Points:        answer.Points,
+				NumericAnswer: item.NumericAnswer,
+				Accepted:      item.AcceptedAnswers,
+				Explanation:   item.Explanation,
+			}
+			for _, option := range item.Options {
+				if option.Correct {
+					row.Answer.CorrectOption = append(row.Answer.CorrectOption, option.ID)
+				}
+			}
+		}
+		view.Items = append(view.Items, row)
+	}
+	return view
+}
+
+// optionRank is where an option was shuffled to; options added after the
+// attempt started go last.
+func optionRank(order []int, id int) int {
+	if i := slices.Index(order, id); i >= 0 {
+		return i
+	}
+	return len(order)
+}
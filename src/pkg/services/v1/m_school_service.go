@@ -0,0 +1,139 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type MSchoolService interface {
+	WithTx(tx *gorm.DB) MSchoolService
+
+	CreateMSchool(input *models.MSchool) (*models.MSchool, error)
+	UpdateMSchool(id int64, updates map[string]interface{}) (*models.MSchool, error)
+	DeleteMSchool(id int64) error
+	GetAllMSchools(filter dto.MSchoolFilterDto) ([]models.MSchool, queryspec.Page, error)
+	GetMSchoolByID(id int64) (*models.MSchool, error)
+	GetDB() *gorm.DB
+}
+
+type mSchoolService struct {
+	repo sql.MSchoolRepository
+	tx   *gorm.DB
+}
+
+func NewMSchoolService(repo sql.MSchoolRepository) MSchoolService {
+	return &mSchoolService{repo: repo}
+}
+
+func (s *mSchoolService) WithTx(tx *gorm.DB) MSchoolService {
+	return &mSchoolService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *mSchoolService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+func (s *mSchoolService) CreateMSchool(input *models.MSchool) (*models.MSchool, error) {
+	input.Name = strings.TrimSpace(input.Name)
+	if input.Name == "" {
+		return nil, fmt.Errorf("nama sekolah wajib diisi")
+	}
+	input.Code = normalizeSchoolCode(input.Code)
+	input.IsActive = true
+
+	data, err := s.repo.InsertMSchool(input)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *mSchoolService) UpdateMSchool(id int64, updates map[string]interface{}) (*models.MSchool, error) {
+	if name, ok := updates["name"].(string); ok {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("nama sekolah wajib diisi")
+		}
+		updates["name"] = name
+	}
+	if code, ok := updates["code"].(string); ok {
+		updates["code"] = normalizeSchoolCode(&code)
+	}
+
+	data, err := s.repo.UpdateMSchool(id, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// DeleteMSchool refuses to drop a school that still owns users or classes;
+// deactivate it instead.
+func (s *mSchoolService) DeleteMSchool(id int64) error {
+	members, err := s.repo.CountMSchoolMembers(id)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if members > 0 {
+		return fmt.Errorf("sekolah masih memiliki user atau kelas, nonaktifkan saja")
+	}
+
+	err = s.repo.RemoveMSchool(id)
+	return gorm_err.TranslateGormError(err)
+}
+
+func (s *mSchoolService) GetAllMSchools(filter dto.MSchoolFilterDto) ([]models.MSchool, queryspec.Page, error) {
+	repo := s.repo
+	if filter.Name != "" {
+		repo = repo.WithWhere("name ILIKE ?", "%"+filter.Name+"%")
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountMSchools()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindMSchoolPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *mSchoolService) GetMSchoolByID(id int64) (*models.MSchool, error) {
+	data, err := s.repo.FindMSchoolByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// normalizeSchoolCode upper-cases the code and turns a blank one into NULL so
+// schools without a code don't collide on the unique index.
+func normalizeSchoolCode(code *string) *string {
+	if code == nil {
+		return nil
+	}
+	normalized := strings.ToUpper(strings.TrimSpace(*code))
+	if normalized == "" {
+		return nil
+	}
+	return &normalized
+}
@@ -1,11 +1,13 @@
 package services
 
 import (
-	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
 	"jk-api/pkg/repository/adapter/sql"
 	"time"
 
@@ -18,7 +20,7 @@ type MSubLessonService interface {
 	CreateMSubLesson(input *models.MSubLesson) (*models.MSubLesson, error)
 	UpdateMSubLesson(id int64, updates map[string]interface{}) (*models.MSubLesson, error)
 	DeleteMSubLesson(id int64) error
-		GetAllMSubLessons(filter dto.MSubLessonFilterDto) ([]models.MSubLesson, error)
+		GetAllMSubLessons(filter dto.MSubLessonFilterDto) ([]models.MSubLesson, queryspec.Page, error)
 	GetMSubLessonByID(id int64, filter dto.MSubLessonFilterDto) (*models.MSubLesson, error)
 	GetMSubLessonsByIDs(ids []int64) ([]*models.MSubLesson, error)
 	GetDB() *gorm.DB
@@ -27,6 +29,12 @@ type MSubLessonService interface {
 	BulkDeleteMSubLessons(ids []int64) error
 }
 
+// publishedSubLessonSQL limits sub-lessons to those of published lessons in
+// a published course.
+const publishedSubLessonSQL = `m_sub_lesson.lesson_id IN (
+	SELECT m_lesson.id FROM m_lesson WHERE ` + publishedLessonSQL + `
+)`
+
 type mSubLessonService struct {
 	repo sql.MSubLessonRepository
 	tx   *gorm.DB
@@ -58,6 +66,14 @@ func (s *mSubLessonService) CreateMSubLesson(input *models.MSubLesson) (*models.
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+
+	courseIDs, err := s.repo.FindCourseIDsByLessonIDs([]int64{data.LessonID})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.publishStructureChanged(courseIDs...); err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
@@ -72,47 +88,75 @@ func (s *mSubLessonService) UpdateMSubLesson(id int64, updates map[string]interf
 		return nil, gorm_err.TranslateGormError(err)
 	}
 
+	beforeCourseIDs, err := s.affectedCourseIDs([]int64{id}, updates)
+	if err != nil {
+		return nil, err
+	}
+
 	updates["updated_at"] = time.Now()
-	fmt.Println(updates)
 
 	data, err := s.repo.UpdateMSubLesson(id, updates)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+
+	afterCourseIDs, err := s.affectedCourseIDs([]int64{id}, updates)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publishStructureChanged(append(beforeCourseIDs, afterCourseIDs...)...); err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
 func (s *mSubLessonService) DeleteMSubLesson(id int64) error {
-	err := s.repo.RemoveMSubLesson(id)
-	return gorm_err.TranslateGormError(err)
+	courseIDs, err := s.repo.FindCourseIDsBySubLessonIDs([]int64{id})
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	if err := s.repo.RemoveMSubLesson(id); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.publishStructureChanged(courseIDs...)
 }
 
-func (s *mSubLessonService) GetAllMSubLessons(filter dto.MSubLessonFilterDto) ([]models.MSubLesson, error) {
+func (s *mSubLessonService) GetAllMSubLessons(filter dto.MSubLessonFilterDto) ([]models.MSubLesson, queryspec.Page, error) {
 	repo := s.repo
-
-	if filter.Preload {
-		repo = repo.WithPreloads("Lesson")
+	if !filter.Preview {
+		repo = repo.WithWhere(publishedSubLessonSQL, constant.ContentPublished, constant.ContentPublished)
+	}
+	if filter.Name != "" {
+		repo = repo.WithWhere("m_sub_lesson.title ILIKE ?", "%"+filter.Name+"%")
+	}
+	if filter.ShowDeleted {
+		repo = repo.WithUnscoped().WithWhere("m_sub_lesson.deleted_at IS NOT NULL")
 	}
+	repo = repo.WithSpec(filter.Spec)
 
-	if filter.Sort != "" && filter.Order != "" {
-		orderClause := filter.Sort + " " + filter.Order
-		repo = repo.WithOrder(orderClause)
+	total, err := repo.CountMSubLessons()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
 
-	if filter.ShowDeleted {
-		repo = repo.WithUnscoped().WithWhere("m_sub_lessons.deleted_at IS NOT NULL")
+	if filter.Preload {
+		repo = repo.WithPreloads("Lesson")
 	}
-	fmt.Println("filter", filter)
 
-	data, err := repo.FindMSubLessons()
+	data, page, err := repo.FindMSubLessonPage()
 	if err != nil {
-		return nil, gorm_err.TranslateGormError(err)
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
-	return data, nil
+	page.Total = total
+	return data, page, nil
 }
 
 func (s *mSubLessonService) GetMSubLessonByID(id int64, filter dto.MSubLessonFilterDto) (*models.MSubLesson, error) {
 	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere(publishedSubLessonSQL, constant.ContentPublished, constant.ContentPublished)
+	}
 	if filter.Preload {
 		repo = repo.WithPreloads("Lesson")
 	}
@@ -128,6 +172,19 @@ func (s *mSubLessonService) BulkCreateMSubLessons(data []*models.MSubLesson) ([]
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+
+	lessonIDs := make([]int64, 0, len(datas))
+	for _, d := range datas {
+		lessonIDs = append(lessonIDs, d.LessonID)
+	}
+
+	courseIDs, err := s.repo.FindCourseIDsByLessonIDs(lessonIDs)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.publishStructureChanged(courseIDs...); err != nil {
+		return nil, err
+	}
 	return datas, nil
 }
 
@@ -138,8 +195,20 @@ func (s *mSubLessonService) BulkUpdateMSubLessons(ids []int64, updates map[strin
 		repo = s.repo.WithUnscoped()
 	}
 
-	err := repo.UpdateManyMSubLessons(ids, updates)
-	return gorm_err.TranslateGormError(err)
+	beforeCourseIDs, err := s.affectedCourseIDs(ids, updates)
+	if err != nil {
+		return err
+	}
+
+	if err := repo.UpdateManyMSubLessons(ids, updates); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	afterCourseIDs, err := s.affectedCourseIDs(ids, updates)
+	if err != nil {
+		return err
+	}
+	return s.publishStructureChanged(append(beforeCourseIDs, afterCourseIDs...)...)
 }
 
 func (s *mSubLessonService) GetMSubLessonsByIDs(ids []int64) ([]*models.MSubLesson, error) {
@@ -151,6 +220,37 @@ func (s *mSubLessonService) GetMSubLessonsByIDs(ids []int64) ([]*models.MSubLess
 }
 
 func (s *mSubLessonService) BulkDeleteMSubLessons(ids []int64) error {
-	err := s.repo.RemoveManyMSubLessons(ids)
-	return gorm_err.TranslateGormError(err)
+	courseIDs, err := s.repo.FindCourseIDsBySubLessonIDs(ids)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	if err := s.repo.RemoveManyMSubLessons(ids); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.publishStructureChanged(courseIDs...)
+}
+
+// affectedCourseIDs returns the courses owning the sub-lessons when the
+// updates can change which course they count towards, and nil otherwise.
+func (s *mSubLessonService) affectedCourseIDs(ids []int64, updates map[string]interface{}) ([]int64, error) {
+	_, movesLesson := updates["lesson_id"]
+	_, togglesDeleted := updates["deleted_at"]
+	if !movesLesson && !togglesDeleted {
+		return nil, nil
+	}
+
+	courseIDs, err := s.repo.FindCourseIDsBySubLessonIDs(ids)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return courseIDs, nil
+}
+
+func (s *mSubLessonService) publishStructureChanged(courseIDs ...int64) error {
+	event := events.NewCourseStructureChanged(courseIDs...)
+	if len(event.CourseIDs) == 0 {
+		return nil
+	}
+	return events.Publish(s.GetDB(), event)
 }
\ No newline at end of file
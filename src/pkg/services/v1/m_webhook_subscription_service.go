@@ -0,0 +1,163 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/webhook"
+	"jk-api/pkg/repository/adapter/sql"
+	"net/netip"
+	"net/url"
+	"strings"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+type MWebhookSubscriptionService interface {
+	WithTx(tx *gorm.DB) MWebhookSubscriptionService
+
+	CreateMWebhookSubscription(input *models.MWebhookSubscription) (*models.MWebhookSubscription, error)
+	UpdateMWebhookSubscription(id int64, updates map[string]interface{}) (*models.MWebhookSubscription, error)
+	DeleteMWebhookSubscription(id int64) error
+	GetAllMWebhookSubscriptions(filter dto.MWebhookSubscriptionFilterDto) ([]models.MWebhookSubscription, queryspec.Page, error)
+	GetMWebhookSubscriptionByID(id int64) (*models.MWebhookSubscription, error)
+	GetDB() *gorm.DB
+}
+
+type mWebhookSubscriptionService struct {
+	repo sql.MWebhookSubscriptionRepository
+	tx   *gorm.DB
+}
+
+func NewMWebhookSubscriptionService(repo sql.MWebhookSubscriptionRepository) MWebhookSubscriptionService {
+	return &mWebhookSubscriptionService{repo: repo}
+}
+
+func (s *mWebhookSubscriptionService) WithTx(tx *gorm.DB) MWebhookSubscriptionService {
+	return &mWebhookSubscriptionService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *mWebhookSubscriptionService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+func (s *mWebhookSubscriptionService) CreateMWebhookSubscription(input *models.MWebhookSubscription) (*models.MWebhookSubscription, error) {
+	if input.Name == "" {
+		return nil, fmt.Errorf("nama webhook wajib diisi")
+	}
+	if err := validateWebhookURL(input.URL); err != nil {
+		return nil, err
+	}
+	if err := validateWebhookEvents(input.Events); err != nil {
+		return nil, err
+	}
+
+	if input.Secret == "" {
+		secret, err := webhook.GenerateSecret()
+		if err != nil {
+			return nil, err
+		}
+		input.Secret = secret
+	}
+	input.IsActive = true
+
+	data, err := s.repo.InsertMWebhookSubscription(input)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *mWebhookSubscriptionService) UpdateMWebhookSubscription(id int64, updates map[string]interface{}) (*models.MWebhookSubscription, error) {
+	if name, ok := updates["name"].(string); ok && name == "" {
+		return nil, fmt.Errorf("nama webhook wajib diisi")
+	}
+	if rawURL, ok := updates["url"].(string); ok {
+		if err := validateWebhookURL(rawURL); err != nil {
+			return nil, err
+		}
+	}
+	if secret, ok := updates["secret"].(string); ok && secret == "" {
+		return nil, fmt.Errorf("secret webhook tidak boleh kosong")
+	}
+	if events, ok := updates["events"].(datatypes.JSONSlice[string]); ok {
+		if err := validateWebhookEvents(events); err != nil {
+			return nil, err
+		}
+	}
+
+	data, err := s.repo.UpdateMWebhookSubscription(id, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *mWebhookSubscriptionService) DeleteMWebhookSubscription(id int64) error {
+	err := s.repo.RemoveMWebhookSubscription(id)
+	return gorm_err.TranslateGormError(err)
+}
+
+func (s *mWebhookSubscriptionService) GetAllMWebhookSubscriptions(filter dto.MWebhookSubscriptionFilterDto) ([]models.MWebhookSubscription, queryspec.Page, error) {
+	repo := s.repo
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountMWebhookSubscriptions()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindMWebhookSubscriptionPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *mWebhookSubscriptionService) GetMWebhookSubscriptionByID(id int64) (*models.MWebhookSubscription, error) {
+	data, err := s.repo.FindMWebhookSubscriptionByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// validateWebhookURL turns away URLs that obviously point inside the
+// network. Names are only resolved when sending, where the sender refuses
+// internal addresses again.
+func validateWebhookURL(rawURL string) error {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || parsed.Hostname() == "" || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return fmt.Errorf("url webhook %q tidak valid", rawURL)
+	}
+	host := strings.TrimSuffix(strings.ToLower(parsed.Hostname()), ".")
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: %s", webhook.ErrBlockedAddress, host)
+	}
+	if ip, err := netip.ParseAddr(host); err == nil && webhook.Blocked(ip) {
+		return fmt.Errorf("%w: %s", webhook.ErrBlockedAddress, host)
+	}
+	return nil
+}
+
+func validateWebhookEvents(events []string) error {
+	for _, event := range events {
+		if !constant.IsWebhookEvent(event) {
+			return fmt.Errorf("event webhook %q tidak didukung", event)
+		}
+	}
+	return nil
+}
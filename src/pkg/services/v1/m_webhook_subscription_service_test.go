@@ -0,0 +1,33 @@
+package services
+
+import (
+	"errors"
+	"jk-api/internal/webhook"
+	"testing"
+)
+
+func TestValidateWebhookURL(t *testing.T) {
+	tests := []struct {
+		url         string
+		wantErr     bool
+		wantBlocked bool
+	}{
+		{"https://hooks.example.com/bajapro", false, false},
+		{"http://203.0.113.10:8080/hook", false, false},
+		{"ftp://example.com/hook", true, false},
+		{"https:///hook", true, false},
+		{"http://localhost:8080/hook", true, true},
+		{"http://api.LOCALHOST./hook", true, true},
+		{"http://127.0.0.1/hook", true, true},
+		{"http://169.254.169.254/latest/meta-data/", true, true},
+		{"http://10.0.0.5/hook", true, true},
+		{"http://[::1]:9000/hook", true, true},
+	}
+
+	for _, tt := range tests {
+		err := validateWebhookURL(tt.url)
+		if (err != nil) != tt.wantErr || errors.Is(err, webhook.ErrBlockedAddress) != tt.wantBlocked {
+			t.Errorf("validateWebhookURL(%q) = %v", tt.url, err)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"jk-api/internal/database/models"
+	"jk-api/internal/richtext"
+
+	"gorm.io/datatypes"
+)
+
+// setMaterialContent validates raw as the document of material and stores it
+// normalized, together with its plain text.
+func setMaterialContent(material *models.MMaterials, raw []byte) error {
+	doc, err := richtext.Parse(raw)
+	if err != nil {
+		return err
+	}
+	return storeMaterialDoc(material, doc)
+}
+
+// restoreMaterialContent is setMaterialContent for content that was already
+// stored once, such as snapshots and packages written before documents were
+// validated. It never fails on content, only keeping the text of what
+// doesn't fit the schema.
+func restoreMaterialContent(material *models.MMaterials, raw []byte) error {
+	return storeMaterialDoc(material, richtext.FromLegacy(raw))
+}
+
+func storeMaterialDoc(material *models.MMaterials, doc *richtext.Node) error {
+	data, err := doc.JSON()
+	if err != nil {
+		return err
+	}
+	material.Materials = datatypes.JSON(data)
+	material.MaterialsText = richtext.PlainText(doc)
+	return nil
+}
+
+// prepareMaterialUpdates validates a new document in updates and adds the
+// plain text that goes with it.
+func prepareMaterialUpdates(updates map[string]interface{}) error {
+	raw, ok := updates["materials"].(datatypes.JSON)
+	if !ok {
+		return nil
+	}
+	var material models.MMaterials
+	if err := setMaterialContent(&material, raw); err != nil {
+		return err
+	}
+	updates["materials"] = material.Materials
+	updates["materials_text"] = material.MaterialsText
+	return nil
+}
+
+// sameDocument compares two stored documents, ignoring how the JSON is
+// spaced.
+func sameDocument(a []byte, b []byte) bool {
+	var compactA, compactB bytes.Buffer
+	if json.Compact(&compactA, a) != nil || json.Compact(&compactB, b) != nil {
+		return bytes.Equal(a, b)
+	}
+	return bytes.Equal(compactA.Bytes(), compactB.Bytes())
+}
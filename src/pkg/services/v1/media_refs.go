@@ -0,0 +1,70 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+)
+
+// imageMediaURL returns the URL of the uploaded image with id. Models keep
+// their plain URL columns next to the media ids for older clients, and this
+// keeps the two in sync. Protected images have no public URL, so the column
+// is cleared and clients ask for a signed link instead.
+func imageMediaURL(media sql.MMediaRepository, id int64) (string, error) {
+	data, err := media.FindMediaByID(id)
+	if err != nil {
+		return "", gorm_err.TranslateGormError(err)
+	}
+	if !strings.HasPrefix(data.MimeType, "image/") {
+		return "", fmt.Errorf("media %d bukan gambar", id)
+	}
+	return data.URL, nil
+}
+
+// fillMediaURL sets *url to the URL of the image id points to, if any.
+func fillMediaURL(media sql.MMediaRepository, id *int64, url *string) error {
+	if id == nil {
+		return nil
+	}
+	value, err := imageMediaURL(media, *id)
+	if err != nil {
+		return err
+	}
+	*url = value
+	return nil
+}
+
+// syncMediaURL sets updates[urlColumn] from the image referenced by
+// updates[idColumn] when the update changes it.
+func syncMediaURL(media sql.MMediaRepository, updates map[string]interface{}, idColumn string, urlColumn string) error {
+	id, ok := updates[idColumn].(int64)
+	if !ok {
+		return nil
+	}
+	value, err := imageMediaURL(media, id)
+	if err != nil {
+		return err
+	}
+	updates[urlColumn] = value
+	return nil
+}
+
+// checkVideoMedia fails unless the media with id is a video.
+func checkVideoMedia(media sql.MMediaRepository, id int64) error {
+	data, err := media.FindMediaByID(id)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if !strings.HasPrefix(data.MimeType, "video/") {
+		return fmt.Errorf("media %d bukan video", id)
+	}
+	return nil
+}
+
+func sameMediaID(a *int64, b *int64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
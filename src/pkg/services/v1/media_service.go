@@ -0,0 +1,579 @@
+package services
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/imaging"
+	"jk-api/internal/signedurl"
+	"jk-api/internal/storage"
+	"jk-api/pkg/repository/adapter/sql"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// maxImagePixels stops a small, highly compressed image from expanding into
+// gigabytes when it is decoded for thumbnails.
+const maxImagePixels = 40_000_000
+
+var (
+	ErrMediaTooLarge      = errors.New("ukuran file melebihi batas")
+	ErrMediaUploadOffset  = errors.New("offset upload tidak sesuai")
+	ErrMediaUploadExpired = errors.New("upload sudah kedaluwarsa")
+	ErrMediaAccessDenied  = errors.New("akses ke media ditolak")
+)
+
+type MediaService interface {
+	WithTx(tx *gorm.DB) MediaService
+
+	UploadMedia(actorID int64, r io.Reader, protected bool) (*models.MMedia, error)
+	CreateUpload(actorID int64, input *dto.CreateMediaUploadDto) (*models.TMediaUpload, error)
+	AppendUpload(actorID int64, id string, offset int64, chunk io.Reader) (*models.TMediaUpload, error)
+	GetUpload(actorID int64, id string) (*models.TMediaUpload, error)
+	GetMediaByID(id int64) (*models.MMedia, error)
+	CleanupExpiredUploads(now time.Time, limit int) (int, error)
+	SignContentMedia(userID int64, staff bool, input dto.SignMediaURLDto) (*dto.SignedMediaURLDto, error)
+	ResolveSignedMedia(mediaID int64, query url.Values) (*models.MediaFile, error)
+	ReadMedia(key string, offset int64, length int64) (io.ReadCloser, error)
+	GetDB() *gorm.DB
+}
+
+type mediaService struct {
+	repo      sql.MMediaRepository
+	store     storage.Storage
+	signer    *signedurl.Signer
+	uploadDir string
+	maxSize   int64
+	uploadTTL time.Duration
+	tx        *gorm.DB
+}
+
+// NewMediaService stores media in store and links to protected media through
+// signer. Uploads are staged in uploadDir until they are complete.
+func NewMediaService(repo sql.MMediaRepository, store storage.Storage, signer *signedurl.Signer, uploadDir string, maxSize int64, uploadTTL time.Duration) MediaService {
+	return &mediaService{
+		repo:      repo,
+		store:     store,
+		signer:    signer,
+		uploadDir: uploadDir,
+		maxSize:   maxSize,
+		uploadTTL: uploadTTL,
+	}
+}
+
+func (s *mediaService) WithTx(tx *gorm.DB) MediaService {
+	clone := *s
+	clone.repo = s.repo.WithTx(tx)
+	clone.tx = tx
+	return &clone
+}
+
+func (s *mediaService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// UploadMedia stores a file sent in one request.
+func (s *mediaService) UploadMedia(actorID int64, r io.Reader, protected bool) (*models.MMedia, error) {
+	if err := os.MkdirAll(s.uploadDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	f, err := os.CreateTemp(s.uploadDir, "direct-*")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(f.Name())
+	defer f.Close()
+
+	n, err := io.Copy(f, io.LimitReader(r, s.maxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > s.maxSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	return s.storeFile(actorID, f.Name(), protected)
+}
+
+// CreateUpload starts a resumable upload. Chunks are then sent with
+// AppendUpload until all Size bytes have arrived.
+func (s *mediaService) CreateUpload(actorID int64, input *dto.CreateMediaUploadDto) (*models.TMediaUpload, error) {
+	if input.Size <= 0 {
+		return nil, fmt.Errorf("ukuran file wajib diisi")
+	}
+	if input.Size > s.maxSize {
+		return nil, ErrMediaTooLarge
+	}
+
+	if err := os.MkdirAll(s.uploadDir, 0o755); err != nil {
+		return nil, err
+	}
+
+	upload := &models.TMediaUpload{
+		ID:         uuid.NewString(),
+		Filename:   filepath.Base(input.Filename),
+		Size:       input.Size,
+		Protected:  input.Protected,
+		UploadedBy: actorID,
+		ExpiresAt:  time.Now().Add(s.uploadTTL),
+	}
+
+	f, err := os.Create(s.stagingPath(upload.ID))
+	if err != nil {
+		return nil, err
+	}
+	f.Close()
+
+	data, err := s.repo.InsertUpload(upload)
+	if err != nil {
+		os.Remove(s.stagingPath(upload.ID))
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// AppendUpload writes chunk at offset, which must be where the previous
+// chunk ended. The chunk that completes the file turns it into media.
+func (s *mediaService) AppendUpload(actorID int64, id string, offset int64, chunk io.Reader) (*models.TMediaUpload, error) {
+	upload, err := s.repo.LockUpload(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if upload.UploadedBy != actorID {
+		return nil, fmt.Errorf("upload tidak ditemukan")
+	}
+	if upload.MediaID != nil {
+		return s.repo.FindUploadByID(id)
+	}
+	if time.Now().After(upload.ExpiresAt) {
+		return nil, ErrMediaUploadExpired
+	}
+	if offset != upload.Offset {
+		return nil, fmt.Errorf("%w: server berada di byte %d", ErrMediaUploadOffset, upload.Offset)
+	}
+
+	path := s.stagingPath(upload.ID)
+	written, err := appendChunk(path, offset, chunk, upload.Size-offset)
+	if err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{"offset": offset + written}
+	if offset+written == upload.Size {
+		media, err := s.storeFile(actorID, path, upload.Protected)
+		if err != nil {
+			return nil, err
+		}
+		updates["media_id"] = media.ID
+	}
+
+	if err := s.repo.UpdateUpload(id, updates); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if _, done := updates["media_id"]; done {
+		os.Remove(path)
+	}
+
+	data, err := s.repo.FindUploadByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// appendChunk writes chunk to the staging file at offset, dropping anything
+// a failed earlier attempt left behind it. At most remaining bytes are
+// accepted.
+func appendChunk(path string, offset int64, chunk io.Reader, remaining int64) (int64, error) {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	if err := f.Truncate(offset); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(offset, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	written, err := io.Copy(f, io.LimitReader(chunk, remaining+1))
+	if err != nil {
+		return 0, err
+	}
+	if written > remaining {
+		f.Truncate(offset)
+		return 0, ErrMediaTooLarge
+	}
+	return written, nil
+}
+
+func (s *mediaService) GetUpload(actorID int64, id string) (*models.TMediaUpload, error) {
+	data, err := s.repo.FindUploadByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if data.UploadedBy != actorID {
+		return nil, fmt.Errorf("upload tidak ditemukan")
+	}
+	return data, nil
+}
+
+func (s *mediaService) GetMediaByID(id int64) (*models.MMedia, error) {
+	data, err := s.repo.FindMediaByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// CleanupExpiredUploads forgets uploads past their expiry and removes their
+// staging files. Media created by finished uploads is kept.
+func (s *mediaService) CleanupExpiredUploads(now time.Time, limit int) (int, error) {
+	uploads, err := s.repo.FindExpiredUploads(now, limit)
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+
+	for _, upload := range uploads {
+		if err := os.Remove(s.stagingPath(upload.ID)); err != nil && !os.IsNotExist(err) {
+			return 0, err
+		}
+		if err := s.repo.RemoveUpload(upload.ID); err != nil {
+			return 0, gorm_err.TranslateGormError(err)
+		}
+	}
+	return len(uploads), nil
+}
+
+func (s *mediaService) stagingPath(id string) string {
+	return filepath.Join(s.uploadDir, id)
+}
+
+// storeFile validates the staged file at path and moves it into storage,
+// unless a file with the same content and protection was stored before.
+func (s *mediaService) storeFile(actorID int64, path string, protected bool) (*models.MMedia, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return nil, fmt.Errorf("file kosong")
+	}
+
+	// The type is taken from the content; the client's claim is ignored.
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, err
+	}
+	mimeType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	ext, ok := constant.MediaTypes[mimeType]
+	if !ok {
+		return nil, fmt.Errorf("tipe file %s tidak didukung", mimeType)
+	}
+
+	hash := sha256.New()
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(hash, f); err != nil {
+		return nil, err
+	}
+	sum := hex.EncodeToString(hash.Sum(nil))
+
+	existing, err := s.repo.FindMediaByHash(sum, protected)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if existing != nil {
+		return existing, nil
+	}
+
+	ctx := s.GetDB().Statement.Context
+	key := mediaKey(sum, "", ext, protected)
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.store.Put(ctx, key, f, info.Size(), mimeType); err != nil {
+		return nil, err
+	}
+
+	media := &models.MMedia{
+		Hash:       sum,
+		MimeType:   mimeType,
+		Size:       info.Size(),
+		Protected:  protected,
+		StorageKey: key,
+		URL:        s.publicURL(key),
+		UploadedBy: &actorID,
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if err := s.storeVariants(f, media); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.InsertMedia(media)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// storeVariants records the dimensions of images the standard library can
+// decode and stores a scaled copy for every variant smaller than the
+// original. Other files are stored as they are.
+func (s *mediaService) storeVariants(r io.ReadSeeker, media *models.MMedia) error {
+	cfg, format, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil
+	}
+	media.Width, media.Height = cfg.Width, cfg.Height
+	if cfg.Width*cfg.Height > maxImagePixels {
+		return nil
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	img, _, err := imaging.Decode(r)
+	if err != nil {
+		return nil
+	}
+
+	ctx := s.GetDB().Statement.Context
+	for _, variant := range constant.MediaVariants {
+		if max(cfg.Width, cfg.Height) <= variant.MaxSide {
+			continue
+		}
+
+		scaled := imaging.Fit(img, variant.MaxSide)
+		var buf bytes.Buffer
+		mimeType, err := imaging.Encode(&buf, scaled, format)
+		if err != nil {
+			return err
+		}
+
+		key := mediaKey(media.Hash, variant.Name, constant.MediaTypes[mimeType], media.Protected)
+		size := int64(buf.Len())
+		if err := s.store.Put(ctx, key, &buf, size, mimeType); err != nil {
+			return err
+		}
+
+		bounds := scaled.Bounds()
+		media.Variants = append(media.Variants, models.MediaFile{
+			Name:       variant.Name,
+			MimeType:   mimeType,
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+			Size:       size,
+			StorageKey: key,
+			URL:        s.publicURL(key),
+		})
+	}
+	return nil
+}
+
+// publicURL is empty for protected objects, which are only reachable through
+// signed links.
+func (s *mediaService) publicURL(key string) string {
+	if storage.IsProtected(key) {
+		return ""
+	}
+	return s.store.URL(key)
+}
+
+// mediaKey spreads objects over directories by the first byte of their hash.
+// Protected objects live under their own prefix so backends can keep them
+// private.
+func mediaKey(hash string, variant string, ext string, protected bool) string {
+	name := hash
+	if variant != "" {
+		name += "_" + variant
+	}
+	key := hash[:2] + "/" + name + strings.ToLower(ext)
+	if protected {
+		key = storage.ProtectedPrefix + key
+	}
+	return key
+}
+
+// SignContentMedia returns a short-lived link to the media of a material or
+// code question. Students get one while they are enrolled in the course and
+// the content is published; staff get a preview link for any course their
+// school can see.
+func (s *mediaService) SignContentMedia(userID int64, staff bool, input dto.SignMediaURLDto) (*dto.SignedMediaURLDto, error) {
+	scope := constant.MediaScopeEnrolled
+	if staff {
+		scope = constant.MediaScopePreview
+	}
+
+	mediaID, course, err := s.contentMedia(userID, scope, input.Type, input.ID)
+	if err != nil {
+		return nil, err
+	}
+	if scope == constant.MediaScopePreview {
+		if _, err := s.repo.FindVisibleCourse(course.ID); err != nil {
+			return nil, ErrMediaAccessDenied
+		}
+	}
+
+	media, err := s.repo.FindMediaByID(mediaID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if _, err := mediaFile(media, input.Variant); err != nil {
+		return nil, err
+	}
+
+	link, expiresAt := s.signer.URL(signedurl.Claims{
+		MediaID:     mediaID,
+		Variant:     input.Variant,
+		UserID:      userID,
+		ContentType: input.Type,
+		ContentID:   input.ID,
+		Scope:       scope,
+	}, time.Now())
+
+	return &dto.SignedMediaURLDto{URL: link, ExpiresAt: expiresAt}, nil
+}
+
+// ResolveSignedMedia checks a signed link to mediaID and returns the file it
+// grants. Access is checked again, so a link stops working as soon as the
+// enrollment ends or the content is unpublished, even before it expires.
+func (s *mediaService) ResolveSignedMedia(mediaID int64, query url.Values) (*models.MediaFile, error) {
+	claims, err := s.signer.Verify(mediaID, query, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	current, _, err := s.contentMedia(claims.UserID, claims.Scope, claims.ContentType, claims.ContentID)
+	if err != nil {
+		return nil, err
+	}
+	if current != mediaID {
+		return nil, ErrMediaAccessDenied
+	}
+
+	media, err := s.repo.FindMediaByID(mediaID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return mediaFile(media, claims.Variant)
+}
+
+func (s *mediaService) ReadMedia(key string, offset int64, length int64) (io.ReadCloser, error) {
+	return s.store.Read(s.GetDB().Statement.Context, key, offset, length)
+}
+
+// contentMedia returns the media referenced by a piece of content and the
+// course it belongs to. For the enrolled scope the content, its lesson and
+// its course must be published and userID must be enrolled in the course.
+func (s *mediaService) contentMedia(userID int64, scope string, contentType string, contentID int64) (int64, *models.MCourse, error) {
+	var (
+		mediaID   *int64
+		subLesson *models.MSubLesson
+		published = true
+	)
+
+	switch contentType {
+	case constant.MediaContentMaterial:
+		material, err := s.repo.FindMaterialWithCourse(contentID)
+		if err != nil {
+			return 0, nil, gorm_err.TranslateGormError(err)
+		}
+		mediaID, subLesson = material.VideoMediaID, material.SubLesson
+		published = material.Status == constant.ContentPublished
+	case constant.MediaContentCodeQuestion:
+		question, err := s.repo.FindCodeQuestionWithCourse(contentID)
+		if err != nil {
+			return 0, nil, gorm_err.TranslateGormError(err)
+		}
+		mediaID, subLesson = question.ImageMediaID, question.SubLesson
+	default:
+		return 0, nil, fmt.Errorf("tipe konten %s tidak dikenal", contentType)
+	}
+
+	if mediaID == nil {
+		return 0, nil, fmt.Errorf("konten tidak memiliki media")
+	}
+	if subLesson == nil || subLesson.Lesson == nil || subLesson.Lesson.Course == nil {
+		return 0, nil, ErrMediaAccessDenied
+	}
+	lesson, course := subLesson.Lesson, subLesson.Lesson.Course
+
+	switch scope {
+	case constant.MediaScopePreview:
+		return *mediaID, course, nil
+	case constant.MediaScopeEnrolled:
+		published = published &&
+			lesson.Status == constant.ContentPublished &&
+			course.Status == constant.ContentPublished
+		if !published {
+			return 0, nil, ErrMediaAccessDenied
+		}
+
+		enrolled, err := s.repo.HasActiveEnrollment(userID, course.ID)
+		if err != nil {
+			return 0, nil, gorm_err.TranslateGormError(err)
+		}
+		if !enrolled {
+			return 0, nil, ErrMediaAccessDenied
+		}
+		return *mediaID, course, nil
+	default:
+		return 0, nil, ErrMediaAccessDenied
+	}
+}
+
+// mediaFile returns the original file of media, or the named variant.
+func mediaFile(media *models.MMedia, variant string) (*models.MediaFile, error) {
+	if variant == "" {
+		return &models.MediaFile{
+			MimeType:   media.MimeType,
+			Width:      media.Width,
+			Height:     media.Height,
+			Size:       media.Size,
+			StorageKey: media.StorageKey,
+			URL:        media.URL,
+		}, nil
+	}
+
+	for _, file := range media.Variants {
+		if file.Name == variant {
+			return &file, nil
+		}
+	}
+	return nil, fmt.Errorf("varian %s tidak ditemukan", variant)
+}
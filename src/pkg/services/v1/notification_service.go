@@ -0,0 +1,437 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/notification"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type NotificationService interface {
+	WithTx(tx *gorm.DB) NotificationService
+
+	OnNotificationEvent(ctx context.Context, event events.Event) error
+	Notify(ctx context.Context, userIDs []int64, msg notification.Message) error
+	DeliverPendingNotifications(ctx context.Context, batchSize int, maxAttempts int, lease time.Duration) (int, error)
+	GetMyNotifications(userID int64, filter dto.NotificationFilterDto) ([]models.Notification, queryspec.Page, error)
+	CountUnreadNotifications(userID int64) (int64, error)
+	MarkNotificationRead(userID int64, id int64, read bool) (*models.Notification, error)
+	MarkAllNotificationsRead(userID int64) (int64, error)
+	GetNotificationPreferences(userID int64) ([]models.MNotificationPreference, error)
+	UpdateNotificationPreferences(userID int64, input []dto.NotificationPreferenceDto) ([]models.MNotificationPreference, error)
+	GetDB() *gorm.DB
+}
+
+type notificationService struct {
+	repo  sql.NotificationRepository
+	inApp notification.Channel
+	push  notification.Channel
+	omni  notification.Channel
+	tx    *gorm.DB
+}
+
+func NewNotificationService(
+	repo sql.NotificationRepository,
+	inApp notification.Channel,
+	push notification.Channel,
+	omni notification.Channel,
+) NotificationService {
+	return &notificationService{
+		repo:  repo,
+		inApp: inApp,
+		push:  push,
+		omni:  omni,
+	}
+}
+
+func (s *notificationService) WithTx(tx *gorm.DB) NotificationService {
+	return &notificationService{
+		repo:  s.repo.WithTx(tx),
+		inApp: s.inApp,
+		push:  s.push,
+		omni:  s.omni,
+		tx:    tx,
+	}
+}
+
+func (s *notificationService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// OnNotificationEvent turns domain events into user notifications. It runs
+// from the outbox relay; the outbox id is used as dedupe key so a retried
+// event does not notify the same user twice.
+func (s *notificationService) OnNotificationEvent(ctx context.Context, event events.Event) error {
+	var (
+		userIDs []int64
+		msg     notification.Message
+	)
+
+	switch e := event.(type) {
+	case events.EssayApproved:
+		userIDs = []int64{e.UserID}
+		msg = notification.Message{
+			Type:     constant.NotificationEssayReviewed,
+			Title:    "Essay kamu sudah dinilai",
+			Body:     "Guru sudah meninjau jawaban essay kamu. Buka untuk melihat nilai dan catatan.",
+			Priority: constant.NotifImportant,
+			Data: map[string]string{
+				"essay_answer_id":   strconv.FormatInt(e.EssayAnswerID, 10),
+				"essay_question_id": strconv.FormatInt(e.EssayQuestionID, 10),
+			},
+		}
+	case events.TeacherApproved:
+		userIDs = []int64{e.UserID}
+		msg = notification.Message{
+			Type:     constant.NotificationTeacherApproved,
+			Title:    "Akun teacher kamu sudah disetujui",
+			Body:     "Kamu sekarang bisa masuk dan mulai mengelola course.",
+			Priority: constant.NotifImportant,
+		}
+	case events.CoursePublished:
+		ids, err := s.repo.FindActiveUserIDsByRole(constant.RoleIDStudent, e.SchoolID)
+		if err != nil {
+			return gorm_err.TranslateGormError(err)
+		}
+		userIDs = ids
+		msg = notification.Message{
+			Type:     constant.NotificationCoursePublished,
+			Title:    fmt.Sprintf("Course baru: %s", e.CourseName),
+			Body:     fmt.Sprintf("Course %s sudah bisa kamu ikuti.", e.CourseName),
+			Priority: constant.NotifInfo,
+			Data: map[string]string{
+				"course_id": strconv.FormatInt(e.CourseID, 10),
+			},
+		}
+	case events.GuardianInvited:
+		userIDs = []int64{e.StudentID}
+		msg = notification.Message{
+			Type:     constant.NotificationGuardianInvite,
+			Title:    "Undangan wali murid",
+			Body:     fmt.Sprintf("%s ingin terhubung sebagai wali kamu. Terima undangan agar dapat melihat perkembangan belajarmu.", e.GuardianName),
+			Priority: constant.NotifImportant,
+			Data: map[string]string{
+				"guardian_link_id": strconv.FormatInt(e.LinkID, 10),
+			},
+		}
+	case events.GuardianLinkAccepted:
+		userIDs = []int64{e.GuardianID}
+		msg = notification.Message{
+			Type:     constant.NotificationGuardianInvite,
+			Title:    "Undangan wali diterima",
+			Body:     fmt.Sprintf("Kamu sekarang dapat melihat perkembangan belajar %s.", e.StudentName),
+			Priority: constant.NotifInfo,
+			Data: map[string]string{
+				"guardian_link_id": strconv.FormatInt(e.LinkID, 10),
+				"student_id":       strconv.FormatInt(e.StudentID, 10),
+			},
+		}
+	case events.GuardianWeeklyDigest:
+		userIDs = []int64{e.GuardianID}
+		msg = notification.Message{
+			Type:     constant.NotificationGuardianDigest,
+			Title:    "Ringkasan mingguan anak",
+			Body:     guardianDigestBody(e.Children),
+			Priority: constant.NotifInfo,
+			Data: map[string]string{
+				"week": e.Week,
+			},
+			// One digest per guardian per week, however often it is raised.
+			DedupeKey: fmt.Sprintf("guardian_digest_%d_%s", e.GuardianID, e.Week),
+		}
+		return s.Notify(ctx, userIDs, msg)
+	default:
+		return nil
+	}
+
+	if outboxID, ok := events.EventID(ctx); ok {
+		msg.DedupeKey = fmt.Sprintf("evt_%d", outboxID)
+	}
+
+	return s.Notify(ctx, userIDs, msg)
+}
+
+func guardianDigestBody(children []events.GuardianDigestChild) string {
+	lines := make([]string, 0, len(children))
+	for _, child := range children {
+		lines = append(lines, fmt.Sprintf(
+			"%s: progres rata-rata %.0f%%, %d essay dinilai, %d tenggat minggu ini, %d terlambat.",
+			child.StudentName, child.AverageProgress, child.EssaysReviewed, child.Upcoming, child.Overdue,
+		))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Notify stores msg in the inbox of every user whose preferences allow it
+// and queues the push and omni-channel sends for DeliverPendingNotifications.
+// Nothing leaves the process here, so a caller inside the outbox relay can be
+// retried freely: the inbox and the queue both ignore a repeated dedupe key.
+func (s *notificationService) Notify(ctx context.Context, userIDs []int64, msg notification.Message) error {
+	preferences, err := s.repo.FindPreferencesByType(userIDs, msg.Type)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	byUser := make(map[int64]models.MNotificationPreference, len(preferences))
+	for _, preference := range preferences {
+		byUser[preference.UserID] = preference
+	}
+
+	now := time.Now()
+	var deliveries []*models.TNotificationDelivery
+	for _, userID := range userIDs {
+		preference, ok := byUser[userID]
+		if !ok {
+			preference = models.DefaultNotificationPreference(userID, msg.Type)
+		}
+
+		userMsg := msg
+		userMsg.UserID = userID
+
+		if preference.InApp {
+			err := s.inApp.Send(ctx, userMsg)
+			if err != nil && !errors.Is(err, notification.ErrAlreadyDelivered) {
+				return err
+			}
+		}
+		if preference.Push {
+			deliveries = append(deliveries, newNotificationDelivery(constant.NotificationChannelPush, userMsg, now))
+		}
+		if preference.Omni {
+			deliveries = append(deliveries, newNotificationDelivery(constant.NotificationChannelOmni, userMsg, now))
+		}
+	}
+
+	if err := s.repo.InsertDeliveriesIgnoreDuplicates(deliveries); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return nil
+}
+
+// DeliverPendingNotifications sends up to batchSize queued push and
+// omni-channel deliveries. The rows are leased rather than locked, so no
+// transaction is open while the channels are called. Failed sends are retried
+// with exponential backoff and marked dead after maxAttempts. It returns the
+// number of deliveries that succeeded.
+func (s *notificationService) DeliverPendingNotifications(ctx context.Context, batchSize int, maxAttempts int, lease time.Duration) (int, error) {
+	now := time.Now()
+
+	rows, err := s.repo.ClaimPendingDeliveries(batchSize, now, now.Add(lease))
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+
+	delivered := 0
+	for _, row := range rows {
+		updates := map[string]interface{}{
+			"updated_at": time.Now(),
+		}
+
+		if sendErr := s.channel(row.Channel).Send(ctx, deliveryMessage(row)); sendErr != nil {
+			message := sendErr.Error()
+			updates["last_error"] = message
+
+			if row.Attempts >= maxAttempts {
+				updates["status"] = constant.NotificationDeliveryDead
+				config.Logger.Errorf("❌ Notification %s to user %d via %s dead after %d attempts: %s", row.Type, row.UserID, row.Channel, row.Attempts, message)
+			} else {
+				updates["next_attempt_at"] = time.Now().Add(outboxBackoff(row.Attempts))
+				config.Logger.Warnf("⚠️ Notification %s to user %d via %s failed, attempt %d: %s", row.Type, row.UserID, row.Channel, row.Attempts, message)
+			}
+		} else {
+			updates["status"] = constant.NotificationDeliverySucceeded
+			updates["delivered_at"] = time.Now()
+			updates["last_error"] = nil
+			delivered++
+		}
+
+		if err := s.repo.UpdateDelivery(row.ID, updates); err != nil {
+			return delivered, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	return delivered, nil
+}
+
+func (s *notificationService) GetMyNotifications(userID int64, filter dto.NotificationFilterDto) ([]models.Notification, queryspec.Page, error) {
+	repo := s.repo.WithWhere("user_id = ?", userID)
+
+	if filter.Unread {
+		repo = repo.WithWhere("read_at IS NULL")
+	}
+	if filter.Type != "" {
+		repo = repo.WithWhere("type = ?", filter.Type)
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountNotifications()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindNotificationPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *notificationService) CountUnreadNotifications(userID int64) (int64, error) {
+	total, err := s.repo.
+		WithWhere("user_id = ? AND read_at IS NULL", userID).
+		CountNotifications()
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+	return total, nil
+}
+
+func (s *notificationService) MarkNotificationRead(userID int64, id int64, read bool) (*models.Notification, error) {
+	var readAt *time.Time
+	if read {
+		now := time.Now()
+		readAt = &now
+	}
+
+	data, err := s.repo.UpdateNotificationReadAt(userID, id, readAt)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *notificationService) MarkAllNotificationsRead(userID int64) (int64, error) {
+	updated, err := s.repo.MarkAllNotificationsRead(userID, time.Now())
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+	return updated, nil
+}
+
+// GetNotificationPreferences returns one entry per notification type, filling
+// in defaults for types the user has not configured.
+func (s *notificationService) GetNotificationPreferences(userID int64) ([]models.MNotificationPreference, error) {
+	stored, err := s.repo.FindPreferencesByUser(userID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	byType := make(map[string]models.MNotificationPreference, len(stored))
+	for _, preference := range stored {
+		byType[preference.Type] = preference
+	}
+
+	preferences := make([]models.MNotificationPreference, 0, len(constant.NotificationTypes))
+	for _, notificationType := range constant.NotificationTypes {
+		preference, ok := byType[notificationType]
+		if !ok {
+			preference = models.DefaultNotificationPreference(userID, notificationType)
+		}
+		preferences = append(preferences, preference)
+	}
+	return preferences, nil
+}
+
+func (s *notificationService) UpdateNotificationPreferences(userID int64, input []dto.NotificationPreferenceDto) ([]models.MNotificationPreference, error) {
+	current, err := s.GetNotificationPreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byType := make(map[string]models.MNotificationPreference, len(current))
+	for _, preference := range current {
+		byType[preference.Type] = preference
+	}
+
+	for _, item := range input {
+		if !constant.IsNotificationType(item.Type) {
+			return nil, fmt.Errorf("tipe notifikasi %q tidak valid", item.Type)
+		}
+
+		preference := byType[item.Type]
+		preference.ID = 0
+		if item.InApp != nil {
+			preference.InApp = *item.InApp
+		}
+		if item.Push != nil {
+			preference.Push = *item.Push
+		}
+		if item.Omni != nil {
+			preference.Omni = *item.Omni
+		}
+
+		if err := s.repo.UpsertPreference(&preference); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	return s.GetNotificationPreferences(userID)
+}
+
+func (s *notificationService) channel(name string) notification.Channel {
+	if name == constant.NotificationChannelOmni {
+		return s.omni
+	}
+	return s.push
+}
+
+func newNotificationDelivery(channel string, msg notification.Message, now time.Time) *models.TNotificationDelivery {
+	data := make(map[string]interface{}, len(msg.Data))
+	for key, value := range msg.Data {
+		data[key] = value
+	}
+
+	delivery := &models.TNotificationDelivery{
+		UserID:        msg.UserID,
+		Channel:       channel,
+		Type:          msg.Type,
+		Title:         msg.Title,
+		Body:          msg.Body,
+		Priority:      msg.Priority,
+		Data:          data,
+		Status:        constant.NotificationDeliveryPending,
+		NextAttemptAt: now,
+	}
+	if msg.DedupeKey != "" {
+		delivery.DedupeKey = &msg.DedupeKey
+	}
+	return delivery
+}
+
+func deliveryMessage(row models.TNotificationDelivery) notification.Message {
+	data := make(map[string]string, len(row.Data))
+	for key, value := range row.Data {
+		data[key] = fmt.Sprint(value)
+	}
+
+	msg := notification.Message{
+		UserID:   row.UserID,
+		Type:     row.Type,
+		Title:    row.Title,
+		Body:     row.Body,
+		Priority: row.Priority,
+		Data:     data,
+	}
+	if row.DedupeKey != nil {
+		msg.DedupeKey = *row.DedupeKey
+	}
+	return msg
+}
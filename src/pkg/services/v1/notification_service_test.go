@@ -0,0 +1,199 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/events"
+	"jk-api/internal/notification"
+	"jk-api/pkg/repository/adapter/sql"
+	"testing"
+	"time"
+)
+
+func TestOnNotificationEventQueuesWithoutSending(t *testing.T) {
+	repo := &fakeNotificationRepository{}
+	inApp := notification.NewFake(constant.NotificationChannelInApp)
+	push := notification.NewFake(constant.NotificationChannelPush)
+	omni := notification.NewFake(constant.NotificationChannelOmni)
+	service := NewNotificationService(repo, inApp, push, omni)
+
+	ctx := events.WithEventID(context.Background(), 7)
+	event := events.EssayApproved{UserID: 1, EssayAnswerID: 2, EssayQuestionID: 3}
+
+	// The relay retries an event whose handler failed part way; the second
+	// run must not queue or store anything twice.
+	for run := 0; run < 2; run++ {
+		if err := service.OnNotificationEvent(ctx, event); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if len(push.Sent()) != 0 || len(omni.Sent()) != 0 {
+		t.Fatalf("channels called from the relay: push %d, omni %d", len(push.Sent()), len(omni.Sent()))
+	}
+	if len(inApp.Sent()) != 1 {
+		t.Errorf("inbox got %d messages, want 1", len(inApp.Sent()))
+	}
+	if len(repo.deliveries) != 2 {
+		t.Fatalf("queued %d deliveries, want push and omni", len(repo.deliveries))
+	}
+	for _, delivery := range repo.deliveries {
+		if delivery.DedupeKey == nil || *delivery.DedupeKey != "evt_7" || delivery.UserID != 1 {
+			t.Errorf("delivery = %+v, want user 1 keyed evt_7", delivery)
+		}
+	}
+}
+
+func TestDeliverPendingNotifications(t *testing.T) {
+	tests := []struct {
+		name         string
+		failures     int
+		maxAttempts  int
+		wantStatus   string
+		wantAttempts int
+		wantSends    int
+	}{
+		{"first try", 0, 3, constant.NotificationDeliverySucceeded, 1, 1},
+		{"after two failures", 2, 3, constant.NotificationDeliverySucceeded, 3, 3},
+		{"dead after max attempts", 5, 3, constant.NotificationDeliveryDead, 3, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeNotificationRepository{}
+			push := &flakyChannel{failures: tt.failures}
+			service := NewNotificationService(repo, notification.NewFake(constant.NotificationChannelInApp), push, notification.NewFake(constant.NotificationChannelOmni))
+
+			key := "evt_1"
+			repo.InsertDeliveriesIgnoreDuplicates([]*models.TNotificationDelivery{{
+				UserID:        1,
+				Channel:       constant.NotificationChannelPush,
+				DedupeKey:     &key,
+				Type:          constant.NotificationCoursePublished,
+				Title:         "Course baru",
+				Data:          map[string]interface{}{"course_id": "9"},
+				Status:        constant.NotificationDeliveryPending,
+				NextAttemptAt: time.Now(),
+			}})
+			row := repo.deliveries[0]
+
+			for run := 1; row.Status == constant.NotificationDeliveryPending; run++ {
+				if run > tt.maxAttempts {
+					t.Fatalf("still pending after %d runs", run-1)
+				}
+
+				before := time.Now()
+				if _, err := service.DeliverPendingNotifications(context.Background(), 10, tt.maxAttempts, time.Minute); err != nil {
+					t.Fatal(err)
+				}
+
+				if row.Status == constant.NotificationDeliveryPending {
+					wait := row.NextAttemptAt.Sub(before)
+					if want := outboxBackoff(run); wait < want || wait > want+time.Second {
+						t.Errorf("run %d: retried after %v, want %v", run, wait, want)
+					}
+					row.NextAttemptAt = time.Now()
+				}
+			}
+
+			if row.Status != tt.wantStatus || row.Attempts != tt.wantAttempts || push.sends != tt.wantSends {
+				t.Errorf("status = %s, attempts = %d, sends = %d", row.Status, row.Attempts, push.sends)
+			}
+			if push.last.UserID != 1 || push.last.Data["course_id"] != "9" || push.last.DedupeKey != key {
+				t.Errorf("sent message = %+v", push.last)
+			}
+		})
+	}
+}
+
+// flakyChannel fails its first failures sends.
+type flakyChannel struct {
+	failures int
+	sends    int
+	last     notification.Message
+}
+
+func (c *flakyChannel) Name() string {
+	return constant.NotificationChannelPush
+}
+
+func (c *flakyChannel) Send(ctx context.Context, msg notification.Message) error {
+	c.sends++
+	c.last = msg
+	if c.sends <= c.failures {
+		return errors.New("push endpoint unavailable")
+	}
+	return nil
+}
+
+// fakeNotificationRepository keeps the delivery queue in memory and honours
+// its unique key the way the table does.
+type fakeNotificationRepository struct {
+	sql.NotificationRepository
+	deliveries []*models.TNotificationDelivery
+}
+
+func (r *fakeNotificationRepository) FindPreferencesByType(userIDs []int64, notificationType string) ([]models.MNotificationPreference, error) {
+	var rows []models.MNotificationPreference
+	for _, userID := range userIDs {
+		preference := models.DefaultNotificationPreference(userID, notificationType)
+		preference.InApp, preference.Push, preference.Omni = true, true, true
+		rows = append(rows, preference)
+	}
+	return rows, nil
+}
+
+func (r *fakeNotificationRepository) InsertDeliveriesIgnoreDuplicates(data []*models.TNotificationDelivery) error {
+	for _, delivery := range data {
+		duplicate := false
+		for _, existing := range r.deliveries {
+			if delivery.DedupeKey != nil && existing.DedupeKey != nil &&
+				existing.UserID == delivery.UserID && existing.Channel == delivery.Channel && *existing.DedupeKey == *delivery.DedupeKey {
+				duplicate = true
+			}
+		}
+		if !duplicate {
+			delivery.ID = int64(len(r.deliveries) + 1)
+			r.deliveries = append(r.deliveries, delivery)
+		}
+	}
+	return nil
+}
+
+func (r *fakeNotificationRepository) ClaimPendingDeliveries(limit int, now time.Time, leaseUntil time.Time) ([]models.TNotificationDelivery, error) {
+	var rows []models.TNotificationDelivery
+	for _, delivery := range r.deliveries {
+		if len(rows) == limit || delivery.Status != constant.NotificationDeliveryPending || delivery.NextAttemptAt.After(now) {
+			continue
+		}
+		delivery.Attempts++
+		delivery.NextAttemptAt = leaseUntil
+		rows = append(rows, *delivery)
+	}
+	return rows, nil
+}
+
+func (r *fakeNotificationRepository) UpdateDelivery(id int64, updates map[string]interface{}) error {
+	for _, delivery := range r.deliveries {
+		if delivery.ID != id {
+			continue
+		}
+		for column, value := range updates {
+			switch column {
+			case "status":
+				delivery.Status = value.(string)
+			case "next_attempt_at":
+				delivery.NextAttemptAt = value.(time.Time)
+			case "last_error":
+				if message, ok := value.(string); ok {
+					delivery.LastError = &message
+				}
+			}
+		}
+		return nil
+	}
+	return fmt.Errorf("delivery %d not found", id)
+}
@@ -0,0 +1,156 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSearchLimit = 20
+	maxSearchLimit     = 50
+	maxSearchQueryLen  = 200
+)
+
+var ErrSearchInvalid = errors.New("pencarian tidak valid")
+
+type SearchService interface {
+	WithTx(tx *gorm.DB) SearchService
+
+	SearchContent(filter dto.SearchFilterDto) (*dto.SearchResponseDto, queryspec.Page, error)
+	GetDB() *gorm.DB
+}
+
+type searchService struct {
+	repo sql.SearchRepository
+	tx   *gorm.DB
+}
+
+func NewSearchService(repo sql.SearchRepository) SearchService {
+	return &searchService{repo: repo}
+}
+
+func (s *searchService) WithTx(tx *gorm.DB) SearchService {
+	return &searchService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *searchService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// SearchContent returns a page of published content matching the query,
+// best first, with the hit counts per course and per level.
+func (s *searchService) SearchContent(filter dto.SearchFilterDto) (*dto.SearchResponseDto, queryspec.Page, error) {
+	query, err := searchQuery(filter)
+	if err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	hits, err := s.repo.SearchContent(query)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	total, err := s.repo.CountSearchHits(query)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	courses, err := s.repo.CountSearchHitsByCourse(query)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	levels, err := s.repo.CountSearchHitsByLevel(query)
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	response := &dto.SearchResponseDto{
+		Results: make([]dto.SearchResultDto, 0, len(hits)),
+		Facets: dto.SearchFacetsDto{
+			Courses: searchFacets(courses),
+			Levels:  searchFacets(levels),
+		},
+	}
+	for _, hit := range hits {
+		response.Results = append(response.Results, dto.SearchResultDto{
+			Type:        hit.Type,
+			ID:          hit.ID,
+			CourseID:    hit.CourseID,
+			LessonID:    hit.LessonID,
+			SubLessonID: hit.SubLessonID,
+			LevelID:     hit.LevelID,
+			Title:       hit.Title,
+			Snippet:     highlightSnippet(hit.Snippet),
+			Rank:        hit.Rank,
+		})
+	}
+
+	return response, queryspec.Page{Total: total, Limit: query.Limit}, nil
+}
+
+func searchQuery(filter dto.SearchFilterDto) (sql.SearchQuery, error) {
+	text := strings.TrimSpace(filter.Query)
+	if text == "" {
+		return sql.SearchQuery{}, fmt.Errorf("%w: kata kunci pencarian wajib diisi", ErrSearchInvalid)
+	}
+	if utf8.RuneCountInString(text) > maxSearchQueryLen {
+		return sql.SearchQuery{}, fmt.Errorf("%w: kata kunci pencarian maksimal %d karakter", ErrSearchInvalid, maxSearchQueryLen)
+	}
+
+	for _, t := range filter.Types {
+		if !slices.Contains(constant.SearchTypes, t) {
+			return sql.SearchQuery{}, fmt.Errorf("%w: tipe %s tidak dikenal", ErrSearchInvalid, t)
+		}
+	}
+
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = defaultSearchLimit
+	}
+	limit = min(limit, maxSearchLimit)
+
+	if filter.Offset < 0 {
+		return sql.SearchQuery{}, fmt.Errorf("%w: offset tidak boleh negatif", ErrSearchInvalid)
+	}
+
+	return sql.SearchQuery{
+		Query:    text,
+		Types:    filter.Types,
+		CourseID: filter.CourseID,
+		LevelID:  filter.LevelID,
+		Limit:    limit,
+		Offset:   filter.Offset,
+	}, nil
+}
+
+// highlightSnippet escapes a snippet for HTML and only then turns the match
+// markers into <mark>, so content can't inject markup.
+func highlightSnippet(snippet string) string {
+	escaped := html.EscapeString(snippet)
+	escaped = strings.ReplaceAll(escaped, sql.SearchMarkStart, "<mark>")
+	return strings.ReplaceAll(escaped, sql.SearchMarkStop, "</mark>")
+}
+
+func searchFacets(facets []sql.SearchFacet) []dto.SearchFacetDto {
+	result := make([]dto.SearchFacetDto, 0, len(facets))
+	for _, facet := range facets {
+		result = append(result, dto.SearchFacetDto{ID: facet.ID, Name: facet.Name, Count: facet.Count})
+	}
+	return result
+}
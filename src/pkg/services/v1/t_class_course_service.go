@@ -0,0 +1,572 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/tenant"
+	"jk-api/pkg/repository/adapter/sql"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TClassCourseService interface {
+	WithTx(tx *gorm.DB) TClassCourseService
+
+	AssignClassCourse(actorID int64, isSuper bool, input *dto.AssignClassCourseDto) (*models.TClassCourse, error)
+	UpdateClassCourse(actorID int64, isSuper bool, id int64, input *dto.UpdateClassCourseDto) (*models.TClassCourse, error)
+	UnassignClassCourse(actorID int64, isSuper bool, id int64) error
+	GetClassCourses(actorID int64, isSuper bool, filter dto.ClassCourseFilterDto) ([]models.TClassCourse, queryspec.Page, error)
+	GetClassCourseByID(actorID int64, isSuper bool, id int64) (*models.TClassCourse, error)
+	GetMyDueItems(userID int64, filter dto.DueItemFilterDto) ([]dto.DueItemDto, error)
+	GetOverdueReport(actorID int64, isSuper bool, id int64) (*dto.ClassCourseOverdueReportDto, error)
+	OnClassStudentJoined(tx *gorm.DB, event events.Event) error
+	OnSubLessonCompleted(tx *gorm.DB, event events.Event) error
+	GetDB() *gorm.DB
+}
+
+type tClassCourseService struct {
+	repo       sql.TClassCourseRepository
+	rosterRepo sql.TClassRosterRepository
+	tx         *gorm.DB
+}
+
+func NewTClassCourseService(repo sql.TClassCourseRepository, rosterRepo sql.TClassRosterRepository) TClassCourseService {
+	return &tClassCourseService{repo: repo, rosterRepo: rosterRepo}
+}
+
+func (s *tClassCourseService) WithTx(tx *gorm.DB) TClassCourseService {
+	return &tClassCourseService{
+		repo:       s.repo.WithTx(tx),
+		rosterRepo: s.rosterRepo.WithTx(tx),
+		tx:         tx,
+	}
+}
+
+func (s *tClassCourseService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// AssignClassCourse assigns a course to a class and enrolls every current
+// student of the class. Students who join later are enrolled by
+// OnClassStudentJoined.
+func (s *tClassCourseService) AssignClassCourse(actorID int64, isSuper bool, input *dto.AssignClassCourseDto) (*models.TClassCourse, error) {
+	if err := authorizeClassTeacher(s.rosterRepo, actorID, isSuper, input.ClassID); err != nil {
+		return nil, err
+	}
+	if _, err := s.rosterRepo.LockClass(input.ClassID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	course, err := s.repo.FindCourseByID(input.CourseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if course.Status != constant.ContentPublished {
+		return nil, fmt.Errorf("course %s belum diterbitkan", course.CourseName)
+	}
+
+	existing, err := s.repo.FindActiveClassCourse(input.ClassID, input.CourseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if existing != nil {
+		return nil, fmt.Errorf("course %s sudah di-assign ke kelas ini", course.CourseName)
+	}
+
+	deadlines, err := s.validateSchedule(course.ID, input.StartAt, input.EndAt, input.LessonDeadlines)
+	if err != nil {
+		return nil, err
+	}
+
+	assignment, err := s.repo.InsertClassCourse(&models.TClassCourse{
+		ClassID:    input.ClassID,
+		CourseID:   input.CourseID,
+		AssignedBy: actorID,
+		StartAt:    input.StartAt,
+		EndAt:      input.EndAt,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.repo.ReplaceLessonDeadlines(assignment.ID, deadlines); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	studentIDs, err := s.rosterRepo.FindClassStudentIDs(input.ClassID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.enroll(input.CourseID, studentIDs); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.RefreshProgressLateFlags(assignment.ID, 0, 0); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return s.findClassCourse(assignment.ID)
+}
+
+func (s *tClassCourseService) UpdateClassCourse(actorID int64, isSuper bool, id int64, input *dto.UpdateClassCourseDto) (*models.TClassCourse, error) {
+	assignment, err := s.repo.FindClassCourseByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := authorizeClassTeacher(s.rosterRepo, actorID, isSuper, assignment.ClassID); err != nil {
+		return nil, err
+	}
+
+	updates := map[string]interface{}{}
+	startAt, endAt := assignment.StartAt, assignment.EndAt
+	if input.ClearStartAt {
+		startAt = nil
+		updates["start_at"] = nil
+	} else if input.StartAt != nil {
+		startAt = input.StartAt
+		updates["start_at"] = *input.StartAt
+	}
+	if input.ClearEndAt {
+		endAt = nil
+		updates["end_at"] = nil
+	} else if input.EndAt != nil {
+		endAt = input.EndAt
+		updates["end_at"] = *input.EndAt
+	}
+
+	requested := make([]dto.ClassLessonDeadlineDto, 0, len(assignment.LessonDeadlines))
+	if input.LessonDeadlines != nil {
+		requested = *input.LessonDeadlines
+	} else {
+		for _, d := range assignment.LessonDeadlines {
+			requested = append(requested, dto.ClassLessonDeadlineDto{LessonID: d.LessonID, DueAt: d.DueAt})
+		}
+	}
+
+	deadlines, err := s.validateSchedule(assignment.CourseID, startAt, endAt, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(updates) > 0 {
+		if _, err := s.repo.UpdateClassCourse(id, updates); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+	if input.LessonDeadlines != nil {
+		if err := s.repo.ReplaceLessonDeadlines(id, deadlines); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	if err := s.repo.RefreshProgressLateFlags(id, 0, 0); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return s.findClassCourse(id)
+}
+
+// UnassignClassCourse removes the assignment. Existing enrollments and
+// progress are kept.
+func (s *tClassCourseService) UnassignClassCourse(actorID int64, isSuper bool, id int64) error {
+	assignment, err := s.repo.FindClassCourseByID(id)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if err := authorizeClassTeacher(s.rosterRepo, actorID, isSuper, assignment.ClassID); err != nil {
+		return err
+	}
+
+	return gorm_err.TranslateGormError(s.repo.RemoveClassCourse(id))
+}
+
+func (s *tClassCourseService) GetClassCourses(actorID int64, isSuper bool, filter dto.ClassCourseFilterDto) ([]models.TClassCourse, queryspec.Page, error) {
+	repo := s.repo
+	if filter.ClassID != 0 {
+		if err := authorizeClassTeacher(s.rosterRepo, actorID, isSuper, filter.ClassID); err != nil {
+			return nil, queryspec.Page{}, err
+		}
+		repo = repo.WithWhere("class_id = ?", filter.ClassID)
+	} else if !isSuper {
+		repo = repo.WithWhere("class_id IN (SELECT m_class_id FROM m_class_teachers WHERE user_id = ?)", actorID)
+	}
+	if filter.CourseID != 0 {
+		repo = repo.WithWhere("course_id = ?", filter.CourseID)
+	}
+	if filter.DepartmentID != 0 {
+		repo = repo.WithWhere("class_id IN (SELECT id FROM m_class WHERE department_id IN ("+tenant.DepartmentSubtreeSQL+"))", tenant.DepartmentArg(filter.DepartmentID))
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountClassCourses()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	if filter.Preload {
+		repo = repo.WithPreloads("Class", "Course", "LessonDeadlines")
+	}
+
+	data, page, err := repo.FindClassCoursePage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *tClassCourseService) GetClassCourseByID(actorID int64, isSuper bool, id int64) (*models.TClassCourse, error) {
+	assignment, err := s.findClassCourse(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := authorizeClassTeacher(s.rosterRepo, actorID, isSuper, assignment.ClassID); err != nil {
+		return nil, err
+	}
+	return assignment, nil
+}
+
+// GetMyDueItems lists the lessons of the student's started assignments that
+// are overdue or due within filter.Days (all upcoming lessons when Days is 0),
+// soonest first.
+func (s *tClassCourseService) GetMyDueItems(userID int64, filter dto.DueItemFilterDto) ([]dto.DueItemDto, error) {
+	user, err := s.repo.FindUserByID(userID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	items := []dto.DueItemDto{}
+	if user.ClassID == nil {
+		return items, nil
+	}
+
+	assignments, err := s.repo.
+		WithWhere("class_id = ?", *user.ClassID).
+		WithPreloads("Course", "LessonDeadlines").
+		FindClassCourses()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	now := time.Now()
+	var horizon *time.Time
+	if filter.Days > 0 {
+		h := now.AddDate(0, 0, int(filter.Days))
+		horizon = &h
+	}
+
+	for i := range assignments {
+		assignment := &assignments[i]
+		if assignment.StartAt != nil && assignment.StartAt.After(now) {
+			continue
+		}
+
+		progress, err := s.lessonProgress(assignment, []int64{userID})
+		if err != nil {
+			return nil, err
+		}
+
+		for _, lesson := range progress.lessons {
+			dueAt := assignment.DueAt(lesson.ID)
+			total := progress.totals[lesson.ID]
+			if dueAt == nil || total == 0 {
+				continue
+			}
+
+			done := progress.completions[userID][lesson.ID]
+			status := lessonDueStatus(*dueAt, done.Completed, total, done.Late, now)
+
+			switch status {
+			case constant.DueStatusCompleted, constant.DueStatusCompletedLate:
+				if !filter.IncludeCompleted {
+					continue
+				}
+			case constant.DueStatusUpcoming:
+				if horizon != nil && dueAt.After(*horizon) {
+					continue
+				}
+			}
+
+			item := dto.DueItemDto{
+				ClassCourseID: assignment.ID,
+				CourseID:      assignment.CourseID,
+				LessonID:      lesson.ID,
+				LessonTitle:   lesson.Title,
+				DueAt:         *dueAt,
+				Completed:     done.Completed,
+				Total:         total,
+				Status:        status,
+			}
+			if assignment.Course != nil {
+				item.CourseName = assignment.Course.CourseName
+			}
+			items = append(items, item)
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].DueAt.Before(items[j].DueAt)
+	})
+	return items, nil
+}
+
+// GetOverdueReport lists, per student of the class, every lesson of the
+// assignment whose due date has passed without all sub-lessons completed.
+func (s *tClassCourseService) GetOverdueReport(actorID int64, isSuper bool, id int64) (*dto.ClassCourseOverdueReportDto, error) {
+	assignment, err := s.GetClassCourseByID(actorID, isSuper, id)
+	if err != nil {
+		return nil, err
+	}
+
+	students, err := s.repo.FindClassStudents(assignment.ClassID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	studentIDs := make([]int64, 0, len(students))
+	for _, student := range students {
+		studentIDs = append(studentIDs, student.ID)
+	}
+
+	progress, err := s.lessonProgress(assignment, studentIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	report := &dto.ClassCourseOverdueReportDto{
+		ClassCourseID: assignment.ID,
+		ClassID:       assignment.ClassID,
+		CourseID:      assignment.CourseID,
+		GeneratedAt:   now,
+		Students:      len(students),
+		Items:         []dto.OverdueItemDto{},
+	}
+	if assignment.Course != nil {
+		report.CourseName = assignment.Course.CourseName
+	}
+
+	overdueStudents := make(map[int64]bool)
+	for _, lesson := range progress.lessons {
+		dueAt := assignment.DueAt(lesson.ID)
+		total := progress.totals[lesson.ID]
+		if dueAt == nil || total == 0 || !dueAt.Before(now) {
+			continue
+		}
+
+		for _, student := range students {
+			done := progress.completions[student.ID][lesson.ID]
+			if done.Completed >= total {
+				continue
+			}
+
+			overdueStudents[student.ID] = true
+			report.Items = append(report.Items, dto.OverdueItemDto{
+				UserID:      student.ID,
+				Name:        student.Name,
+				Email:       student.Email,
+				LessonID:    lesson.ID,
+				LessonTitle: lesson.Title,
+				DueAt:       *dueAt,
+				Completed:   done.Completed,
+				Total:       total,
+				DaysOverdue: int(now.Sub(*dueAt).Hours() / 24),
+			})
+		}
+	}
+	report.Overdue = len(overdueStudents)
+
+	sort.SliceStable(report.Items, func(i, j int) bool {
+		if !report.Items[i].DueAt.Equal(report.Items[j].DueAt) {
+			return report.Items[i].DueAt.Before(report.Items[j].DueAt)
+		}
+		return report.Items[i].Name < report.Items[j].Name
+	})
+	return report, nil
+}
+
+// OnClassStudentJoined enrolls a student who joins a class in every course
+// assigned to that class.
+func (s *tClassCourseService) OnClassStudentJoined(tx *gorm.DB, event events.Event) error {
+	joined, ok := event.(events.ClassStudentJoined)
+	if !ok {
+		return nil
+	}
+
+	service := s.WithTx(tx).(*tClassCourseService)
+	assignments, err := service.repo.WithWhere("class_id = ?", joined.ClassID).FindClassCourses()
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	for _, assignment := range assignments {
+		if err := service.enroll(assignment.CourseID, []int64{joined.UserID}); err != nil {
+			return err
+		}
+		if err := service.repo.RefreshProgressLateFlags(assignment.ID, joined.UserID, 0); err != nil {
+			return gorm_err.TranslateGormError(err)
+		}
+	}
+	return nil
+}
+
+// OnSubLessonCompleted marks the completion late when it happened after the
+// lesson's due date in the student's class.
+func (s *tClassCourseService) OnSubLessonCompleted(tx *gorm.DB, event events.Event) error {
+	completed, ok := event.(events.SubLessonCompleted)
+	if !ok {
+		return nil
+	}
+
+	service := s.WithTx(tx).(*tClassCourseService)
+	user, err := service.repo.FindUserByID(completed.UserID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if user.ClassID == nil {
+		return nil
+	}
+
+	assignment, err := service.repo.FindActiveClassCourse(*user.ClassID, completed.CourseID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if assignment == nil {
+		return nil
+	}
+
+	err = service.repo.RefreshProgressLateFlags(assignment.ID, completed.UserID, completed.SubLessonID)
+	return gorm_err.TranslateGormError(err)
+}
+
+func (s *tClassCourseService) findClassCourse(id int64) (*models.TClassCourse, error) {
+	data, err := s.repo.WithPreloads("Course", "LessonDeadlines.Lesson").FindClassCourseByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *tClassCourseService) enroll(courseID int64, userIDs []int64) error {
+	enrollments, err := s.repo.EnrollStudents(courseID, userIDs)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	for _, enrollment := range enrollments {
+		err := events.Publish(s.GetDB(), events.CourseEnrolled{
+			UserID:          enrollment.UserID,
+			CourseID:        enrollment.CourseID,
+			StudentCourseID: enrollment.ID,
+			EnrolledAt:      enrollment.CreatedAt,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateSchedule checks the assignment window and that every deadline
+// belongs to a lesson of the course and falls inside the window.
+func (s *tClassCourseService) validateSchedule(courseID int64, startAt *time.Time, endAt *time.Time, requested []dto.ClassLessonDeadlineDto) ([]models.TClassLessonDeadline, error) {
+	if startAt != nil && endAt != nil && !endAt.After(*startAt) {
+		return nil, fmt.Errorf("tanggal selesai harus setelah tanggal mulai")
+	}
+	if len(requested) == 0 {
+		return nil, nil
+	}
+
+	lessons, err := s.repo.FindCourseLessons(courseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	inCourse := make(map[int64]bool, len(lessons))
+	for _, lesson := range lessons {
+		inCourse[lesson.ID] = true
+	}
+
+	seen := make(map[int64]bool, len(requested))
+	deadlines := make([]models.TClassLessonDeadline, 0, len(requested))
+	for _, d := range requested {
+		switch {
+		case !inCourse[d.LessonID]:
+			return nil, fmt.Errorf("lesson %d bukan bagian dari course ini", d.LessonID)
+		case seen[d.LessonID]:
+			return nil, fmt.Errorf("deadline lesson %d diisi lebih dari sekali", d.LessonID)
+		case d.DueAt.IsZero():
+			return nil, fmt.Errorf("deadline lesson %d wajib diisi", d.LessonID)
+		case startAt != nil && d.DueAt.Before(*startAt):
+			return nil, fmt.Errorf("deadline lesson %d sebelum tanggal mulai", d.LessonID)
+		case endAt != nil && d.DueAt.After(*endAt):
+			return nil, fmt.Errorf("deadline lesson %d setelah tanggal selesai", d.LessonID)
+		}
+		seen[d.LessonID] = true
+		deadlines = append(deadlines, models.TClassLessonDeadline{LessonID: d.LessonID, DueAt: d.DueAt})
+	}
+	return deadlines, nil
+}
+
+type assignmentProgress struct {
+	lessons     []models.MLesson
+	totals      map[int64]int64
+	completions map[int64]map[int64]sql.LessonCompletion
+}
+
+// lessonProgress loads the lessons of an assignment's course with their
+// sub-lesson counts and each student's completions, keyed by user then lesson.
+func (s *tClassCourseService) lessonProgress(assignment *models.TClassCourse, userIDs []int64) (*assignmentProgress, error) {
+	lessons, err := s.repo.FindCourseLessons(assignment.CourseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	lessonIDs := make([]int64, 0, len(lessons))
+	for _, lesson := range lessons {
+		lessonIDs = append(lessonIDs, lesson.ID)
+	}
+
+	totals, err := s.repo.CountSubLessonsByLesson(lessonIDs)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	rows, err := s.repo.FindLessonCompletions(userIDs, lessonIDs)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	completions := make(map[int64]map[int64]sql.LessonCompletion, len(userIDs))
+	for _, row := range rows {
+		if completions[row.UserID] == nil {
+			completions[row.UserID] = make(map[int64]sql.LessonCompletion)
+		}
+		completions[row.UserID][row.LessonID] = row
+	}
+
+	return &assignmentProgress{lessons: lessons, totals: totals, completions: completions}, nil
+}
+
+func lessonDueStatus(dueAt time.Time, completed int64, total int64, late int64, now time.Time) string {
+	switch {
+	case completed >= total && late > 0:
+		return constant.DueStatusCompletedLate
+	case completed >= total:
+		return constant.DueStatusCompleted
+	case dueAt.Before(now):
+		return constant.DueStatusOverdue
+	default:
+		return constant.DueStatusUpcoming
+	}
+}
@@ -0,0 +1,510 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/helper"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const classCodeLength = 8
+
+type TClassRosterService interface {
+	WithTx(tx *gorm.DB) TClassRosterService
+
+	JoinMClassByCode(userID int64, classCode string) (*models.TClassJoinRequest, error)
+	RegenerateClassCode(actorID int64, isSuper bool, classID int64, expiresAt *time.Time) (*models.MClass, error)
+	UpdateClassJoinSettings(actorID int64, isSuper bool, classID int64, input *dto.UpdateClassJoinSettingsDto) (*models.MClass, error)
+	GetClassJoinRequests(actorID int64, isSuper bool, classID int64, filter dto.ClassJoinRequestFilterDto) ([]models.TClassJoinRequest, queryspec.Page, error)
+	DecideClassJoinRequest(actorID int64, isSuper bool, requestID int64, approve bool, note *string) (*models.TClassJoinRequest, error)
+	MoveClassStudent(actorID int64, isSuper bool, classID int64, userID int64, toClassID int64) error
+	RemoveClassStudent(actorID int64, isSuper bool, classID int64, userID int64) error
+	AssignClassTeacher(actorID int64, isSuper bool, classID int64, teacherID int64) error
+	RemoveClassTeacher(actorID int64, isSuper bool, classID int64, teacherID int64) error
+	GetClassRosterLogs(actorID int64, isSuper bool, classID int64, filter dto.ClassRosterLogFilterDto) ([]models.TClassRosterLog, queryspec.Page, error)
+	GetDB() *gorm.DB
+}
+
+type tClassRosterService struct {
+	repo sql.TClassRosterRepository
+	tx   *gorm.DB
+}
+
+func NewTClassRosterService(repo sql.TClassRosterRepository) TClassRosterService {
+	return &tClassRosterService{repo: repo}
+}
+
+func (s *tClassRosterService) WithTx(tx *gorm.DB) TClassRosterService {
+	return &tClassRosterService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *tClassRosterService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// JoinMClassByCode files a join request for the student. Classes with
+// auto_approve_join place the student immediately and return an approved
+// request.
+func (s *tClassRosterService) JoinMClassByCode(userID int64, classCode string) (*models.TClassJoinRequest, error) {
+	classCode = strings.TrimSpace(classCode)
+	if classCode == "" {
+		return nil, fmt.Errorf("kode kelas wajib diisi")
+	}
+
+	class, err := s.repo.FindClassByCode(classCode)
+	if err != nil {
+		return nil, fmt.Errorf("kode kelas tidak ditemukan")
+	}
+	if !class.IsActive {
+		return nil, fmt.Errorf("kelas %s tidak aktif", class.ClassName)
+	}
+	if class.ClassCodeExpiresAt != nil && time.Now().After(*class.ClassCodeExpiresAt) {
+		return nil, fmt.Errorf("kode kelas sudah kedaluwarsa")
+	}
+
+	student, err := s.repo.LockUser(userID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if student.RoleID != constant.RoleIDStudent {
+		return nil, fmt.Errorf("hanya student yang dapat bergabung ke kelas")
+	}
+	if student.ClassID != nil && *student.ClassID == class.ID {
+		return nil, fmt.Errorf("kamu sudah terdaftar di kelas %s", class.ClassName)
+	}
+	if class.SchoolID != nil && student.SchoolID != nil && *class.SchoolID != *student.SchoolID {
+		return nil, fmt.Errorf("kelas %s milik sekolah lain", class.ClassName)
+	}
+
+	pending, err := s.repo.FindPendingJoinRequest(class.ID, userID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if pending != nil {
+		return pending, nil
+	}
+
+	request := &models.TClassJoinRequest{
+		ClassID: class.ID,
+		UserID:  userID,
+		Status:  constant.JoinRequestPending,
+	}
+
+	if !class.AutoApproveJoin {
+		request, err = s.repo.InsertJoinRequest(request)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		return request, s.log(class.ID, &userID, &userID, constant.RosterJoinRequested, nil, nil)
+	}
+
+	now := time.Now()
+	request.Status = constant.JoinRequestApproved
+	request.DecidedAt = &now
+	request, err = s.repo.InsertJoinRequest(request)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if err := enterClassSchool(s.repo, student, class); err != nil {
+		return nil, err
+	}
+	if err := s.placeStudent(student, class.ID, &userID, constant.RosterJoined); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (s *tClassRosterService) RegenerateClassCode(actorID int64, isSuper bool, classID int64, expiresAt *time.Time) (*models.MClass, error) {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.LockClass(classID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if expiresAt != nil && expiresAt.Before(time.Now()) {
+		return nil, fmt.Errorf("waktu kedaluwarsa kode kelas harus di masa depan")
+	}
+
+	code, err := helper.GenerateClassCode(classCodeLength)
+	if err != nil {
+		return nil, err
+	}
+
+	class, err := s.repo.UpdateClass(classID, map[string]interface{}{
+		"class_code":            code,
+		"class_code_expires_at": expiresAt,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return class, s.log(classID, nil, &actorID, constant.RosterCodeRegenerated, nil, nil)
+}
+
+func (s *tClassRosterService) UpdateClassJoinSettings(actorID int64, isSuper bool, classID int64, input *dto.UpdateClassJoinSettingsDto) (*models.MClass, error) {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.LockClass(classID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	updates := map[string]interface{}{}
+	if input.ClearClassCodeExpiry {
+		updates["class_code_expires_at"] = nil
+	} else if input.ClassCodeExpiresAt != nil {
+		updates["class_code_expires_at"] = *input.ClassCodeExpiresAt
+	}
+	if input.AutoApproveJoin != nil {
+		updates["auto_approve_join"] = *input.AutoApproveJoin
+	}
+	if len(updates) == 0 {
+		return nil, fmt.Errorf("tidak ada pengaturan yang diubah")
+	}
+
+	class, err := s.repo.UpdateClass(classID, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return class, s.log(classID, nil, &actorID, constant.RosterSettingsChanged, nil, nil)
+}
+
+func (s *tClassRosterService) GetClassJoinRequests(actorID int64, isSuper bool, classID int64, filter dto.ClassJoinRequestFilterDto) ([]models.TClassJoinRequest, queryspec.Page, error) {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	repo := s.repo.WithWhere("class_id = ?", classID)
+	if filter.Status != "" {
+		repo = repo.WithWhere("status = ?", filter.Status)
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountJoinRequests()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.WithPreloads("User").FindJoinRequestPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *tClassRosterService) DecideClassJoinRequest(actorID int64, isSuper bool, requestID int64, approve bool, note *string) (*models.TClassJoinRequest, error) {
+	request, err := s.repo.LockJoinRequest(requestID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.authorize(actorID, isSuper, request.ClassID); err != nil {
+		return nil, err
+	}
+	if request.Status != constant.JoinRequestPending {
+		return nil, fmt.Errorf("permintaan bergabung sudah %s", request.Status)
+	}
+
+	status := constant.JoinRequestRejected
+	if approve {
+		status = constant.JoinRequestApproved
+	}
+
+	updated, err := s.repo.UpdateJoinRequest(requestID, map[string]interface{}{
+		"status":     status,
+		"decided_by": actorID,
+		"decided_at": time.Now(),
+		"note":       note,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if !approve {
+		return updated, s.log(request.ClassID, &request.UserID, &actorID, constant.RosterJoinRejected, nil, note)
+	}
+
+	class, err := s.repo.LockClass(request.ClassID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	student, err := s.repo.LockUser(request.UserID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := enterClassSchool(s.repo, student, class); err != nil {
+		return nil, err
+	}
+	if err := s.placeStudent(student, request.ClassID, &actorID, constant.RosterJoined); err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// MoveClassStudent transfers a student to another class. Teachers must teach
+// both classes.
+func (s *tClassRosterService) MoveClassStudent(actorID int64, isSuper bool, classID int64, userID int64, toClassID int64) error {
+	if classID == toClassID {
+		return fmt.Errorf("kelas tujuan sama dengan kelas asal")
+	}
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return err
+	}
+	if err := s.authorize(actorID, isSuper, toClassID); err != nil {
+		return err
+	}
+	if _, err := s.repo.LockClass(toClassID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	student, err := s.memberStudent(classID, userID)
+	if err != nil {
+		return err
+	}
+
+	return s.placeStudent(student, toClassID, &actorID, constant.RosterMovedIn)
+}
+
+func (s *tClassRosterService) RemoveClassStudent(actorID int64, isSuper bool, classID int64, userID int64) error {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return err
+	}
+
+	if _, err := s.memberStudent(classID, userID); err != nil {
+		return err
+	}
+
+	if err := s.repo.SetUserClass(userID, nil); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.log(classID, &userID, &actorID, constant.RosterStudentRemoved, nil, nil)
+}
+
+func (s *tClassRosterService) AssignClassTeacher(actorID int64, isSuper bool, classID int64, teacherID int64) error {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return err
+	}
+
+	teacher, err := s.repo.FindUserByID(teacherID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if teacher.RoleID != constant.RoleIDTeacher {
+		return fmt.Errorf("user %d bukan teacher", teacherID)
+	}
+
+	class, err := s.repo.LockClass(classID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if err := enterClassSchool(s.repo, teacher, class); err != nil {
+		return err
+	}
+
+	assigned, err := s.repo.IsClassTeacher(classID, teacherID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if assigned {
+		return nil
+	}
+
+	if err := s.repo.AddClassTeacher(classID, teacherID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.log(classID, &teacherID, &actorID, constant.RosterTeacherAssigned, nil, nil)
+}
+
+func (s *tClassRosterService) RemoveClassTeacher(actorID int64, isSuper bool, classID int64, teacherID int64) error {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return err
+	}
+
+	assigned, err := s.repo.IsClassTeacher(classID, teacherID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if !assigned {
+		return fmt.Errorf("user %d bukan teacher di kelas ini", teacherID)
+	}
+
+	if err := s.repo.RemoveClassTeacher(classID, teacherID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return s.log(classID, &teacherID, &actorID, constant.RosterTeacherRemoved, nil, nil)
+}
+
+func (s *tClassRosterService) GetClassRosterLogs(actorID int64, isSuper bool, classID int64, filter dto.ClassRosterLogFilterDto) ([]models.TClassRosterLog, queryspec.Page, error) {
+	if err := s.authorize(actorID, isSuper, classID); err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	repo := s.repo.WithWhere("class_id = ?", classID)
+	if filter.Action != "" {
+		repo = repo.WithWhere("action = ?", filter.Action)
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountRosterLogs()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindRosterLogPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *tClassRosterService) authorize(actorID int64, isSuper bool, classID int64) error {
+	return authorizeClassTeacher(s.repo, actorID, isSuper, classID)
+}
+
+// authorizeClassTeacher allows super admins and teachers assigned to the class.
+func authorizeClassTeacher(repo sql.TClassRosterRepository, actorID int64, isSuper bool, classID int64) error {
+	if isSuper {
+		// Super is limited to their own school; the scoped lookup hides the rest.
+		if _, err := repo.FindClassByID(classID); err != nil {
+			return gorm_err.TranslateGormError(err)
+		}
+		return nil
+	}
+
+	ok, err := repo.IsClassTeacher(classID, actorID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if !ok {
+		return fmt.Errorf("kamu bukan teacher di kelas %d", classID)
+	}
+	return nil
+}
+
+func (s *tClassRosterService) memberStudent(classID int64, userID int64) (*models.User, error) {
+	student, err := s.repo.LockUser(userID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if student.ClassID == nil || *student.ClassID != classID {
+		return nil, fmt.Errorf("student %d tidak terdaftar di kelas %d", userID, classID)
+	}
+	return student, nil
+}
+
+// enterClassSchool makes sure user belongs to the school that owns class.
+// Users without a school are adopted by it, members of another school are
+// refused.
+func enterClassSchool(repo sql.TClassRosterRepository, user *models.User, class *models.MClass) error {
+	if class.SchoolID == nil {
+		return nil
+	}
+	if user.SchoolID != nil {
+		if *user.SchoolID != *class.SchoolID {
+			return fmt.Errorf("%s terdaftar di sekolah lain", user.Name)
+		}
+		return nil
+	}
+
+	if err := repo.SetUserSchool(user.ID, class.SchoolID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	user.SchoolID = class.SchoolID
+	return nil
+}
+
+// placeStudent sets the student's class and records the move out of the
+// previous class, if any, along with action on the new class.
+func (s *tClassRosterService) placeStudent(student *models.User, classID int64, actorID *int64, action string) error {
+	if err := s.repo.SetUserClass(student.ID, &classID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	logs := []*models.TClassRosterLog{{
+		ClassID:      classID,
+		UserID:       &student.ID,
+		ActorID:      actorID,
+		Action:       action,
+		OtherClassID: student.ClassID,
+	}}
+	if student.ClassID != nil {
+		logs = append(logs, &models.TClassRosterLog{
+			ClassID:      *student.ClassID,
+			UserID:       &student.ID,
+			ActorID:      actorID,
+			Action:       constant.RosterMovedOut,
+			OtherClassID: &classID,
+		})
+	}
+
+	if err := s.repo.InsertRosterLogs(logs); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	return events.Publish(s.GetDB(), events.ClassStudentJoined{
+		ClassID:  classID,
+		UserID:   student.ID,
+		JoinedAt: time.Now(),
+	})
+}
+
+func (s *tClassRosterService) log(classID int64, userID *int64, actorID *int64, action string, otherClassID *int64, note *string) error {
+	err := s.repo.InsertRosterLogs([]*models.TClassRosterLog{{
+		ClassID:      classID,
+		UserID:       userID,
+		ActorID:      actorID,
+		Action:       action,
+		OtherClassID: otherClassID,
+		Note:         note,
+	}})
+	return gorm_err.TranslateGormError(err)
+}
+
+// rosterDiffLogs records the members added to and removed from a class when
+// its roster is replaced wholesale.
+func rosterDiffLogs(classID int64, before []int64, after []int64, addAction string, removeAction string) []*models.TClassRosterLog {
+	inBefore := make(map[int64]bool, len(before))
+	for _, id := range before {
+		inBefore[id] = true
+	}
+	inAfter := make(map[int64]bool, len(after))
+	for _, id := range after {
+		inAfter[id] = true
+	}
+
+	var logs []*models.TClassRosterLog
+	for _, id := range after {
+		if !inBefore[id] {
+			userID := id
+			logs = append(logs, &models.TClassRosterLog{ClassID: classID, UserID: &userID, Action: addAction})
+		}
+	}
+	for _, id := range before {
+		if !inAfter[id] {
+			userID := id
+			logs = append(logs, &models.TClassRosterLog{ClassID: classID, UserID: &userID, Action: removeAction})
+		}
+	}
+	return logs
+}
@@ -1,7 +1,9 @@
 package services
 
 import (
+	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
 	"jk-api/pkg/repository/adapter/sql"
@@ -11,25 +13,27 @@ import (
 
 type TCodeQuestionService interface {
 	WithTx(tx *gorm.DB) TCodeQuestionService
-	GetCodeQuestionsBySubLessonID(subLessonID int64) ([]models.CodeQuestion, error)
-	GetCodeQuestionByID(id int64) (*models.CodeQuestion, error)
+	GetCodeQuestionsBySubLessonID(subLessonID int64, filter dto.TCodeQuestionFilterDto) ([]models.CodeQuestion, error)
+	GetCodeQuestionByID(id int64, filter dto.TCodeQuestionFilterDto) (*models.CodeQuestion, error)
 	CreateCodeQuestion(data *models.CodeQuestion) (*models.CodeQuestion, error)
 	GetDB() *gorm.DB
 }
 
 type tCodeQuestionService struct {
-	repo sql.TCodeQuestionRepository
-	tx   *gorm.DB
+	repo  sql.TCodeQuestionRepository
+	media sql.MMediaRepository
+	tx    *gorm.DB
 }
 
-func NewTCodeQuestionService(repo sql.TCodeQuestionRepository) TCodeQuestionService {
-	return &tCodeQuestionService{repo: repo}
+func NewTCodeQuestionService(repo sql.TCodeQuestionRepository, media sql.MMediaRepository) TCodeQuestionService {
+	return &tCodeQuestionService{repo: repo, media: media}
 }
 
 func (s *tCodeQuestionService) WithTx(tx *gorm.DB) TCodeQuestionService {
 	return &tCodeQuestionService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:  s.repo.WithTx(tx),
+		media: s.media.WithTx(tx),
+		tx:    tx,
 	}
 }
 
@@ -40,15 +44,28 @@ func (s *tCodeQuestionService) GetDB() *gorm.DB {
 	return config.DB
 }
 
-func (s *tCodeQuestionService) GetCodeQuestionsBySubLessonID(subLessonID int64) ([]models.CodeQuestion, error) {
-	data, err := s.repo.FindTCodeQuestionsBySubLessonID(subLessonID)
+func (s *tCodeQuestionService) GetCodeQuestionsBySubLessonID(subLessonID int64, filter dto.TCodeQuestionFilterDto) ([]models.CodeQuestion, error) {
+	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere("t_code_question.status = ?", constant.ContentPublished)
+	}
+	data, err := repo.FindTCodeQuestionsBySubLessonID(subLessonID)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+	if !filter.Preview {
+		for i := range data {
+			data[i].EssayQuestions = publishedEssayQuestions(data[i].EssayQuestions)
+		}
+	}
 	return data, nil
 }
 
 func (s *tCodeQuestionService) CreateCodeQuestion(data *models.CodeQuestion) (*models.CodeQuestion, error) {
+	if err := fillMediaURL(s.media, data.ImageMediaID, &data.Image); err != nil {
+		return nil, err
+	}
+
 	data, err := s.repo.CreateTCodeQuestion(data)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
@@ -56,13 +73,32 @@ func (s *tCodeQuestionService) CreateCodeQuestion(data *models.CodeQuestion) (*m
 	return data, nil
 }
 
-func (s *tCodeQuestionService) GetCodeQuestionByID(id int64) (*models.CodeQuestion, error) {
-	data, err := s.repo.FindTCodeQuestionByID(id)
+func (s *tCodeQuestionService) GetCodeQuestionByID(id int64, filter dto.TCodeQuestionFilterDto) (*models.CodeQuestion, error) {
+	repo := s.repo
+	if !filter.Preview {
+		repo = repo.WithWhere("t_code_question.status = ?", constant.ContentPublished)
+	}
+	data, err := repo.FindTCodeQuestionByID(id)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+	if !filter.Preview {
+		data.EssayQuestions = publishedEssayQuestions(data.EssayQuestions)
+	}
 	return data, nil
 }
 
+// publishedEssayQuestions drops the generated essay questions no teacher has
+// accepted yet.
+func publishedEssayQuestions(list []models.EssayQuestion) []models.EssayQuestion {
+	published := make([]models.EssayQuestion, 0, len(list))
+	for _, item := range list {
+		if item.Status == constant.ContentPublished {
+			published = append(published, item)
+		}
+	}
+	return published
+}
+
 
 
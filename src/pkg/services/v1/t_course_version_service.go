@@ -0,0 +1,599 @@
+package services
+
+import (
+	"encoding/json"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"sort"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// changeTypeSubLesson marks sub-lessons in a version diff; courses, lessons
+// and materials use their content type.
+const changeTypeSubLesson = "sub_lesson"
+
+type TCourseVersionService interface {
+	WithTx(tx *gorm.DB) TCourseVersionService
+
+	CreateCourseVersion(courseID int64, publishedBy *int64) (*models.TCourseVersion, error)
+	GetCourseVersions(filter dto.CourseVersionFilterDto) ([]models.TCourseVersion, queryspec.Page, error)
+	GetCourseVersionByID(id int64) (*models.TCourseVersion, error)
+	DiffCourseVersions(fromID int64, toID int64) (*dto.CourseVersionDiffDto, error)
+	RollbackCourse(actorID int64, versionID int64) (*models.MCourse, error)
+	MigrateEnrollment(actorID int64, isStaff bool, studentCourseID int64, versionID *int64) (*models.TStudentCourse, error)
+	OnCoursePublished(tx *gorm.DB, event events.Event) error
+	PinEnrollment(studentCourseID int64, courseID int64) error
+	OnCourseEnrolled(tx *gorm.DB, event events.Event) error
+	GetDB() *gorm.DB
+}
+
+type tCourseVersionService struct {
+	repo sql.TCourseVersionRepository
+	tx   *gorm.DB
+}
+
+func NewTCourseVersionService(repo sql.TCourseVersionRepository) TCourseVersionService {
+	return &tCourseVersionService{repo: repo}
+}
+
+func (s *tCourseVersionService) WithTx(tx *gorm.DB) TCourseVersionService {
+	return &tCourseVersionService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *tCourseVersionService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// CreateCourseVersion snapshots the published lessons, sub-lessons and
+// materials of the course as its next version.
+func (s *tCourseVersionService) CreateCourseVersion(courseID int64, publishedBy *int64) (*models.TCourseVersion, error) {
+	course, err := s.repo.FindCourseTree(courseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	latest, err := s.repo.FindLatestCourseVersion(courseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	next := 1
+	if latest != nil {
+		next = latest.Version + 1
+	}
+
+	snapshot, subLessonIDs := buildCourseSnapshot(course)
+	data, err := s.repo.InsertCourseVersion(&models.TCourseVersion{
+		CourseID:     courseID,
+		Version:      next,
+		Snapshot:     datatypes.NewJSONType(snapshot),
+		SubLessonIDs: datatypes.JSONSlice[int64](subLessonIDs),
+		PublishedBy:  publishedBy,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *tCourseVersionService) GetCourseVersions(filter dto.CourseVersionFilterDto) ([]models.TCourseVersion, queryspec.Page, error) {
+	if filter.CourseID == 0 {
+		return nil, queryspec.Page{}, fmt.Errorf("course_id wajib diisi")
+	}
+	if _, err := s.repo.FindCourseByID(filter.CourseID); err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	repo := s.repo.
+		WithWhere("course_id = ?", filter.CourseID).
+		WithSpec(filter.Spec)
+
+	total, err := repo.CountCourseVersions()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindCourseVersionPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// GetCourseVersionByID only finds versions of courses visible to the caller.
+func (s *tCourseVersionService) GetCourseVersionByID(id int64) (*models.TCourseVersion, error) {
+	data, err := s.repo.FindCourseVersionByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if _, err := s.repo.FindCourseByID(data.CourseID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *tCourseVersionService) DiffCourseVersions(fromID int64, toID int64) (*dto.CourseVersionDiffDto, error) {
+	from, err := s.GetCourseVersionByID(fromID)
+	if err != nil {
+		return nil, err
+	}
+	to, err := s.GetCourseVersionByID(toID)
+	if err != nil {
+		return nil, err
+	}
+	if from.CourseID != to.CourseID {
+		return nil, fmt.Errorf("versi yang dibandingkan harus dari course yang sama")
+	}
+
+	return &dto.CourseVersionDiffDto{
+		CourseID:    from.CourseID,
+		FromVersion: from.Version,
+		ToVersion:   to.Version,
+		Changes:     diffCourseSnapshots(from.Snapshot.Data(), to.Snapshot.Data()),
+	}, nil
+}
+
+// RollbackCourse restores the course content to a version. Rows that were
+// deleted since are recreated, lessons and materials added since are
+// archived and sub-lessons added to restored lessons are removed. The
+// course goes back to draft so the restored content is reviewed and
+// published as a new version; pinned enrollments are not touched.
+func (s *tCourseVersionService) RollbackCourse(actorID int64, versionID int64) (*models.MCourse, error) {
+	version, err := s.GetCourseVersionByID(versionID)
+	if err != nil {
+		return nil, err
+	}
+
+	course, err := s.repo.FindCourseTree(version.CourseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	snapshot := version.Snapshot.Data()
+
+	err = s.repo.UpdateCourseContent(course.ID, map[string]interface{}{
+		"course_name":   snapshot.CourseName,
+		"description":   snapshot.Description,
+		"img_thumbnail": snapshot.ImgThumbnail,
+		"status":        constant.ContentDraft,
+		"published":     false,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	lessons := map[int64]*models.MLesson{}
+	subLessons := map[int64]*models.MSubLesson{}
+	materials := map[int64]*models.MMaterials{}
+	if course.Lessons != nil {
+		for i := range *course.Lessons {
+			lesson := &(*course.Lessons)[i]
+			lessons[lesson.ID] = lesson
+			for j := range lesson.SubLessons {
+				subLesson := &lesson.SubLessons[j]
+				subLessons[subLesson.ID] = subLesson
+				for k := range subLesson.Materials {
+					materials[subLesson.Materials[k].ID] = &subLesson.Materials[k]
+				}
+			}
+		}
+	}
+
+	keptLessons := map[int64]bool{}
+	keptSubLessons := map[int64]bool{}
+	keptMaterials := map[int64]bool{}
+
+	for _, lessonSnapshot := range snapshot.Lessons {
+		lesson := lessons[lessonSnapshot.ID]
+		if lesson == nil {
+			lesson = &models.MLesson{CourseID: course.ID}
+		}
+		lesson.LevelID = lessonSnapshot.LevelID
+		lesson.Title = lessonSnapshot.Title
+		lesson.Description = lessonSnapshot.Description
+		lesson.Position = lessonSnapshot.Position
+		lesson.ImgThumbnail = lessonSnapshot.ImgThumbnail
+		lesson.Status = constant.ContentPublished
+		lesson.Published = true
+		if err := s.repo.SaveLesson(lesson); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		keptLessons[lesson.ID] = true
+
+		for _, subLessonSnapshot := range lessonSnapshot.SubLessons {
+			subLesson := subLessons[subLessonSnapshot.ID]
+			if subLesson == nil {
+				subLesson = &models.MSubLesson{}
+			}
+			subLesson.LessonID = lesson.ID
+			subLesson.Title = subLessonSnapshot.Title
+			subLesson.OrderPosition = subLessonSnapshot.OrderPosition
+			if err := s.repo.SaveSubLesson(subLesson); err != nil {
+				return nil, gorm_err.TranslateGormError(err)
+			}
+			keptSubLessons[subLesson.ID] = true
+
+			for _, materialSnapshot := range subLessonSnapshot.Materials {
+				material := materials[materialSnapshot.ID]
+				if material == nil {
+					material = &models.MMaterials{}
+				}
+				material.SubLessonID = subLesson.ID
+				material.Title = materialSnapshot.Title
+				if err := restoreMaterialContent(material, materialSnapshot.Materials); err != nil {
+					return nil, err
+				}
+				material.URLVideo = materialSnapshot.URLVideo
+				material.VideoMediaID = materialSnapshot.VideoMediaID
+				material.ContentPosition = materialSnapshot.ContentPosition
+				material.PromptLLM = materialSnapshot.PromptLLM
+				material.PromptTemplateVersionID = materialSnapshot.PromptTemplateVersionID
+				material.Status = constant.ContentPublished
+				material.Published = true
+				if err := s.repo.SaveMaterial(material); err != nil {
+					return nil, gorm_err.TranslateGormError(err)
+				}
+				keptMaterials[material.ID] = true
+			}
+		}
+	}
+
+	var archiveLessons, removeSubLessons, archiveMaterials []int64
+	for id := range lessons {
+		if !keptLessons[id] {
+			archiveLessons = append(archiveLessons, id)
+		}
+	}
+	for id, subLesson := range subLessons {
+		if !keptSubLessons[id] && keptLessons[subLesson.LessonID] {
+			removeSubLessons = append(removeSubLessons, id)
+		}
+	}
+	for id := range materials {
+		if !keptMaterials[id] {
+			archiveMaterials = append(archiveMaterials, id)
+		}
+	}
+
+	if err := s.repo.ArchiveLessons(archiveLessons); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.repo.ArchiveMaterials(archiveMaterials); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.repo.RemoveSubLessons(removeSubLessons); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	comment := fmt.Sprintf("Dikembalikan ke versi %d", version.Version)
+	err = s.repo.InsertContentTransition(&models.TContentTransition{
+		ContentType: constant.ContentTypeCourse,
+		ContentID:   course.ID,
+		Action:      constant.ContentActionRollback,
+		FromStatus:  course.Status,
+		ToStatus:    constant.ContentDraft,
+		ActorID:     actorID,
+		Comment:     &comment,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if err := events.Publish(s.GetDB(), events.NewCourseStructureChanged(course.ID)); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.FindCourseTree(course.ID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// MigrateEnrollment pins an enrollment to a newer version of its course.
+// Students may only migrate their own enrollments; progress on sub-lessons
+// that carried over is kept.
+func (s *tCourseVersionService) MigrateEnrollment(actorID int64, isStaff bool, studentCourseID int64, versionID *int64) (*models.TStudentCourse, error) {
+	enrollment, err := s.repo.LockStudentCourse(studentCourseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if isStaff {
+		if _, err := s.repo.FindVisibleUserByID(enrollment.UserID); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	} else if enrollment.UserID != actorID {
+		return nil, gorm_err.TranslateGormError(gorm.ErrRecordNotFound)
+	}
+
+	var target *models.TCourseVersion
+	if versionID != nil {
+		target, err = s.repo.FindCourseVersionByID(*versionID)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		if target.CourseID != enrollment.CourseID {
+			return nil, fmt.Errorf("versi %d bukan milik course ini", *versionID)
+		}
+	} else {
+		target, err = s.repo.FindLatestCourseVersion(enrollment.CourseID)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		if target == nil {
+			return nil, fmt.Errorf("course belum memiliki versi")
+		}
+	}
+
+	if enrollment.CourseVersionID != nil {
+		current, err := s.repo.FindCourseVersionByID(*enrollment.CourseVersionID)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		if target.Version <= current.Version {
+			return nil, fmt.Errorf("enrollment sudah berada di versi %d", current.Version)
+		}
+	}
+
+	if err := s.repo.UpdateStudentCourseVersion(enrollment.ID, target.ID); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	err = events.Publish(s.GetDB(), events.EnrollmentVersionChanged{
+		UserID:          enrollment.UserID,
+		CourseID:        enrollment.CourseID,
+		StudentCourseID: enrollment.ID,
+		FromVersionID:   enrollment.CourseVersionID,
+		ToVersionID:     target.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.LockStudentCourse(enrollment.ID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// OnCoursePublished snapshots every publish of a course as a new version.
+func (s *tCourseVersionService) OnCoursePublished(tx *gorm.DB, event events.Event) error {
+	published, ok := event.(events.CoursePublished)
+	if !ok {
+		return nil
+	}
+
+	var publishedBy *int64
+	if published.PublishedBy != 0 {
+		publishedBy = &published.PublishedBy
+	}
+
+	_, err := s.WithTx(tx).CreateCourseVersion(published.CourseID, publishedBy)
+	return err
+}
+
+// PinEnrollment pins an enrollment to the latest version of its course.
+// Courses published before versioning get their first version here.
+func (s *tCourseVersionService) PinEnrollment(studentCourseID int64, courseID int64) error {
+	latest, err := s.repo.FindLatestCourseVersion(courseID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if latest == nil {
+		if latest, err = s.CreateCourseVersion(courseID, nil); err != nil {
+			return err
+		}
+	}
+
+	err = s.repo.UpdateStudentCourseVersion(studentCourseID, latest.ID)
+	return gorm_err.TranslateGormError(err)
+}
+
+func (s *tCourseVersionService) OnCourseEnrolled(tx *gorm.DB, event events.Event) error {
+	enrolled, ok := event.(events.CourseEnrolled)
+	if !ok {
+		return nil
+	}
+	return s.WithTx(tx).PinEnrollment(enrolled.StudentCourseID, enrolled.CourseID)
+}
+
+// buildCourseSnapshot returns the published content of the course in
+// display order, together with the ids of its sub-lessons.
+func buildCourseSnapshot(course *models.MCourse) (models.CourseSnapshot, []int64) {
+	snapshot := models.CourseSnapshot{
+		CourseName:   course.CourseName,
+		Description:  course.Description,
+		ImgThumbnail: course.ImgThumbnail,
+		Lessons:      []models.LessonSnapshot{},
+	}
+	subLessonIDs := []int64{}
+
+	var lessons []models.MLesson
+	if course.Lessons != nil {
+		lessons = append(lessons, *course.Lessons...)
+	}
+	sort.SliceStable(lessons, func(i, j int) bool {
+		if lessons[i].Position != lessons[j].Position {
+			return lessons[i].Position < lessons[j].Position
+		}
+		return lessons[i].ID < lessons[j].ID
+	})
+
+	for _, lesson := range lessons {
+		if lesson.Status != constant.ContentPublished {
+			continue
+		}
+
+		subLessons := append([]models.MSubLesson(nil), lesson.SubLessons...)
+		sort.SliceStable(subLessons, func(i, j int) bool {
+			if subLessons[i].OrderPosition != subLessons[j].OrderPosition {
+				return subLessons[i].OrderPosition < subLessons[j].OrderPosition
+			}
+			return subLessons[i].ID < subLessons[j].ID
+		})
+
+		lessonSnapshot := models.LessonSnapshot{
+			ID:           lesson.ID,
+			LevelID:      lesson.LevelID,
+			Title:        lesson.Title,
+			Description:  lesson.Description,
+			Position:     lesson.Position,
+			ImgThumbnail: lesson.ImgThumbnail,
+			SubLessons:   []models.SubLessonSnapshot{},
+		}
+
+		for _, subLesson := range subLessons {
+			materials := append([]models.MMaterials(nil), subLesson.Materials...)
+			sort.SliceStable(materials, func(i, j int) bool {
+				if materials[i].ContentPosition != materials[j].ContentPosition {
+					return materials[i].ContentPosition < materials[j].ContentPosition
+				}
+				return materials[i].ID < materials[j].ID
+			})
+
+			subLessonSnapshot := models.SubLessonSnapshot{
+				ID:            subLesson.ID,
+				Title:         subLesson.Title,
+				OrderPosition: subLesson.OrderPosition,
+				Materials:     []models.MaterialSnapshot{},
+			}
+			for _, material := range materials {
+				if material.Status != constant.ContentPublished {
+					continue
+				}
+				subLessonSnapshot.Materials = append(subLessonSnapshot.Materials, models.MaterialSnapshot{
+					ID:                      material.ID,
+					Title:                   material.Title,
+					Materials:               json.RawMessage(material.Materials),
+					URLVideo:                material.URLVideo,
+					VideoMediaID:            material.VideoMediaID,
+					ContentPosition:         material.ContentPosition,
+					PromptLLM:               material.PromptLLM,
+					PromptTemplateVersionID: material.PromptTemplateVersionID,
+				})
+			}
+
+			lessonSnapshot.SubLessons = append(lessonSnapshot.SubLessons, subLessonSnapshot)
+			subLessonIDs = append(subLessonIDs, subLesson.ID)
+		}
+
+		snapshot.Lessons = append(snapshot.Lessons, lessonSnapshot)
+	}
+
+	return snapshot, subLessonIDs
+}
+
+// diffCourseSnapshots lists what changed from one snapshot to the other:
+// the course itself first, then lessons, sub-lessons and materials in the
+// order of the newer snapshot, followed by what was removed.
+func diffCourseSnapshots(from models.CourseSnapshot, to models.CourseSnapshot) []dto.CourseVersionChangeDto {
+	changes := []dto.CourseVersionChangeDto{}
+
+	courseFields := fieldDiff(nil).
+		add("course_name", from.CourseName != to.CourseName).
+		add("description", from.Description != to.Description).
+		add("img_thumbnail", from.ImgThumbnail != to.ImgThumbnail)
+	if len(courseFields) > 0 {
+		changes = append(changes, dto.CourseVersionChangeDto{
+			Type:   constant.ContentTypeCourse,
+			Title:  to.CourseName,
+			Change: "modified",
+			Fields: courseFields,
+		})
+	}
+
+	oldLessons := map[int64]models.LessonSnapshot{}
+	oldSubLessons := map[int64]models.SubLessonSnapshot{}
+	oldMaterials := map[int64]models.MaterialSnapshot{}
+	for _, lesson := range from.Lessons {
+		oldLessons[lesson.ID] = lesson
+		for _, subLesson := range lesson.SubLessons {
+			oldSubLessons[subLesson.ID] = subLesson
+			for _, material := range subLesson.Materials {
+				oldMaterials[material.ID] = material
+			}
+		}
+	}
+
+	seen := map[string]bool{}
+	record := func(kind string, id int64, title string, fields []string, existed bool) {
+		seen[fmt.Sprintf("%s:%d", kind, id)] = true
+		switch {
+		case !existed:
+			changes = append(changes, dto.CourseVersionChangeDto{Type: kind, ID: id, Title: title, Change: "added"})
+		case len(fields) > 0:
+			changes = append(changes, dto.CourseVersionChangeDto{Type: kind, ID: id, Title: title, Change: "modified", Fields: fields})
+		}
+	}
+
+	for _, lesson := range to.Lessons {
+		old, existed := oldLessons[lesson.ID]
+		record(constant.ContentTypeLesson, lesson.ID, lesson.Title, fieldDiff(nil).
+			add("title", old.Title != lesson.Title).
+			add("description", old.Description != lesson.Description).
+			add("position", old.Position != lesson.Position).
+			add("level_id", old.LevelID != lesson.LevelID).
+			add("img_thumbnail", old.ImgThumbnail != lesson.ImgThumbnail), existed)
+
+		for _, subLesson := range lesson.SubLessons {
+			old, existed := oldSubLessons[subLesson.ID]
+			record(changeTypeSubLesson, subLesson.ID, subLesson.Title, fieldDiff(nil).
+				add("title", old.Title != subLesson.Title).
+				add("order_position", old.OrderPosition != subLesson.OrderPosition), existed)
+
+			for _, material := range subLesson.Materials {
+				old, existed := oldMaterials[material.ID]
+				record(constant.ContentTypeMaterial, material.ID, material.Title, fieldDiff(nil).
+					add("title", old.Title != material.Title).
+					add("materials", !sameDocument(old.Materials, material.Materials)).
+					add("url_video", old.URLVideo != material.URLVideo).
+					add("video_media_id", !sameMediaID(old.VideoMediaID, material.VideoMediaID)).
+					add("content_position", old.ContentPosition != material.ContentPosition).
+					add("prompt_llm", old.PromptLLM != material.PromptLLM).
+					add("prompt_template_version_id", !sameMediaID(old.PromptTemplateVersionID, material.PromptTemplateVersionID)), existed)
+			}
+		}
+	}
+
+	removed := func(kind string, id int64, title string) {
+		if !seen[fmt.Sprintf("%s:%d", kind, id)] {
+			changes = append(changes, dto.CourseVersionChangeDto{Type: kind, ID: id, Title: title, Change: "removed"})
+		}
+	}
+	for _, lesson := range from.Lessons {
+		removed(constant.ContentTypeLesson, lesson.ID, lesson.Title)
+		for _, subLesson := range lesson.SubLessons {
+			removed(changeTypeSubLesson, subLesson.ID, subLesson.Title)
+			for _, material := range subLesson.Materials {
+				removed(constant.ContentTypeMaterial, material.ID, material.Title)
+			}
+		}
+	}
+
+	return changes
+}
+
+// fieldDiff collects the names of changed fields.
+type fieldDiff []string
+
+func (d fieldDiff) add(name string, changed bool) fieldDiff {
+	if changed {
+		return append(d, name)
+	}
+	return d
+}
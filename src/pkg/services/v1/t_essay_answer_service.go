@@ -4,7 +4,9 @@ import (
 	"jk-api/internal/config"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
 	"jk-api/pkg/repository/adapter/sql"
+	"time"
 
 	"gorm.io/gorm"
 )
@@ -13,6 +15,7 @@ type TEssayAnswerService interface {
 	WithTx(tx *gorm.DB) TEssayAnswerService
 	GetTEssayAnswersByEssayQuestionIDAndUserID(essayQuestionID, userID int64) (*models.TEssayAnswer, error)
 	CreateTEssayAnswer(data *models.TEssayAnswer, userID int64) (*models.TEssayAnswer, error)
+	ApproveTEssayAnswer(id int64, teacherID int64, teacherNotes *string) (*models.TEssayAnswer, error)
 	GetDB() *gorm.DB
 }
 
@@ -55,6 +58,38 @@ func (s *tEssayAnswerService) CreateTEssayAnswer(data *models.TEssayAnswer, user
 	return data, nil
 }
 
+func (s *tEssayAnswerService) ApproveTEssayAnswer(id int64, teacherID int64, teacherNotes *string) (*models.TEssayAnswer, error) {
+	existing, err := s.repo.FindTEssayAnswerByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
 
+	if existing.IsApprovedByTeacher {
+		return existing, nil
+	}
+
+	updates := map[string]interface{}{
+		"is_approved_by_teacher": true,
+		"updated_at":             time.Now(),
+	}
+	if teacherNotes != nil {
+		updates["teacher_notes"] = *teacherNotes
+	}
 
+	data, err := s.repo.UpdateTEssayAnswer(id, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
 
+	err = events.Publish(s.GetDB(), events.EssayApproved{
+		EssayAnswerID:   data.ID,
+		EssayQuestionID: data.EssayQuestionID,
+		UserID:          data.UserID,
+		ApprovedBy:      teacherID,
+		ApprovedAt:      time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
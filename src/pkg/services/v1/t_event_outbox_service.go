@@ -0,0 +1,162 @@
+package services
+
+import (
+	"context"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/pkg/repository/adapter/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	outboxBaseBackoff = 5 * time.Second
+	outboxMaxBackoff  = time.Hour
+)
+
+type TEventOutboxService interface {
+	WithTx(tx *gorm.DB) TEventOutboxService
+
+	RelayPending(ctx context.Context, batchSize int, maxAttempts int) (int, error)
+	GetAllTEventOutboxes(filter dto.TEventOutboxFilterDto) ([]models.TEventOutbox, queryspec.Page, error)
+	GetTEventOutboxByID(id int64) (*models.TEventOutbox, error)
+	RetryTEventOutbox(id int64) (*models.TEventOutbox, error)
+	GetDB() *gorm.DB
+}
+
+type tEventOutboxService struct {
+	repo sql.TEventOutboxRepository
+	tx   *gorm.DB
+}
+
+func NewTEventOutboxService(repo sql.TEventOutboxRepository) TEventOutboxService {
+	return &tEventOutboxService{repo: repo}
+}
+
+func (s *tEventOutboxService) WithTx(tx *gorm.DB) TEventOutboxService {
+	return &tEventOutboxService{
+		repo: s.repo.WithTx(tx),
+		tx:   tx,
+	}
+}
+
+func (s *tEventOutboxService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// RelayPending dispatches up to batchSize due events to their async handlers.
+// Failed events are retried with exponential backoff and moved to the dead
+// status once they have failed maxAttempts times. It returns the number of
+// events dispatched successfully.
+func (s *tEventOutboxService) RelayPending(ctx context.Context, batchSize int, maxAttempts int) (int, error) {
+	now := time.Now()
+
+	rows, err := s.repo.ClaimPendingTEventOutboxes(batchSize, now)
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+
+	dispatched := 0
+	for _, row := range rows {
+		attempts := row.Attempts + 1
+		updates := map[string]interface{}{
+			"attempts":   attempts,
+			"updated_at": time.Now(),
+		}
+
+		if dispatchErr := events.Dispatch(events.WithEventID(ctx, row.ID), row.EventName, row.Payload); dispatchErr != nil {
+			message := dispatchErr.Error()
+			updates["last_error"] = message
+
+			if attempts >= maxAttempts {
+				updates["status"] = constant.OutboxDead
+				config.Logger.Errorf("❌ Event %d (%s) dead-lettered after %d attempts: %s", row.ID, row.EventName, attempts, message)
+			} else {
+				updates["available_at"] = time.Now().Add(outboxBackoff(attempts))
+				config.Logger.Warnf("⚠️ Event %d (%s) failed, attempt %d: %s", row.ID, row.EventName, attempts, message)
+			}
+		} else {
+			updates["status"] = constant.OutboxDispatched
+			updates["dispatched_at"] = time.Now()
+			dispatched++
+		}
+
+		if _, err := s.repo.UpdateTEventOutbox(row.ID, updates); err != nil {
+			return dispatched, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	return dispatched, nil
+}
+
+func (s *tEventOutboxService) GetAllTEventOutboxes(filter dto.TEventOutboxFilterDto) ([]models.TEventOutbox, queryspec.Page, error) {
+	repo := s.repo
+
+	if filter.Status != "" {
+		repo = repo.WithWhere("status = ?", filter.Status)
+	}
+	if filter.EventName != "" {
+		repo = repo.WithWhere("event_name = ?", filter.EventName)
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountTEventOutboxes()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindTEventOutboxPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *tEventOutboxService) GetTEventOutboxByID(id int64) (*models.TEventOutbox, error) {
+	data, err := s.repo.FindTEventOutboxByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// RetryTEventOutbox puts a dead or failing event back in the queue with a
+// fresh attempt budget.
+func (s *tEventOutboxService) RetryTEventOutbox(id int64) (*models.TEventOutbox, error) {
+	if _, err := s.repo.FindTEventOutboxByID(id); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	data, err := s.repo.UpdateTEventOutbox(id, map[string]interface{}{
+		"status":       constant.OutboxPending,
+		"attempts":     0,
+		"available_at": time.Now(),
+		"updated_at":   time.Now(),
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func outboxBackoff(attempts int) time.Duration {
+	backoff := outboxBaseBackoff
+	for i := 1; i < attempts; i++ {
+		backoff *= 2
+		if backoff >= outboxMaxBackoff {
+			return outboxMaxBackoff
+		}
+	}
+	return backoff
+}
@@ -0,0 +1,138 @@
+package services
+
+import (
+	"context"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/events"
+	"jk-api/pkg/repository/adapter/sql"
+	"testing"
+	"time"
+)
+
+func TestOutboxBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{1, 5 * time.Second},
+		{2, 10 * time.Second},
+		{3, 20 * time.Second},
+		{4, 40 * time.Second},
+		{5, 80 * time.Second},
+		{10, 2560 * time.Second},
+		{11, time.Hour},
+		{50, time.Hour},
+	}
+
+	for _, tt := range tests {
+		if got := outboxBackoff(tt.attempts); got != tt.want {
+			t.Errorf("outboxBackoff(%d) = %v, want %v", tt.attempts, got, tt.want)
+		}
+	}
+}
+
+func TestRelayPendingDeadLetters(t *testing.T) {
+	tests := []struct {
+		name        string
+		maxAttempts int
+	}{
+		{"single attempt", 1},
+		{"three attempts", 3},
+		{"ten attempts", 10},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeOutboxRepository{row: models.TEventOutbox{
+				ID:        1,
+				EventName: "test.unknown",
+				Payload:   []byte(`{}`),
+				Status:    constant.OutboxPending,
+			}}
+			service := NewTEventOutboxService(repo)
+
+			runs := 0
+			for repo.row.Status == constant.OutboxPending {
+				runs++
+				if runs > tt.maxAttempts {
+					t.Fatalf("still pending after %d runs", runs-1)
+				}
+
+				before := time.Now()
+				dispatched, err := service.RelayPending(context.Background(), 10, tt.maxAttempts)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if dispatched != 0 {
+					t.Fatalf("dispatched = %d, want 0", dispatched)
+				}
+
+				if repo.row.Status == constant.OutboxPending {
+					wait := repo.row.AvailableAt.Sub(before)
+					if want := outboxBackoff(runs); wait < want || wait > want+time.Second {
+						t.Errorf("run %d: retried after %v, want %v", runs, wait, want)
+					}
+				}
+			}
+
+			if runs != tt.maxAttempts {
+				t.Errorf("dead after %d runs, want %d", runs, tt.maxAttempts)
+			}
+			if repo.row.Status != constant.OutboxDead || repo.row.Attempts != tt.maxAttempts {
+				t.Errorf("row = %s after %d attempts, want %s after %d", repo.row.Status, repo.row.Attempts, constant.OutboxDead, tt.maxAttempts)
+			}
+			if repo.row.LastError == nil || *repo.row.LastError == "" {
+				t.Error("last_error not recorded")
+			}
+		})
+	}
+}
+
+func TestRelayPendingDispatches(t *testing.T) {
+	repo := &fakeOutboxRepository{row: models.TEventOutbox{
+		ID:        1,
+		EventName: events.SubLessonCompletedEvent,
+		Payload:   []byte(`{}`),
+		Status:    constant.OutboxPending,
+	}}
+
+	dispatched, err := NewTEventOutboxService(repo).RelayPending(context.Background(), 10, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dispatched != 1 || repo.row.Status != constant.OutboxDispatched || repo.row.Attempts != 1 {
+		t.Errorf("dispatched = %d, row = %s after %d attempts", dispatched, repo.row.Status, repo.row.Attempts)
+	}
+}
+
+// fakeOutboxRepository holds a single outbox row, claimed whenever it is
+// pending.
+type fakeOutboxRepository struct {
+	sql.TEventOutboxRepository
+	row models.TEventOutbox
+}
+
+func (r *fakeOutboxRepository) ClaimPendingTEventOutboxes(limit int, now time.Time) ([]models.TEventOutbox, error) {
+	if r.row.Status != constant.OutboxPending {
+		return nil, nil
+	}
+	return []models.TEventOutbox{r.row}, nil
+}
+
+func (r *fakeOutboxRepository) UpdateTEventOutbox(id int64, updates map[string]interface{}) (*models.TEventOutbox, error) {
+	for column, value := range updates {
+		switch column {
+		case "attempts":
+			r.row.Attempts = value.(int)
+		case "status":
+			r.row.Status = value.(string)
+		case "last_error":
+			message := value.(string)
+			r.row.LastError = &message
+		case "available_at":
+			r.row.AvailableAt = value.(time.Time)
+		}
+	}
+	return &r.row, nil
+}
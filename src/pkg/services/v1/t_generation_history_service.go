@@ -0,0 +1,502 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/generation"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/richtext"
+	"jk-api/pkg/repository/adapter/sql"
+	"slices"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultGenerationCount = 3
+	maxGenerationCount     = 5
+	maxGenerationTopic     = 200
+	// maxGenerationContext caps how much of the existing materials goes into
+	// a prompt.
+	maxGenerationContext = 6000
+)
+
+var (
+	// ErrGenerationInvalid is returned for runs that can't be started or
+	// reviewed as asked.
+	ErrGenerationInvalid = errors.New("permintaan generasi tidak valid")
+	// ErrGenerationFailed is returned when the provider failed or answered
+	// something unusable. The run is still logged.
+	ErrGenerationFailed = errors.New("generasi konten gagal")
+)
+
+type TGenerationHistoryService interface {
+	WithTx(tx *gorm.DB) TGenerationHistoryService
+
+	GenerateContent(actorID int64, input *dto.GenerateContentDto) (*models.TGenerationHistory, error)
+	GetGenerations(filter dto.GenerationFilterDto) ([]models.TGenerationHistory, queryspec.Page, error)
+	GetGenerationByID(id int64) (*models.TGenerationHistory, error)
+	AcceptGeneration(actorID int64, id int64) (*models.TGenerationHistory, error)
+	DiscardGeneration(actorID int64, id int64) (*models.TGenerationHistory, error)
+	GetDB() *gorm.DB
+}
+
+type tGenerationHistoryService struct {
+	repo     sql.TGenerationHistoryRepository
+	prompts  sql.MPromptTemplateRepository
+	provider generation.LLMProvider
+	tx       *gorm.DB
+}
+
+func NewTGenerationHistoryService(repo sql.TGenerationHistoryRepository, prompts sql.MPromptTemplateRepository, provider generation.LLMProvider) TGenerationHistoryService {
+	return &tGenerationHistoryService{repo: repo, prompts: prompts, provider: provider}
+}
+
+func (s *tGenerationHistoryService) WithTx(tx *gorm.DB) TGenerationHistoryService {
+	return &tGenerationHistoryService{
+		repo:     s.repo.WithTx(tx),
+		prompts:  s.prompts.WithTx(tx),
+		provider: s.provider,
+		tx:       tx,
+	}
+}
+
+func (s *tGenerationHistoryService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// GenerateContent asks the provider for drafts and stores them with the log
+// of the run. The provider is called outside of any transaction; a failed
+// run is logged and returned together with ErrGenerationFailed.
+func (s *tGenerationHistoryService) GenerateContent(actorID int64, input *dto.GenerateContentDto) (*models.TGenerationHistory, error) {
+	if !slices.Contains(constant.GenerationKinds, input.Kind) {
+		return nil, fmt.Errorf("%w: kind harus salah satu dari %s", ErrGenerationInvalid, strings.Join(constant.GenerationKinds, ", "))
+	}
+	count := input.Count
+	if count == 0 {
+		count = defaultGenerationCount
+	}
+	if count < 1 || count > maxGenerationCount {
+		return nil, fmt.Errorf("%w: count harus antara 1 dan %d", ErrGenerationInvalid, maxGenerationCount)
+	}
+	if utf8.RuneCountInString(input.Topic) > maxGenerationTopic {
+		return nil, fmt.Errorf("%w: topic maksimal %d karakter", ErrGenerationInvalid, maxGenerationTopic)
+	}
+
+	subLesson, course, err := s.findSubLesson(input.SubLessonID)
+	if err != nil {
+		return nil, err
+	}
+	version, err := s.findPromptVersion(input)
+	if err != nil {
+		return nil, err
+	}
+
+	topic := strings.TrimSpace(input.Topic)
+	if topic == "" {
+		topic = subLesson.Title
+	}
+	pipeline := map[string]any{
+		constant.PromptVarCourse:    course.CourseName,
+		constant.PromptVarSubLesson: subLesson.Title,
+		constant.PromptVarTopic:     topic,
+		constant.PromptVarCount:     count,
+		constant.PromptVarContext:   materialContext(subLesson.Materials),
+	}
+	if lesson := subLesson.Lesson; lesson != nil {
+		pipeline[constant.PromptVarLesson] = lesson.Title
+		if lesson.Level != nil {
+			pipeline[constant.PromptVarLevel] = lesson.Level.LevelName
+		}
+	}
+
+	var codeQuestionID *int64
+	if input.Kind == constant.GenerationKindEssayQuestion {
+		if input.CodeQuestionID == nil {
+			return nil, fmt.Errorf("%w: code_question_id wajib diisi untuk soal esai", ErrGenerationInvalid)
+		}
+		question, err := s.repo.FindCodeQuestionByID(*input.CodeQuestionID)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		if question.SubLessonID != subLesson.ID {
+			return nil, fmt.Errorf("%w: soal kode bukan bagian dari sub-bab ini", ErrGenerationInvalid)
+		}
+		pipeline[constant.PromptVarCodeQuestion] = question.CodeQuestion
+		codeQuestionID = &question.ID
+	}
+
+	values, err := promptValues(version.Variables, pipeline, input.Variables)
+	if err != nil {
+		return nil, err
+	}
+	prompt, err := generation.Render(version.Body, version.Variables, values)
+	if err != nil {
+		return nil, err
+	}
+	req, err := generation.NewRequest(input.Kind, prompt, topic, count)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrGenerationInvalid, err)
+	}
+
+	history := &models.TGenerationHistory{
+		SubLessonID:             subLesson.ID,
+		TopicUsed:               topic,
+		Kind:                    input.Kind,
+		CodeQuestionID:          codeQuestionID,
+		PromptTemplateVersionID: &version.ID,
+		Variables:               datatypes.JSONMap(input.Variables),
+		Prompt:                  req.Prompt,
+		Provider:                s.provider.Name(),
+		CreatedBy:               &actorID,
+	}
+
+	started := time.Now()
+	resp, err := s.provider.Generate(s.GetDB().Statement.Context, *req)
+	history.GenerationTime = int(time.Since(started).Milliseconds())
+	if err != nil {
+		return s.logFailedRun(history, err)
+	}
+	history.Result = resp.Text
+	history.Model = resp.Model
+
+	err = s.GetDB().Transaction(func(tx *gorm.DB) error {
+		repo := s.repo.WithTx(tx)
+
+		ids, err := writeDrafts(repo, history, subLesson, resp.Text)
+		if err != nil {
+			return err
+		}
+
+		history.Status = constant.GenerationDrafted
+		history.ItemIDs = datatypes.JSONSlice[int64](ids)
+		_, err = repo.InsertGenerationHistory(history)
+		return err
+	})
+	if errors.Is(err, generation.ErrInvalidOutput) {
+		return s.logFailedRun(history, err)
+	}
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return history, nil
+}
+
+// logFailedRun stores a run that produced nothing, so failures show up in
+// the history too.
+func (s *tGenerationHistoryService) logFailedRun(history *models.TGenerationHistory, cause error) (*models.TGenerationHistory, error) {
+	history.Status = constant.GenerationFailed
+	history.Error = cause.Error()
+	history.ItemIDs = datatypes.JSONSlice[int64]{}
+	if _, err := s.repo.InsertGenerationHistory(history); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return history, fmt.Errorf("%w: %v", ErrGenerationFailed, cause)
+}
+
+// writeDrafts parses the answer of the provider and stores its items as
+// drafts in the sub-lesson.
+func writeDrafts(repo sql.TGenerationHistoryRepository, history *models.TGenerationHistory, subLesson *models.MSubLesson, text string) ([]int64, error) {
+	var ids []int64
+
+	switch history.Kind {
+	case constant.GenerationKindMaterial:
+		items, err := generation.ParseMaterials(text)
+		if err != nil {
+			return nil, err
+		}
+
+		position := 0
+		for _, m := range subLesson.Materials {
+			position = max(position, m.ContentPosition)
+		}
+
+		materials := make([]*models.MMaterials, 0, len(items))
+		for i, item := range items {
+			material := &models.MMaterials{
+				SubLessonID:             subLesson.ID,
+				Title:                   truncateRunes(strings.TrimSpace(item.Title), 150),
+				ContentPosition:         position + i + 1,
+				PromptTemplateVersionID: history.PromptTemplateVersionID,
+				Status:                  constant.ContentDraft,
+				IsActive:                true,
+			}
+			if err := storeMaterialDoc(material, richtext.FromText(item.Content)); err != nil {
+				return nil, err
+			}
+			materials = append(materials, material)
+		}
+		if err := repo.InsertMaterials(materials); err != nil {
+			return nil, err
+		}
+		for _, m := range materials {
+			ids = append(ids, m.ID)
+		}
+
+	case constant.GenerationKindCodeQuestion:
+		items, err := generation.ParseCodeQuestions(text)
+		if err != nil {
+			return nil, err
+		}
+
+		questions := make([]*models.CodeQuestion, 0, len(items))
+		for _, item := range items {
+			questions = append(questions, &models.CodeQuestion{
+				SubLessonID:  subLesson.ID,
+				CodeQuestion: strings.TrimSpace(item.Question),
+				Hint:         strings.TrimSpace(item.Hint),
+				Score:        max(item.Score, 0),
+				Status:       constant.ContentDraft,
+			})
+		}
+		if err := repo.InsertCodeQuestions(questions); err != nil {
+			return nil, err
+		}
+		for _, q := range questions {
+			ids = append(ids, q.ID)
+		}
+
+	case constant.GenerationKindEssayQuestion:
+		items, err := generation.ParseEssayQuestions(text)
+		if err != nil {
+			return nil, err
+		}
+
+		questions := make([]*models.EssayQuestion, 0, len(items))
+		for _, item := range items {
+			question := &models.EssayQuestion{
+				CodeQuestionID: *history.CodeQuestionID,
+				EssayQuestion:  strings.TrimSpace(item.Question),
+				Status:         constant.ContentDraft,
+			}
+			answers := []*string{&question.Answer, &question.Answer2, &question.Answer3, &question.Answer4}
+			for i, answer := range item.Answers {
+				if i == len(answers) {
+					break
+				}
+				*answers[i] = strings.TrimSpace(answer)
+			}
+			questions = append(questions, question)
+		}
+		if err := repo.InsertEssayQuestions(questions); err != nil {
+			return nil, err
+		}
+		for _, q := range questions {
+			ids = append(ids, q.ID)
+		}
+	}
+
+	return ids, nil
+}
+
+func (s *tGenerationHistoryService) GetGenerations(filter dto.GenerationFilterDto) ([]models.TGenerationHistory, queryspec.Page, error) {
+	if filter.SubLessonID == 0 {
+		return nil, queryspec.Page{}, fmt.Errorf("sub_lesson_id wajib diisi")
+	}
+	if _, _, err := s.findSubLesson(filter.SubLessonID); err != nil {
+		return nil, queryspec.Page{}, err
+	}
+
+	repo := s.repo.
+		WithWhere("sub_lesson_id = ?", filter.SubLessonID).
+		WithSpec(filter.Spec)
+
+	total, err := repo.CountGenerationHistories()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindGenerationHistoryPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// GetGenerationByID only finds runs for sub-lessons of courses visible to
+// the caller.
+func (s *tGenerationHistoryService) GetGenerationByID(id int64) (*models.TGenerationHistory, error) {
+	data, err := s.repo.FindGenerationHistoryByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if _, _, err := s.findSubLesson(data.SubLessonID); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// AcceptGeneration publishes the questions of a run. Generated materials
+// stay drafts and go through the editorial workflow like any other material.
+func (s *tGenerationHistoryService) AcceptGeneration(actorID int64, id int64) (*models.TGenerationHistory, error) {
+	data, err := s.reviewableRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch data.Kind {
+	case constant.GenerationKindCodeQuestion:
+		err = s.repo.PublishCodeQuestions(data.ItemIDs)
+	case constant.GenerationKindEssayQuestion:
+		err = s.repo.PublishEssayQuestions(data.ItemIDs)
+	}
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return s.finishReview(data, actorID, constant.GenerationAccepted)
+}
+
+// DiscardGeneration removes the items of a run that are still drafts.
+func (s *tGenerationHistoryService) DiscardGeneration(actorID int64, id int64) (*models.TGenerationHistory, error) {
+	data, err := s.reviewableRun(id)
+	if err != nil {
+		return nil, err
+	}
+
+	switch data.Kind {
+	case constant.GenerationKindMaterial:
+		err = s.repo.RemoveDraftMaterials(data.ItemIDs)
+	case constant.GenerationKindCodeQuestion:
+		err = s.repo.RemoveDraftCodeQuestions(data.ItemIDs)
+	case constant.GenerationKindEssayQuestion:
+		err = s.repo.RemoveDraftEssayQuestions(data.ItemIDs)
+	}
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	return s.finishReview(data, actorID, constant.GenerationDiscarded)
+}
+
+// reviewableRun locks a run and checks it still has drafts to review.
+func (s *tGenerationHistoryService) reviewableRun(id int64) (*models.TGenerationHistory, error) {
+	if _, err := s.GetGenerationByID(id); err != nil {
+		return nil, err
+	}
+
+	data, err := s.repo.LockGenerationHistory(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if data.Status != constant.GenerationDrafted {
+		return nil, fmt.Errorf("%w: generasi berstatus %s tidak bisa direview", ErrGenerationInvalid, data.Status)
+	}
+	return data, nil
+}
+
+func (s *tGenerationHistoryService) finishReview(data *models.TGenerationHistory, actorID int64, status string) (*models.TGenerationHistory, error) {
+	updates := map[string]interface{}{
+		"status":      status,
+		"reviewed_by": actorID,
+	}
+	if err := s.repo.UpdateGenerationHistory(data.ID, updates); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	data.Status = status
+	data.ReviewedBy = &actorID
+	return data, nil
+}
+
+// findSubLesson loads a sub-lesson and checks its course is visible to the
+// caller.
+func (s *tGenerationHistoryService) findSubLesson(id int64) (*models.MSubLesson, *models.MCourse, error) {
+	subLesson, err := s.repo.FindSubLessonByID(id)
+	if err != nil {
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+	if subLesson.Lesson == nil {
+		return nil, nil, gorm_err.TranslateGormError(gorm.ErrRecordNotFound)
+	}
+	course, err := s.repo.FindCourseByID(subLesson.Lesson.CourseID)
+	if err != nil {
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+	return subLesson, course, nil
+}
+
+// findPromptVersion returns the template version a run asked for, or the
+// current version of the default template of its kind.
+func (s *tGenerationHistoryService) findPromptVersion(input *dto.GenerateContentDto) (*models.TPromptTemplateVersion, error) {
+	var template *models.MPromptTemplate
+	var err error
+	if input.PromptTemplateID != nil {
+		template, err = s.prompts.FindPromptTemplateByID(*input.PromptTemplateID)
+	} else {
+		template, err = s.prompts.FindDefaultPromptTemplate(input.Kind)
+	}
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if template == nil {
+		return nil, fmt.Errorf("%w: belum ada template prompt bawaan untuk %s", ErrGenerationInvalid, input.Kind)
+	}
+	if template.Kind != input.Kind || !template.IsActive {
+		return nil, fmt.Errorf("%w: template prompt %d tidak bisa dipakai untuk %s", ErrGenerationInvalid, template.ID, input.Kind)
+	}
+
+	number := template.CurrentVersion
+	if input.PromptTemplateVersion != nil {
+		number = *input.PromptTemplateVersion
+	}
+	version, err := s.prompts.FindPromptTemplateVersion(template.ID, number)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return version, nil
+}
+
+// promptValues fills in the variables of a template: the pipeline supplies
+// those describing the run, the caller the others.
+func promptValues(vars []models.PromptVariable, pipeline map[string]any, input map[string]any) (map[string]any, error) {
+	values := make(map[string]any, len(vars))
+	for name, value := range input {
+		i := slices.IndexFunc(vars, func(v models.PromptVariable) bool { return v.Name == name })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: variabel %q tidak ada di template", generation.ErrInvalidVariables, name)
+		}
+		if !slices.Contains(constant.PromptInputVarTypes, vars[i].Type) {
+			return nil, fmt.Errorf("%w: variabel %q diisi otomatis", generation.ErrInvalidVariables, name)
+		}
+		values[name] = value
+	}
+	for _, v := range vars {
+		if value, ok := pipeline[v.Type]; ok {
+			values[v.Name] = value
+		}
+	}
+	return values, nil
+}
+
+// materialContext joins the text of the materials of a sub-lesson that
+// aren't archived, for prompts to build on.
+func materialContext(materials []models.MMaterials) string {
+	var parts []string
+	for _, m := range materials {
+		if m.Status == constant.ContentArchived || strings.TrimSpace(m.MaterialsText) == "" {
+			continue
+		}
+		parts = append(parts, m.Title+"\n"+m.MaterialsText)
+	}
+	return truncateRunes(strings.Join(parts, "\n\n"), maxGenerationContext)
+}
+
+func truncateRunes(s string, limit int) string {
+	if utf8.RuneCountInString(s) <= limit {
+		return s
+	}
+	return string([]rune(s)[:limit])
+}
@@ -0,0 +1,453 @@
+package services
+
+import (
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/tenant"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// guardianEssayLimit caps how many reviewed essays the dashboard shows.
+const guardianEssayLimit = 20
+
+type TGuardianLinkService interface {
+	WithTx(tx *gorm.DB) TGuardianLinkService
+
+	InviteStudent(guardianID int64, input *dto.InviteGuardianStudentDto) (*models.TGuardianLink, error)
+	DecideGuardianInvite(actorID int64, isSuper bool, id int64, accept bool) (*models.TGuardianLink, error)
+	RevokeGuardianLink(actorID int64, isSuper bool, id int64) (*models.TGuardianLink, error)
+	GetGuardianLinks(actorID int64, isSuper bool, filter dto.GuardianLinkFilterDto) ([]models.TGuardianLink, queryspec.Page, error)
+	GetChildren(guardianID int64) ([]dto.GuardianChildDto, error)
+	GetChildDashboard(guardianID int64, studentID int64, filter dto.GuardianDashboardFilterDto) (*dto.GuardianDashboardDto, error)
+	PublishWeeklyDigests(now time.Time) (int, error)
+	GetDB() *gorm.DB
+}
+
+type tGuardianLinkService struct {
+	repo     sql.TGuardianLinkRepository
+	dueItems TClassCourseService
+	tx       *gorm.DB
+}
+
+func NewTGuardianLinkService(repo sql.TGuardianLinkRepository, dueItems TClassCourseService) TGuardianLinkService {
+	return &tGuardianLinkService{repo: repo, dueItems: dueItems}
+}
+
+func (s *tGuardianLinkService) WithTx(tx *gorm.DB) TGuardianLinkService {
+	return &tGuardianLinkService{
+		repo:     s.repo.WithTx(tx),
+		dueItems: s.dueItems.WithTx(tx),
+		tx:       tx,
+	}
+}
+
+func (s *tGuardianLinkService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// InviteStudent creates a pending link from the guardian to a student. A
+// rejected or revoked link can be invited again.
+func (s *tGuardianLinkService) InviteStudent(guardianID int64, input *dto.InviteGuardianStudentDto) (*models.TGuardianLink, error) {
+	guardian, err := s.repo.FindUserByID(guardianID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if guardian.RoleID != constant.RoleIDGuardian {
+		return nil, fmt.Errorf("hanya wali murid yang dapat mengirim undangan")
+	}
+
+	login := strings.TrimSpace(input.Student)
+	if login == "" {
+		return nil, fmt.Errorf("email atau kode siswa wajib diisi")
+	}
+	student, err := s.repo.FindStudentByLogin(login)
+	if err != nil {
+		return nil, fmt.Errorf("siswa %s tidak ditemukan", login)
+	}
+
+	existing, err := s.repo.FindGuardianLink(guardianID, student.ID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	var link *models.TGuardianLink
+	switch {
+	case existing == nil:
+		link, err = s.repo.InsertGuardianLink(&models.TGuardianLink{
+			GuardianID:   guardianID,
+			StudentID:    student.ID,
+			Relationship: strings.TrimSpace(input.Relationship),
+			Status:       constant.GuardianLinkPending,
+		})
+	case existing.Status == constant.GuardianLinkPending || existing.Status == constant.GuardianLinkAccepted:
+		return nil, fmt.Errorf("undangan untuk siswa ini sudah %s", existing.Status)
+	default:
+		link, err = s.repo.UpdateGuardianLink(existing.ID, map[string]interface{}{
+			"relationship": strings.TrimSpace(input.Relationship),
+			"status":       constant.GuardianLinkPending,
+			"decided_by":   nil,
+			"decided_at":   nil,
+		})
+	}
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	err = events.Publish(s.GetDB(), events.GuardianInvited{
+		LinkID:       link.ID,
+		GuardianID:   guardianID,
+		GuardianName: guardian.Name,
+		StudentID:    student.ID,
+		InvitedAt:    time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+// DecideGuardianInvite accepts or rejects a pending invite. Only the student
+// or an admin of the student's school may decide.
+func (s *tGuardianLinkService) DecideGuardianInvite(actorID int64, isSuper bool, id int64, accept bool) (*models.TGuardianLink, error) {
+	link, err := s.repo.LockGuardianLink(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.authorizeStudentSide(actorID, isSuper, link); err != nil {
+		return nil, err
+	}
+	if link.Status != constant.GuardianLinkPending {
+		return nil, fmt.Errorf("undangan wali sudah %s", link.Status)
+	}
+
+	status := constant.GuardianLinkRejected
+	if accept {
+		status = constant.GuardianLinkAccepted
+	}
+
+	now := time.Now()
+	updated, err := s.repo.UpdateGuardianLink(id, map[string]interface{}{
+		"status":     status,
+		"decided_by": actorID,
+		"decided_at": now,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if !accept {
+		return updated, nil
+	}
+
+	student, err := s.repo.FindUserByID(link.StudentID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	err = events.Publish(s.GetDB(), events.GuardianLinkAccepted{
+		LinkID:      link.ID,
+		GuardianID:  link.GuardianID,
+		StudentID:   link.StudentID,
+		StudentName: student.Name,
+		AcceptedBy:  actorID,
+		AcceptedAt:  now,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// RevokeGuardianLink ends a pending or accepted link. The guardian, the
+// student or an admin of the student's school may revoke.
+func (s *tGuardianLinkService) RevokeGuardianLink(actorID int64, isSuper bool, id int64) (*models.TGuardianLink, error) {
+	link, err := s.repo.LockGuardianLink(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if actorID != link.GuardianID {
+		if err := s.authorizeStudentSide(actorID, isSuper, link); err != nil {
+			return nil, err
+		}
+	}
+	if link.Status != constant.GuardianLinkPending && link.Status != constant.GuardianLinkAccepted {
+		return nil, fmt.Errorf("undangan wali sudah %s", link.Status)
+	}
+
+	updated, err := s.repo.UpdateGuardianLink(id, map[string]interface{}{
+		"status":     constant.GuardianLinkRevoked,
+		"decided_by": actorID,
+		"decided_at": time.Now(),
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return updated, nil
+}
+
+// GetGuardianLinks lists the caller's own links. Admins see every link of the
+// students in their school.
+func (s *tGuardianLinkService) GetGuardianLinks(actorID int64, isSuper bool, filter dto.GuardianLinkFilterDto) ([]models.TGuardianLink, queryspec.Page, error) {
+	repo := s.repo
+	if isSuper {
+		if scope, ok := tenant.FromContext(s.GetDB().Statement.Context); ok && !scope.AllSchools {
+			if scope.SchoolID == nil {
+				repo = repo.WithWhere("student_id IN (SELECT id FROM users WHERE school_id IS NULL)")
+			} else {
+				repo = repo.WithWhere("student_id IN (SELECT id FROM users WHERE school_id = ?)", *scope.SchoolID)
+			}
+		}
+	} else {
+		repo = repo.WithWhere("guardian_id = ? OR student_id = ?", actorID, actorID)
+	}
+
+	if filter.Status != "" {
+		repo = repo.WithWhere("status = ?", filter.Status)
+	}
+	if filter.StudentID != 0 {
+		repo = repo.WithWhere("student_id = ?", filter.StudentID)
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountGuardianLinks()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindGuardianLinkPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *tGuardianLinkService) GetChildren(guardianID int64) ([]dto.GuardianChildDto, error) {
+	links, err := s.repo.
+		WithWhere("guardian_id = ? AND status = ?", guardianID, constant.GuardianLinkAccepted).
+		WithPreloads("Student.HasClass").
+		WithOrder("student_id ASC").
+		FindGuardianLinks()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	children := make([]dto.GuardianChildDto, 0, len(links))
+	for i := range links {
+		children = append(children, guardianChild(&links[i]))
+	}
+	return children, nil
+}
+
+// GetChildDashboard returns the child's progress, badges, essay feedback and
+// upcoming deadlines. Guardians are authorized by their accepted link rather
+// than by school, since their children may attend different schools.
+func (s *tGuardianLinkService) GetChildDashboard(guardianID int64, studentID int64, filter dto.GuardianDashboardFilterDto) (*dto.GuardianDashboardDto, error) {
+	link, err := s.acceptedLink(guardianID, studentID)
+	if err != nil {
+		return nil, err
+	}
+
+	courses, err := s.repo.FindStudentCourses(studentID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	essays, err := s.repo.FindReviewedEssays(studentID, nil, guardianEssayLimit)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	deadlines, err := s.dueItems.GetMyDueItems(studentID, dto.DueItemFilterDto{Days: filter.Days})
+	if err != nil {
+		return nil, err
+	}
+
+	dashboard := &dto.GuardianDashboardDto{
+		Child:         guardianChild(link),
+		Courses:       []dto.GuardianCourseProgressDto{},
+		Badges:        []dto.GuardianBadgeDto{},
+		EssayFeedback: []dto.GuardianEssayFeedbackDto{},
+		Deadlines:     deadlines,
+	}
+
+	for _, course := range courses {
+		progress := dto.GuardianCourseProgressDto{
+			CourseID:           course.CourseID,
+			ProgressPercentage: course.ProgressPercentage,
+			TotalScore:         course.TotalScore,
+		}
+		if course.Course != nil {
+			progress.CourseName = course.Course.CourseName
+		}
+		if course.Badge != nil {
+			badge := dto.GuardianBadgeDto{
+				BadgeID:    course.Badge.ID,
+				Name:       course.Badge.Name,
+				Image:      course.Badge.Image,
+				CourseID:   course.CourseID,
+				CourseName: progress.CourseName,
+			}
+			progress.Badge = &badge
+			dashboard.Badges = append(dashboard.Badges, badge)
+		}
+		dashboard.Courses = append(dashboard.Courses, progress)
+	}
+
+	for _, essay := range essays {
+		feedback := dto.GuardianEssayFeedbackDto{
+			EssayAnswerID:     essay.ID,
+			EssayQuestionID:   essay.EssayQuestionID,
+			KonteksPenjelasan: essay.KonteksPenjelasan,
+			Keruntutan:        essay.Keruntutan,
+			Kebenaran:         essay.Kebenaran,
+			TeacherNotes:      essay.TeacherNotes,
+			ReviewedAt:        essay.UpdatedAt,
+		}
+		if essay.EssayQuestion != nil {
+			feedback.Question = essay.EssayQuestion.EssayQuestion
+		}
+		dashboard.EssayFeedback = append(dashboard.EssayFeedback, feedback)
+	}
+
+	return dashboard, nil
+}
+
+// PublishWeeklyDigests raises one digest event per guardian whose digest for
+// the ISO week of now has not been sent, and returns how many were raised.
+// Running it again in the same week is a no-op.
+func (s *tGuardianLinkService) PublishWeeklyDigests(now time.Time) (int, error) {
+	year, weekNo := now.ISOWeek()
+	week := fmt.Sprintf("%d-W%02d", year, weekNo)
+
+	links, err := s.repo.FindDigestLinks(week)
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+
+	byGuardian := make(map[int64][]events.GuardianDigestChild)
+	var guardianIDs []int64
+	for i := range links {
+		child, err := s.digestChild(&links[i], now)
+		if err != nil {
+			return 0, err
+		}
+		if _, ok := byGuardian[links[i].GuardianID]; !ok {
+			guardianIDs = append(guardianIDs, links[i].GuardianID)
+		}
+		byGuardian[links[i].GuardianID] = append(byGuardian[links[i].GuardianID], child)
+	}
+
+	for _, guardianID := range guardianIDs {
+		err := events.Publish(s.GetDB(), events.GuardianWeeklyDigest{
+			GuardianID: guardianID,
+			Week:       week,
+			Children:   byGuardian[guardianID],
+		})
+		if err != nil {
+			return 0, err
+		}
+		if err := s.repo.MarkDigestSent(guardianID, week); err != nil {
+			return 0, gorm_err.TranslateGormError(err)
+		}
+	}
+	return len(guardianIDs), nil
+}
+
+func (s *tGuardianLinkService) digestChild(link *models.TGuardianLink, now time.Time) (events.GuardianDigestChild, error) {
+	child := events.GuardianDigestChild{StudentID: link.StudentID}
+	if link.Student != nil {
+		child.StudentName = link.Student.Name
+	}
+
+	courses, err := s.repo.FindStudentCourses(link.StudentID)
+	if err != nil {
+		return child, gorm_err.TranslateGormError(err)
+	}
+	if len(courses) > 0 {
+		var total float64
+		for _, course := range courses {
+			total += course.ProgressPercentage
+		}
+		child.AverageProgress = total / float64(len(courses))
+	}
+
+	since := now.AddDate(0, 0, -7)
+	essays, err := s.repo.FindReviewedEssays(link.StudentID, &since, 0)
+	if err != nil {
+		return child, gorm_err.TranslateGormError(err)
+	}
+	child.EssaysReviewed = len(essays)
+
+	items, err := s.dueItems.GetMyDueItems(link.StudentID, dto.DueItemFilterDto{Days: 7})
+	if err != nil {
+		return child, err
+	}
+	for _, item := range items {
+		switch item.Status {
+		case constant.DueStatusOverdue:
+			child.Overdue++
+		case constant.DueStatusUpcoming:
+			child.Upcoming++
+		}
+	}
+	return child, nil
+}
+
+// authorizeStudentSide lets the student of the link, or an admin who can see
+// the student in their school, act on it.
+func (s *tGuardianLinkService) authorizeStudentSide(actorID int64, isSuper bool, link *models.TGuardianLink) error {
+	if actorID == link.StudentID {
+		return nil
+	}
+	if isSuper {
+		if _, err := s.repo.FindVisibleUserByID(link.StudentID); err == nil {
+			return nil
+		}
+	}
+	return fmt.Errorf("kamu tidak berhak mengelola undangan wali ini")
+}
+
+func (s *tGuardianLinkService) acceptedLink(guardianID int64, studentID int64) (*models.TGuardianLink, error) {
+	links, err := s.repo.
+		WithWhere("guardian_id = ? AND student_id = ? AND status = ?", guardianID, studentID, constant.GuardianLinkAccepted).
+		WithPreloads("Student.HasClass").
+		FindGuardianLinks()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if len(links) == 0 {
+		return nil, fmt.Errorf("siswa %d bukan anak perwalian kamu", studentID)
+	}
+	return &links[0], nil
+}
+
+func guardianChild(link *models.TGuardianLink) dto.GuardianChildDto {
+	child := dto.GuardianChildDto{
+		LinkID:       link.ID,
+		StudentID:    link.StudentID,
+		Relationship: link.Relationship,
+	}
+	if student := link.Student; student != nil {
+		child.Name = student.Name
+		child.Code = student.Code
+		child.ClassID = student.ClassID
+		if student.HasClass != nil {
+			child.ClassName = &student.HasClass.ClassName
+		}
+	}
+	return child
+}
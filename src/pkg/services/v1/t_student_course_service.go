@@ -1,10 +1,13 @@
 package services
 
 import (
+	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
 	"jk-api/pkg/repository/adapter/sql"
 
 	"gorm.io/gorm"
@@ -43,10 +46,28 @@ func (s *tStudentCourseService) GetDB() *gorm.DB {
 }
 
 func (s *tStudentCourseService) EnrollTStudentCourse(input *models.TStudentCourse) (*models.TStudentCourse, error) {
+	course, err := s.repo.FindCourseByID(input.CourseID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if course.Status != constant.ContentPublished {
+		return nil, fmt.Errorf("course %s belum diterbitkan", course.CourseName)
+	}
+
 	data, err := s.repo.EnrollCourse(input)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+
+	err = events.Publish(s.GetDB(), events.CourseEnrolled{
+		UserID:          data.UserID,
+		CourseID:        data.CourseID,
+		StudentCourseID: data.ID,
+		EnrolledAt:      data.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
 	return data, nil
 }
 
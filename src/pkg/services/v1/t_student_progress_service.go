@@ -1,19 +1,39 @@
 package services
 
 import (
+	"errors"
 	"fmt"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
 	"jk-api/pkg/repository/adapter/sql"
+	"time"
 
 	"gorm.io/gorm"
 )
 
+var (
+	ErrInvalidProgressStatus     = errors.New("status progress tidak valid")
+	ErrInvalidProgressTransition = errors.New("transisi status progress tidak diizinkan")
+	ErrProgressForbidden         = errors.New("kamu bukan teacher dari siswa ini")
+	ErrProgressNotEnrolled       = errors.New("kamu belum terdaftar di course ini")
+)
+
 type TStudentProgressService interface {
 	WithTx(tx *gorm.DB) TStudentProgressService
 
-	CompleteTStudentProgress(input *models.TStudentProgress) (*models.TStudentProgress, error)
+	TransitionTStudentProgress(userID int64, subLessonID int64, status string) (*models.TStudentProgress, error)
+	ApproveTStudentProgress(actorID int64, isSuper bool, userID int64, subLessonID int64) (*models.TStudentProgress, error)
+	ResetTStudentProgress(actorID int64, isSuper bool, userID int64, courseID int64, subLessonIDs []int64) error
+	GetProgressTree(userID int64, courseID int64, preview bool) (*models.MCourse, []models.TStudentProgress, error)
+	GetStudentProgressTree(actorID int64, isSuper bool, userID int64, courseID int64) (*models.MCourse, []models.TStudentProgress, error)
+	RecomputeCourseProgress(courseID int64) (int, error)
+	GetEnrolledCourseIDs() ([]int64, error)
+	OnCourseStructureChanged(tx *gorm.DB, event events.Event) error
+	OnEnrollmentVersionChanged(tx *gorm.DB, event events.Event) error
+	OnQuizSubmitted(tx *gorm.DB, event events.Event) error
 	GetDB() *gorm.DB
 }
 
@@ -40,71 +60,447 @@ func (s *tStudentProgressService) GetDB() *gorm.DB {
 	return config.DB
 }
 
-func (s *tStudentProgressService) CompleteTStudentProgress(
-	input *models.TStudentProgress,
+// TransitionTStudentProgress moves a student's sub-lesson progress to status.
+// Repeating the current status is a no-op apart from refreshing last_viewed_at.
+func (s *tStudentProgressService) TransitionTStudentProgress(
+	userID int64,
+	subLessonID int64,
+	status string,
 ) (*models.TStudentProgress, error) {
 
-	// sudah pernah complete?
-	existing, _ := s.repo.FindByUserAndSubLesson(
-		input.UserID,
-		input.SubLessonID,
-	)
+	if !constant.IsValidProgressStatus(status) || status == constant.ProgressNotStarted {
+		return nil, fmt.Errorf("%w: %q", ErrInvalidProgressStatus, status)
+	}
+
+	existing, err := s.repo.FirstOrCreateTStudentProgress(&models.TStudentProgress{
+		UserID:      userID,
+		SubLessonID: subLessonID,
+		Status:      constant.ProgressNotStarted,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if existing.Status != status && !constant.CanTransitionProgress(existing.Status, status) {
+		return nil, fmt.Errorf(
+			"%w: dari %s ke %s",
+			ErrInvalidProgressTransition,
+			existing.Status,
+			status,
+		)
+	}
+
+	now := time.Now()
+	return s.moveProgress(existing, status, now, map[string]interface{}{
+		"last_viewed_at": now,
+	})
+}
+
+// ApproveTStudentProgress completes a sub-lesson a student submitted for
+// review. Only the student's teachers and super admins of their school may
+// approve it.
+func (s *tStudentProgressService) ApproveTStudentProgress(
+	actorID int64,
+	isSuper bool,
+	userID int64,
+	subLessonID int64,
+) (*models.TStudentProgress, error) {
 
-	if existing != nil {
-		return existing, nil
+	if err := s.authorizeStudentTeacher(actorID, isSuper, userID); err != nil {
+		return nil, err
 	}
 
-	progress, err := s.repo.CompleteTStudentProgress(input)
+	existing, err := s.repo.FindByUserAndSubLesson(userID, subLessonID)
 	if err != nil {
 		return nil, gorm_err.TranslateGormError(err)
 	}
+	if existing.Status != constant.ProgressNeedsReview {
+		return nil, fmt.Errorf(
+			"%w: dari %s ke %s",
+			ErrInvalidProgressTransition,
+			existing.Status,
+			constant.ProgressCompleted,
+		)
+	}
 
-	courseID, err := s.repo.FindCourseIDBySubLesson(
-		input.SubLessonID,
-	)
+	return s.moveProgress(existing, constant.ProgressCompleted, time.Now(), map[string]interface{}{})
+}
 
+// moveProgress saves updates along with the move of existing to status,
+// recomputing the course progress when completion changes.
+func (s *tStudentProgressService) moveProgress(
+	existing *models.TStudentProgress,
+	status string,
+	now time.Time,
+	updates map[string]interface{},
+) (*models.TStudentProgress, error) {
+
+	userID, subLessonID := existing.UserID, existing.SubLessonID
+
+	if existing.Status != status {
+		updates["status"] = status
+		if existing.StartedAt == nil {
+			updates["started_at"] = now
+		}
+		if status == constant.ProgressCompleted {
+			updates["completed_at"] = now
+		} else if existing.Status == constant.ProgressCompleted {
+			updates["completed_at"] = nil
+			updates["is_late"] = false
+		}
+	}
+
+	progress, err := s.repo.UpdateTStudentProgress(existing.ID, updates)
 	if err != nil {
-		return nil, err
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if status == existing.Status {
+		return progress, nil
+	}
+
+	if status == constant.ProgressCompleted || existing.Status == constant.ProgressCompleted {
+		courseID, err := s.repo.FindCourseIDBySubLesson(subLessonID)
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+
+		if err := s.recomputeCourseProgress(userID, courseID); err != nil {
+			return nil, err
+		}
+
+		if status == constant.ProgressCompleted {
+			err := events.Publish(s.GetDB(), events.SubLessonCompleted{
+				UserID:      userID,
+				SubLessonID: subLessonID,
+				CourseID:    courseID,
+				CompletedAt: now,
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return progress, nil
+}
+
+// ResetTStudentProgress puts a student's progress in a course back to
+// not_started. When subLessonIDs is empty the whole course is reset. Only
+// the student's teachers and super admins of their school may reset it.
+func (s *tStudentProgressService) ResetTStudentProgress(
+	actorID int64,
+	isSuper bool,
+	userID int64,
+	courseID int64,
+	subLessonIDs []int64,
+) error {
+
+	if err := s.authorizeStudentTeacher(actorID, isSuper, userID); err != nil {
+		return err
 	}
 
-	totalSubLesson, err := s.repo.CountTotalSubLessonByCourse(
-		courseID,
-	)
+	if err := s.repo.ResetByUserAndCourse(userID, courseID, subLessonIDs); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	return s.recomputeCourseProgress(userID, courseID)
+}
+
+// GetProgressTree returns the published tree of a course the user is
+// enrolled in with their progress. Staff may preview any course of their
+// school, drafts included, without being enrolled.
+func (s *tStudentProgressService) GetProgressTree(
+	userID int64,
+	courseID int64,
+	preview bool,
+) (*models.MCourse, []models.TStudentProgress, error) {
 
+	course, err := s.repo.FindCourseTree(courseID, !preview)
 	if err != nil {
-		return nil, err
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+
+	if !preview {
+		enrolled, err := s.repo.IsEnrolled(userID, courseID)
+		if err != nil {
+			return nil, nil, gorm_err.TranslateGormError(err)
+		}
+		if !enrolled {
+			return nil, nil, ErrProgressNotEnrolled
+		}
 	}
 
-	completedSubLesson, err := s.repo.CountCompletedByCourse(
-		input.UserID,
-		courseID,
-	)
+	return s.progressTree(userID, course)
+}
+
+// GetStudentProgressTree returns the tree a student sees, with their
+// progress, to a teacher of the student or any super admin of their school.
+func (s *tStudentProgressService) GetStudentProgressTree(
+	actorID int64,
+	isSuper bool,
+	userID int64,
+	courseID int64,
+) (*models.MCourse, []models.TStudentProgress, error) {
+
+	if err := s.authorizeStudentTeacher(actorID, isSuper, userID); err != nil {
+		return nil, nil, err
+	}
 
+	course, err := s.repo.FindCourseTree(courseID, true)
 	if err != nil {
-		return nil, err
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+	return s.progressTree(userID, course)
+}
+
+func (s *tStudentProgressService) progressTree(
+	userID int64,
+	course *models.MCourse,
+) (*models.MCourse, []models.TStudentProgress, error) {
+
+	progresses, err := s.repo.FindByUserAndCourse(userID, course.ID)
+	if err != nil {
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+
+	return course, progresses, nil
+}
+
+// RecomputeCourseProgress recalculates progress_percentage for every student
+// enrolled in the course. The enrollments are locked for the duration of the
+// caller's transaction. It returns the number of enrollments updated.
+// Enrollments pinned to a course version are counted against that version.
+func (s *tStudentProgressService) RecomputeCourseProgress(courseID int64) (int, error) {
+	enrollments, err := s.repo.LockStudentCoursesByCourse(courseID)
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+
+	if len(enrollments) == 0 {
+		return 0, nil
+	}
+
+	type courseCounts struct {
+		total           int64
+		completedByUser map[int64]int64
+	}
+	counts := map[int64]*courseCounts{}
+
+	for _, enrollment := range enrollments {
+		var versionID int64
+		if enrollment.CourseVersionID != nil {
+			versionID = *enrollment.CourseVersionID
+		}
+
+		count := counts[versionID]
+		if count == nil {
+			count = &courseCounts{}
+			if versionID == 0 {
+				count.total, err = s.repo.CountTotalSubLessonByCourse(courseID)
+				if err == nil {
+					count.completedByUser, err = s.repo.CountCompletedByCourseGroupedByUser(courseID)
+				}
+			} else {
+				var subLessonIDs []int64
+				subLessonIDs, err = s.repo.FindCourseVersionSubLessonIDs(versionID)
+				if err == nil {
+					count.total = int64(len(subLessonIDs))
+					count.completedByUser, err = s.repo.CountCompletedBySubLessonsGroupedByUser(subLessonIDs)
+				}
+			}
+			if err != nil {
+				return 0, gorm_err.TranslateGormError(err)
+			}
+			counts[versionID] = count
+		}
+
+		percentage := progressPercentage(count.completedByUser[enrollment.UserID], count.total)
+		if err := s.repo.UpdateStudentCourseProgress(enrollment.UserID, courseID, percentage); err != nil {
+			return 0, gorm_err.TranslateGormError(err)
+		}
+		if err := s.onCourseProgressUpdated(&enrollment, percentage); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(enrollments), nil
+}
+
+func (s *tStudentProgressService) GetEnrolledCourseIDs() ([]int64, error) {
+	courseIDs, err := s.repo.FindEnrolledCourseIDs()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
 	}
+	return courseIDs, nil
+}
 
-	var percentage float64
+// OnCourseStructureChanged keeps progress_percentage in step with the course
+// outline when lessons or sub-lessons are added, moved or removed.
+func (s *tStudentProgressService) OnCourseStructureChanged(tx *gorm.DB, event events.Event) error {
+	changed, ok := event.(events.CourseStructureChanged)
+	if !ok {
+		return nil
+	}
 
-	fmt.Println("CourseID:", courseID)
-	fmt.Println("Completed:", completedSubLesson)
-	fmt.Println("Total:", totalSubLesson)
+	service := s.WithTx(tx)
+	for _, courseID := range changed.CourseIDs {
+		if _, err := service.RecomputeCourseProgress(courseID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-	if totalSubLesson > 0 {
-		percentage = (float64(completedSubLesson) / float64(totalSubLesson)) * 100
-	
+// OnEnrollmentVersionChanged recounts the enrollment's progress against the
+// version it was moved to.
+func (s *tStudentProgressService) OnEnrollmentVersionChanged(tx *gorm.DB, event events.Event) error {
+	changed, ok := event.(events.EnrollmentVersionChanged)
+	if !ok {
+		return nil
 	}
+	service := &tStudentProgressService{repo: s.repo.WithTx(tx), tx: tx}
+	return service.recomputeCourseProgress(changed.UserID, changed.CourseID)
+}
 
-	fmt.Println("Percentage:", percentage)
-	err = s.repo.UpdateStudentCourseProgress(
-		input.UserID,
-		courseID,
-		percentage,
-	)
+// OnQuizSubmitted adds the change in the student's kept quiz score to their
+// course score. Students who already finished the course get the badge of
+// their new score.
+func (s *tStudentProgressService) OnQuizSubmitted(tx *gorm.DB, event events.Event) error {
+	submitted, ok := event.(events.QuizSubmitted)
+	if !ok || submitted.ScoreDelta == 0 {
+		return nil
+	}
 
+	service := &tStudentProgressService{repo: s.repo.WithTx(tx), tx: tx}
+	enrollment, err := service.repo.LockStudentCourse(submitted.UserID, submitted.CourseID)
 	if err != nil {
-		return nil, err
+		return gorm_err.TranslateGormError(err)
+	}
+	if enrollment == nil {
+		return nil
 	}
 
-	return progress, nil
+	if err := service.repo.AddStudentCourseScore(enrollment.ID, submitted.ScoreDelta); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if enrollment.ProgressPercentage < 100 {
+		return nil
+	}
+	return service.awardBadge(enrollment, enrollment.TotalScore+submitted.ScoreDelta, submitted.SubmittedAt)
+}
+
+func (s *tStudentProgressService) recomputeCourseProgress(userID int64, courseID int64) error {
+	enrollment, err := s.repo.LockStudentCourse(userID, courseID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	totalSubLesson, completedSubLesson, err := s.countCourseProgress(enrollment, userID, courseID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	percentage := progressPercentage(completedSubLesson, totalSubLesson)
+	if err := s.repo.UpdateStudentCourseProgress(userID, courseID, percentage); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	if enrollment == nil {
+		return nil
+	}
+	return s.onCourseProgressUpdated(enrollment, percentage)
+}
+
+// countCourseProgress returns the total and completed sub-lessons of the
+// version the enrollment is pinned to, or of the live course for enrollments
+// made before versioning.
+func (s *tStudentProgressService) countCourseProgress(enrollment *models.TStudentCourse, userID int64, courseID int64) (int64, int64, error) {
+	if enrollment == nil || enrollment.CourseVersionID == nil {
+		total, err := s.repo.CountTotalSubLessonByCourse(courseID)
+		if err != nil {
+			return 0, 0, err
+		}
+		completed, err := s.repo.CountCompletedByCourse(userID, courseID)
+		return total, completed, err
+	}
+
+	subLessonIDs, err := s.repo.FindCourseVersionSubLessonIDs(*enrollment.CourseVersionID)
+	if err != nil {
+		return 0, 0, err
+	}
+	completed, err := s.repo.CountCompletedBySubLessons(userID, subLessonIDs)
+	return int64(len(subLessonIDs)), completed, err
+}
+
+// onCourseProgressUpdated raises CourseCompleted when an enrollment reaches
+// 100% and awards the badge matching the enrollment's score, if it changed.
+func (s *tStudentProgressService) onCourseProgressUpdated(enrollment *models.TStudentCourse, percentage float64) error {
+	if enrollment.ProgressPercentage >= 100 || percentage < 100 {
+		return nil
+	}
+
+	now := time.Now()
+	err := events.Publish(s.GetDB(), events.CourseCompleted{
+		UserID:          enrollment.UserID,
+		CourseID:        enrollment.CourseID,
+		StudentCourseID: enrollment.ID,
+		CompletedAt:     now,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.awardBadge(enrollment, enrollment.TotalScore, now)
+}
+
+// awardBadge gives the enrollment the badge matching score, if it changed.
+func (s *tStudentProgressService) awardBadge(enrollment *models.TStudentCourse, score int, now time.Time) error {
+	badge, err := s.repo.FindBadgeByScore(score)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if badge == nil || badge.ID == enrollment.BadgeID {
+		return nil
+	}
+
+	if err := s.repo.UpdateStudentCourseBadge(enrollment.ID, badge.ID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	return events.Publish(s.GetDB(), events.BadgeEarned{
+		UserID:          enrollment.UserID,
+		CourseID:        enrollment.CourseID,
+		StudentCourseID: enrollment.ID,
+		BadgeID:         badge.ID,
+		BadgeName:       badge.Name,
+		EarnedAt:        now,
+	})
+}
+
+// authorizeStudentTeacher allows super admins, limited to students of their
+// own school by the scoped lookup, and teachers of the student's class.
+func (s *tStudentProgressService) authorizeStudentTeacher(actorID int64, isSuper bool, studentID int64) error {
+	if isSuper {
+		if _, err := s.repo.FindStudentByID(studentID); err != nil {
+			return gorm_err.TranslateGormError(err)
+		}
+		return nil
+	}
+
+	ok, err := s.repo.IsStudentTeacher(studentID, actorID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if !ok {
+		return ErrProgressForbidden
+	}
+	return nil
+}
+
+func progressPercentage(completed int64, total int64) float64 {
+	if total <= 0 {
+		return 0
+	}
+	return (float64(completed) / float64(total)) * 100
 }
@@ -0,0 +1,162 @@
+package services
+
+import (
+	"errors"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/pkg/repository/adapter/sql"
+	"testing"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+)
+
+func TestGetProgressTreeRequiresEnrollment(t *testing.T) {
+	tests := []struct {
+		name          string
+		enrolled      bool
+		preview       bool
+		wantErr       error
+		wantPublished bool
+	}{
+		{"enrolled student", true, false, nil, true},
+		{"student not enrolled", false, false, ErrProgressNotEnrolled, true},
+		{"staff preview", false, true, nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProgressRepository{enrolled: tt.enrolled}
+			service := NewTStudentProgressService(repo)
+
+			_, _, err := service.GetProgressTree(1, 9, tt.preview)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if repo.publishedOnly != tt.wantPublished {
+				t.Errorf("publishedOnly = %v, want %v", repo.publishedOnly, tt.wantPublished)
+			}
+		})
+	}
+}
+
+// A student may submit a sub-lesson for review but not take it out of review.
+func TestStudentCannotLeaveNeedsReview(t *testing.T) {
+	for _, status := range []string{constant.ProgressInProgress, constant.ProgressCompleted} {
+		t.Run(status, func(t *testing.T) {
+			repo := &fakeProgressRepository{progress: &models.TStudentProgress{ID: 3, UserID: 1, SubLessonID: 5, Status: constant.ProgressNeedsReview}}
+			service := NewTStudentProgressService(repo)
+
+			_, err := service.TransitionTStudentProgress(1, 5, status)
+			if !errors.Is(err, ErrInvalidProgressTransition) {
+				t.Fatalf("err = %v, want ErrInvalidProgressTransition", err)
+			}
+			if repo.updates != nil {
+				t.Errorf("progress updated: %v", repo.updates)
+			}
+		})
+	}
+}
+
+func TestApproveTStudentProgress(t *testing.T) {
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name       string
+		teacher    bool
+		status     string
+		wantErr    error
+		wantStatus any
+	}{
+		{"teacher approves", true, constant.ProgressNeedsReview, nil, constant.ProgressCompleted},
+		{"not the student's teacher", false, constant.ProgressNeedsReview, ErrProgressForbidden, nil},
+		{"not submitted for review", true, constant.ProgressInProgress, ErrInvalidProgressTransition, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := &fakeProgressRepository{
+				teacher:  tt.teacher,
+				progress: &models.TStudentProgress{ID: 3, UserID: 1, SubLessonID: 5, Status: tt.status},
+			}
+			service := NewTStudentProgressService(repo).WithTx(db)
+
+			_, err := service.ApproveTStudentProgress(2, false, 1, 5)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if repo.updates["status"] != tt.wantStatus {
+				t.Errorf("status = %v, want %v", repo.updates["status"], tt.wantStatus)
+			}
+			if tt.wantErr == nil && repo.updates["completed_at"] == nil {
+				t.Errorf("completed_at not set: %v", repo.updates)
+			}
+		})
+	}
+}
+
+type fakeProgressRepository struct {
+	sql.TStudentProgressRepository
+	enrolled      bool
+	publishedOnly bool
+	teacher       bool
+	progress      *models.TStudentProgress
+	updates       map[string]interface{}
+}
+
+func (r *fakeProgressRepository) WithTx(tx *gorm.DB) sql.TStudentProgressRepository {
+	return r
+}
+
+func (r *fakeProgressRepository) FindCourseTree(courseID int64, publishedOnly bool) (*models.MCourse, error) {
+	r.publishedOnly = publishedOnly
+	return &models.MCourse{ID: courseID}, nil
+}
+
+func (r *fakeProgressRepository) IsEnrolled(userID int64, courseID int64) (bool, error) {
+	return r.enrolled, nil
+}
+
+func (r *fakeProgressRepository) FindByUserAndCourse(userID int64, courseID int64) ([]models.TStudentProgress, error) {
+	return nil, nil
+}
+
+func (r *fakeProgressRepository) IsStudentTeacher(studentID int64, teacherID int64) (bool, error) {
+	return r.teacher, nil
+}
+
+func (r *fakeProgressRepository) FirstOrCreateTStudentProgress(data *models.TStudentProgress) (*models.TStudentProgress, error) {
+	return r.progress, nil
+}
+
+func (r *fakeProgressRepository) FindByUserAndSubLesson(userID int64, subLessonID int64) (*models.TStudentProgress, error) {
+	return r.progress, nil
+}
+
+func (r *fakeProgressRepository) UpdateTStudentProgress(id int64, updates map[string]interface{}) (*models.TStudentProgress, error) {
+	r.updates = updates
+	return r.progress, nil
+}
+
+func (r *fakeProgressRepository) FindCourseIDBySubLesson(subLessonID int64) (int64, error) {
+	return 9, nil
+}
+
+func (r *fakeProgressRepository) LockStudentCourse(userID int64, courseID int64) (*models.TStudentCourse, error) {
+	return nil, nil
+}
+
+func (r *fakeProgressRepository) CountTotalSubLessonByCourse(courseID int64) (int64, error) {
+	return 1, nil
+}
+
+func (r *fakeProgressRepository) CountCompletedByCourse(userID int64, courseID int64) (int64, error) {
+	return 1, nil
+}
+
+func (r *fakeProgressRepository) UpdateStudentCourseProgress(userID int64, courseID int64, percentage float64) error {
+	return nil
+}
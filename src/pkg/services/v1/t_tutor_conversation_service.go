@@ -0,0 +1,379 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/tutor"
+	"jk-api/pkg/repository/adapter/sql"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"gorm.io/gorm"
+)
+
+const (
+	maxTutorMessage = 2000
+	maxTutorTitle   = 150
+	// tutorHistory is how many messages, the new one included, are sent to
+	// the provider.
+	tutorHistory = 12
+)
+
+var (
+	ErrTutorInvalid     = errors.New("pesan tutor tidak valid")
+	ErrTutorRateLimited = errors.New("batas pesan ke tutor tercapai, coba lagi nanti")
+	ErrTutorTokenLimit  = errors.New("kuota tutor hari ini sudah habis")
+	ErrTutorFailed      = errors.New("tutor gagal menjawab")
+)
+
+// TutorTurn is a student message waiting for its reply.
+type TutorTurn struct {
+	Conversation *models.TTutorConversation
+	Message      *models.TTutorMessage
+	Request      tutor.ChatRequest
+}
+
+type TTutorConversationService interface {
+	WithTx(tx *gorm.DB) TTutorConversationService
+
+	CreateConversation(actorID int64, input *dto.CreateTutorConversationDto, preview bool) (*models.TTutorConversation, error)
+	GetConversations(filter dto.TutorConversationFilterDto) ([]models.TTutorConversation, queryspec.Page, error)
+	GetConversationByID(id int64, filter dto.TutorConversationFilterDto) (*models.TTutorConversation, error)
+	StartReply(actorID int64, id int64, input *dto.SendTutorMessageDto, preview bool) (*TutorTurn, error)
+	FinishReply(ctx context.Context, turn *TutorTurn, onDelta func(text string) error) (*models.TTutorMessage, error)
+	GetDB() *gorm.DB
+}
+
+type tTutorConversationService struct {
+	repo     sql.TTutorConversationRepository
+	provider tutor.ChatProvider
+	tx       *gorm.DB
+}
+
+func NewTTutorConversationService(repo sql.TTutorConversationRepository, provider tutor.ChatProvider) TTutorConversationService {
+	return &tTutorConversationService{repo: repo, provider: provider}
+}
+
+func (s *tTutorConversationService) WithTx(tx *gorm.DB) TTutorConversationService {
+	return &tTutorConversationService{
+		repo:     s.repo.WithTx(tx),
+		provider: s.provider,
+		tx:       tx,
+	}
+}
+
+func (s *tTutorConversationService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// CreateConversation starts a conversation of the caller about a sub-lesson
+// they can see. A material or code question must belong to the sub-lesson.
+func (s *tTutorConversationService) CreateConversation(actorID int64, input *dto.CreateTutorConversationDto, preview bool) (*models.TTutorConversation, error) {
+	subLesson, _, err := s.findSubLesson(input.SubLessonID, preview)
+	if err != nil {
+		return nil, err
+	}
+
+	title := strings.TrimSpace(input.Title)
+	if utf8.RuneCountInString(title) > maxTutorTitle {
+		return nil, fmt.Errorf("%w: title maksimal %d karakter", ErrTutorInvalid, maxTutorTitle)
+	}
+	if title == "" {
+		title = subLesson.Title
+	}
+
+	if input.MaterialID != nil && findMaterial(subLesson, *input.MaterialID, preview) == nil {
+		return nil, fmt.Errorf("%w: materi %d tidak ada di sub-bab ini", ErrTutorInvalid, *input.MaterialID)
+	}
+	if input.CodeQuestionID != nil && findCodeQuestion(subLesson, *input.CodeQuestionID, preview) == nil {
+		return nil, fmt.Errorf("%w: soal %d tidak ada di sub-bab ini", ErrTutorInvalid, *input.CodeQuestionID)
+	}
+
+	data, err := s.repo.InsertConversation(&models.TTutorConversation{
+		UserID:         actorID,
+		SubLessonID:    subLesson.ID,
+		MaterialID:     input.MaterialID,
+		CodeQuestionID: input.CodeQuestionID,
+		Title:          title,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *tTutorConversationService) GetConversations(filter dto.TutorConversationFilterDto) ([]models.TTutorConversation, queryspec.Page, error) {
+	repo := s.repo
+	if filter.UserID != 0 {
+		repo = repo.WithWhere("t_tutor_conversation.user_id = ?", filter.UserID)
+	}
+	if filter.SubLessonID != 0 {
+		repo = repo.WithWhere("t_tutor_conversation.sub_lesson_id = ?", filter.SubLessonID)
+	}
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountConversations()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindConversationPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+// GetConversationByID returns the transcript of a conversation. Students
+// only find their own; teachers find those of their school.
+func (s *tTutorConversationService) GetConversationByID(id int64, filter dto.TutorConversationFilterDto) (*models.TTutorConversation, error) {
+	data, err := s.findConversation(id, filter.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	data.Messages, err = s.repo.FindMessages(data.ID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// StartReply stores the student's message and builds the request for its
+// reply, grounded in the sub-lesson as it is now. Only the student who
+// started the conversation can write in it, within their hourly message and
+// daily token limits.
+func (s *tTutorConversationService) StartReply(actorID int64, id int64, input *dto.SendTutorMessageDto, preview bool) (*TutorTurn, error) {
+	content := strings.TrimSpace(input.Content)
+	if content == "" || utf8.RuneCountInString(content) > maxTutorMessage {
+		return nil, fmt.Errorf("%w: content wajib diisi, maksimal %d karakter", ErrTutorInvalid, maxTutorMessage)
+	}
+
+	conversation, err := s.findConversation(id, actorID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLimits(actorID); err != nil {
+		return nil, err
+	}
+
+	subLesson, course, err := s.findSubLesson(conversation.SubLessonID, preview)
+	if err != nil {
+		return nil, err
+	}
+	grounding, err := s.grounding(actorID, conversation, subLesson, course, preview)
+	if err != nil {
+		return nil, err
+	}
+
+	message, err := s.repo.InsertMessage(&models.TTutorMessage{
+		ConversationID: conversation.ID,
+		UserID:         actorID,
+		Role:           constant.TutorRoleStudent,
+		Content:        content,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	history, err := s.repo.FindRecentMessages(conversation.ID, tutorHistory)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	req := tutor.ChatRequest{
+		System:    tutor.SystemPrompt(grounding),
+		MaxTokens: config.AppConfig.TutorMaxReplyTokens,
+	}
+	for _, m := range history {
+		if m.Error != "" || strings.TrimSpace(m.Content) == "" {
+			continue
+		}
+		req.Messages = append(req.Messages, tutor.Message{Role: m.Role, Content: m.Content})
+	}
+
+	return &TutorTurn{Conversation: conversation, Message: message, Request: req}, nil
+}
+
+// FinishReply streams the reply to a turn through onDelta and stores it.
+// A reply the provider couldn't finish is stored with what it wrote so far
+// and its tokens still count towards the student's limit.
+func (s *tTutorConversationService) FinishReply(ctx context.Context, turn *TutorTurn, onDelta func(text string) error) (*models.TTutorMessage, error) {
+	var partial strings.Builder
+	resp, streamErr := s.provider.Stream(ctx, turn.Request, func(text string) error {
+		partial.WriteString(text)
+		return onDelta(text)
+	})
+
+	message := &models.TTutorMessage{
+		ConversationID: turn.Conversation.ID,
+		UserID:         turn.Conversation.UserID,
+		Role:           constant.TutorRoleTutor,
+		Provider:       s.provider.Name(),
+	}
+	if streamErr != nil {
+		message.Content = partial.String()
+		message.PromptTokens = turn.Request.PromptTokens()
+		message.CompletionTokens = tutor.EstimateTokens(message.Content)
+		message.Error = truncateRunes(streamErr.Error(), 1000)
+	} else {
+		message.Content = resp.Text
+		message.Model = resp.Model
+		message.PromptTokens = resp.PromptTokens
+		message.CompletionTokens = resp.CompletionTokens
+	}
+
+	data, err := s.repo.InsertMessage(message)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	if err := s.repo.AddConversationTokens(turn.Conversation.ID, data.PromptTokens+data.CompletionTokens); err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if streamErr != nil {
+		return data, fmt.Errorf("%w: %v", ErrTutorFailed, streamErr)
+	}
+	return data, nil
+}
+
+// findConversation returns a conversation of the caller's school, limited
+// to those of userID unless it is zero.
+func (s *tTutorConversationService) findConversation(id int64, userID int64) (*models.TTutorConversation, error) {
+	repo := s.repo
+	if userID != 0 {
+		repo = repo.WithWhere("t_tutor_conversation.user_id = ?", userID)
+	}
+	data, err := repo.FindConversationByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// findSubLesson returns a sub-lesson of a course the caller can see. Unless
+// previewing, its lesson and course must be published.
+func (s *tTutorConversationService) findSubLesson(id int64, preview bool) (*models.MSubLesson, *models.MCourse, error) {
+	subLesson, err := s.repo.FindSubLessonByID(id)
+	if err != nil {
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+	if subLesson.Lesson == nil {
+		return nil, nil, gorm_err.TranslateGormError(gorm.ErrRecordNotFound)
+	}
+	course, err := s.repo.FindCourseByID(subLesson.Lesson.CourseID)
+	if err != nil {
+		return nil, nil, gorm_err.TranslateGormError(err)
+	}
+
+	if !preview && (subLesson.Lesson.Status != constant.ContentPublished || course.Status != constant.ContentPublished) {
+		return nil, nil, gorm_err.TranslateGormError(gorm.ErrRecordNotFound)
+	}
+	return subLesson, course, nil
+}
+
+func (s *tTutorConversationService) checkLimits(userID int64) error {
+	now := time.Now()
+
+	messages, err := s.repo.CountStudentMessagesSince(userID, now.Add(-time.Hour))
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if messages >= int64(config.AppConfig.TutorMessagesPerHour) {
+		return ErrTutorRateLimited
+	}
+
+	tokens, err := s.repo.SumTokensSince(userID, now.Add(-24*time.Hour))
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if tokens >= int64(config.AppConfig.TutorTokensPerDay) {
+		return ErrTutorTokenLimit
+	}
+	return nil
+}
+
+// grounding collects what the reply is based on: the material of the
+// conversation or else every material of the sub-lesson, its code question
+// and the student's latest error on it, or on any question of the
+// sub-lesson when the conversation isn't about one.
+func (s *tTutorConversationService) grounding(userID int64, conversation *models.TTutorConversation, subLesson *models.MSubLesson, course *models.MCourse, preview bool) (tutor.Grounding, error) {
+	g := tutor.Grounding{
+		Course:    course.CourseName,
+		Lesson:    subLesson.Lesson.Title,
+		SubLesson: subLesson.Title,
+	}
+
+	var materials []string
+	for _, m := range subLesson.Materials {
+		if !visibleStatus(m.Status, preview) || strings.TrimSpace(m.MaterialsText) == "" {
+			continue
+		}
+		if conversation.MaterialID != nil && m.ID != *conversation.MaterialID {
+			continue
+		}
+		materials = append(materials, m.Title+"\n"+m.MaterialsText)
+	}
+	g.Material = strings.Join(materials, "\n\n")
+
+	var questionIDs []int64
+	if conversation.CodeQuestionID != nil {
+		if question := findCodeQuestion(subLesson, *conversation.CodeQuestionID, preview); question != nil {
+			g.Question = question.CodeQuestion
+			g.Hint = question.Hint
+			questionIDs = append(questionIDs, question.ID)
+		}
+	} else {
+		for _, q := range subLesson.CodeQuestions {
+			questionIDs = append(questionIDs, q.ID)
+		}
+	}
+
+	failed, err := s.repo.FindLatestErrorLog(userID, questionIDs)
+	if err != nil {
+		return g, gorm_err.TranslateGormError(err)
+	}
+	if failed != nil {
+		g.LastError = failed.Message
+	}
+	return g, nil
+}
+
+func findMaterial(subLesson *models.MSubLesson, id int64, preview bool) *models.MMaterials {
+	for i, m := range subLesson.Materials {
+		if m.ID == id && visibleStatus(m.Status, preview) {
+			return &subLesson.Materials[i]
+		}
+	}
+	return nil
+}
+
+func findCodeQuestion(subLesson *models.MSubLesson, id int64, preview bool) *models.CodeQuestion {
+	for i, q := range subLesson.CodeQuestions {
+		if q.ID == id && visibleStatus(q.Status, preview) {
+			return &subLesson.CodeQuestions[i]
+		}
+	}
+	return nil
+}
+
+// visibleStatus tells whether content in status can be shown: students only
+// see published content.
+func visibleStatus(status string, preview bool) bool {
+	if preview {
+		return status != constant.ContentArchived
+	}
+	return status == constant.ContentPublished
+}
@@ -0,0 +1,302 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/webhook"
+	"jk-api/pkg/repository/adapter/sql"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TWebhookDeliveryService interface {
+	WithTx(tx *gorm.DB) TWebhookDeliveryService
+
+	OnWebhookEvent(ctx context.Context, event events.Event) error
+	DeliverPending(ctx context.Context, batchSize int, maxAttempts int, lease time.Duration) (int, error)
+	SendTestTWebhookDelivery(ctx context.Context, subscriptionID int64) (*models.TWebhookDelivery, error)
+	ReplayTWebhookDelivery(id int64) (*models.TWebhookDelivery, error)
+	GetAllTWebhookDeliveries(filter dto.TWebhookDeliveryFilterDto) ([]models.TWebhookDelivery, queryspec.Page, error)
+	GetTWebhookDeliveryByID(id int64) (*models.TWebhookDelivery, error)
+	GetDB() *gorm.DB
+}
+
+type tWebhookDeliveryService struct {
+	repo             sql.TWebhookDeliveryRepository
+	subscriptionRepo sql.MWebhookSubscriptionRepository
+	sender           webhook.Sender
+	tx               *gorm.DB
+}
+
+func NewTWebhookDeliveryService(
+	repo sql.TWebhookDeliveryRepository,
+	subscriptionRepo sql.MWebhookSubscriptionRepository,
+	sender webhook.Sender,
+) TWebhookDeliveryService {
+	return &tWebhookDeliveryService{
+		repo:             repo,
+		subscriptionRepo: subscriptionRepo,
+		sender:           sender,
+	}
+}
+
+func (s *tWebhookDeliveryService) WithTx(tx *gorm.DB) TWebhookDeliveryService {
+	return &tWebhookDeliveryService{
+		repo:             s.repo.WithTx(tx),
+		subscriptionRepo: s.subscriptionRepo.WithTx(tx),
+		sender:           s.sender,
+		tx:               tx,
+	}
+}
+
+func (s *tWebhookDeliveryService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// OnWebhookEvent queues one delivery per active subscription interested in the
+// event. It runs from the outbox relay; deliveries are keyed on the outbox id
+// so a re-dispatched event does not queue duplicates. Subscriptions are
+// managed by platform admins only, so events of every school go to all of
+// them.
+func (s *tWebhookDeliveryService) OnWebhookEvent(ctx context.Context, event events.Event) error {
+	outboxID, ok := events.EventID(ctx)
+	if !ok {
+		return fmt.Errorf("webhook fan-out for %s has no outbox id", event.Name())
+	}
+
+	subscriptions, err := s.subscriptionRepo.FindActiveMWebhookSubscriptions()
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(webhook.Envelope{
+		ID:         fmt.Sprintf("evt_%d", outboxID),
+		Event:      event.Name(),
+		OccurredAt: time.Now(),
+		Data:       data,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	var deliveries []*models.TWebhookDelivery
+	for _, subscription := range subscriptions {
+		if !subscription.Subscribes(event.Name()) {
+			continue
+		}
+		deliveries = append(deliveries, &models.TWebhookDelivery{
+			SubscriptionID: subscription.ID,
+			OutboxID:       &outboxID,
+			EventName:      event.Name(),
+			Payload:        payload,
+			Status:         constant.WebhookDeliveryPending,
+			NextAttemptAt:  now,
+		})
+	}
+
+	if err := s.repo.InsertManyTWebhookDeliveriesIgnoreDuplicates(deliveries); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	return nil
+}
+
+// DeliverPending sends up to batchSize due deliveries. The rows are leased
+// rather than locked, so no transaction is open while the receivers are
+// called. Failed deliveries are retried with exponential backoff and marked
+// dead after maxAttempts. It returns the number of deliveries that succeeded.
+func (s *tWebhookDeliveryService) DeliverPending(ctx context.Context, batchSize int, maxAttempts int, lease time.Duration) (int, error) {
+	now := time.Now()
+
+	rows, err := s.repo.ClaimPendingTWebhookDeliveries(batchSize, now, now.Add(lease))
+	if err != nil {
+		return 0, gorm_err.TranslateGormError(err)
+	}
+
+	delivered := 0
+	for _, row := range rows {
+		updates := s.attempt(ctx, &row, maxAttempts)
+		if updates["status"] == constant.WebhookDeliverySucceeded {
+			delivered++
+		}
+
+		if err := s.repo.RecordTWebhookDeliveryAttempt(row.ID, updates); err != nil {
+			return delivered, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	return delivered, nil
+}
+
+// SendTestTWebhookDelivery sends a webhook.ping to the subscription right away
+// and records the outcome as a delivery. A failed ping is not retried.
+func (s *tWebhookDeliveryService) SendTestTWebhookDelivery(ctx context.Context, subscriptionID int64) (*models.TWebhookDelivery, error) {
+	subscription, err := s.subscriptionRepo.FindMWebhookSubscriptionByID(subscriptionID)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	now := time.Now()
+	payload, err := json.Marshal(webhook.Envelope{
+		ID:         fmt.Sprintf("ping_%d", now.UnixNano()),
+		Event:      constant.WebhookPingEvent,
+		OccurredAt: now,
+		Data:       json.RawMessage(fmt.Sprintf(`{"subscription_id":%d}`, subscription.ID)),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	delivery, err := s.repo.InsertTWebhookDelivery(&models.TWebhookDelivery{
+		SubscriptionID: subscription.ID,
+		EventName:      constant.WebhookPingEvent,
+		Payload:        payload,
+		Status:         constant.WebhookDeliveryPending,
+		Attempts:       1,
+		NextAttemptAt:  now,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	delivery.Subscription = subscription
+	updates := s.attempt(ctx, delivery, 1)
+
+	data, err := s.repo.UpdateTWebhookDelivery(delivery.ID, updates)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// ReplayTWebhookDelivery queues a fresh copy of a delivery with the same
+// payload. The original row is left untouched for the audit trail.
+func (s *tWebhookDeliveryService) ReplayTWebhookDelivery(id int64) (*models.TWebhookDelivery, error) {
+	original, err := s.repo.FindTWebhookDeliveryByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if _, err := s.subscriptionRepo.FindMWebhookSubscriptionByID(original.SubscriptionID); err != nil {
+		return nil, fmt.Errorf("subscription webhook untuk delivery %d tidak ditemukan", id)
+	}
+
+	data, err := s.repo.InsertTWebhookDelivery(&models.TWebhookDelivery{
+		SubscriptionID: original.SubscriptionID,
+		OutboxID:       original.OutboxID,
+		ReplayOfID:     &original.ID,
+		EventName:      original.EventName,
+		Payload:        original.Payload,
+		Status:         constant.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+func (s *tWebhookDeliveryService) GetAllTWebhookDeliveries(filter dto.TWebhookDeliveryFilterDto) ([]models.TWebhookDelivery, queryspec.Page, error) {
+	repo := s.repo
+
+	if filter.SubscriptionID > 0 {
+		repo = repo.WithWhere("subscription_id = ?", filter.SubscriptionID)
+	}
+	if filter.Status != "" {
+		repo = repo.WithWhere("status = ?", filter.Status)
+	}
+	if filter.EventName != "" {
+		repo = repo.WithWhere("event_name = ?", filter.EventName)
+	}
+
+	repo = repo.WithSpec(filter.Spec)
+
+	total, err := repo.CountTWebhookDeliveries()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+
+	data, page, err := repo.FindTWebhookDeliveryPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
+}
+
+func (s *tWebhookDeliveryService) GetTWebhookDeliveryByID(id int64) (*models.TWebhookDelivery, error) {
+	data, err := s.repo.FindTWebhookDeliveryByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	return data, nil
+}
+
+// attempt sends a delivery once and returns the column updates describing
+// the outcome. The attempt has already been counted in row.Attempts.
+func (s *tWebhookDeliveryService) attempt(ctx context.Context, row *models.TWebhookDelivery, maxAttempts int) map[string]interface{} {
+	attempts := row.Attempts
+	updates := map[string]interface{}{
+		"updated_at": time.Now(),
+	}
+
+	subscription := row.Subscription
+	if subscription == nil || !subscription.IsActive {
+		updates["status"] = constant.WebhookDeliveryDead
+		updates["last_error"] = "subscription is inactive or deleted"
+		return updates
+	}
+
+	resp, sendErr := s.sender.Send(ctx, webhook.Request{
+		URL:        subscription.URL,
+		Secret:     subscription.Secret,
+		Event:      row.EventName,
+		DeliveryID: row.ID,
+		Body:       row.Payload,
+	})
+
+	if resp != nil {
+		updates["response_status"] = resp.StatusCode
+	}
+
+	if resp.Succeeded() {
+		updates["status"] = constant.WebhookDeliverySucceeded
+		updates["delivered_at"] = time.Now()
+		updates["last_error"] = nil
+		return updates
+	}
+
+	var message string
+	if sendErr != nil {
+		message = sendErr.Error()
+	} else {
+		message = fmt.Sprintf("receiver responded with status %d", resp.StatusCode)
+	}
+	updates["last_error"] = message
+
+	if attempts >= maxAttempts {
+		updates["status"] = constant.WebhookDeliveryDead
+		config.Logger.Errorf("❌ Webhook delivery %d (%s) dead after %d attempts: %s", row.ID, row.EventName, attempts, message)
+	} else {
+		updates["next_attempt_at"] = time.Now().Add(outboxBackoff(attempts))
+		config.Logger.Warnf("⚠️ Webhook delivery %d (%s) failed, attempt %d: %s", row.ID, row.EventName, attempts, message)
+	}
+	return updates
+}
@@ -0,0 +1,168 @@
+package services
+
+import (
+	"context"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/webhook"
+	"jk-api/pkg/repository/adapter/sql"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestDeliverPendingRetriesUntilSuccess(t *testing.T) {
+	var repo *fakeWebhookDeliveryRepository
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		// The receiver is called while the row is leased, not locked.
+		if time.Until(repo.row.NextAttemptAt) < 59*time.Minute {
+			t.Errorf("call %d: row not leased, next attempt at %v", calls, repo.row.NextAttemptAt)
+		}
+		if calls < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo = newFakeWebhookDeliveryRepository(server.URL)
+	service := NewTWebhookDeliveryService(repo, nil, loopbackSender())
+
+	for run := 1; run <= 3; run++ {
+		before := time.Now()
+		delivered, err := service.DeliverPending(context.Background(), 10, 5, time.Hour)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if run < 3 {
+			if delivered != 0 || repo.row.Status != constant.WebhookDeliveryPending {
+				t.Fatalf("run %d: delivered = %d, status = %s", run, delivered, repo.row.Status)
+			}
+			if repo.row.ResponseStatus == nil || *repo.row.ResponseStatus != http.StatusServiceUnavailable {
+				t.Errorf("run %d: response status = %v", run, repo.row.ResponseStatus)
+			}
+			wait := repo.row.NextAttemptAt.Sub(before)
+			if want := outboxBackoff(run); wait < want || wait > want+time.Second {
+				t.Errorf("run %d: retried after %v, want %v", run, wait, want)
+			}
+			// Make the row due again without waiting out the backoff.
+			repo.row.NextAttemptAt = time.Now()
+			continue
+		}
+
+		if delivered != 1 || repo.row.Status != constant.WebhookDeliverySucceeded {
+			t.Fatalf("run %d: delivered = %d, status = %s", run, delivered, repo.row.Status)
+		}
+	}
+
+	if calls != 3 || repo.row.Attempts != 3 {
+		t.Errorf("calls = %d, attempts = %d, want 3", calls, repo.row.Attempts)
+	}
+	if repo.row.DeliveredAt == nil || repo.row.LastError != nil {
+		t.Errorf("delivered_at = %v, last_error = %v", repo.row.DeliveredAt, repo.row.LastError)
+	}
+}
+
+func TestDeliverPendingDeadLetters(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := newFakeWebhookDeliveryRepository(server.URL)
+	service := NewTWebhookDeliveryService(repo, nil, loopbackSender())
+
+	for repo.row.Status == constant.WebhookDeliveryPending {
+		if calls > 3 {
+			t.Fatalf("still pending after %d calls", calls)
+		}
+		repo.row.NextAttemptAt = time.Now()
+		if _, err := service.DeliverPending(context.Background(), 10, 3, time.Hour); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if calls != 3 || repo.row.Status != constant.WebhookDeliveryDead || repo.row.Attempts != 3 {
+		t.Errorf("calls = %d, row = %s after %d attempts", calls, repo.row.Status, repo.row.Attempts)
+	}
+	if repo.row.LastError == nil || *repo.row.LastError == "" {
+		t.Error("last_error not recorded")
+	}
+}
+
+// fakeWebhookDeliveryRepository holds a single delivery to an active
+// subscription, claimed whenever it is pending and due.
+type fakeWebhookDeliveryRepository struct {
+	sql.TWebhookDeliveryRepository
+	row models.TWebhookDelivery
+}
+
+func newFakeWebhookDeliveryRepository(url string) *fakeWebhookDeliveryRepository {
+	return &fakeWebhookDeliveryRepository{row: models.TWebhookDelivery{
+		ID:             1,
+		SubscriptionID: 1,
+		EventName:      "course.published",
+		Payload:        []byte(`{"id":"evt_1"}`),
+		Status:         constant.WebhookDeliveryPending,
+		NextAttemptAt:  time.Now(),
+		Subscription: &models.MWebhookSubscription{
+			ID:       1,
+			URL:      url,
+			Secret:   "s3cret",
+			IsActive: true,
+		},
+	}}
+}
+
+func (r *fakeWebhookDeliveryRepository) ClaimPendingTWebhookDeliveries(limit int, now time.Time, leaseUntil time.Time) ([]models.TWebhookDelivery, error) {
+	if r.row.Status != constant.WebhookDeliveryPending || r.row.NextAttemptAt.After(now) {
+		return nil, nil
+	}
+	r.row.Attempts++
+	r.row.NextAttemptAt = leaseUntil
+	return []models.TWebhookDelivery{r.row}, nil
+}
+
+func (r *fakeWebhookDeliveryRepository) RecordTWebhookDeliveryAttempt(id int64, updates map[string]interface{}) error {
+	_, err := r.UpdateTWebhookDelivery(id, updates)
+	return err
+}
+
+func (r *fakeWebhookDeliveryRepository) UpdateTWebhookDelivery(id int64, updates map[string]interface{}) (*models.TWebhookDelivery, error) {
+	for column, value := range updates {
+		switch column {
+		case "attempts":
+			r.row.Attempts = value.(int)
+		case "status":
+			r.row.Status = value.(string)
+		case "response_status":
+			status := value.(int)
+			r.row.ResponseStatus = &status
+		case "last_error":
+			if message, ok := value.(string); ok {
+				r.row.LastError = &message
+			} else {
+				r.row.LastError = nil
+			}
+		case "next_attempt_at":
+			r.row.NextAttemptAt = value.(time.Time)
+		case "delivered_at":
+			deliveredAt := value.(time.Time)
+			r.row.DeliveredAt = &deliveredAt
+		}
+	}
+	return &r.row, nil
+}
+
+// loopbackSender is a sender without the guard against internal addresses,
+// so it can reach the httptest server.
+func loopbackSender() webhook.Sender {
+	return &webhook.HTTPSender{Client: &http.Client{Timeout: time.Second}}
+}
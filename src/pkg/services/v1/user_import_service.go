@@ -0,0 +1,543 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/config"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/helper"
+	"jk-api/internal/spreadsheet"
+	"jk-api/pkg/repository/adapter/sql"
+	"net/mail"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	defaultPasswordLength = 10
+	minImportPassword     = 8
+)
+
+// ErrUserImportInvalid is returned when an import is applied while some rows
+// still fail validation. Nothing is written; the report lists the problems.
+var ErrUserImportInvalid = errors.New("terdapat baris yang tidak valid, tidak ada data yang disimpan")
+
+// importRoles maps the role column (and its Indonesian spellings) to role IDs.
+// Super admins can't be created through an import.
+var importRoles = map[string]int64{
+	"student": constant.RoleIDStudent,
+	"siswa":   constant.RoleIDStudent,
+	"murid":   constant.RoleIDStudent,
+	"teacher": constant.RoleIDTeacher,
+	"guru":    constant.RoleIDTeacher,
+}
+
+type UserImportService interface {
+	WithTx(tx *gorm.DB) UserImportService
+
+	ImportUsers(actorID int64, isSuper bool, rows [][]string, options dto.UserImportOptionsDto) (*dto.UserImportReportDto, error)
+	GetDB() *gorm.DB
+}
+
+type userImportService struct {
+	userRepo   sql.UserRepository
+	rosterRepo sql.TClassRosterRepository
+	tx         *gorm.DB
+}
+
+func NewUserImportService(userRepo sql.UserRepository, rosterRepo sql.TClassRosterRepository) UserImportService {
+	return &userImportService{userRepo: userRepo, rosterRepo: rosterRepo}
+}
+
+func (s *userImportService) WithTx(tx *gorm.DB) UserImportService {
+	return &userImportService{
+		userRepo:   s.userRepo.WithTx(tx),
+		rosterRepo: s.rosterRepo.WithTx(tx),
+		tx:         tx,
+	}
+}
+
+func (s *userImportService) GetDB() *gorm.DB {
+	if s.tx != nil {
+		return s.tx
+	}
+	return config.DB
+}
+
+// importRow is a validated spreadsheet row together with what it resolves to
+// in the database.
+type importRow struct {
+	result   dto.UserImportRowResultDto
+	roleID   int64
+	password string
+	existing *models.User
+	class    *models.MClass
+}
+
+// ImportUsers validates every row of rows (the first row is the header) and,
+// unless this is a dry run, creates or updates the users and their class
+// memberships. Rows are applied only when all of them are valid, so callers
+// should run it inside a transaction.
+func (s *userImportService) ImportUsers(actorID int64, isSuper bool, rows [][]string, options dto.UserImportOptionsDto) (*dto.UserImportReportDto, error) {
+	if len(rows) < 2 {
+		return nil, fmt.Errorf("file tidak berisi data")
+	}
+
+	columns, err := resolveImportColumns(rows[0], options.Mapping)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &dto.UserImportReportDto{
+		DryRun:  options.DryRun,
+		Columns: make(map[string]string, len(columns)),
+	}
+	for field, index := range columns {
+		report.Columns[field] = strings.TrimSpace(rows[0][index])
+	}
+
+	parsed, err := s.validateRows(actorID, isSuper, rows, columns, options)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range parsed {
+		report.Rows = append(report.Rows, row.result)
+		if len(row.result.Errors) > 0 {
+			report.Invalid++
+		} else {
+			report.Valid++
+		}
+	}
+	report.Total = len(parsed)
+
+	if options.DryRun {
+		return report, nil
+	}
+	if report.Invalid > 0 {
+		return report, ErrUserImportInvalid
+	}
+
+	for i, row := range parsed {
+		credential, err := s.applyRow(actorID, row)
+		if err != nil {
+			return nil, fmt.Errorf("baris %d: %w", row.result.Row, err)
+		}
+		if row.existing == nil {
+			report.Created++
+			report.Rows[i].UserID = credential.userID
+		} else {
+			report.Updated++
+			report.Rows[i].UserID = row.existing.ID
+		}
+		if credential.password != "" {
+			report.Credentials = append(report.Credentials, dto.UserImportCredentialDto{
+				Row:       row.result.Row,
+				Name:      row.result.Name,
+				Email:     row.result.Email,
+				Code:      credential.code,
+				Role:      row.result.Role,
+				ClassCode: row.result.ClassCode,
+				Password:  credential.password,
+			})
+		}
+	}
+
+	return report, nil
+}
+
+func (s *userImportService) validateRows(actorID int64, isSuper bool, rows [][]string, columns map[string]int, options dto.UserImportOptionsDto) ([]*importRow, error) {
+	cell := func(row []string, field string) string {
+		index, ok := columns[field]
+		if !ok || index >= len(row) {
+			return ""
+		}
+		return strings.TrimSpace(row[index])
+	}
+
+	var parsed []*importRow
+	seenEmails := make(map[string]int)
+	var emails []string
+
+	for i, raw := range rows[1:] {
+		if spreadsheet.IsEmptyRow(raw) {
+			continue
+		}
+
+		row := &importRow{result: dto.UserImportRowResultDto{
+			Row:       i + 2,
+			Name:      cell(raw, constant.UserImportFieldName),
+			Email:     strings.ToLower(cell(raw, constant.UserImportFieldEmail)),
+			Role:      strings.ToLower(cell(raw, constant.UserImportFieldRole)),
+			ClassCode: strings.ToUpper(cell(raw, constant.UserImportFieldClassCode)),
+		}}
+		row.password = cell(raw, constant.UserImportFieldPassword)
+		addError := func(format string, args ...interface{}) {
+			row.result.Errors = append(row.result.Errors, fmt.Sprintf(format, args...))
+		}
+
+		if row.result.Role == "" {
+			row.result.Role = strings.ToLower(options.DefaultRole)
+		}
+		if row.result.Role == "" {
+			row.result.Role = "student"
+		}
+		if row.result.ClassCode == "" {
+			row.result.ClassCode = strings.ToUpper(strings.TrimSpace(options.ClassCode))
+		}
+
+		switch {
+		case row.result.Name == "":
+			addError("nama wajib diisi")
+		case len(row.result.Name) > 255:
+			addError("nama maksimal 255 karakter")
+		}
+
+		if row.result.Email == "" {
+			addError("email wajib diisi")
+		} else if addr, err := mail.ParseAddress(row.result.Email); err != nil || addr.Address != row.result.Email {
+			addError("email %q tidak valid", row.result.Email)
+		} else if first, ok := seenEmails[row.result.Email]; ok {
+			addError("email %s sudah dipakai di baris %d", row.result.Email, first)
+		} else {
+			seenEmails[row.result.Email] = row.result.Row
+			emails = append(emails, row.result.Email)
+		}
+
+		roleID, ok := importRoles[row.result.Role]
+		if !ok {
+			addError("role %q tidak valid, gunakan student atau teacher", row.result.Role)
+		} else if roleID == constant.RoleIDTeacher && !isSuper {
+			addError("hanya super admin yang dapat mengimpor teacher")
+		} else if roleID == constant.RoleIDTeacher {
+			row.result.Role = "teacher"
+		} else {
+			row.result.Role = "student"
+		}
+		row.roleID = roleID
+
+		if row.password != "" && len(row.password) < minImportPassword {
+			addError("password minimal %d karakter", minImportPassword)
+		}
+
+		if row.result.ClassCode == "" && !isSuper {
+			addError("kode kelas wajib diisi")
+		}
+
+		parsed = append(parsed, row)
+	}
+
+	if len(parsed) == 0 {
+		return nil, fmt.Errorf("file tidak berisi data")
+	}
+
+	existing, err := s.findExistingUsers(emails)
+	if err != nil {
+		return nil, err
+	}
+
+	classes := make(map[string]*models.MClass)
+	teaches := make(map[int64]bool)
+
+	for _, row := range parsed {
+		addError := func(format string, args ...interface{}) {
+			row.result.Errors = append(row.result.Errors, fmt.Sprintf(format, args...))
+		}
+
+		if user, ok := existing[row.result.Email]; ok {
+			row.existing = user
+			row.result.Action = constant.UserImportActionUpdate
+			row.result.UserID = user.ID
+
+			switch {
+			case user.DeletedAt.Valid:
+				addError("email %s milik user yang sudah dihapus", row.result.Email)
+			case user.RoleID == constant.RoleIDSuper:
+				addError("email %s milik super admin", row.result.Email)
+			case user.RoleID != row.roleID && !isSuper:
+				addError("hanya super admin yang dapat mengubah role user")
+			}
+		} else {
+			row.result.Action = constant.UserImportActionCreate
+		}
+
+		if row.result.ClassCode == "" {
+			continue
+		}
+
+		class, ok := classes[row.result.ClassCode]
+		if !ok {
+			class, err = s.rosterRepo.FindClassByCode(row.result.ClassCode)
+			if err == nil {
+				// Join codes are global; classes of other schools are treated
+				// as unknown.
+				class, err = s.rosterRepo.FindClassByID(class.ID)
+			}
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				class, err = nil, nil
+			}
+			if err != nil {
+				return nil, gorm_err.TranslateGormError(err)
+			}
+			classes[row.result.ClassCode] = class
+		}
+		if class == nil {
+			addError("kode kelas %s tidak ditemukan", row.result.ClassCode)
+			continue
+		}
+		if !class.IsActive {
+			addError("kelas %s tidak aktif", class.ClassName)
+			continue
+		}
+		row.class = class
+
+		if isSuper {
+			continue
+		}
+		if _, ok := teaches[class.ID]; !ok {
+			assigned, err := s.rosterRepo.IsClassTeacher(class.ID, actorID)
+			if err != nil {
+				return nil, gorm_err.TranslateGormError(err)
+			}
+			teaches[class.ID] = assigned
+		}
+		if !teaches[class.ID] {
+			addError("kamu bukan teacher di kelas %s", class.ClassName)
+			continue
+		}
+
+		// Teachers may only pull in students who have no class yet or who
+		// already sit in one of their classes.
+		if row.existing != nil && row.existing.ClassID != nil && *row.existing.ClassID != class.ID {
+			otherID := *row.existing.ClassID
+			if _, ok := teaches[otherID]; !ok {
+				assigned, err := s.rosterRepo.IsClassTeacher(otherID, actorID)
+				if err != nil {
+					return nil, gorm_err.TranslateGormError(err)
+				}
+				teaches[otherID] = assigned
+			}
+			if !teaches[otherID] {
+				addError("student %s sudah terdaftar di kelas lain", row.result.Email)
+			}
+		}
+	}
+
+	return parsed, nil
+}
+
+func (s *userImportService) findExistingUsers(emails []string) (map[string]*models.User, error) {
+	existing := make(map[string]*models.User, len(emails))
+	if len(emails) == 0 {
+		return existing, nil
+	}
+
+	users, err := s.userRepo.WithUnscoped().WithWhere("LOWER(email) IN ?", emails).FindUser()
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+	for i := range users {
+		existing[strings.ToLower(users[i].Email)] = &users[i]
+	}
+	return existing, nil
+}
+
+type appliedImportRow struct {
+	userID   int64
+	code     string
+	password string
+}
+
+// applyRow creates or updates the row's user and places them in its class.
+// Only new accounts get the row's password: an import never changes the
+// password of an existing account, since that would hand it to whoever ran
+// the import.
+func (s *userImportService) applyRow(actorID int64, row *importRow) (*appliedImportRow, error) {
+	applied := &appliedImportRow{}
+
+	var user *models.User
+	if row.existing == nil {
+		applied.password = row.password
+		if applied.password == "" {
+			password, err := helper.GeneratePassword(defaultPasswordLength)
+			if err != nil {
+				return nil, err
+			}
+			applied.password = password
+		}
+
+		hashed, err := HashPassword(applied.password)
+		if err != nil {
+			return nil, err
+		}
+
+		created, err := s.userRepo.InsertUser(&models.User{
+			RoleID:            row.roleID,
+			Name:              row.result.Name,
+			Email:             row.result.Email,
+			Password:          hashed,
+			IsPasswordDefault: true,
+			IsApprovedByAdmin: true,
+			IsActive:          true,
+			HasRoles:          []models.Role{{ID: row.roleID}},
+		})
+		if err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+		user = created
+
+		err = events.Publish(s.GetDB(), events.UserRegistered{
+			UserID:       created.ID,
+			Email:        created.Email,
+			Role:         row.result.Role,
+			RegisteredAt: created.CreatedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		user = row.existing
+		updates := map[string]interface{}{"name": row.result.Name}
+
+		repo := s.userRepo
+		if user.RoleID != row.roleID {
+			updates["role_id"] = row.roleID
+			repo = repo.WithAssociations("HasRoles").WithReplacements(map[string]interface{}{
+				"HasRoles": []models.Role{{ID: row.roleID}},
+			})
+		}
+
+		if _, err := repo.UpdateUser(user.ID, updates); err != nil {
+			return nil, gorm_err.TranslateGormError(err)
+		}
+	}
+
+	applied.userID = user.ID
+	if user.Code != nil {
+		applied.code = *user.Code
+	}
+
+	if row.class == nil {
+		return applied, nil
+	}
+	if row.roleID == constant.RoleIDTeacher {
+		return applied, s.assignImportedTeacher(actorID, row.class.ID, user.ID)
+	}
+	return applied, s.placeImportedStudent(actorID, row.class.ID, user)
+}
+
+func (s *userImportService) placeImportedStudent(actorID int64, classID int64, user *models.User) error {
+	if user.ClassID != nil && *user.ClassID == classID {
+		return nil
+	}
+	if err := s.rosterRepo.SetUserClass(user.ID, &classID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	note := "roster import"
+	logs := []*models.TClassRosterLog{{
+		ClassID:      classID,
+		UserID:       &user.ID,
+		ActorID:      &actorID,
+		Action:       constant.RosterStudentAdded,
+		OtherClassID: user.ClassID,
+		Note:         &note,
+	}}
+	if user.ClassID != nil {
+		logs[0].Action = constant.RosterMovedIn
+		logs = append(logs, &models.TClassRosterLog{
+			ClassID:      *user.ClassID,
+			UserID:       &user.ID,
+			ActorID:      &actorID,
+			Action:       constant.RosterMovedOut,
+			OtherClassID: &classID,
+			Note:         &note,
+		})
+	}
+	if err := s.rosterRepo.InsertRosterLogs(logs); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	return events.Publish(s.GetDB(), events.ClassStudentJoined{
+		ClassID:  classID,
+		UserID:   user.ID,
+		JoinedAt: time.Now(),
+	})
+}
+
+func (s *userImportService) assignImportedTeacher(actorID int64, classID int64, userID int64) error {
+	assigned, err := s.rosterRepo.IsClassTeacher(classID, userID)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if assigned {
+		return nil
+	}
+	if err := s.rosterRepo.AddClassTeacher(classID, userID); err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+
+	note := "roster import"
+	return gorm_err.TranslateGormError(s.rosterRepo.InsertRosterLogs([]*models.TClassRosterLog{{
+		ClassID: classID,
+		UserID:  &userID,
+		ActorID: &actorID,
+		Action:  constant.RosterTeacherAssigned,
+		Note:    &note,
+	}}))
+}
+
+// resolveImportColumns finds the column index of each import field, using the
+// explicit mapping when given and the known header aliases otherwise.
+func resolveImportColumns(header []string, mapping map[string]string) (map[string]int, error) {
+	normalized := make(map[string]int, len(header))
+	for i, h := range header {
+		key := normalizeImportHeader(h)
+		if _, ok := normalized[key]; !ok && key != "" {
+			normalized[key] = i
+		}
+	}
+
+	columns := make(map[string]int)
+	for field, source := range mapping {
+		if !constant.IsUserImportField(field) {
+			return nil, fmt.Errorf("kolom %q tidak dikenal", field)
+		}
+		index, ok := normalized[normalizeImportHeader(source)]
+		if !ok {
+			return nil, fmt.Errorf("kolom %q tidak ditemukan di file", source)
+		}
+		columns[field] = index
+	}
+
+	for field, aliases := range constant.UserImportColumnAliases {
+		if _, ok := columns[field]; ok {
+			continue
+		}
+		for _, alias := range aliases {
+			if index, ok := normalized[alias]; ok {
+				columns[field] = index
+				break
+			}
+		}
+	}
+
+	for _, field := range []string{constant.UserImportFieldName, constant.UserImportFieldEmail} {
+		if _, ok := columns[field]; !ok {
+			return nil, fmt.Errorf("kolom %s tidak ditemukan di file", field)
+		}
+	}
+	return columns, nil
+}
+
+func normalizeImportHeader(header string) string {
+	header = strings.ToLower(strings.TrimSpace(header))
+	header = strings.ReplaceAll(header, "_", " ")
+	return strings.Join(strings.Fields(header), " ")
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"jk-api/api/http/controllers/v1/dto"
+	"jk-api/internal/constant"
+	"jk-api/internal/database/models"
+	"jk-api/pkg/repository/adapter/sql"
+	"testing"
+)
+
+// Re-importing an existing account with a password column must not reset
+// the password, nor report one in the credentials export.
+func TestImportUsersKeepsExistingPasswords(t *testing.T) {
+	repo := &fakeImportUserRepository{users: []models.User{{
+		ID:       7,
+		RoleID:   constant.RoleIDStudent,
+		Name:     "Siti",
+		Email:    "siti@example.com",
+		Password: "hashed-original",
+	}}}
+	service := NewUserImportService(repo, nil)
+
+	rows := [][]string{
+		{"name", "email", "role", "password"},
+		{"Siti Aminah", "siti@example.com", "student", "diambilalih"},
+	}
+	report, err := service.ImportUsers(1, true, rows, dto.UserImportOptionsDto{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if report.Updated != 1 || len(report.Credentials) != 0 {
+		t.Errorf("updated = %d, credentials = %+v", report.Updated, report.Credentials)
+	}
+	if len(repo.updates) != 1 {
+		t.Fatalf("got %d updates, want 1", len(repo.updates))
+	}
+	for _, column := range []string{"password", "is_password_default"} {
+		if _, ok := repo.updates[0][column]; ok {
+			t.Errorf("import updated %s: %v", column, repo.updates[0])
+		}
+	}
+	if repo.updates[0]["name"] != "Siti Aminah" {
+		t.Errorf("updates = %v, want the new name", repo.updates[0])
+	}
+}
+
+type fakeImportUserRepository struct {
+	sql.UserRepository
+	users   []models.User
+	updates []map[string]interface{}
+}
+
+func (r *fakeImportUserRepository) WithUnscoped() sql.UserRepository {
+	return r
+}
+
+func (r *fakeImportUserRepository) WithWhere(query interface{}, args ...interface{}) sql.UserRepository {
+	return r
+}
+
+func (r *fakeImportUserRepository) FindUser() ([]models.User, error) {
+	return r.users, nil
+}
+
+func (r *fakeImportUserRepository) UpdateUser(id int64, updates map[string]interface{}) (*models.User, error) {
+	r.updates = append(r.updates, updates)
+	return &r.users[0], nil
+}
@@ -4,8 +4,12 @@ import (
 	"fmt"
 	"jk-api/api/http/controllers/v1/dto"
 	"jk-api/internal/config"
+	"jk-api/internal/constant"
 	"jk-api/internal/database/models"
 	"jk-api/internal/errors/gorm_err"
+	"jk-api/internal/events"
+	"jk-api/internal/queryspec"
+	"jk-api/internal/tenant"
 	"jk-api/pkg/repository/adapter/sql"
 	"time"
 
@@ -19,27 +23,30 @@ type UserService interface {
 	CreateUser(input *models.User) (*models.User, error)
 	UpdateUser(id int64, updates map[string]interface{}, associations map[string]interface{}) (*models.User, error)
 	DeleteUser(id int64, isPermanent bool) error
-	GetAllUsers(filter dto.UserFilterDto) ([]models.User, error)
+	GetAllUsers(filter dto.UserFilterDto) ([]models.User, queryspec.Page, error)
 	GetUserByID(id int64, filter dto.UserFilterDto) (*models.User, error)
 	GetDB() *gorm.DB
 	BulkCreateUsers(data []*models.User) ([]*models.User, error)
 	BulkUpdateUsers(ids []int64, updates map[string]interface{}, associatons map[string]interface{}) error
 	BulkDeleteUsers(ids []int64, isPermanent bool) error
+	ApproveTeacher(id int64, approvedBy int64) (*models.User, error)
 }
 
 type userService struct {
-	repo sql.UserRepository
-	tx   *gorm.DB
+	repo           sql.UserRepository
+	departmentRepo sql.DepartmentRepository
+	tx             *gorm.DB
 }
 
-func NewUserService(repo sql.UserRepository) UserService {
-	return &userService{repo: repo}
+func NewUserService(repo sql.UserRepository, departmentRepo sql.DepartmentRepository) UserService {
+	return &userService{repo: repo, departmentRepo: departmentRepo}
 }
 
 func (s *userService) WithTx(tx *gorm.DB) UserService {
 	return &userService{
-		repo: s.repo.WithTx(tx),
-		tx:   tx,
+		repo:           s.repo.WithTx(tx),
+		departmentRepo: s.departmentRepo.WithTx(tx),
+		tx:             tx,
 	}
 }
 
@@ -51,6 +58,10 @@ func (s *userService) GetDB() *gorm.DB {
 }
 
 func (s *userService) CreateUser(input *models.User) (*models.User, error) {
+	if err := checkDepartment(s.departmentRepo, input.DepartmentID); err != nil {
+		return nil, err
+	}
+
 	hashedPassword, err := HashPassword(input.Password)
 	if err != nil {
 		return nil, err
@@ -69,6 +80,19 @@ func (s *userService) CreateUser(input *models.User) (*models.User, error) {
 func (s *userService) UpdateUser(id int64, updates map[string]interface{}, associations map[string]interface{}) (*models.User, error) {
 	repo := s.repo
 
+	if err := s.guardDepartmentScope(updates); err != nil {
+		return nil, err
+	}
+	if err := checkDepartmentUpdate(s.departmentRepo, updates, "department_id", "scope_department_id"); err != nil {
+		return nil, err
+	}
+
+	if roles, ok := associations["HasRoles"].([]models.Role); ok {
+		if err := s.guardPlatformRole(roles); err != nil {
+			return nil, err
+		}
+	}
+
 	if len(associations) > 0 {
 		assocNames := make([]string, 0, len(associations))
 		for name := range associations {
@@ -101,6 +125,73 @@ func (s *userService) UpdateUser(id int64, updates map[string]interface{}, assoc
 	return data, nil
 }
 
+// guardPlatformRole keeps school-scoped callers from granting the platform
+// admin role, which would let them escape their school.
+func (s *userService) guardPlatformRole(roles []models.Role) error {
+	if scope, ok := tenant.FromContext(s.GetDB().Statement.Context); !ok || scope.AllSchools {
+		return nil
+	}
+
+	roleIDs := make([]int64, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+
+	count, err := s.repo.CountRolesByName(roleIDs, constant.RolePlatformAdmin)
+	if err != nil {
+		return gorm_err.TranslateGormError(err)
+	}
+	if count > 0 {
+		return fmt.Errorf("role %s hanya dapat diberikan oleh admin platform", constant.RolePlatformAdmin)
+	}
+	return nil
+}
+
+// guardDepartmentScope keeps department-scoped callers from changing anyone's
+// department scope, which would let them widen their own view.
+func (s *userService) guardDepartmentScope(updates map[string]interface{}) error {
+	if _, ok := updates["scope_department_id"]; !ok {
+		return nil
+	}
+	if scope, ok := tenant.FromContext(s.GetDB().Statement.Context); ok && scope.DepartmentID != nil {
+		return fmt.Errorf("scope department hanya dapat diubah oleh admin sekolah")
+	}
+	return nil
+}
+
+// ApproveTeacher lets a registered teacher log in. Approving an already
+// approved teacher is a no-op.
+func (s *userService) ApproveTeacher(id int64, approvedBy int64) (*models.User, error) {
+	existing, err := s.repo.FindUserByID(id)
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	if existing.RoleID != 2 {
+		return nil, fmt.Errorf("user %d bukan teacher", id)
+	}
+	if existing.IsApprovedByAdmin {
+		return existing, nil
+	}
+
+	data, err := s.repo.UpdateUser(id, map[string]interface{}{
+		"is_approved_by_admin": true,
+	})
+	if err != nil {
+		return nil, gorm_err.TranslateGormError(err)
+	}
+
+	err = events.Publish(s.GetDB(), events.TeacherApproved{
+		UserID:     data.ID,
+		ApprovedBy: approvedBy,
+		ApprovedAt: time.Now(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
 func (s *userService) DeleteUser(id int64, isPermanent bool) (err error) {
 	repo := s.repo
 	if isPermanent {
@@ -121,39 +212,40 @@ func (s *userService) DeleteUser(id int64, isPermanent bool) (err error) {
 	return gorm_err.TranslateGormError(err)
 }
 
-func (s *userService) GetAllUsers(filter dto.UserFilterDto) ([]models.User, error) {
+func (s *userService) GetAllUsers(filter dto.UserFilterDto) ([]models.User, queryspec.Page, error) {
 	repo := s.repo
 	if filter.SquadID != 0 {
 		repo = repo.
 			WithJoins("JOIN squad_members ON squad_members.user_id = users.id").
 			WithWhere("squad_members.squad_id = ?", filter.SquadID)
 	}
-
-	if filter.Preload {
-		repo = repo.WithPreloads("HasRoles", "HasClass", "TeachingClasses")
-	}
-	if filter.Limit != 0 {
-		repo = repo.WithLimit(int(filter.Limit))
-	}
-	if filter.Cursor != 0 {
-		repo = repo.WithCursor(int(filter.Cursor))
-	}
 	if filter.Name != "" {
 		repo = repo.WithWhere("users.name ILIKE ?", "%"+filter.Name+"%")
 	}
-	if filter.Sort != "" && filter.Order != "" {
-		orderClause := filter.Sort + " " + filter.Order
-		repo = repo.WithOrder(orderClause)
+	if filter.DepartmentID != 0 {
+		condition := (&models.User{}).DepartmentCondition(tenant.DepartmentSubtreeSQL)
+		repo = repo.WithWhere(condition, tenant.DepartmentArg(filter.DepartmentID))
 	}
 	if filter.ShowDeleted {
-		repo = repo.WithUnscoped().WithWhere("deleted_at IS NOT NULL")
+		repo = repo.WithUnscoped().WithWhere("users.deleted_at IS NOT NULL")
 	}
+	repo = repo.WithSpec(filter.Spec)
 
-	data, err := repo.FindUser()
+	total, err := repo.CountUsers()
 	if err != nil {
-		return nil, gorm_err.TranslateGormError(err)
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
 	}
-	return data, nil
+
+	if filter.Preload {
+		repo = repo.WithPreloads("HasRoles", "HasClass", "TeachingClasses")
+	}
+
+	data, page, err := repo.FindUserPage()
+	if err != nil {
+		return nil, queryspec.Page{}, gorm_err.TranslateGormError(err)
+	}
+	page.Total = total
+	return data, page, nil
 }
 
 func (s *userService) GetUserByID(id int64, filter dto.UserFilterDto) (*models.User, error) {
@@ -178,6 +270,9 @@ func HashPassword(password string) (string, error) {
 
 func (s *userService) BulkCreateUsers(data []*models.User) ([]*models.User, error) {
 	for i, user := range data {
+		if err := checkDepartment(s.departmentRepo, user.DepartmentID); err != nil {
+			return nil, err
+		}
 		if user.Password == "" {
 			return nil, fmt.Errorf("password untuk user index %d tidak boleh kosong", i)
 		}
@@ -205,6 +300,13 @@ func (s *userService) BulkUpdateUsers(
 	updates map[string]interface{},
 	associations map[string]interface{},
 ) error {
+	if err := s.guardDepartmentScope(updates); err != nil {
+		return err
+	}
+	if err := checkDepartmentUpdate(s.departmentRepo, updates, "department_id", "scope_department_id"); err != nil {
+		return err
+	}
+
 	repo := s.repo
 	if _, ok := updates["deleted_at"]; ok {
 		repo = repo.WithUnscoped()